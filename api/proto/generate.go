@@ -0,0 +1,4 @@
+// Package proto gRPC接口定义（.proto）与生成的Go代码
+package proto
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cryptowallet/v1/wallet.proto
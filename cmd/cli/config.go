@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"crypto-wallet-api/internal/config"
+)
+
+// newConfigCommand 配置相关命令
+func newConfigCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect configuration",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Load and validate the configuration without connecting to any dependency",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 与服务启动时相同的加载与校验流程（含环境变量覆盖），敏感字段在输出中脱敏
+			cfg, err := config.Load(opts.configPath)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s is valid\n%s\n", opts.configPath, cfg)
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"crypto-wallet-api/internal/app"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// newQueueCommand 消息队列相关命令
+func newQueueCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Inspect message queues",
+	}
+	dlq := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and replay dead-lettered messages",
+	}
+	var queueName string
+	var limit int
+	dlq.PersistentFlags().StringVar(&queueName, "queue", service.TransactionCreatedQueue, "主队列名称（操作<queue>.dlq）")
+	dlq.PersistentFlags().IntVar(&limit, "limit", 100, "最多处理的消息数")
+	dlq.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if limit <= 0 {
+			return fmt.Errorf("invalid limit: %d", limit)
+		}
+		return nil
+	}
+
+	dlq.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List dead-lettered messages without removing them",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+					letters, err := application.MQ.ListDeadLetters(queueName, limit)
+					if err != nil {
+						return err
+					}
+					return printJSON(letters)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "replay",
+			Short: "Move dead-lettered messages back to the main queue",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+					if opts.dryRun {
+						letters, err := application.MQ.ListDeadLetters(queueName, limit)
+						if err != nil {
+							return err
+						}
+						fmt.Printf("would replay %d messages from %s.dlq%s\n", len(letters), queueName, dryRunNote(opts))
+						return nil
+					}
+					replayed, err := application.MQ.ReplayDeadLetters(queueName, limit)
+					if err != nil {
+						return err
+					}
+					logger.WithCtx(ctx).Info("dead letters replayed",
+						zap.String("queue", queueName),
+						zap.Int("count", replayed),
+						zap.String("operator", opts.operator),
+					)
+					fmt.Printf("replayed %d messages from %s.dlq\n", replayed, queueName)
+					return nil
+				})
+			},
+		},
+	)
+	cmd.AddCommand(dlq)
+	return cmd
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"crypto-wallet-api/internal/app"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
+)
+
+// newTxCommand 交易相关命令
+func newTxCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Inspect and repair transactions",
+	}
+	cmd.AddCommand(newTxRequeueCommand(opts), newTxSetStatusCommand(opts))
+	return cmd
+}
+
+// newTxRequeueCommand 为卡住的交易重新写入监听队列消息
+func newTxRequeueCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "requeue <tx_hash>",
+		Short: "Re-queue a pending or confirming transaction for monitoring",
+		Args:  txHashArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+				tx, err := application.TxService.RequeueTransaction(ctx, args[0], opts.operator, opts.dryRun)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("requeued %s (status %s)%s\n", tx.TxHash, tx.Status, dryRunNote(opts))
+				return nil
+			})
+		},
+	}
+}
+
+// newTxSetStatusCommand 将卡住的交易手动设置为最终状态
+func newTxSetStatusCommand(opts *options) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "set-status <tx_hash> <success|failed|cancelled>",
+		Short: "Force a pending or confirming transaction into a final status",
+		Args: cobra.MatchAll(cobra.ExactArgs(2), func(cmd *cobra.Command, args []string) error {
+			return txHashArgs(cmd, args[:1])
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+				status := models.TransactionStatus(args[1])
+				tx, err := application.TxService.SetTransactionStatus(ctx, args[0], status, reason, opts.operator, opts.dryRun)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s: status set to %s%s\n", tx.TxHash, status, dryRunNote(opts))
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "修改原因（记录在交易的错误信息与审计日志中）")
+	return cmd
+}
+
+// txHashArgs 校验唯一参数为链上交易哈希
+func txHashArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	if !utils.IsTxHash(args[0]) {
+		return fmt.Errorf("invalid transaction hash: %s", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"crypto-wallet-api/internal/app"
+)
+
+// newUserCommand 用户相关命令
+func newUserCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage user accounts",
+	}
+	cmd.AddCommand(
+		newUserDisabledCommand(opts, "disable", "Disable an account and revoke all of its tokens", true),
+		newUserDisabledCommand(opts, "enable", "Re-enable a disabled account", false),
+	)
+	return cmd
+}
+
+// newUserDisabledCommand 停用或启用用户
+func newUserDisabledCommand(opts *options, use, short string, disabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <email>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+				user, err := application.AuthService.SetUserDisabled(ctx, args[0], disabled, opts.operator, opts.dryRun)
+				if err != nil {
+					return err
+				}
+				state := "enabled"
+				if user.DisabledAt != nil {
+					state = "disabled"
+				}
+				fmt.Printf("user %d (%s) is %s%s\n", user.ID, user.Username, state, dryRunNote(opts))
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"crypto-wallet-api/internal/app"
+	"crypto-wallet-api/internal/utils"
+)
+
+// newWalletCommand 钱包相关命令
+func newWalletCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wallet",
+		Short: "Inspect and repair wallets",
+	}
+	cmd.AddCommand(newWalletRefreshBalanceCommand(opts))
+	return cmd
+}
+
+// newWalletRefreshBalanceCommand 从链上刷新钱包余额
+func newWalletRefreshBalanceCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh-balance <address>",
+		Short: "Re-read a wallet's balance from the chain and store it",
+		Args: cobra.MatchAll(cobra.ExactArgs(1), func(cmd *cobra.Command, args []string) error {
+			if !utils.IsEthAddress(args[0]) {
+				return fmt.Errorf("invalid wallet address: %s", args[0])
+			}
+			return nil
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withApp(cmd, opts, func(ctx context.Context, application *app.App) error {
+				wallet, balance, err := application.WalletService.RefreshBalanceByAddress(ctx, args[0], opts.operator, opts.dryRun)
+				if err != nil {
+					return err
+				}
+				if balance == nil {
+					fmt.Printf("%s: stored balance %s wei%s\n", wallet.Address, wallet.Balance, dryRunNote(opts))
+					return nil
+				}
+				fmt.Printf("%s: balance %s -> %s wei\n", wallet.Address, wallet.Balance, balance)
+				return nil
+			})
+		},
+	}
+}
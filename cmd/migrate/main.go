@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"crypto-wallet-api/internal/config"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/pkg/database"
+	"crypto-wallet-api/pkg/keys"
+)
+
+// backfillBatchSize 回填邮箱时每批处理的用户数
+const backfillBatchSize = 500
+
+const usage = `Usage: migrate [-config path] <command>
+
+Commands:
+  up               执行全部未应用的迁移
+  down             回滚最近一次迁移
+  status           查看各迁移的应用状态
+  backfill-emails  加密已有用户的明文邮箱并生成检索摘要（执行up之后运行，可重复执行）
+`
+
+func main() {
+	// 1. 解析命令行参数
+	configPath := flag.String("config", "./configs/configs.yaml", "配置文件路径")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 2. 加载配置
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configs: %v", err)
+	}
+
+	// 加载加密密钥，注册加密字段序列化器
+	keyProvider, err := cfg.Keys.NewProvider()
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	piiKey, err := keyProvider.Key(keys.PIIEncryption)
+	if err != nil {
+		log.Fatalf("Failed to load PII encryption key: %v", err)
+	}
+	database.RegisterEncryptedSerializer(piiKey)
+
+	// 3. 连接数据库
+	db, err := database.NewPostgresDB(
+		cfg.Database.GetDSN(),
+		1,
+		1,
+		cfg.Database.ConnMaxLifetime,
+		cfg.Database.LogLevel,
+		cfg.Database.SlowThreshold,
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to create migrator: %v", err)
+	}
+
+	// 4. 执行命令
+	ctx := context.Background()
+	switch flag.Arg(0) {
+	case "up":
+		results, err := migrator.Up(ctx)
+		for _, result := range results {
+			fmt.Println(result)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(results) == 0 {
+			fmt.Println("No pending migrations")
+		}
+
+	case "down":
+		result, err := migrator.Down(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "-"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-8s %-20s %s\n", status.State, appliedAt, status.Source.Path)
+		}
+
+	case "backfill-emails":
+		emailHMACKey, err := keyProvider.Key(keys.EmailHMAC)
+		if err != nil {
+			log.Fatalf("Failed to load email HMAC key: %v", err)
+		}
+		count, err := repository.NewUserRepository(db, emailHMACKey).BackfillEmails(ctx, backfillBatchSize)
+		fmt.Printf("Backfilled %d users\n", count)
+		if err != nil {
+			log.Fatalf("Backfill failed: %v", err)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -5,25 +5,22 @@ import (
 
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
-	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/app"
 	"crypto-wallet-api/internal/config"
-	"crypto-wallet-api/internal/handler"
+	"crypto-wallet-api/internal/i18n"
 	"crypto-wallet-api/internal/logger"
-	"crypto-wallet-api/internal/middleware"
-	"crypto-wallet-api/internal/repository"
-	"crypto-wallet-api/internal/service"
-	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/internal/tracing"
+	"crypto-wallet-api/internal/utils"
 	"crypto-wallet-api/pkg/database"
-	"crypto-wallet-api/pkg/queue"
 )
 
 func main() {
@@ -45,96 +42,72 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logger.Logger.Sync()
+	logger.Info("Configuration loaded", zap.Stringer("config", cfg))
 
 	logger.Info("Starting CryptoWallet API Server...")
 
-	// 3. 连接数据库
-	db, err := database.NewPostgresDB(
-		cfg.Database.GetDSN(),
-		cfg.Database.MaxOpenConns,
-		cfg.Database.MaxIdleConns,
-		cfg.Database.ConnMaxLifetime,
+	// 初始化链路追踪
+	shutdownTracer, err := tracing.InitTracer(
+		cfg.Tracing.Enabled,
+		cfg.Tracing.ServiceName,
+		cfg.Tracing.Endpoint,
+		cfg.Tracing.Insecure,
+		cfg.Tracing.SampleRate,
 	)
 	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
+		logger.Fatal("Failed to initialize tracer", zap.Error(err))
 	}
-	logger.Info("Database connected successfully")
+	defer shutdownTracer(context.Background())
 
-	// 4. 自动迁移数据库表结构
-	if err := database.AutoMigrate(db); err != nil {
-		logger.Fatal("Failed to migrate database", zap.Error(err))
+	// 加载响应消息语言包（每个业务状态码在全部语言包中都需要有默认消息，其余缺少的消息回退到英文）
+	if err := i18n.Load(); err != nil {
+		logger.Fatal("Failed to load translations", zap.Error(err))
 	}
-	logger.Info("Database migrated successfully")
-
-	// 5. 连接Redis
-	redisCache, err := cache.NewRedisCache(
-		cfg.Redis.GetRedisAddr(),
-		cfg.Redis.Password,
-		cfg.Redis.DB,
-		cfg.Redis.PoolSize,
-		cfg.Redis.MinIdleConns,
-	)
-	if err != nil {
-		logger.Fatal("Failed to connect to Redis", zap.Error(err))
+	if err := utils.CheckTranslations(); err != nil {
+		logger.Fatal("Incomplete translations", zap.Error(err))
 	}
-	defer redisCache.Close()
-	logger.Info("Redis connected successfully")
-
-	// 6. 连接RabbitMQ
-	mq, err := queue.NewRabbitMQ(cfg.RabbitMQ.GetRabbitMQURL())
-	if err != nil {
-		logger.Fatal("Failed to connect to RabbitMQ", zap.Error(err))
+	for _, lang := range i18n.Languages {
+		if missing := i18n.Missing(lang); len(missing) > 0 {
+			logger.Warn("Translations missing, falling back to English", zap.String("lang", lang), zap.Strings("keys", missing))
+		}
 	}
-	defer mq.Close()
-	logger.Info("RabbitMQ connected successfully")
 
-	// 7. 初始化区块链客户端
-	ethClient, err := blockchain.NewEthereumClient(
-		cfg.Blockchain.Ethereum.RPCURL,
-		cfg.Blockchain.Ethereum.ChainID,
-	)
+	// 3. 初始化依赖（数据库、Redis、RabbitMQ、区块链客户端与各Service）
+	application, err := app.NewApp(cfg)
 	if err != nil {
-		logger.Fatal("Failed to create Ethereum client", zap.Error(err))
+		logger.Fatal("Failed to initialize application", zap.Error(err))
 	}
-	logger.Info("Ethereum client initialized successfully")
+	defer application.Close()
 
-	// 8. 生成加密密钥（实际生产环境应从环境变量或KMS获取）
-	encryptionKey := []byte("12345678901234567890123456789012") // 32字节密钥
-
-	// 9. 初始化Repository层
-	userRepo := repository.NewUserRepository(db)
-	walletRepo := repository.NewWalletRepository(db)
-	txRepo := repository.NewTransactionRepository(db)
-
-	// 10. 初始化Service层
-	authService := service.NewAuthService(userRepo, cfg.JWT.Secret, cfg.JWT.ExpireHours)
-	walletService := service.NewWalletService(walletRepo, ethClient, redisCache, encryptionKey)
-	txService := service.NewTransactionService(txRepo, walletRepo, walletService, ethClient, mq)
-
-	// 11. 初始化Handler层
-	authHandler := handler.NewAuthHandler(authService)
-	walletHandler := handler.NewWalletHandler(walletService)
-	txHandler := handler.NewTransactionHandler(txService)
+	// 4. 开发模式下直接按模型自动建表
+	if cfg.Database.AutoMigrate {
+		if err := database.AutoMigrate(application.DB); err != nil {
+			logger.Fatal("Failed to migrate database", zap.Error(err))
+		}
+		logger.Warn("Database auto-migrated, this mode is for development only")
+	}
 
-	// 12. 初始化Gin引擎
-	if cfg.Server.Mode == "release" {
-		gin.SetMode(gin.ReleaseMode)
+	// 同步内置的公共地址标签数据集（失败时交易仍可正常返回，只是没有对手方标签）
+	if seeded, err := application.LabelService.SeedDataset(context.Background()); err != nil {
+		logger.Error("Failed to seed address labels", zap.Error(err))
+	} else {
+		logger.Info("Address labels seeded", zap.Int64("labels", seeded))
 	}
-	router := gin.New()
 
-	// 13. 注册全局中间件
-	router.Use(middleware.LoggerMiddleware())
-	router.Use(middleware.CORSMiddleware())
-	router.Use(gin.Recovery())
-	router.Use(middleware.RateLimitMiddleware(
-		cfg.RateLimit.RequestsPerSecond,
-		cfg.RateLimit.Burst,
-	))
+	// 启动后台任务（关闭时在请求处理完后取消，并等待退出后再关闭连接）
+	// 事件分发：将Worker发布的事件推送到本进程的WebSocket连接
+	application.Lifecycle.Go("events", application.EventService.Run)
+	// 后台余额刷新：有界并发，按批查询链上余额
+	application.Lifecycle.Go("balance_refresher", application.BalanceRefresher.Run)
+	// 链头监控：节点长时间未同步到新区块时拒绝发送交易
+	application.Lifecycle.Go("chain_health", application.ChainHealth.Run)
+	application.Start()
 
-	// 14. 注册路由
-	setupRoutes(router, authHandler, walletHandler, txHandler, authService)
+	// 5. 初始化Gin引擎与路由，监听配置热加载（日志级别、限流参数、缓存过期时间）
+	router := application.Router()
+	application.WatchConfig()
 
-	// 15. 启动HTTP服务器
+	// 6. 启动HTTP服务器
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	srv := &http.Server{
 		Addr:         addr,
@@ -143,7 +116,7 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
-	// 16. 优雅关闭
+	// 7. 优雅关闭
 	go func() {
 		logger.Info("Server started", zap.String("address", addr))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -151,71 +124,62 @@ func main() {
 		}
 	}()
 
-	// 17. 等待中断信号
+	// 启动gRPC服务器（与HTTP共用Service层，监听独立端口）
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.GRPC.Port)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		grpcServer = application.GRPCServer()
+		go func() {
+			logger.Info("gRPC server started", zap.String("address", grpcAddr))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+	}
+
+	// 8. 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
 
-	// 18. 优雅关闭（5秒超时）
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// 9. 优雅关闭：先等待进行中的请求完成（不再接收新请求，也就不再提交新的后台任务），
+	// 再取消后台任务并等待已提交的任务执行完毕，最后按初始化的相反顺序关闭数据库、Redis与RabbitMQ连接
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to shutdown", zap.Error(err))
+		logger.Error("Server forced to shutdown", zap.Error(err))
+	}
+
+	tasksCtx, tasksCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer tasksCancel()
+	if err := application.Shutdown(tasksCtx); err != nil {
+		logger.Error("Background tasks did not finish before shutdown timeout", zap.Error(err))
 	}
 
 	logger.Info("Server exited")
 }
 
-// setupRoutes 设置路由
-func setupRoutes(
-	router *gin.Engine,
-	authHandler *handler.AuthHandler,
-	walletHandler *handler.WalletHandler,
-	txHandler *handler.TransactionHandler,
-	authService *service.AuthService,
-) {
-	// 健康检查
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "ok",
-			"time":   time.Now().Unix(),
-		})
-	})
-
-	// API v1路由组
-	v1 := router.Group("/api/v1")
-	{
-		// 认证路由（无需JWT）
-		auth := v1.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.GET("/profile", middleware.AuthMiddleware(authService), authHandler.GetProfile)
-		}
-
-		// 钱包路由（需要JWT）
-		wallets := v1.Group("/wallets")
-		wallets.Use(middleware.AuthMiddleware(authService))
-		{
-			wallets.POST("", walletHandler.CreateWallet)
-			wallets.GET("", walletHandler.GetWallets)
-			wallets.GET("/:address", walletHandler.GetWallet)
-			wallets.GET("/:address/balance", walletHandler.GetBalance)
-			wallets.PUT("/:address", walletHandler.UpdateWallet)
-			wallets.DELETE("/:address", walletHandler.DeleteWallet)
-			wallets.GET("/:address/transactions", txHandler.GetWalletTransactions)
-		}
+// stopGRPC 等待进行中的gRPC请求完成，超时后强制关闭连接
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
 
-		// 交易路由（需要JWT）
-		transactions := v1.Group("/transactions")
-		transactions.Use(middleware.AuthMiddleware(authService))
-		{
-			transactions.POST("", txHandler.SendTransaction)
-			transactions.GET("", txHandler.ListTransactions)
-			transactions.GET("/:tx_hash", txHandler.GetTransaction)
-		}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
 	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/pkg/queue"
+)
+
+// dlqQueueName 死信管理命令操作的队列（与交易监控消费者使用同一队列，否则会以不同参数重新声明旧队列）
+const dlqQueueName = service.TransactionCreatedQueue
+
+// runDLQCommand 执行死信队列管理命令
+//
+//	worker dlq list [limit]    查看死信消息（不移除）
+//	worker dlq replay [limit]  将死信消息重新投递到主队列
+func runDLQCommand(mq *queue.RabbitMQ, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: worker dlq <list|replay> [limit]")
+	}
+
+	limit := 100
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid limit: %s", args[1])
+		}
+		limit = n
+	}
+
+	switch args[0] {
+	case "list":
+		letters, err := mq.ListDeadLetters(dlqQueueName, limit)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(letters)
+	case "replay":
+		replayed, err := mq.ReplayDeadLetters(dlqQueueName, limit)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("replayed %d messages from %s.dlq\n", replayed, dlqQueueName)
+		return nil
+	default:
+		return fmt.Errorf("unknown dlq command: %s", args[0])
+	}
+}
@@ -3,22 +3,23 @@ package main
 import (
 	"context"
 	"crypto-wallet-api/internal/logger"
-	"encoding/json"
+	"crypto-wallet-api/internal/utils"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
-	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/app"
 	"crypto-wallet-api/internal/config"
 	"crypto-wallet-api/internal/models"
-	"crypto-wallet-api/internal/repository"
 	"crypto-wallet-api/internal/service"
-	"crypto-wallet-api/pkg/cache"
-	"crypto-wallet-api/pkg/database"
+	"crypto-wallet-api/internal/tracing"
 	"crypto-wallet-api/pkg/queue"
 )
 
@@ -41,88 +42,240 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logger.Logger.Sync()
+	logger.Info("Configuration loaded", zap.Stringer("config", cfg))
 
 	logger.Info("Starting Transaction Monitor Worker...")
 
-	// 3. 连接数据库
-	db, err := database.NewPostgresDB(
-		cfg.Database.GetDSN(),
-		cfg.Database.MaxOpenConns,
-		cfg.Database.MaxIdleConns,
-		cfg.Database.ConnMaxLifetime,
+	// 初始化链路追踪
+	shutdownTracer, err := tracing.InitTracer(
+		cfg.Tracing.Enabled,
+		cfg.Tracing.ServiceName+"-worker",
+		cfg.Tracing.Endpoint,
+		cfg.Tracing.Insecure,
+		cfg.Tracing.SampleRate,
 	)
 	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
+		logger.Fatal("Failed to initialize tracer", zap.Error(err))
 	}
+	defer shutdownTracer(context.Background())
 
-	// 4. 连接Redis
-	redisCache, err := cache.NewRedisCache(
-		cfg.Redis.GetRedisAddr(),
-		cfg.Redis.Password,
-		cfg.Redis.DB,
-		cfg.Redis.PoolSize,
-		cfg.Redis.MinIdleConns,
-	)
+	// 3. 初始化依赖（数据库、Redis、RabbitMQ、区块链客户端与各Service）
+	application, err := app.NewApp(cfg)
 	if err != nil {
-		logger.Fatal("Failed to connect to Redis", zap.Error(err))
+		logger.Fatal("Failed to initialize application", zap.Error(err))
 	}
-	defer redisCache.Close()
+	defer application.Close()
+	mq := application.MQ
+	txService := application.TxService
 
-	// 5. 连接RabbitMQ
-	mq, err := queue.NewRabbitMQ(cfg.RabbitMQ.GetRabbitMQURL())
-	if err != nil {
-		logger.Fatal("Failed to connect to RabbitMQ", zap.Error(err))
+	// 死信队列管理命令（执行后退出）
+	if len(os.Args) > 1 && os.Args[1] == "dlq" {
+		if err := runDLQCommand(mq, os.Args[2:]); err != nil {
+			logger.Fatal("DLQ command failed", zap.Error(err))
+		}
+		return
 	}
-	defer mq.Close()
 
-	// 6. 初始化区块链客户端
-	ethClient, err := blockchain.NewEthereumClient(
-		cfg.Blockchain.Ethereum.RPCURL,
-		cfg.Blockchain.Ethereum.ChainID,
-	)
-	if err != nil {
-		logger.Fatal("Failed to create Ethereum client", zap.Error(err))
-	}
+	// 监听配置热加载（日志级别、缓存过期时间）
+	application.WatchConfig()
 
-	// 7. 初始化服务
-	txRepo := repository.NewTransactionRepository(db)
-	walletRepo := repository.NewWalletRepository(db)
-	encryptionKey := []byte("12345678901234567890123456789012")
-	walletService := service.NewWalletService(walletRepo, ethClient, redisCache, encryptionKey)
-	txService := service.NewTransactionService(txRepo, walletRepo, walletService, ethClient, mq)
+	// 启动后台任务队列（余额写入等异步任务，退出时执行完已提交的任务）
+	application.Start()
 
-	// 8. 创建上下文（支持优雅关闭）
+	// 4. 创建上下文（支持优雅关闭）
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// 9. 启动交易监听消费者
-	if err := mq.ConsumeWithContext(ctx, "transaction.created", func(body []byte) error {
-		var tx models.Transaction
-		if err := json.Unmarshal(body, &tx); err != nil {
-			logger.Error("Failed to unmarshal transaction", zap.Error(err))
-			return err
+	// 暴露Prometheus指标
+	if cfg.Metrics.Enabled {
+		metricsServer := &http.Server{Addr: cfg.Metrics.WorkerAddr, Handler: promhttp.Handler()}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Metrics server failed", zap.Error(err))
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
+	// 启动各链的新区块订阅（配置ws_url时按区块批量检查该链的回执，不可用时由下方轮询路径接管）
+	receiptMonitors := make(map[int]*service.ReceiptMonitor)
+	for _, chain := range cfg.Blockchain.Connected() {
+		receiptMonitors[chain.ChainID] = service.NewReceiptMonitor(txService, chain.WSURL, chain.ChainID, 30*time.Second)
+		go receiptMonitors[chain.ChainID].Run(ctx)
+	}
+
+	// 启动发件箱分发（将交易事件投递到RabbitMQ）
+	outboxDispatcher := service.NewOutboxDispatcher(application.OutboxRepo, mq, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize, cfg.Outbox.Retention)
+	go outboxDispatcher.Run(ctx)
+
+	// 启动各链的代币入账扫描（ERC-20 Transfer事件，按该链的确认深度确认）
+	for _, chain := range cfg.Blockchain.Connected() {
+		client, _ := application.Chains.Get(chain.ChainID)
+		tokenDepositScanner := service.NewTokenDepositScanner(
+			application.TxRepo,
+			application.WalletRepo,
+			application.CursorRepo,
+			application.TokenService,
+			client,
+			application.EventService,
+			cfg.Tokens.DepositPollInterval,
+			cfg.Tokens.DepositBlockRange,
+			chain.Confirmations,
+		)
+		tokenDepositScanner.SetLocker(application.Redis)
+		go tokenDepositScanner.Run(ctx)
+	}
+
+	// 启动定时余额快照
+	snapshotScheduler := service.NewBalanceSnapshotScheduler(application.BalanceHistoryService, cfg.BalanceHistory.SnapshotInterval, cfg.BalanceHistory.HourlyRetention)
+	snapshotScheduler.SetLocker(application.Redis)
+	go snapshotScheduler.Run(ctx)
+
+	// 启动余额对账（批量查询链上余额，修正漂移的钱包余额）
+	application.ReconciliationService.SetLocker(application.Redis)
+	go application.ReconciliationService.Run(ctx, cfg.Reconciliation.Interval)
+
+	// 启动后台余额刷新（有界并发，按批查询链上余额）
+	go application.BalanceRefresher.Run(ctx)
+
+	// 启动待刷新余额重试（交易确认后提交后的余额刷新失败或进程退出时，按钱包上的待刷新标记补刷新）
+	go func() {
+		ticker := time.NewTicker(cfg.BalanceRefresh.RetryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				application.WalletService.RefreshStaleBalances(ctx, cfg.BalanceRefresh.RetryInterval)
+			}
 		}
+	}()
+
+	// 启动Gas价格预言机
+	go application.GasOracle.Run(ctx, cfg.GasOracle.RefreshInterval)
+
+	// 启动链头监控（按各链的health_check_interval检查，节点长时间未同步到新区块时暂停该链的定期转账等发送）
+	go application.ChainHealth.Run(ctx)
 
-		logger.Info("Monitoring transaction", zap.String("tx_hash", tx.TxHash))
+	// 5. 启动各链的交易确认调度：订阅正常时由新区块驱动，否则按该链的间隔批量检查到期的待确认交易（按交易年龄分级退避）
+	monitorKicks := make(map[int]chan struct{})
+	for _, chain := range cfg.Blockchain.Connected() {
+		kick := make(chan struct{}, 1)
+		monitorKicks[chain.ChainID] = kick
+		receiptMonitor := receiptMonitors[chain.ChainID]
+		go func(chainID int, interval time.Duration) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
 
-		// 轮询监听交易状态（最多5分钟）
-		for i := 0; i < 60; i++ {
-			time.Sleep(5 * time.Second)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				case <-kick:
+				}
+				if !receiptMonitor.Subscribed() {
+					txService.BatchMonitor(ctx, chainID, cfg.Monitor.Concurrency)
+				}
+			}
+		}(chain.ChainID, chain.MonitorInterval(cfg.Monitor.PollInterval))
+	}
 
-			err := txService.MonitorTransaction(ctx, tx.TxHash)
-			if err == nil {
-				logger.Info("Transaction confirmed", zap.String("tx_hash", tx.TxHash))
-				return nil
+	// 转发升级前的交易监听队列中的消息（旧队列没有死信参数，新队列使用新名称；滚动升级期间旧版本API仍可能发布到旧队列）
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			moved, err := mq.MoveLegacyQueue(ctx, service.LegacyTransactionCreatedQueue, service.TransactionCreatedQueue)
+			if err != nil && ctx.Err() == nil {
+				logger.Warn("Failed to move legacy transaction messages", zap.Int("moved", moved), zap.Error(err))
+			} else if moved > 0 {
+				logger.Info("Moved legacy transaction messages", zap.Int("moved", moved))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
 			}
 		}
+	}()
+
+	// 启动交易监听消费者（交易已以pending状态入库，消息用于将交易设为立即检查并触发一轮检查；
+	// 写入失败时由队列重试；兼容升级前发布的完整交易记录，无法解析、校验失败或交易哈希格式错误的消息直接转入死信队列）
+	if err := mq.ConsumeWithContext(ctx, service.TransactionCreatedQueue, func(msgCtx context.Context, body []byte) error {
+		var msg models.TransactionCreatedMessage
+		if err := application.QueueCodec.Open(body, &msg); err != nil {
+			logger.Error("Rejected transaction message", zap.Error(err))
+			return fmt.Errorf("%w: %v", queue.ErrReject, err)
+		}
+		if !utils.IsTxHash(msg.TxHash) {
+			logger.Error("Rejected transaction message: malformed tx hash", zap.String("tx_hash", msg.TxHash))
+			return fmt.Errorf("%w: malformed tx hash %q", queue.ErrReject, msg.TxHash)
+		}
 
-		logger.Warn("Transaction confirmation timeout", zap.String("tx_hash", tx.TxHash))
+		logger.Info("Transaction queued for monitoring",
+			zap.String("tx_hash", msg.TxHash),
+			zap.Int("chain_id", msg.ChainID),
+			zap.Uint("wallet_id", msg.WalletID),
+		)
+		if err := txService.SeedMonitoring(msgCtx, msg.TxHash); err != nil {
+			return fmt.Errorf("seed transaction monitoring: %w", err)
+		}
+		select {
+		case monitorKicks[msg.ChainID] <- struct{}{}:
+		default:
+		}
 		return nil
 	}); err != nil {
 		logger.Fatal("Failed to start consumer", zap.Error(err))
 	}
 
-	// 10. 启动定时任务：扫描待确认交易
+	// 启动测试链领水消费者（仅配置了水龙头时；可重试的错误由队列重试，其他错误转入死信队列）
+	if application.FaucetService != nil {
+		if err := mq.ConsumeWithContext(ctx, service.WalletFundingQueue, func(msgCtx context.Context, body []byte) error {
+			var msg models.WalletFundingMessage
+			if err := application.QueueCodec.Open(body, &msg); err != nil {
+				logger.Error("Rejected wallet funding message", zap.Error(err))
+				return fmt.Errorf("%w: %v", queue.ErrReject, err)
+			}
+			return application.FaucetService.Fund(msgCtx, &msg)
+		}); err != nil {
+			logger.Fatal("Failed to start wallet funding consumer", zap.Error(err))
+		}
+	}
+
+	// 启动外发邮件消费者（发送失败由队列按退避重试，无法解析、模板不存在或收件人被拒的邮件转入死信队列）
+	if err := mq.ConsumeWithContext(ctx, service.EmailSendQueue, func(msgCtx context.Context, body []byte) error {
+		var msg models.EmailSendMessage
+		if err := application.QueueCodec.Open(body, &msg); err != nil {
+			logger.Error("Rejected email message", zap.Error(err))
+			return fmt.Errorf("%w: %v", queue.ErrReject, err)
+		}
+		return application.EmailService.Deliver(msgCtx, &msg)
+	}); err != nil {
+		logger.Fatal("Failed to start email consumer", zap.Error(err))
+	}
+
+	// 启动异步导出消费者（生成失败由队列按退避重试，无法解析的消息转入死信队列）
+	if err := mq.ConsumeWithContext(ctx, service.ExportJobQueue, func(msgCtx context.Context, body []byte) error {
+		var msg models.ExportJobMessage
+		if err := application.QueueCodec.Open(body, &msg); err != nil {
+			logger.Error("Rejected export job message", zap.Error(err))
+			return fmt.Errorf("%w: %v", queue.ErrReject, err)
+		}
+		return application.ExportService.Generate(msgCtx, msg.JobID)
+	}); err != nil {
+		logger.Fatal("Failed to start export consumer", zap.Error(err))
+	}
+
+	// 启动过期导出文件清理
+	go application.ExportService.RunCleanup(ctx, cfg.Exports.CleanupInterval)
+
+	// 6. 启动定时任务：过期待审批交易
 	go func() {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
@@ -132,14 +285,29 @@ func main() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				scanPendingTransactions(ctx, txService)
+				txService.ExpireStaleApprovals(ctx)
+			}
+		}
+	}()
+
+	// 启动定时任务：执行到期的定期转账
+	go func() {
+		ticker := time.NewTicker(cfg.Recurring.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				application.RecurringService.RunDue(ctx, cfg.Recurring.BatchSize)
 			}
 		}
 	}()
 
 	logger.Info("Worker started successfully")
 
-	// 11. 等待中断信号
+	// 7. 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -147,31 +315,18 @@ func main() {
 	logger.Info("Shutting down worker...")
 	cancel()
 
-	// 等待所有goroutine结束
-	time.Sleep(2 * time.Second)
-	logger.Info("Worker exited")
-}
-
-// scanPendingTransactions 扫描待确认的交易
-func scanPendingTransactions(ctx context.Context, txService *service.TransactionService) {
-	transactions, err := txService.GetPendingTransactions(ctx)
-	if err != nil {
-		logger.Error("Failed to get pending transactions", zap.Error(err))
-		return
+	// 等待处理中的消息完成（超时后中断并重新入队），随后关闭RabbitMQ连接
+	if mq.Drain(cfg.RabbitMQ.DrainTimeout) {
+		logger.Info("In-flight messages drained")
+	} else {
+		logger.Warn("Drain timeout exceeded, interrupted messages were requeued", zap.Duration("timeout", cfg.RabbitMQ.DrainTimeout))
 	}
 
-	logger.Info("Scanning pending transactions", zap.Int("count", len(transactions)))
-
-	for _, tx := range transactions {
-		// 检查交易是否超时（超过10分钟）
-		if time.Since(tx.CreatedAt) > 10*time.Minute {
-			logger.Warn("Transaction timeout", zap.String("tx_hash", tx.TxHash))
-			continue
-		}
-
-		// 监听交易状态
-		if err := txService.MonitorTransaction(ctx, tx.TxHash); err != nil {
-			logger.Debug("Transaction not confirmed yet", zap.String("tx_hash", tx.TxHash))
-		}
+	// 等待后台任务执行完毕（与消息排空共用超时），随后关闭连接
+	tasksCtx, tasksCancel := context.WithTimeout(context.Background(), cfg.RabbitMQ.DrainTimeout)
+	defer tasksCancel()
+	if err := application.Shutdown(tasksCtx); err != nil {
+		logger.Error("Background tasks did not finish before shutdown timeout", zap.Error(err))
 	}
+	logger.Info("Worker exited")
 }
@@ -0,0 +1,514 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/config"
+	"crypto-wallet-api/internal/grpcapi"
+	"crypto-wallet-api/internal/handler"
+	"crypto-wallet-api/internal/lifecycle"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/middleware"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/database"
+	"crypto-wallet-api/pkg/keys"
+	"crypto-wallet-api/pkg/mailer"
+	"crypto-wallet-api/pkg/pricing"
+	"crypto-wallet-api/pkg/queue"
+)
+
+// App API服务与Worker共用的依赖（连接、Repository与Service），由NewApp统一初始化
+type App struct {
+	Config *config.Config
+
+	// 外部连接
+	DB         *gorm.DB
+	Replica    *database.Replica // 只读副本（未配置时为nil）
+	Redis      *cache.RedisCache
+	Cache      cache.Cache // Redis之上可选的进程内缓存层
+	MQ         *queue.RabbitMQ
+	Chains     *blockchain.Clients // 各条配置了节点的链的客户端（按链ID路由）
+	QueueCodec *service.QueueCodec // 队列消息编解码（配置队列密钥时加密）
+
+	// 后台任务
+	Lifecycle *lifecycle.Manager   // 后台任务的启动与有序关闭（Start启动，Shutdown关闭）
+	Jobs      *lifecycle.JobRunner // 请求处理中提交的异步任务
+
+	// Repository层（仅暴露Worker直接使用的部分）
+	TxRepo     *repository.TransactionRepository
+	WalletRepo *repository.WalletRepository
+	OutboxRepo *repository.OutboxRepository
+	CursorRepo *repository.ChainCursorRepository
+
+	// Service层
+	EventService          *service.EventService
+	FeatureFlagService    *service.FeatureFlagService
+	NotificationService   *service.NotificationService
+	EmailService          *service.EmailService // 外发邮件（API发布到队列，Worker发送）
+	ContactService        *service.ContactService
+	LabelService          *service.LabelService
+	ActivityService       *service.ActivityService
+	PriceClient           *pricing.CoinGeckoClient
+	AuthService           *service.AuthService
+	APIKeyService         *service.APIKeyService
+	StatsService          *service.StatsService
+	AdminStatsService     *service.AdminStatsService
+	ExportService         *service.ExportService
+	WalletService         *service.WalletService
+	BalanceHistoryService *service.BalanceHistoryService
+	WhitelistService      *service.WhitelistService
+	LimitService          *service.LimitService
+	ContractService       *service.ContractService
+	TxService             *service.TransactionService
+	GasOracle             *service.GasOracle
+	ChainHealth           *service.ChainHealthMonitor
+	ChainService          *service.ChainService
+	TokenService          *service.TokenService
+	OrgService            *service.OrganizationService
+	RecurringService      *service.RecurringPaymentService
+	BalanceRefresher      *service.BalanceRefresher
+	ReconciliationService *service.ReconciliationService
+	FaucetService         *service.FaucetService // 测试链水龙头（未配置时为nil）
+
+	rateLimiter   *middleware.RateLimiter // Router或GRPCServer创建后用于热加载限流参数
+	routeLimiters *RouteLimiters          // Router创建后用于热加载按路由组的限流参数
+	closers       []func()                // 按初始化顺序记录的释放函数，Close时倒序执行
+}
+
+// NewApp 按配置建立连接并初始化全部Repository与Service（任一步骤失败时释放已建立的连接）
+func NewApp(cfg *config.Config) (_ *App, err error) {
+	a := &App{Config: cfg}
+	defer func() {
+		if err != nil {
+			a.Close()
+		}
+	}()
+
+	// 1. 加载加密密钥，注册加密字段序列化器（需在访问数据库之前）
+	keyProvider, err := cfg.Keys.NewProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	piiKey, err := keyProvider.Key(keys.PIIEncryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PII encryption key: %w", err)
+	}
+	database.RegisterEncryptedSerializer(piiKey)
+	encryptionKey, err := keyProvider.Key(keys.WalletEncryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet encryption key: %w", err)
+	}
+	emailHMACKey, err := keyProvider.Key(keys.EmailHMAC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email HMAC key: %w", err)
+	}
+	queueKey, err := keyProvider.Key(keys.QueueEncryption)
+	if err != nil && !errors.Is(err, keys.ErrKeyNotFound) {
+		return nil, fmt.Errorf("failed to load queue encryption key: %w", err)
+	}
+	a.QueueCodec = service.NewQueueCodec(queueKey)
+
+	// 2. 连接数据库（开发模式下由调用方按模型自动建表，否则检查数据库版本）
+	a.DB, err = database.NewPostgresDB(
+		cfg.Database.GetDSN(),
+		cfg.Database.MaxOpenConns,
+		cfg.Database.MaxIdleConns,
+		cfg.Database.ConnMaxLifetime,
+		cfg.Database.LogLevel,
+		cfg.Database.SlowThreshold,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	a.onClose(func() {
+		if sqlDB, err := a.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	logger.Info("Database connected successfully")
+	if !cfg.Database.AutoMigrate {
+		if err := database.CheckSchemaVersion(context.Background(), a.DB); err != nil {
+			return nil, fmt.Errorf("database schema is not up to date: %w", err)
+		}
+	}
+
+	// 可选的只读副本（可容忍复制延迟的只读查询使用，延迟超限时回退到主库）
+	if cfg.Database.ReplicaEnabled() {
+		replicaCfg := cfg.Database.ReplicaDatabase()
+		var replicaDB *gorm.DB
+		replicaDB, err = database.NewPostgresDB(
+			replicaCfg.GetDSN(),
+			replicaCfg.MaxOpenConns,
+			replicaCfg.MaxIdleConns,
+			replicaCfg.ConnMaxLifetime,
+			replicaCfg.LogLevel,
+			replicaCfg.SlowThreshold,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("database replica: %w", err)
+		}
+		a.onClose(func() {
+			if sqlDB, err := replicaDB.DB(); err == nil {
+				sqlDB.Close()
+			}
+		})
+		a.Replica = database.NewReplica(a.DB, replicaDB, cfg.Database.Replica.MaxLag)
+		logger.Info("Database replica connected successfully")
+	}
+
+	// 3. 连接Redis
+	a.Redis, err = cache.NewRedisCache(
+		cfg.Redis.GetRedisAddr(),
+		cfg.Redis.Password,
+		cfg.Redis.DB,
+		cfg.Redis.PoolSize,
+		cfg.Redis.MinIdleConns,
+		cfg.Redis.OpTimeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	a.onClose(func() { a.Redis.Close() })
+	logger.Info("Redis connected successfully")
+
+	// 可选的进程内缓存层
+	a.Cache = a.Redis
+	if cfg.Cache.LocalSize > 0 {
+		a.Cache = cache.NewTieredCache(cache.NewMemoryCache(cfg.Cache.LocalSize), a.Redis, cfg.Cache.LocalTTL)
+	}
+
+	// 4. 连接RabbitMQ
+	a.MQ, err = queue.NewRabbitMQ(
+		cfg.RabbitMQ.GetRabbitMQURL(),
+		cfg.RabbitMQ.PublishTimeout,
+		cfg.RabbitMQ.MaxRetries,
+		cfg.RabbitMQ.RetryBaseDelay,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq: %w", err)
+	}
+	a.MQ.SetConsumerConcurrency(cfg.Worker.Prefetch, cfg.Worker.Concurrency)
+	a.onClose(func() { a.MQ.Close() })
+	logger.Info("RabbitMQ connected successfully")
+
+	// 创建外发邮件发送器（driver为log时只记录日志不发送）
+	mail, err := newMailer(cfg.Mail)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: %w", err)
+	}
+
+	// 5. 注册已配置的链并为每条配置了节点的链初始化区块链客户端
+	models.SetChains(cfg.Blockchain.Metadata())
+	var clients []blockchain.BlockchainClient
+	for _, chain := range cfg.Blockchain.Connected() {
+		ethClient, err := blockchain.NewFailoverClient(
+			chain.RPCURLs,
+			chain.ChainID,
+			chain.Strategy,
+			chain.MaxBlockLag,
+			chain.HealthCheckInterval,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ethereum client for chain %d: %w", chain.ChainID, err)
+		}
+		a.onClose(ethClient.Close)
+		clients = append(clients, blockchain.NewTracedClient(blockchain.NewTimeoutClient(ethClient, cfg.Blockchain.RPCTimeout, cfg.Blockchain.SendTimeout)))
+		logger.Info("Ethereum client initialized successfully", zap.Int("chain_id", chain.ChainID), zap.String("chain", chain.Name))
+	}
+	a.Chains = blockchain.NewClients(clients...)
+
+	// 6. 初始化Repository层与Service层
+	a.initServices(encryptionKey, emailHMACKey, mail)
+	return a, nil
+}
+
+// newMailer 按配置创建邮件发送器
+func newMailer(cfg config.MailConfig) (mailer.Mailer, error) {
+	if cfg.Driver != "smtp" {
+		return mailer.LogMailer{}, nil
+	}
+	return mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+		TLSMode:  cfg.TLSMode,
+		Timeout:  cfg.Timeout,
+	})
+}
+
+// initServices 初始化Repository层与Service层
+func (a *App) initServices(encryptionKey, emailHMACKey []byte, mail mailer.Mailer) {
+	cfg := a.Config
+	db := a.DB
+
+	// 1. Repository层
+	userRepo := repository.NewUserRepository(db, emailHMACKey)
+	a.WalletRepo = repository.NewWalletRepository(db)
+	a.TxRepo = repository.NewTransactionRepository(db)
+	a.OutboxRepo = repository.NewOutboxRepository(db)
+	a.CursorRepo = repository.NewChainCursorRepository(db)
+	contactRepo := repository.NewContactRepository(db)
+	whitelistRepo := repository.NewWhitelistRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	spendRepo := repository.NewSpendLedgerRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	recurringRepo := repository.NewRecurringPaymentRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	loginRepo := repository.NewLoginHistoryRepository(db)
+	snapshotRepo := repository.NewBalanceSnapshotRepository(db)
+	featureFlagRepo := repository.NewFeatureFlagRepository(db)
+	reconRepo := repository.NewReconciliationRepository(db)
+	labelRepo := repository.NewLabelRepository(db)
+	if a.Replica != nil {
+		a.TxRepo.SetReplica(a.Replica)
+		activityRepo.SetReplica(a.Replica)
+	}
+
+	// 2. 后台任务队列（最先注册，关闭时最后停止，执行完其他后台任务退出前提交的任务）
+	a.Lifecycle = lifecycle.New()
+	a.Jobs = lifecycle.NewJobRunner(cfg.Jobs.Workers, cfg.Jobs.QueueSize)
+	a.Lifecycle.Register("jobs", a.Jobs)
+	if a.Replica != nil {
+		a.Lifecycle.Go("db_replica", func(ctx context.Context) {
+			a.Replica.Run(ctx, cfg.Database.Replica.CheckInterval)
+		})
+	}
+
+	// 3. Service层
+	a.EventService = service.NewEventService(a.Redis)
+	a.FeatureFlagService = service.NewFeatureFlagService(a.Redis, featureFlagRepo, cfg.FeatureFlags.RefreshInterval)
+	a.NotificationService = service.NewNotificationService(notificationRepo, userRepo, a.WalletRepo, orgRepo, cfg.Notifications.WebhookTimeout)
+	a.EventService.OnPublish(a.NotificationService.HandleEvent)
+	a.EmailService = service.NewEmailService(a.MQ, a.QueueCodec, mail)
+	a.NotificationService.SetSender(models.NotificationChannelEmail, service.NewEmailNotificationSender(a.EmailService))
+	a.ContactService = service.NewContactService(contactRepo)
+	a.LabelService = service.NewLabelService(labelRepo, contactRepo)
+	a.ActivityService = service.NewActivityService(activityRepo, a.TxRepo, a.WalletRepo, a.ContactService)
+	a.PriceClient = pricing.NewCoinGeckoClient(cfg.Pricing.BaseURL, cfg.Pricing.APIKey, cfg.Pricing.Timeout, a.Cache)
+	a.AuthService = service.NewAuthService(userRepo, loginRepo, a.Redis, a.EventService, cfg.JWT.Secret, cfg.JWT.ExpireHours)
+	a.AuthService.SetPasswordPolicy(cfg.PasswordPolicy.Policy())
+	a.APIKeyService = service.NewAPIKeyService(apiKeyRepo)
+	a.StatsService = service.NewStatsService(a.TxRepo, a.WalletRepo, a.Cache)
+	a.AdminStatsService = service.NewAdminStatsService(userRepo, a.WalletRepo, a.TxRepo, a.MQ, a.Redis, a.Chains.All()...)
+	a.ExportService = service.NewExportService(a.TxRepo, a.WalletRepo, repository.NewExportJobRepository(db), a.Redis, a.QueueCodec, cfg.JWT.Secret, cfg.Exports.Dir, cfg.Exports.FileTTL, cfg.Exports.TokenTTL)
+	a.ChainHealth = service.NewChainHealthMonitor(a.Chains.All()...)
+	for _, chain := range cfg.Blockchain.Connected() {
+		a.ChainHealth.SetStaleAfter(chain.ChainID, chain.HeadStaleAfter())
+		a.ChainHealth.SetCheckInterval(chain.ChainID, chain.HealthCheckInterval)
+	}
+	a.WalletService = service.NewWalletService(a.WalletRepo, userRepo, a.Chains, a.Cache, a.EventService, a.PriceClient, a.ActivityService, encryptionKey)
+	a.WalletService.SetChainHealth(a.ChainHealth)
+	a.WalletService.SetJobRunner(a.Jobs)
+	a.WalletService.SetWalletQuota(cfg.Wallets.MaxPerUser)
+	a.WalletService.SetBulkConcurrency(cfg.Wallets.BulkConcurrency)
+	if cfg.KeyCache.Enabled {
+		a.WalletService.EnableKeyCache(cfg.KeyCache.TTL, cfg.KeyCache.MaxSize)
+	}
+	a.onClose(a.WalletService.Close)
+	a.BalanceHistoryService = service.NewBalanceHistoryService(snapshotRepo, a.WalletRepo, a.WalletService)
+	a.WalletService.SetBalanceHistory(a.BalanceHistoryService)
+	a.WhitelistService = service.NewWhitelistService(whitelistRepo, a.WalletService, a.ActivityService, cfg.Whitelist.CoolingOffPeriod)
+	a.LimitService = service.NewLimitService(spendRepo)
+	a.ContractService = service.NewContractService(a.Chains)
+	a.TxService = service.NewTransactionService(a.TxRepo, a.WalletRepo, a.WalletService, a.Chains, a.EventService, a.ContactService, a.WhitelistService, a.LimitService)
+	a.TxService.SetChainParams(cfg.Blockchain.Params()...)
+	a.TxService.SetApprovalTTL(cfg.Approval.TTL)
+	a.TxService.SetLocker(a.Redis)
+	a.TxService.SetFeatureFlags(a.FeatureFlagService)
+	a.TxService.SetQueueCodec(a.QueueCodec)
+	a.TxService.SetChainHealth(a.ChainHealth)
+	a.TxService.SetLabelService(a.LabelService)
+	a.TxService.SetMonitorSchedule(service.MonitorSchedule{
+		BatchSize:      cfg.Monitor.BatchSize,
+		FreshAge:       cfg.Monitor.FreshAge,
+		FreshInterval:  cfg.Monitor.FreshInterval,
+		RecentAge:      cfg.Monitor.RecentAge,
+		RecentInterval: cfg.Monitor.RecentInterval,
+		StaleInterval:  cfg.Monitor.StaleInterval,
+		StuckAfter:     cfg.Monitor.StuckAfter,
+		UnknownGrace:   cfg.Monitor.UnknownGrace,
+	})
+	if faucets := cfg.Blockchain.Faucets(); len(faucets) > 0 {
+		a.FaucetService = service.NewFaucetService(a.WalletRepo, a.TxService, a.Redis, a.QueueCodec, faucets)
+		a.WalletService.SetFaucet(a.FaucetService)
+	}
+	a.GasOracle = service.NewGasOracle(a.Redis, cfg.GasOracle.BlockCount, cfg.GasOracle.CacheTTL, a.Chains.All()...)
+	a.GasOracle.SetChainParams(cfg.Blockchain.Params()...)
+	a.TxService.SetGasOracle(a.GasOracle)
+	a.ChainService = service.NewChainService(a.GasOracle, a.ChainHealth, a.FeatureFlagService)
+	if cfg.ENS.Enabled {
+		ensService := service.NewENSService(a.Chains, a.Cache, cfg.ENS.CacheTTL, cfg.ENS.ReverseLookup)
+		a.ContactService.SetENSService(ensService)
+		a.TxService.SetENSService(ensService)
+	}
+	a.TokenService = service.NewTokenService(tokenRepo, a.WalletRepo, a.Chains, a.Cache, cfg.Tokens.MetadataTTL, cfg.Tokens.BalanceConcurrency)
+	a.OrgService = service.NewOrganizationService(orgRepo, a.WalletRepo)
+	a.RecurringService = service.NewRecurringPaymentService(recurringRepo, a.WalletRepo, a.TxService, a.EventService, cfg.Recurring.MaxFailures)
+	a.BalanceRefresher = service.NewBalanceRefresher(a.WalletService, a.Chains, cfg.BalanceRefresh.Workers, cfg.BalanceRefresh.BatchSize)
+	a.WalletService.SetBalanceRefresher(a.BalanceRefresher)
+	a.ReconciliationService = service.NewReconciliationService(reconRepo, a.WalletRepo, a.WalletService, a.Chains, cfg.Reconciliation.BatchSize, cfg.Reconciliation.ThresholdWei)
+	a.applyCacheTTLs(cfg)
+}
+
+// applyCacheTTLs 应用缓存过期时间配置
+func (a *App) applyCacheTTLs(c *config.Config) {
+	a.WalletService.SetBalanceCacheTTL(c.Cache.BalanceTTL)
+	a.PriceClient.SetCacheTTL(c.Cache.PriceTTL)
+	a.StatsService.SetCacheTTL(c.Cache.StatsTTL)
+}
+
+// Router 创建API服务的Gin引擎（全局中间件、全部路由与可选的/metrics）
+func (a *App) Router() *gin.Engine {
+	cfg := a.Config
+
+	// 1. 初始化Gin引擎
+	if cfg.Server.Mode == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	utils.InitValidator()
+
+	// 2. 注册全局中间件
+	router.Use(middleware.TracingMiddleware())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.LoggerMiddleware())
+	if cfg.Server.Gzip {
+		router.Use(middleware.GzipMiddleware(cfg.Server.GzipMinSize))
+	}
+	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.RecoveryMiddleware())
+	router.Use(middleware.TimeoutMiddleware(cfg.Server.RequestTimeout))
+	router.Use(a.limiter().Middleware())
+
+	// 3. 注册路由
+	a.routeLimiters = &RouteLimiters{
+		Expensive: middleware.NewUserRateLimiter(models.RateLimitBucketExpensive, cfg.RateLimit.Expensive.RequestsPerSecond, cfg.RateLimit.Expensive.Burst),
+		Export:    middleware.NewUserRateLimiter(models.RateLimitBucketExport, cfg.RateLimit.Export.RequestsPerSecond, cfg.RateLimit.Export.Burst),
+	}
+	SetupRoutes(router, a.handlers(), a.AuthService, a.APIKeyService, a.FeatureFlagService, a.routeLimiters, cfg.Server.SendTimeout)
+	if cfg.Metrics.Enabled {
+		router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+	return router
+}
+
+// GRPCServer 创建gRPC服务器（与HTTP共用Service层与限流令牌桶）
+func (a *App) GRPCServer() *grpc.Server {
+	return grpcapi.NewServer(&grpcapi.Services{
+		Auth:        a.AuthService,
+		APIKey:      a.APIKeyService,
+		Wallet:      a.WalletService,
+		Transaction: a.TxService,
+	}, a.limiter())
+}
+
+// limiter 获取全局限流器（HTTP与gRPC共用，首次调用时创建）
+func (a *App) limiter() *middleware.RateLimiter {
+	if a.rateLimiter == nil {
+		a.rateLimiter = middleware.NewRateLimiter(a.Config.RateLimit.RequestsPerSecond, a.Config.RateLimit.Burst)
+	}
+	return a.rateLimiter
+}
+
+// handlers 创建全部HTTP处理器
+func (a *App) handlers() *Handlers {
+	cfg := a.Config
+	return &Handlers{
+		Health:         handler.NewHealthHandler(a.DB, a.Redis, a.MQ, a.Chains, a.ChainHealth),
+		Auth:           handler.NewAuthHandler(a.AuthService),
+		Wallet:         handler.NewWalletHandler(a.WalletService),
+		Transaction:    handler.NewTransactionHandler(a.TxService),
+		APIKey:         handler.NewAPIKeyHandler(a.APIKeyService),
+		Contact:        handler.NewContactHandler(a.ContactService),
+		Whitelist:      handler.NewWhitelistHandler(a.WhitelistService),
+		Contract:       handler.NewContractHandler(a.ContractService),
+		Stats:          handler.NewStatsHandler(a.StatsService),
+		Export:         handler.NewExportHandler(a.ExportService),
+		Recurring:      handler.NewRecurringPaymentHandler(a.RecurringService),
+		Activity:       handler.NewActivityHandler(a.ActivityService),
+		BalanceHistory: handler.NewBalanceHistoryHandler(a.BalanceHistoryService),
+		Token:          handler.NewTokenHandler(a.TokenService),
+		Organization:   handler.NewOrganizationHandler(a.OrgService),
+		Notification:   handler.NewNotificationHandler(a.NotificationService),
+		Gas:            handler.NewGasHandler(a.GasOracle),
+		Chain:          handler.NewChainHandler(a.ChainService),
+		Admin:          handler.NewAdminHandler(a.FeatureFlagService, a.AdminStatsService, a.TxService, a.ReconciliationService),
+		Label:          handler.NewLabelHandler(a.LabelService),
+		WebSocket: handler.NewWebSocketHandler(
+			a.AuthService,
+			a.WalletService,
+			a.EventService,
+			cfg.WebSocket.MaxSubscriptions,
+			cfg.WebSocket.AuthTimeout,
+			cfg.WebSocket.PingInterval,
+		),
+	}
+}
+
+// WatchConfig 监听配置热加载（日志级别、缓存过期时间，创建过Router或GRPCServer时包括全局与按路由组的限流参数）
+func (a *App) WatchConfig() {
+	config.OnChange(func(c *config.Config) interface{} { return c.Log.Level }, func(c *config.Config) {
+		logger.SetLevel(c.Log.Level)
+	})
+	if a.rateLimiter != nil {
+		config.OnChange(func(c *config.Config) interface{} { return c.RateLimit }, func(c *config.Config) {
+			a.rateLimiter.Update(c.RateLimit.RequestsPerSecond, c.RateLimit.Burst)
+		})
+	}
+	if a.routeLimiters != nil {
+		config.OnChange(func(c *config.Config) interface{} { return c.RateLimit }, func(c *config.Config) {
+			a.routeLimiters.Expensive.Update(c.RateLimit.Expensive.RequestsPerSecond, c.RateLimit.Expensive.Burst)
+			a.routeLimiters.Export.Update(c.RateLimit.Export.RequestsPerSecond, c.RateLimit.Export.Burst)
+		})
+	}
+	config.OnChange(func(c *config.Config) interface{} { return c.Cache }, a.applyCacheTTLs)
+	// 链的启用策略可热加载，链列表本身（节点与参数）仍以启动时的配置为准
+	config.OnChange(func(c *config.Config) interface{} {
+		return [2][]int{c.Blockchain.AllowedChains, c.Blockchain.DeniedChains}
+	}, func(c *config.Config) {
+		models.SetChains(c.Blockchain.Metadata())
+	})
+	config.Watch()
+}
+
+// onClose 登记释放函数
+func (a *App) onClose(fn func()) {
+	a.closers = append(a.closers, fn)
+}
+
+// Start 启动已注册的后台任务（包括后台任务队列，调用方在此之前通过Lifecycle.Go注册各自的循环任务）
+func (a *App) Start() {
+	a.Lifecycle.Start()
+}
+
+// Shutdown 取消后台任务并等待退出（后台任务队列执行完已提交的任务，最长等待到ctx到期），随后调用Close关闭连接
+func (a *App) Shutdown(ctx context.Context) error {
+	err := a.Lifecycle.Shutdown(ctx)
+	a.Close()
+	return err
+}
+
+// Close 按初始化的相反顺序释放资源（可重复调用）
+func (a *App) Close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+	a.closers = nil
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+)
+
+func TestWalletETag(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+	wallet := a.createWallet(t, user, ether(0))
+	path := "/api/v1/wallets/" + wallet.Address
+
+	first := a.do(t, http.MethodGet, path, user.Token, nil)
+	etag := first.Header.Get("ETag")
+	if first.Status != http.StatusOK || etag == "" {
+		t.Fatalf("first request: status = %d, etag = %q, want 200 with an ETag", first.Status, etag)
+	}
+
+	// 数据未变化：If-None-Match匹配时返回304，不返回响应体
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		language    string
+		status      int
+	}{
+		{"same etag", etag, "", http.StatusNotModified},
+		{"one of several etags", `W/"stale", ` + etag, "", http.StatusNotModified},
+		{"wildcard", "*", "", http.StatusNotModified},
+		{"different etag", `W/"stale"`, "", http.StatusOK},
+		{"different language", etag, "zh-CN", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"If-None-Match": {tt.ifNoneMatch}}
+			if tt.language != "" {
+				header.Set("Accept-Language", tt.language)
+			}
+			resp := a.doWithHeader(t, http.MethodGet, path, user.Token, nil, header)
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d, want %d", resp.Status, tt.status)
+			}
+			if resp.Status == http.StatusNotModified && (resp.Data != nil || resp.Header.Get("ETag") != etag) {
+				t.Errorf("304 response: data = %s, etag = %q, want no body and the same ETag", resp.Data, resp.Header.Get("ETag"))
+			}
+		})
+	}
+
+	// 修改钱包后旧ETag不再匹配
+	name := "renamed"
+	if resp := a.do(t, http.MethodPatch, path, user.Token, models.WalletUpdateRequest{Name: &name}); resp.Status != http.StatusOK {
+		t.Fatalf("patch wallet: status = %d (%s)", resp.Status, resp.Message)
+	}
+	resp := a.doWithHeader(t, http.MethodGet, path, user.Token, nil, http.Header{"If-None-Match": {etag}})
+	if resp.Status != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("after update: status = %d, etag = %q, want 200 with a new ETag", resp.Status, resp.Header.Get("ETag"))
+	}
+}
+
+func TestTransactionListETag(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+	wallet := a.createWallet(t, user, ether(10))
+	resp := a.do(t, http.MethodPost, "/api/v1/transactions", user.Token, models.TransactionCreateRequest{
+		FromAddress: wallet.Address, ToAddress: recipient, Amount: "1000", ChainID: chainID,
+	})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("send: status = %d (%s)", resp.Status, resp.Message)
+	}
+	var tx models.TransactionResponse
+	resp.decode(t, &tx)
+
+	const path = "/api/v1/transactions"
+	first := a.do(t, http.MethodGet, path, user.Token, nil)
+	etag := first.Header.Get("ETag")
+	if first.Status != http.StatusOK || etag == "" {
+		t.Fatalf("first request: status = %d, etag = %q, want 200 with an ETag", first.Status, etag)
+	}
+	if resp := a.doWithHeader(t, http.MethodGet, path, user.Token, nil, http.Header{"If-None-Match": {etag}}); resp.Status != http.StatusNotModified {
+		t.Fatalf("unchanged list: status = %d, want 304", resp.Status)
+	}
+	// 分页不同的列表使用不同的ETag
+	if resp := a.doWithHeader(t, http.MethodGet, path+"?page_size=5", user.Token, nil, http.Header{"If-None-Match": {etag}}); resp.Status != http.StatusOK {
+		t.Errorf("different page size: status = %d, want 200", resp.Status)
+	}
+
+	// 标签存于独立表，修改标签后旧ETag不再匹配
+	tags := []string{"rent"}
+	if resp := a.do(t, http.MethodPatch, path+"/"+tx.TxHash+"/meta", user.Token, models.TransactionMetaRequest{Tags: &tags}); resp.Status != http.StatusOK {
+		t.Fatalf("update tags: status = %d (%s)", resp.Status, resp.Message)
+	}
+	resp = a.doWithHeader(t, http.MethodGet, path, user.Token, nil, http.Header{"If-None-Match": {etag}})
+	if resp.Status != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("after tag update: status = %d, etag = %q, want 200 with a new ETag", resp.Status, resp.Header.Get("ETag"))
+	}
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/models"
+)
+
+// grpcClients 连接测试gRPC服务器的客户端
+type grpcClients struct {
+	auth   pb.AuthServiceClient
+	wallet pb.WalletServiceClient
+	tx     pb.TransactionServiceClient
+}
+
+// newGRPCClients 在内存连接（bufconn）上启动gRPC服务器并返回客户端，测试结束时关闭
+func (a *testApp) newGRPCClients(t *testing.T) *grpcClients {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := a.GRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial grpc: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &grpcClients{
+		auth:   pb.NewAuthServiceClient(conn),
+		wallet: pb.NewWalletServiceClient(conn),
+		tx:     pb.NewTransactionServiceClient(conn),
+	}
+}
+
+// withToken 附带Bearer Token的context
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// assertCode 断言gRPC错误码
+func assertCode(t *testing.T, what string, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("%s: code = %s (%v), want %s", what, got, err, want)
+	}
+}
+
+func TestGRPCAuth(t *testing.T) {
+	a := newTestApp(t)
+	clients := a.newGRPCClients(t)
+	ctx := context.Background()
+
+	register := &pb.RegisterRequest{Username: "grpcuser", Email: "grpcuser@example.com", Password: "Password123!"}
+	registered, err := clients.auth.Register(ctx, register)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	_, err = clients.auth.Register(ctx, register)
+	assertCode(t, "duplicate register", err, codes.InvalidArgument)
+	_, err = clients.auth.Register(ctx, &pb.RegisterRequest{Username: "x", Email: "not-an-email", Password: "short"})
+	assertCode(t, "invalid register", err, codes.InvalidArgument)
+
+	_, err = clients.auth.Login(ctx, &pb.LoginRequest{Email: register.Email, Password: "Wrong123!"})
+	assertCode(t, "wrong password", err, codes.Unauthenticated)
+	login, err := clients.auth.Login(ctx, &pb.LoginRequest{Email: register.Email, Password: register.Password})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// 认证拦截器：缺少或无效的Token被拒绝
+	_, err = clients.auth.GetProfile(ctx, &pb.GetProfileRequest{})
+	assertCode(t, "profile without token", err, codes.Unauthenticated)
+	_, err = clients.auth.GetProfile(withToken("invalid"), &pb.GetProfileRequest{})
+	assertCode(t, "profile with invalid token", err, codes.Unauthenticated)
+	profile, err := clients.auth.GetProfile(withToken(login.GetToken()), &pb.GetProfileRequest{})
+	if err != nil {
+		t.Fatalf("profile: %v", err)
+	}
+	if profile.GetUser().GetId() != registered.GetUser().GetId() {
+		t.Errorf("profile user = %d, want %d", profile.GetUser().GetId(), registered.GetUser().GetId())
+	}
+
+	// 登出后Token失效
+	if _, err := clients.auth.Logout(withToken(login.GetToken()), &pb.LogoutRequest{}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	_, err = clients.auth.GetProfile(withToken(login.GetToken()), &pb.GetProfileRequest{})
+	assertCode(t, "profile after logout", err, codes.Unauthenticated)
+}
+
+func TestGRPCWalletAndTransaction(t *testing.T) {
+	a := newTestApp(t)
+	clients := a.newGRPCClients(t)
+	owner := a.registerUser(t)
+	other := a.registerUser(t)
+	ctx := withToken(owner.Token)
+
+	created, err := clients.wallet.CreateWallet(ctx, &pb.CreateWalletRequest{ChainId: chainID, Name: "grpc"})
+	if err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	address := created.GetWallet().GetAddress()
+	a.chain.SetBalance(address, ether(5))
+
+	balance, err := clients.wallet.GetBalance(ctx, &pb.GetBalanceRequest{Address: address, ForceRefresh: true})
+	if err != nil {
+		t.Fatalf("get balance: %v", err)
+	}
+	if balance.GetBalanceWei() != ether(5).String() {
+		t.Errorf("balance = %s, want %s", balance.GetBalanceWei(), ether(5))
+	}
+
+	sent, err := clients.tx.SendTransaction(ctx, &pb.SendTransactionRequest{
+		FromAddress: address,
+		ToAddress:   recipient,
+		Amount:      ether(1).String(),
+		ChainId:     chainID,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	txHash := sent.GetTransaction().GetTxHash()
+	got, err := clients.tx.GetTransaction(ctx, &pb.GetTransactionRequest{TxHash: txHash})
+	if err != nil {
+		t.Fatalf("get transaction: %v", err)
+	}
+	if got.GetTransaction().GetStatus() != string(models.TxStatusPending) {
+		t.Errorf("status = %s, want pending", got.GetTransaction().GetStatus())
+	}
+	list, err := clients.tx.ListTransactions(ctx, &pb.ListTransactionsRequest{WalletAddress: address})
+	if err != nil {
+		t.Fatalf("list transactions: %v", err)
+	}
+	if list.GetTotal() != 1 {
+		t.Errorf("total = %d, want 1", list.GetTotal())
+	}
+
+	// 错误映射：余额不足、参数错误与其他用户的钱包
+	_, err = clients.tx.SendTransaction(ctx, &pb.SendTransactionRequest{
+		FromAddress: address,
+		ToAddress:   recipient,
+		Amount:      ether(100).String(),
+		ChainId:     chainID,
+	})
+	assertCode(t, "insufficient balance", err, codes.FailedPrecondition)
+	_, err = clients.tx.SendTransaction(ctx, &pb.SendTransactionRequest{
+		FromAddress: address,
+		ToAddress:   "not-an-address",
+		Amount:      ether(1).String(),
+		ChainId:     chainID,
+	})
+	assertCode(t, "invalid recipient", err, codes.InvalidArgument)
+
+	otherCtx := withToken(other.Token)
+	_, err = clients.wallet.GetWallet(otherCtx, &pb.GetWalletRequest{Address: address})
+	assertCode(t, "other user's wallet", err, codes.NotFound)
+	_, err = clients.tx.GetTransaction(otherCtx, &pb.GetTransactionRequest{TxHash: txHash})
+	assertCode(t, "other user's transaction", err, codes.NotFound)
+}
+
+func TestGRPCReadOnlyAPIKey(t *testing.T) {
+	a := newTestApp(t)
+	clients := a.newGRPCClients(t)
+	user := a.registerUser(t)
+
+	resp := a.do(t, http.MethodPost, "/api/v1/apikeys", user.Token, models.APIKeyCreateRequest{Name: "read", Scope: models.APIKeyScopeRead})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("create key: status = %d (%s)", resp.Status, resp.Message)
+	}
+	var key models.APIKeyResponse
+	resp.decode(t, &key)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key.Key)
+
+	// 只读Key可以查询，不能创建
+	if _, err := clients.auth.GetProfile(ctx, &pb.GetProfileRequest{}); err != nil {
+		t.Errorf("profile with read key: %v", err)
+	}
+	_, err := clients.wallet.CreateWallet(ctx, &pb.CreateWalletRequest{ChainId: chainID})
+	assertCode(t, "create wallet with read key", err, codes.PermissionDenied)
+
+	bad := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "invalid")
+	_, err = clients.auth.GetProfile(bad, &pb.GetProfileRequest{})
+	assertCode(t, "invalid key", err, codes.Unauthenticated)
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/blockchain/mock"
+	"crypto-wallet-api/internal/config"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/pkg/mailer"
+)
+
+// chainID 集成测试使用的链（请求参数只接受已支持的链ID）
+const chainID = 560048
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testApp 集成测试环境：按生产方式组装的App（SQLite、miniredis、内存区块链客户端，不连接RabbitMQ），
+// 通过httptest服务器以真实路由与中间件处理请求
+type testApp struct {
+	*App
+	chain  *mock.Client
+	server *httptest.Server
+}
+
+// newTestApp 创建集成测试环境
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	cfg, err := config.Load("../../configs/configs.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	// 价格接口不可用（余额的美元估值为空），限流不影响测试
+	prices := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(prices.Close)
+	cfg.Pricing.BaseURL = prices.URL
+	cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst = 10000, 10000
+	cfg.Metrics.Enabled = false
+	models.SetChains(cfg.Blockchain.Metadata())
+
+	redis, _ := testutil.NewRedis(t)
+	chain := mock.NewClient(chainID)
+	a := &App{
+		Config:     cfg,
+		DB:         testutil.NewDB(t),
+		Redis:      redis,
+		Cache:      redis,
+		Chains:     blockchain.NewClients(chain),
+		QueueCodec: service.NewQueueCodec(nil),
+	}
+	a.initServices(testutil.EncryptionKey, testutil.EmailHMACKey, mailer.LogMailer{})
+	t.Cleanup(a.Close)
+
+	server := httptest.NewServer(a.Router())
+	t.Cleanup(server.Close)
+	return &testApp{App: a, chain: chain, server: server}
+}
+
+// apiResponse 统一响应结构（data保留原始JSON，由调用方按接口解析）
+type apiResponse struct {
+	Status  int             `json:"-"`
+	Header  http.Header     `json:"-"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// decode 解析data字段
+func (r *apiResponse) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("decode data %s: %v", r.Data, err)
+	}
+}
+
+// do 发送请求（token为空时不带认证头，body为nil时不带请求体）
+func (a *testApp) do(t *testing.T, method, path, token string, body interface{}) *apiResponse {
+	t.Helper()
+	return a.doWithHeader(t, method, path, token, body, nil)
+}
+
+// doWithHeader 发送带额外请求头的请求
+func (a *testApp) doWithHeader(t *testing.T, method, path, token string, body interface{}, header http.Header) *apiResponse {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, a.server.URL+path, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := a.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	resp := &apiResponse{Status: res.StatusCode, Header: res.Header}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, resp); err != nil {
+			t.Fatalf("%s %s: decode response %q: %v", method, path, data, err)
+		}
+	}
+	return resp
+}
+
+var userSeq atomic.Int64
+
+// testUser 已注册并登录的用户
+type testUser struct {
+	ID    uint
+	Email string
+	Token string
+}
+
+// testPassword 测试用户的登录密码
+const testPassword = "Password123!"
+
+// registerUser 通过API注册并登录新用户
+func (a *testApp) registerUser(t *testing.T) *testUser {
+	t.Helper()
+	n := userSeq.Add(1)
+	username := fmt.Sprintf("user%d", n)
+	email := username + "@example.com"
+
+	if resp := a.do(t, http.MethodPost, "/api/v1/auth/register", "", models.UserCreateRequest{
+		Username: username,
+		Email:    email,
+		Password: testPassword,
+	}); resp.Status != http.StatusOK {
+		t.Fatalf("register: status %d: %s", resp.Status, resp.Message)
+	}
+	resp := a.do(t, http.MethodPost, "/api/v1/auth/login", "", models.UserLoginRequest{
+		Email:    email,
+		Password: testPassword,
+	})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("login: status %d: %s", resp.Status, resp.Message)
+	}
+	var login models.LoginResponse
+	resp.decode(t, &login)
+	return &testUser{ID: login.User.ID, Email: email, Token: login.Token}
+}
+
+// createWallet 通过API为用户创建钱包并设置链上余额（Wei）
+func (a *testApp) createWallet(t *testing.T, user *testUser, balanceWei *big.Int) *models.WalletResponse {
+	t.Helper()
+	resp := a.do(t, http.MethodPost, "/api/v1/wallets", user.Token, models.WalletCreateRequest{ChainID: chainID, Name: "test"})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("create wallet: status %d: %s", resp.Status, resp.Message)
+	}
+	var wallet models.WalletResponse
+	resp.decode(t, &wallet)
+	a.chain.SetBalance(wallet.Address, balanceWei)
+	a.WalletService.InvalidateBalance(context.Background(), wallet.Address)
+	return &wallet
+}
+
+// ether 以ETH为单位的Wei金额
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/handler"
+	"crypto-wallet-api/internal/middleware"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+)
+
+// Handlers 路由使用的全部HTTP处理器
+type Handlers struct {
+	Health         *handler.HealthHandler
+	Auth           *handler.AuthHandler
+	Wallet         *handler.WalletHandler
+	Transaction    *handler.TransactionHandler
+	APIKey         *handler.APIKeyHandler
+	Contact        *handler.ContactHandler
+	Whitelist      *handler.WhitelistHandler
+	Contract       *handler.ContractHandler
+	Stats          *handler.StatsHandler
+	Export         *handler.ExportHandler
+	Recurring      *handler.RecurringPaymentHandler
+	Activity       *handler.ActivityHandler
+	BalanceHistory *handler.BalanceHistoryHandler
+	Token          *handler.TokenHandler
+	Organization   *handler.OrganizationHandler
+	Notification   *handler.NotificationHandler
+	WebSocket      *handler.WebSocketHandler
+	Gas            *handler.GasHandler
+	Chain          *handler.ChainHandler
+	Admin          *handler.AdminHandler
+	Label          *handler.LabelHandler
+}
+
+// RouteLimiters 按路由组的用户级限流器
+type RouteLimiters struct {
+	Expensive *middleware.UserRateLimiter // 调用链节点等外部系统的路由
+	Export    *middleware.UserRateLimiter // 产生重查询的导出路由
+}
+
+// SetupRoutes 设置路由（API服务与集成测试共用）
+func SetupRoutes(
+	router *gin.Engine,
+	h *Handlers,
+	authService *service.AuthService,
+	apiKeyService *service.APIKeyService,
+	featureFlags *service.FeatureFlagService,
+	limiters *RouteLimiters,
+	sendTimeout time.Duration,
+) {
+	authMiddleware := middleware.AuthMiddleware(authService, apiKeyService)
+	// 账户与会话管理、API Key管理只接受登录Token（API Key不能签发新Key或注销会话）
+	userToken := middleware.UserTokenOnly()
+	// 只读维护模式：认证与管理路由不受影响，其他路由只允许只读请求
+	maintenance := middleware.ReadOnlyMaintenance(featureFlags)
+	// 按用户限流：调用链节点的路由比纯数据库查询的预算更低
+	expensive := limiters.Expensive.Middleware()
+	forceRefresh := limiters.Expensive.MiddlewareWhen(func(c *gin.Context) bool {
+		return c.Query("force_refresh") == "true"
+	})
+	export := limiters.Export.Middleware()
+	// 按路由类别替换全局处理时限：发送交易（含广播）的时限更长，流式响应与长连接不限制
+	send := middleware.RequestTimeout(sendTimeout)
+	stream := middleware.RequestTimeout(0)
+
+	// 健康检查
+	router.GET("/health", h.Health.Ready)
+	router.GET("/health/live", h.Health.Live)
+	router.GET("/health/ready", h.Health.Ready)
+
+	// API v1路由组
+	v1 := router.Group("/api/v1")
+	{
+		// 认证路由（无需JWT）
+		auth := v1.Group("/auth")
+		{
+			auth.POST("/register", h.Auth.Register)
+			auth.POST("/login", h.Auth.Login)
+			auth.GET("/profile", authMiddleware, h.Auth.GetProfile)
+			auth.PUT("/profile", authMiddleware, userToken, h.Auth.UpdateProfile)
+			auth.POST("/change-password", authMiddleware, userToken, h.Auth.ChangePassword)
+			auth.POST("/logout", authMiddleware, userToken, h.Auth.Logout)
+			auth.POST("/logout-all", authMiddleware, userToken, h.Auth.LogoutAll)
+			auth.GET("/sessions", authMiddleware, userToken, h.Auth.GetSessions)
+			auth.POST("/sessions/revoke-others", authMiddleware, userToken, h.Auth.RevokeOtherSessions)
+		}
+
+		// 钱包路由（需要JWT）
+		wallets := v1.Group("/wallets")
+		wallets.Use(authMiddleware, maintenance)
+		{
+			wallets.POST("", middleware.FeatureGate(featureFlags, models.FlagWalletsCreate), h.Wallet.CreateWallet)
+			wallets.POST("/import", middleware.FeatureGate(featureFlags, models.FlagWalletsCreate), expensive, h.Wallet.ImportWallet)
+			wallets.POST("/bulk", middleware.FeatureGate(featureFlags, models.FlagWalletsCreate), expensive, h.Wallet.BulkCreateWallets)
+			wallets.GET("", h.Wallet.GetWallets)
+			wallets.GET("/:address", h.Wallet.GetWallet)
+			wallets.GET("/:address/balance", forceRefresh, h.Wallet.GetBalance)
+			wallets.GET("/:address/balance-history", h.BalanceHistory.GetBalanceHistory)
+			wallets.PUT("/:address", h.Wallet.UpdateWallet)
+			wallets.PATCH("/:address", h.Wallet.PatchWallet)
+			wallets.DELETE("/:address", h.Wallet.DeleteWallet)
+			wallets.PUT("/:address/settings", h.Wallet.UpdateSettings)
+			wallets.PUT("/:address/default", h.Wallet.SetDefaultWallet)
+			wallets.POST("/:address/export-keystore", export, h.Wallet.ExportKeystore)
+			wallets.PUT("/:address/limits", h.Wallet.UpdateLimits)
+			wallets.GET("/:address/approval-policy", h.Wallet.GetApprovalPolicy)
+			wallets.PUT("/:address/approval-policy", h.Wallet.UpdateApprovalPolicy)
+			wallets.GET("/:address/whitelist", h.Whitelist.ListEntries)
+			wallets.POST("/:address/whitelist", h.Whitelist.AddEntry)
+			wallets.DELETE("/:address/whitelist/:id", h.Whitelist.RemoveEntry)
+			wallets.POST("/:address/sweep", middleware.FeatureGate(featureFlags, models.FlagTransactionsSend), send, h.Transaction.SweepWallet)
+			wallets.GET("/:address/transactions", h.Transaction.GetWalletTransactions)
+			wallets.GET("/:address/activity", h.Activity.GetActivity)
+			wallets.GET("/:address/tokens", expensive, h.Token.GetWalletTokens)
+		}
+
+		// 交易路由（需要JWT）
+		transactions := v1.Group("/transactions")
+		transactions.Use(authMiddleware, maintenance)
+		{
+			transactions.POST("", middleware.FeatureGate(featureFlags, models.FlagTransactionsSend), send, h.Transaction.SendTransaction)
+			transactions.POST("/contract", middleware.FeatureGate(featureFlags, models.FlagTransactionsSend), send, h.Transaction.SendContractTransaction)
+			transactions.POST("/simulate", expensive, h.Transaction.SimulateTransaction)
+			transactions.GET("", h.Transaction.ListTransactions)
+			transactions.GET("/export", export, stream, middleware.NoCompression(), h.Export.ExportTransactions)
+			transactions.POST("/export", export, h.Export.CreateExportJob)
+			transactions.GET("/approvals", h.Transaction.ListPendingApprovals)
+			transactions.POST("/:id/approve", send, h.Transaction.ApproveTransaction)
+			transactions.POST("/:id/reject", h.Transaction.RejectTransaction)
+			transactions.POST("/:id/expire", h.Transaction.ExpireTransaction)
+			transactions.GET("/:tx_hash", h.Transaction.GetTransaction)
+			transactions.PATCH("/:tx_hash/meta", h.Transaction.UpdateTransactionMeta)
+		}
+
+		// 异步导出路由（下载凭查询任务时签发的一次性令牌，无需JWT；文件原样下载，不压缩）
+		exports := v1.Group("/exports")
+		{
+			exports.GET("/:id", authMiddleware, maintenance, h.Export.GetExportJob)
+			exports.GET("/:id/download", maintenance, stream, middleware.NoCompression(), h.Export.DownloadExport)
+		}
+
+		// 合约交互路由（需要认证）
+		contracts := v1.Group("/contracts")
+		contracts.Use(authMiddleware, maintenance, expensive)
+		{
+			contracts.POST("/call", h.Contract.Call)
+		}
+
+		// 代币关注列表路由（需要认证）
+		tokens := v1.Group("/tokens")
+		tokens.Use(authMiddleware, maintenance)
+		{
+			tokens.POST("/watch", h.Token.WatchToken)
+			tokens.GET("/watch", h.Token.GetWatchlist)
+			tokens.DELETE("/watch", h.Token.UnwatchToken)
+		}
+
+		// 地址簿路由（需要认证）
+		contacts := v1.Group("/contacts")
+		contacts.Use(authMiddleware, maintenance)
+		{
+			contacts.POST("", h.Contact.CreateContact)
+			contacts.GET("", h.Contact.GetContacts)
+			contacts.GET("/:id", h.Contact.GetContact)
+			contacts.PUT("/:id", h.Contact.UpdateContact)
+			contacts.DELETE("/:id", h.Contact.DeleteContact)
+		}
+
+		// 组织相关路由（需要认证）
+		orgs := v1.Group("/orgs")
+		orgs.Use(authMiddleware, maintenance)
+		{
+			orgs.POST("", h.Organization.CreateOrg)
+			orgs.GET("", h.Organization.GetOrgs)
+			orgs.GET("/:id", h.Organization.GetOrg)
+			orgs.PUT("/:id", h.Organization.UpdateOrg)
+			orgs.DELETE("/:id", h.Organization.DeleteOrg)
+			orgs.GET("/:id/members", h.Organization.GetMembers)
+			orgs.POST("/:id/members", h.Organization.AddMember)
+			orgs.PUT("/:id/members/:user_id", h.Organization.UpdateMember)
+			orgs.DELETE("/:id/members/:user_id", h.Organization.RemoveMember)
+		}
+
+		// 地址标签路由（需要认证）
+		labels := v1.Group("/labels")
+		labels.Use(authMiddleware, maintenance)
+		{
+			labels.GET("/:address", h.Label.GetLabel)
+		}
+
+		// 通知相关路由（需要认证）
+		notifications := v1.Group("/notifications")
+		notifications.Use(authMiddleware, maintenance)
+		{
+			notifications.GET("", h.Notification.GetNotifications)
+			notifications.POST("/:id/read", h.Notification.MarkRead)
+			notifications.GET("/preferences", h.Notification.GetPreferences)
+			notifications.PUT("/preferences", h.Notification.UpdatePreferences)
+		}
+
+		// 定期转账相关路由
+		recurring := v1.Group("/recurring-payments")
+		recurring.Use(authMiddleware, maintenance)
+		{
+			recurring.POST("", h.Recurring.CreatePayment)
+			recurring.GET("", h.Recurring.GetPayments)
+			recurring.GET("/:id", h.Recurring.GetPayment)
+			recurring.PUT("/:id", h.Recurring.UpdatePayment)
+			recurring.DELETE("/:id", h.Recurring.DeletePayment)
+		}
+
+		// 链发现路由（无需认证，只读取缓存）
+		v1.GET("/chains", h.Chain.ListChains)
+
+		// Gas价格路由（需要认证）
+		gasPrices := v1.Group("/gas-prices")
+		gasPrices.Use(authMiddleware, maintenance, expensive)
+		{
+			gasPrices.GET("", h.Gas.GetGasPrices)
+		}
+
+		// 统计路由（需要认证）
+		stats := v1.Group("/stats")
+		stats.Use(authMiddleware, maintenance)
+		{
+			stats.GET("/transactions", h.Stats.GetTransactionStats)
+		}
+
+		// API Key管理路由（仅接受登录Token）
+		apiKeys := v1.Group("/apikeys")
+		apiKeys.Use(authMiddleware, userToken, maintenance)
+		{
+			apiKeys.POST("", h.APIKey.CreateAPIKey)
+			apiKeys.GET("", h.APIKey.GetAPIKeys)
+			apiKeys.DELETE("/:id", h.APIKey.DeleteAPIKey)
+		}
+
+		// 运维管理路由（需要管理员角色）
+		admin := v1.Group("/admin")
+		admin.Use(authMiddleware, middleware.AdminMiddleware(authService))
+		{
+			admin.GET("/stats", h.Admin.GetStats)
+			admin.GET("/internal-ledger", h.Admin.GetInternalLedger)
+			admin.GET("/reconciliation", h.Admin.ListReconciliation)
+			admin.GET("/feature-flags", h.Admin.ListFeatureFlags)
+			admin.GET("/feature-flags/changes", h.Admin.ListFeatureFlagChanges)
+			admin.PUT("/feature-flags/:name", h.Admin.UpdateFeatureFlag)
+			admin.POST("/labels/import", h.Label.ImportLabels)
+		}
+
+		// 实时事件推送（WebSocket自行完成JWT认证，长连接不受处理时限限制）
+		v1.GET("/ws", stream, h.WebSocket.Connect)
+	}
+}
@@ -0,0 +1,414 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"crypto-wallet-api/internal/middleware"
+	"crypto-wallet-api/internal/models"
+)
+
+const (
+	recipient = "0x1111111111111111111111111111111111111111"
+	txHash    = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+func TestRoutesRequireAuthentication(t *testing.T) {
+	a := newTestApp(t)
+	wallet := "/api/v1/wallets/" + recipient
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/auth/profile"},
+		{http.MethodPost, "/api/v1/auth/logout-all"},
+		{http.MethodGet, "/api/v1/auth/sessions"},
+		{http.MethodPost, "/api/v1/wallets"},
+		{http.MethodGet, "/api/v1/wallets"},
+		{http.MethodGet, wallet},
+		{http.MethodGet, wallet + "/balance"},
+		{http.MethodPut, wallet},
+		{http.MethodDelete, wallet},
+		{http.MethodGet, wallet + "/transactions"},
+		{http.MethodPost, "/api/v1/transactions"},
+		{http.MethodGet, "/api/v1/transactions"},
+		{http.MethodGet, "/api/v1/transactions/" + txHash},
+		{http.MethodPost, "/api/v1/transactions/1/approve"},
+		{http.MethodGet, "/api/v1/contacts"},
+		{http.MethodGet, "/api/v1/orgs"},
+		{http.MethodGet, "/api/v1/recurring-payments"},
+		{http.MethodGet, "/api/v1/stats/transactions"},
+		{http.MethodPost, "/api/v1/apikeys"},
+		{http.MethodGet, "/api/v1/admin/feature-flags"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			if resp := a.do(t, route.method, route.path, "", nil); resp.Status != http.StatusUnauthorized {
+				t.Errorf("without token: status = %d, want 401", resp.Status)
+			}
+			if resp := a.do(t, route.method, route.path, "not-a-token", nil); resp.Status != http.StatusUnauthorized {
+				t.Errorf("invalid token: status = %d, want 401", resp.Status)
+			}
+			header := http.Header{middleware.APIKeyHeader: {"cwa_invalid"}}
+			if resp := a.doWithHeader(t, route.method, route.path, "", nil, header); resp.Status != http.StatusUnauthorized {
+				t.Errorf("invalid api key: status = %d, want 401", resp.Status)
+			}
+		})
+	}
+
+	// 公开路由不需要认证
+	if resp := a.do(t, http.MethodGet, "/health/live", "", nil); resp.Status != http.StatusOK {
+		t.Errorf("GET /health/live: status = %d, want 200", resp.Status)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	a := newTestApp(t)
+	existing := a.registerUser(t)
+
+	tests := []struct {
+		name   string
+		req    models.UserCreateRequest
+		status int
+	}{
+		{name: "success", req: models.UserCreateRequest{Username: "newuser", Email: "new@example.com", Password: testPassword}, status: http.StatusOK},
+		{name: "duplicate email", req: models.UserCreateRequest{Username: "another", Email: existing.Email, Password: testPassword}, status: http.StatusBadRequest},
+		{name: "missing username", req: models.UserCreateRequest{Email: "nousername@example.com", Password: testPassword}, status: http.StatusBadRequest},
+		{name: "malformed email", req: models.UserCreateRequest{Username: "bademail", Email: "not-an-email", Password: testPassword}, status: http.StatusBadRequest},
+		{name: "short password", req: models.UserCreateRequest{Username: "shortpw", Email: "shortpw@example.com", Password: "123"}, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := a.do(t, http.MethodPost, "/api/v1/auth/register", "", tt.req)
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d (%s), want %d", resp.Status, resp.Message, tt.status)
+			}
+			if tt.status == http.StatusOK {
+				var user models.UserResponse
+				resp.decode(t, &user)
+				if user.ID == 0 || user.Email != tt.req.Email {
+					t.Errorf("registered user = %+v, want email %s", user, tt.req.Email)
+				}
+			}
+		})
+	}
+
+	// 重复注册不会覆盖原用户
+	var count int64
+	if err := a.DB.Model(&models.User{}).Count(&count).Error; err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("users = %d, want 2", count)
+	}
+}
+
+func TestLoginBadCredentials(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+
+	tests := []struct {
+		name   string
+		req    models.UserLoginRequest
+		status int
+	}{
+		{name: "wrong password", req: models.UserLoginRequest{Email: user.Email, Password: "WrongPassword1!"}, status: http.StatusUnauthorized},
+		{name: "unknown email", req: models.UserLoginRequest{Email: "nobody@example.com", Password: testPassword}, status: http.StatusUnauthorized},
+		{name: "missing password", req: models.UserLoginRequest{Email: user.Email}, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := a.do(t, http.MethodPost, "/api/v1/auth/login", "", tt.req)
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d (%s), want %d", resp.Status, resp.Message, tt.status)
+			}
+			if strings.Contains(string(resp.Data), "token") {
+				t.Errorf("failed login returned a token: %s", resp.Data)
+			}
+		})
+	}
+}
+
+func TestWalletRoutesOwnership(t *testing.T) {
+	a := newTestApp(t)
+	owner := a.registerUser(t)
+	other := a.registerUser(t)
+	wallet := a.createWallet(t, owner, ether(0))
+	path := "/api/v1/wallets/" + wallet.Address
+
+	// 其他用户访问与不存在的钱包一样返回404；所有者正常访问
+	routes := []struct{ method, path string }{
+		{http.MethodGet, path},
+		{http.MethodGet, path + "/balance"},
+		{http.MethodGet, path + "/transactions"},
+		{http.MethodGet, path + "/whitelist"},
+		{http.MethodGet, path + "/approval-policy"},
+	}
+	for _, route := range routes {
+		t.Run(route.method+" "+strings.TrimPrefix(route.path, path), func(t *testing.T) {
+			if resp := a.do(t, route.method, route.path, other.Token, nil); resp.Status != http.StatusNotFound {
+				t.Errorf("other user: status = %d (%s), want 404", resp.Status, resp.Message)
+			}
+			unknown := strings.Replace(route.path, wallet.Address, recipient, 1)
+			if resp := a.do(t, route.method, unknown, owner.Token, nil); resp.Status != http.StatusNotFound {
+				t.Errorf("unknown wallet: status = %d (%s), want 404", resp.Status, resp.Message)
+			}
+			if resp := a.do(t, route.method, route.path, owner.Token, nil); resp.Status != http.StatusOK {
+				t.Errorf("owner: status = %d (%s), want 200", resp.Status, resp.Message)
+			}
+		})
+	}
+
+	// 删除放在最后：其他用户无法删除，所有者删除后不再可见
+	if resp := a.do(t, http.MethodDelete, path, other.Token, nil); resp.Status == http.StatusOK {
+		t.Errorf("other user delete: status = %d, want an error", resp.Status)
+	}
+	if resp := a.do(t, http.MethodDelete, path, owner.Token, nil); resp.Status != http.StatusOK {
+		t.Fatalf("owner delete: status = %d (%s), want 200", resp.Status, resp.Message)
+	}
+	if resp := a.do(t, http.MethodGet, path, owner.Token, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("get deleted wallet: status = %d, want 404", resp.Status)
+	}
+}
+
+func TestTransactionRoutesStatus(t *testing.T) {
+	a := newTestApp(t)
+	owner := a.registerUser(t)
+	other := a.registerUser(t)
+	wallet := a.createWallet(t, owner, ether(10))
+
+	valid := models.TransactionCreateRequest{FromAddress: wallet.Address, ToAddress: recipient, Amount: ether(1).String(), ChainID: chainID}
+	tests := []struct {
+		name   string
+		token  string
+		modify func(req *models.TransactionCreateRequest)
+		status int
+	}{
+		{name: "missing amount", token: owner.Token, modify: func(req *models.TransactionCreateRequest) { req.Amount = "" }, status: http.StatusBadRequest},
+		{name: "malformed recipient", token: owner.Token, modify: func(req *models.TransactionCreateRequest) { req.ToAddress = "0x123" }, status: http.StatusBadRequest},
+		{name: "insufficient balance", token: owner.Token, modify: func(req *models.TransactionCreateRequest) { req.Amount = ether(100).String() }, status: http.StatusBadRequest},
+		{name: "other user's wallet", token: other.Token, status: http.StatusNotFound},
+		{name: "success", token: owner.Token, status: http.StatusOK},
+	}
+	var sent models.TransactionResponse
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			if tt.modify != nil {
+				tt.modify(&req)
+			}
+			resp := a.do(t, http.MethodPost, "/api/v1/transactions", tt.token, req)
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d (%s), want %d", resp.Status, resp.Message, tt.status)
+			}
+			if resp.Status == http.StatusOK {
+				resp.decode(t, &sent)
+			}
+		})
+	}
+	if sent.TxHash == "" {
+		t.Fatal("no transaction sent")
+	}
+
+	// 交易详情：所有者可见，其他用户返回404
+	path := "/api/v1/transactions/" + sent.TxHash
+	if resp := a.do(t, http.MethodGet, path, owner.Token, nil); resp.Status != http.StatusOK {
+		t.Errorf("owner get: status = %d, want 200", resp.Status)
+	}
+	if resp := a.do(t, http.MethodGet, path, other.Token, nil); resp.Status != http.StatusNotFound {
+		t.Errorf("other user get: status = %d, want 404", resp.Status)
+	}
+	note := "rent"
+	if resp := a.do(t, http.MethodPatch, path+"/meta", other.Token, models.TransactionMetaRequest{Note: &note}); resp.Status != http.StatusNotFound {
+		t.Errorf("other user update meta: status = %d, want 404", resp.Status)
+	}
+	if resp := a.do(t, http.MethodPatch, path+"/meta", owner.Token, models.TransactionMetaRequest{Note: &note}); resp.Status != http.StatusOK {
+		t.Errorf("owner update meta: status = %d (%s), want 200", resp.Status, resp.Message)
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	a := newTestApp(t)
+	owner := a.registerUser(t)
+	other := a.registerUser(t)
+	wallet := a.createWallet(t, owner, ether(10))
+	otherWallet := a.createWallet(t, other, ether(10))
+
+	send := func(user *testUser, from string) {
+		t.Helper()
+		resp := a.do(t, http.MethodPost, "/api/v1/transactions", user.Token, models.TransactionCreateRequest{
+			FromAddress: from, ToAddress: recipient, Amount: "1000", ChainID: chainID,
+		})
+		if resp.Status != http.StatusOK {
+			t.Fatalf("send: status = %d (%s)", resp.Status, resp.Message)
+		}
+	}
+	for range 3 {
+		send(owner, wallet.Address)
+	}
+	send(other, otherWallet.Address)
+
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		wantTotal int64
+		wantCount int
+	}{
+		{name: "all own transactions", query: "", status: http.StatusOK, wantTotal: 3, wantCount: 3},
+		{name: "paged", query: "?page=2&page_size=2", status: http.StatusOK, wantTotal: 3, wantCount: 1},
+		{name: "by wallet", query: "?wallet_address=" + wallet.Address, status: http.StatusOK, wantTotal: 3, wantCount: 3},
+		{name: "by status", query: "?status=success", status: http.StatusOK, wantTotal: 0, wantCount: 0},
+		{name: "dropped status", query: "?status=dropped", status: http.StatusOK, wantTotal: 0, wantCount: 0},
+		{name: "other user's wallet", query: "?wallet_address=" + otherWallet.Address, status: http.StatusNotFound},
+		{name: "malformed wallet", query: "?wallet_address=0x123", status: http.StatusBadRequest},
+		{name: "unknown status", query: "?status=lost", status: http.StatusBadRequest},
+		{name: "page size too large", query: "?page_size=1000", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := a.do(t, http.MethodGet, "/api/v1/transactions"+tt.query, owner.Token, nil)
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d (%s), want %d", resp.Status, resp.Message, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var list models.TransactionListResponse
+			resp.decode(t, &list)
+			if list.Total != tt.wantTotal || len(list.Transactions) != tt.wantCount {
+				t.Errorf("total = %d, count = %d; want %d, %d", list.Total, len(list.Transactions), tt.wantTotal, tt.wantCount)
+			}
+			for _, tx := range list.Transactions {
+				if tx.FromAddress != wallet.Address {
+					t.Errorf("listed transaction from %s, want only %s", tx.FromAddress, wallet.Address)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionListPaging(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+	wallet := a.createWallet(t, user, ether(0))
+
+	// 直接写入25条交易（不经过发送流程）
+	const total = 25
+	for i := 0; i < total; i++ {
+		if err := a.TxRepo.Create(context.Background(), &models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      fmt.Sprintf("0x%064x", i+1),
+			FromAddress: wallet.Address,
+			ToAddress:   recipient,
+			Amount:      "1",
+			Status:      models.TxStatusSuccess,
+			ChainID:     wallet.ChainID,
+		}); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+	path := "/api/v1/wallets/" + wallet.Address + "/transactions"
+
+	tests := []struct {
+		name     string
+		query    string
+		page     int
+		pageSize int
+		items    int
+		hasNext  bool
+	}{
+		{name: "defaults", query: "", page: 1, pageSize: 20, items: 20, hasNext: true},
+		{name: "zero values use defaults", query: "?page=0&page_size=0", page: 1, pageSize: 20, items: 20, hasNext: true},
+		{name: "second page", query: "?page=2", page: 2, pageSize: 20, items: 5},
+		{name: "page past the end", query: "?page=3", page: 3, pageSize: 20, items: 0},
+		{name: "custom page size", query: "?page=3&page_size=10", page: 3, pageSize: 10, items: 5},
+		{name: "maximum page size", query: "?page_size=100", page: 1, pageSize: 100, items: total},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := a.do(t, http.MethodGet, path+tt.query, user.Token, nil)
+			if resp.Status != http.StatusOK {
+				t.Fatalf("status = %d (%s), want 200", resp.Status, resp.Message)
+			}
+			var list models.TransactionListResponse
+			resp.decode(t, &list)
+			if list.Page != tt.page || list.PageSize != tt.pageSize || len(list.Transactions) != tt.items {
+				t.Errorf("page = %d, page_size = %d, items = %d, want %d, %d, %d", list.Page, list.PageSize, len(list.Transactions), tt.page, tt.pageSize, tt.items)
+			}
+			if list.Total != total || list.TotalPages != models.TotalPages(total, tt.pageSize) || list.HasNext != tt.hasNext {
+				t.Errorf("total = %d, total_pages = %d, has_next = %t, want %d, %d, %t", list.Total, list.TotalPages, list.HasNext, total, models.TotalPages(total, tt.pageSize), tt.hasNext)
+			}
+		})
+	}
+
+	// 每页数量超过上限时拒绝而不是截断（两个列表接口一致）
+	for _, query := range []string{"?page_size=101", "?page_size=1000", "?page_size=-1", "?page=-1"} {
+		for _, p := range []string{path, "/api/v1/transactions"} {
+			if resp := a.do(t, http.MethodGet, p+query, user.Token, nil); resp.Status != http.StatusBadRequest {
+				t.Errorf("GET %s%s: status = %d, want 400", p, query, resp.Status)
+			}
+		}
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+
+	createKey := func(scope string) string {
+		resp := a.do(t, http.MethodPost, "/api/v1/apikeys", user.Token, models.APIKeyCreateRequest{Name: scope, Scope: scope})
+		if resp.Status != http.StatusOK {
+			t.Fatalf("create %s key: status = %d (%s)", scope, resp.Status, resp.Message)
+		}
+		var key models.APIKeyResponse
+		resp.decode(t, &key)
+		return key.Key
+	}
+	writeKey := http.Header{middleware.APIKeyHeader: {createKey(models.APIKeyScopeWrite)}}
+	readKey := http.Header{middleware.APIKeyHeader: {createKey(models.APIKeyScopeRead)}}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		body   interface{}
+		status int
+	}{
+		{"write key lists contacts", http.MethodGet, "/api/v1/contacts", writeKey, nil, http.StatusOK},
+		{"write key creates wallet", http.MethodPost, "/api/v1/wallets", writeKey, models.WalletCreateRequest{ChainID: chainID}, http.StatusOK},
+		{"read key lists contacts", http.MethodGet, "/api/v1/contacts", readKey, nil, http.StatusOK},
+		{"read key cannot create wallet", http.MethodPost, "/api/v1/wallets", readKey, models.WalletCreateRequest{ChainID: chainID}, http.StatusForbidden},
+		{"write key cannot mint keys", http.MethodPost, "/api/v1/apikeys", writeKey, models.APIKeyCreateRequest{Name: "minted"}, http.StatusForbidden},
+		{"write key cannot list keys", http.MethodGet, "/api/v1/apikeys", writeKey, nil, http.StatusForbidden},
+		{"write key cannot log out sessions", http.MethodPost, "/api/v1/auth/logout-all", writeKey, nil, http.StatusForbidden},
+		{"write key cannot list sessions", http.MethodGet, "/api/v1/auth/sessions", writeKey, nil, http.StatusForbidden},
+		{"write key reads profile", http.MethodGet, "/api/v1/auth/profile", writeKey, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := a.doWithHeader(t, tt.method, tt.path, "", tt.body, tt.header); resp.Status != tt.status {
+				t.Errorf("status = %d (%s), want %d", resp.Status, resp.Message, tt.status)
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRequireAdminRole(t *testing.T) {
+	a := newTestApp(t)
+	user := a.registerUser(t)
+
+	if resp := a.do(t, http.MethodGet, "/api/v1/admin/feature-flags", user.Token, nil); resp.Status != http.StatusForbidden {
+		t.Errorf("user: status = %d, want 403", resp.Status)
+	}
+
+	if err := a.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("role", models.UserRoleAdmin).Error; err != nil {
+		t.Fatalf("promote user: %v", err)
+	}
+	if resp := a.do(t, http.MethodGet, "/api/v1/admin/feature-flags", user.Token, nil); resp.Status != http.StatusOK {
+		t.Errorf("admin: status = %d (%s), want 200", resp.Status, resp.Message)
+	}
+}
@@ -0,0 +1,107 @@
+// Package apperr 类型化业务错误：区分可以原样返回给客户端的错误与只记录日志的内部错误
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// 错误类别（errors.Is匹配），决定HTTP状态码与业务码
+var (
+	ErrNotFound  = errors.New("resource not found")
+	ErrInvalid   = errors.New("invalid request")
+	ErrConflict  = errors.New("resource conflict")
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error 类型化业务错误，Error()返回的消息可以安全地返回给客户端
+//
+// key为响应消息键（i18n语言包中的键），响应按请求语言翻译；message为英文消息，用于日志与gRPC。
+type Error struct {
+	kind    error
+	key     string
+	message string
+}
+
+// Error 实现error接口
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Is 按错误类别匹配（errors.Is(err, ErrNotFound)）
+func (e *Error) Is(target error) bool {
+	return target == e.kind
+}
+
+// New 不属于任何类别的业务错误（由调用方按errors.Is映射业务码），如服务层的哨兵错误
+func New(key, message string) error {
+	return &Error{key: key, message: message}
+}
+
+// NotFound 资源不存在（消息为"<resource> not found"，消息键为"error.<resource>_not_found"）
+func NotFound(resource string) error {
+	return &Error{
+		kind:    ErrNotFound,
+		key:     "error." + strings.ReplaceAll(resource, " ", "_") + "_not_found",
+		message: resource + " not found",
+	}
+}
+
+// Invalid 请求不合法
+func Invalid(key, message string) error {
+	return &Error{kind: ErrInvalid, key: key, message: message}
+}
+
+// Invalidf 请求不合法（格式化消息，参数不得包含底层错误文本；没有消息键，响应不翻译）
+func Invalidf(format string, args ...any) error {
+	return &Error{kind: ErrInvalid, message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict 资源冲突（如重复创建）
+func Conflict(key, message string) error {
+	return &Error{kind: ErrConflict, key: key, message: message}
+}
+
+// Forbidden 无权执行操作
+func Forbidden(key, message string) error {
+	return &Error{kind: ErrForbidden, key: key, message: message}
+}
+
+// Message 返回可以安全展示给客户端的消息，err链中没有类型化错误时ok为false
+func Message(err error) (string, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.message, true
+	}
+	return "", false
+}
+
+// MessageKey 返回响应使用的消息：err链中的业务错误带消息键时返回消息键，错误本身之后追加的说明原样保留
+// （如"error.invalid_amount: must be greater than 0"）；没有消息键时返回英文消息，不是业务错误时返回err.Error()
+func MessageKey(err error) string {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		return err.Error()
+	}
+	message := appErr.message
+	if appErr.key != "" {
+		message = appErr.key
+	}
+	if detail, ok := strings.CutPrefix(err.Error(), appErr.message); ok {
+		return message + detail
+	}
+	return message
+}
+
+// IsTimeout 错误是否由下游依赖（链节点、Redis、数据库）超时引起：调用的ctx到期或网络读写超时
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,270 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// ParseABI 解析ABI，支持完整ABI数组或单个方法片段对象
+func ParseABI(abiJSON string) (abi.ABI, error) {
+	trimmed := strings.TrimSpace(abiJSON)
+	if strings.HasPrefix(trimmed, "{") {
+		trimmed = "[" + trimmed + "]"
+	}
+	return abi.JSON(strings.NewReader(trimmed))
+}
+
+// LoadMethod 根据ABI（完整ABI或片段）与方法名加载方法；abiJSON为空时method需为签名形式，如"approve(address,uint256)"
+func LoadMethod(abiJSON, method string) (abi.Method, error) {
+	if strings.TrimSpace(abiJSON) == "" {
+		return ParseMethodSignature(method)
+	}
+
+	parsed, err := ParseABI(abiJSON)
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid abi: %w", err)
+	}
+	return ResolveMethod(parsed, method)
+}
+
+// ParseMethodSignature 解析方法签名（不支持tuple类型），如"transfer(address,uint256)"
+func ParseMethodSignature(signature string) (abi.Method, error) {
+	signature = strings.TrimSpace(signature)
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return abi.Method{}, fmt.Errorf("invalid method signature %q", signature)
+	}
+	name := signature[:open]
+	params := strings.TrimSpace(signature[open+1 : len(signature)-1])
+
+	var inputs abi.Arguments
+	if params != "" {
+		for i, param := range strings.Split(params, ",") {
+			// 支持"address spender"形式的参数名
+			fields := strings.Fields(param)
+			if len(fields) == 0 || len(fields) > 2 {
+				return abi.Method{}, fmt.Errorf("invalid parameter %q in method signature", param)
+			}
+			typ, err := abi.NewType(fields[0], "", nil)
+			if err != nil {
+				return abi.Method{}, fmt.Errorf("invalid parameter type %q: %w", fields[0], err)
+			}
+			argName := fmt.Sprintf("arg%d", i)
+			if len(fields) == 2 {
+				argName = fields[1]
+			}
+			inputs = append(inputs, abi.Argument{Name: argName, Type: typ})
+		}
+	}
+
+	return abi.NewMethod(name, name, abi.Function, "nonpayable", false, true, inputs, nil), nil
+}
+
+// ResolveMethod 查找方法，name为空且ABI仅包含一个方法时直接使用该方法
+func ResolveMethod(parsed abi.ABI, name string) (abi.Method, error) {
+	if name == "" {
+		if len(parsed.Methods) != 1 {
+			return abi.Method{}, fmt.Errorf("method is required when abi contains %d methods", len(parsed.Methods))
+		}
+		for _, method := range parsed.Methods {
+			return method, nil
+		}
+	}
+
+	method, ok := parsed.Methods[name]
+	if !ok {
+		return abi.Method{}, fmt.Errorf("method %q not found in abi", name)
+	}
+	return method, nil
+}
+
+// EncodeCall 将JSON参数按方法签名转换并编码为调用数据
+func EncodeCall(method abi.Method, args []interface{}) ([]byte, error) {
+	// 1. 校验参数个数
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", method.Name, len(method.Inputs), len(args))
+	}
+
+	// 2. 逐个转换参数类型
+	values := make([]interface{}, len(args))
+	for i, input := range method.Inputs {
+		value, err := convertArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %w", i, input.Type.String(), input.Name, err)
+		}
+		values[i] = value
+	}
+
+	// 3. 编码（方法选择器 + 参数）
+	packed, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, method.ID...), packed...), nil
+}
+
+// DecodeOutputs 解码返回数据，结果转换为JSON友好的格式
+func DecodeOutputs(method abi.Method, data []byte) ([]interface{}, error) {
+	values, err := method.Outputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(values))
+	for i, value := range values {
+		results[i] = formatValue(value)
+	}
+	return results, nil
+}
+
+// DecodeInputs 从调用数据中解码参数（用于记录规范化后的参数）
+func DecodeInputs(method abi.Method, data []byte) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short")
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(values))
+	for i, value := range values {
+		results[i] = formatValue(value)
+	}
+	return results, nil
+}
+
+// convertArg 将JSON值转换为abi包要求的Go类型
+func convertArg(t abi.Type, arg interface{}) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		s, ok := arg.(string)
+		if !ok || !addressPattern.MatchString(s) {
+			return nil, fmt.Errorf("expected hex address string")
+		}
+		return common.HexToAddress(s), nil
+
+	case abi.BoolTy:
+		b, ok := arg.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean")
+		}
+		return b, nil
+
+	case abi.StringTy:
+		s, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string")
+		}
+		return s, nil
+
+	case abi.BytesTy:
+		b, err := decodeHexArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+
+	case abi.FixedBytesTy:
+		b, err := decodeHexArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		array := reflect.New(t.GetType()).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array.Interface(), nil
+
+	case abi.UintTy, abi.IntTy:
+		n, err := parseIntegerArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return nil, fmt.Errorf("expected non-negative integer")
+		}
+		if n.BitLen() > t.Size {
+			return nil, fmt.Errorf("value out of range")
+		}
+		// 64位及以下使用原生整型，其余使用*big.Int
+		goType := t.GetType()
+		switch goType.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v := reflect.New(goType).Elem()
+			v.SetUint(n.Uint64())
+			return v.Interface(), nil
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v := reflect.New(goType).Elem()
+			v.SetInt(n.Int64())
+			return v.Interface(), nil
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("unsupported argument type %s", t.String())
+}
+
+// parseIntegerArg 解析整数参数（支持十进制/十六进制字符串和JSON数字）
+func parseIntegerArg(arg interface{}) (*big.Int, error) {
+	switch v := arg.(type) {
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("expected integer")
+		}
+		return n, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("expected integer, large values must be passed as strings")
+		}
+		return big.NewInt(int64(v)), nil
+	}
+	return nil, fmt.Errorf("expected integer")
+}
+
+// decodeHexArg 解析0x前缀的十六进制字节参数
+func decodeHexArg(arg interface{}) ([]byte, error) {
+	s, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected 0x-prefixed hex string")
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("expected 0x-prefixed hex string")
+	}
+	return b, nil
+}
+
+// formatValue 将解码结果转换为JSON友好的格式（整数以十进制字符串返回避免精度丢失）
+func formatValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	case uint8, uint16, uint32, uint64, int8, int16, int32, int64:
+		return fmt.Sprintf("%d", v)
+	}
+
+	// 定长字节数组
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	}
+	return value
+}
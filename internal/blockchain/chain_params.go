@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultNativeGasLimit 原生币转账的gas用量
+	DefaultNativeGasLimit = 21000
+	// DefaultTokenGasLimit ERC-20转账的默认gas用量（标准代币的transfer低于该值）
+	DefaultTokenGasLimit = 65000
+)
+
+// erc20TransferSelector transfer(address,uint256)的方法选择器
+var erc20TransferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+// ChainParams 链相关的交易构建参数（Gas定价方式、出块时间、gas价格下限与默认gas用量）
+type ChainParams struct {
+	ChainID        int
+	LegacyGas      bool          // 不采用EIP-1559费用市场（如BSC），Gas价格只按eth_gasPrice计算
+	BlockTime      time.Duration // 平均出块时间
+	MinGasPrice    *big.Int      // gas价格下限（部分链的节点拒绝低于该值的交易），nil表示不限制
+	NativeGasLimit uint64        // 原生币转账的默认gas用量
+	TokenGasLimit  uint64        // ERC-20转账的默认gas用量
+	Confirmations  uint64        // 交易视为最终确认所需的区块数（含交易所在区块，1表示出现回执即确认）
+}
+
+// knownChainParams 已知链的默认参数
+var knownChainParams = map[int]ChainParams{
+	1:        {ChainID: 1, BlockTime: 12 * time.Second},                                                        // Ethereum
+	11155111: {ChainID: 11155111, BlockTime: 12 * time.Second},                                                 // Sepolia
+	560048:   {ChainID: 560048, BlockTime: 12 * time.Second},                                                   // Hoodi
+	56:       {ChainID: 56, LegacyGas: true, BlockTime: 3 * time.Second, MinGasPrice: big.NewInt(100_000_000)}, // BSC（0.1 Gwei）
+	97:       {ChainID: 97, LegacyGas: true, BlockTime: 3 * time.Second, MinGasPrice: big.NewInt(100_000_000)}, // BSC测试网
+}
+
+// testnetChainIDs 测试链白名单（只有列出的链可以启用测试水龙头，主网链ID不得加入）
+var testnetChainIDs = map[int]bool{
+	11155111: true, // Sepolia
+	560048:   true, // Hoodi
+	17000:    true, // Holesky
+	97:       true, // BSC测试网
+}
+
+// IsTestnet 链是否在测试链白名单中（未知链一律视为主网）
+func IsTestnet(chainID int) bool {
+	return testnetChainIDs[chainID]
+}
+
+// DefaultChainParams 返回链的默认参数（未知链按以太坊处理）
+func DefaultChainParams(chainID int) ChainParams {
+	params, ok := knownChainParams[chainID]
+	if !ok {
+		params = ChainParams{ChainID: chainID, BlockTime: 12 * time.Second}
+	}
+	params.NativeGasLimit = DefaultNativeGasLimit
+	params.TokenGasLimit = DefaultTokenGasLimit
+	params.Confirmations = 1
+	return params
+}
+
+// ApplyGasPriceFloor 返回不低于链gas价格下限的价格
+func (p ChainParams) ApplyGasPriceFloor(price *big.Int) *big.Int {
+	if p.MinGasPrice != nil && price.Cmp(p.MinGasPrice) < 0 {
+		return new(big.Int).Set(p.MinGasPrice)
+	}
+	return price
+}
+
+// DefaultGasLimit 未指定gas用量时的默认值：原生币转账与ERC-20转账使用固定值，其他合约调用返回0（需估算）
+func (p ChainParams) DefaultGasLimit(data []byte) uint64 {
+	switch {
+	case len(data) == 0:
+		return p.NativeGasLimit
+	case len(data) == 68 && bytes.Equal(data[:4], erc20TransferSelector):
+		return p.TokenGasLimit
+	default:
+		return 0
+	}
+}
+
+// BuildTransaction 按链的Gas模型构建未签名交易：采用EIP-1559的链在提供小费上限时构建动态费用交易（gasPrice为每单位gas的最高费用），
+// 否则构建legacy交易（gasPrice为固定价格，由EIP-155签名）
+func (p ChainParams) BuildTransaction(nonce uint64, to string, value *big.Int, gasLimit uint64, gasPrice, tipCap *big.Int, data []byte) *types.Transaction {
+	if !p.LegacyGas && tipCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(int64(p.ChainID)),
+			Nonce:     nonce,
+			To:        ptrAddress(common.HexToAddress(to)),
+			Value:     value,
+			Gas:       gasLimit,
+			GasTipCap: tipCap,
+			GasFeeCap: gasPrice,
+			Data:      data,
+		})
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       ptrAddress(common.HexToAddress(to)),
+		Value:    value,
+		Gas:      gasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	})
+}
+
+// ptrAddress 返回地址的指针
+func ptrAddress(address common.Address) *common.Address {
+	return &address
+}
@@ -0,0 +1,50 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBuildTransaction(t *testing.T) {
+	gasPrice := big.NewInt(30_000_000_000)
+	tipCap := big.NewInt(2_000_000_000)
+
+	tests := []struct {
+		name     string
+		params   ChainParams
+		tipCap   *big.Int
+		wantType uint8
+	}{
+		{name: "eip1559 chain with tip", params: DefaultChainParams(11155111), tipCap: tipCap, wantType: types.DynamicFeeTxType},
+		{name: "eip1559 chain without tip", params: DefaultChainParams(11155111), wantType: types.LegacyTxType},
+		{name: "legacy chain", params: DefaultChainParams(56), tipCap: tipCap, wantType: types.LegacyTxType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := tt.params.BuildTransaction(3, "0x1111111111111111111111111111111111111111", big.NewInt(1), 21000, gasPrice, tt.tipCap, nil)
+			if tx.Type() != tt.wantType {
+				t.Fatalf("type = %d, want %d", tx.Type(), tt.wantType)
+			}
+			// 动态费用交易的最高费用为gasPrice，legacy交易两者均为gasPrice
+			if tx.GasFeeCap().Cmp(gasPrice) != 0 {
+				t.Errorf("fee cap = %s, want %s", tx.GasFeeCap(), gasPrice)
+			}
+			wantTip := gasPrice
+			if tt.wantType == types.DynamicFeeTxType {
+				wantTip = tipCap
+				if tx.ChainId().Int64() != int64(tt.params.ChainID) {
+					t.Errorf("chain id = %s, want %d", tx.ChainId(), tt.params.ChainID)
+				}
+			}
+			if tx.GasTipCap().Cmp(wantTip) != 0 {
+				t.Errorf("tip cap = %s, want %s", tx.GasTipCap(), wantTip)
+			}
+			if tx.Nonce() != 3 || tx.Gas() != 21000 {
+				t.Errorf("nonce = %d gas = %d, want 3 and 21000", tx.Nonce(), tx.Gas())
+			}
+		})
+	}
+}
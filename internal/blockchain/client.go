@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -13,14 +14,23 @@ type BlockchainClient interface {
 	// GetBalance 查询地址余额
 	GetBalance(ctx context.Context, address string) (*big.Int, error)
 
+	// BatchGetBalances 批量查询地址余额（结果与addresses一一对应）
+	BatchGetBalances(ctx context.Context, addresses []string) ([]*big.Int, error)
+
 	// GetNonce 获取地址的nonce
 	GetNonce(ctx context.Context, address string) (uint64, error)
 
+	// GetConfirmedNonce 获取地址在最新区块中的nonce（已打包的交易数，不含交易池）
+	GetConfirmedNonce(ctx context.Context, address string) (uint64, error)
+
 	// GetGasPrice 获取当前gas价格
 	GetGasPrice(ctx context.Context) (*big.Int, error)
 
-	// EstimateGas 估算gas用量
-	EstimateGas(ctx context.Context, from, to string, value *big.Int) (uint64, error)
+	// FeeHistory 查询最近blockCount个区块的基础费用与小费分位数（eth_feeHistory，BaseFee含下一个区块）
+	FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+
+	// EstimateGas 估算gas用量（data为合约调用数据，普通转账传nil）
+	EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (uint64, error)
 
 	// SendTransaction 发送交易
 	SendTransaction(ctx context.Context, signedTx *types.Transaction) error
@@ -28,6 +38,28 @@ type BlockchainClient interface {
 	// GetTransactionReceipt 获取交易回执
 	GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
 
+	// GetTransactionByHash 按哈希查询交易，isPending表示交易仍在交易池中等待打包；节点不知道该交易（从未收到或已从交易池移除）时返回ethereum.NotFound
+	GetTransactionByHash(ctx context.Context, txHash string) (tx *types.Transaction, isPending bool, err error)
+
+	// CallContract 执行只读合约调用（eth_call），blockNumber为nil表示最新区块
+	CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error)
+
+	// SimulateCall 以指定发送方与金额在最新区块上执行调用（eth_call），用于发送前模拟交易；执行回滚时可通过RevertReason解析原因
+	SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error)
+
+	// ReplayTransaction 在指定区块上以原交易的发送方、接收方、金额、数据与gas上限重新执行已上链的交易（eth_call），
+	// 用于获取失败交易的回滚原因（通过RevertReason解析）；节点已裁剪该区块的状态时返回节点错误
+	ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) ([]byte, error)
+
+	// FilterLogs 按区块范围、合约地址与topic查询事件日志（eth_getLogs）
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+
+	// ResolveName 解析ENS名称对应的地址（当前链不支持ENS时返回ErrENSUnsupported，未解析到地址时返回ErrENSNameNotFound）
+	ResolveName(ctx context.Context, name string) (string, error)
+
+	// LookupAddress 反向解析地址的ENS主名称，未设置时返回空字符串
+	LookupAddress(ctx context.Context, address string) (string, error)
+
 	// GetBlockNumber 获取最新区块号
 	GetBlockNumber(ctx context.Context) (uint64, error)
 
@@ -0,0 +1,37 @@
+package blockchain
+
+// Clients 按链ID路由的区块链客户端（每条配置了节点的链一个客户端）
+//
+// 余额、nonce、Gas价格、广播与回执查询必须使用钱包或交易所在链的客户端，不能回退到其他链的节点。
+type Clients struct {
+	list []BlockchainClient
+	byID map[int]BlockchainClient
+}
+
+// NewClients 创建客户端集合（按传入顺序，第一个为默认链）
+func NewClients(clients ...BlockchainClient) *Clients {
+	c := &Clients{
+		list: clients,
+		byID: make(map[int]BlockchainClient, len(clients)),
+	}
+	for _, client := range clients {
+		c.byID[client.GetChainID()] = client
+	}
+	return c
+}
+
+// Get 获取链的客户端，没有连接该链的节点时返回false
+func (c *Clients) Get(chainID int) (BlockchainClient, bool) {
+	client, ok := c.byID[chainID]
+	return client, ok
+}
+
+// All 全部客户端（按配置顺序）
+func (c *Clients) All() []BlockchainClient {
+	return append([]BlockchainClient(nil), c.list...)
+}
+
+// Default 默认链的客户端（配置中第一条连接了节点的链，用于不区分链的操作，如生成密钥对）
+func (c *Clients) Default() BlockchainClient {
+	return c.list[0]
+}
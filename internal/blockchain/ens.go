@@ -0,0 +1,165 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ENSRegistryAddress ENS注册表合约地址（主网与测试网相同）
+const ENSRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// ensChains 部署了ENS注册表的链
+var ensChains = map[int]bool{
+	1:        true, // Ethereum
+	11155111: true, // Sepolia
+}
+
+var (
+	// ErrENSUnsupported 当前链没有部署ENS
+	ErrENSUnsupported = errors.New("ENS is not available on this chain")
+	// ErrENSNameNotFound ENS名称未注册、未设置解析器或解析结果为零地址
+	ErrENSNameNotFound = errors.New("ENS name does not resolve to an address")
+)
+
+// ensNamePattern ENS名称格式（仅支持.eth，标签为小写字母、数字与连字符）
+var ensNamePattern = regexp.MustCompile(`^([a-z0-9-]+\.)+eth$`)
+
+// ensABI ENS注册表与解析器的只读方法
+var ensABI = mustParseABI(`[
+	{"type":"function","name":"resolver","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"name","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"string"}]}
+]`)
+
+// contractCaller 执行只读合约调用的客户端
+type contractCaller interface {
+	CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error)
+}
+
+// NormalizeENSName 规范化ENS名称（去除首尾空白并转为小写，不做完整的UTS-46处理）
+func NormalizeENSName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// IsENSName 是否为ENS名称（如vitalik.eth）
+func IsENSName(name string) bool {
+	name = NormalizeENSName(name)
+	return len(name) <= 255 && ensNamePattern.MatchString(name)
+}
+
+// NameHash 计算ENS名称的namehash（EIP-137）
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node.Bytes(), labelHash)
+	}
+	return node
+}
+
+// resolveENSName 通过ENS注册表查询名称的解析器并解析地址
+func resolveENSName(ctx context.Context, caller contractCaller, chainID int, name string) (string, error) {
+	if !ensChains[chainID] {
+		return "", ErrENSUnsupported
+	}
+	node := NameHash(NormalizeENSName(name))
+
+	// 1. 查询解析器
+	resolver, err := callENSAddress(ctx, caller, ENSRegistryAddress, "resolver", node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == (common.Address{}) {
+		return "", ErrENSNameNotFound
+	}
+
+	// 2. 解析地址
+	address, err := callENSAddress(ctx, caller, resolver.Hex(), "addr", node)
+	if err != nil {
+		return "", err
+	}
+	if address == (common.Address{}) {
+		return "", ErrENSNameNotFound
+	}
+	return address.Hex(), nil
+}
+
+// lookupENSAddress 反向解析地址的主名称（正向解析结果与地址不一致时视为未设置），未设置时返回空字符串
+func lookupENSAddress(ctx context.Context, caller contractCaller, chainID int, address string) (string, error) {
+	if !ensChains[chainID] {
+		return "", ErrENSUnsupported
+	}
+	reverseName := strings.ToLower(strings.TrimPrefix(common.HexToAddress(address).Hex(), "0x")) + ".addr.reverse"
+	node := NameHash(reverseName)
+
+	// 1. 查询反向记录的解析器
+	resolver, err := callENSAddress(ctx, caller, ENSRegistryAddress, "resolver", node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == (common.Address{}) {
+		return "", nil
+	}
+
+	// 2. 读取名称
+	data, err := ensABI.Pack("name", node)
+	if err != nil {
+		return "", err
+	}
+	result, err := caller.CallContract(ctx, resolver.Hex(), data, nil)
+	if err != nil {
+		return "", err
+	}
+	values, err := ensABI.Unpack("name", result)
+	if err != nil || len(values) == 0 {
+		return "", nil
+	}
+	name, _ := values[0].(string)
+	if name == "" {
+		return "", nil
+	}
+
+	// 3. 正向校验（反向记录可由任何人设置为任意名称）
+	resolved, err := resolveENSName(ctx, caller, chainID, name)
+	if err != nil {
+		if errors.Is(err, ErrENSNameNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !strings.EqualFold(resolved, address) {
+		return "", nil
+	}
+	return name, nil
+}
+
+// callENSAddress 调用返回address的ENS方法
+func callENSAddress(ctx context.Context, caller contractCaller, contract, method string, node common.Hash) (common.Address, error) {
+	data, err := ensABI.Pack(method, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	result, err := caller.CallContract(ctx, contract, data, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(result) == 0 {
+		return common.Address{}, nil
+	}
+	values, err := ensABI.Unpack(method, result)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to decode %s() result: %w", method, err)
+	}
+	return values[0].(common.Address), nil
+}
@@ -0,0 +1,137 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// erc20ABI ERC-20只读方法
+var erc20ABI = mustParseABI(`[
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`)
+
+// ERC20TransferTopic Transfer(address,address,uint256)事件签名
+var ERC20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// TokenTransfer 解码后的ERC-20 Transfer事件
+type TokenTransfer struct {
+	Contract string
+	From     string
+	To       string
+	Value    *big.Int
+}
+
+// TokenMetadata ERC-20代币元数据
+type TokenMetadata struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// ReadTokenMetadata 从合约读取name、symbol、decimals
+func ReadTokenMetadata(ctx context.Context, client BlockchainClient, contract string) (*TokenMetadata, error) {
+	symbol, err := callTokenString(ctx, client, contract, "symbol")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbol: %w", err)
+	}
+
+	decimals, err := callERC20(ctx, client, contract, "decimals")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decimals: %w", err)
+	}
+
+	// name非必需，读取失败时使用symbol
+	name, err := callTokenString(ctx, client, contract, "name")
+	if err != nil || name == "" {
+		name = symbol
+	}
+
+	return &TokenMetadata{
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: decimals[0].(uint8),
+	}, nil
+}
+
+// TokenBalanceOf 查询地址的ERC-20代币余额（最小单位）
+func TokenBalanceOf(ctx context.Context, client BlockchainClient, contract, owner string) (*big.Int, error) {
+	values, err := callERC20(ctx, client, contract, "balanceOf", common.HexToAddress(owner))
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// DecodeTransferLog 解码ERC-20 Transfer事件，非标准事件（如ERC-721的Transfer，tokenId在topic中）返回错误
+func DecodeTransferLog(log *types.Log) (*TokenTransfer, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != ERC20TransferTopic {
+		return nil, fmt.Errorf("not an ERC-20 Transfer event")
+	}
+	if len(log.Data) != 32 {
+		return nil, fmt.Errorf("unexpected Transfer event data length: %d", len(log.Data))
+	}
+	return &TokenTransfer{
+		Contract: log.Address.Hex(),
+		From:     common.BytesToAddress(log.Topics[1].Bytes()).Hex(),
+		To:       common.BytesToAddress(log.Topics[2].Bytes()).Hex(),
+		Value:    new(big.Int).SetBytes(log.Data),
+	}, nil
+}
+
+// callERC20 编码并执行ERC-20只读调用
+func callERC20(ctx context.Context, client BlockchainClient, contract, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := erc20ABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.CallContract(ctx, contract, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty result from %s(), contract may not be an ERC-20 token", method)
+	}
+	return erc20ABI.Unpack(method, result)
+}
+
+// callTokenString 读取字符串字段，兼容早期以bytes32返回的代币（如MKR）
+func callTokenString(ctx context.Context, client BlockchainClient, contract, method string) (string, error) {
+	data, err := erc20ABI.Pack(method)
+	if err != nil {
+		return "", err
+	}
+	result, err := client.CallContract(ctx, contract, data, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("empty result from %s(), contract may not be an ERC-20 token", method)
+	}
+
+	if values, err := erc20ABI.Unpack(method, result); err == nil {
+		return values[0].(string), nil
+	}
+	if len(result) == 32 {
+		return string(bytes.TrimRight(result, "\x00")), nil
+	}
+	return "", fmt.Errorf("unexpected %s() return data", method)
+}
+
+// mustParseABI 解析内置ABI，失败时panic
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := ParseABI(abiJSON)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
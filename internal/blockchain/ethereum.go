@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // EthereumClient 以太坊客户端实现
@@ -42,6 +45,32 @@ func (c *EthereumClient) GetBalance(ctx context.Context, address string) (*big.I
 	return balance, nil
 }
 
+// BatchGetBalances 通过JSON-RPC批量请求查询多个地址余额（一次HTTP往返，任一地址失败时返回错误）
+func (c *EthereumClient) BatchGetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	results := make([]hexutil.Big, len(addresses))
+	batch := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{common.HexToAddress(address), "latest"},
+			Result: &results[i],
+		}
+	}
+
+	if err := c.client.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("eth_getBalance %s: %w", addresses[i], elem.Error)
+		}
+		balances[i] = results[i].ToInt()
+	}
+	return balances, nil
+}
+
 // GetNonce 获取地址的nonce（交易计数）
 func (c *EthereumClient) GetNonce(ctx context.Context, address string) (uint64, error) {
 	account := common.HexToAddress(address)
@@ -52,6 +81,12 @@ func (c *EthereumClient) GetNonce(ctx context.Context, address string) (uint64,
 	return nonce, nil
 }
 
+// GetConfirmedNonce 获取地址在最新区块中的nonce
+func (c *EthereumClient) GetConfirmedNonce(ctx context.Context, address string) (uint64, error) {
+	account := common.HexToAddress(address)
+	return c.client.NonceAt(ctx, account, nil)
+}
+
 // GetGasPrice 获取当前建议的gas价格
 func (c *EthereumClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	gasPrice, err := c.client.SuggestGasPrice(ctx)
@@ -61,8 +96,13 @@ func (c *EthereumClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	return gasPrice, nil
 }
 
+// FeeHistory 查询最近区块的基础费用与小费分位数
+func (c *EthereumClient) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return c.client.FeeHistory(ctx, blockCount, nil, rewardPercentiles)
+}
+
 // EstimateGas 估算交易所需的gas
-func (c *EthereumClient) EstimateGas(ctx context.Context, from, to string, value *big.Int) (uint64, error) {
+func (c *EthereumClient) EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (uint64, error) {
 	fromAddr := common.HexToAddress(from)
 	toAddr := common.HexToAddress(to)
 
@@ -71,6 +111,7 @@ func (c *EthereumClient) EstimateGas(ctx context.Context, from, to string, value
 		From:  fromAddr,
 		To:    &toAddr,
 		Value: value,
+		Data:  data,
 	}
 
 	// 估算gas
@@ -97,6 +138,70 @@ func (c *EthereumClient) GetTransactionReceipt(ctx context.Context, txHash strin
 	return receipt, nil
 }
 
+// GetTransactionByHash 按哈希查询交易（区分交易池中的交易与节点不知道的交易）
+func (c *EthereumClient) GetTransactionByHash(ctx context.Context, txHash string) (*types.Transaction, bool, error) {
+	return c.client.TransactionByHash(ctx, common.HexToHash(txHash))
+}
+
+// CallContract 执行只读合约调用（不上链、不消耗gas）
+func (c *EthereumClient) CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error) {
+	toAddr := common.HexToAddress(to)
+	msg := ethereum.CallMsg{
+		To:   &toAddr,
+		Data: data,
+	}
+	return c.client.CallContract(ctx, msg, blockNumber)
+}
+
+// SimulateCall 以指定发送方与金额在最新区块上执行调用（不上链、不消耗gas）
+func (c *EthereumClient) SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error) {
+	toAddr := common.HexToAddress(to)
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(from),
+		To:    &toAddr,
+		Value: value,
+		Data:  data,
+	}
+	return c.client.CallContract(ctx, msg, nil)
+}
+
+// ReplayTransaction 在指定区块上重新执行已上链的交易（不上链、不消耗gas）
+//
+// 不设置gas单价，避免按调用时的余额校验手续费；gas上限沿用原交易，以便复现gas耗尽。
+func (c *EthereumClient) ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) ([]byte, error) {
+	tx, _, err := c.client.TransactionByHash(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	return c.client.CallContract(ctx, msg, blockNumber)
+}
+
+// FilterLogs 查询事件日志
+func (c *EthereumClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return c.client.FilterLogs(ctx, query)
+}
+
+// ResolveName 解析ENS名称对应的地址
+func (c *EthereumClient) ResolveName(ctx context.Context, name string) (string, error) {
+	return resolveENSName(ctx, c, c.chainID, name)
+}
+
+// LookupAddress 反向解析地址的ENS主名称
+func (c *EthereumClient) LookupAddress(ctx context.Context, address string) (string, error) {
+	return lookupENSAddress(ctx, c, c.chainID, address)
+}
+
 // GetBlockNumber 获取最新区块号
 func (c *EthereumClient) GetBlockNumber(ctx context.Context) (uint64, error) {
 	header, err := c.client.HeaderByNumber(ctx, nil)
@@ -106,6 +211,11 @@ func (c *EthereumClient) GetBlockNumber(ctx context.Context) (uint64, error) {
 	return header.Number.Uint64(), nil
 }
 
+// SubscribeNewHead 订阅新区块头（需使用websocket地址创建客户端）
+func (c *EthereumClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return c.client.SubscribeNewHead(ctx, ch)
+}
+
 // CreateWallet 创建新钱包（生成私钥和地址）
 func (c *EthereumClient) CreateWallet() (address string, privateKey *ecdsa.PrivateKey, err error) {
 	// 生成私钥
@@ -129,8 +239,8 @@ func (c *EthereumClient) CreateWallet() (address string, privateKey *ecdsa.Priva
 
 // SignTransaction 签名交易
 func (c *EthereumClient) SignTransaction(tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
-	// 使用EIP-155签名（防重放攻击）
-	signer := types.NewEIP155Signer(chainID)
+	// legacy交易使用EIP-155签名（防重放攻击），EIP-1559动态费用交易按交易类型签名
+	signer := types.LatestSignerForChainID(chainID)
 	signedTx, err := types.SignTx(tx, signer, privateKey)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,475 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// RPC节点选择策略
+const (
+	StrategyPrimary    = "primary"     // 优先使用第一个健康节点，故障时依次回退
+	StrategyRoundRobin = "round_robin" // 在健康节点间轮询
+)
+
+// rpcEndpoint 单个RPC节点及其健康状态
+type rpcEndpoint struct {
+	name        string // 脱敏后的节点标识（仅scheme+host，避免日志泄露API Key）
+	client      *EthereumClient
+	healthy     bool
+	latestBlock uint64
+	lastError   string
+}
+
+// EndpointStatus RPC节点状态（用于运维排查）
+type EndpointStatus struct {
+	Name        string `json:"name"`
+	Healthy     bool   `json:"healthy"`
+	LatestBlock uint64 `json:"latest_block"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// EndpointReporter 可报告各RPC节点状态的客户端
+type EndpointReporter interface {
+	Endpoints() []EndpointStatus
+}
+
+// FailoverClient 多RPC节点故障转移客户端
+type FailoverClient struct {
+	endpoints   []*rpcEndpoint
+	chainID     int
+	strategy    string
+	maxBlockLag uint64
+	interval    time.Duration
+
+	mu        sync.RWMutex
+	next      uint64 // 轮询计数
+	failovers uint64 // 故障转移次数
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewFailoverClient 创建多节点客户端并启动健康检查
+func NewFailoverClient(rpcURLs []string, chainID int, strategy string, maxBlockLag uint64, healthCheckInterval time.Duration) (*FailoverClient, error) {
+	if len(rpcURLs) == 0 {
+		return nil, errors.New("no rpc endpoints configured")
+	}
+	if strategy != StrategyRoundRobin {
+		strategy = StrategyPrimary
+	}
+	if healthCheckInterval <= 0 {
+		healthCheckInterval = 15 * time.Second
+	}
+
+	c := &FailoverClient{
+		chainID:     chainID,
+		strategy:    strategy,
+		maxBlockLag: maxBlockLag,
+		interval:    healthCheckInterval,
+		stop:        make(chan struct{}),
+	}
+
+	for _, rpcURL := range rpcURLs {
+		client, err := NewEthereumClient(rpcURL, chainID)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to dial rpc endpoint %s: %w", endpointName(rpcURL), err)
+		}
+		c.endpoints = append(c.endpoints, &rpcEndpoint{
+			name:    endpointName(rpcURL),
+			client:  client,
+			healthy: true,
+		})
+	}
+
+	go c.healthLoop()
+	return c, nil
+}
+
+// endpointName 提取节点标识（去除路径与查询参数中的密钥）
+func endpointName(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil || u.Host == "" {
+		return "invalid-url"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// healthLoop 定期检查节点的最新区块与延迟
+func (c *FailoverClient) healthLoop() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.checkHealth()
+		}
+	}
+}
+
+// checkHealth 并发查询各节点区块高度，落后超过maxBlockLag或出错的节点被降级
+func (c *FailoverClient) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.interval/2)
+	defer cancel()
+
+	blocks := make([]uint64, len(c.endpoints))
+	errs := make([]error, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range c.endpoints {
+		wg.Add(1)
+		go func(i int, ep *rpcEndpoint) {
+			defer wg.Done()
+			blocks[i], errs[i] = ep.client.GetBlockNumber(ctx)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i := range c.endpoints {
+		if errs[i] == nil && blocks[i] > highest {
+			highest = blocks[i]
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, ep := range c.endpoints {
+		healthy := errs[i] == nil && (c.maxBlockLag == 0 || highest-blocks[i] <= c.maxBlockLag)
+
+		lastError := ""
+		if errs[i] != nil {
+			lastError = errs[i].Error()
+			ep.latestBlock = 0
+		} else {
+			ep.latestBlock = blocks[i]
+			if !healthy {
+				lastError = fmt.Sprintf("lagging %d blocks behind", highest-blocks[i])
+			}
+		}
+
+		if healthy != ep.healthy {
+			if healthy {
+				logger.Info("rpc endpoint recovered",
+					zap.String("endpoint", ep.name),
+					zap.Uint64("latest_block", blocks[i]),
+				)
+			} else {
+				logger.Warn("rpc endpoint demoted by health check",
+					zap.String("endpoint", ep.name),
+					zap.String("reason", lastError),
+				)
+			}
+		}
+		ep.healthy = healthy
+		ep.lastError = lastError
+	}
+}
+
+// candidates 按策略返回本次请求的节点顺序（无健康节点时退化为全部节点）
+func (c *FailoverClient) candidates() []*rpcEndpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	healthy := make([]*rpcEndpoint, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		if ep.healthy {
+			healthy = append(healthy, ep)
+		}
+	}
+	if len(healthy) == 0 {
+		return append([]*rpcEndpoint{}, c.endpoints...)
+	}
+
+	if c.strategy == StrategyRoundRobin && len(healthy) > 1 {
+		start := int(atomic.AddUint64(&c.next, 1) % uint64(len(healthy)))
+		ordered := make([]*rpcEndpoint, 0, len(healthy))
+		ordered = append(ordered, healthy[start:]...)
+		return append(ordered, healthy[:start]...)
+	}
+	return healthy
+}
+
+// demote 请求失败时降级节点，等待健康检查恢复
+func (c *FailoverClient) demote(ep *rpcEndpoint, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ep.healthy {
+		logger.Warn("rpc endpoint demoted after request error",
+			zap.String("endpoint", ep.name),
+			zap.Error(err),
+		)
+	}
+	ep.healthy = false
+	ep.lastError = err.Error()
+}
+
+// do 在候选节点上依次执行请求，仅节点级错误（网络、限流、5xx）触发故障转移
+func (c *FailoverClient) do(ctx context.Context, method string, fn func(client *EthereumClient) error) error {
+	candidates := c.candidates()
+
+	var lastErr error
+	for i, ep := range candidates {
+		err := fn(ep.client)
+		if err == nil || !isEndpointError(ctx, err) {
+			return err
+		}
+
+		lastErr = err
+		c.demote(ep, err)
+
+		if i+1 < len(candidates) {
+			total := atomic.AddUint64(&c.failovers, 1)
+			logger.WithCtx(ctx).Warn("rpc failover",
+				zap.String("method", method),
+				zap.String("from", ep.name),
+				zap.String("to", candidates[i+1].name),
+				zap.Uint64("failovers_total", total),
+				zap.Error(err),
+			)
+			trace.SpanFromContext(ctx).AddEvent("rpc.failover", trace.WithAttributes(
+				attribute.String("rpc.method", method),
+				attribute.String("rpc.from", ep.name),
+				attribute.String("rpc.to", candidates[i+1].name),
+			))
+		}
+	}
+	return lastErr
+}
+
+// isEndpointError 判断错误是否由节点本身引起（业务错误如nonce过低、执行回滚不触发故障转移）
+func isEndpointError(ctx context.Context, err error) bool {
+	// 调用方取消或超时
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == -32005 // 请求超出限额
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Endpoints 返回各节点当前状态
+func (c *FailoverClient) Endpoints() []EndpointStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, ep := range c.endpoints {
+		statuses[i] = EndpointStatus{
+			Name:        ep.name,
+			Healthy:     ep.healthy,
+			LatestBlock: ep.latestBlock,
+			LastError:   ep.lastError,
+		}
+	}
+	return statuses
+}
+
+// GetBalance 查询地址余额
+func (c *FailoverClient) GetBalance(ctx context.Context, address string) (balance *big.Int, err error) {
+	err = c.do(ctx, "GetBalance", func(client *EthereumClient) error {
+		balance, err = client.GetBalance(ctx, address)
+		return err
+	})
+	return balance, err
+}
+
+// BatchGetBalances 批量查询地址余额
+func (c *FailoverClient) BatchGetBalances(ctx context.Context, addresses []string) (balances []*big.Int, err error) {
+	err = c.do(ctx, "BatchGetBalances", func(client *EthereumClient) error {
+		balances, err = client.BatchGetBalances(ctx, addresses)
+		return err
+	})
+	return balances, err
+}
+
+// GetNonce 获取地址的nonce
+func (c *FailoverClient) GetNonce(ctx context.Context, address string) (nonce uint64, err error) {
+	err = c.do(ctx, "GetNonce", func(client *EthereumClient) error {
+		nonce, err = client.GetNonce(ctx, address)
+		return err
+	})
+	return nonce, err
+}
+
+// GetConfirmedNonce 获取地址在最新区块中的nonce
+func (c *FailoverClient) GetConfirmedNonce(ctx context.Context, address string) (nonce uint64, err error) {
+	err = c.do(ctx, "GetConfirmedNonce", func(client *EthereumClient) error {
+		nonce, err = client.GetConfirmedNonce(ctx, address)
+		return err
+	})
+	return nonce, err
+}
+
+// GetGasPrice 获取当前gas价格
+func (c *FailoverClient) GetGasPrice(ctx context.Context) (gasPrice *big.Int, err error) {
+	err = c.do(ctx, "GetGasPrice", func(client *EthereumClient) error {
+		gasPrice, err = client.GetGasPrice(ctx)
+		return err
+	})
+	return gasPrice, err
+}
+
+// FeeHistory 查询最近区块的基础费用与小费分位数
+func (c *FailoverClient) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (history *ethereum.FeeHistory, err error) {
+	err = c.do(ctx, "FeeHistory", func(client *EthereumClient) error {
+		history, err = client.FeeHistory(ctx, blockCount, rewardPercentiles)
+		return err
+	})
+	return history, err
+}
+
+// EstimateGas 估算gas用量
+func (c *FailoverClient) EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (gas uint64, err error) {
+	err = c.do(ctx, "EstimateGas", func(client *EthereumClient) error {
+		gas, err = client.EstimateGas(ctx, from, to, value, data)
+		return err
+	})
+	return gas, err
+}
+
+// SendTransaction 发送交易（同一已签名交易重复广播是安全的）
+func (c *FailoverClient) SendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	return c.do(ctx, "SendTransaction", func(client *EthereumClient) error {
+		return client.SendTransaction(ctx, signedTx)
+	})
+}
+
+// GetTransactionReceipt 获取交易回执
+func (c *FailoverClient) GetTransactionReceipt(ctx context.Context, txHash string) (receipt *types.Receipt, err error) {
+	err = c.do(ctx, "GetTransactionReceipt", func(client *EthereumClient) error {
+		receipt, err = client.GetTransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+// GetTransactionByHash 按哈希查询交易
+func (c *FailoverClient) GetTransactionByHash(ctx context.Context, txHash string) (tx *types.Transaction, isPending bool, err error) {
+	err = c.do(ctx, "GetTransactionByHash", func(client *EthereumClient) error {
+		tx, isPending, err = client.GetTransactionByHash(ctx, txHash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+// CallContract 执行只读合约调用
+func (c *FailoverClient) CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, "CallContract", func(client *EthereumClient) error {
+		result, err = client.CallContract(ctx, to, data, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// SimulateCall 模拟执行调用（执行回滚不触发故障转移）
+func (c *FailoverClient) SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) (result []byte, err error) {
+	err = c.do(ctx, "SimulateCall", func(client *EthereumClient) error {
+		result, err = client.SimulateCall(ctx, from, to, value, data)
+		return err
+	})
+	return result, err
+}
+
+// ReplayTransaction 重新执行已上链的交易（执行回滚不触发故障转移）
+func (c *FailoverClient) ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, "ReplayTransaction", func(client *EthereumClient) error {
+		result, err = client.ReplayTransaction(ctx, txHash, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// FilterLogs 查询事件日志
+func (c *FailoverClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.do(ctx, "FilterLogs", func(client *EthereumClient) error {
+		logs, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+// ResolveName 解析ENS名称对应的地址
+func (c *FailoverClient) ResolveName(ctx context.Context, name string) (address string, err error) {
+	err = c.do(ctx, "ResolveName", func(client *EthereumClient) error {
+		address, err = client.ResolveName(ctx, name)
+		return err
+	})
+	return address, err
+}
+
+// LookupAddress 反向解析地址的ENS主名称
+func (c *FailoverClient) LookupAddress(ctx context.Context, address string) (name string, err error) {
+	err = c.do(ctx, "LookupAddress", func(client *EthereumClient) error {
+		name, err = client.LookupAddress(ctx, address)
+		return err
+	})
+	return name, err
+}
+
+// GetBlockNumber 获取最新区块号
+func (c *FailoverClient) GetBlockNumber(ctx context.Context) (number uint64, err error) {
+	err = c.do(ctx, "GetBlockNumber", func(client *EthereumClient) error {
+		number, err = client.GetBlockNumber(ctx)
+		return err
+	})
+	return number, err
+}
+
+// CreateWallet 创建钱包（本地操作）
+func (c *FailoverClient) CreateWallet() (string, *ecdsa.PrivateKey, error) {
+	return c.endpoints[0].client.CreateWallet()
+}
+
+// SignTransaction 签名交易（本地操作）
+func (c *FailoverClient) SignTransaction(tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
+	return c.endpoints[0].client.SignTransaction(tx, privateKey, chainID)
+}
+
+// GetChainID 获取链ID
+func (c *FailoverClient) GetChainID() int {
+	return c.chainID
+}
+
+// Close 停止健康检查并关闭所有连接
+func (c *FailoverClient) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	for _, ep := range c.endpoints {
+		ep.client.Close()
+	}
+}
@@ -0,0 +1,499 @@
+// Package mock 提供可编排的内存区块链客户端，用于本地调试与服务层测试
+package mock
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"crypto-wallet-api/internal/blockchain"
+)
+
+// 可注入故障的方法名（与BlockchainClient方法同名）
+const (
+	MethodGetBalance            = "GetBalance"
+	MethodBatchGetBalances      = "BatchGetBalances"
+	MethodGetNonce              = "GetNonce"
+	MethodGetConfirmedNonce     = "GetConfirmedNonce"
+	MethodGetGasPrice           = "GetGasPrice"
+	MethodFeeHistory            = "FeeHistory"
+	MethodEstimateGas           = "EstimateGas"
+	MethodSendTransaction       = "SendTransaction"
+	MethodGetTransactionReceipt = "GetTransactionReceipt"
+	MethodGetTransactionByHash  = "GetTransactionByHash"
+	MethodCallContract          = "CallContract"
+	MethodSimulateCall          = "SimulateCall"
+	MethodReplayTransaction     = "ReplayTransaction"
+	MethodFilterLogs            = "FilterLogs"
+	MethodResolveName           = "ResolveName"
+	MethodLookupAddress         = "LookupAddress"
+	MethodGetBlockNumber        = "GetBlockNumber"
+	MethodCreateWallet          = "CreateWallet"
+	MethodSignTransaction       = "SignTransaction"
+)
+
+var _ blockchain.BlockchainClient = (*Client)(nil)
+
+// Client 内存区块链客户端（并发安全）
+type Client struct {
+	mu          sync.Mutex
+	chainID     int
+	balances    map[string]*big.Int
+	nonces      map[string]uint64
+	confirmed   map[string]uint64
+	gasPrice    *big.Int
+	feeHistory  *ethereum.FeeHistory // 为nil时FeeHistory返回错误（模拟不支持eth_feeHistory的节点）
+	gasEstimate uint64
+	blockNumber uint64
+	receipts    map[string]*types.Receipt
+	callResults map[string][]byte
+	logs        []types.Log
+	ensNames    map[string]string // ENS名称 -> 地址
+	failures    map[string]error
+	sent        []*types.Transaction
+}
+
+// NewClient 创建内存区块链客户端（默认Gas价格1 Gwei、Gas估算21000）
+func NewClient(chainID int) *Client {
+	return &Client{
+		chainID:     chainID,
+		balances:    make(map[string]*big.Int),
+		nonces:      make(map[string]uint64),
+		confirmed:   make(map[string]uint64),
+		gasPrice:    big.NewInt(1_000_000_000),
+		gasEstimate: 21000,
+		receipts:    make(map[string]*types.Receipt),
+		callResults: make(map[string][]byte),
+		ensNames:    make(map[string]string),
+		failures:    make(map[string]error),
+	}
+}
+
+// SetBalance 设置地址余额（Wei）
+func (c *Client) SetBalance(address string, wei *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances[normalize(address)] = new(big.Int).Set(wei)
+}
+
+// SetNonce 设置地址nonce
+func (c *Client) SetNonce(address string, nonce uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nonces[normalize(address)] = nonce
+}
+
+// SetConfirmedNonce 设置地址在最新区块中的nonce（未设置时与SetNonce一致）
+func (c *Client) SetConfirmedNonce(address string, nonce uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.confirmed[normalize(address)] = nonce
+}
+
+// SetGasPrice 设置Gas价格（Wei）
+func (c *Client) SetGasPrice(wei *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gasPrice = new(big.Int).Set(wei)
+}
+
+// SetFeeHistory 设置eth_feeHistory的返回结果
+func (c *Client) SetFeeHistory(history *ethereum.FeeHistory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.feeHistory = history
+}
+
+// SetGasEstimate 设置Gas估算结果
+func (c *Client) SetGasEstimate(gas uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gasEstimate = gas
+}
+
+// SetBlockNumber 设置最新区块号
+func (c *Client) SetBlockNumber(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockNumber = number
+}
+
+// SetReceipt 设置交易回执（status为types.ReceiptStatusSuccessful或types.ReceiptStatusFailed）
+func (c *Client) SetReceipt(txHash string, status uint64, blockNumber uint64, gasUsed uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receipts[normalize(txHash)] = &types.Receipt{
+		Status:      status,
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+		GasUsed:     gasUsed,
+	}
+}
+
+// AddLog 添加事件日志（供FilterLogs查询；已设置回执的交易同时写入回执日志）
+func (c *Client) AddLog(log types.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, log)
+	if receipt, ok := c.receipts[normalize(log.TxHash.Hex())]; ok {
+		receipt.Logs = append(receipt.Logs, &log)
+	}
+}
+
+// SetCallResult 设置合约只读调用的返回数据
+func (c *Client) SetCallResult(to string, result []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.callResults[normalize(to)] = result
+}
+
+// SetENSName 设置ENS名称解析结果（同时作为该地址的反向解析名称）
+func (c *Client) SetENSName(name, address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensNames[blockchain.NormalizeENSName(name)] = address
+}
+
+// FailOn 注入故障：之后调用指定方法均返回err，err为nil时取消
+func (c *Client) FailOn(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.failures, method)
+		return
+	}
+	c.failures[method] = err
+}
+
+// SentTransactions 已广播的交易（按发送顺序）
+func (c *Client) SentTransactions() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*types.Transaction(nil), c.sent...)
+}
+
+// GetBalance 查询地址余额（未设置时为0）
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetBalance]; err != nil {
+		return nil, err
+	}
+	if balance, ok := c.balances[normalize(address)]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return big.NewInt(0), nil
+}
+
+// BatchGetBalances 批量查询地址余额（未设置的地址为0）
+func (c *Client) BatchGetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodBatchGetBalances]; err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addresses))
+	for i, address := range addresses {
+		balances[i] = big.NewInt(0)
+		if balance, ok := c.balances[normalize(address)]; ok {
+			balances[i].Set(balance)
+		}
+	}
+	return balances, nil
+}
+
+// GetNonce 获取地址的nonce
+func (c *Client) GetNonce(ctx context.Context, address string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetNonce]; err != nil {
+		return 0, err
+	}
+	return c.nonces[normalize(address)], nil
+}
+
+// GetConfirmedNonce 获取地址在最新区块中的nonce
+func (c *Client) GetConfirmedNonce(ctx context.Context, address string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetConfirmedNonce]; err != nil {
+		return 0, err
+	}
+	if nonce, ok := c.confirmed[normalize(address)]; ok {
+		return nonce, nil
+	}
+	return c.nonces[normalize(address)], nil
+}
+
+// GetGasPrice 获取当前gas价格
+func (c *Client) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetGasPrice]; err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.gasPrice), nil
+}
+
+// FeeHistory 查询最近区块的基础费用与小费分位数（未设置时返回错误）
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodFeeHistory]; err != nil {
+		return nil, err
+	}
+	if c.feeHistory == nil {
+		return nil, fmt.Errorf("the method eth_feeHistory does not exist/is not available")
+	}
+	return c.feeHistory, nil
+}
+
+// EstimateGas 估算gas用量
+func (c *Client) EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodEstimateGas]; err != nil {
+		return 0, err
+	}
+	return c.gasEstimate, nil
+}
+
+// SendTransaction 广播交易：校验链ID与nonce，记录交易并扣减发送方余额
+func (c *Client) SendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodSendTransaction]; err != nil {
+		return err
+	}
+
+	// 1. 校验链ID（与节点行为一致）
+	if signedTx.ChainId().Int64() != int64(c.chainID) {
+		return fmt.Errorf("invalid chain id: have %d want %d", signedTx.ChainId().Int64(), c.chainID)
+	}
+
+	// 2. 恢复发送方并校验nonce
+	from, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+	if err != nil {
+		return err
+	}
+	sender := normalize(from.Hex())
+	if signedTx.Nonce() != c.nonces[sender] {
+		return fmt.Errorf("invalid nonce: have %d want %d", signedTx.Nonce(), c.nonces[sender])
+	}
+
+	// 3. 校验余额（金额 + Gas上限费用）
+	balance, ok := c.balances[sender]
+	if !ok {
+		balance = big.NewInt(0)
+	}
+	if balance.Cmp(signedTx.Cost()) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value")
+	}
+
+	c.balances[sender] = new(big.Int).Sub(balance, signedTx.Cost())
+	c.nonces[sender]++
+	c.sent = append(c.sent, signedTx)
+	return nil
+}
+
+// GetTransactionReceipt 获取交易回执（未设置时返回ethereum.NotFound，与节点行为一致）
+func (c *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetTransactionReceipt]; err != nil {
+		return nil, err
+	}
+	receipt, ok := c.receipts[normalize(txHash)]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+// GetTransactionByHash 按哈希查询已发送的交易（未设置回执时视为在交易池中，未发送过时返回ethereum.NotFound）
+func (c *Client) GetTransactionByHash(ctx context.Context, txHash string) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetTransactionByHash]; err != nil {
+		return nil, false, err
+	}
+	hash := normalize(txHash)
+	for _, tx := range c.sent {
+		if normalize(tx.Hash().Hex()) == hash {
+			_, mined := c.receipts[hash]
+			return tx, !mined, nil
+		}
+	}
+	return nil, false, ethereum.NotFound
+}
+
+// CallContract 执行只读合约调用（返回SetCallResult设置的数据）
+func (c *Client) CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodCallContract]; err != nil {
+		return nil, err
+	}
+	return c.callResults[normalize(to)], nil
+}
+
+// SimulateCall 模拟执行调用（返回SetCallResult设置的数据，可通过FailOn模拟执行回滚）
+func (c *Client) SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodSimulateCall]; err != nil {
+		return nil, err
+	}
+	return c.callResults[normalize(to)], nil
+}
+
+// ReplayTransaction 重新执行已上链的交易（可通过FailOn模拟执行回滚或节点已裁剪状态，否则执行成功且无返回数据）
+func (c *Client) ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodReplayTransaction]; err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// FilterLogs 按区块范围、合约地址与topic筛选AddLog添加的日志
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodFilterLogs]; err != nil {
+		return nil, err
+	}
+
+	var result []types.Log
+	for _, log := range c.logs {
+		if matchLog(&log, query) {
+			result = append(result, log)
+		}
+	}
+	return result, nil
+}
+
+// ResolveName 解析ENS名称（未设置时返回ErrENSNameNotFound）
+func (c *Client) ResolveName(ctx context.Context, name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodResolveName]; err != nil {
+		return "", err
+	}
+	if address, ok := c.ensNames[blockchain.NormalizeENSName(name)]; ok {
+		return address, nil
+	}
+	return "", blockchain.ErrENSNameNotFound
+}
+
+// LookupAddress 反向解析地址（未设置时返回空字符串）
+func (c *Client) LookupAddress(ctx context.Context, address string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodLookupAddress]; err != nil {
+		return "", err
+	}
+	for name, resolved := range c.ensNames {
+		if strings.EqualFold(resolved, address) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// GetBlockNumber 获取最新区块号
+func (c *Client) GetBlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failures[MethodGetBlockNumber]; err != nil {
+		return 0, err
+	}
+	return c.blockNumber, nil
+}
+
+// CreateWallet 创建钱包（真实生成密钥，便于签名校验）
+func (c *Client) CreateWallet() (address string, privateKey *ecdsa.PrivateKey, err error) {
+	c.mu.Lock()
+	failure := c.failures[MethodCreateWallet]
+	c.mu.Unlock()
+	if failure != nil {
+		return "", nil, failure
+	}
+
+	privateKey, err = crypto.GenerateKey()
+	if err != nil {
+		return "", nil, err
+	}
+	return crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), privateKey, nil
+}
+
+// SignTransaction 签名交易（legacy交易为EIP-155，动态费用交易为EIP-1559）
+func (c *Client) SignTransaction(tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
+	c.mu.Lock()
+	failure := c.failures[MethodSignTransaction]
+	c.mu.Unlock()
+	if failure != nil {
+		return nil, failure
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+}
+
+// GetChainID 获取链ID
+func (c *Client) GetChainID() int {
+	return c.chainID
+}
+
+// normalize 地址与哈希统一转为小写作为键
+func normalize(value string) string {
+	return strings.ToLower(value)
+}
+
+// matchLog 判断日志是否满足查询条件（topic位置内为OR，位置间为AND）
+func matchLog(log *types.Log, query ethereum.FilterQuery) bool {
+	if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() {
+		return false
+	}
+	if query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+		return false
+	}
+
+	if len(query.Addresses) > 0 {
+		matched := false
+		for _, address := range query.Addresses {
+			if address == log.Address {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(query.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, options := range query.Topics {
+		if len(options) == 0 {
+			continue
+		}
+		matched := false
+		for _, topic := range options {
+			if topic == log.Topics[i] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertReason 从eth_call或eth_estimateGas返回的错误中提取回滚原因（解码Error(string)与Panic(uint256)），
+// 错误不是执行回滚（网络错误、节点故障等）时返回false；回滚数据无法解码时返回节点的原始错误信息
+func RevertReason(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	// 1. 节点随错误返回的回滚数据
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if reason, ok := DecodeRevertData(dataErr.ErrorData()); ok {
+			return reason, true
+		}
+		return err.Error(), true
+	}
+
+	// 2. 未返回回滚数据的节点只能按错误信息判断
+	if strings.Contains(err.Error(), "execution reverted") {
+		return err.Error(), true
+	}
+	return "", false
+}
+
+// DecodeRevertData 解码回滚数据（十六进制字符串或字节），未知的自定义错误返回false
+func DecodeRevertData(data interface{}) (string, bool) {
+	var raw []byte
+	switch v := data.(type) {
+	case string:
+		decoded, err := hexutil.Decode(v)
+		if err != nil {
+			return "", false
+		}
+		raw = decoded
+	case []byte:
+		raw = v
+	default:
+		return "", false
+	}
+
+	reason, err := abi.UnpackRevert(raw)
+	if err != nil {
+		return "", false
+	}
+	return reason, true
+}
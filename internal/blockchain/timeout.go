@@ -0,0 +1,175 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TimeoutClient 为BlockchainClient的每次RPC调用设置时限，节点无响应时调用返回context.DeadlineExceeded而不是一直等待
+//
+// 广播交易使用更长的sendTimeout；调用方的ctx先到期时以调用方为准。
+type TimeoutClient struct {
+	next        BlockchainClient
+	callTimeout time.Duration
+	sendTimeout time.Duration
+}
+
+// NewTimeoutClient 创建带调用时限的区块链客户端（时限为0表示不限制）
+func NewTimeoutClient(next BlockchainClient, callTimeout, sendTimeout time.Duration) *TimeoutClient {
+	return &TimeoutClient{next: next, callTimeout: callTimeout, sendTimeout: sendTimeout}
+}
+
+// Endpoints 返回底层多节点客户端的各节点状态（单节点客户端返回nil）
+func (c *TimeoutClient) Endpoints() []EndpointStatus {
+	if reporter, ok := c.next.(EndpointReporter); ok {
+		return reporter.Endpoints()
+	}
+	return nil
+}
+
+// withTimeout 为单次调用设置时限
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// GetBalance 查询地址余额
+func (c *TimeoutClient) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetBalance(ctx, address)
+}
+
+// BatchGetBalances 批量查询地址余额
+func (c *TimeoutClient) BatchGetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.BatchGetBalances(ctx, addresses)
+}
+
+// GetNonce 获取地址的nonce
+func (c *TimeoutClient) GetNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetNonce(ctx, address)
+}
+
+// GetConfirmedNonce 获取地址在最新区块中的nonce
+func (c *TimeoutClient) GetConfirmedNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetConfirmedNonce(ctx, address)
+}
+
+// GetGasPrice 获取当前gas价格
+func (c *TimeoutClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetGasPrice(ctx)
+}
+
+// FeeHistory 查询历史费用
+func (c *TimeoutClient) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.FeeHistory(ctx, blockCount, rewardPercentiles)
+}
+
+// EstimateGas 估算gas用量
+func (c *TimeoutClient) EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (uint64, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.EstimateGas(ctx, from, to, value, data)
+}
+
+// SendTransaction 发送交易（使用广播时限）
+func (c *TimeoutClient) SendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	ctx, cancel := withTimeout(ctx, c.sendTimeout)
+	defer cancel()
+	return c.next.SendTransaction(ctx, signedTx)
+}
+
+// GetTransactionReceipt 获取交易回执
+func (c *TimeoutClient) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetTransactionReceipt(ctx, txHash)
+}
+
+// GetTransactionByHash 按哈希查询交易
+func (c *TimeoutClient) GetTransactionByHash(ctx context.Context, txHash string) (*types.Transaction, bool, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetTransactionByHash(ctx, txHash)
+}
+
+// CallContract 执行只读合约调用
+func (c *TimeoutClient) CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.CallContract(ctx, to, data, blockNumber)
+}
+
+// SimulateCall 模拟执行调用
+func (c *TimeoutClient) SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.SimulateCall(ctx, from, to, value, data)
+}
+
+// ReplayTransaction 重新执行已上链的交易
+func (c *TimeoutClient) ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.ReplayTransaction(ctx, txHash, blockNumber)
+}
+
+// FilterLogs 查询事件日志
+func (c *TimeoutClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.FilterLogs(ctx, query)
+}
+
+// ResolveName 解析ENS名称
+func (c *TimeoutClient) ResolveName(ctx context.Context, name string) (string, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.ResolveName(ctx, name)
+}
+
+// LookupAddress 反向解析ENS名称
+func (c *TimeoutClient) LookupAddress(ctx context.Context, address string) (string, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.LookupAddress(ctx, address)
+}
+
+// GetBlockNumber 获取最新区块号
+func (c *TimeoutClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	ctx, cancel := withTimeout(ctx, c.callTimeout)
+	defer cancel()
+	return c.next.GetBlockNumber(ctx)
+}
+
+// CreateWallet 创建钱包（本地操作，不设时限）
+func (c *TimeoutClient) CreateWallet() (string, *ecdsa.PrivateKey, error) {
+	return c.next.CreateWallet()
+}
+
+// SignTransaction 签名交易（本地操作，不设时限）
+func (c *TimeoutClient) SignTransaction(tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
+	return c.next.SignTransaction(tx, privateKey, chainID)
+}
+
+// GetChainID 获取链ID
+func (c *TimeoutClient) GetChainID() int {
+	return c.next.GetChainID()
+}
@@ -0,0 +1,198 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/tracing"
+)
+
+// TracedClient 为BlockchainClient的RPC调用添加链路追踪与调用次数统计
+type TracedClient struct {
+	next BlockchainClient
+}
+
+// NewTracedClient 创建带链路追踪的区块链客户端
+func NewTracedClient(next BlockchainClient) *TracedClient {
+	return &TracedClient{next: next}
+}
+
+// Endpoints 返回底层多节点客户端的各节点状态（单节点客户端返回nil）
+func (c *TracedClient) Endpoints() []EndpointStatus {
+	if reporter, ok := c.next.(EndpointReporter); ok {
+		return reporter.Endpoints()
+	}
+	return nil
+}
+
+// startSpan 创建RPC调用Span并计数
+func (c *TracedClient) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	metrics.RPCCalls.WithLabelValues(method).Inc()
+	attrs = append(attrs,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+		attribute.Int("chain.id", c.next.GetChainID()),
+	)
+	return tracing.StartSpan(ctx, "blockchain."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// GetBalance 查询地址余额
+func (c *TracedClient) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	ctx, span := c.startSpan(ctx, "GetBalance", attribute.String("address", address))
+	balance, err := c.next.GetBalance(ctx, address)
+	tracing.EndSpan(span, err)
+	return balance, err
+}
+
+// BatchGetBalances 批量查询地址余额
+func (c *TracedClient) BatchGetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	ctx, span := c.startSpan(ctx, "BatchGetBalances", attribute.Int("batch_size", len(addresses)))
+	balances, err := c.next.BatchGetBalances(ctx, addresses)
+	tracing.EndSpan(span, err)
+	return balances, err
+}
+
+// GetNonce 获取地址的nonce
+func (c *TracedClient) GetNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, span := c.startSpan(ctx, "GetNonce", attribute.String("address", address))
+	nonce, err := c.next.GetNonce(ctx, address)
+	tracing.EndSpan(span, err)
+	return nonce, err
+}
+
+// GetConfirmedNonce 获取地址在最新区块中的nonce
+func (c *TracedClient) GetConfirmedNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, span := c.startSpan(ctx, "GetConfirmedNonce", attribute.String("address", address))
+	nonce, err := c.next.GetConfirmedNonce(ctx, address)
+	tracing.EndSpan(span, err)
+	return nonce, err
+}
+
+// GetGasPrice 获取当前gas价格
+func (c *TracedClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, span := c.startSpan(ctx, "GetGasPrice")
+	gasPrice, err := c.next.GetGasPrice(ctx)
+	tracing.EndSpan(span, err)
+	return gasPrice, err
+}
+
+// FeeHistory 查询最近区块的基础费用与小费分位数
+func (c *TracedClient) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	ctx, span := c.startSpan(ctx, "FeeHistory", attribute.Int64("block_count", int64(blockCount)))
+	history, err := c.next.FeeHistory(ctx, blockCount, rewardPercentiles)
+	tracing.EndSpan(span, err)
+	return history, err
+}
+
+// EstimateGas 估算gas用量
+func (c *TracedClient) EstimateGas(ctx context.Context, from, to string, value *big.Int, data []byte) (uint64, error) {
+	ctx, span := c.startSpan(ctx, "EstimateGas")
+	gas, err := c.next.EstimateGas(ctx, from, to, value, data)
+	tracing.EndSpan(span, err)
+	return gas, err
+}
+
+// SendTransaction 发送交易
+func (c *TracedClient) SendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	ctx, span := c.startSpan(ctx, "SendTransaction", attribute.String("tx_hash", signedTx.Hash().Hex()))
+	err := c.next.SendTransaction(ctx, signedTx)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// GetTransactionReceipt 获取交易回执
+func (c *TracedClient) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	ctx, span := c.startSpan(ctx, "GetTransactionReceipt", attribute.String("tx_hash", txHash))
+	receipt, err := c.next.GetTransactionReceipt(ctx, txHash)
+	tracing.EndSpan(span, err)
+	return receipt, err
+}
+
+// GetTransactionByHash 按哈希查询交易
+func (c *TracedClient) GetTransactionByHash(ctx context.Context, txHash string) (*types.Transaction, bool, error) {
+	ctx, span := c.startSpan(ctx, "GetTransactionByHash", attribute.String("tx_hash", txHash))
+	tx, isPending, err := c.next.GetTransactionByHash(ctx, txHash)
+	tracing.EndSpan(span, err)
+	return tx, isPending, err
+}
+
+// CallContract 执行只读合约调用
+func (c *TracedClient) CallContract(ctx context.Context, to string, data []byte, blockNumber *big.Int) ([]byte, error) {
+	ctx, span := c.startSpan(ctx, "CallContract", attribute.String("contract", to))
+	result, err := c.next.CallContract(ctx, to, data, blockNumber)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+// SimulateCall 模拟执行调用
+func (c *TracedClient) SimulateCall(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error) {
+	ctx, span := c.startSpan(ctx, "SimulateCall", attribute.String("contract", to))
+	result, err := c.next.SimulateCall(ctx, from, to, value, data)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+// ReplayTransaction 重新执行已上链的交易
+func (c *TracedClient) ReplayTransaction(ctx context.Context, txHash string, blockNumber *big.Int) ([]byte, error) {
+	ctx, span := c.startSpan(ctx, "ReplayTransaction", attribute.String("tx_hash", txHash))
+	result, err := c.next.ReplayTransaction(ctx, txHash, blockNumber)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+// FilterLogs 查询事件日志
+func (c *TracedClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, span := c.startSpan(ctx, "FilterLogs")
+	logs, err := c.next.FilterLogs(ctx, query)
+	tracing.EndSpan(span, err)
+	return logs, err
+}
+
+// ResolveName 解析ENS名称对应的地址
+func (c *TracedClient) ResolveName(ctx context.Context, name string) (string, error) {
+	ctx, span := c.startSpan(ctx, "ResolveName", attribute.String("name", name))
+	address, err := c.next.ResolveName(ctx, name)
+	tracing.EndSpan(span, err)
+	return address, err
+}
+
+// LookupAddress 反向解析地址的ENS主名称
+func (c *TracedClient) LookupAddress(ctx context.Context, address string) (string, error) {
+	ctx, span := c.startSpan(ctx, "LookupAddress", attribute.String("address", address))
+	name, err := c.next.LookupAddress(ctx, address)
+	tracing.EndSpan(span, err)
+	return name, err
+}
+
+// GetBlockNumber 获取最新区块号
+func (c *TracedClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	ctx, span := c.startSpan(ctx, "GetBlockNumber")
+	number, err := c.next.GetBlockNumber(ctx)
+	tracing.EndSpan(span, err)
+	return number, err
+}
+
+// CreateWallet 创建钱包（本地操作，不追踪）
+func (c *TracedClient) CreateWallet() (string, *ecdsa.PrivateKey, error) {
+	return c.next.CreateWallet()
+}
+
+// SignTransaction 签名交易（本地操作，不追踪）
+func (c *TracedClient) SignTransaction(tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
+	return c.next.SignTransaction(tx, privateKey, chainID)
+}
+
+// GetChainID 获取链ID
+func (c *TracedClient) GetChainID() int {
+	return c.next.GetChainID()
+}
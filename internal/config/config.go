@@ -1,31 +1,81 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/keys"
 )
 
 // Config 全局配置结构
 type Config struct {
-	Server     ServerConfig     `mapstructure:"server"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	Redis      RedisConfig      `mapstructure:"redis"`
-	RabbitMQ   RabbitMQConfig   `mapstructure:"rabbitmq"`
-	JWT        JWTConfig        `mapstructure:"jwt"`
-	Blockchain BlockchainConfig `mapstructure:"blockchain"`
-	Log        LogConfig        `mapstructure:"log"`
-	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Server         ServerConfig         `mapstructure:"server"`
+	GRPC           GRPCConfig           `mapstructure:"grpc"`
+	Database       DatabaseConfig       `mapstructure:"database"`
+	Redis          RedisConfig          `mapstructure:"redis"`
+	RabbitMQ       RabbitMQConfig       `mapstructure:"rabbitmq"`
+	Worker         WorkerConfig         `mapstructure:"worker"`
+	JWT            JWTConfig            `mapstructure:"jwt"`
+	PasswordPolicy PasswordPolicyConfig `mapstructure:"password_policy"`
+	Blockchain     BlockchainConfig     `mapstructure:"blockchain"`
+	Log            LogConfig            `mapstructure:"log"`
+	RateLimit      RateLimitConfig      `mapstructure:"rate_limit"`
+	WebSocket      WebSocketConfig      `mapstructure:"websocket"`
+	Tracing        TracingConfig        `mapstructure:"tracing"`
+	Pricing        PricingConfig        `mapstructure:"pricing"`
+	Whitelist      WhitelistConfig      `mapstructure:"whitelist"`
+	Approval       ApprovalConfig       `mapstructure:"approval"`
+	Outbox         OutboxConfig         `mapstructure:"outbox"`
+	Cache          CacheConfig          `mapstructure:"cache"`
+	Recurring      RecurringConfig      `mapstructure:"recurring"`
+	Monitor        MonitorConfig        `mapstructure:"monitor"`
+	Tokens         TokensConfig         `mapstructure:"tokens"`
+	Metrics        MetricsConfig        `mapstructure:"metrics"`
+	KeyCache       KeyCacheConfig       `mapstructure:"key_cache"`
+	Notifications  NotificationsConfig  `mapstructure:"notifications"`
+	Mail           MailConfig           `mapstructure:"mail"`
+	BalanceRefresh BalanceRefreshConfig `mapstructure:"balance_refresh"`
+	BalanceHistory BalanceHistoryConfig `mapstructure:"balance_history"`
+	ENS            ENSConfig            `mapstructure:"ens"`
+	FeatureFlags   FeatureFlagsConfig   `mapstructure:"feature_flags"`
+	Keys           KeysConfig           `mapstructure:"keys"`
+	GasOracle      GasOracleConfig      `mapstructure:"gas_oracle"`
+	Reconciliation ReconciliationConfig `mapstructure:"reconciliation"`
+	Jobs           JobsConfig           `mapstructure:"jobs"`
+	Wallets        WalletsConfig        `mapstructure:"wallets"`
+	Exports        ExportsConfig        `mapstructure:"exports"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	Mode         string        `mapstructure:"mode"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	Host            string        `mapstructure:"host"`
+	Port            int           `mapstructure:"port"`
+	Mode            string        `mapstructure:"mode"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"` // 优雅关闭时每个阶段的最长等待时间（先等待进行中的请求，再等待后台任务）
+	Gzip            bool          `mapstructure:"gzip"`             // 客户端支持时以gzip压缩响应
+	GzipMinSize     int           `mapstructure:"gzip_min_size"`    // 响应体达到该字节数才压缩（小响应压缩收益低于开销）
+	RequestTimeout  time.Duration `mapstructure:"request_timeout"`  // 普通API请求的处理时限，到期后仍在等待的下游调用返回504（0表示不限制）
+	SendTimeout     time.Duration `mapstructure:"send_timeout"`     // 发送交易类请求的处理时限（广播交易需要更长时间）
+}
+
+// GRPCConfig gRPC服务配置（与HTTP服务在同一进程中启动，监听server.host上的独立端口）
+type GRPCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Port    int  `mapstructure:"port"`
 }
 
 // DatabaseConfig 数据库配置
@@ -39,25 +89,58 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	AutoMigrate     bool          `mapstructure:"auto_migrate"`   // 启动时按模型自动建表（仅开发环境，生产使用cmd/migrate）
+	LogLevel        string        `mapstructure:"log_level"`      // SQL日志级别：silent、error、warn、info
+	SlowThreshold   time.Duration `mapstructure:"slow_threshold"` // 慢查询阈值，超过时以Warn级别记录，0表示不记录
+	Replica         ReplicaConfig `mapstructure:"replica"`        // 只读副本（可选）
+}
+
+// ReplicaConfig 只读副本配置：列表、统计、导出等只读查询在复制延迟不超过max_lag时读副本，否则读主库；
+// host为空时不启用，全部查询使用主库。user、password、dbname、sslmode与连接池参数未设置时沿用主库配置
+type ReplicaConfig struct {
+	Host          string        `mapstructure:"host"`
+	Port          int           `mapstructure:"port"`
+	User          string        `mapstructure:"user"`
+	Password      string        `mapstructure:"password"`
+	DBName        string        `mapstructure:"dbname"`
+	SSLMode       string        `mapstructure:"sslmode"`
+	MaxOpenConns  int           `mapstructure:"max_open_conns"`
+	MaxIdleConns  int           `mapstructure:"max_idle_conns"`
+	MaxLag        time.Duration `mapstructure:"max_lag"`        // 复制延迟容忍度，超过时只读查询回退到主库
+	CheckInterval time.Duration `mapstructure:"check_interval"` // 检查复制延迟的间隔
 }
 
 // RedisConfig Redis配置
 type RedisConfig struct {
-	Host         string `mapstructure:"host"`
-	Port         int    `mapstructure:"port"`
-	Password     string `mapstructure:"password"`
-	DB           int    `mapstructure:"db"`
-	PoolSize     int    `mapstructure:"pool_size"`
-	MinIdleConns int    `mapstructure:"min_idle_conns"`
+	Host         string        `mapstructure:"host"`
+	Port         int           `mapstructure:"port"`
+	Password     string        `mapstructure:"password"`
+	DB           int           `mapstructure:"db"`
+	PoolSize     int           `mapstructure:"pool_size"`
+	MinIdleConns int           `mapstructure:"min_idle_conns"`
+	OpTimeout    time.Duration `mapstructure:"op_timeout"` // 单次命令的时限（0表示只受调用方ctx与连接读写超时限制）
 }
 
 // RabbitMQConfig RabbitMQ配置
 type RabbitMQConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	VHost    string `mapstructure:"vhost"`
+	Host           string        `mapstructure:"host"`
+	Port           int           `mapstructure:"port"`
+	User           string        `mapstructure:"user"`
+	Password       string        `mapstructure:"password"`
+	VHost          string        `mapstructure:"vhost"`
+	PublishTimeout time.Duration `mapstructure:"publish_timeout"`  // 发布消息等待重连与服务端确认的最长时间
+	MaxRetries     int           `mapstructure:"max_retries"`      // 消息处理失败的最大重试次数
+	RetryBaseDelay time.Duration `mapstructure:"retry_base_delay"` // 首次重试延迟（指数递增）
+	DrainTimeout   time.Duration `mapstructure:"drain_timeout"`    // Worker关闭时等待处理中消息完成的最长时间
+}
+
+// WorkerConfig 队列消费配置
+//
+// 每条消息由处理它的协程确认；处理失败的消息按rabbitmq.max_retries转入延迟重试或死信队列，
+// 与并发度无关，重试消息回到主队列后由任一空闲协程处理，因此同一消息的多次重试不保证顺序。
+type WorkerConfig struct {
+	Prefetch    int `mapstructure:"prefetch"`    // 服务端推送但未确认的最大消息数（QoS），应不小于concurrency
+	Concurrency int `mapstructure:"concurrency"` // 同时运行的消息处理协程数
 }
 
 // JWTConfig JWT配置
@@ -66,16 +149,206 @@ type JWTConfig struct {
 	ExpireHours int    `mapstructure:"expire_hours"`
 }
 
+// PasswordPolicyConfig 密码复杂度策略（修改密码时校验）
+type PasswordPolicyConfig struct {
+	MinLength     int  `mapstructure:"min_length"`     // 最小长度
+	RequireUpper  bool `mapstructure:"require_upper"`  // 必须包含大写字母
+	RequireLower  bool `mapstructure:"require_lower"`  // 必须包含小写字母
+	RequireDigit  bool `mapstructure:"require_digit"`  // 必须包含数字
+	RequireSymbol bool `mapstructure:"require_symbol"` // 必须包含标点或符号
+}
+
+// Policy 转换为服务层使用的密码策略
+func (c PasswordPolicyConfig) Policy() models.PasswordPolicy {
+	return models.PasswordPolicy{
+		MinLength:     c.MinLength,
+		RequireUpper:  c.RequireUpper,
+		RequireLower:  c.RequireLower,
+		RequireDigit:  c.RequireDigit,
+		RequireSymbol: c.RequireSymbol,
+	}
+}
+
 // BlockchainConfig 区块链配置
 type BlockchainConfig struct {
-	Ethereum ChainConfig `mapstructure:"ethereum"`
-	BSC      ChainConfig `mapstructure:"bsc"`
+	Chains        []ChainConfig `mapstructure:"chains"`         // 支持的链（配置了rpc_url的链各连接一组节点，未配置的链只用于历史记录的链名称，不能启用）
+	AllowedChains []int         `mapstructure:"allowed_chains"` // 允许创建钱包与发送交易的链ID，为空表示chains中的全部链
+	DeniedChains  []int         `mapstructure:"denied_chains"`  // 禁止创建钱包与发送交易的链ID（优先于allowed_chains）
+	RPCTimeout    time.Duration `mapstructure:"rpc_timeout"`    // 单次RPC调用（查询）的时限
+	SendTimeout   time.Duration `mapstructure:"send_timeout"`   // 广播交易的时限（不随请求取消）
+}
+
+// Connected 配置了节点的链（按配置顺序，每条链一个客户端）
+func (c BlockchainConfig) Connected() []ChainConfig {
+	var chains []ChainConfig
+	for _, chain := range c.Chains {
+		if chain.Connected() {
+			chains = append(chains, chain)
+		}
+	}
+	return chains
+}
+
+// Metadata 所有链的元数据（按部署策略标记是否启用）
+func (c BlockchainConfig) Metadata() []models.Chain {
+	chains := make([]models.Chain, len(c.Chains))
+	for i, chain := range c.Chains {
+		chains[i] = chain.Metadata()
+		chains[i].Enabled = c.ChainEnabled(chain.ChainID)
+	}
+	return chains
+}
+
+// ChainEnabled 链是否允许创建钱包与发送交易：配置了节点、在allowed_chains中（为空时不限制）且不在denied_chains中
+func (c BlockchainConfig) ChainEnabled(chainID int) bool {
+	idx := slices.IndexFunc(c.Chains, func(chain ChainConfig) bool { return chain.ChainID == chainID })
+	if idx < 0 || !c.Chains[idx].Connected() || slices.Contains(c.DeniedChains, chainID) {
+		return false
+	}
+	return len(c.AllowedChains) == 0 || slices.Contains(c.AllowedChains, chainID)
+}
+
+// Params 所有链的交易构建参数
+func (c BlockchainConfig) Params() []blockchain.ChainParams {
+	params := make([]blockchain.ChainParams, len(c.Chains))
+	for i, chain := range c.Chains {
+		params[i] = chain.Params()
+	}
+	return params
+}
+
+// Faucets 启用了测试水龙头的链（口令从passphrase_env指定的环境变量读取）
+func (c BlockchainConfig) Faucets() []models.Faucet {
+	var faucets []models.Faucet
+	for _, chain := range c.Chains {
+		if chain.Faucet == nil {
+			continue
+		}
+		faucets = append(faucets, models.Faucet{
+			ChainID:    chain.ChainID,
+			Address:    chain.Faucet.Address,
+			Passphrase: os.Getenv(chain.Faucet.PassphraseEnv),
+			AmountWei:  chain.Faucet.AmountWei,
+			DailyLimit: chain.Faucet.DailyLimit,
+		})
+	}
+	return faucets
+}
+
+// applyDefaults 填充列表中每条链未配置的可选项（viper的默认值无法作用于列表元素）
+func (c *BlockchainConfig) applyDefaults() {
+	for i := range c.Chains {
+		chain := &c.Chains[i]
+		if chain.NativeSymbol == "" {
+			chain.NativeSymbol = "ETH"
+		}
+		if chain.Decimals == 0 {
+			chain.Decimals = 18
+		}
+		if chain.Strategy == "" {
+			chain.Strategy = "primary"
+		}
+		if chain.HealthCheckInterval == 0 {
+			chain.HealthCheckInterval = 15 * time.Second
+		}
+		if chain.Confirmations == 0 {
+			chain.Confirmations = 12
+		}
+	}
 }
 
 // ChainConfig 链配置
 type ChainConfig struct {
-	RPCURL  string `mapstructure:"rpc_url"`
-	ChainID int    `mapstructure:"chain_id"`
+	Name                string        `mapstructure:"name"`                  // 链名称（响应中的chain_name）
+	ChainID             int           `mapstructure:"chain_id"`              // 链ID（不可重复）
+	NativeSymbol        string        `mapstructure:"native_symbol"`         // 原生币符号，默认ETH
+	Decimals            int           `mapstructure:"decimals"`              // 原生币精度，默认18
+	ExplorerURL         string        `mapstructure:"explorer_url"`          // 区块浏览器地址（响应中的链接按<explorer_url>/tx/<hash>、/address/<address>生成），可为空
+	RPCURLs             []string      `mapstructure:"rpc_url"`               // 单个地址或地址列表，为空时不连接该链（链不能启用）
+	WSURL               string        `mapstructure:"ws_url"`                // websocket地址，配置后Worker通过新区块订阅监听交易回执
+	Strategy            string        `mapstructure:"strategy"`              // primary, round_robin
+	MaxBlockLag         uint64        `mapstructure:"max_block_lag"`         // 落后最高节点超过该区块数时降级，0表示不检查
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"` // 节点健康检查间隔
+	Confirmations       uint64        `mapstructure:"confirmations"`         // 交易视为最终确认所需的区块数（含交易所在区块）
+	PollInterval        time.Duration `mapstructure:"poll_interval"`         // 交易确认轮询间隔，0表示取monitor.poll_interval与出块时间中较短者
+	StaleAfter          time.Duration `mapstructure:"stale_after"`           // 最新区块号超过该时长未推进时视为节点不健康（拒绝发送交易），0表示10个出块时间
+
+	// 交易构建参数（未配置时使用链的默认值，见blockchain.DefaultChainParams）
+	EIP1559        *bool         `mapstructure:"eip1559"`          // 是否按EIP-1559费用市场定价并发送动态费用交易（BSC默认关闭，只按eth_gasPrice定价并发送legacy交易）
+	BlockTime      time.Duration `mapstructure:"block_time"`       // 平均出块时间
+	MinGasPrice    uint64        `mapstructure:"min_gas_price"`    // gas价格下限（Wei）
+	NativeGasLimit uint64        `mapstructure:"native_gas_limit"` // 原生币转账的默认gas用量
+	TokenGasLimit  uint64        `mapstructure:"token_gas_limit"`  // ERC-20转账的默认gas用量
+
+	Faucet *FaucetConfig `mapstructure:"faucet"` // 测试水龙头（新建钱包时自动领水），为空表示不启用
+}
+
+// FaucetConfig 测试链水龙头配置（只能在blockchain.IsTestnet白名单中的链上启用，主网链ID无法通过配置校验）
+type FaucetConfig struct {
+	Address       string `mapstructure:"address"`        // 水龙头钱包地址（需为系统中已有的托管钱包）
+	PassphraseEnv string `mapstructure:"passphrase_env"` // 保存水龙头钱包口令的环境变量名（钱包未设置口令时留空）
+	AmountWei     string `mapstructure:"amount_wei"`     // 每个新钱包领取的金额（Wei）
+	DailyLimit    int    `mapstructure:"daily_limit"`    // 每个用户每天可领取的次数
+}
+
+// Connected 是否配置了节点（未配置的链不创建客户端，钱包与交易不能使用该链）
+func (c ChainConfig) Connected() bool {
+	return len(c.RPCURLs) > 0
+}
+
+// Params 合并链的默认参数与配置中的覆盖值
+func (c ChainConfig) Params() blockchain.ChainParams {
+	params := blockchain.DefaultChainParams(c.ChainID)
+	if c.EIP1559 != nil {
+		params.LegacyGas = !*c.EIP1559
+	}
+	if c.BlockTime > 0 {
+		params.BlockTime = c.BlockTime
+	}
+	if c.MinGasPrice > 0 {
+		params.MinGasPrice = new(big.Int).SetUint64(c.MinGasPrice)
+	}
+	if c.NativeGasLimit > 0 {
+		params.NativeGasLimit = c.NativeGasLimit
+	}
+	if c.TokenGasLimit > 0 {
+		params.TokenGasLimit = c.TokenGasLimit
+	}
+	if c.Confirmations > 0 {
+		params.Confirmations = c.Confirmations
+	}
+	return params
+}
+
+// Metadata 链的元数据（注册到models后用于响应中的链名称与chain_id校验）
+func (c ChainConfig) Metadata() models.Chain {
+	return models.Chain{
+		ChainID:      c.ChainID,
+		Name:         c.Name,
+		NativeSymbol: c.NativeSymbol,
+		Decimals:     c.Decimals,
+		ExplorerURL:  c.ExplorerURL,
+		EIP1559:      !c.Params().LegacyGas,
+	}
+}
+
+// MonitorInterval 交易确认轮询间隔（未单独配置时使用全局间隔，链的出块时间更短时按出块时间轮询）
+func (c ChainConfig) MonitorInterval(fallback time.Duration) time.Duration {
+	if c.PollInterval > 0 {
+		return c.PollInterval
+	}
+	if blockTime := c.Params().BlockTime; blockTime > 0 && blockTime < fallback {
+		return blockTime
+	}
+	return fallback
+}
+
+// HeadStaleAfter 链头未推进多久后视为节点不健康
+func (c ChainConfig) HeadStaleAfter() time.Duration {
+	if c.StaleAfter > 0 {
+		return c.StaleAfter
+	}
+	return 10 * c.Params().BlockTime
 }
 
 // LogConfig 日志配置
@@ -90,17 +363,234 @@ type LogConfig struct {
 
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
+	RequestsPerSecond float64         `mapstructure:"requests_per_second"`
+	Burst             int             `mapstructure:"burst"`
+	Expensive         RateLimitBucket `mapstructure:"expensive"` // 调用链节点等外部系统的路由（Gas价格、强制刷新余额、代币余额、合约调用、交易模拟、导入钱包）
+	Export            RateLimitBucket `mapstructure:"export"`    // 产生重查询的导出路由与私钥keystore备份导出（scrypt开销大）
+}
+
+// RateLimitBucket 按路由组的限流配置（每个用户独立计数，在全局限流之外生效）
+type RateLimitBucket struct {
 	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
 	Burst             int     `mapstructure:"burst"`
 }
 
-// Load 加载配置文件
+// WebSocketConfig WebSocket配置
+type WebSocketConfig struct {
+	MaxSubscriptions int           `mapstructure:"max_subscriptions"` // 单连接最大订阅地址数
+	AuthTimeout      time.Duration `mapstructure:"auth_timeout"`      // 首条消息认证超时
+	PingInterval     time.Duration `mapstructure:"ping_interval"`     // 心跳间隔
+}
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	Endpoint    string  `mapstructure:"endpoint"`    // OTLP HTTP端点（host:port）
+	Insecure    bool    `mapstructure:"insecure"`    // 不使用TLS
+	SampleRate  float64 `mapstructure:"sample_rate"` // 采样率 0~1
+}
+
+// PricingConfig 法币价格配置（CoinGecko）
+type PricingConfig struct {
+	BaseURL string        `mapstructure:"base_url"`
+	APIKey  string        `mapstructure:"api_key"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// WhitelistConfig 转账白名单配置
+type WhitelistConfig struct {
+	CoolingOffPeriod time.Duration `mapstructure:"cooling_off_period"` // 新增地址生效前的冷静期
+}
+
+// ApprovalConfig 大额转账审批配置
+type ApprovalConfig struct {
+	TTL time.Duration `mapstructure:"ttl"` // 待审批交易的有效期，过期后不再广播
+}
+
+// OutboxConfig 发件箱分发配置
+type OutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 轮询未投递事件的间隔
+	BatchSize    int           `mapstructure:"batch_size"`    // 每批处理的事件数
+	Retention    time.Duration `mapstructure:"retention"`     // 已投递事件的保留时长
+}
+
+// CacheConfig 缓存配置（过期时间支持热加载，本地缓存层修改后需重启）
+type CacheConfig struct {
+	BalanceTTL time.Duration `mapstructure:"balance_ttl"` // 钱包余额
+	PriceTTL   time.Duration `mapstructure:"price_ttl"`   // 法币价格
+	StatsTTL   time.Duration `mapstructure:"stats_ttl"`   // 交易统计
+	LocalSize  int           `mapstructure:"local_size"`  // 进程内缓存的最大键数量（0表示不启用本地缓存层）
+	LocalTTL   time.Duration `mapstructure:"local_ttl"`   // 本地条目的最长有效期（多副本间的最大不一致窗口）
+}
+
+// RecurringConfig 定期转账执行配置
+type RecurringConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 扫描到期计划的间隔
+	BatchSize    int           `mapstructure:"batch_size"`    // 每次扫描执行的计划数
+	MaxFailures  int           `mapstructure:"max_failures"`  // 连续失败达到该次数后暂停计划
+}
+
+// MonitorConfig 交易确认轮询配置（新区块订阅不可用时由Worker使用）
+type MonitorConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // 批量检查到期交易的间隔
+	Concurrency    int           `mapstructure:"concurrency"`     // 同时查询回执的最大数量
+	BatchSize      int           `mapstructure:"batch_size"`      // 每轮最多检查的到期交易数
+	FreshAge       time.Duration `mapstructure:"fresh_age"`       // 创建未超过该时长的交易按fresh_interval检查
+	FreshInterval  time.Duration `mapstructure:"fresh_interval"`  // 新交易（及已打包等待确认深度的交易）的检查间隔
+	RecentAge      time.Duration `mapstructure:"recent_age"`      // 创建未超过该时长的交易按recent_interval检查
+	RecentInterval time.Duration `mapstructure:"recent_interval"` // 较新交易的检查间隔
+	StaleInterval  time.Duration `mapstructure:"stale_interval"`  // 更早交易的检查间隔
+	StuckAfter     time.Duration `mapstructure:"stuck_after"`     // 创建超过该时长仍未打包的交易视为卡住：nonce已被其他交易使用时标记为dropped，否则提醒用户加速或取消
+	UnknownGrace   time.Duration `mapstructure:"unknown_grace"`   // 创建超过该时长后节点仍不知道的交易（从未传播或已被移出交易池）标记为dropped
+}
+
+// WalletsConfig 钱包配置
+type WalletsConfig struct {
+	MaxPerUser      int `mapstructure:"max_per_user"`     // 每个用户最多拥有的钱包数（创建、批量创建与导入时检查，0表示不限制）
+	BulkConcurrency int `mapstructure:"bulk_concurrency"` // 批量创建钱包时同时生成与加密私钥的协程数
+}
+
+// ExportsConfig 异步导出配置（Worker将文件生成到目录，API凭一次性下载令牌提供下载）
+type ExportsConfig struct {
+	Dir             string        `mapstructure:"dir"`              // 导出文件目录（API与Worker需能访问同一目录，如挂载同一存储卷）
+	FileTTL         time.Duration `mapstructure:"file_ttl"`         // 文件保留时间，过期后删除
+	TokenTTL        time.Duration `mapstructure:"token_ttl"`        // 下载令牌有效期
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // Worker清理过期文件的间隔
+}
+
+// KeyCacheConfig 私钥内存缓存配置（仅缓存在进程内，不写入Redis）
+type KeyCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`  // 是否启用
+	TTL     time.Duration `mapstructure:"ttl"`      // 私钥在内存中保留的时间
+	MaxSize int           `mapstructure:"max_size"` // 最多缓存的钱包数
+}
+
+// NotificationsConfig 站外通知配置
+type NotificationsConfig struct {
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"` // 回调用户webhook的超时时间
+}
+
+// MailConfig 外发邮件配置（通知邮件发布到email.send队列，由Worker渲染模板后发送）
+type MailConfig struct {
+	Driver   string        `mapstructure:"driver"`   // smtp或log（只记录日志不发送，用于开发环境）
+	Host     string        `mapstructure:"host"`     // SMTP服务器地址
+	Port     int           `mapstructure:"port"`     // SMTP端口（starttls一般为587，tls为465）
+	Username string        `mapstructure:"username"` // 为空时不认证
+	Password string        `mapstructure:"password"`
+	From     string        `mapstructure:"from"`     // 发件人，可带显示名称
+	TLSMode  string        `mapstructure:"tls_mode"` // starttls、tls或none
+	Timeout  time.Duration `mapstructure:"timeout"`  // 单封邮件从建立连接到发送完成的超时时间
+}
+
+// BalanceRefreshConfig 后台余额刷新配置（创建钱包、交易确认后异步查询链上余额）
+type BalanceRefreshConfig struct {
+	Workers       int           `mapstructure:"workers"`        // 同时进行的余额查询数
+	BatchSize     int           `mapstructure:"batch_size"`     // 单次JSON-RPC批量请求的最大地址数，1表示逐个查询
+	RetryInterval time.Duration `mapstructure:"retry_interval"` // Worker重试交易确认后未完成的余额刷新的间隔（同时是标记后等待提交后刷新的宽限时间）
+}
+
+// JobsConfig 后台任务队列配置（请求处理中提交的异步任务，如数据库余额更新；进程关闭时执行完已提交的任务）
+type JobsConfig struct {
+	Workers   int `mapstructure:"workers"`    // 同时执行的任务数
+	QueueSize int `mapstructure:"queue_size"` // 等待执行的最大任务数，队列满时新任务被丢弃
+}
+
+// BalanceHistoryConfig 余额快照配置（由Worker定时执行）
+type BalanceHistoryConfig struct {
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval"` // 为所有钱包记录天快照的间隔
+	HourlyRetention  time.Duration `mapstructure:"hourly_retention"`  // 小时快照保留时长，之后降采样为天快照
+}
+
+// ENSConfig ENS名称解析配置（仅在部署了ENS的链上生效）
+type ENSConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`        // 是否允许以ENS名称指定收款方
+	CacheTTL      time.Duration `mapstructure:"cache_ttl"`      // 解析结果缓存时间
+	ReverseLookup bool          `mapstructure:"reverse_lookup"` // 是否在交易列表中反向解析转入交易发送方的主名称
+}
+
+// FeatureFlagsConfig 功能开关配置（开关状态保存在Redis中，通过管理接口修改）
+type FeatureFlagsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 进程内缓存时间，修改后其他实例在此时间内生效
+}
+
+// GasOracleConfig Gas价格预言机配置（Worker定时计算三档价格写入Redis）
+type GasOracleConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 刷新间隔
+	BlockCount      uint64        `mapstructure:"block_count"`      // eth_feeHistory采样的区块数
+	CacheTTL        time.Duration `mapstructure:"cache_ttl"`        // 缓存有效期（Worker停止后过期，改为实时计算）
+}
+
+// ReconciliationConfig 余额对账配置（Worker定时比较数据库余额与链上余额并修正）
+type ReconciliationConfig struct {
+	Interval     time.Duration `mapstructure:"interval"`      // 对账间隔（距上一次对账不足该间隔时Worker重启后不会立即执行）
+	BatchSize    int           `mapstructure:"batch_size"`    // 每批钱包数（单次JSON-RPC批量请求）
+	ThresholdWei string        `mapstructure:"threshold_wei"` // 差异超过该值（Wei）时记录到对账报告，所有差异都会被修正
+}
+
+// KeysConfig 加密密钥（十六进制编码的32字节密钥，生产环境通过CWA_KEYS_*环境变量注入）
+type KeysConfig struct {
+	WalletEncryption string `mapstructure:"wallet_encryption"` // 钱包私钥加密密钥
+	PIIEncryption    string `mapstructure:"pii_encryption"`    // 用户邮箱等敏感信息加密密钥
+	EmailHMAC        string `mapstructure:"email_hmac"`        // 邮箱检索摘要密钥（必须与加密密钥不同）
+	QueueEncryption  string `mapstructure:"queue_encryption"`  // 队列消息加密密钥（可选，为空时消息不加密）
+}
+
+// NewProvider 创建密钥提供者（未配置的可选密钥不加载）
+func (c KeysConfig) NewProvider() (*keys.StaticProvider, error) {
+	hexKeys := map[string]string{
+		keys.WalletEncryption: c.WalletEncryption,
+		keys.PIIEncryption:    c.PIIEncryption,
+		keys.EmailHMAC:        c.EmailHMAC,
+	}
+	if c.QueueEncryption != "" {
+		hexKeys[keys.QueueEncryption] = c.QueueEncryption
+	}
+	return keys.NewStaticProvider(hexKeys)
+}
+
+// MetricsConfig Prometheus指标配置
+type MetricsConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`     // 是否暴露/metrics
+	WorkerAddr string `mapstructure:"worker_addr"` // Worker指标监听地址（API服务使用自身端口）
+}
+
+// TokensConfig 代币元数据、余额查询与入账扫描配置
+type TokensConfig struct {
+	MetadataTTL         time.Duration `mapstructure:"metadata_ttl"`          // 代币元数据缓存时间
+	BalanceConcurrency  int           `mapstructure:"balance_concurrency"`   // 并发查询代币余额的最大数量
+	DepositPollInterval time.Duration `mapstructure:"deposit_poll_interval"` // 扫描Transfer事件的间隔
+	DepositBlockRange   uint64        `mapstructure:"deposit_block_range"`   // 单次eth_getLogs查询的区块数
+}
+
+// envPrefix 环境变量前缀，如CWA_DATABASE_PASSWORD覆盖database.password
+const envPrefix = "CWA"
+
+// Load 加载配置（配置文件 < 环境变量），并校验必填项
 func Load(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
 
-	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
+	// 环境变量覆盖（key中的"."替换为"_"）
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+	bindEnvs(reflect.TypeOf(Config{}), "")
+	setDefaults()
+
+	config, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	setCurrent(config)
+
+	return config, nil
+}
+
+// readConfig 读取、解析并校验配置
+func readConfig() (*Config, error) {
+	// 读取配置文件（容器部署时可仅使用环境变量）
+	if err := viper.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return nil, fmt.Errorf("failed to read configs file: %w", err)
 	}
 
@@ -109,10 +599,201 @@ func Load(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal configs: %w", err)
 	}
+	config.Blockchain.applyDefaults()
+
+	// 校验配置
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
 
+// bindEnvs 为所有配置项绑定环境变量（AutomaticEnv仅对已知key生效，配置文件中缺失的项需显式绑定）
+func bindEnvs(t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			bindEnvs(field.Type, key)
+			continue
+		}
+		viper.BindEnv(key)
+	}
+}
+
+// setDefaults 可选配置项的默认值
+func setDefaults() {
+	viper.SetDefault("server.host", "0.0.0.0")
+	viper.SetDefault("server.port", 8080)
+	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.read_timeout", 30*time.Second)
+	viper.SetDefault("server.write_timeout", 30*time.Second)
+	viper.SetDefault("server.shutdown_timeout", 10*time.Second)
+	viper.SetDefault("server.gzip", true)
+	viper.SetDefault("server.gzip_min_size", 1024)
+	viper.SetDefault("server.request_timeout", 15*time.Second)
+	viper.SetDefault("server.send_timeout", 25*time.Second)
+
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.port", 9090)
+
+	viper.SetDefault("database.port", 5432)
+	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.max_open_conns", 100)
+	viper.SetDefault("database.max_idle_conns", 10)
+	viper.SetDefault("database.conn_max_lifetime", time.Hour)
+	viper.SetDefault("database.auto_migrate", false)
+	viper.SetDefault("database.log_level", "warn")
+	viper.SetDefault("database.slow_threshold", 200*time.Millisecond)
+	viper.SetDefault("database.replica.port", 5432)
+	viper.SetDefault("database.replica.max_lag", 5*time.Second)
+	viper.SetDefault("database.replica.check_interval", 5*time.Second)
+
+	viper.SetDefault("redis.port", 6379)
+	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("redis.min_idle_conns", 5)
+	viper.SetDefault("redis.op_timeout", time.Second)
+
+	viper.SetDefault("blockchain.rpc_timeout", 5*time.Second)
+	viper.SetDefault("blockchain.send_timeout", 20*time.Second)
+
+	viper.SetDefault("rabbitmq.port", 5672)
+	viper.SetDefault("rabbitmq.vhost", "/")
+	viper.SetDefault("rabbitmq.publish_timeout", 5*time.Second)
+	viper.SetDefault("rabbitmq.max_retries", 5)
+	viper.SetDefault("rabbitmq.retry_base_delay", 5*time.Second)
+	viper.SetDefault("rabbitmq.drain_timeout", 30*time.Second)
+
+	viper.SetDefault("worker.prefetch", 8)
+	viper.SetDefault("worker.concurrency", 4)
+
+	viper.SetDefault("jwt.expire_hours", 24)
+
+	viper.SetDefault("password_policy.min_length", 10)
+	viper.SetDefault("password_policy.require_upper", true)
+	viper.SetDefault("password_policy.require_lower", true)
+	viper.SetDefault("password_policy.require_digit", true)
+	viper.SetDefault("password_policy.require_symbol", false)
+
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.output", "stdout")
+	viper.SetDefault("log.file_path", "./logs/app.log")
+	viper.SetDefault("log.max_size", 100)
+	viper.SetDefault("log.max_backups", 3)
+	viper.SetDefault("log.max_age", 7)
+
+	viper.SetDefault("rate_limit.requests_per_second", 100)
+	viper.SetDefault("rate_limit.burst", 200)
+	viper.SetDefault("rate_limit.expensive.requests_per_second", 2)
+	viper.SetDefault("rate_limit.expensive.burst", 10)
+	viper.SetDefault("rate_limit.export.requests_per_second", 0.2)
+	viper.SetDefault("rate_limit.export.burst", 2)
+
+	viper.SetDefault("websocket.max_subscriptions", 20)
+	viper.SetDefault("websocket.auth_timeout", 10*time.Second)
+	viper.SetDefault("websocket.ping_interval", 30*time.Second)
+
+	viper.SetDefault("tracing.service_name", "crypto-wallet-api")
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.sample_rate", 0.1)
+
+	viper.SetDefault("pricing.base_url", "https://api.coingecko.com/api/v3")
+	viper.SetDefault("pricing.timeout", 3*time.Second)
+
+	viper.SetDefault("whitelist.cooling_off_period", 24*time.Hour)
+
+	viper.SetDefault("approval.ttl", 72*time.Hour)
+
+	viper.SetDefault("outbox.poll_interval", time.Second)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.retention", 7*24*time.Hour)
+
+	viper.SetDefault("cache.balance_ttl", 30*time.Second)
+	viper.SetDefault("cache.price_ttl", time.Minute)
+	viper.SetDefault("cache.stats_ttl", 5*time.Minute)
+	viper.SetDefault("cache.local_size", 0)
+	viper.SetDefault("cache.local_ttl", 5*time.Second)
+
+	viper.SetDefault("recurring.poll_interval", 30*time.Second)
+	viper.SetDefault("recurring.batch_size", 50)
+	viper.SetDefault("recurring.max_failures", 3)
+
+	viper.SetDefault("monitor.poll_interval", 5*time.Second)
+	viper.SetDefault("monitor.concurrency", 10)
+	viper.SetDefault("monitor.batch_size", 200)
+	viper.SetDefault("monitor.fresh_age", 2*time.Minute)
+	viper.SetDefault("monitor.fresh_interval", 10*time.Second)
+	viper.SetDefault("monitor.recent_age", 30*time.Minute)
+	viper.SetDefault("monitor.recent_interval", time.Minute)
+	viper.SetDefault("monitor.stale_interval", 10*time.Minute)
+	viper.SetDefault("monitor.stuck_after", 2*time.Hour)
+	viper.SetDefault("monitor.unknown_grace", 30*time.Minute)
+
+	viper.SetDefault("tokens.metadata_ttl", 24*time.Hour)
+	viper.SetDefault("tokens.balance_concurrency", 8)
+	viper.SetDefault("tokens.deposit_poll_interval", 15*time.Second)
+	viper.SetDefault("tokens.deposit_block_range", 500)
+
+	// 钱包默认值
+	viper.SetDefault("wallets.max_per_user", 10000)
+	viper.SetDefault("wallets.bulk_concurrency", 8)
+
+	// 异步导出默认值
+	viper.SetDefault("exports.dir", filepath.Join(os.TempDir(), "crypto-wallet-exports"))
+	viper.SetDefault("exports.file_ttl", 24*time.Hour)
+	viper.SetDefault("exports.token_ttl", 5*time.Minute)
+	viper.SetDefault("exports.cleanup_interval", 10*time.Minute)
+
+	// 私钥缓存默认值
+	viper.SetDefault("key_cache.enabled", false)
+	viper.SetDefault("key_cache.ttl", 30*time.Second)
+	viper.SetDefault("key_cache.max_size", 100)
+	viper.SetDefault("notifications.webhook_timeout", 5*time.Second)
+
+	// 外发邮件默认值
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.port", 587)
+	viper.SetDefault("mail.tls_mode", "starttls")
+	viper.SetDefault("mail.timeout", 10*time.Second)
+
+	viper.SetDefault("balance_refresh.workers", 4)
+	viper.SetDefault("balance_refresh.batch_size", 20)
+	viper.SetDefault("balance_refresh.retry_interval", time.Minute)
+
+	// 后台任务队列默认值
+	viper.SetDefault("jobs.workers", 4)
+	viper.SetDefault("jobs.queue_size", 1000)
+
+	viper.SetDefault("balance_history.snapshot_interval", 24*time.Hour)
+	viper.SetDefault("balance_history.hourly_retention", 7*24*time.Hour)
+
+	viper.SetDefault("ens.enabled", true)
+	viper.SetDefault("ens.cache_ttl", 5*time.Minute)
+	viper.SetDefault("ens.reverse_lookup", false)
+
+	viper.SetDefault("feature_flags.refresh_interval", 5*time.Second)
+
+	viper.SetDefault("gas_oracle.refresh_interval", 15*time.Second)
+	viper.SetDefault("gas_oracle.block_count", 20)
+	viper.SetDefault("gas_oracle.cache_ttl", time.Minute)
+
+	viper.SetDefault("reconciliation.interval", 24*time.Hour)
+	viper.SetDefault("reconciliation.batch_size", 100)
+	viper.SetDefault("reconciliation.threshold_wei", "0")
+
+	// Metrics默认值
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.worker_addr", ":9091")
+}
+
 // GetDSN 获取数据库连接字符串
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf(
@@ -121,6 +802,35 @@ func (c *DatabaseConfig) GetDSN() string {
 	)
 }
 
+// ReplicaEnabled 是否配置了只读副本
+func (c *DatabaseConfig) ReplicaEnabled() bool {
+	return c.Replica.Host != ""
+}
+
+// ReplicaDatabase 只读副本的连接配置（未设置的字段沿用主库配置）
+func (c *DatabaseConfig) ReplicaDatabase() DatabaseConfig {
+	replica := *c
+	replica.Host = c.Replica.Host
+	replica.Port = c.Replica.Port
+	if c.Replica.User != "" {
+		replica.User = c.Replica.User
+		replica.Password = c.Replica.Password
+	}
+	if c.Replica.DBName != "" {
+		replica.DBName = c.Replica.DBName
+	}
+	if c.Replica.SSLMode != "" {
+		replica.SSLMode = c.Replica.SSLMode
+	}
+	if c.Replica.MaxOpenConns > 0 {
+		replica.MaxOpenConns = c.Replica.MaxOpenConns
+	}
+	if c.Replica.MaxIdleConns > 0 {
+		replica.MaxIdleConns = c.Replica.MaxIdleConns
+	}
+	return replica
+}
+
 // GetRedisAddr 获取Redis地址
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
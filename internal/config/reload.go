@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// 热加载仅应用以下配置项：log.level、rate_limit、cache、blockchain.allowed_chains、blockchain.denied_chains
+// 其余配置（数据库、Redis、RabbitMQ、节点地址、监听端口、JWT密钥等）变更时仅记录告警，需重启生效
+
+// subscriber 配置变更订阅者
+type subscriber struct {
+	selector func(*Config) interface{}
+	apply    func(*Config)
+}
+
+var (
+	currentMu   sync.RWMutex
+	current     *Config
+	reloadMu    sync.Mutex
+	subscribers []subscriber
+)
+
+// Current 获取当前生效的配置
+func Current() *Config {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// setCurrent 替换当前生效的配置
+func setCurrent(cfg *Config) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = cfg
+}
+
+// OnChange 订阅配置变更：selector选取关心的字段，热加载后字段值变化时以新配置调用apply
+func OnChange(selector func(*Config) interface{}, apply func(*Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	subscribers = append(subscribers, subscriber{selector: selector, apply: apply})
+}
+
+// Watch 监听配置文件变更与SIGHUP信号，触发热加载（需在Load与日志初始化之后调用）
+func Watch() {
+	// 1. 配置文件变更（仅使用环境变量部署时无文件可监听）
+	if _, err := os.Stat(viper.ConfigFileUsed()); err == nil {
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			logger.Info("Configuration file changed", zap.String("file", e.Name))
+			reloadAndLog()
+		})
+		viper.WatchConfig()
+	}
+
+	// 2. SIGHUP手动触发
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logger.Info("Received SIGHUP, reloading configuration")
+			reloadAndLog()
+		}
+	}()
+}
+
+// reloadAndLog 热加载配置，失败时保留原配置
+func reloadAndLog() {
+	if err := Reload(); err != nil {
+		logger.Error("Failed to reload configuration, keeping previous configuration", zap.Error(err))
+	}
+}
+
+// Reload 重新读取配置并应用可热加载的配置项，通知字段发生变化的订阅者
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	// 1. 读取并校验新配置
+	next, err := readConfig()
+	if err != nil {
+		return err
+	}
+
+	// 2. 仅合入可热加载的配置项
+	prev := Current()
+	applied := *prev
+	applied.Log.Level = next.Log.Level
+	applied.RateLimit = next.RateLimit
+	applied.Cache = next.Cache
+	applied.Blockchain.AllowedChains = next.Blockchain.AllowedChains
+	applied.Blockchain.DeniedChains = next.Blockchain.DeniedChains
+
+	// 3. 其余配置项的变更不生效，记录告警
+	for _, key := range diffKeys(&applied, next) {
+		logger.Warn("Configuration change requires restart, ignored", zap.String("key", key))
+	}
+
+	setCurrent(&applied)
+	logger.Info("Configuration reloaded",
+		zap.String("log_level", applied.Log.Level),
+		zap.Float64("rate_limit_rps", applied.RateLimit.RequestsPerSecond),
+		zap.Int("rate_limit_burst", applied.RateLimit.Burst),
+	)
+
+	// 4. 通知订阅者
+	for _, sub := range subscribers {
+		if !reflect.DeepEqual(sub.selector(prev), sub.selector(&applied)) {
+			sub.apply(&applied)
+		}
+	}
+
+	return nil
+}
+
+// diffKeys 比较两份配置，返回取值不同的配置项（精确到二级key）
+func diffKeys(a, b *Config) []string {
+	var keys []string
+	va, vb := reflect.ValueOf(*a), reflect.ValueOf(*b)
+	t := va.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		sa, sb := va.Field(i), vb.Field(i)
+		if reflect.DeepEqual(sa.Interface(), sb.Interface()) {
+			continue
+		}
+
+		name := section.Tag.Get("mapstructure")
+		if section.Type.Kind() != reflect.Struct {
+			keys = append(keys, name)
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			if !reflect.DeepEqual(sa.Field(j).Interface(), sb.Field(j).Interface()) {
+				keys = append(keys, name+"."+section.Type.Field(j).Tag.Get("mapstructure"))
+			}
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"crypto-wallet-api/internal/blockchain"
+)
+
+// redactedMask 脱敏占位符
+const redactedMask = "******"
+
+// Validate 校验配置，一次性返回所有缺失或非法的配置项
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// 服务
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535")
+	check(c.Server.Mode == "debug" || c.Server.Mode == "release", "server.mode must be debug or release")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.GzipMinSize >= 0, "server.gzip_min_size must not be negative")
+	check(c.Server.RequestTimeout >= 0, "server.request_timeout must not be negative")
+	check(c.Server.SendTimeout >= 0, "server.send_timeout must not be negative")
+	// 处理时限超过写超时时，响应在写出前已被连接超时截断
+	check(c.Server.WriteTimeout <= 0 || c.Server.SendTimeout < c.Server.WriteTimeout, "server.send_timeout must be shorter than server.write_timeout")
+	check(c.Server.WriteTimeout <= 0 || c.Server.RequestTimeout < c.Server.WriteTimeout, "server.request_timeout must be shorter than server.write_timeout")
+	if c.GRPC.Enabled {
+		check(c.GRPC.Port > 0 && c.GRPC.Port <= 65535, "grpc.port must be between 1 and 65535")
+		check(c.GRPC.Port != c.Server.Port, "grpc.port must differ from server.port")
+	}
+
+	// 数据库
+	check(c.Database.Host != "", "database.host is required")
+	check(c.Database.Port > 0, "database.port must be positive")
+	check(c.Database.User != "", "database.user is required")
+	check(c.Database.DBName != "", "database.dbname is required")
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive")
+	check(c.Database.MaxIdleConns > 0, "database.max_idle_conns must be positive")
+	switch c.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		problems = append(problems, "database.log_level must be one of silent, error, warn, info")
+	}
+	check(c.Database.SlowThreshold >= 0, "database.slow_threshold must not be negative")
+	if c.Database.ReplicaEnabled() {
+		check(c.Database.Replica.Port > 0, "database.replica.port must be positive")
+		check(c.Database.Replica.MaxLag > 0, "database.replica.max_lag must be positive")
+		check(c.Database.Replica.CheckInterval > 0, "database.replica.check_interval must be positive")
+	}
+
+	// Redis
+	check(c.Redis.Host != "", "redis.host is required")
+	check(c.Redis.Port > 0, "redis.port must be positive")
+	check(c.Redis.PoolSize > 0, "redis.pool_size must be positive")
+	check(c.Redis.OpTimeout >= 0, "redis.op_timeout must not be negative")
+
+	// RabbitMQ
+	check(c.RabbitMQ.Host != "", "rabbitmq.host is required")
+	check(c.RabbitMQ.Port > 0, "rabbitmq.port must be positive")
+	check(c.RabbitMQ.PublishTimeout > 0, "rabbitmq.publish_timeout must be positive")
+	check(c.RabbitMQ.DrainTimeout > 0, "rabbitmq.drain_timeout must be positive")
+
+	// 队列消费
+	check(c.Worker.Concurrency > 0, "worker.concurrency must be positive")
+	check(c.Worker.Prefetch >= c.Worker.Concurrency, "worker.prefetch must be at least worker.concurrency")
+
+	// JWT
+	check(c.JWT.Secret != "", "jwt.secret is required")
+	check(c.JWT.ExpireHours > 0, "jwt.expire_hours must be positive")
+
+	// 密码策略（bcrypt只使用前72字节，请求参数限制为50个字符）
+	check(c.PasswordPolicy.MinLength >= 8 && c.PasswordPolicy.MinLength <= 50, "password_policy.min_length must be between 8 and 50")
+
+	// 区块链
+	check(len(c.Blockchain.Chains) > 0, "blockchain.chains must contain at least one chain")
+	check(c.Blockchain.RPCTimeout >= 0, "blockchain.rpc_timeout must not be negative")
+	check(c.Blockchain.SendTimeout >= 0, "blockchain.send_timeout must not be negative")
+	// 广播在发送类请求的时限内完成才能返回结果（广播本身不随请求取消）
+	check(c.Server.SendTimeout <= 0 || c.Blockchain.SendTimeout < c.Server.SendTimeout, "blockchain.send_timeout must be shorter than server.send_timeout")
+	chainIDs := make(map[int]bool, len(c.Blockchain.Chains))
+	for i, chain := range c.Blockchain.Chains {
+		key := fmt.Sprintf("blockchain.chains[%d]", i)
+		check(chain.Name != "", "%s.name is required", key)
+		check(chain.ChainID > 0, "%s.chain_id must be positive", key)
+		check(!chainIDs[chain.ChainID], "%s.chain_id %d is duplicated", key, chain.ChainID)
+		chainIDs[chain.ChainID] = true
+		check(chain.Decimals > 0 && chain.Decimals <= 36, "%s.decimals must be between 1 and 36", key)
+		check(chain.ExplorerURL == "" || strings.HasPrefix(chain.ExplorerURL, "http://") || strings.HasPrefix(chain.ExplorerURL, "https://"),
+			"%s.explorer_url must start with http:// or https://", key)
+		// 未配置节点的链只用于历史记录的链名称，不能订阅新区块或启用水龙头
+		check(chain.Connected() || chain.WSURL == "", "%s.ws_url requires rpc_url", key)
+		check(chain.Connected() || chain.Faucet == nil, "%s.faucet requires rpc_url", key)
+		check(chain.Strategy == "primary" || chain.Strategy == "round_robin", "%s.strategy must be primary or round_robin", key)
+		check(chain.WSURL == "" || strings.HasPrefix(chain.WSURL, "ws://") || strings.HasPrefix(chain.WSURL, "wss://"),
+			"%s.ws_url must start with ws:// or wss://", key)
+		check(chain.PollInterval >= 0, "%s.poll_interval must not be negative", key)
+		check(chain.BlockTime >= 0, "%s.block_time must not be negative", key)
+		check(chain.HealthCheckInterval > 0, "%s.health_check_interval must be positive", key)
+		check(chain.StaleAfter == 0 || chain.StaleAfter > chain.HealthCheckInterval,
+			"%s.stale_after must be longer than %s.health_check_interval", key, key)
+		check(chain.NativeGasLimit == 0 || chain.NativeGasLimit >= 21000, "%s.native_gas_limit must be at least 21000", key)
+		check(chain.TokenGasLimit == 0 || chain.TokenGasLimit >= 21000, "%s.token_gas_limit must be at least 21000", key)
+		if faucet := chain.Faucet; faucet != nil {
+			// 水龙头只能在测试链白名单中的链上启用，防止误配置到主网
+			check(blockchain.IsTestnet(chain.ChainID), "%s.faucet cannot be enabled on chain_id %d (not an allowlisted test chain)", key, chain.ChainID)
+			check(common.IsHexAddress(faucet.Address), "%s.faucet.address must be a hex address", key)
+			amount, ok := new(big.Int).SetString(faucet.AmountWei, 10)
+			check(ok && amount.Sign() > 0, "%s.faucet.amount_wei must be a positive integer", key)
+			check(faucet.DailyLimit > 0, "%s.faucet.daily_limit must be positive", key)
+		}
+	}
+
+	// 部署允许的链（只能引用chains中配置的链，允许的链必须配置了节点，且至少启用一条链）
+	for _, list := range []struct {
+		key string
+		ids []int
+	}{{"blockchain.allowed_chains", c.Blockchain.AllowedChains}, {"blockchain.denied_chains", c.Blockchain.DeniedChains}} {
+		for _, id := range list.ids {
+			check(chainIDs[id], "%s contains chain_id %d which is not in blockchain.chains", list.key, id)
+		}
+	}
+	for _, chain := range c.Blockchain.Chains {
+		check(chain.Connected() || !slices.Contains(c.Blockchain.AllowedChains, chain.ChainID),
+			"blockchain.allowed_chains contains chain_id %d which has no rpc_url", chain.ChainID)
+	}
+	enabled := false
+	for _, chain := range c.Blockchain.Chains {
+		enabled = enabled || c.Blockchain.ChainEnabled(chain.ChainID)
+	}
+	check(len(c.Blockchain.Chains) == 0 || enabled, "blockchain.allowed_chains and blockchain.denied_chains leave no chain enabled")
+
+	// 日志
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, "log.level must be one of debug, info, warn, error")
+	}
+	check(c.Log.Output == "stdout" || c.Log.Output == "file", "log.output must be stdout or file")
+
+	// 限流与WebSocket
+	check(c.RateLimit.RequestsPerSecond > 0, "rate_limit.requests_per_second must be positive")
+	check(c.RateLimit.Burst > 0, "rate_limit.burst must be positive")
+	check(c.RateLimit.Expensive.RequestsPerSecond > 0, "rate_limit.expensive.requests_per_second must be positive")
+	check(c.RateLimit.Expensive.Burst > 0, "rate_limit.expensive.burst must be positive")
+	check(c.RateLimit.Export.RequestsPerSecond > 0, "rate_limit.export.requests_per_second must be positive")
+	check(c.RateLimit.Export.Burst > 0, "rate_limit.export.burst must be positive")
+	check(c.WebSocket.MaxSubscriptions > 0, "websocket.max_subscriptions must be positive")
+	check(c.WebSocket.PingInterval > 0, "websocket.ping_interval must be positive")
+
+	// 链路追踪
+	check(c.Tracing.SampleRate >= 0 && c.Tracing.SampleRate <= 1, "tracing.sample_rate must be between 0 and 1")
+
+	// 缓存
+	check(c.Cache.BalanceTTL >= time.Second, "cache.balance_ttl must be at least 1s")
+	check(c.Cache.PriceTTL >= time.Second, "cache.price_ttl must be at least 1s")
+	check(c.Cache.StatsTTL >= time.Second, "cache.stats_ttl must be at least 1s")
+	check(c.Cache.LocalSize >= 0, "cache.local_size must not be negative")
+	if c.Cache.LocalSize > 0 {
+		check(c.Cache.LocalTTL > 0, "cache.local_ttl must be positive when the local cache is enabled")
+	}
+
+	// 审批
+	check(c.Approval.TTL > 0, "approval.ttl must be positive")
+
+	// 发件箱
+	check(c.Outbox.PollInterval > 0, "outbox.poll_interval must be positive")
+	check(c.Outbox.BatchSize > 0, "outbox.batch_size must be positive")
+
+	// 定期转账
+	check(c.Recurring.PollInterval > 0, "recurring.poll_interval must be positive")
+	check(c.Recurring.BatchSize > 0, "recurring.batch_size must be positive")
+	check(c.Recurring.MaxFailures > 0, "recurring.max_failures must be positive")
+
+	// 交易确认轮询
+	check(c.Monitor.PollInterval > 0, "monitor.poll_interval must be positive")
+	check(c.Monitor.Concurrency > 0, "monitor.concurrency must be positive")
+	check(c.Monitor.BatchSize > 0, "monitor.batch_size must be positive")
+	check(c.Monitor.FreshInterval > 0, "monitor.fresh_interval must be positive")
+	check(c.Monitor.RecentInterval > 0, "monitor.recent_interval must be positive")
+	check(c.Monitor.StaleInterval > 0, "monitor.stale_interval must be positive")
+	check(c.Monitor.FreshAge > 0 && c.Monitor.FreshAge <= c.Monitor.RecentAge, "monitor.fresh_age must be positive and not exceed monitor.recent_age")
+	check(c.Monitor.StuckAfter > 0, "monitor.stuck_after must be positive")
+	check(c.Monitor.UnknownGrace > 0, "monitor.unknown_grace must be positive")
+
+	// 代币
+	check(c.Tokens.MetadataTTL > 0, "tokens.metadata_ttl must be positive")
+	check(c.Tokens.BalanceConcurrency > 0, "tokens.balance_concurrency must be positive")
+	check(c.Tokens.DepositPollInterval > 0, "tokens.deposit_poll_interval must be positive")
+	check(c.Tokens.DepositBlockRange > 0, "tokens.deposit_block_range must be positive")
+
+	// 钱包
+	check(c.Wallets.MaxPerUser >= 0, "wallets.max_per_user must not be negative")
+	check(c.Wallets.BulkConcurrency > 0, "wallets.bulk_concurrency must be positive")
+	check(c.Exports.Dir != "", "exports.dir is required")
+	check(c.Exports.FileTTL > 0, "exports.file_ttl must be positive")
+	check(c.Exports.TokenTTL > 0, "exports.token_ttl must be positive")
+	check(c.Exports.CleanupInterval > 0, "exports.cleanup_interval must be positive")
+
+	// 私钥缓存
+	if c.KeyCache.Enabled {
+		check(c.KeyCache.TTL > 0, "key_cache.ttl must be positive")
+		check(c.KeyCache.MaxSize > 0, "key_cache.max_size must be positive")
+	}
+
+	// 通知
+	check(c.Notifications.WebhookTimeout > 0, "notifications.webhook_timeout must be positive")
+
+	// 外发邮件
+	switch c.Mail.Driver {
+	case "log":
+	case "smtp":
+		check(c.Mail.Host != "", "mail.host is required when mail.driver is smtp")
+		check(c.Mail.Port > 0 && c.Mail.Port <= 65535, "mail.port must be between 1 and 65535")
+		_, err := mail.ParseAddress(c.Mail.From)
+		check(err == nil, "mail.from must be an email address")
+		check(c.Mail.TLSMode == "starttls" || c.Mail.TLSMode == "tls" || c.Mail.TLSMode == "none", "mail.tls_mode must be starttls, tls or none")
+		check(c.Mail.Timeout > 0, "mail.timeout must be positive")
+	default:
+		problems = append(problems, "mail.driver must be smtp or log")
+	}
+
+	// 后台余额刷新
+	check(c.BalanceRefresh.Workers > 0, "balance_refresh.workers must be positive")
+	check(c.BalanceRefresh.BatchSize > 0, "balance_refresh.batch_size must be positive")
+	check(c.BalanceRefresh.RetryInterval > 0, "balance_refresh.retry_interval must be positive")
+
+	// 后台任务队列
+	check(c.Jobs.Workers > 0, "jobs.workers must be positive")
+	check(c.Jobs.QueueSize > 0, "jobs.queue_size must be positive")
+
+	// 余额快照
+	check(c.BalanceHistory.SnapshotInterval > 0, "balance_history.snapshot_interval must be positive")
+	check(c.BalanceHistory.HourlyRetention >= 24*time.Hour, "balance_history.hourly_retention must be at least 24h")
+
+	// ENS
+	check(!c.ENS.Enabled || c.ENS.CacheTTL > 0, "ens.cache_ttl must be positive when ENS is enabled")
+
+	// 功能开关
+	check(c.FeatureFlags.RefreshInterval > 0, "feature_flags.refresh_interval must be positive")
+
+	// Gas价格预言机
+	check(c.GasOracle.RefreshInterval > 0, "gas_oracle.refresh_interval must be positive")
+	check(c.GasOracle.BlockCount > 0 && c.GasOracle.BlockCount <= 1024, "gas_oracle.block_count must be between 1 and 1024")
+	check(c.GasOracle.CacheTTL >= c.GasOracle.RefreshInterval, "gas_oracle.cache_ttl must not be shorter than gas_oracle.refresh_interval")
+
+	// 余额对账
+	check(c.Reconciliation.Interval > 0, "reconciliation.interval must be positive")
+	check(c.Reconciliation.BatchSize > 0, "reconciliation.batch_size must be positive")
+	threshold, ok := new(big.Int).SetString(c.Reconciliation.ThresholdWei, 10)
+	check(ok && threshold.Sign() >= 0, "reconciliation.threshold_wei must be a non-negative integer")
+
+	// 加密密钥
+	_, keyErr := c.Keys.NewProvider()
+	check(keyErr == nil, "keys: %v", keyErr)
+	check(c.Keys.EmailHMAC != c.Keys.WalletEncryption && c.Keys.EmailHMAC != c.Keys.PIIEncryption, "keys.email_hmac must differ from the encryption keys")
+	check(c.Keys.QueueEncryption == "" || (c.Keys.QueueEncryption != c.Keys.WalletEncryption && c.Keys.QueueEncryption != c.Keys.PIIEncryption && c.Keys.QueueEncryption != c.Keys.EmailHMAC), "keys.queue_encryption must differ from the other keys")
+
+	// 指标
+	check(!c.Metrics.Enabled || c.Metrics.WorkerAddr != "", "metrics.worker_addr is required when metrics are enabled")
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration:\n  - " + strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// String 返回脱敏后的配置（用于启动日志，密码、密钥与RPC地址中的凭证被遮蔽）
+func (c *Config) String() string {
+	redacted := *c
+	redacted.Database.Password = mask(c.Database.Password)
+	redacted.Database.Replica.Password = mask(c.Database.Replica.Password)
+	redacted.Redis.Password = mask(c.Redis.Password)
+	redacted.RabbitMQ.Password = mask(c.RabbitMQ.Password)
+	redacted.Mail.Password = mask(c.Mail.Password)
+	redacted.JWT.Secret = mask(c.JWT.Secret)
+	redacted.Keys.WalletEncryption = mask(c.Keys.WalletEncryption)
+	redacted.Keys.PIIEncryption = mask(c.Keys.PIIEncryption)
+	redacted.Keys.EmailHMAC = mask(c.Keys.EmailHMAC)
+	redacted.Keys.QueueEncryption = mask(c.Keys.QueueEncryption)
+	redacted.Pricing.APIKey = mask(c.Pricing.APIKey)
+	redacted.Blockchain.Chains = make([]ChainConfig, len(c.Blockchain.Chains))
+	for i, chain := range c.Blockchain.Chains {
+		chain.RPCURLs = redactURLs(chain.RPCURLs)
+		chain.WSURL = redactURL(chain.WSURL)
+		redacted.Blockchain.Chains[i] = chain
+	}
+
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		return fmt.Sprintf("<config: %v>", err)
+	}
+	return string(data)
+}
+
+// mask 非空值替换为占位符
+func mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedMask
+}
+
+// redactURL 脱敏单个地址
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	return redactURLs([]string{raw})[0]
+}
+
+// redactURLs 仅保留scheme与host（RPC服务商通常将API Key放在路径或查询参数中）
+func redactURLs(urls []string) []string {
+	if urls == nil {
+		return nil
+	}
+
+	redacted := make([]string, len(urls))
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			redacted[i] = redactedMask
+			continue
+		}
+		redacted[i] = u.Scheme + "://" + u.Host
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			redacted[i] += "/" + redactedMask
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// authServer 认证服务
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	authService *service.AuthService
+}
+
+// Register 用户注册
+func (s *authServer) Register(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.UserCreateRequest{
+		Username: in.GetUsername(),
+		Email:    in.GetEmail(),
+		Password: in.GetPassword(),
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	user, err := s.authService.Register(ctx, req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailTaken) || errors.Is(err, service.ErrUsernameTaken) {
+			return nil, invalidArgument(err.Error())
+		}
+		return nil, appError(ctx, err)
+	}
+	return &pb.RegisterResponse{User: userToProto(user.ToResponse())}, nil
+}
+
+// Login 用户登录
+func (s *authServer) Login(ctx context.Context, in *pb.LoginRequest) (*pb.LoginResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.UserLoginRequest{
+		Email:    in.GetEmail(),
+		Password: in.GetPassword(),
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	resp, err := s.authService.Login(ctx, req, clientIP(ctx), firstMetadata(ctx, "user-agent"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			return nil, statusError(codes.Unauthenticated, utils.CodeUnauthorized, err.Error())
+		}
+		return nil, appError(ctx, err)
+	}
+	return &pb.LoginResponse{
+		Token:     resp.Token,
+		User:      userToProto(resp.User),
+		NewDevice: resp.NewDevice,
+	}, nil
+}
+
+// GetProfile 获取当前用户信息
+func (s *authServer) GetProfile(ctx context.Context, _ *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	user, err := s.authService.GetProfile(ctx, userIDFromContext(ctx))
+	if err != nil {
+		return nil, appError(ctx, err)
+	}
+	return &pb.GetProfileResponse{User: userToProto(user.ToResponse())}, nil
+}
+
+// Logout 吊销当前使用的JWT Token
+func (s *authServer) Logout(ctx context.Context, _ *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	// 1. 获取当前Token信息（API Key认证时不存在）
+	info := authFromContext(ctx)
+	if info == nil || info.claims == nil {
+		return nil, statusError(codes.FailedPrecondition, utils.CodeInvalidParams, "logout requires a bearer token")
+	}
+
+	// 2. 调用服务层
+	if err := s.authService.Logout(ctx, info.claims); err != nil {
+		return nil, internalError(ctx, codes.Internal, utils.CodeInternalError, "internal server error", err)
+	}
+	return &pb.LogoutResponse{}, nil
+}
@@ -0,0 +1,118 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/models"
+)
+
+// timestampOrNil 将可选时间转换为Timestamp（nil或零值返回nil）
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// uint64OrNil 将可选ID转换为proto optional字段
+func uint64OrNil(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	v := uint64(*id)
+	return &v
+}
+
+// userToProto 转换用户信息
+func userToProto(u *models.UserResponse) *pb.User {
+	return &pb.User{
+		Id:        uint64(u.ID),
+		Username:  u.Username,
+		Email:     u.Email,
+		Role:      string(u.Role),
+		CreatedAt: timestampOrNil(&u.CreatedAt),
+	}
+}
+
+// walletToProto 转换钱包信息
+func walletToProto(w *models.WalletResponse) *pb.Wallet {
+	return &pb.Wallet{
+		Id:                    uint64(w.ID),
+		Address:               w.Address,
+		ChainId:               int64(w.ChainID),
+		ChainName:             w.ChainName,
+		Balance:               w.Balance,
+		Name:                  w.Name,
+		Label:                 w.Label,
+		Color:                 w.Color,
+		Archived:              w.Archived,
+		OrgId:                 uint64OrNil(w.OrgID),
+		CreatedAt:             timestampOrNil(&w.CreatedAt),
+		WhitelistEnabled:      w.WhitelistEnabled,
+		DailyLimitWei:         w.DailyLimitWei,
+		DailyTxLimit:          int64(w.DailyTxLimit),
+		ApprovalThresholdWei:  w.ApprovalThresholdWei,
+		RequiredApprovals:     int64(w.RequiredApprovals),
+		ConfirmationsRequired: w.ConfirmationsRequired,
+		PassphraseProtected:   w.PassphraseProtected,
+		BalanceUsd:            w.BalanceUSD,
+		PriceUnavailable:      w.PriceUnavailable,
+		Stale:                 w.Stale,
+	}
+}
+
+// transactionToProto 转换交易信息
+func transactionToProto(tx *models.TransactionResponse) *pb.Transaction {
+	approvedBy := make([]uint64, len(tx.ApprovedBy))
+	for i, id := range tx.ApprovedBy {
+		approvedBy[i] = uint64(id)
+	}
+	return &pb.Transaction{
+		Id:                    uint64(tx.ID),
+		TxHash:                tx.TxHash,
+		Type:                  string(tx.Type),
+		FromAddress:           tx.FromAddress,
+		ToAddress:             tx.ToAddress,
+		ToEnsName:             tx.ToENSName,
+		FromEnsName:           tx.FromENSName,
+		Amount:                tx.Amount,
+		GasPrice:              tx.GasPrice,
+		GasUsed:               tx.GasUsed,
+		Status:                string(tx.Status),
+		BlockNumber:           tx.BlockNumber,
+		Confirmations:         tx.Confirmations,
+		ConfirmationsRequired: tx.ConfirmationsRequired,
+		ChainId:               int64(tx.ChainID),
+		ChainName:             tx.ChainName,
+		ContactName:           tx.ContactName,
+		Method:                tx.Method,
+		MethodArgs:            string(tx.MethodArgs),
+		RecurringPaymentId:    uint64OrNil(tx.RecurringPaymentID),
+		TokenAddress:          tx.TokenAddress,
+		TokenSymbol:           tx.TokenSymbol,
+		Note:                  tx.Note,
+		Tags:                  tx.Tags,
+		RequiredApprovals:     int64(tx.RequiredApprovals),
+		ApprovedBy:            approvedBy,
+		ApprovalExpiresAt:     timestampOrNil(tx.ApprovalExpiresAt),
+		CreatedAt:             timestampOrNil(&tx.CreatedAt),
+		ConfirmedAt:           timestampOrNil(tx.ConfirmedAt),
+	}
+}
+
+// transactionListToProto 转换交易列表
+func transactionListToProto(resp *models.TransactionListResponse) *pb.ListTransactionsResponse {
+	txs := make([]*pb.Transaction, len(resp.Transactions))
+	for i, tx := range resp.Transactions {
+		txs[i] = transactionToProto(tx)
+	}
+	return &pb.ListTransactionsResponse{
+		Total:        resp.Total,
+		Page:         int32(resp.Page),
+		PageSize:     int32(resp.PageSize),
+		Transactions: txs,
+	}
+}
@@ -0,0 +1,146 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// errorDomain ErrorInfo详情的域
+const errorDomain = "crypto-wallet-api"
+
+// statusError 构建gRPC错误，ErrorInfo.Reason为与REST响应一致的业务码（如10011）
+func statusError(c codes.Code, code int, message string) error {
+	st := status.New(c, message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: strconv.Itoa(code), Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// invalidArgument 参数错误
+func invalidArgument(message string) error {
+	return statusError(codes.InvalidArgument, utils.CodeInvalidParams, message)
+}
+
+// invalidFields 参数校验失败（字段级错误放入BadRequest详情，与REST响应的data.fields一致）
+func invalidFields(message string, fields map[string]string) error {
+	st := status.New(codes.InvalidArgument, message)
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: fields[field]})
+	}
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{Reason: strconv.Itoa(utils.CodeInvalidParams), Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	}
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// notFound 资源不存在
+func notFound(message string) error {
+	return statusError(codes.NotFound, utils.CodeNotFound, message)
+}
+
+// permissionDenied 无权访问
+func permissionDenied(message string) error {
+	return statusError(codes.PermissionDenied, utils.CodeForbidden, message)
+}
+
+// internalError 内部错误（详细错误只记录日志，不返回给客户端）
+func internalError(ctx context.Context, c codes.Code, code int, message string, err error) error {
+	if apperr.IsTimeout(err) {
+		c, code, message = codes.DeadlineExceeded, utils.CodeUpstreamTimeout, "upstream service timed out"
+	}
+	logger.WithCtx(ctx).Error("gRPC request failed", zap.String("message", message), zap.Error(err))
+	return statusError(c, code, message)
+}
+
+// databaseError 数据库错误
+func databaseError(ctx context.Context, err error) error {
+	return internalError(ctx, codes.Internal, utils.CodeDatabaseError, "database error", err)
+}
+
+// appError 按类型化错误（apperr）返回gRPC错误，其余错误按内部错误处理（与REST的utils.AppError一致）
+func appError(ctx context.Context, err error) error {
+	message, ok := apperr.Message(err)
+	switch {
+	case !ok:
+		return internalError(ctx, codes.Internal, utils.CodeInternalError, "internal server error", err)
+	case errors.Is(err, apperr.ErrNotFound):
+		return notFound(message)
+	case errors.Is(err, apperr.ErrInvalid):
+		return invalidArgument(message)
+	case errors.Is(err, apperr.ErrConflict):
+		return statusError(codes.AlreadyExists, utils.CodeDuplicateResource, message)
+	case errors.Is(err, apperr.ErrForbidden):
+		return permissionDenied(message)
+	default:
+		return internalError(ctx, codes.Internal, utils.CodeInternalError, "internal server error", err)
+	}
+}
+
+// sendErrors 发送交易的业务错误对应的gRPC状态码与业务码（与REST的sendError一致）
+var sendErrors = []struct {
+	err  error
+	code codes.Code
+	biz  int
+}{
+	{service.ErrWalletNotFound, codes.NotFound, utils.CodeNotFound},
+	{service.ErrChainUnhealthy, codes.Unavailable, utils.CodeChainUnhealthy},
+	{service.ErrWalletArchived, codes.FailedPrecondition, utils.CodeWalletArchived},
+	{service.ErrChainNotAllowed, codes.PermissionDenied, utils.CodeChainNotAllowed},
+	{service.ErrAddressNotWhitelisted, codes.PermissionDenied, utils.CodeAddressNotWhitelisted},
+	{service.ErrZeroAddress, codes.InvalidArgument, utils.CodeZeroAddress},
+	{service.ErrENSResolution, codes.InvalidArgument, utils.CodeENSResolutionFailed},
+	{service.ErrSelfTransfer, codes.InvalidArgument, utils.CodeSelfTransfer},
+	{service.ErrInternalRecipient, codes.InvalidArgument, utils.CodeInvalidParams},
+	{service.ErrInsufficientBalance, codes.FailedPrecondition, utils.CodeInsufficientBalance},
+	{service.ErrInsufficientLedgerBalance, codes.FailedPrecondition, utils.CodeInsufficientBalance},
+	{service.ErrInvalidAmount, codes.InvalidArgument, utils.CodeInvalidParams},
+	{service.ErrNoDefaultWallet, codes.InvalidArgument, utils.CodeInvalidParams},
+	{service.ErrPassphraseRequired, codes.InvalidArgument, utils.CodePassphraseRequired},
+	{service.ErrInvalidPassphrase, codes.PermissionDenied, utils.CodeInvalidPassphrase},
+	{service.ErrApprovalRequired, codes.PermissionDenied, utils.CodeApprovalRequired},
+	{service.ErrInvalidApprovalPolicy, codes.InvalidArgument, utils.CodeInvalidParams},
+	{service.ErrPermissionDenied, codes.PermissionDenied, utils.CodeForbidden},
+	{service.ErrMemberSendLimitExceeded, codes.PermissionDenied, utils.CodeForbidden},
+}
+
+// sendError 将发送交易的业务错误映射为gRPC错误
+func sendError(ctx context.Context, err error) error {
+	var limitErr *service.DailyLimitExceededError
+	if errors.As(err, &limitErr) {
+		return statusError(codes.ResourceExhausted, utils.CodeDailyLimitExceeded, "daily transfer limit exceeded")
+	}
+	var disabledErr *service.FeatureDisabledError
+	if errors.As(err, &disabledErr) {
+		return statusError(codes.Unavailable, utils.CodeFeatureDisabled, service.ErrFeatureDisabled.Error())
+	}
+	for _, e := range sendErrors {
+		if errors.Is(err, e.err) {
+			return statusError(e.code, e.biz, err.Error())
+		}
+	}
+	if errors.Is(err, apperr.ErrNotFound) || errors.Is(err, apperr.ErrInvalid) ||
+		errors.Is(err, apperr.ErrConflict) || errors.Is(err, apperr.ErrForbidden) {
+		return appError(ctx, err)
+	}
+	return internalError(ctx, codes.Unavailable, utils.CodeBlockchainError, "blockchain interaction error", err)
+}
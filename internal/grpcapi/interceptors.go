@@ -0,0 +1,193 @@
+package grpcapi
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/middleware"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+const (
+	// requestIDKey 请求ID metadata键（与HTTP的X-Request-ID对应）
+	requestIDKey = "x-request-id"
+	// apiKeyKey API Key metadata键（与HTTP的X-API-Key对应）
+	apiKeyKey = "x-api-key"
+	// authorizationKey JWT metadata键
+	authorizationKey = "authorization"
+)
+
+// publicMethods 无需认证的方法
+var publicMethods = map[string]bool{
+	pb.AuthService_Register_FullMethodName: true,
+	pb.AuthService_Login_FullMethodName:    true,
+}
+
+// authInfo 认证信息（由认证拦截器写入context）
+type authInfo struct {
+	userID uint
+	claims *service.TokenClaims // API Key认证时为nil
+}
+
+type authInfoKey struct{}
+
+// authFromContext 获取认证信息
+func authFromContext(ctx context.Context) *authInfo {
+	info, _ := ctx.Value(authInfoKey{}).(*authInfo)
+	return info
+}
+
+// userIDFromContext 获取当前用户ID（认证拦截器保证非公开方法中存在）
+func userIDFromContext(ctx context.Context) uint {
+	if info := authFromContext(ctx); info != nil {
+		return info.userID
+	}
+	return 0
+}
+
+// firstMetadata 获取metadata中的第一个值
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// clientIP 客户端IP（不含端口）
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if i := strings.LastIndexByte(addr, ':'); i >= 0 {
+		addr = addr[:i]
+	}
+	return strings.Trim(addr, "[]")
+}
+
+// requestIDInterceptor 请求ID拦截器（沿用客户端传入的x-request-id，否则生成UUID，并通过响应头返回）
+func requestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	// 1. 获取或生成请求ID（限制长度，避免日志被超长头部污染）
+	requestID := firstMetadata(ctx, requestIDKey)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = uuid.NewString()
+	}
+
+	// 2. 写入context并关联到当前Span
+	ctx = logger.ContextWithRequestID(ctx, requestID)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("rpc.request_id", requestID))
+
+	// 3. 返回给客户端
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	return handler(ctx, req)
+}
+
+// loggingInterceptor 日志拦截器
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	startTime := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	logger.WithCtx(ctx).Info("gRPC Request",
+		zap.String("method", info.FullMethod),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("latency", time.Since(startTime)),
+		zap.String("client_ip", clientIP(ctx)),
+		zap.String("user_agent", firstMetadata(ctx, "user-agent")),
+	)
+	return resp, err
+}
+
+// recoveryInterceptor 将处理过程中的panic转换为INTERNAL错误，避免进程退出
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.WithCtx(ctx).Error("gRPC handler panic",
+				zap.String("method", info.FullMethod),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+			err = statusError(codes.Internal, utils.CodeInternalError, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// rateLimitInterceptor 限流拦截器（与HTTP共用令牌桶，热加载的限流参数同时生效）
+func rateLimitInterceptor(limiter *middleware.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !limiter.Allow() {
+			return nil, statusError(codes.ResourceExhausted, utils.CodeRateLimited, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authInterceptor 认证拦截器（支持Bearer JWT或x-api-key，与HTTP的AuthMiddleware一致）
+func authInterceptor(authService *service.AuthService, apiKeyService *service.APIKeyService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		// 1. 优先使用API Key
+		if rawKey := firstMetadata(ctx, apiKeyKey); rawKey != "" {
+			key, err := apiKeyService.Authenticate(ctx, rawKey)
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid api key")
+			}
+
+			// 只读Key仅允许查询方法
+			if key.Scope == models.APIKeyScopeRead && !isReadOnlyMethod(info.FullMethod) {
+				return nil, status.Error(codes.PermissionDenied, "api key is read-only")
+			}
+
+			ctx = context.WithValue(ctx, authInfoKey{}, &authInfo{userID: key.UserID})
+			return handler(ctx, req)
+		}
+
+		// 2. 解析Bearer Token
+		authHeader := firstMetadata(ctx, authorizationKey)
+		if authHeader == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+		}
+
+		// 3. 验证Token
+		claims, err := authService.ValidateToken(ctx, parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+
+		ctx = context.WithValue(ctx, authInfoKey{}, &authInfo{userID: claims.UserID, claims: claims})
+		return handler(ctx, req)
+	}
+}
+
+// isReadOnlyMethod 判断是否为只读方法（Get*、List*）
+func isReadOnlyMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndexByte(fullMethod, '/')+1:]
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
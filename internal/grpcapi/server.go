@@ -0,0 +1,49 @@
+// Package grpcapi gRPC接口（与REST API调用同一组Service，拦截器对应HTTP的请求ID、日志、限流与认证中间件）
+package grpcapi
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/middleware"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// Services gRPC接口依赖的Service
+type Services struct {
+	Auth        *service.AuthService
+	APIKey      *service.APIKeyService
+	Wallet      *service.WalletService
+	Transaction *service.TransactionService
+}
+
+// NewServer 创建gRPC服务器并注册全部服务（limiter与HTTP共用，opts追加到默认选项之后）
+func NewServer(services *Services, limiter *middleware.RateLimiter, opts ...grpc.ServerOption) *grpc.Server {
+	utils.InitValidator()
+
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			requestIDInterceptor,
+			loggingInterceptor,
+			recoveryInterceptor,
+			rateLimitInterceptor(limiter),
+			authInterceptor(services.Auth, services.APIKey),
+		),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+
+	pb.RegisterAuthServiceServer(server, &authServer{authService: services.Auth})
+	pb.RegisterWalletServiceServer(server, &walletServer{walletService: services.Wallet})
+	pb.RegisterTransactionServiceServer(server, &transactionServer{txService: services.Transaction})
+	return server
+}
+
+// validate 按binding标签校验请求（与REST的参数绑定使用同一套规则）
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return invalidFields("invalid request parameters", utils.FieldErrors(err))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+)
+
+// transactionServer 交易服务
+type transactionServer struct {
+	pb.UnimplementedTransactionServiceServer
+	txService *service.TransactionService
+}
+
+// SendTransaction 发起转账
+func (s *transactionServer) SendTransaction(ctx context.Context, in *pb.SendTransactionRequest) (*pb.SendTransactionResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.TransactionCreateRequest{
+		FromAddress: in.GetFromAddress(),
+		ToAddress:   in.GetToAddress(),
+		ContactID:   uint(in.GetContactId()),
+		Amount:      in.GetAmount(),
+		ChainID:     int(in.GetChainId()),
+		GasLimit:    in.GetGasLimit(),
+		Speed:       models.GasSpeed(in.GetSpeed()),
+		Passphrase:  in.GetPassphrase(),
+		Note:        in.GetNote(),
+		Tags:        in.GetTags(),
+		Internal:    in.GetInternal(),
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	userID := userIDFromContext(ctx)
+	tx, err := s.txService.SendTransaction(ctx, userID, req)
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+
+	// 3. 返回响应（金额超过审批阈值时交易等待审批）
+	return &pb.SendTransactionResponse{
+		Transaction:      transactionToProto(s.txService.BuildResponse(ctx, userID, tx)),
+		AwaitingApproval: tx.Status == models.TxStatusAwaitingApproval,
+	}, nil
+}
+
+// GetTransaction 获取交易详情
+func (s *transactionServer) GetTransaction(ctx context.Context, in *pb.GetTransactionRequest) (*pb.GetTransactionResponse, error) {
+	userID := userIDFromContext(ctx)
+	tx, err := s.txService.GetTransaction(ctx, userID, in.GetTxHash())
+	if err != nil {
+		return nil, notFound("transaction not found")
+	}
+	return &pb.GetTransactionResponse{Transaction: transactionToProto(s.txService.BuildResponse(ctx, userID, tx))}, nil
+}
+
+// ListTransactions 获取交易列表
+func (s *transactionServer) ListTransactions(ctx context.Context, in *pb.ListTransactionsRequest) (*pb.ListTransactionsResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.TransactionListRequest{
+		WalletAddress: in.GetWalletAddress(),
+		Status:        models.TransactionStatus(in.GetStatus()),
+		ChainID:       int(in.GetChainId()),
+		Tag:           in.GetTag(),
+		Page:          int(in.GetPage()),
+		PageSize:      int(in.GetPageSize()),
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	resp, err := s.txService.ListTransactions(ctx, userIDFromContext(ctx), req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			return nil, notFound(err.Error())
+		}
+		if errors.Is(err, apperr.ErrInvalid) {
+			return nil, appError(ctx, err)
+		}
+		return nil, databaseError(ctx, err)
+	}
+	return transactionListToProto(resp), nil
+}
@@ -0,0 +1,177 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+
+	pb "crypto-wallet-api/api/proto/cryptowallet/v1"
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// walletServer 钱包服务
+type walletServer struct {
+	pb.UnimplementedWalletServiceServer
+	walletService *service.WalletService
+}
+
+// withValuation 附带美元估值与链健康状态
+func (s *walletServer) withValuation(ctx context.Context, wallet *models.Wallet) *pb.Wallet {
+	resp := wallet.ToResponse()
+	usd, ok := s.walletService.ValueInUSD(ctx, wallet.ChainID, utils.DecimalToWei(wallet.Balance))
+	if ok {
+		resp.BalanceUSD = usd
+	} else {
+		resp.PriceUnavailable = true
+	}
+	resp.Stale = s.walletService.ChainDegraded(wallet.ChainID)
+	return walletToProto(resp)
+}
+
+// CreateWallet 创建钱包
+func (s *walletServer) CreateWallet(ctx context.Context, in *pb.CreateWalletRequest) (*pb.CreateWalletResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.WalletCreateRequest{
+		ChainID:    int(in.GetChainId()),
+		Name:       in.GetName(),
+		Passphrase: in.GetPassphrase(),
+		OrgID:      uint(in.GetOrgId()),
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	wallet, err := s.walletService.CreateWallet(ctx, userIDFromContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrgNotFound):
+			return nil, notFound(err.Error())
+		case errors.Is(err, service.ErrPermissionDenied):
+			return nil, permissionDenied(err.Error())
+		case errors.Is(err, service.ErrChainNotAllowed):
+			return nil, statusError(codes.PermissionDenied, utils.CodeChainNotAllowed, err.Error())
+		default:
+			return nil, appError(ctx, err)
+		}
+	}
+	return &pb.CreateWalletResponse{Wallet: walletToProto(wallet.ToResponse())}, nil
+}
+
+// ListWallets 获取钱包列表
+func (s *walletServer) ListWallets(ctx context.Context, in *pb.ListWalletsRequest) (*pb.ListWalletsResponse, error) {
+	// 1. 转换并校验请求参数
+	req := &models.WalletListRequest{Archived: in.GetArchived()}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	wallets, err := s.walletService.GetUserWallets(ctx, userIDFromContext(ctx), req.ArchivedFilter())
+	if err != nil {
+		return nil, databaseError(ctx, err)
+	}
+
+	// 3. 转换为响应格式（附带美元估值）
+	resp := &pb.ListWalletsResponse{
+		Total:   int64(len(wallets)),
+		Wallets: make([]*pb.Wallet, len(wallets)),
+	}
+	for i, wallet := range wallets {
+		resp.Wallets[i] = s.withValuation(ctx, wallet)
+	}
+	return resp, nil
+}
+
+// GetWallet 获取钱包详情
+func (s *walletServer) GetWallet(ctx context.Context, in *pb.GetWalletRequest) (*pb.GetWalletResponse, error) {
+	wallet, err := s.walletService.GetWalletByAddress(ctx, userIDFromContext(ctx), in.GetAddress())
+	if err != nil {
+		return nil, notFound("wallet not found")
+	}
+	resp := wallet.ToResponse()
+	resp.Stale = s.walletService.ChainDegraded(wallet.ChainID)
+	return &pb.GetWalletResponse{Wallet: walletToProto(resp)}, nil
+}
+
+// GetBalance 查询钱包余额
+func (s *walletServer) GetBalance(ctx context.Context, in *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	// 1. 校验钱包访问权限
+	userID := userIDFromContext(ctx)
+	wallet, err := s.walletService.GetWalletByAddress(ctx, userID, in.GetAddress())
+	if err != nil {
+		return nil, notFound("wallet not found")
+	}
+
+	// 2. 查询余额（force_refresh时绕过缓存）
+	getBalance := s.walletService.GetBalance
+	if in.GetForceRefresh() {
+		getBalance = s.walletService.RefreshBalance
+	}
+	balance, err := getBalance(ctx, userID, in.GetAddress())
+	if err != nil {
+		return nil, internalError(ctx, codes.Unavailable, utils.CodeBlockchainError, "blockchain interaction error", err)
+	}
+
+	// 3. 返回响应（Wei、Ether两种单位及美元估值）
+	resp := &pb.GetBalanceResponse{
+		Address:    in.GetAddress(),
+		BalanceWei: balance.String(),
+		BalanceEth: utils.WeiToEthString(balance),
+		Stale:      s.walletService.ChainDegraded(wallet.ChainID),
+	}
+	usd, ok := s.walletService.ValueInUSD(ctx, wallet.ChainID, balance)
+	if ok {
+		resp.BalanceUsd = usd
+	} else {
+		resp.PriceUnavailable = true
+	}
+	return resp, nil
+}
+
+// UpdateWallet 部分更新钱包信息
+func (s *walletServer) UpdateWallet(ctx context.Context, in *pb.UpdateWalletRequest) (*pb.UpdateWalletResponse, error) {
+	// 1. 转换并校验请求参数（未设置的字段保持不变）
+	req := &models.WalletUpdateRequest{
+		Name:                  in.Name,
+		Label:                 in.Label,
+		Color:                 in.Color,
+		Archived:              in.Archived,
+		ConfirmationsRequired: in.ConfirmationsRequired,
+	}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 2. 调用服务层
+	wallet, err := s.walletService.PatchWallet(ctx, userIDFromContext(ctx), in.GetAddress(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			return nil, permissionDenied(err.Error())
+		case errors.Is(err, service.ErrWalletNotFound):
+			return nil, notFound(err.Error())
+		default:
+			return nil, databaseError(ctx, err)
+		}
+	}
+	return &pb.UpdateWalletResponse{Wallet: walletToProto(wallet.ToResponse())}, nil
+}
+
+// DeleteWallet 删除钱包
+func (s *walletServer) DeleteWallet(ctx context.Context, in *pb.DeleteWalletRequest) (*pb.DeleteWalletResponse, error) {
+	if err := s.walletService.DeleteWallet(ctx, userIDFromContext(ctx), in.GetAddress()); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			return nil, permissionDenied(err.Error())
+		}
+		if message, ok := apperr.Message(err); ok && errors.Is(err, apperr.ErrInvalid) {
+			return nil, statusError(codes.FailedPrecondition, utils.CodeInvalidParams, message)
+		}
+		return nil, appError(ctx, err)
+	}
+	return &pb.DeleteWalletResponse{}, nil
+}
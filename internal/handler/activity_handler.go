@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ActivityHandler 钱包动态处理器
+type ActivityHandler struct {
+	activityService *service.ActivityService
+}
+
+// NewActivityHandler 创建钱包动态处理器实例
+func NewActivityHandler(activityService *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetActivity 获取钱包动态
+// @Summary 获取钱包动态
+// @Description 按时间倒序合并转出/转入交易、余额变化、改名、限额与白名单变更；使用next_cursor翻页，新动态不会导致翻页错位
+// @Tags 钱包
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param cursor query string false "上一页返回的next_cursor"
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.ActivityFeedResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/activity [get]
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定查询参数
+	var req models.ActivityFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.activityService.GetFeed(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, apperr.ErrInvalid) {
+			utils.BadRequest(c, apperr.MessageKey(err))
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, resp)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// AdminHandler 运维管理处理器（仅管理员可访问）
+type AdminHandler struct {
+	featureFlags *service.FeatureFlagService
+	stats        *service.AdminStatsService
+	txService    *service.TransactionService
+	recon        *service.ReconciliationService
+}
+
+// NewAdminHandler 创建运维管理处理器实例
+func NewAdminHandler(featureFlags *service.FeatureFlagService, stats *service.AdminStatsService, txService *service.TransactionService, recon *service.ReconciliationService) *AdminHandler {
+	return &AdminHandler{
+		featureFlags: featureFlags,
+		stats:        stats,
+		txService:    txService,
+		recon:        recon,
+	}
+}
+
+// GetStats 获取运维总览统计
+// @Summary 获取运维总览统计
+// @Description 返回用户与钱包总数、各状态交易数量，以及按链统计的钱包数、交易数、最近24小时/7天成交量、平均确认耗时与RPC健康状态，和队列积压情况（结果缓存60秒）
+// @Tags 运维管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.AdminStatsResponse}
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/stats [get]
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.stats.GetStats(c.Request.Context())
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+	utils.Success(c, stats)
+}
+
+// GetInternalLedger 获取内部转账对账报告
+// @Summary 获取内部转账对账报告
+// @Description 列出存在未在链上结算的内部转账净额的钱包，比较账本余额与实时链上余额：unbacked表示账本余额超过链上余额（需在链上结算后才能全部转出），drift表示余额同步落后；各链净额合计不为0时balanced为false
+// @Tags 运维管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.InternalLedgerReport}
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/internal-ledger [get]
+func (h *AdminHandler) GetInternalLedger(c *gin.Context) {
+	report, err := h.txService.InternalLedgerReport(c.Request.Context())
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+	utils.Success(c, report)
+}
+
+// ListReconciliation 获取余额对账记录
+// @Summary 获取余额对账记录
+// @Description 按开始时间倒序返回最近的余额对账（Worker定时比较数据库余额与链上余额并修正），reports为超过阈值的差异：delta_wei = 链上余额 + 内部转账净额 − 修正前的数据库余额；finished_at为空表示仍在执行或中途退出
+// @Tags 运维管理
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "返回的对账次数（默认10，最大100）"
+// @Success 200 {object} utils.Response{data=[]models.ReconciliationRun}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/admin/reconciliation [get]
+func (h *AdminHandler) ListReconciliation(c *gin.Context) {
+	// 1. 绑定查询参数
+	var req models.ReconciliationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	// 2. 调用服务层
+	runs, err := h.recon.ListRuns(c.Request.Context(), req.Limit)
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, runs)
+}
+
+// ListFeatureFlags 获取功能开关
+// @Summary 获取功能开关
+// @Description 返回所有功能开关的当前状态（default=true表示未修改或Redis不可用时的默认值）
+// @Tags 运维管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.FeatureFlag}
+// @Failure 403 {object} utils.Response
+// @Router /api/v1/admin/feature-flags [get]
+func (h *AdminHandler) ListFeatureFlags(c *gin.Context) {
+	flags, err := h.featureFlags.List(c.Request.Context())
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	utils.Success(c, flags)
+}
+
+// UpdateFeatureFlag 修改功能开关
+// @Summary 修改功能开关
+// @Description 开启或关闭功能（如transactions.send.enabled、wallets.create.enabled、maintenance.read_only），所有实例在数秒内生效，变更写入审计记录
+// @Tags 运维管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "开关名称"
+// @Param request body models.FeatureFlagUpdateRequest true "修改功能开关请求"
+// @Success 200 {object} utils.Response{data=models.FeatureFlag}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/admin/feature-flags/{name} [put]
+func (h *AdminHandler) UpdateFeatureFlag(c *gin.Context) {
+	// 1. 获取管理员ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.FeatureFlagUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	flag, err := h.featureFlags.Set(c.Request.Context(), userID.(uint), c.ClientIP(), c.Param("name"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownFeatureFlag) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "feature_flag.updated", flag)
+}
+
+// ListFeatureFlagChanges 获取功能开关变更记录
+// @Summary 获取功能开关变更记录
+// @Description 按时间倒序返回功能开关的修改记录（修改人、IP、修改前后的状态）
+// @Tags 运维管理
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "返回条数（默认100，最大500）"
+// @Success 200 {object} utils.Response{data=[]models.FeatureFlagChange}
+// @Failure 403 {object} utils.Response
+// @Router /api/v1/admin/feature-flags/changes [get]
+func (h *AdminHandler) ListFeatureFlagChanges(c *gin.Context) {
+	// 1. 绑定查询参数
+	var req models.FeatureFlagChangeListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	// 2. 调用服务层
+	changes, err := h.featureFlags.ListChanges(c.Request.Context(), req.Limit)
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, changes)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// APIKeyHandler API Key处理器
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建API Key处理器实例
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey 创建API Key
+// @Summary 创建API Key
+// @Description 创建用于机器对机器调用的API Key，完整密钥仅在本次响应中返回
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.APIKeyCreateRequest true "创建API Key请求"
+// @Success 200 {object} utils.Response{data=models.APIKeyResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/apikeys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	key, rawKey, err := h.apiKeyService.CreateKey(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应（包含完整密钥）
+	resp := key.ToResponse()
+	resp.Key = rawKey
+	utils.SuccessWithMessage(c, "api_key.created", resp)
+}
+
+// GetAPIKeys 获取API Key列表
+// @Summary 获取API Key列表
+// @Description 获取当前用户的所有API Key（不含完整密钥）
+// @Tags API Key
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.APIKeyResponse}
+// @Router /api/v1/apikeys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = key.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// DeleteAPIKey 吊销API Key
+// @Summary 吊销API Key
+// @Tags API Key
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/apikeys/{id} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	// 1. 获取用户ID和API Key ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_api_key_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "api_key.revoked", nil)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/service"
 	"crypto-wallet-api/internal/utils"
@@ -36,51 +38,58 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// 1. 绑定请求参数
 	var req models.UserCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "invalid request parameters")
+		utils.BindError(c, "request.invalid_params", err)
 		return
 	}
 
 	// 2. 调用服务层
 	user, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, err.Error(), err)
+		if errors.Is(err, service.ErrEmailTaken) || errors.Is(err, service.ErrUsernameTaken) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.InternalError(c, err)
 		return
 	}
 
 	// 3. 返回响应
-	utils.SuccessWithMessage(c, "registration successful", user.ToResponse())
+	utils.SuccessWithMessage(c, "auth.registered", user.ToResponse())
 }
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户登录获取JWT Token
+// @Description 用户登录获取JWT Token，首次从新IP或新设备登录时new_device为true并发送通知
 // @Tags 认证
 // @Accept json
 // @Produce json
 // @Param request body models.UserLoginRequest true "登录信息"
-// @Success 200 {object} utils.Response{data=map[string]interface{}}
+// @Success 200 {object} utils.Response{data=models.LoginResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response "账号已停用"
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	// 1. 绑定请求参数
 	var req models.UserLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "invalid request parameters")
+		utils.BindError(c, "request.invalid_params", err)
 		return
 	}
 
 	// 2. 调用服务层
-	token, user, err := h.authService.Login(c.Request.Context(), &req)
+	resp, err := h.authService.Login(c.Request.Context(), &req, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
-		utils.ErrorWithDetail(c, http.StatusUnauthorized, utils.CodeUnauthorized, err.Error(), err)
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			utils.ErrorWithDetail(c, http.StatusUnauthorized, utils.CodeUnauthorized, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
 		return
 	}
 
 	// 3. 返回响应（包含Token和用户信息）
-	utils.Success(c, gin.H{
-		"token": token,
-		"user":  user.ToResponse(),
-	})
+	utils.Success(c, resp)
 }
 
 // GetProfile 获取用户信息
@@ -96,17 +105,203 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	// 1. 从上下文获取用户ID（由中间件注入）
 	userID, exists := c.Get("user_id")
 	if !exists {
-		utils.Unauthorized(c, "unauthorized")
+		utils.Unauthorized(c, utils.CodeMessageKey(utils.CodeUnauthorized))
 		return
 	}
 
 	// 2. 调用服务层
 	user, err := h.authService.GetProfile(c.Request.Context(), userID.(uint))
 	if err != nil {
-		utils.NotFound(c, "user not found")
+		utils.AppError(c, err)
 		return
 	}
 
 	// 3. 返回响应
 	utils.Success(c, user.ToResponse())
 }
+
+// UpdateProfile 更新个人资料
+// @Summary 更新个人资料
+// @Description 修改当前登录用户的用户名（用户名需唯一）
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UserProfileUpdateRequest true "个人资料"
+// @Success 200 {object} utils.Response{data=models.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/v1/auth/profile [put]
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req models.UserProfileUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 2. 调用服务层
+	userID, _ := c.Get("user_id")
+	user, err := h.authService.UpdateProfile(c.Request.Context(), userID.(uint), &req, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrUsernameTaken) {
+			utils.ErrorWithDetail(c, http.StatusConflict, utils.CodeDuplicateResource, apperr.MessageKey(err), err)
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "auth.profile_updated", user.ToResponse())
+}
+
+// ChangePassword 修改密码
+// @Summary 修改密码
+// @Description 校验当前密码后修改密码，新密码需满足复杂度策略。修改后已签发的所有Token失效，响应中返回当前会话的新Token
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangePasswordRequest true "当前密码与新密码"
+// @Success 200 {object} utils.Response{data=models.ChangePasswordResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/v1/auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	// 1. 获取当前Token信息（API Key认证时不存在）
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		utils.BadRequest(c, "auth.change_password_bearer_required")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.authService.ChangePassword(c.Request.Context(), claims.(*service.TokenClaims), &req, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPassword):
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeInvalidPassword, apperr.MessageKey(err), err)
+		case errors.Is(err, service.ErrWeakPassword), errors.Is(err, service.ErrPasswordUnchanged):
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeWeakPassword, apperr.MessageKey(err), err)
+		default:
+			utils.DatabaseError(c, err)
+		}
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "auth.password_changed", resp)
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 吊销当前使用的JWT Token
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	// 1. 获取当前Token信息（API Key认证时不存在）
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		utils.BadRequest(c, "auth.logout_bearer_required")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.authService.Logout(c.Request.Context(), claims.(*service.TokenClaims)); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "auth.logged_out", nil)
+}
+
+// LogoutAll 退出所有会话
+// @Summary 退出所有会话
+// @Description 使当前用户已签发的所有JWT Token失效
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	if err := h.authService.LogoutAll(c.Request.Context(), userID.(uint)); err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "auth.sessions_revoked_all", nil)
+}
+
+// GetSessions 获取登录会话
+// @Summary 获取登录会话
+// @Description 返回最近的登录记录（IP、设备指纹、地理位置），并标记当前会话与仍然有效的会话
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.SessionResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	// 1. 获取当前Token信息（API Key认证时不存在）
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		utils.BadRequest(c, "auth.list_sessions_bearer_required")
+		return
+	}
+
+	// 2. 调用服务层
+	sessions, err := h.authService.ListSessions(c.Request.Context(), claims.(*service.TokenClaims))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, sessions)
+}
+
+// RevokeOtherSessions 下线其他会话
+// @Summary 下线其他会话
+// @Description 使当前会话以外的所有JWT Token失效，并为当前会话签发新Token（原Token同时失效）
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.RevokeSessionsResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/auth/sessions/revoke-others [post]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	// 1. 获取当前Token信息（API Key认证时不存在）
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		utils.BadRequest(c, "auth.revoke_sessions_bearer_required")
+		return
+	}
+
+	// 2. 调用服务层
+	resp, err := h.authService.RevokeOtherSessions(c.Request.Context(), claims.(*service.TokenClaims))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "auth.sessions_revoked_others", resp)
+}
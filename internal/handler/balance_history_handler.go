@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// BalanceHistoryHandler 余额历史处理器
+type BalanceHistoryHandler struct {
+	historyService *service.BalanceHistoryService
+}
+
+// NewBalanceHistoryHandler 创建余额历史处理器实例
+func NewBalanceHistoryHandler(historyService *service.BalanceHistoryService) *BalanceHistoryHandler {
+	return &BalanceHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// GetBalanceHistory 获取钱包余额曲线
+// @Summary 获取钱包余额曲线
+// @Description 按小时或天返回余额时间序列（UTC时间段起点），没有快照的时间段沿用上一个值；小时快照保留数天，更早的数据请使用天粒度
+// @Tags 钱包
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param resolution query string false "粒度：hour、day" default(hour)
+// @Param from query string false "开始时间（RFC3339），默认小时粒度为24小时前、天粒度为30天前"
+// @Param to query string false "结束时间（RFC3339），默认当前时间"
+// @Success 200 {object} utils.Response{data=models.BalanceHistoryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/balance-history [get]
+func (h *BalanceHistoryHandler) GetBalanceHistory(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定查询参数
+	var req models.BalanceHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.historyService.GetHistory(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeRange) || errors.Is(err, service.ErrTimeRangeTooLarge) {
+			utils.BadRequest(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, resp)
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ChainHandler 链发现处理器
+type ChainHandler struct {
+	chainService *service.ChainService
+}
+
+// NewChainHandler 创建链发现处理器实例
+func NewChainHandler(chainService *service.ChainService) *ChainHandler {
+	return &ChainHandler{
+		chainService: chainService,
+	}
+}
+
+// ListChains 获取已配置的链
+// @Summary 获取已配置的链
+// @Description 按配置顺序返回本部署支持的链：链ID、名称、原生币符号与精度、区块浏览器、当前能否发送交易、三档Gas价格与最新区块号。
+// @Description 客户端应以此替代硬编码的chain_id与名称映射；Gas价格与最新区块号来自缓存，暂无数据时省略。
+// @Tags 链
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.ChainInfo}
+// @Router /api/v1/chains [get]
+func (h *ChainHandler) ListChains(c *gin.Context) {
+	utils.Success(c, h.chainService.ListChains(c.Request.Context()))
+}
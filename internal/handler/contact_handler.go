@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ContactHandler 地址簿处理器
+type ContactHandler struct {
+	contactService *service.ContactService
+}
+
+// NewContactHandler 创建地址簿处理器实例
+func NewContactHandler(contactService *service.ContactService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+	}
+}
+
+// CreateContact 创建联系人
+// @Summary 创建联系人
+// @Description 在地址簿中保存常用收款地址（同一链上地址唯一），地址可填写ENS名称（保存解析后的地址）
+// @Tags 地址簿
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ContactCreateRequest true "创建联系人请求"
+// @Success 200 {object} utils.Response{data=models.ContactResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contacts [post]
+func (h *ContactHandler) CreateContact(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.ContactCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	contact, err := h.contactService.CreateContact(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrENSResolution) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeENSResolutionFailed, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "contact.created", contact.ToResponse())
+}
+
+// GetContacts 获取联系人列表
+// @Summary 获取联系人列表
+// @Description 获取当前用户地址簿中的所有联系人
+// @Tags 地址簿
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.ContactResponse}
+// @Router /api/v1/contacts [get]
+func (h *ContactHandler) GetContacts(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	contacts, err := h.contactService.ListContacts(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.ContactResponse, len(contacts))
+	for i, contact := range contacts {
+		responses[i] = contact.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// GetContact 获取联系人详情
+// @Summary 获取联系人详情
+// @Tags 地址簿
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "联系人ID"
+// @Success 200 {object} utils.Response{data=models.ContactResponse}
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/contacts/{id} [get]
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	// 1. 获取用户ID和联系人ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_contact_id")
+		return
+	}
+
+	// 2. 调用服务层
+	contact, err := h.contactService.GetContact(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, contact.ToResponse())
+}
+
+// UpdateContact 更新联系人
+// @Summary 更新联系人
+// @Tags 地址簿
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "联系人ID"
+// @Param request body models.ContactUpdateRequest true "更新联系人请求"
+// @Success 200 {object} utils.Response{data=models.ContactResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/contacts/{id} [put]
+func (h *ContactHandler) UpdateContact(c *gin.Context) {
+	// 1. 获取用户ID和联系人ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_contact_id")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.ContactUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	contact, err := h.contactService.UpdateContact(c.Request.Context(), userID.(uint), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrENSResolution) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeENSResolutionFailed, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "contact.updated", contact.ToResponse())
+}
+
+// DeleteContact 删除联系人
+// @Summary 删除联系人
+// @Tags 地址簿
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "联系人ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/contacts/{id} [delete]
+func (h *ContactHandler) DeleteContact(c *gin.Context) {
+	// 1. 获取用户ID和联系人ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_contact_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.contactService.DeleteContact(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "contact.deleted", nil)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ContractHandler 合约交互处理器
+type ContractHandler struct {
+	contractService *service.ContractService
+}
+
+// NewContractHandler 创建合约交互处理器实例
+func NewContractHandler(contractService *service.ContractService) *ContractHandler {
+	return &ContractHandler{
+		contractService: contractService,
+	}
+}
+
+// Call 合约只读调用
+// @Summary 合约只读调用
+// @Description 通过eth_call读取合约状态（如allowance、symbol），参数按ABI编码，返回值解码为JSON
+// @Tags 合约
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ContractCallRequest true "合约调用请求"
+// @Success 200 {object} utils.Response{data=models.ContractCallResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Failure 504 {object} utils.Response "链节点或Redis在请求时限内未响应（code=10026）"
+// @Router /api/v1/contracts/call [post]
+func (h *ContractHandler) Call(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req models.ContractCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 2. 调用服务层
+	resp, err := h.contractService.Call(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidContractCall) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.BlockchainError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, resp)
+}
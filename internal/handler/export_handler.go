@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ExportHandler 交易导出处理器
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler 创建交易导出处理器实例
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportTransactions 导出交易记录
+// @Summary 导出交易记录
+// @Description 以CSV或JSON格式流式导出用户的全部交易记录（转出与转入），用于对账与报税
+// @Tags 交易
+// @Produce text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "导出格式" Enums(csv, json) default(csv)
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param chain_id query int false "链ID"
+// @Param wallet_address query string false "钱包地址"
+// @Param direction query string false "方向：outgoing为钱包发送的交易，incoming为代币入账与转入钱包的交易" Enums(incoming, outgoing)
+// @Param min_amount query string false "金额下限（含，主单位，按数值比较）"
+// @Param max_amount query string false "金额上限（含，主单位，按数值比较）"
+// @Param counterparty query string false "对手方地址（交易的发送方或接收方）"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=export）"
+// @Router /api/v1/transactions/export [get]
+func (h *ExportHandler) ExportTransactions(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定查询参数
+	var req models.TransactionExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+	if req.Format == "" {
+		req.Format = "csv"
+	}
+
+	// 3. 校验请求（开始写入响应后无法再返回错误状态码）
+	filter, err := h.exportService.PrepareExport(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, "error.wallet_not_found")
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 设置下载响应头
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == "json" {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().UTC().Format("20060102"), req.Format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// 导出耗时可能超过服务器写超时，取消本次响应的写截止时间
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	// 5. 流式写出
+	if err := h.exportService.ExportTransactions(c.Request.Context(), filter, req.Format, c.Writer); err != nil {
+		logger.WithCtx(c.Request.Context()).Error("transaction export interrupted", zap.Error(err))
+		c.Abort()
+	}
+}
+
+// CreateExportJob 创建异步导出任务
+// @Summary 创建异步导出任务
+// @Description 校验参数并估算行数后创建导出任务，由Worker生成文件；通过GET /api/v1/exports/{id}查询状态，完成后响应中附带一次性下载链接（浏览器可直接打开，无需Authorization头）
+// @Tags 交易
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ExportJobRequest true "导出请求"
+// @Success 202 {object} utils.Response{data=models.ExportJobResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=export）"
+// @Router /api/v1/transactions/export [post]
+func (h *ExportHandler) CreateExportJob(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.ExportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	job, err := h.exportService.CreateJob(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, "error.wallet_not_found")
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Accepted(c, "export.queued", job)
+}
+
+// GetExportJob 查询导出任务
+// @Summary 查询导出任务
+// @Description 查询导出任务状态（pending、processing、completed、failed、expired）；completed且文件未过期时返回download_url，链接只能使用一次，过期或使用后重新查询获取新链接
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "任务ID"
+// @Success 200 {object} utils.Response{data=models.ExportJobResponse}
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/exports/{id} [get]
+func (h *ExportHandler) GetExportJob(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	job, err := h.exportService.GetJob(c.Request.Context(), userID.(uint), c.Param("id"))
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	c.Header("Cache-Control", "no-store")
+	utils.Success(c, job)
+}
+
+// DownloadExport 下载导出文件
+// @Summary 下载导出文件
+// @Description 凭查询任务时返回的一次性令牌下载导出文件，无需Authorization头；令牌只对发起导出的用户签发，使用一次后失效
+// @Tags 交易
+// @Produce text/csv
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param token query string true "下载令牌"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.Response "令牌无效、已过期或已使用"
+// @Failure 409 {object} utils.Response "文件尚未生成或已过期"
+// @Router /api/v1/exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	// 1. 校验令牌并打开文件
+	job, file, err := h.exportService.OpenDownload(c.Request.Context(), c.Param("id"), c.Query("token"))
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// 2. 设置下载响应头（令牌只能使用一次，禁止缓存）
+	contentType := "text/csv; charset=utf-8"
+	if job.Format == "json" {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName()))
+	c.Header("Cache-Control", "no-store")
+
+	// 大文件下载耗时可能超过服务器写超时，取消本次响应的写截止时间
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	// 3. 写出文件
+	modTime := job.UpdatedAt
+	if job.CompletedAt != nil {
+		modTime = *job.CompletedAt
+	}
+	http.ServeContent(c.Writer, c.Request, job.FileName(), modTime, file)
+}
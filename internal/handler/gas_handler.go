@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// GasHandler Gas价格处理器
+type GasHandler struct {
+	gasOracle *service.GasOracle
+}
+
+// NewGasHandler 创建Gas价格处理器实例
+func NewGasHandler(gasOracle *service.GasOracle) *GasHandler {
+	return &GasHandler{
+		gasOracle: gasOracle,
+	}
+}
+
+// GetGasPrices 获取Gas价格档位
+// @Summary 获取Gas价格档位
+// @Description 返回slow、standard、fast三档Gas价格及按gas_limit计算的预估费用，发起转账时可通过speed选择档位
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Param chain_id query int true "链ID"
+// @Param gas_limit query int false "预估费用使用的gas用量（默认为链的原生币转账gas用量，通常为21000）"
+// @Success 200 {object} utils.Response{data=models.GasPriceResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Router /api/v1/gas-prices [get]
+func (h *GasHandler) GetGasPrices(c *gin.Context) {
+	// 1. 绑定查询参数
+	var req models.GasPriceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 2. 调用服务层
+	resp, err := h.gasOracle.Estimate(c.Request.Context(), req.ChainID, req.GasLimit)
+	if err != nil {
+		if errors.Is(err, service.ErrGasChainUnsupported) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.BlockchainError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, resp)
+}
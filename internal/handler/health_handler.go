@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/queue"
+)
+
+// readinessTimeout 就绪检查的总超时时间
+const readinessTimeout = 3 * time.Second
+
+// HealthHandler 健康检查处理器
+type HealthHandler struct {
+	db          *gorm.DB
+	cache       *cache.RedisCache
+	queue       *queue.RabbitMQ
+	chains      *blockchain.Clients
+	chainHealth *service.ChainHealthMonitor
+}
+
+// NewHealthHandler 创建健康检查处理器实例
+func NewHealthHandler(
+	db *gorm.DB,
+	cache *cache.RedisCache,
+	queue *queue.RabbitMQ,
+	chains *blockchain.Clients,
+	chainHealth *service.ChainHealthMonitor,
+) *HealthHandler {
+	return &HealthHandler{
+		db:          db,
+		cache:       cache,
+		queue:       queue,
+		chains:      chains,
+		chainHealth: chainHealth,
+	}
+}
+
+// dependencyStatus 单个依赖的检查结果（就绪检查无需认证，错误详情只记录在日志中，不返回给调用方）
+type dependencyStatus struct {
+	Status    string `json:"status"` // up, down
+	LatencyMs int64  `json:"latency_ms"`
+	err       error
+}
+
+// Live 存活检查（进程可响应即可）
+// @Summary 存活检查
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"time":   time.Now().Unix(),
+	})
+}
+
+// Ready 就绪检查（并行检查所有依赖与每条连接了节点的链，任一失败返回503；链头长时间未推进同样视为失败）
+// @Summary 就绪检查
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := map[string]func(ctx context.Context) error{
+		"database": func(ctx context.Context) error {
+			sqlDB, err := h.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+		"redis": h.cache.Ping,
+		"rabbitmq": func(ctx context.Context) error {
+			if !h.queue.IsConnected() {
+				return errors.New("not connected")
+			}
+			return nil
+		},
+	}
+	for _, client := range h.chains.All() {
+		chainID := client.GetChainID()
+		checks[fmt.Sprintf("blockchain:%d", chainID)] = func(ctx context.Context) error {
+			_, err := client.GetBlockNumber(ctx)
+			return err
+		}
+		checks[fmt.Sprintf("chain_head:%d", chainID)] = func(ctx context.Context) error {
+			return h.chainHealth.CheckSendable(chainID)
+		}
+	}
+
+	// 并行执行检查
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]*dependencyStatus, len(checks))
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			result := runCheck(ctx, check)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	// 汇总结果（失败原因记录日志）
+	status, httpStatus := "ok", http.StatusOK
+	for name, result := range results {
+		if result.Status != "up" {
+			status, httpStatus = "unavailable", http.StatusServiceUnavailable
+			logger.WithCtx(c.Request.Context()).Warn("readiness check failed",
+				zap.String("check", name),
+				zap.Error(result.err),
+			)
+		}
+	}
+
+	// 链头状态不返回节点错误信息（可能包含节点地址）
+	chains := h.chainHealth.Statuses()
+	for _, chain := range chains {
+		chain.LastError = ""
+	}
+
+	c.JSON(httpStatus, gin.H{
+		"status":       status,
+		"time":         time.Now().Unix(),
+		"dependencies": results,
+		"chains":       chains,
+	})
+}
+
+// runCheck 执行单个检查，超过截止时间视为失败
+func runCheck(ctx context.Context, check func(ctx context.Context) error) *dependencyStatus {
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := &dependencyStatus{
+		Status:    "up",
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = "down"
+		result.err = err
+	}
+	return result
+}
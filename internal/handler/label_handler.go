@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// LabelHandler 地址标签处理器
+type LabelHandler struct {
+	labelService *service.LabelService
+}
+
+// NewLabelHandler 创建地址标签处理器实例
+func NewLabelHandler(labelService *service.LabelService) *LabelHandler {
+	return &LabelHandler{
+		labelService: labelService,
+	}
+}
+
+// GetLabel 查询地址标签
+// @Summary 查询地址标签
+// @Description 查询地址在各链上的公共标签（交易所、DeFi合约、代币合约），地址不区分大小写；没有公共标签时返回当前用户地址簿中的联系人（category为contact）
+// @Tags 地址标签
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "地址"
+// @Param chain_id query int false "链ID（不传时返回所有链上的标签）"
+// @Success 200 {object} utils.Response{data=[]models.AddressLabelResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/labels/{address} [get]
+func (h *LabelHandler) GetLabel(c *gin.Context) {
+	// 1. 获取用户ID与地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定查询参数
+	var req models.AddressLabelLookupRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 调用服务层
+	labels, err := h.labelService.Lookup(c.Request.Context(), userID.(uint), address, req.ChainID)
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, labels)
+}
+
+// ImportLabels 导入地址标签
+// @Summary 导入地址标签
+// @Description 批量导入公共地址标签（每次最多1000条，分类为exchange、defi或token contract），已存在的（地址, 链）覆盖名称与分类；导入的标签在启动同步内置数据集时不会被覆盖
+// @Tags 运维管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AddressLabelImportRequest true "导入地址标签请求"
+// @Success 200 {object} utils.Response{data=models.AddressLabelImportResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/v1/admin/labels/import [post]
+func (h *LabelHandler) ImportLabels(c *gin.Context) {
+	// 1. 获取管理员ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.AddressLabelImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	result, err := h.labelService.Import(c.Request.Context(), userID.(uint), c.ClientIP(), &req)
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "label.imported", result)
+}
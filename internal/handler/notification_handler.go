@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// NotificationHandler 通知处理器
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler 创建通知处理器实例
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications 获取站内通知
+// @Summary 获取站内通知
+// @Description 按时间倒序分页返回站内通知，并返回未读总数
+// @Tags 通知
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "仅返回未读通知"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=models.NotificationListResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定查询参数
+	var req models.NotificationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.notificationService.List(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, resp)
+}
+
+// MarkRead 标记通知为已读
+// @Summary 标记通知为已读
+// @Tags 通知
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "通知ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	// 1. 获取用户ID和通知ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_notification_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.notificationService.MarkRead(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "notification.marked_read", nil)
+}
+
+// GetPreferences 获取通知偏好
+// @Summary 获取通知偏好
+// @Description 返回全部通知类型的偏好，未设置的类型为默认值（启用、仅站内通知）
+// @Tags 通知
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.NotificationPreference}
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, prefs)
+}
+
+// UpdatePreferences 更新通知偏好
+// @Summary 更新通知偏好
+// @Description 按通知类型设置是否启用与站外渠道（none仅站内、email、webhook），只更新请求中列出的类型；关闭的类型不再记录站内通知
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.NotificationPreferencesRequest true "通知偏好"
+// @Success 200 {object} utils.Response{data=[]models.NotificationPreference}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	prefs, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookURLRequired) {
+			utils.BadRequest(c, apperr.MessageKey(err))
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "notification.preferences_updated", prefs)
+}
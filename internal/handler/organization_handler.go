@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// OrganizationHandler 组织处理器
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationHandler 创建组织处理器实例
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// CreateOrg 创建组织
+// @Summary 创建组织
+// @Description 创建团队组织，创建者成为owner；组织钱包由成员按角色共享（viewer只读、member限额内转账、admin管理钱包设置、owner管理成员）
+// @Tags 组织
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.OrgCreateRequest true "创建组织请求"
+// @Success 200 {object} utils.Response{data=models.OrgResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/orgs [post]
+func (h *OrganizationHandler) CreateOrg(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.OrgCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	membership, err := h.orgService.CreateOrg(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "organization.created", membership.ToResponse())
+}
+
+// GetOrgs 获取组织列表
+// @Summary 获取组织列表
+// @Description 获取当前用户加入的所有组织及其角色
+// @Tags 组织
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.OrgResponse}
+// @Router /api/v1/orgs [get]
+func (h *OrganizationHandler) GetOrgs(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	memberships, err := h.orgService.ListOrgs(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.OrgResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = membership.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// GetOrg 获取组织详情
+// @Summary 获取组织详情
+// @Tags 组织
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Success 200 {object} utils.Response{data=models.OrgResponse}
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id} [get]
+func (h *OrganizationHandler) GetOrg(c *gin.Context) {
+	// 1. 获取用户ID和组织ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	membership, err := h.orgService.GetOrg(c.Request.Context(), userID.(uint), orgID)
+	if err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, membership.ToResponse())
+}
+
+// UpdateOrg 更新组织
+// @Summary 更新组织
+// @Description 修改组织名称与member角色的单笔转账上限（Wei，空字符串或0表示不限），需要admin及以上角色
+// @Tags 组织
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Param request body models.OrgUpdateRequest true "更新组织请求"
+// @Success 200 {object} utils.Response{data=models.OrgResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id} [put]
+func (h *OrganizationHandler) UpdateOrg(c *gin.Context) {
+	// 1. 获取用户ID和组织ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.OrgUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	membership, err := h.orgService.UpdateOrg(c.Request.Context(), userID.(uint), orgID, &req)
+	if err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "organization.updated", membership.ToResponse())
+}
+
+// DeleteOrg 删除组织
+// @Summary 删除组织
+// @Description 删除组织（仅owner，组织下仍有钱包时不能删除）
+// @Tags 组织
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id} [delete]
+func (h *OrganizationHandler) DeleteOrg(c *gin.Context) {
+	// 1. 获取用户ID和组织ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.orgService.DeleteOrg(c.Request.Context(), userID.(uint), orgID); err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "organization.deleted", nil)
+}
+
+// GetMembers 获取组织成员
+// @Summary 获取组织成员
+// @Tags 组织
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Success 200 {object} utils.Response{data=[]models.OrgMemberResponse}
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id}/members [get]
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	// 1. 获取用户ID和组织ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	memberships, err := h.orgService.ListMembers(c.Request.Context(), userID.(uint), orgID)
+	if err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.OrgMemberResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = membership.ToMemberResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// AddMember 添加组织成员
+// @Summary 添加组织成员
+// @Description 将已注册用户加入组织并指定角色（仅owner）
+// @Tags 组织
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Param request body models.OrgMemberRequest true "添加成员请求"
+// @Success 200 {object} utils.Response{data=models.OrgMemberResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id}/members [post]
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	// 1. 获取用户ID和组织ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.OrgMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	membership, err := h.orgService.AddMember(c.Request.Context(), userID.(uint), orgID, &req)
+	if err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "organization.member_added", membership.ToMemberResponse())
+}
+
+// UpdateMember 修改成员角色
+// @Summary 修改成员角色
+// @Description 修改组织成员的角色（仅owner，组织至少保留一名owner）
+// @Tags 组织
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Param user_id path int true "成员用户ID"
+// @Param request body models.OrgMemberUpdateRequest true "修改角色请求"
+// @Success 200 {object} utils.Response{data=models.OrgMemberResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id}/members/{user_id} [put]
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	// 1. 获取用户ID、组织ID和成员ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_user_id")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.OrgMemberUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	membership, err := h.orgService.UpdateMember(c.Request.Context(), userID.(uint), orgID, uint(memberID), &req)
+	if err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "organization.member_updated", membership.ToMemberResponse())
+}
+
+// RemoveMember 移除组织成员
+// @Summary 移除组织成员
+// @Description owner可移除任意成员，其他成员可移除自己以退出组织（组织至少保留一名owner）
+// @Tags 组织
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "组织ID"
+// @Param user_id path int true "成员用户ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/orgs/{id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	// 1. 获取用户ID、组织ID和成员ID
+	userID, _ := c.Get("user_id")
+	orgID, ok := parseOrgID(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_user_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.orgService.RemoveMember(c.Request.Context(), userID.(uint), orgID, uint(memberID)); err != nil {
+		orgError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "organization.member_removed", nil)
+}
+
+// parseOrgID 解析路径中的组织ID，失败时直接返回400
+func parseOrgID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_organization_id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// orgError 将组织相关的业务错误映射为对应的响应码（未识别的错误按数据库错误处理）
+func orgError(c *gin.Context, err error) {
+	if _, ok := apperr.Message(err); ok {
+		utils.AppError(c, err)
+		return
+	}
+	utils.DatabaseError(c, err)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// addressParam 读取路径中的钱包地址，格式错误时直接返回400（路径参数不经过binding的eth_addr校验）
+func addressParam(c *gin.Context) (string, bool) {
+	address := c.Param("address")
+	if !utils.IsEthAddress(address) {
+		utils.BadRequest(c, "request.invalid_address")
+		return "", false
+	}
+	return address, true
+}
+
+// txHashParam 读取路径中的交易哈希（链上哈希或内部转账哈希），格式错误时直接返回400，不再查询存储
+func txHashParam(c *gin.Context) (string, bool) {
+	txHash := c.Param("tx_hash")
+	if !service.IsTransactionHash(txHash) {
+		utils.BadRequest(c, "request.invalid_tx_hash")
+		return "", false
+	}
+	return txHash, true
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// RecurringPaymentHandler 定期转账处理器
+type RecurringPaymentHandler struct {
+	paymentService *service.RecurringPaymentService
+}
+
+// NewRecurringPaymentHandler 创建定期转账处理器实例
+func NewRecurringPaymentHandler(paymentService *service.RecurringPaymentService) *RecurringPaymentHandler {
+	return &RecurringPaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// CreatePayment 创建定期转账计划
+// @Summary 创建定期转账计划
+// @Description 按daily、weekly、monthly或自定义间隔（如36h）定期转账，可设置结束时间或执行次数
+// @Tags 定期转账
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RecurringPaymentCreateRequest true "创建定期转账请求"
+// @Success 200 {object} utils.Response{data=models.RecurringPaymentResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/recurring-payments [post]
+func (h *RecurringPaymentHandler) CreatePayment(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.RecurringPaymentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	payment, err := h.paymentService.CreatePayment(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrZeroAddress) || errors.Is(err, service.ErrSelfTransfer) || errors.Is(err, service.ErrInvalidAmount) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "recurring_payment.created", payment.ToResponse())
+}
+
+// GetPayments 获取定期转账计划列表
+// @Summary 获取定期转账计划列表
+// @Tags 定期转账
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.RecurringPaymentResponse}
+// @Router /api/v1/recurring-payments [get]
+func (h *RecurringPaymentHandler) GetPayments(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	payments, err := h.paymentService.ListPayments(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.RecurringPaymentResponse, len(payments))
+	for i, payment := range payments {
+		responses[i] = payment.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// GetPayment 获取定期转账计划详情
+// @Summary 获取定期转账计划详情
+// @Tags 定期转账
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "计划ID"
+// @Success 200 {object} utils.Response{data=models.RecurringPaymentResponse}
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/recurring-payments/{id} [get]
+func (h *RecurringPaymentHandler) GetPayment(c *gin.Context) {
+	// 1. 获取用户ID和计划ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_recurring_payment_id")
+		return
+	}
+
+	// 2. 调用服务层
+	payment, err := h.paymentService.GetPayment(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, payment.ToResponse())
+}
+
+// UpdatePayment 更新定期转账计划
+// @Summary 更新定期转账计划
+// @Description 修改金额、周期、结束条件，或通过status暂停（paused）/恢复（active）
+// @Tags 定期转账
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "计划ID"
+// @Param request body models.RecurringPaymentUpdateRequest true "更新定期转账请求"
+// @Success 200 {object} utils.Response{data=models.RecurringPaymentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/recurring-payments/{id} [put]
+func (h *RecurringPaymentHandler) UpdatePayment(c *gin.Context) {
+	// 1. 获取用户ID和计划ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_recurring_payment_id")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.RecurringPaymentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	payment, err := h.paymentService.UpdatePayment(c.Request.Context(), userID.(uint), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrZeroAddress) || errors.Is(err, service.ErrSelfTransfer) || errors.Is(err, service.ErrInvalidAmount) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "recurring_payment.updated", payment.ToResponse())
+}
+
+// DeletePayment 取消定期转账计划
+// @Summary 取消定期转账计划
+// @Description 取消后不再执行，已执行的交易仍保留与计划的关联
+// @Tags 定期转账
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "计划ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/recurring-payments/{id} [delete]
+func (h *RecurringPaymentHandler) DeletePayment(c *gin.Context) {
+	// 1. 获取用户ID和计划ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_recurring_payment_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.paymentService.CancelPayment(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "recurring_payment.cancelled", nil)
+}
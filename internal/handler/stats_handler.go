@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// StatsHandler 统计处理器
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+// NewStatsHandler 创建统计处理器实例
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetTransactionStats 获取交易统计
+// @Summary 获取交易统计
+// @Description 按天统计转出/转入金额、Gas花费与各状态交易数量；方向、金额与对手方筛选同时作用于转出统计与转入金额（钱包之间的互转同时属于两个方向）
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Param chain_id query int false "链ID"
+// @Param wallet_address query string false "钱包地址"
+// @Param direction query string false "方向：outgoing为钱包发送的交易，incoming为代币入账与转入钱包的交易" Enums(incoming, outgoing)
+// @Param min_amount query string false "金额下限（含，主单位，按数值比较）"
+// @Param max_amount query string false "金额上限（含，主单位，按数值比较）"
+// @Param counterparty query string false "对手方地址（交易的发送方或接收方）"
+// @Success 200 {object} utils.Response{data=models.TransactionStatsResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/stats/transactions [get]
+func (h *StatsHandler) GetTransactionStats(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定查询参数
+	var req models.TransactionStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.statsService.GetTransactionStats(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, resp)
+}
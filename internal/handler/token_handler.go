@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// TokenHandler 代币处理器
+type TokenHandler struct {
+	tokenService *service.TokenService
+}
+
+// NewTokenHandler 创建代币处理器实例
+func NewTokenHandler(tokenService *service.TokenService) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// WatchToken 关注代币
+// @Summary 关注代币
+// @Description 将代币加入关注列表，未收录的代币会从合约读取symbol、name、decimals
+// @Tags 代币
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TokenWatchRequest true "关注代币请求"
+// @Success 200 {object} utils.Response{data=models.TokenResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/tokens/watch [post]
+func (h *TokenHandler) WatchToken(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.TokenWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	token, err := h.tokenService.WatchToken(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedChain) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		if errors.Is(err, service.ErrTokenMetadata) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(service.ErrTokenMetadata), err)
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "token.watched", token.ToResponse())
+}
+
+// UnwatchToken 取消关注代币
+// @Summary 取消关注代币
+// @Tags 代币
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TokenWatchRequest true "取消关注代币请求"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/tokens/watch [delete]
+func (h *TokenHandler) UnwatchToken(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.TokenWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	if err := h.tokenService.UnwatchToken(c.Request.Context(), userID.(uint), &req); err != nil {
+		if err.Error() == "token not watched" {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "token.unwatched", nil)
+}
+
+// GetWatchlist 获取关注的代币列表
+// @Summary 获取关注的代币列表
+// @Tags 代币
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.TokenResponse}
+// @Router /api/v1/tokens/watch [get]
+func (h *TokenHandler) GetWatchlist(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	tokens, err := h.tokenService.ListWatchlist(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.TokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = token.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// GetWalletTokens 获取钱包的代币余额
+// @Summary 获取钱包代币余额
+// @Description 返回钱包所在链上所有关注代币的余额，单个代币查询失败时该项携带error
+// @Tags 代币
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Success 200 {object} utils.Response{data=models.WalletTokensResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Router /api/v1/wallets/{address}/tokens [get]
+func (h *TokenHandler) GetWalletTokens(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	resp, err := h.tokenService.GetWalletTokens(c.Request.Context(), userID.(uint), address)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedChain) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, resp)
+}
@@ -1,8 +1,14 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/service"
 	"crypto-wallet-api/internal/utils"
@@ -22,7 +28,7 @@ func NewTransactionHandler(txService *service.TransactionService) *TransactionHa
 
 // SendTransaction 发起转账
 // @Summary 发起转账
-// @Description 创建并发送区块链转账交易
+// @Description 创建并发送区块链转账交易，省略from_address时使用当前用户在chain_id上的默认钱包，收款方可通过to_address（地址或ENS名称）或地址簿contact_id指定，speed可选slow/standard/fast（价格见/gas-prices）。internal=true且收款方为当前用户可访问的同链钱包时作为内部转账处理：只移动双方账本余额，不上链、不消耗gas，交易type为internal。响应中的max_fee_*与total_max_cost_*为服务端按整数精确计算的最高手续费与最多扣除总额，最终确认后查询交易可得到actual_fee_*
 // @Tags 交易
 // @Accept json
 // @Produce json
@@ -30,6 +36,20 @@ func NewTransactionHandler(txService *service.TransactionService) *TransactionHa
 // @Param request body models.TransactionCreateRequest true "转账请求"
 // @Success 200 {object} utils.Response{data=models.TransactionResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "收款地址不在白名单中（code=10010）"
+// @Failure 403 {object} utils.Response{data=models.DailyLimitExceededData} "超出每日限额（code=10011）"
+// @Failure 400 {object} utils.Response "钱包设置了口令但未提供（code=10012）"
+// @Failure 400 {object} utils.Response "ENS名称无法解析（code=10018）"
+// @Failure 400 {object} utils.Response "内部转账账本余额不足（code=10008）"
+// @Failure 400 {object} utils.Response "收款地址为零地址（code=10014）或发送钱包自身（code=10015）"
+// @Failure 403 {object} utils.Response "钱包口令错误（code=10013）"
+// @Failure 400 {object} utils.Response "未指定from_address且该链上没有默认钱包"
+// @Failure 404 {object} utils.Response "发送钱包不存在或无权访问"
+// @Failure 409 {object} utils.Response "发送钱包已归档（code=10020）"
+// @Failure 403 {object} utils.Response "发送钱包所在的链被本部署禁用（code=10025）"
+// @Failure 503 {object} utils.Response "链节点不健康，暂停发送（code=10021）"
+// @Success 202 {object} utils.Response{data=models.TransactionResponse} "金额超过审批阈值，交易等待审批（status=awaiting_approval）"
+// @Failure 504 {object} utils.Response "广播前的链节点查询超时（code=10026）；交易记录保存后广播超时不返回错误，交易保持pending由监听任务确认"
 // @Router /api/v1/transactions [post]
 func (h *TransactionHandler) SendTransaction(c *gin.Context) {
 	// 1. 获取用户ID
@@ -38,19 +58,242 @@ func (h *TransactionHandler) SendTransaction(c *gin.Context) {
 	// 2. 绑定请求参数
 	var req models.TransactionCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "invalid request parameters")
+		utils.BindError(c, "request.invalid_params", err)
 		return
 	}
 
 	// 3. 调用服务层
 	tx, err := h.txService.SendTransaction(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
-		utils.BlockchainError(c, err)
+		sendError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	if tx.Status == models.TxStatusAwaitingApproval {
+		utils.Accepted(c, "transaction.awaiting_approval", h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+		return
+	}
+	utils.SuccessWithMessage(c, "transaction.sent", h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+}
+
+// SendContractTransaction 调用合约写方法
+// @Summary 调用合约写方法
+// @Description 通过托管钱包签名并广播合约调用（如approve、stake、mint），calldata按ABI或方法签名编码
+// @Tags 交易
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ContractTransactionRequest true "合约调用请求"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response "发送钱包不存在或无权访问"
+// @Failure 409 {object} utils.Response "发送钱包已归档（code=10020）"
+// @Failure 403 {object} utils.Response "发送钱包所在的链被本部署禁用（code=10025）"
+// @Failure 503 {object} utils.Response "链节点不健康，暂停发送（code=10021）"
+// @Router /api/v1/transactions/contract [post]
+func (h *TransactionHandler) SendContractTransaction(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.ContractTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	tx, err := h.txService.SendContractTransaction(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidContractCall) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		sendError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "transaction.sent", h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+}
+
+// SweepWallet 清空钱包余额
+// @Summary 清空钱包余额
+// @Description 将钱包的全部原生币余额扣除网络费用后转出到指定地址，金额按链上最新余额精确计算（余额 − gasLimit × gasPrice），广播后钱包余额为0。计算后余额发生变化导致节点拒绝时自动按最新余额重试一次
+// @Tags 交易
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WalletSweepRequest true "清空余额请求"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 400 {object} utils.Response "余额不足以支付网络费用（code=10008）"
+// @Failure 403 {object} utils.Response "收款地址不在白名单中（code=10010）或金额超过审批阈值（code=10016）"
+// @Failure 403 {object} utils.Response{data=models.DailyLimitExceededData} "超出每日限额（code=10011）"
+// @Failure 404 {object} utils.Response "钱包不存在或无权访问"
+// @Failure 409 {object} utils.Response "钱包已归档（code=10020）"
+// @Failure 503 {object} utils.Response "链节点不健康，暂停发送（code=10021）"
+// @Router /api/v1/wallets/{address}/sweep [post]
+func (h *TransactionHandler) SweepWallet(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WalletSweepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	tx, err := h.txService.SweepWallet(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrNothingToSweep) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInsufficientBalance, apperr.MessageKey(err), err)
+			return
+		}
+		sendError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.swept", h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+}
+
+// SimulateTransaction 模拟发送交易
+// @Summary 模拟发送交易
+// @Description 按发送交易的规则构建交易但不签名、不保存。提供method时为合约调用：以钱包为发送方通过eth_call在最新区块执行，返回是否回滚、回滚原因（Error(string)/Panic(uint256)解码）与gas估算；否则按普通转账只校验地址、金额与余额。计入按用户的expensive限流，不计入每日限额
+// @Tags 交易
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TransactionSimulateRequest true "模拟请求"
+// @Success 200 {object} utils.Response{data=models.TransactionSimulateResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response "发送钱包不存在或无权访问"
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Router /api/v1/transactions/simulate [post]
+func (h *TransactionHandler) SimulateTransaction(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.TransactionSimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	result, err := h.txService.SimulateTransaction(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidContractCall) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		sendError(c, err)
 		return
 	}
 
 	// 4. 返回响应
-	utils.SuccessWithMessage(c, "transaction sent successfully", tx.ToResponse())
+	utils.Success(c, result)
+}
+
+// sendError 将发送交易的业务错误映射为对应的响应码
+func sendError(c *gin.Context, err error) {
+	var limitErr *service.DailyLimitExceededError
+	if errors.As(err, &limitErr) {
+		utils.ErrorWithData(c, http.StatusForbidden, utils.CodeDailyLimitExceeded, utils.CodeMessageKey(utils.CodeDailyLimitExceeded), limitErr.Data)
+		return
+	}
+	var disabledErr *service.FeatureDisabledError
+	if errors.As(err, &disabledErr) {
+		utils.ErrorWithData(c, http.StatusServiceUnavailable, utils.CodeFeatureDisabled, apperr.MessageKey(service.ErrFeatureDisabled), disabledErr.Data)
+		return
+	}
+	if errors.Is(err, service.ErrWalletNotFound) {
+		utils.NotFound(c, apperr.MessageKey(err))
+		return
+	}
+	if errors.Is(err, service.ErrChainUnhealthy) {
+		utils.ErrorWithDetail(c, http.StatusServiceUnavailable, utils.CodeChainUnhealthy, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrWalletArchived) {
+		utils.ErrorWithDetail(c, http.StatusConflict, utils.CodeWalletArchived, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrChainNotAllowed) {
+		utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeChainNotAllowed, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrAddressNotWhitelisted) {
+		utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeAddressNotWhitelisted, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrZeroAddress) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeZeroAddress, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrENSResolution) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeENSResolutionFailed, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrSelfTransfer) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeSelfTransfer, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrInternalRecipient) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrInsufficientBalance) || errors.Is(err, service.ErrInsufficientLedgerBalance) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInsufficientBalance, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidAmount) || errors.Is(err, service.ErrNoDefaultWallet) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrPassphraseRequired) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodePassphraseRequired, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidPassphrase) {
+		utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeInvalidPassphrase, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrApprovalRequired) {
+		utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeApprovalRequired, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidApprovalPolicy) {
+		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+		return
+	}
+	if errors.Is(err, service.ErrPermissionDenied) || errors.Is(err, service.ErrMemberSendLimitExceeded) {
+		utils.Forbidden(c, apperr.MessageKey(err))
+		return
+	}
+	// 其他按类别定义的业务错误（如链ID不匹配）是请求本身的问题，不是链交互失败
+	if isCategorized(err) {
+		utils.AppError(c, err)
+		return
+	}
+	utils.BlockchainError(c, err)
+}
+
+// isCategorized 错误是否为按类别（不存在、参数不合法、冲突、无权限）定义的业务错误
+func isCategorized(err error) bool {
+	return errors.Is(err, apperr.ErrNotFound) || errors.Is(err, apperr.ErrInvalid) ||
+		errors.Is(err, apperr.ErrConflict) || errors.Is(err, apperr.ErrForbidden)
 }
 
 // GetTransaction 获取交易详情
@@ -61,37 +304,199 @@ func (h *TransactionHandler) SendTransaction(c *gin.Context) {
 // @Security BearerAuth
 // @Param tx_hash path string true "交易哈希"
 // @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /api/v1/transactions/{tx_hash} [get]
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	// 1. 获取用户ID和交易哈希
 	userID, _ := c.Get("user_id")
-	txHash := c.Param("tx_hash")
+	txHash, ok := txHashParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 调用服务层
 	tx, err := h.txService.GetTransaction(c.Request.Context(), userID.(uint), txHash)
 	if err != nil {
-		utils.NotFound(c, "transaction not found")
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+}
+
+// UpdateTransactionMeta 更新交易备注与标签
+// @Summary 更新交易备注与标签
+// @Description 设置交易的备注与标签（仅保存在本地，不会写入链上），未提供的字段保持不变，tags整体替换
+// @Tags 交易
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tx_hash path string true "交易哈希"
+// @Param request body models.TransactionMetaRequest true "备注与标签"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/transactions/{tx_hash}/meta [patch]
+func (h *TransactionHandler) UpdateTransactionMeta(c *gin.Context) {
+	// 1. 获取用户ID和交易哈希
+	userID, _ := c.Get("user_id")
+	txHash, ok := txHashParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.TransactionMetaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	tx, err := h.txService.UpdateTransactionMeta(c.Request.Context(), userID.(uint), txHash, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrEmptyMetaUpdate) {
+			utils.BadRequest(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, apperr.ErrNotFound) {
+			utils.NotFound(c, "error.transaction_not_found")
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "transaction.updated", h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
+}
+
+// ListPendingApprovals 查询待我审批的交易
+// @Summary 查询待审批交易
+// @Description 查询当前用户作为审批人、仍处于等待审批状态的交易
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.TransactionResponse}
+// @Router /api/v1/transactions/approvals [get]
+func (h *TransactionHandler) ListPendingApprovals(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 调用服务层
+	transactions, err := h.txService.ListPendingApprovals(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.DatabaseError(c, err)
 		return
 	}
 
 	// 3. 返回响应
-	utils.Success(c, tx.ToResponse())
+	utils.Success(c, transactions)
+}
+
+// ApproveTransaction 批准交易
+// @Summary 批准交易
+// @Description 审批人批准等待审批的交易，批准人数达到钱包策略要求后立即签名并广播（nonce与余额在广播时获取与校验）
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "交易ID"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "交易不处于等待审批状态或审批已过期（code=10017）"
+// @Router /api/v1/transactions/{id}/approve [post]
+func (h *TransactionHandler) ApproveTransaction(c *gin.Context) {
+	h.handleApproval(c, h.txService.ApproveTransaction)
+}
+
+// RejectTransaction 拒绝交易
+// @Summary 拒绝交易
+// @Description 审批人拒绝等待审批的交易，交易不会被广播
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "交易ID"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "交易不处于等待审批状态（code=10017）"
+// @Router /api/v1/transactions/{id}/reject [post]
+func (h *TransactionHandler) RejectTransaction(c *gin.Context) {
+	h.handleApproval(c, h.txService.RejectTransaction)
+}
+
+// ExpireTransaction 结束审批
+// @Summary 结束审批
+// @Description 钱包所有者或审批人提前结束等待审批的交易（标记为expired，不会被广播）
+// @Tags 交易
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "交易ID"
+// @Success 200 {object} utils.Response{data=models.TransactionResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "交易不处于等待审批状态（code=10017）"
+// @Router /api/v1/transactions/{id}/expire [post]
+func (h *TransactionHandler) ExpireTransaction(c *gin.Context) {
+	h.handleApproval(c, h.txService.ExpireTransaction)
+}
+
+// handleApproval 审批操作的公共处理流程
+func (h *TransactionHandler) handleApproval(c *gin.Context, action func(ctx context.Context, userID uint, id uint) (*models.Transaction, error)) {
+	// 1. 获取用户ID和交易ID
+	userID, _ := c.Get("user_id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_transaction_id")
+		return
+	}
+
+	// 2. 调用服务层
+	tx, err := action(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrNotAwaitingApproval) || errors.Is(err, service.ErrApprovalExpired) {
+			utils.ErrorWithDetail(c, http.StatusConflict, utils.CodeNotAwaitingApproval, apperr.MessageKey(err), err)
+			return
+		}
+		if errors.Is(err, apperr.ErrNotFound) {
+			utils.NotFound(c, "error.transaction_not_found")
+			return
+		}
+		sendError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, h.txService.BuildResponse(c.Request.Context(), userID.(uint), tx))
 }
 
 // ListTransactions 查询交易列表
 // @Summary 查询交易列表
-// @Description 查询用户的交易记录（支持分页和筛选）
+// @Description 查询用户所有钱包的交易记录（支持分页和筛选），指定的钱包地址不属于当前用户时返回404；携带上次响应的ETag（If-None-Match）且数据未变化时返回304
 // @Tags 交易
 // @Produce json
 // @Security BearerAuth
 // @Param wallet_address query string false "钱包地址"
-// @Param status query string false "交易状态" Enums(pending, success, failed)
+// @Param status query string false "交易状态" Enums(pending, confirming, success, failed, dropped, awaiting_approval, rejected, expired)
 // @Param chain_id query int false "链ID" Enums(1, 56)
+// @Param tag query string false "标签"
+// @Param from query string false "创建时间下限（含，RFC3339）"
+// @Param to query string false "创建时间上限（不含，RFC3339）"
+// @Param direction query string false "方向：outgoing为钱包发送的交易，incoming为代币入账与转入钱包的交易" Enums(incoming, outgoing)
+// @Param min_amount query string false "金额下限（含，主单位，按数值比较）"
+// @Param max_amount query string false "金额上限（含，主单位，按数值比较）"
+// @Param counterparty query string false "对手方地址（交易的发送方或接收方）"
 // @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
+// @Param page_size query int false "每页数量（超过100返回400）" default(20) maximum(100)
+// @Param If-None-Match header string false "上次响应的ETag"
 // @Success 200 {object} utils.Response{data=models.TransactionListResponse}
+// @Success 304 "数据未变化"
 // @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /api/v1/transactions [get]
 func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 	// 1. 获取用户ID
@@ -100,18 +505,44 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 	// 2. 绑定查询参数
 	var req models.TransactionListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		utils.BadRequest(c, "invalid query parameters")
+		utils.BindError(c, "request.invalid_query", err)
 		return
 	}
 
-	// 3. 调用服务层
+	// 3. 数据未变化时返回304（只查询匹配交易的数量与最近更新时间）
+	version, err := h.txService.ListTransactionsVersion(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, apperr.ErrInvalid) {
+			utils.AppError(c, err)
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+	if utils.NotModified(c, version) {
+		return
+	}
+
+	// 4. 调用服务层
 	resp, err := h.txService.ListTransactions(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, apperr.ErrInvalid) {
+			utils.AppError(c, err)
+			return
+		}
 		utils.DatabaseError(c, err)
 		return
 	}
 
-	// 4. 返回响应
+	// 5. 返回响应
 	utils.Success(c, resp)
 }
 
@@ -122,20 +553,30 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param address path string true "钱包地址"
+// @Param from query string false "创建时间下限（含，RFC3339）"
+// @Param to query string false "创建时间上限（不含，RFC3339）"
+// @Param direction query string false "方向：outgoing为钱包发送的交易，incoming为代币入账与转入钱包的交易" Enums(incoming, outgoing)
+// @Param min_amount query string false "金额下限（含，主单位，按数值比较）"
+// @Param max_amount query string false "金额上限（含，主单位，按数值比较）"
+// @Param counterparty query string false "对手方地址（交易的发送方或接收方）"
 // @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
+// @Param page_size query int false "每页数量（超过100返回400）" default(20) maximum(100)
 // @Success 200 {object} utils.Response{data=models.TransactionListResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /api/v1/wallets/{address}/transactions [get]
 func (h *TransactionHandler) GetWalletTransactions(c *gin.Context) {
 	// 1. 获取用户ID和钱包地址
 	userID, _ := c.Get("user_id")
-	address := c.Param("address")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 绑定分页参数
 	var req models.TransactionListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		utils.BadRequest(c, "invalid query parameters")
+		utils.BindError(c, "request.invalid_query", err)
 		return
 	}
 	req.WalletAddress = address
@@ -143,6 +584,14 @@ func (h *TransactionHandler) GetWalletTransactions(c *gin.Context) {
 	// 3. 调用服务层
 	resp, err := h.txService.ListTransactions(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, apperr.ErrInvalid) {
+			utils.AppError(c, err)
+			return
+		}
 		utils.DatabaseError(c, err)
 		return
 	}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// gasPriceChain 只提供链ID与Gas价格的区块链客户端（限额校验之前仅会调用GetGasPrice）
+type gasPriceChain struct {
+	blockchain.BlockchainClient
+}
+
+func (gasPriceChain) GetChainID() int {
+	return testutil.ChainID
+}
+
+func (gasPriceChain) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+// newTransactionService 按生产方式组装交易服务（区块链客户端只提供链ID与Gas价格）
+func newTransactionService(db *gorm.DB, redis *cache.RedisCache) (*service.TransactionService, *service.LimitService) {
+	chains := blockchain.NewClients(gasPriceChain{})
+	walletRepo := repository.NewWalletRepository(db)
+	events := service.NewEventService(redis)
+	txRepo := repository.NewTransactionRepository(db)
+	contacts := service.NewContactService(repository.NewContactRepository(db))
+	activity := service.NewActivityService(repository.NewActivityRepository(db), txRepo, walletRepo, contacts)
+	wallets := service.NewWalletService(walletRepo, repository.NewUserRepository(db, testutil.EmailHMACKey), chains, redis, events, nil, activity, testutil.EncryptionKey)
+	whitelist := service.NewWhitelistService(repository.NewWhitelistRepository(db), wallets, activity, 0)
+	limits := service.NewLimitService(repository.NewSpendLedgerRepository(db))
+	return service.NewTransactionService(txRepo, walletRepo, wallets, chains, events, contacts, whitelist, limits), limits
+}
+
+func TestSendTransactionDailyLimitExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	utils.InitValidator() // 注册eth_addr_or_ens等自定义绑定规则
+	testutil.RegisterChains()
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t)
+
+	user := &models.User{Username: "limit", Email: "limit@example.com", Password: "unused"}
+	if err := repository.NewUserRepository(db, testutil.EmailHMACKey).Create(ctx, user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	walletRepo := repository.NewWalletRepository(db)
+	wallet := &models.Wallet{
+		UserID:              user.ID,
+		Address:             "0x00000000000000000000000000000000000000a1",
+		PrivateKeyEncrypted: "unused",
+		ChainID:             testutil.ChainID,
+		Balance:             "0",
+		DailyLimitWei:       "1000",
+	}
+	if err := walletRepo.Create(ctx, wallet); err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	redis.Set(ctx, "balance:"+wallet.Address, "1000000000000000000", 60)
+
+	txs, limits := newTransactionService(db, redis)
+
+	// 窗口内已转出600 Wei
+	spent, err := limits.Reserve(ctx, wallet, big.NewInt(600))
+	if err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+
+	router := gin.New()
+	router.POST("/api/v1/transactions", func(c *gin.Context) { c.Set("user_id", user.ID) }, NewTransactionHandler(txs).SendTransaction)
+	body, _ := json.Marshal(&models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   "0x1111111111111111111111111111111111111111",
+		Amount:      "500",
+		ChainID:     testutil.ChainID,
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body)))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Code int                           `json:"code"`
+		Data models.DailyLimitExceededData `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Code != utils.CodeDailyLimitExceeded {
+		t.Errorf("code = %d, want %d", resp.Code, utils.CodeDailyLimitExceeded)
+	}
+	if resp.Data.RemainingWei != "400" {
+		t.Errorf("remaining_wei = %q, want 400", resp.Data.RemainingWei)
+	}
+	if resp.Data.RemainingCount != nil {
+		t.Errorf("remaining_count = %d, want omitted without a count limit", *resp.Data.RemainingCount)
+	}
+	if want := spent.CreatedAt.Add(24 * time.Hour); !resp.Data.ResetAt.Equal(want) {
+		t.Errorf("reset_at = %s, want %s", resp.Data.ResetAt, want)
+	}
+}
+
+func TestListTransactionsOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t)
+	txs, _ := newTransactionService(db, redis)
+
+	userRepo := repository.NewUserRepository(db, testutil.EmailHMACKey)
+	walletRepo := repository.NewWalletRepository(db)
+	txRepo := repository.NewTransactionRepository(db)
+	var seq int
+	createUser := func() *models.User {
+		seq++
+		user := &models.User{Username: fmt.Sprintf("list%d", seq), Email: fmt.Sprintf("list%d@example.com", seq), Password: "unused"}
+		if err := userRepo.Create(ctx, user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		return user
+	}
+	createWallet := func(userID uint) *models.Wallet {
+		seq++
+		wallet := &models.Wallet{UserID: userID, Address: fmt.Sprintf("0x%040x", seq), PrivateKeyEncrypted: "unused", ChainID: 1, Balance: "0"}
+		if err := walletRepo.Create(ctx, wallet); err != nil {
+			t.Fatalf("create wallet: %v", err)
+		}
+		return wallet
+	}
+	createTransaction := func(wallet *models.Wallet) string {
+		seq++
+		tx := &models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      fmt.Sprintf("0x%064x", seq),
+			FromAddress: wallet.Address,
+			ToAddress:   "0x1111111111111111111111111111111111111111",
+			Amount:      "1",
+			Status:      models.TxStatusPending,
+			ChainID:     wallet.ChainID,
+		}
+		if err := txRepo.Create(ctx, tx); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+		return tx.TxHash
+	}
+
+	owner, other, empty := createUser(), createUser(), createUser()
+	wallet, second, otherWallet := createWallet(owner.ID), createWallet(owner.ID), createWallet(other.ID)
+	first := map[string]bool{createTransaction(wallet): true, createTransaction(wallet): true}
+	all := map[string]bool{createTransaction(second): true}
+	for hash := range first {
+		all[hash] = true
+	}
+	createTransaction(otherWallet)
+
+	handler := NewTransactionHandler(txs)
+	tests := []struct {
+		name   string
+		userID uint
+		path   string
+		status int
+		hashes map[string]bool // 200时期望返回的交易
+	}{
+		{"own wallet", owner.ID, "/api/v1/wallets/" + wallet.Address + "/transactions", http.StatusOK, first},
+		{"own wallet filtered by status", owner.ID, "/api/v1/wallets/" + wallet.Address + "/transactions?status=success", http.StatusOK, map[string]bool{}},
+		{"another user's wallet", owner.ID, "/api/v1/wallets/" + otherWallet.Address + "/transactions", http.StatusNotFound, nil},
+		{"unknown address", owner.ID, "/api/v1/wallets/0x2222222222222222222222222222222222222222/transactions", http.StatusNotFound, nil},
+		{"invalid status filter", owner.ID, "/api/v1/wallets/" + wallet.Address + "/transactions?status=unknown", http.StatusBadRequest, nil},
+		{"all own wallets", owner.ID, "/api/v1/transactions", http.StatusOK, all},
+		{"list filtered by another user's wallet", owner.ID, "/api/v1/transactions?wallet_address=" + otherWallet.Address, http.StatusNotFound, nil},
+		{"user without wallets", empty.ID, "/api/v1/transactions", http.StatusOK, map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			auth := func(c *gin.Context) { c.Set("user_id", tt.userID) }
+			router.GET("/api/v1/transactions", auth, handler.ListTransactions)
+			router.GET("/api/v1/wallets/:address/transactions", auth, handler.GetWalletTransactions)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d (%s), want %d", w.Code, w.Body, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data models.TransactionListResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Data.Total != int64(len(tt.hashes)) || len(resp.Data.Transactions) != len(tt.hashes) {
+				t.Fatalf("total = %d, transactions = %d, want %d", resp.Data.Total, len(resp.Data.Transactions), len(tt.hashes))
+			}
+			for _, tx := range resp.Data.Transactions {
+				if !tt.hashes[tx.TxHash] {
+					t.Errorf("unexpected transaction %s from %s", tx.TxHash, tx.FromAddress)
+				}
+			}
+		})
+	}
+}
@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"math/big"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/service"
 	"crypto-wallet-api/internal/utils"
@@ -25,7 +26,7 @@ func NewWalletHandler(walletService *service.WalletService) *WalletHandler {
 
 // CreateWallet 创建钱包
 // @Summary 创建钱包
-// @Description 为当前用户创建新的区块链钱包
+// @Description 为当前用户创建新的区块链钱包（用户在该链上还没有默认钱包时，新建的个人钱包自动成为默认钱包）
 // @Tags 钱包
 // @Accept json
 // @Produce json
@@ -33,6 +34,7 @@ func NewWalletHandler(walletService *service.WalletService) *WalletHandler {
 // @Param request body models.WalletCreateRequest true "创建钱包请求"
 // @Success 200 {object} utils.Response{data=models.WalletResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "组织权限不足、超出钱包数量上限或链被本部署禁用（code=10025）"
 // @Router /api/v1/wallets [post]
 func (h *WalletHandler) CreateWallet(c *gin.Context) {
 	// 1. 获取用户ID
@@ -41,48 +43,236 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 	// 2. 绑定请求参数
 	var req models.WalletCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "invalid request parameters")
+		utils.BindError(c, "request.invalid_params", err)
 		return
 	}
 
 	// 3. 调用服务层
 	wallet, err := h.walletService.CreateWallet(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
-		utils.InternalError(c, err)
+		if errors.Is(err, service.ErrOrgNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrChainNotAllowed) {
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeChainNotAllowed, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.created", wallet.ToResponse())
+}
+
+// BulkCreateWallets 批量创建钱包
+// @Summary 批量创建钱包
+// @Description 一次创建最多500个钱包并返回地址列表（用于批量生成充值地址）：私钥由服务端生成并加密，不支持私钥口令，不领取测试链水龙头；与单个创建共用每个用户的钱包数量上限
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.WalletBulkCreateRequest true "批量创建钱包请求"
+// @Success 200 {object} utils.Response{data=models.WalletBulkCreateResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "组织权限不足、超出钱包数量上限或链被本部署禁用（code=10025）"
+// @Failure 404 {object} utils.Response "组织不存在"
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Router /api/v1/wallets/bulk [post]
+func (h *WalletHandler) BulkCreateWallets(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.WalletBulkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	result, err := h.walletService.BulkCreateWallets(c.Request.Context(), userID.(uint), &req, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrOrgNotFound) {
+			utils.NotFound(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrChainNotAllowed) {
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeChainNotAllowed, apperr.MessageKey(err), err)
+			return
+		}
+		utils.AppError(c, err)
 		return
 	}
 
 	// 4. 返回响应
-	utils.SuccessWithMessage(c, "wallet created successfully", wallet.ToResponse())
+	utils.SuccessWithMessage(c, "wallet.bulk_created", result)
+}
+
+// ImportWallet 导入钱包
+// @Summary 导入钱包
+// @Description 导入已有私钥创建钱包：private_key为十六进制私钥，或keystore为Web3 Secret Storage格式的keystore JSON（geth、MetaMask导出）加keystore_passphrase，二者选一
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.WalletImportRequest true "导入钱包请求"
+// @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "超出钱包数量上限或链被本部署禁用（code=10025）"
+// @Failure 409 {object} utils.Response{data=models.WalletResponse} "地址已被收录（code=10009）；已是当前用户的钱包时data为已有钱包"
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive）"
+// @Router /api/v1/wallets/import [post]
+func (h *WalletHandler) ImportWallet(c *gin.Context) {
+	// 1. 获取用户ID
+	userID, _ := c.Get("user_id")
+
+	// 2. 绑定请求参数
+	var req models.WalletImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	wallet, err := h.walletService.ImportWallet(c.Request.Context(), userID.(uint), &req, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidKeystore) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(service.ErrInvalidKeystore), err)
+			return
+		}
+		if errors.Is(err, service.ErrChainNotAllowed) {
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeChainNotAllowed, apperr.MessageKey(err), err)
+			return
+		}
+		var importedErr *service.WalletAlreadyImportedError
+		if errors.As(err, &importedErr) {
+			utils.ErrorWithData(c, http.StatusConflict, utils.CodeDuplicateResource, apperr.MessageKey(err), importedErr.Wallet.ToResponse())
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.imported", wallet.ToResponse())
+}
+
+// ExportKeystore 导出keystore备份
+// @Summary 导出keystore备份
+// @Description 将钱包私钥导出为Web3 Secret Storage格式（scrypt）的keystore JSON，可导入geth、MetaMask。需要钱包管理权限并重新输入登录密码，导出记录写入钱包动态并通知用户
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WalletKeystoreExportRequest true "登录密码与keystore口令"
+// @Success 200 {object} utils.Response{data=models.WalletKeystoreResponse}
+// @Failure 400 {object} utils.Response "参数错误或缺少钱包口令（code=10012）"
+// @Failure 403 {object} utils.Response "登录密码错误（code=10022）或钱包口令错误（code=10013）"
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=export）"
+// @Router /api/v1/wallets/{address}/export-keystore [post]
+func (h *WalletHandler) ExportKeystore(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WalletKeystoreExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	resp, err := h.walletService.ExportKeystore(c.Request.Context(), userID.(uint), address, &req, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPassword):
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeInvalidPassword, apperr.MessageKey(err), err)
+		case errors.Is(err, service.ErrPassphraseRequired):
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodePassphraseRequired, apperr.MessageKey(err), err)
+		case errors.Is(err, service.ErrInvalidPassphrase):
+			utils.ErrorWithDetail(c, http.StatusForbidden, utils.CodeInvalidPassphrase, apperr.MessageKey(err), err)
+		default:
+			utils.AppError(c, err)
+		}
+		return
+	}
+
+	// 4. 返回响应
+	utils.Success(c, resp)
 }
 
 // GetWallets 获取钱包列表
 // @Summary 获取钱包列表
-// @Description 获取当前用户的所有钱包
+// @Description 获取当前用户的钱包（默认不含已归档的钱包），is_default标记每条链上的默认钱包；携带上次响应的ETag（If-None-Match）且数据未变化时返回304
 // @Tags 钱包
 // @Produce json
 // @Security BearerAuth
+// @Param archived query string false "归档状态筛选：true、false（默认）或all"
+// @Param If-None-Match header string false "上次响应的ETag"
 // @Success 200 {object} utils.Response{data=models.WalletListResponse}
+// @Success 304 "数据未变化"
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Router /api/v1/wallets [get]
 func (h *WalletHandler) GetWallets(c *gin.Context) {
 	// 1. 获取用户ID
 	userID, _ := c.Get("user_id")
 
-	// 2. 调用服务层
-	wallets, err := h.walletService.GetUserWallets(c.Request.Context(), userID.(uint))
+	// 2. 绑定查询参数
+	var req models.WalletListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BindError(c, "request.invalid_query", err)
+		return
+	}
+
+	// 3. 数据未变化时返回304（只查询各链的钱包数量与最近更新时间）
+	version, err := h.walletService.WalletListVersion(c.Request.Context(), userID.(uint), req.ArchivedFilter())
 	if err != nil {
 		utils.DatabaseError(c, err)
 		return
 	}
+	if utils.NotModified(c, version) {
+		return
+	}
 
-	// 3. 转换为响应格式
+	// 4. 调用服务层
+	wallets, err := h.walletService.GetUserWallets(c.Request.Context(), userID.(uint), req.ArchivedFilter())
+	if err != nil {
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 5. 转换为响应格式（附带美元估值）
 	walletResponses := make([]*models.WalletResponse, len(wallets))
 	for i, wallet := range wallets {
-		walletResponses[i] = wallet.ToResponse()
+		resp := wallet.ToResponse()
+		usd, ok := h.walletService.ValueInUSD(c.Request.Context(), wallet.ChainID, utils.DecimalToWei(wallet.Balance))
+		if ok {
+			resp.BalanceUSD = usd
+		} else {
+			resp.PriceUnavailable = true
+		}
+		resp.Stale = h.walletService.ChainDegraded(wallet.ChainID)
+		walletResponses[i] = resp
 	}
 
-	// 4. 返回响应
+	// 6. 返回响应
 	utils.Success(c, &models.WalletListResponse{
 		Total:   int64(len(walletResponses)),
 		Wallets: walletResponses,
@@ -91,58 +281,97 @@ func (h *WalletHandler) GetWallets(c *gin.Context) {
 
 // GetWallet 获取钱包详情
 // @Summary 获取钱包详情
-// @Description 根据地址获取钱包详细信息
+// @Description 根据地址获取钱包详细信息；携带上次响应的ETag（If-None-Match）且数据未变化时返回304
 // @Tags 钱包
 // @Produce json
 // @Security BearerAuth
 // @Param address path string true "钱包地址"
+// @Param If-None-Match header string false "上次响应的ETag"
 // @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Success 304 "数据未变化"
+// @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /api/v1/wallets/{address} [get]
 func (h *WalletHandler) GetWallet(c *gin.Context) {
 	// 1. 获取用户ID和钱包地址
 	userID, _ := c.Get("user_id")
-	address := c.Param("address")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 调用服务层
 	wallet, err := h.walletService.GetWalletByAddress(c.Request.Context(), userID.(uint), address)
 	if err != nil {
-		utils.NotFound(c, "wallet not found")
+		utils.AppError(c, err)
 		return
 	}
 
-	// 3. 返回响应
-	utils.Success(c, wallet.ToResponse())
+	// 3. 数据未变化时返回304
+	stale := h.walletService.ChainDegraded(wallet.ChainID)
+	if utils.NotModified(c, wallet.ID, wallet.UpdatedAt.UnixNano(), stale) {
+		return
+	}
+
+	// 4. 返回响应
+	resp := wallet.ToResponse()
+	resp.Stale = stale
+	utils.Success(c, resp)
 }
 
 // GetBalance 查询钱包余额
 // @Summary 查询钱包余额
-// @Description 实时查询钱包在链上的余额
+// @Description 查询钱包在链上的余额（默认读取短期缓存，force_refresh=true时直接查询链上；链节点长时间未同步到新区块时stale=true）
 // @Tags 钱包
 // @Produce json
 // @Security BearerAuth
 // @Param address path string true "钱包地址"
-// @Success 200 {object} utils.Response{data=map[string]interface{}}
+// @Param force_refresh query bool false "绕过缓存"
+// @Success 200 {object} utils.Response{data=models.BalanceResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response{data=models.RateLimitExceededData} "超出按用户限流（bucket=expensive，仅force_refresh=true时）"
+// @Failure 504 {object} utils.Response "链节点或Redis在请求时限内未响应（code=10026）"
 // @Router /api/v1/wallets/{address}/balance [get]
 func (h *WalletHandler) GetBalance(c *gin.Context) {
 	// 1. 获取用户ID和钱包地址
 	userID, _ := c.Get("user_id")
-	address := c.Param("address")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 调用服务层
-	balance, err := h.walletService.GetBalance(c.Request.Context(), userID.(uint), address)
+	wallet, err := h.walletService.GetWalletByAddress(c.Request.Context(), userID.(uint), address)
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	getBalance := h.walletService.GetBalance
+	if c.Query("force_refresh") == "true" {
+		getBalance = h.walletService.RefreshBalance
+	}
+	balance, err := getBalance(c.Request.Context(), userID.(uint), address)
 	if err != nil {
 		utils.BlockchainError(c, err)
 		return
 	}
 
-	// 3. 返回响应（Wei和Ether两种单位）
-	utils.Success(c, gin.H{
-		"address":     address,
-		"balance_wei": balance.String(),
-		"balance_eth": weiToEther(balance),
-	})
+	// 3. 返回响应（Wei、Ether两种单位及美元估值）
+	resp := &models.BalanceResponse{
+		Address:    address,
+		BalanceWei: balance.String(),
+		BalanceEth: utils.FormatUnits(balance, utils.EtherDecimals),
+	}
+	usd, ok := h.walletService.ValueInUSD(c.Request.Context(), wallet.ChainID, balance)
+	if ok {
+		resp.BalanceUSD = usd
+	} else {
+		resp.PriceUnavailable = true
+	}
+	resp.Stale = h.walletService.ChainDegraded(wallet.ChainID)
+	utils.Success(c, resp)
 }
 
 // UpdateWallet 更新钱包信息
@@ -160,25 +389,292 @@ func (h *WalletHandler) GetBalance(c *gin.Context) {
 func (h *WalletHandler) UpdateWallet(c *gin.Context) {
 	// 1. 获取用户ID和钱包地址
 	userID, _ := c.Get("user_id")
-	address := c.Param("address")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 绑定请求参数
 	var req struct {
 		Name string `json:"name" binding:"max=100"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "invalid request parameters")
+		utils.BindError(c, "request.invalid_params", err)
 		return
 	}
 
 	// 3. 调用服务层
 	if err := h.walletService.UpdateWallet(c.Request.Context(), userID.(uint), address, req.Name); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
 
 	// 4. 返回响应
-	utils.SuccessWithMessage(c, "wallet updated successfully", nil)
+	utils.SuccessWithMessage(c, "wallet.updated", nil)
+}
+
+// PatchWallet 部分更新钱包信息
+// @Summary 部分更新钱包信息
+// @Description 修改名称、标签、颜色、归档状态或最终确认深度（只修改请求中提供的字段）。归档的钱包保留私钥，但不在默认列表中展示、不参与后台余额刷新，且在取消归档前不能发送交易；confirmations_required决定之后发送的交易需要多少个区块才视为最终确认（0表示使用链配置）
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WalletUpdateRequest true "更新信息"
+// @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address} [patch]
+func (h *WalletHandler) PatchWallet(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WalletUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	wallet, err := h.walletService.PatchWallet(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			utils.Forbidden(c, apperr.MessageKey(err))
+		case errors.Is(err, service.ErrWalletNotFound):
+			utils.NotFound(c, apperr.MessageKey(err))
+		default:
+			utils.DatabaseError(c, err)
+		}
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.updated", wallet.ToResponse())
+}
+
+// UpdateSettings 更新钱包安全设置
+// @Summary 更新钱包安全设置
+// @Description 启用或关闭转账白名单，启用后仅允许向已过冷静期的白名单地址转账
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WalletSettingsRequest true "安全设置"
+// @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/settings [put]
+func (h *WalletHandler) UpdateSettings(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WalletSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	wallet, err := h.walletService.UpdateSettings(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.settings_updated", wallet.ToResponse())
+}
+
+// SetDefaultWallet 设为默认钱包
+// @Summary 设为默认钱包
+// @Description 将个人钱包设为当前用户在该链上的默认钱包（原默认钱包自动取消），发起转账时可省略from_address。每条链上创建的第一个个人钱包自动成为默认钱包
+// @Tags 钱包
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Failure 400 {object} utils.Response "组织钱包不能设为默认"
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "钱包已归档（code=10020）"
+// @Router /api/v1/wallets/{address}/default [put]
+func (h *WalletHandler) SetDefaultWallet(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	wallet, err := h.walletService.SetDefaultWallet(c.Request.Context(), userID.(uint), address)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPermissionDenied):
+			utils.Forbidden(c, apperr.MessageKey(err))
+		case errors.Is(err, service.ErrNotPersonalWallet):
+			utils.BadRequest(c, apperr.MessageKey(err))
+		case errors.Is(err, service.ErrWalletArchived):
+			utils.ErrorWithDetail(c, http.StatusConflict, utils.CodeWalletArchived, apperr.MessageKey(err), err)
+		case errors.Is(err, service.ErrWalletNotFound):
+			utils.NotFound(c, "error.wallet_not_found")
+		default:
+			utils.DatabaseError(c, err)
+		}
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "wallet.default_updated", wallet.ToResponse())
+}
+
+// UpdateLimits 更新钱包每日限额
+// @Summary 更新钱包每日限额
+// @Description 设置滚动24小时内的最大转出金额（Wei）与最大交易笔数，0表示不限
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WalletLimitsRequest true "限额设置"
+// @Success 200 {object} utils.Response{data=models.WalletResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/limits [put]
+func (h *WalletHandler) UpdateLimits(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WalletLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	wallet, err := h.walletService.UpdateLimits(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.limits_updated", wallet.ToResponse())
+}
+
+// GetApprovalPolicy 查询钱包审批策略
+// @Summary 查询钱包审批策略
+// @Description 查询转账审批阈值、所需审批人数量与审批人列表
+// @Tags 钱包
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Success 200 {object} utils.Response{data=models.ApprovalPolicyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/approval-policy [get]
+func (h *WalletHandler) GetApprovalPolicy(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	policy, err := h.walletService.GetApprovalPolicy(c.Request.Context(), userID.(uint), address)
+	if err != nil {
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, "error.wallet_not_found")
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.Success(c, policy)
+}
+
+// UpdateApprovalPolicy 更新钱包审批策略
+// @Summary 更新钱包审批策略
+// @Description 设置转账审批阈值（Wei）与审批人，超过阈值的转账需由指定数量的不同审批人批准后才会签名广播；阈值为空或0表示关闭审批
+// @Tags 钱包
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.ApprovalPolicyRequest true "审批策略"
+// @Success 200 {object} utils.Response{data=models.ApprovalPolicyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/approval-policy [put]
+func (h *WalletHandler) UpdateApprovalPolicy(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.ApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	policy, err := h.walletService.UpdateApprovalPolicy(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		if errors.Is(err, service.ErrInvalidApprovalPolicy) || errors.Is(err, apperr.ErrInvalid) {
+			utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, apperr.MessageKey(err), err)
+			return
+		}
+		if errors.Is(err, service.ErrWalletNotFound) {
+			utils.NotFound(c, "error.wallet_not_found")
+			return
+		}
+		utils.DatabaseError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "wallet.approval_policy_updated", policy)
 }
 
 // DeleteWallet 删除钱包
@@ -194,22 +690,21 @@ func (h *WalletHandler) UpdateWallet(c *gin.Context) {
 func (h *WalletHandler) DeleteWallet(c *gin.Context) {
 	// 1. 获取用户ID和钱包地址
 	userID, _ := c.Get("user_id")
-	address := c.Param("address")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
 
 	// 2. 调用服务层
 	if err := h.walletService.DeleteWallet(c.Request.Context(), userID.(uint), address); err != nil {
-		utils.ErrorWithDetail(c, http.StatusBadRequest, utils.CodeInvalidParams, err.Error(), err)
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
 		return
 	}
 
 	// 3. 返回响应
-	utils.SuccessWithMessage(c, "wallet deleted successfully", nil)
-}
-
-// weiToEther 将Wei转换为Ether（辅助函数）
-func weiToEther(wei *big.Int) string {
-	// 1 Ether = 10^18 Wei
-	ether := new(big.Float).SetInt(wei)
-	ether.Quo(ether, big.NewFloat(1e18))
-	return ether.Text('f', 6) // 保留6位小数
+	utils.SuccessWithMessage(c, "wallet.deleted", nil)
 }
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// WhitelistHandler 转账白名单处理器
+type WhitelistHandler struct {
+	whitelistService *service.WhitelistService
+}
+
+// NewWhitelistHandler 创建白名单处理器实例
+func NewWhitelistHandler(whitelistService *service.WhitelistService) *WhitelistHandler {
+	return &WhitelistHandler{
+		whitelistService: whitelistService,
+	}
+}
+
+// AddEntry 添加白名单地址
+// @Summary 添加白名单地址
+// @Description 新地址需经过冷静期（activates_at）后才允许转账
+// @Tags 白名单
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param request body models.WhitelistAddRequest true "白名单地址"
+// @Success 200 {object} utils.Response{data=models.WhitelistEntryResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/wallets/{address}/whitelist [post]
+func (h *WhitelistHandler) AddEntry(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req models.WhitelistAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindError(c, "request.invalid_params", err)
+		return
+	}
+
+	// 3. 调用服务层
+	entry, err := h.whitelistService.AddEntry(c.Request.Context(), userID.(uint), address, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SuccessWithMessage(c, "whitelist.added", entry.ToResponse())
+}
+
+// ListEntries 获取白名单
+// @Summary 获取白名单
+// @Description 获取钱包的白名单地址，包含仍在冷静期内（active=false）的条目
+// @Tags 白名单
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Success 200 {object} utils.Response{data=[]models.WhitelistEntryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/whitelist [get]
+func (h *WhitelistHandler) ListEntries(c *gin.Context) {
+	// 1. 获取用户ID和钱包地址
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用服务层
+	entries, err := h.whitelistService.ListEntries(c.Request.Context(), userID.(uint), address)
+	if err != nil {
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 转换为响应格式
+	responses := make([]*models.WhitelistEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = entry.ToResponse()
+	}
+
+	// 4. 返回响应
+	utils.Success(c, responses)
+}
+
+// RemoveEntry 删除白名单地址
+// @Summary 删除白名单地址
+// @Tags 白名单
+// @Produce json
+// @Security BearerAuth
+// @Param address path string true "钱包地址"
+// @Param id path int true "白名单条目ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/wallets/{address}/whitelist/{id} [delete]
+func (h *WhitelistHandler) RemoveEntry(c *gin.Context) {
+	// 1. 获取用户ID、钱包地址和条目ID
+	userID, _ := c.Get("user_id")
+	address, ok := addressParam(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(c, "request.invalid_whitelist_entry_id")
+		return
+	}
+
+	// 2. 调用服务层
+	if err := h.whitelistService.RemoveEntry(c.Request.Context(), userID.(uint), address, uint(id)); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Forbidden(c, apperr.MessageKey(err))
+			return
+		}
+		utils.AppError(c, err)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SuccessWithMessage(c, "whitelist.removed", nil)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+)
+
+// WebSocketHandler WebSocket实时事件处理器
+type WebSocketHandler struct {
+	authService      *service.AuthService
+	walletService    *service.WalletService
+	eventService     *service.EventService
+	upgrader         websocket.Upgrader
+	maxSubscriptions int
+	authTimeout      time.Duration
+	pingInterval     time.Duration
+}
+
+// NewWebSocketHandler 创建WebSocket处理器实例
+func NewWebSocketHandler(
+	authService *service.AuthService,
+	walletService *service.WalletService,
+	eventService *service.EventService,
+	maxSubscriptions int,
+	authTimeout time.Duration,
+	pingInterval time.Duration,
+) *WebSocketHandler {
+	return &WebSocketHandler{
+		authService:   authService,
+		walletService: walletService,
+		eventService:  eventService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     func(r *http.Request) bool { return true }, // 跨域由CORS中间件统一处理
+		},
+		maxSubscriptions: maxSubscriptions,
+		authTimeout:      authTimeout,
+		pingInterval:     pingInterval,
+	}
+}
+
+// wsConn 单个WebSocket连接的会话状态
+type wsConn struct {
+	conn          *websocket.Conn
+	userID        uint
+	events        chan *models.WalletEvent
+	writeMu       sync.Mutex
+	subscriptions map[string]string // 小写地址 -> 原始地址
+}
+
+// writeJSON 串行写入（gorilla/websocket不支持并发写）
+func (w *wsConn) writeJSON(v interface{}) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	w.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return w.conn.WriteJSON(v)
+}
+
+// Connect 建立WebSocket连接
+// @Summary 实时事件推送
+// @Description 通过WebSocket订阅钱包的交易确认与入账事件。JWT可通过token查询参数或首条auth消息传递
+// @Tags 实时
+// @Param token query string false "JWT Token"
+// @Router /api/v1/ws [get]
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	// 1. 升级为WebSocket连接
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("websocket upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// 2. 认证（查询参数或首条消息）
+	userID, err := h.authenticate(c.Request.Context(), conn, c.Query("token"))
+	if err != nil {
+		conn.WriteJSON(&models.WebSocketReply{Type: "error", Action: "auth", Message: "invalid or expired token"})
+		return
+	}
+
+	session := &wsConn{
+		conn:          conn,
+		userID:        userID,
+		events:        make(chan *models.WalletEvent, 64),
+		subscriptions: make(map[string]string),
+	}
+	session.writeJSON(&models.WebSocketReply{Type: "ack", Action: "auth"})
+
+	// 3. 自动订阅定向推送给当前用户的事件（如待审批交易），断开时清理所有订阅
+	h.eventService.SubscribeUser(userID, session.events)
+	defer func() {
+		h.eventService.UnsubscribeUser(userID, session.events)
+		for _, address := range session.subscriptions {
+			h.eventService.Unsubscribe(address, session.events)
+		}
+	}()
+
+	// 4. 启动写协程（事件推送与心跳）
+	done := make(chan struct{})
+	defer close(done)
+	go h.writeLoop(session, done)
+
+	// 5. 读循环（处理订阅请求）
+	h.readLoop(c, session)
+}
+
+// authenticate 校验JWT，未通过查询参数提供时等待首条auth消息
+func (h *WebSocketHandler) authenticate(ctx context.Context, conn *websocket.Conn, token string) (uint, error) {
+	if token == "" {
+		conn.SetReadDeadline(time.Now().Add(h.authTimeout))
+		var msg models.WebSocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return 0, err
+		}
+		conn.SetReadDeadline(time.Time{})
+		token = msg.Token
+	}
+
+	claims, err := h.authService.ValidateToken(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+// readLoop 读取客户端消息直到连接关闭
+func (h *WebSocketHandler) readLoop(c *gin.Context, session *wsConn) {
+	pongWait := h.pingInterval * 2
+	session.conn.SetReadDeadline(time.Now().Add(pongWait))
+	session.conn.SetPongHandler(func(string) error {
+		session.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		var msg models.WebSocketMessage
+		if err := session.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		session.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		switch msg.Action {
+		case "subscribe":
+			h.subscribe(c, session, msg.Address)
+		case "unsubscribe":
+			key := strings.ToLower(msg.Address)
+			if address, ok := session.subscriptions[key]; ok {
+				h.eventService.Unsubscribe(address, session.events)
+				delete(session.subscriptions, key)
+			}
+			session.writeJSON(&models.WebSocketReply{Type: "ack", Action: msg.Action, Address: msg.Address})
+		default:
+			session.writeJSON(&models.WebSocketReply{Type: "error", Action: msg.Action, Message: "unknown action"})
+		}
+	}
+}
+
+// subscribe 校验钱包所有权与订阅上限后注册订阅
+func (h *WebSocketHandler) subscribe(c *gin.Context, session *wsConn, address string) {
+	key := strings.ToLower(address)
+	if _, ok := session.subscriptions[key]; ok {
+		session.writeJSON(&models.WebSocketReply{Type: "ack", Action: "subscribe", Address: address})
+		return
+	}
+
+	if len(session.subscriptions) >= h.maxSubscriptions {
+		session.writeJSON(&models.WebSocketReply{Type: "error", Action: "subscribe", Address: address, Message: "subscription limit reached"})
+		return
+	}
+
+	if _, err := h.walletService.GetWalletByAddress(c.Request.Context(), session.userID, address); err != nil {
+		session.writeJSON(&models.WebSocketReply{Type: "error", Action: "subscribe", Address: address, Message: "wallet not found"})
+		return
+	}
+
+	h.eventService.Subscribe(address, session.events)
+	session.subscriptions[key] = address
+	session.writeJSON(&models.WebSocketReply{Type: "ack", Action: "subscribe", Address: address})
+}
+
+// writeLoop 推送事件并定期发送心跳
+func (h *WebSocketHandler) writeLoop(session *wsConn, done <-chan struct{}) {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-session.events:
+			if err := session.writeJSON(event); err != nil {
+				session.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			session.writeMu.Lock()
+			err := session.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+			session.writeMu.Unlock()
+			if err != nil {
+				session.conn.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// newWSServer 启动挂载WebSocket处理器的测试服务，返回ws地址与签发Token的认证服务
+func newWSServer(t *testing.T, maxSubscriptions int) (string, *service.AuthService) {
+	t.Helper()
+	logger.Logger = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	redis, redisServer := testutil.NewRedis(t)
+	redisServer.Set("jwt:version:1", "0") // 测试用户的Token版本（无需回源数据库）
+	authService := service.NewAuthService(nil, nil, redis, nil, "test-secret", 1)
+	h := NewWebSocketHandler(authService, nil, service.NewEventService(redis), maxSubscriptions, time.Second, time.Minute)
+	router := gin.New()
+	router.GET("/api/v1/ws", h.Connect)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws", authService
+}
+
+// dialWS 建立WebSocket连接
+func dialWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// readReply 读取一条服务端回复并校验类型与动作
+func readReply(t *testing.T, conn *websocket.Conn, wantType, wantAction string) *models.WebSocketReply {
+	t.Helper()
+	var reply models.WebSocketReply
+	if err := conn.ReadJSON(&reply); err != nil {
+		t.Fatalf("read reply: %v", err)
+	}
+	if reply.Type != wantType || reply.Action != wantAction {
+		t.Fatalf("reply = %+v, want type %q action %q", reply, wantType, wantAction)
+	}
+	return &reply
+}
+
+func TestWebSocketAuthentication(t *testing.T) {
+	url, authService := newWSServer(t, 10)
+	token, err := authService.GenerateToken(1, 0)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	t.Run("token query parameter", func(t *testing.T) {
+		conn := dialWS(t, url+"?token="+token)
+		readReply(t, conn, "ack", "auth")
+	})
+
+	t.Run("first message", func(t *testing.T) {
+		conn := dialWS(t, url)
+		if err := conn.WriteJSON(&models.WebSocketMessage{Action: "auth", Token: token}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		readReply(t, conn, "ack", "auth")
+	})
+
+	t.Run("invalid token closes the connection", func(t *testing.T) {
+		conn := dialWS(t, url+"?token=invalid")
+		readReply(t, conn, "error", "auth")
+		if _, _, err := conn.ReadMessage(); err == nil {
+			t.Error("connection still open after failed authentication")
+		}
+	})
+
+	t.Run("no auth message before timeout", func(t *testing.T) {
+		conn := dialWS(t, url)
+		readReply(t, conn, "error", "auth")
+	})
+}
+
+func TestWebSocketSubscriptionRules(t *testing.T) {
+	// 订阅上限为0：校验钱包所有权之前即被拒绝
+	url, authService := newWSServer(t, 0)
+	token, err := authService.GenerateToken(1, 0)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	conn := dialWS(t, url+"?token="+token)
+	readReply(t, conn, "ack", "auth")
+
+	const address = "0x0000000000000000000000000000000000000001"
+	conn.WriteJSON(&models.WebSocketMessage{Action: "subscribe", Address: address})
+	if reply := readReply(t, conn, "error", "subscribe"); reply.Message != "subscription limit reached" {
+		t.Errorf("subscribe message = %q, want subscription limit reached", reply.Message)
+	}
+
+	// 未订阅的地址取消订阅同样确认
+	conn.WriteJSON(&models.WebSocketMessage{Action: "unsubscribe", Address: address})
+	readReply(t, conn, "ack", "unsubscribe")
+
+	conn.WriteJSON(&models.WebSocketMessage{Action: "ping"})
+	if reply := readReply(t, conn, "error", "ping"); reply.Message != "unknown action" {
+		t.Errorf("unknown action message = %q", reply.Message)
+	}
+}
@@ -0,0 +1,145 @@
+// Package i18n 响应消息的多语言翻译：消息键 -> 请求语言的文本（语言包内嵌于二进制，启动时加载）
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 支持的语言
+const (
+	English = "en"
+	Chinese = "zh-CN"
+
+	// Default 默认语言，其他语言包缺少的消息键回退到该语言
+	Default = English
+)
+
+// Languages 支持的语言（每种语言对应locales目录下的一个语言包）
+var Languages = []string{English, Chinese}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	mu      sync.RWMutex
+	bundles map[string]map[string]string // 语言 -> 消息键 -> 文本
+)
+
+// Load 加载全部语言包（启动时调用，语言包缺失或格式错误时返回错误）
+func Load() error {
+	loaded := make(map[string]map[string]string, len(Languages))
+	for _, lang := range Languages {
+		data, err := locales.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			return fmt.Errorf("read %s bundle: %w", lang, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("parse %s bundle: %w", lang, err)
+		}
+		loaded[lang] = messages
+	}
+
+	mu.Lock()
+	bundles = loaded
+	mu.Unlock()
+	return nil
+}
+
+// lookup 在指定语言包中查找消息键，缺少时回退到默认语言
+func lookup(lang, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if text, ok := bundles[lang][key]; ok {
+		return text, true
+	}
+	text, ok := bundles[Default][key]
+	return text, ok
+}
+
+// T 将消息翻译为指定语言
+//
+// message不是消息键时原样返回（如动态生成的校验错误）；消息键后跟": 说明"时只翻译消息键，说明原样保留。
+func T(lang, message string) string {
+	if text, ok := lookup(lang, message); ok {
+		return text
+	}
+	if key, detail, ok := strings.Cut(message, ": "); ok {
+		if text, ok := lookup(lang, key); ok {
+			return text + ": " + detail
+		}
+	}
+	return message
+}
+
+// Has 指定语言包是否包含消息键（不回退到默认语言）
+func Has(lang, key string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := bundles[lang][key]
+	return ok
+}
+
+// Missing 返回默认语言包中存在、指定语言包中缺少的消息键（运行时回退到默认语言）
+func Missing(lang string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var missing []string
+	for key := range bundles[Default] {
+		if _, ok := bundles[lang][key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// Match 按Accept-Language头（或单个语言标签）选择支持的语言，没有匹配的语言时返回空字符串
+//
+// 按q值从高到低匹配；zh及其地区变体使用zh-CN，en及其地区变体使用en，"*"使用默认语言。
+func Match(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag == "" || q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: strings.ToLower(strings.TrimSpace(tag)), q: q})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		base, _, _ := strings.Cut(strings.ReplaceAll(c.tag, "_", "-"), "-")
+		switch base {
+		case "zh":
+			return Chinese
+		case "en":
+			return English
+		case "*":
+			return Default
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBundlesComplete(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	// 每种语言包中的消息键在其他语言包中都存在，且译文非空
+	for _, lang := range Languages {
+		for key, text := range bundles[lang] {
+			if strings.TrimSpace(text) == "" {
+				t.Errorf("%s: %s has empty text", lang, key)
+			}
+			for _, other := range Languages {
+				if !Has(other, key) {
+					t.Errorf("%s: missing %s (present in %s)", other, key, lang)
+				}
+			}
+		}
+	}
+}
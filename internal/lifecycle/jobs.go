@@ -0,0 +1,112 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+)
+
+// Job 后台任务（ctx只在关闭超时后取消，任务自身的超时由任务设置）
+type Job func(ctx context.Context)
+
+// JobRunner 有界并发的后台任务队列：替代请求处理中直接启动的协程，关闭时先执行完已提交的任务再退出
+type JobRunner struct {
+	workers int
+	jobs    chan Job
+	ctx     context.Context // 传给任务的上下文，关闭超时后取消
+	cancel  context.CancelFunc
+
+	mu     sync.RWMutex // 保护closed与jobs的关闭
+	closed bool
+	start  sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewJobRunner 创建后台任务队列（需由Manager启动；队列满时新提交的任务被丢弃）
+func NewJobRunner(workers, queueSize int) *JobRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobRunner{
+		workers: max(workers, 1),
+		jobs:    make(chan Job, queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Submit 提交任务（不阻塞），队列已满或已关闭时丢弃任务并返回false
+func (r *JobRunner) Submit(name string, job Job) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		metrics.BackgroundJobsDropped.WithLabelValues("stopped").Inc()
+		logger.Warn("background job dropped: runner stopped", zap.String("job", name))
+		return false
+	}
+	select {
+	case r.jobs <- job:
+		metrics.BackgroundJobsQueueDepth.Set(float64(len(r.jobs)))
+		return true
+	default:
+		metrics.BackgroundJobsDropped.WithLabelValues("queue_full").Inc()
+		logger.Warn("background job dropped: queue full", zap.String("job", name), zap.Int("capacity", cap(r.jobs)))
+		return false
+	}
+}
+
+// Start 启动工作协程（任务队列不随共享上下文退出，关闭时由Stop排空）
+func (r *JobRunner) Start(context.Context) {
+	r.start.Do(func() {
+		for i := 0; i < r.workers; i++ {
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				for job := range r.jobs {
+					metrics.BackgroundJobsQueueDepth.Set(float64(len(r.jobs)))
+					r.run(job)
+				}
+			}()
+		}
+	})
+}
+
+// run 执行单个任务（panic只记录日志，不影响其他任务）
+func (r *JobRunner) run(job Job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error("background job panicked", zap.Any("panic", rec), zap.Stack("stack"))
+		}
+	}()
+	job(r.ctx)
+}
+
+// Stop 停止接收新任务并等待已提交的任务执行完毕；ctx到期时取消进行中的任务，未开始的任务被丢弃
+func (r *JobRunner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.jobs)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		abandoned := len(r.jobs)
+		metrics.BackgroundJobsDropped.WithLabelValues("timeout").Add(float64(abandoned))
+		return fmt.Errorf("%d queued jobs abandoned: %w", abandoned, ctx.Err())
+	}
+}
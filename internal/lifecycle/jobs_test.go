@@ -0,0 +1,115 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func TestJobRunnerStopRunsQueuedJobs(t *testing.T) {
+	const jobs = 20
+	runner := NewJobRunner(2, jobs)
+	runner.Start(context.Background())
+
+	// 任务阻塞直到关闭开始，保证关闭时既有进行中的任务也有排队的任务
+	release := make(chan struct{})
+	var ran atomic.Int32
+	for i := 0; i < jobs; i++ {
+		if !runner.Submit("test", func(ctx context.Context) {
+			<-release
+			if ctx.Err() == nil {
+				ran.Add(1)
+			}
+		}) {
+			t.Fatalf("job %d rejected before shutdown", i)
+		}
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- runner.Stop(ctx)
+	}()
+	close(release)
+
+	if err := <-stopped; err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := ran.Load(); got != jobs {
+		t.Errorf("jobs run = %d, want %d", got, jobs)
+	}
+	if runner.Submit("late", func(context.Context) {}) {
+		t.Error("job accepted after stop")
+	}
+}
+
+func TestJobRunnerStopTimeoutCancelsJobs(t *testing.T) {
+	runner := NewJobRunner(1, 10)
+	runner.Start(context.Background())
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	runner.Submit("blocking", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	})
+	runner.Submit("queued", func(context.Context) {})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := runner.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("stop err = %v, want deadline exceeded", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight job not cancelled after shutdown deadline")
+	}
+}
+
+func TestManagerShutdownDrainsJobsFromProducers(t *testing.T) {
+	m := New()
+	runner := NewJobRunner(4, 1000)
+	m.Register("jobs", runner)
+
+	// 生产者在任务队列之后注册，关闭时先停止，之后不再有新任务
+	var submitted, ran atomic.Int32
+	m.Go("producer", func(ctx context.Context) {
+		for ctx.Err() == nil {
+			if runner.Submit("test", func(context.Context) {
+				time.Sleep(time.Millisecond)
+				ran.Add(1)
+			}) {
+				submitted.Add(1)
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+	})
+	m.Start()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if submitted.Load() == 0 || ran.Load() != submitted.Load() {
+		t.Errorf("jobs run = %d, submitted = %d, want all submitted jobs run", ran.Load(), submitted.Load())
+	}
+}
@@ -0,0 +1,132 @@
+// Package lifecycle 后台任务的启动与有序关闭：任务共享一个上下文，关闭时先取消上下文，再按注册的相反顺序等待任务退出
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// Task 由Manager管理的后台任务
+type Task interface {
+	// Start 启动后台协程后立即返回，ctx在关闭时取消
+	Start(ctx context.Context)
+	// Stop 等待后台协程退出（ctx到期时放弃等待并返回错误）
+	Stop(ctx context.Context) error
+}
+
+// namedTask 带名称的任务（用于日志）
+type namedTask struct {
+	name string
+	task Task
+}
+
+// Manager 后台任务管理器
+type Manager struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	tasks   []namedTask
+	started bool
+	stopped bool
+}
+
+// New 创建后台任务管理器
+func New() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{ctx: ctx, cancel: cancel}
+}
+
+// Register 注册后台任务（已启动时立即启动，已关闭时忽略）
+func (m *Manager) Register(name string, task Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		logger.Warn("background task registered after shutdown, ignored", zap.String("task", name))
+		return
+	}
+	m.tasks = append(m.tasks, namedTask{name: name, task: task})
+	if m.started {
+		task.Start(m.ctx)
+	}
+}
+
+// Go 注册阻塞运行直到ctx取消的循环函数（如定时任务、事件分发）
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	m.Register(name, &loopTask{run: run, done: make(chan struct{})})
+}
+
+// Start 启动已注册的全部任务（可重复调用）
+func (m *Manager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started || m.stopped {
+		return
+	}
+	m.started = true
+	for _, t := range m.tasks {
+		t.task.Start(m.ctx)
+	}
+}
+
+// Shutdown 取消共享上下文，按注册的相反顺序停止任务（先停止后注册的生产者，再停止先注册的任务队列），
+// ctx到期后不再等待，返回未能按时退出的任务（可重复调用）
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	started := m.started
+	tasks := m.tasks
+	m.mu.Unlock()
+
+	m.cancel()
+	if !started {
+		return nil
+	}
+
+	var errs []error
+	for i := len(tasks) - 1; i >= 0; i-- {
+		t := tasks[i]
+		if err := t.task.Stop(ctx); err != nil {
+			logger.Warn("background task did not stop in time", zap.String("task", t.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", t.name, err))
+			continue
+		}
+		logger.Debug("background task stopped", zap.String("task", t.name))
+	}
+	return errors.Join(errs...)
+}
+
+// loopTask 将阻塞运行的循环函数包装为Task
+type loopTask struct {
+	run  func(ctx context.Context)
+	done chan struct{}
+}
+
+// Start 在新协程中运行循环函数
+func (t *loopTask) Start(ctx context.Context) {
+	go func() {
+		defer close(t.done)
+		t.run(ctx)
+	}()
+}
+
+// Stop 等待循环函数返回（共享上下文已由Manager取消）
+func (t *loopTask) Stop(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"context"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -10,22 +12,34 @@ import (
 
 var Logger *zap.Logger
 
-// InitLogger 初始化日志
-func InitLogger(level string, output string, filePath string, maxSize int, maxBackups int, maxAge int) error {
-	// 解析日志级别
-	var zapLevel zapcore.Level
+// atomicLevel 日志级别（支持运行时调整）
+var atomicLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// parseLevel 解析日志级别，未知级别按info处理
+func parseLevel(level string) zapcore.Level {
 	switch level {
 	case "debug":
-		zapLevel = zapcore.DebugLevel
+		return zapcore.DebugLevel
 	case "info":
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	case "warn":
-		zapLevel = zapcore.WarnLevel
+		return zapcore.WarnLevel
 	case "error":
-		zapLevel = zapcore.ErrorLevel
+		return zapcore.ErrorLevel
 	default:
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	}
+}
+
+// SetLevel 运行时调整日志级别
+func SetLevel(level string) {
+	atomicLevel.SetLevel(parseLevel(level))
+}
+
+// InitLogger 初始化日志
+func InitLogger(level string, output string, filePath string, maxSize int, maxBackups int, maxAge int) error {
+	// 解析日志级别
+	SetLevel(level)
 
 	// 编码器配置
 	encoderConfig := zapcore.EncoderConfig{
@@ -63,7 +77,7 @@ func InitLogger(level string, output string, filePath string, maxSize int, maxBa
 	core := zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderConfig), // JSON格式
 		writeSyncer,
-		zapLevel,
+		atomicLevel,
 	)
 
 	// 创建Logger
@@ -96,3 +110,29 @@ func Error(msg string, fields ...zap.Field) {
 func Fatal(msg string, fields ...zap.Field) {
 	Logger.Fatal(msg, fields...)
 }
+
+// requestIDKey 请求ID在context中的键
+type requestIDKey struct{}
+
+// ContextWithRequestID 将请求ID写入context
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 从context读取请求ID
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithCtx 返回附带请求ID与Trace ID字段的Logger
+func WithCtx(ctx context.Context) *zap.Logger {
+	l := Logger
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		l = l.With(zap.String("request_id", requestID))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		l = l.With(zap.String("trace_id", spanCtx.TraceID().String()))
+	}
+	return l
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "crypto_wallet"
+
+var (
+	// RPCCalls 区块链RPC调用次数（按方法）
+	RPCCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_calls_total",
+		Help:      "Blockchain RPC calls by method.",
+	}, []string{"method"})
+
+	// TransactionsConfirmed 达到最终确认的交易数（按结果）
+	// 每笔确认交易的RPC调用次数：sum(rate(crypto_wallet_rpc_calls_total{method=~"GetTransactionReceipt|GetTransactionByHash|GetBlockNumber|GetConfirmedNonce"}[1h])) / sum(rate(crypto_wallet_transactions_confirmed_total[1h]))
+	TransactionsConfirmed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_confirmed_total",
+		Help:      "Transactions that reached final confirmation, by status.",
+	}, []string{"status"})
+
+	// TransactionChecks 未最终确认交易的检查结果：included为已打包，mempool为仍在交易池中，unknown为节点不知道该交易，rpc_error为查询失败
+	TransactionChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transaction_checks_total",
+		Help:      "Checks of unfinalized transactions, by outcome: included, mempool, unknown (not known to the node) or rpc_error.",
+	}, []string{"outcome"})
+
+	// ReceiptMonitorSubscribed 各链的回执监听模式（1为新区块订阅，0为轮询）
+	ReceiptMonitorSubscribed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "receipt_monitor_subscribed",
+		Help:      "Whether receipt monitoring is driven by a new-head subscription (1) or polling (0), by chain.",
+	}, []string{"chain_id"})
+
+	// BalanceRefreshQueueDepth 等待刷新余额的地址数（已去重）
+	BalanceRefreshQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "balance_refresh_queue_depth",
+		Help:      "Wallet addresses waiting for a background balance refresh.",
+	})
+
+	// BackgroundJobsQueueDepth 等待执行的后台任务数
+	BackgroundJobsQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "background_jobs_queue_depth",
+		Help:      "Background jobs waiting for a worker.",
+	})
+
+	// BackgroundJobsDropped 未执行或被中断的后台任务数（queue_full为队列已满，stopped为关闭后提交，timeout为关闭超时被取消）
+	BackgroundJobsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "background_jobs_dropped_total",
+		Help:      "Background jobs that were rejected or interrupted, by reason.",
+	}, []string{"reason"})
+
+	// EmailsSent 外发邮件数（result为sent、retry或rejected）
+	EmailsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_sent_total",
+		Help:      "Outgoing emails processed by the worker, by template and result.",
+	}, []string{"template", "result"})
+
+	// ExportJobs 异步导出任务数（result为completed或failed）
+	ExportJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "export_jobs_total",
+		Help:      "Asynchronous transaction export jobs processed by the worker, by format and result.",
+	}, []string{"format", "result"})
+
+	// BalanceRefreshBatchSize 每次余额刷新请求包含的地址数
+	BalanceRefreshBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "balance_refresh_batch_size",
+		Help:      "Addresses fetched per balance refresh RPC round trip.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 50, 100},
+	})
+
+	// BalanceWriteFailures 重试后仍未能保存的余额更新（按存储：cache、database）
+	BalanceWriteFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "balance_write_failures_total",
+		Help:      "Balance updates that could not be saved after retries, by store.",
+	}, []string{"store"})
+
+	// CacheReadFailures 读取缓存失败后回源查询的次数（按原因：error为Redis等读取错误，invalid为缓存值无法解析）
+	CacheReadFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_read_failures_total",
+		Help:      "Cache reads that failed and fell back to the source of truth, by reason.",
+	}, []string{"reason"})
+
+	// ChainHeadBlock 节点报告的最新区块号（按链）
+	ChainHeadBlock = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_block",
+		Help:      "Latest block number reported by the chain's RPC node.",
+	}, []string{"chain_id"})
+
+	// ChainHeadAge 距最新区块号上一次推进的时间（秒，按链）
+	ChainHeadAge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_age_seconds",
+		Help:      "Seconds since the chain head last advanced, as observed locally.",
+	}, []string{"chain_id"})
+
+	// ChainDegraded 链头长时间未推进，节点视为不健康（1为不健康）
+	ChainDegraded = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_degraded",
+		Help:      "Whether the chain's RPC node is considered stale (1) because its head stopped advancing.",
+	}, []string{"chain_id"})
+
+	// ReconciliationDiscrepancies 最近一次余额对账中超过阈值的差异数（按链）
+	ReconciliationDiscrepancies = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_discrepancies",
+		Help:      "Wallets whose stored balance differed from the chain by more than the threshold in the last reconciliation run.",
+	}, []string{"chain_id"})
+
+	// ReconciliationCorrected 最近一次余额对账中修正的钱包数（按链，含低于阈值的差异）
+	ReconciliationCorrected = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_corrected",
+		Help:      "Wallet balances corrected in the last reconciliation run, including differences below the threshold.",
+	}, []string{"chain_id"})
+
+	// ReconciliationLastRun 最近一次余额对账完成的时间（Unix秒，按链）
+	ReconciliationLastRun = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_last_run_timestamp_seconds",
+		Help:      "Unix time at which the last reconciliation run finished.",
+	}, []string{"chain_id"})
+
+	// QueueHandlersInFlight 正在运行的消息处理函数数（按队列）
+	QueueHandlersInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_handlers_in_flight",
+		Help:      "Queue message handlers currently running, by queue.",
+	}, []string{"queue"})
+
+	// QueueMessageDuration 单条消息的处理耗时（秒，按队列与结果：success、error、aborted）
+	QueueMessageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_message_duration_seconds",
+		Help:      "Time spent processing a single queue message, by queue and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"queue", "result"})
+)
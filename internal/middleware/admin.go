@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// AdminMiddleware 管理员中间件（需在AuthMiddleware之后使用，仅接受JWT认证，不接受API Key）
+func AdminMiddleware(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. 管理操作必须使用登录Token
+		if _, ok := c.Get("token_claims"); !ok {
+			utils.Forbidden(c, "auth.admin_user_token_required")
+			c.Abort()
+			return
+		}
+
+		// 2. 校验管理员角色
+		userID, _ := c.Get("user_id")
+		isAdmin, err := authService.IsAdmin(c.Request.Context(), userID.(uint))
+		if err != nil {
+			utils.DatabaseError(c, err)
+			c.Abort()
+			return
+		}
+		if !isAdmin {
+			utils.Forbidden(c, "auth.admin_role_required")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,47 +1,93 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/service"
 	"crypto-wallet-api/internal/utils"
 )
 
-// AuthMiddleware JWT认证中间件
-func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
+// APIKeyHeader API Key请求头
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware 认证中间件（支持Bearer JWT或X-API-Key）
+func AuthMiddleware(authService *service.AuthService, apiKeyService *service.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 1. 从Header获取Token
+		// 1. 优先使用API Key
+		if rawKey := c.GetHeader(APIKeyHeader); rawKey != "" {
+			key, err := apiKeyService.Authenticate(c.Request.Context(), rawKey)
+			if err != nil {
+				utils.Unauthorized(c, "error.invalid_api_key")
+				c.Abort()
+				return
+			}
+
+			// 只读Key仅允许安全方法
+			if key.Scope == models.APIKeyScopeRead && !isReadOnlyMethod(c.Request.Method) {
+				utils.Forbidden(c, "auth.api_key_read_only")
+				c.Abort()
+				return
+			}
+
+			c.Set("user_id", key.UserID)
+			c.Set("auth_scope", key.Scope)
+			c.Next()
+			return
+		}
+
+		// 2. 从Header获取Token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			utils.Unauthorized(c, "missing authorization header")
+			utils.Unauthorized(c, "auth.missing_header")
 			c.Abort()
 			return
 		}
 
-		// 2. 解析Bearer Token
+		// 3. 解析Bearer Token
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.Unauthorized(c, "invalid authorization header format")
+			utils.Unauthorized(c, "auth.invalid_header_format")
 			c.Abort()
 			return
 		}
 
 		tokenString := parts[1]
 
-		// 3. 验证Token
-		userID, err := authService.ValidateToken(tokenString)
+		// 4. 验证Token
+		claims, err := authService.ValidateToken(c.Request.Context(), tokenString)
 		if err != nil {
-			utils.Unauthorized(c, "invalid or expired token")
+			utils.Unauthorized(c, "auth.invalid_token")
 			c.Abort()
 			return
 		}
 
-		// 4. 将用户ID存入上下文
-		c.Set("user_id", userID)
+		// 5. 将用户ID存入上下文
+		c.Set("user_id", claims.UserID)
+		c.Set("token_claims", claims)
+		c.Set("auth_scope", models.APIKeyScopeWrite)
+
+		// 6. 继续处理请求
+		c.Next()
+	}
+}
 
-		// 5. 继续处理请求
+// UserTokenOnly 仅接受JWT认证（需在AuthMiddleware之后使用）：API Key不能管理Key本身或用户会话，避免泄露的Key自我续期或注销用户登录
+func UserTokenOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("token_claims"); !ok {
+			utils.Forbidden(c, "auth.user_token_required")
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// isReadOnlyMethod 判断是否为只读HTTP方法
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
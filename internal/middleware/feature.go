@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/service"
+	"crypto-wallet-api/internal/utils"
+)
+
+// FeatureGate 功能开关中间件（开关关闭时返回503）
+func FeatureGate(flags *service.FeatureFlagService, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := flags.Check(c.Request.Context(), name); err != nil {
+			abortFeatureDisabled(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ReadOnlyMaintenance 只读维护模式中间件（维护期间只允许只读请求）
+func ReadOnlyMaintenance(flags *service.FeatureFlagService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isReadOnlyMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if err := flags.CheckWritable(c.Request.Context()); err != nil {
+			abortFeatureDisabled(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// abortFeatureDisabled 返回功能关闭响应并中止请求
+func abortFeatureDisabled(c *gin.Context, err error) {
+	var disabledErr *service.FeatureDisabledError
+	if errors.As(err, &disabledErr) {
+		utils.ErrorWithData(c, http.StatusServiceUnavailable, utils.CodeFeatureDisabled, apperr.MessageKey(service.ErrFeatureDisabled), disabledErr.Data)
+	} else {
+		utils.InternalError(c, err)
+	}
+	c.Abort()
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipWriters 复用gzip压缩器（每个压缩器约占用数百KB内存）
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// GzipMiddleware 响应压缩中间件：客户端Accept-Encoding支持gzip时压缩响应体；
+// 响应体小于minSize字节、处理函数已设置Content-Encoding或路由使用NoCompression时原样返回
+func GzipMiddleware(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer, minSize: minSize}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// NoCompression 路由级中间件：本次响应不压缩（流式导出等需要边生成边发送的响应）
+func NoCompression() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if w, ok := c.Writer.(*gzipWriter); ok && !w.decided {
+			_ = w.start(false)
+		}
+		c.Next()
+	}
+}
+
+// acceptsGzip 判断Accept-Encoding是否接受gzip（q=0表示拒绝）
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q, ok := strings.CutPrefix(strings.ReplaceAll(strings.ToLower(params), " ", ""), "q=")
+		if !ok {
+			return true
+		}
+		weight, err := strconv.ParseFloat(q, 64)
+		return err == nil && weight > 0
+	}
+	return false
+}
+
+// gzipWriter 缓冲响应体的前minSize字节后决定是否压缩
+type gzipWriter struct {
+	gin.ResponseWriter
+	minSize int
+
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+// Write 未决定是否压缩时先缓冲，缓冲达到minSize后开始压缩
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 同Write
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow 立即发送响应头（此时尚无响应体，不压缩）
+func (w *gzipWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written 响应体已开始写入（含缓冲中）时返回true
+func (w *gzipWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush 发送已写入的数据（缓冲未达到minSize时按流式响应处理，开始压缩）
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		_ = w.start(true)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap 供http.ResponseController访问底层连接（设置写超时等）
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// start 决定是否压缩并写出已缓冲的数据
+func (w *gzipWriter) start(compress bool) error {
+	w.decided = true
+	status := w.Status()
+	if compress && w.Header().Get("Content-Encoding") == "" &&
+		status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish 请求处理完成：写出未达到minSize的缓冲数据，或结束压缩流
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+	}
+}
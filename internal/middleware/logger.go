@@ -22,7 +22,7 @@ func LoggerMiddleware() gin.HandlerFunc {
 		latency := time.Since(startTime)
 
 		// 记录日志
-		logger.Info("HTTP Request",
+		logger.WithCtx(c.Request.Context()).Info("HTTP Request",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.Int("status", c.Writer.Status()),
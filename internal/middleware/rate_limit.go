@@ -1,25 +1,202 @@
 package middleware
 
 import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 
+	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/utils"
 )
 
-// RateLimitMiddleware 限流中间件（令牌桶算法）
-func RateLimitMiddleware(requestsPerSecond float64, burst int) gin.HandlerFunc {
-	// 创建限流器
-	limiter := rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+// userLimiterSweepInterval 清理已回满的用户令牌桶的间隔
+const userLimiterSweepInterval = time.Minute
+
+// RateLimiter 全局限流器（令牌桶算法，参数支持运行时调整）
+type RateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+	}
+}
+
+// Update 调整限流参数（并发安全）
+func (l *RateLimiter) Update(requestsPerSecond float64, burst int) {
+	l.limiter.SetLimit(rate.Limit(requestsPerSecond))
+	l.limiter.SetBurst(burst)
+}
+
+// Allow 尝试获取令牌（HTTP与gRPC共用同一个令牌桶）
+func (l *RateLimiter) Allow() bool {
+	return l.limiter.Allow()
+}
 
+// Middleware 限流中间件
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 尝试获取令牌
-		if !limiter.Allow() {
-			utils.ErrorJson(c, 429, utils.CodeInvalidParams, "rate limit exceeded")
-			c.Abort()
+		if !takeToken(c, l.limiter) {
+			rateLimitExceeded(c, models.RateLimitBucketGlobal)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// RateLimitMiddleware 限流中间件（令牌桶算法，参数固定）
+func RateLimitMiddleware(requestsPerSecond float64, burst int) gin.HandlerFunc {
+	return NewRateLimiter(requestsPerSecond, burst).Middleware()
+}
+
+// UserRateLimiter 按用户限流的命名令牌桶（用于调用外部系统或产生重查询的路由组，在全局限流之外生效）
+//
+// 令牌桶保存在进程内存中，已回满的令牌桶会被定期清理（清理后重新创建的令牌桶同样是满的，不影响限流结果）。
+type UserRateLimiter struct {
+	name string
+
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewUserRateLimiter 创建按用户限流的命名令牌桶
+func NewUserRateLimiter(name string, requestsPerSecond float64, burst int) *UserRateLimiter {
+	return &UserRateLimiter{
+		name:      name,
+		limit:     rate.Limit(requestsPerSecond),
+		burst:     burst,
+		limiters:  make(map[string]*rate.Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Name 令牌桶名称
+func (l *UserRateLimiter) Name() string {
+	return l.name
+}
+
+// Update 调整限流参数（并发安全，已有用户的令牌桶同时生效）
+func (l *UserRateLimiter) Update(requestsPerSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(requestsPerSecond)
+	l.burst = burst
+	for _, limiter := range l.limiters {
+		limiter.SetLimit(l.limit)
+		limiter.SetBurst(l.burst)
+	}
+}
+
+// Allow 尝试获取指定用户的令牌
+func (l *UserRateLimiter) Allow(key string) bool {
+	now := time.Now()
+	return l.limiterFor(key, now).AllowN(now, 1)
+}
+
+// limiterFor 获取指定用户的令牌桶（不存在时创建）
+func (l *UserRateLimiter) limiterFor(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= userLimiterSweepInterval {
+		l.sweepLocked(now)
+	}
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// sweepLocked 删除已回满的令牌桶（调用方持有锁）
+func (l *UserRateLimiter) sweepLocked(now time.Time) {
+	for key, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.limiters, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware 按用户限流中间件（需在认证中间件之后使用，未认证的请求按客户端IP计数）
+func (l *UserRateLimiter) Middleware() gin.HandlerFunc {
+	return l.MiddlewareWhen(nil)
+}
+
+// MiddlewareWhen 仅对满足条件的请求限流的中间件（如只限制强制刷新余额的请求）
+func (l *UserRateLimiter) MiddlewareWhen(cond func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cond != nil && !cond(c) {
+			c.Next()
+			return
+		}
+
+		if !takeToken(c, l.limiterFor(rateLimitKey(c), time.Now())) {
+			rateLimitExceeded(c, l.name)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey 按用户限流的计数键
+func rateLimitKey(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// takeToken 尝试获取令牌并写入限流响应头（同一请求经过多个令牌桶时，以最后一个令牌桶为准）
+//
+//   - X-RateLimit-Limit: 令牌桶容量
+//   - X-RateLimit-Remaining: 本次请求后剩余的令牌数
+//   - X-RateLimit-Reset: 令牌桶回满所需的秒数
+//   - Retry-After: 被限流时下一个令牌可用所需的秒数
+func takeToken(c *gin.Context, limiter *rate.Limiter) bool {
+	now := time.Now()
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+	burst := limiter.Burst()
+	limit := float64(limiter.Limit())
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+	if limit > 0 {
+		c.Header("X-RateLimit-Reset", strconv.Itoa(secondsUntil(float64(burst)-tokens, limit)))
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(max(1, secondsUntil(1-tokens, limit))))
+		}
+	}
+	return allowed
+}
+
+// secondsUntil 以limit速率补充missing个令牌所需的秒数（向上取整）
+func secondsUntil(missing, limit float64) int {
+	if missing <= 0 {
+		return 0
+	}
+	return int(math.Ceil(missing / limit))
+}
+
+// rateLimitExceeded 返回429响应（附带超出的令牌桶名称）
+func rateLimitExceeded(c *gin.Context, bucket string) {
+	utils.ErrorWithData(c, http.StatusTooManyRequests, utils.CodeRateLimited, utils.CodeMessageKey(utils.CodeRateLimited), &models.RateLimitExceededData{Bucket: bucket})
+	c.Abort()
+}
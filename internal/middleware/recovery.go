@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/utils"
+)
+
+// RecoveryMiddleware 将处理过程中的panic转换为统一错误响应（500，业务码CodeInternalError），堆栈只记录日志
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			// 1. http.ErrAbortHandler表示主动中止响应，交给net/http处理
+			if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(r)
+			}
+
+			// 2. 记录panic与堆栈（附带请求ID）
+			logger.WithCtx(c.Request.Context()).Error("HTTP handler panic",
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			// 3. 响应尚未写出时返回统一错误结构（已写出部分响应时只能中止）
+			c.Abort()
+			if !c.Writer.Written() {
+				utils.ErrorJson(c, http.StatusInternalServerError, utils.CodeInternalError, utils.CodeMessageKey(utils.CodeInternalError))
+			}
+		}()
+		c.Next()
+	}
+}
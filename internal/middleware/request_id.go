@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// RequestIDHeader 请求ID请求/响应头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware 请求ID中间件（沿用客户端传入的X-Request-ID，否则生成UUID）
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. 获取或生成请求ID（限制长度，避免日志被超长头部污染）
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		// 2. 写入gin上下文与请求context，供日志和错误响应使用
+		c.Set("request_id", requestID)
+		ctx := logger.ContextWithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		// 3. 关联到当前Span
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", requestID))
+
+		// 4. 返回给客户端
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
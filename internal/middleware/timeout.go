@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// baseContextKey 设置处理时限前的请求context（路由级RequestTimeout在其基础上重新设置时限）
+const baseContextKey = "request_base_context"
+
+// TimeoutMiddleware 请求处理时限中间件：为c.Request.Context()设置截止时间，到期后仍在等待的链节点、Redis与数据库调用返回超时错误（响应504）；
+// timeout为0表示不限制
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(baseContextKey, c.Request.Context())
+		withDeadline(c, c.Request.Context(), timeout)
+	}
+}
+
+// RequestTimeout 路由级中间件：以timeout替换全局处理时限（发送交易需要更长的时限，流式导出与WebSocket传0不限制）
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base, ok := c.Value(baseContextKey).(context.Context)
+		if !ok {
+			base = c.Request.Context()
+		}
+		withDeadline(c, base, timeout)
+	}
+}
+
+// withDeadline 以ctx加上时限作为后续处理的请求context
+func withDeadline(c *gin.Context, ctx context.Context, timeout time.Duration) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
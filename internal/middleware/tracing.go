@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"crypto-wallet-api/internal/tracing"
+)
+
+// TracingMiddleware 链路追踪中间件（为每个请求创建根Span，并延续上游传入的Trace）
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. 从请求头恢复上游链路上下文
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		// 2. 创建根Span（优先使用路由模板，避免路径参数导致Span名称发散）
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("%s %s", c.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("client.address", c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		// 3. 将Span上下文传递给后续处理链
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		// 4. 记录响应状态
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			span.SetAttributes(attribute.Int64("user.id", int64(userID.(uint))))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WalletActivityType 钱包动态类型
+type WalletActivityType string
+
+const (
+	ActivityTxOutgoing           WalletActivityType = "transaction.outgoing"         // 转出交易
+	ActivityTxIncoming           WalletActivityType = "transaction.incoming"         // 转入交易
+	ActivityBalanceChanged       WalletActivityType = "balance.changed"              // 余额变化
+	ActivityWalletRenamed        WalletActivityType = "wallet.renamed"               // 钱包改名
+	ActivitySettingsChanged      WalletActivityType = "wallet.settings_changed"      // 安全设置变更
+	ActivityLimitsChanged        WalletActivityType = "wallet.limits_changed"        // 每日限额变更
+	ActivityApprovalChanged      WalletActivityType = "wallet.approval_changed"      // 审批策略变更
+	ActivityArchiveChanged       WalletActivityType = "wallet.archive_changed"       // 归档或取消归档
+	ActivityConfirmationsChanged WalletActivityType = "wallet.confirmations_changed" // 最终确认深度变更
+	ActivityWhitelistAdded       WalletActivityType = "whitelist.added"              // 添加白名单地址
+	ActivityWhitelistRemoved     WalletActivityType = "whitelist.removed"            // 删除白名单地址
+	ActivityKeyExported          WalletActivityType = "wallet.key_exported"          // 导出私钥备份
+)
+
+// WalletActivity 钱包变更记录（余额快照与设置变更，交易不在此表）
+type WalletActivity struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	WalletID  uint               `gorm:"not null;index:idx_wallet_activities_feed,priority:1" json:"wallet_id"` // 所属钱包ID
+	Type      WalletActivityType `gorm:"not null;size:50" json:"type"`                                          // 动态类型
+	Details   string             `gorm:"type:text" json:"details"`                                              // 详情JSON（结构随类型不同）
+	CreatedAt time.Time          `gorm:"index:idx_wallet_activities_feed,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (WalletActivity) TableName() string {
+	return "wallet_activities"
+}
+
+// BalanceChangedDetails 余额变化详情（Wei）
+type BalanceChangedDetails struct {
+	Previous string `json:"previous"`
+	Balance  string `json:"balance"`
+	Delta    string `json:"delta"` // 可为负数
+}
+
+// WalletRenamedDetails 钱包改名详情
+type WalletRenamedDetails struct {
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+// SettingsChangedDetails 安全设置变更详情
+type SettingsChangedDetails struct {
+	WhitelistEnabled bool `json:"whitelist_enabled"`
+}
+
+// ArchiveChangedDetails 归档状态变更详情
+type ArchiveChangedDetails struct {
+	Archived bool `json:"archived"`
+}
+
+// ConfirmationsChangedDetails 最终确认深度变更详情（0表示使用链配置）
+type ConfirmationsChangedDetails struct {
+	ConfirmationsRequired uint64 `json:"confirmations_required"`
+}
+
+// LimitsChangedDetails 每日限额变更详情
+type LimitsChangedDetails struct {
+	DailyLimitWei string `json:"daily_limit_wei,omitempty"`
+	DailyTxLimit  int    `json:"daily_tx_limit"`
+}
+
+// ApprovalChangedDetails 审批策略变更详情
+type ApprovalChangedDetails struct {
+	ThresholdWei      string `json:"threshold_wei,omitempty"`
+	RequiredApprovals int    `json:"required_approvals"`
+	ApproverIDs       []uint `json:"approver_ids,omitempty"`
+}
+
+// WhitelistChangedDetails 白名单变更详情
+type WhitelistChangedDetails struct {
+	EntryID     uint       `json:"entry_id"`
+	Address     string     `json:"address,omitempty"`
+	Label       string     `json:"label,omitempty"`
+	ActivatesAt *time.Time `json:"activates_at,omitempty"`
+}
+
+// KeyExportedDetails 导出私钥备份详情
+type KeyExportedDetails struct {
+	Format string `json:"format"` // 备份格式（keystore）
+	UserID uint   `json:"user_id"`
+	IP     string `json:"ip,omitempty"`
+}
+
+// ActivityCursor 动态流游标（按created_at、来源、id倒序翻页）
+type ActivityCursor struct {
+	CreatedAt time.Time `json:"t"`
+	Source    int       `json:"s"` // 0=钱包变更记录 1=交易
+	ID        uint      `json:"i"`
+}
+
+// 动态来源（同一时间戳内的排序依据）
+const (
+	ActivitySourceChange      = 0
+	ActivitySourceTransaction = 1
+)
+
+// ActivityFeedRequest 钱包动态查询请求
+type ActivityFeedRequest struct {
+	Cursor string `form:"cursor"`                                  // 上一页返回的next_cursor，首页为空
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"` // 每页数量，默认20
+}
+
+// ActivityItem 钱包动态（按type区分：transaction.*携带transaction，其余携带details）
+type ActivityItem struct {
+	Type        WalletActivityType   `json:"type"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+	Details     json.RawMessage      `json:"details,omitempty"`
+}
+
+// ActivityFeedResponse 钱包动态响应
+type ActivityFeedResponse struct {
+	Items      []*ActivityItem `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"` // 为空表示没有更多
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+)
+
+// ChainCount 按链统计的数量
+type ChainCount struct {
+	ChainID int   `json:"chain_id"`
+	Count   int64 `json:"count"`
+}
+
+// ChainStatusCount 按链与状态统计的交易数量
+type ChainStatusCount struct {
+	ChainID int               `json:"chain_id"`
+	Status  TransactionStatus `json:"status"`
+	Count   int64             `json:"count"`
+}
+
+// ChainVolumeStat 按链统计的成交量与确认耗时
+type ChainVolumeStat struct {
+	ChainID                int     `json:"chain_id"`
+	Volume24h              string  `json:"volume_24h"`               // 最近24小时成功交易金额合计（原生币）
+	Volume7d               string  `json:"volume_7d"`                // 最近7天成功交易金额合计（原生币）
+	AvgConfirmationSeconds float64 `json:"avg_confirmation_seconds"` // 最近7天平均确认耗时（confirmed_at - created_at，秒）
+}
+
+// AdminRPCEndpoint 单个RPC节点状态
+type AdminRPCEndpoint struct {
+	Name        string `json:"name"`
+	Healthy     bool   `json:"healthy"`
+	LatestBlock uint64 `json:"latest_block"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// AdminRPCHealth 链的RPC健康状态
+type AdminRPCHealth struct {
+	Status      string              `json:"status"` // up, down
+	LatestBlock uint64              `json:"latest_block"`
+	LatencyMs   int64               `json:"latency_ms"`
+	Error       string              `json:"error,omitempty"`
+	Endpoints   []*AdminRPCEndpoint `json:"endpoints,omitempty"` // 多节点时各节点的状态
+}
+
+// AdminChainStats 单条链的统计
+type AdminChainStats struct {
+	ChainID                int                         `json:"chain_id"`
+	Wallets                int64                       `json:"wallets"`
+	Transactions           map[TransactionStatus]int64 `json:"transactions"`
+	Volume24h              string                      `json:"volume_24h"`
+	Volume7d               string                      `json:"volume_7d"`
+	AvgConfirmationSeconds float64                     `json:"avg_confirmation_seconds"`
+	RPC                    *AdminRPCHealth             `json:"rpc,omitempty"` // 未连接该链的节点时为空
+}
+
+// AdminQueueStats 队列积压情况
+type AdminQueueStats struct {
+	Queue     string `json:"queue"`
+	Messages  int    `json:"messages"`  // 待消费消息数
+	Consumers int    `json:"consumers"` // 消费者数量
+	Retrying  int    `json:"retrying"`  // 等待重试的消息数
+	Dead      int    `json:"dead"`      // 死信消息数
+	Error     string `json:"error,omitempty"`
+}
+
+// AdminStatsResponse 运维总览统计
+type AdminStatsResponse struct {
+	Users        int64                       `json:"users"`
+	Wallets      int64                       `json:"wallets"`
+	Transactions map[TransactionStatus]int64 `json:"transactions"`
+	Chains       []*AdminChainStats          `json:"chains"`
+	Queues       []*AdminQueueStats          `json:"queues"`
+	GeneratedAt  time.Time                   `json:"generated_at"` // 统计时间（结果缓存60秒）
+}
+
+// InternalLedgerEntry 存在未结算内部转账净额的钱包（金额均为Wei）
+type InternalLedgerEntry struct {
+	WalletID        uint   `json:"wallet_id"`
+	Address         string `json:"address"`
+	ChainID         int    `json:"chain_id"`
+	LedgerBalance   string `json:"ledger_balance"`    // 账本余额
+	InternalNet     string `json:"internal_net"`      // 内部转账净额（转入为正）
+	RecordedOnChain string `json:"recorded_onchain"`  // 账本记录的链上余额（账本余额 − 内部转账净额）
+	OnChain         string `json:"onchain,omitempty"` // 实时链上余额，查询失败时为空
+	Unbacked        bool   `json:"unbacked"`          // 账本余额超过实时链上余额（需在链上结算后才能全部转出）
+	Drift           bool   `json:"drift"`             // 记录的链上余额与实时链上余额不一致（余额同步落后）
+	Error           string `json:"error,omitempty"`
+}
+
+// InternalLedgerChain 单条链的内部转账净额合计（内部转账不增减总额，合计应为0）
+type InternalLedgerChain struct {
+	ChainID  int    `json:"chain_id"`
+	NetTotal string `json:"net_total"`
+	Balanced bool   `json:"balanced"`
+}
+
+// InternalLedgerReport 内部转账账本与链上余额的对账报告
+type InternalLedgerReport struct {
+	Chains      []*InternalLedgerChain `json:"chains"`
+	Wallets     []*InternalLedgerEntry `json:"wallets"`
+	Flagged     int                    `json:"flagged"` // 标记为unbacked或drift的钱包数
+	GeneratedAt time.Time              `json:"generated_at"`
+}
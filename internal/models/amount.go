@@ -0,0 +1,36 @@
+package models
+
+import (
+	"math/big"
+	"strings"
+)
+
+// AmountUnit 请求中原生币金额的单位
+type AmountUnit string
+
+const (
+	AmountUnitWei AmountUnit = "wei" // 最小单位的十进制整数，如"500000000000000000"（默认，兼容未指定单位的客户端）
+	AmountUnitEth AmountUnit = "eth" // 原生币主单位的十进制数，如"0.5"（BSC上即BNB），最多18位小数
+)
+
+// etherDecimals 原生币的小数位数（交易记录中的金额为18位小数的主单位金额）
+const etherDecimals = 18
+
+// nativeAmountWei 将交易记录中的原生币金额（主单位十进制字符串）精确换算为Wei，无法解析时返回空字符串
+func nativeAmountWei(amount string) string {
+	integer, fraction, _ := strings.Cut(amount, ".")
+	if len(fraction) > etherDecimals {
+		return ""
+	}
+	wei, ok := new(big.Int).SetString(integer+fraction+strings.Repeat("0", etherDecimals-len(fraction)), 10)
+	if !ok {
+		return ""
+	}
+	return wei.String()
+}
+
+// nativeSymbol 链的原生币符号（未配置的链返回空字符串）
+func nativeSymbol(chainID int) string {
+	chain, _ := LookupChain(chainID)
+	return chain.NativeSymbol
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// API Key权限范围
+const (
+	APIKeyScopeRead  = "read"  // 只读：仅允许GET请求
+	APIKeyScopeWrite = "write" // 读写：与JWT登录权限一致
+)
+
+// APIKey API密钥模型（用于机器对机器调用）
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	Name       string     `gorm:"size:100" json:"name"`                  // 密钥名称
+	Prefix     string     `gorm:"unique;not null;size:16" json:"prefix"` // 公开前缀（用于查找密钥）
+	KeyHash    string     `gorm:"not null;size:64" json:"-"`             // 完整密钥的SHA-256哈希
+	Scope      string     `gorm:"not null;size:20" json:"scope"`         // 权限范围：read, write
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                // 最后使用时间
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// APIKeyCreateRequest 创建API Key请求
+type APIKeyCreateRequest struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Scope string `json:"scope" binding:"omitempty,oneof=read write"` // 默认write
+}
+
+// APIKeyResponse API Key响应
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	Key        string     `json:"key,omitempty"` // 完整密钥，仅创建时返回一次
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (k *APIKey) ToResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scope:      k.Scope,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"math/big"
+	"time"
+)
+
+// ApprovalDecision 审批决定
+type ApprovalDecision string
+
+const (
+	ApprovalApproved ApprovalDecision = "approved" // 批准
+	ApprovalRejected ApprovalDecision = "rejected" // 拒绝
+)
+
+// WalletApprover 钱包审批人（超过审批阈值的转账需由审批人批准后才签名广播）
+type WalletApprover struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	WalletID  uint      `gorm:"not null;uniqueIndex:idx_wallet_approvers_wallet_user,priority:1" json:"wallet_id"`     // 钱包ID
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_wallet_approvers_wallet_user,priority:2;index" json:"user_id"` // 审批人用户ID
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (WalletApprover) TableName() string {
+	return "wallet_approvers"
+}
+
+// TransactionApproval 交易审批记录（每位审批人对同一交易只记录一次）
+type TransactionApproval struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	TransactionID uint             `gorm:"not null;uniqueIndex:idx_transaction_approvals_tx_user,priority:1" json:"transaction_id"` // 交易ID
+	UserID        uint             `gorm:"not null;uniqueIndex:idx_transaction_approvals_tx_user,priority:2" json:"user_id"`        // 审批人用户ID
+	Decision      ApprovalDecision `gorm:"not null;size:20" json:"decision"`                                                        // 审批决定
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TransactionApproval) TableName() string {
+	return "transaction_approvals"
+}
+
+// RequiresApproval 转账金额是否超过钱包审批阈值
+func (w *Wallet) RequiresApproval(amount *big.Int) bool {
+	if w.ApprovalThresholdWei == "" || w.RequiredApprovals == 0 {
+		return false
+	}
+	threshold, ok := new(big.Int).SetString(w.ApprovalThresholdWei, 10)
+	if !ok {
+		return false
+	}
+	return amount.Cmp(threshold) > 0
+}
+
+// ApprovalPolicyRequest 钱包审批策略设置请求
+type ApprovalPolicyRequest struct {
+	ThresholdWei      string `json:"threshold_wei" binding:"omitempty,numeric"`                // 超过该金额（Wei）的转账需要审批，为空或0表示关闭
+	RequiredApprovals int    `json:"required_approvals" binding:"min=0,max=20"`                // 所需的不同审批人数量
+	ApproverIDs       []uint `json:"approver_ids" binding:"omitempty,max=20,unique,dive,gt=0"` // 审批人用户ID（交易发起人不能批准自己发起的交易）
+}
+
+// ApprovalPolicyResponse 钱包审批策略响应
+type ApprovalPolicyResponse struct {
+	ThresholdWei      string `json:"threshold_wei,omitempty"`
+	RequiredApprovals int    `json:"required_approvals"`
+	ApproverIDs       []uint `json:"approver_ids"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// SnapshotResolution 余额快照粒度
+type SnapshotResolution string
+
+const (
+	SnapshotHourly SnapshotResolution = "hour" // 每小时一条（保留最近数天）
+	SnapshotDaily  SnapshotResolution = "day"  // 每天一条（长期保留）
+)
+
+// Duration 快照粒度对应的时间长度
+func (r SnapshotResolution) Duration() time.Duration {
+	if r == SnapshotDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Bucket 时间所在的快照时间段起点（UTC）
+func (r SnapshotResolution) Bucket(t time.Time) time.Time {
+	return t.UTC().Truncate(r.Duration())
+}
+
+// BalanceSnapshot 钱包余额快照（同一时间段只保留一条，记录该时间段内最后一次观测到的余额）
+type BalanceSnapshot struct {
+	ID         uint               `gorm:"primaryKey" json:"-"`
+	WalletID   uint               `gorm:"not null;uniqueIndex:idx_balance_snapshots_bucket,priority:1" json:"-"`
+	Resolution SnapshotResolution `gorm:"not null;size:10;uniqueIndex:idx_balance_snapshots_bucket,priority:2" json:"-"`
+	TakenAt    time.Time          `gorm:"not null;uniqueIndex:idx_balance_snapshots_bucket,priority:3;index" json:"taken_at"` // 时间段起点（UTC）
+	Balance    string             `gorm:"type:decimal(78,0);not null" json:"balance"`                                         // 余额（Wei）
+	USDValue   *string            `gorm:"type:decimal(24,2)" json:"usd_value,omitempty"`                                      // 快照时的美元估值，价格不可用时为空
+	CreatedAt  time.Time          `json:"-"`
+	UpdatedAt  time.Time          `json:"-"`
+}
+
+// TableName 指定表名
+func (BalanceSnapshot) TableName() string {
+	return "balance_snapshots"
+}
+
+// BalanceHistoryRequest 余额历史查询参数
+type BalanceHistoryRequest struct {
+	Resolution SnapshotResolution `form:"resolution" binding:"omitempty,oneof=hour day"`
+	From       time.Time          `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // 开始时间（RFC3339），默认：小时粒度为24小时前，天粒度为30天前
+	To         time.Time          `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // 结束时间（RFC3339），默认当前时间
+}
+
+// BalanceHistoryPoint 余额曲线上的一个点（无快照的时间段沿用上一个值）
+type BalanceHistoryPoint struct {
+	Time     time.Time `json:"time"`
+	Balance  string    `json:"balance"`             // 余额（Wei）
+	USDValue *string   `json:"usd_value,omitempty"` // 美元估值
+}
+
+// BalanceHistoryResponse 余额历史响应
+type BalanceHistoryResponse struct {
+	Address    string                 `json:"address"`
+	Resolution SnapshotResolution     `json:"resolution"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Points     []*BalanceHistoryPoint `json:"points"`
+}
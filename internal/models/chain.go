@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Chain 链元数据（来自blockchain.chains配置，启动时通过SetChains注册）
+type Chain struct {
+	ChainID      int    `json:"chain_id"`
+	Name         string `json:"name"`
+	NativeSymbol string `json:"native_symbol"`
+	Decimals     int    `json:"decimals"`
+	ExplorerURL  string `json:"explorer_url,omitempty"`
+	EIP1559      bool   `json:"eip1559"`
+	Enabled      bool   `json:"enabled"` // 本部署是否允许在该链上创建钱包与发送交易（blockchain.allowed_chains/denied_chains），禁用后已有钱包仍可查询
+}
+
+// chainRegistry 已配置的链（按配置顺序）
+var chainRegistry struct {
+	sync.RWMutex
+	list []Chain
+	byID map[int]Chain
+}
+
+// SetChains 注册已配置的链（替换之前的注册结果）
+func SetChains(chains []Chain) {
+	byID := make(map[int]Chain, len(chains))
+	for _, chain := range chains {
+		byID[chain.ChainID] = chain
+	}
+
+	chainRegistry.Lock()
+	defer chainRegistry.Unlock()
+	chainRegistry.list = append([]Chain(nil), chains...)
+	chainRegistry.byID = byID
+}
+
+// Chains 已配置的链（按配置顺序）
+func Chains() []Chain {
+	chainRegistry.RLock()
+	defer chainRegistry.RUnlock()
+	return append([]Chain(nil), chainRegistry.list...)
+}
+
+// LookupChain 查询链元数据，未配置的链返回false
+func LookupChain(chainID int) (Chain, bool) {
+	chainRegistry.RLock()
+	defer chainRegistry.RUnlock()
+	chain, ok := chainRegistry.byID[chainID]
+	return chain, ok
+}
+
+// SupportedChain 链是否已配置（请求中的chain_id校验）
+func SupportedChain(chainID int) bool {
+	_, ok := LookupChain(chainID)
+	return ok
+}
+
+// ChainEnabled 链是否已配置且未被部署策略禁用（创建、导入钱包与发送交易前校验）
+func ChainEnabled(chainID int) bool {
+	chain, ok := LookupChain(chainID)
+	return ok && chain.Enabled
+}
+
+// ChainName 获取链名称（已从配置中移除的链返回"Chain <id>"，旧记录仍可区分）
+func ChainName(chainID int) string {
+	if chain, ok := LookupChain(chainID); ok {
+		return chain.Name
+	}
+	return fmt.Sprintf("Chain %d", chainID)
+}
+
+// ChainInfo 链发现接口返回的链信息（配置的元数据与当前运行状态）
+type ChainInfo struct {
+	Chain
+	SendsEnabled bool      `json:"sends_enabled"`          // 当前能否发送交易（部署策略允许、发送功能未关闭、非只读维护且链头正常推进）
+	GasPrices    *GasTiers `json:"gas_prices,omitempty"`   // 当前三档Gas价格（缓存中没有时省略）
+	LatestBlock  uint64    `json:"latest_block,omitempty"` // 本地最近观测到的最新区块号（未监控或尚未查询成功时省略）
+}
+
+// ExplorerTxURL 交易在区块浏览器中的链接（<explorer_url>/tx/<hash>）；链未配置浏览器或不是链上交易哈希（如内部转账）时返回空字符串
+func ExplorerTxURL(chainID int, txHash string) string {
+	if !strings.HasPrefix(txHash, "0x") {
+		return ""
+	}
+	return explorerURL(chainID, "tx", txHash)
+}
+
+// ExplorerAddressURL 地址在区块浏览器中的链接（<explorer_url>/address/<address>），链未配置浏览器时返回空字符串
+func ExplorerAddressURL(chainID int, address string) string {
+	if address == "" {
+		return ""
+	}
+	return explorerURL(chainID, "address", address)
+}
+
+// explorerURL 按Etherscan系浏览器的路径约定拼接链接
+func explorerURL(chainID int, kind, id string) string {
+	chain, ok := LookupChain(chainID)
+	if !ok || chain.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(chain.ExplorerURL, "/") + "/" + kind + "/" + id
+}
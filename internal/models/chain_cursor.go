@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// ChainCursor 链上扫描进度（按链与扫描主题记录最后处理的区块，重启后从此处继续）
+type ChainCursor struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ChainID     int       `gorm:"not null;uniqueIndex:idx_chain_cursors_chain_topic" json:"chain_id"`       // 链ID
+	Topic       string    `gorm:"not null;size:100;uniqueIndex:idx_chain_cursors_chain_topic" json:"topic"` // 扫描主题，如erc20_transfer
+	BlockNumber uint64    `gorm:"not null" json:"block_number"`                                             // 最后处理完成的区块号
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ChainCursor) TableName() string {
+	return "chain_cursors"
+}
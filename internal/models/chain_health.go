@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// ChainHeadStatus 链头状态（本地观测到的最新区块号及其推进时间）
+type ChainHeadStatus struct {
+	ChainID     int           `json:"chain_id"`
+	LatestBlock uint64        `json:"latest_block"`
+	Age         time.Duration `json:"-"`           // 距区块号上一次推进的时间
+	AgeSeconds  int64         `json:"age_seconds"` // 同Age（秒）
+	StaleAfter  int64         `json:"stale_after"` // 判定为不健康的阈值（秒）
+	Degraded    bool          `json:"degraded"`    // 链头超过阈值未推进，暂停发送交易
+	LastError   string        `json:"last_error,omitempty"`
+}
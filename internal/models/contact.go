@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Contact 地址簿联系人模型
+type Contact struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_contacts_user_address_chain" json:"user_id"`         // 所属用户ID
+	Name      string    `gorm:"not null;size:100" json:"name"`                                               // 联系人名称
+	Address   string    `gorm:"not null;size:42;uniqueIndex:idx_contacts_user_address_chain" json:"address"` // 收款地址
+	ChainID   int       `gorm:"not null;uniqueIndex:idx_contacts_user_address_chain" json:"chain_id"`        // 链ID
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Contact) TableName() string {
+	return "contacts"
+}
+
+// ContactCreateRequest 创建联系人请求
+type ContactCreateRequest struct {
+	Name    string `json:"name" binding:"required,max=100"`
+	Address string `json:"address" binding:"required,eth_addr_or_ens"` // 地址或ENS名称（保存解析后的地址）
+	ChainID int    `json:"chain_id" binding:"required,chain_id"`
+}
+
+// ContactUpdateRequest 更新联系人请求（字段均可选）
+type ContactUpdateRequest struct {
+	Name    string `json:"name" binding:"omitempty,max=100"`
+	Address string `json:"address" binding:"omitempty,eth_addr_or_ens"` // 地址或ENS名称（保存解析后的地址）
+	ChainID int    `json:"chain_id" binding:"omitempty,chain_id"`
+}
+
+// ContactResponse 联系人响应
+type ContactResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	ChainID   int       `json:"chain_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse 转换为响应格式
+func (c *Contact) ToResponse() *ContactResponse {
+	return &ContactResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Address:   c.Address,
+		ChainID:   c.ChainID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
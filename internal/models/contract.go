@@ -0,0 +1,38 @@
+package models
+
+// ContractCallRequest 合约只读调用请求
+type ContractCallRequest struct {
+	ContractAddress string        `json:"contract_address" binding:"required,eth_addr"`
+	ABI             string        `json:"abi" binding:"required_without=Method"`  // 完整ABI数组或单个方法片段（JSON字符串）
+	Method          string        `json:"method"`                                 // 方法名（ABI仅含一个方法时可省略），未提供ABI时为方法签名
+	Args            []interface{} `json:"args"`                                   // 方法参数，大整数请使用字符串
+	BlockNumber     *int64        `json:"block_number" binding:"omitempty,min=0"` // 查询区块高度，默认最新区块
+	ChainID         int           `json:"chain_id" binding:"omitempty,chain_id"`  // 调用所在链，默认为第一条连接了节点的链
+}
+
+// ContractOutput 合约调用的单个返回值
+type ContractOutput struct {
+	Name  string      `json:"name,omitempty"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ContractCallResponse 合约只读调用响应
+type ContractCallResponse struct {
+	ContractAddress string            `json:"contract_address"`
+	Method          string            `json:"method"`
+	Outputs         []*ContractOutput `json:"outputs"`
+}
+
+// ContractTransactionRequest 合约写调用请求（通过托管钱包签名广播）
+type ContractTransactionRequest struct {
+	FromAddress     string        `json:"from_address" binding:"required,eth_addr"`
+	ContractAddress string        `json:"contract_address" binding:"required,eth_addr"`
+	ABI             string        `json:"abi"`                       // 完整ABI数组或单个方法片段，可省略
+	Method          string        `json:"method" binding:"required"` // 方法名，未提供ABI时为方法签名，如"approve(address,uint256)"
+	Args            []interface{} `json:"args"`                      // 方法参数，大整数请使用字符串
+	Value           string        `json:"value"`                     // 随调用转入的金额（Wei，仅十进制数字），默认0
+	ChainID         int           `json:"chain_id" binding:"required,chain_id"`
+	GasLimit        int64         `json:"gas_limit" binding:"omitempty,gt=0"` // 可选，默认按calldata估算
+	Passphrase      string        `json:"passphrase,omitempty"`               // 钱包私钥口令（钱包设置了口令时必填）
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// WalletEventType 钱包事件类型
+type WalletEventType string
+
+const (
+	EventTransactionIncluded  WalletEventType = "transaction.included"     // 交易首次被打包（尚未达到确认深度）
+	EventTransactionConfirmed WalletEventType = "transaction.confirmed"    // 交易达到确认深度，最终确认（成功或失败）
+	EventTransactionReorged   WalletEventType = "transaction.reorged"      // 交易所在区块被链重组移除，恢复为待确认
+	EventTransactionStuck     WalletEventType = "transaction.stuck"        // 交易长时间未打包且nonce尚未被使用，建议加速或取消
+	EventTransactionDropped   WalletEventType = "transaction.dropped"      // 交易未打包且nonce已被其他交易使用，标记为dropped
+	EventDepositDetected      WalletEventType = "deposit.detected"         // 检测到入账
+	EventRecurringPaused      WalletEventType = "recurring_payment.paused" // 定期转账连续失败已暂停
+	EventApprovalRequested    WalletEventType = "approval.requested"       // 交易等待审批（推送给审批人）
+	EventApprovalResolved     WalletEventType = "approval.resolved"        // 审批结束（已广播、被拒绝或已过期）
+	EventLimitsChanged        WalletEventType = "wallet.limits_changed"    // 钱包每日限额变更
+	EventNewLogin             WalletEventType = "auth.new_login"           // 从新IP或新设备登录（定向推送给用户）
+	EventPasswordChanged      WalletEventType = "auth.password_changed"    // 登录密码已修改（定向推送给用户）
+	EventKeyExported          WalletEventType = "wallet.key_exported"      // 钱包私钥备份已导出（定向推送给用户）
+)
+
+// WalletEvent 钱包实时事件（通过WebSocket推送给客户端）
+type WalletEvent struct {
+	Type                  WalletEventType   `json:"type"`
+	Address               string            `json:"address"`                          // 事件关联的钱包地址
+	UserID                uint              `json:"user_id,omitempty"`                // 接收事件的用户（设置后仅推送给该用户，不按地址分发）
+	TransactionID         uint              `json:"transaction_id,omitempty"`         // 交易ID（审批事件，待审批交易尚无哈希）
+	TxHash                string            `json:"tx_hash,omitempty"`                // 交易哈希（交易事件）
+	ExplorerURL           string            `json:"explorer_url,omitempty"`           // 交易（或无哈希时钱包地址）在区块浏览器中的链接
+	Status                TransactionStatus `json:"status,omitempty"`                 // 交易状态（交易事件）
+	BlockNumber           int64             `json:"block_number,omitempty"`           // 区块号（交易事件）
+	Confirmations         uint64            `json:"confirmations,omitempty"`          // 当前确认数（交易事件）
+	ConfirmationsRequired uint64            `json:"confirmations_required,omitempty"` // 最终确认所需的区块数（交易事件）
+	Balance               string            `json:"balance,omitempty"`                // 最新余额Wei（入账事件）
+	Amount                string            `json:"amount,omitempty"`                 // 入账金额Wei（入账事件），代币入账为代币最小单位
+	TokenAddress          string            `json:"token_address,omitempty"`          // 代币合约地址（代币入账事件）
+	RecurringPaymentID    uint              `json:"recurring_payment_id,omitempty"`   // 定期转账计划ID（定期转账事件）
+	Message               string            `json:"message,omitempty"`                // 事件说明（如暂停原因）
+	Timestamp             time.Time         `json:"timestamp"`
+}
+
+// WebSocketMessage WebSocket客户端消息
+type WebSocketMessage struct {
+	Action  string `json:"action"`            // auth, subscribe, unsubscribe
+	Token   string `json:"token,omitempty"`   // JWT Token（action=auth）
+	Address string `json:"address,omitempty"` // 钱包地址（action=subscribe/unsubscribe）
+}
+
+// WebSocketReply WebSocket服务端回复
+type WebSocketReply struct {
+	Type    string `json:"type"` // ack, error
+	Action  string `json:"action,omitempty"`
+	Address string `json:"address,omitempty"`
+	Message string `json:"message,omitempty"`
+}
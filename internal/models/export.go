@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// ExportJobStatus 导出任务状态
+type ExportJobStatus string
+
+const (
+	ExportJobPending    ExportJobStatus = "pending"    // 等待Worker生成
+	ExportJobProcessing ExportJobStatus = "processing" // 生成中
+	ExportJobCompleted  ExportJobStatus = "completed"  // 已生成，可下载
+	ExportJobFailed     ExportJobStatus = "failed"     // 生成失败（队列重试成功后变为completed）
+	ExportJobExpired    ExportJobStatus = "expired"    // 文件已过期删除
+)
+
+// ExportJob 异步导出任务：Worker将交易记录生成到文件，用户凭一次性下载令牌下载
+type ExportJob struct {
+	ID            string               `gorm:"primaryKey;size:36" json:"id"`             // 任务ID（UUID，出现在下载链接中，不可枚举）
+	UserID        uint                 `gorm:"not null;index" json:"-"`                  // 发起导出的用户（只有该用户能查询与下载）
+	Format        string               `gorm:"not null;size:10" json:"format"`           // 导出格式：csv或json
+	WalletID      uint                 `gorm:"not null;default:0" json:"-"`              // 按钱包筛选，0表示用户所有钱包
+	ChainID       int                  `gorm:"not null;default:0" json:"-"`              // 按链筛选，0表示不限
+	FromDate      *time.Time           `json:"-"`                                        // 开始时间（含）
+	ToDate        *time.Time           `json:"-"`                                        // 结束时间（不含）
+	Direction     TransactionDirection `gorm:"not null;size:10;default:''" json:"-"`     // 按方向筛选，空表示不限
+	MinAmount     string               `gorm:"not null;size:40;default:''" json:"-"`     // 金额下限，空表示不限
+	MaxAmount     string               `gorm:"not null;size:40;default:''" json:"-"`     // 金额上限，空表示不限
+	Counterparty  string               `gorm:"not null;size:42;default:''" json:"-"`     // 按对手方地址筛选，空表示不限
+	Status        ExportJobStatus      `gorm:"not null;size:20;index" json:"status"`     // 任务状态
+	EstimatedRows int64                `gorm:"not null;default:0" json:"estimated_rows"` // 创建时估算的交易数
+	RowCount      int64                `gorm:"not null;default:0" json:"row_count"`      // 实际导出的交易数
+	FileSize      int64                `gorm:"not null;default:0" json:"file_size"`      // 文件字节数
+	ErrorMsg      string               `gorm:"type:text" json:"error_msg,omitempty"`     // 失败原因
+	ExpiresAt     *time.Time           `gorm:"index" json:"expires_at,omitempty"`        // 文件过期时间（生成完成时设置）
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`                   // 生成完成时间
+	CreatedAt     time.Time            `gorm:"index" json:"created_at"`                  // 创建时间
+	UpdatedAt     time.Time            `json:"updated_at"`                               // 更新时间
+}
+
+// Filter 导出查询条件
+func (j *ExportJob) Filter() *TransactionExportFilter {
+	filter := &TransactionExportFilter{
+		UserID:   j.UserID,
+		WalletID: j.WalletID,
+		ChainID:  j.ChainID,
+		TransactionFilter: TransactionFilter{
+			Direction:    j.Direction,
+			MinAmount:    j.MinAmount,
+			MaxAmount:    j.MaxAmount,
+			Counterparty: j.Counterparty,
+		},
+	}
+	if j.FromDate != nil {
+		filter.From = *j.FromDate
+	}
+	if j.ToDate != nil {
+		filter.To = *j.ToDate
+	}
+	return filter
+}
+
+// FileName 下载文件名（按任务创建日期命名）
+func (j *ExportJob) FileName() string {
+	return "transactions-" + j.CreatedAt.UTC().Format("20060102") + "." + j.Format
+}
+
+// ExportJobRequest 创建导出任务请求
+type ExportJobRequest struct {
+	Format        string `json:"format" binding:"omitempty,oneof=csv json"`    // 导出格式，默认csv
+	From          string `json:"from" binding:"omitempty,datetime=2006-01-02"` // 开始日期（含）YYYY-MM-DD，默认不限
+	To            string `json:"to" binding:"omitempty,datetime=2006-01-02"`   // 结束日期（含）YYYY-MM-DD，默认不限
+	ChainID       int    `json:"chain_id" binding:"omitempty,chain_id"`        // 按链筛选
+	WalletAddress string `json:"wallet_address" binding:"omitempty,eth_addr"`  // 按钱包地址筛选，默认用户所有钱包
+	TransactionFilter
+}
+
+// ExportRequest 转换为交易导出请求（日期格式已由binding校验）
+func (r *ExportJobRequest) ExportRequest() *TransactionExportRequest {
+	req := &TransactionExportRequest{
+		Format:            r.Format,
+		ChainID:           r.ChainID,
+		WalletAddress:     r.WalletAddress,
+		TransactionFilter: r.TransactionFilter,
+	}
+	if req.Format == "" {
+		req.Format = "csv"
+	}
+	req.From, _ = time.Parse("2006-01-02", r.From)
+	req.To, _ = time.Parse("2006-01-02", r.To)
+	return req
+}
+
+// ExportJobResponse 导出任务响应（任务完成且文件未过期时附带一次性下载链接）
+type ExportJobResponse struct {
+	*ExportJob
+	DownloadURL    string     `json:"download_url,omitempty"`     // 下载链接（无需Authorization头，只能使用一次）
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"` // 下载链接过期时间，过期后重新查询任务获取新链接
+}
@@ -0,0 +1,17 @@
+package models
+
+// 新钱包的测试链领水状态（未配置水龙头的链、导入的钱包与超出每日领取次数的钱包为空）
+const (
+	WalletFundingPending = "funding_pending" // 领水交易待发送
+	WalletFunded         = "funded"          // 领水交易已广播
+	WalletFundingFailed  = "funding_failed"  // 领水失败（水龙头钱包不可用等不可重试的错误）
+)
+
+// Faucet 测试链水龙头（新建钱包时从水龙头钱包转入少量原生币，只能在测试链白名单中的链上启用）
+type Faucet struct {
+	ChainID    int
+	Address    string // 水龙头钱包地址（系统中已有的托管钱包，以其所有者身份发送交易）
+	Passphrase string // 水龙头钱包口令（钱包未设置口令时为空）
+	AmountWei  string // 每个新钱包领取的金额（Wei）
+	DailyLimit int    // 每个用户每天可领取的次数
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// 功能开关名称
+const (
+	FlagMaintenanceReadOnly = "maintenance.read_only"     // 全局只读维护模式（开启时拒绝所有写操作）
+	FlagTransactionsSend    = "transactions.send.enabled" // 发送交易（含合约调用、审批执行与定期转账）
+	FlagWalletsCreate       = "wallets.create.enabled"    // 创建与导入钱包
+)
+
+// FeatureFlagDefaults 功能开关的默认值（Redis中没有记录或Redis不可用时使用）
+var FeatureFlagDefaults = map[string]bool{
+	FlagMaintenanceReadOnly: false,
+	FlagTransactionsSend:    true,
+	FlagWalletsCreate:       true,
+}
+
+// FeatureFlag 功能开关当前状态（以JSON保存在Redis中，所有实例共享）
+type FeatureFlag struct {
+	Name      string     `json:"name"`
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`    // 关闭功能时返回给客户端的说明
+	Default   bool       `json:"default"`              // 是否为默认值（从未修改或Redis不可用）
+	UpdatedBy uint       `json:"updated_by,omitempty"` // 最后修改的管理员
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// FeatureFlagChange 功能开关变更审计记录
+type FeatureFlagChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Flag      string    `gorm:"not null;size:100;index" json:"flag"`
+	Previous  bool      `gorm:"not null" json:"previous"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	Message   string    `gorm:"size:255" json:"message,omitempty"`
+	ChangedBy uint      `gorm:"not null" json:"changed_by"` // 执行修改的管理员
+	IP        string    `gorm:"not null;size:45" json:"ip"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (FeatureFlagChange) TableName() string {
+	return "feature_flag_changes"
+}
+
+// FeatureFlagUpdateRequest 修改功能开关请求
+type FeatureFlagUpdateRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Message string `json:"message" binding:"max=255"`
+}
+
+// FeatureDisabledData 功能暂时关闭响应的附加信息
+type FeatureDisabledData struct {
+	Feature string `json:"feature"`
+	Message string `json:"message,omitempty"`
+}
+
+// FeatureFlagChangeListRequest 查询开关变更记录请求
+type FeatureFlagChangeListRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=500"` // 默认100
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// GasSpeed 交易速度档位
+type GasSpeed string
+
+const (
+	GasSpeedSlow     GasSpeed = "slow"     // 较低费用，打包可能较慢
+	GasSpeedStandard GasSpeed = "standard" // 普通
+	GasSpeedFast     GasSpeed = "fast"     // 较高费用，尽快打包
+)
+
+// GasPriceSource 档位价格的计算方式
+type GasPriceSource string
+
+const (
+	GasSourceFeeHistory GasPriceSource = "fee_history" // 由eth_feeHistory的基础费用与小费分位数计算
+	GasSourceGasPrice   GasPriceSource = "gas_price"   // 节点不支持eth_feeHistory，由eth_gasPrice平滑后按比例计算
+)
+
+// GasTiers 链的三档Gas价格（Wei，由Worker定时刷新并缓存在Redis中）
+type GasTiers struct {
+	ChainID   int            `json:"chain_id"`
+	Slow      string         `json:"slow"`
+	Standard  string         `json:"standard"`
+	Fast      string         `json:"fast"`
+	BaseFee   string         `json:"base_fee,omitempty"` // 下一个区块的基础费用（fee_history时）
+	Source    GasPriceSource `json:"source"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Price 返回档位对应的价格
+func (t *GasTiers) Price(speed GasSpeed) string {
+	switch speed {
+	case GasSpeedSlow:
+		return t.Slow
+	case GasSpeedFast:
+		return t.Fast
+	default:
+		return t.Standard
+	}
+}
+
+// GasPriceRequest 查询Gas价格请求
+type GasPriceRequest struct {
+	ChainID  int   `form:"chain_id" binding:"required,chain_id"`
+	GasLimit int64 `form:"gas_limit" binding:"omitempty,gt=0"` // 预估费用使用的gas用量，默认为链的原生币转账gas用量
+}
+
+// GasTierEstimate 单个档位的价格与预估费用
+type GasTierEstimate struct {
+	GasPrice     string `json:"gas_price"`     // Wei
+	EstimatedFee string `json:"estimated_fee"` // gas_price × gas_limit（ETH）
+}
+
+// GasPriceResponse 三档Gas价格与预估费用
+type GasPriceResponse struct {
+	ChainID   int                          `json:"chain_id"`
+	GasLimit  int64                        `json:"gas_limit"`
+	Tiers     map[GasSpeed]GasTierEstimate `json:"tiers"`
+	BaseFee   string                       `json:"base_fee,omitempty"`
+	Source    GasPriceSource               `json:"source"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// 地址标签分类
+const (
+	LabelCategoryExchange = "exchange"       // 交易所充值/热钱包地址
+	LabelCategoryDeFi     = "defi"           // DeFi协议合约（路由、借贷池等）
+	LabelCategoryToken    = "token contract" // 代币合约
+	LabelCategoryContact  = "contact"        // 用户自己的地址簿联系人（未匹配到公共标签时回退）
+)
+
+// LabelCategories 数据集与导入允许的分类（contact仅用于回退，不写入标签表）
+var LabelCategories = []string{LabelCategoryExchange, LabelCategoryDeFi, LabelCategoryToken}
+
+// 地址标签来源
+const (
+	LabelSourceDataset = "dataset" // 内置数据集（启动时同步）
+	LabelSourceImport  = "import"  // 管理员导入（优先于内置数据集，启动同步时不覆盖）
+)
+
+// AddressLabel 公共地址标签（所有用户共享）
+type AddressLabel struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Address   string    `gorm:"not null;size:42;uniqueIndex:idx_address_labels_address_chain" json:"address"` // EIP-55校验和格式
+	ChainID   int       `gorm:"not null;uniqueIndex:idx_address_labels_address_chain" json:"chain_id"`
+	Name      string    `gorm:"not null;size:100" json:"name"`
+	Category  string    `gorm:"not null;size:20" json:"category"`
+	Source    string    `gorm:"not null;size:20" json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AddressLabel) TableName() string {
+	return "address_labels"
+}
+
+// AddressLabelEntry 数据集或导入请求中的一条标签
+type AddressLabelEntry struct {
+	Address  string `json:"address" binding:"required,eth_addr"`
+	ChainID  int    `json:"chain_id" binding:"required,min=1"`
+	Name     string `json:"name" binding:"required,max=100"`
+	Category string `json:"category" binding:"required,oneof=exchange defi 'token contract'"`
+}
+
+// AddressLabelLookupRequest 查询地址标签请求
+type AddressLabelLookupRequest struct {
+	ChainID int `form:"chain_id" binding:"omitempty,chain_id"` // 只返回指定链上的标签（不传时返回所有链）
+}
+
+// AddressLabelImportRequest 导入地址标签请求（已存在的地址覆盖名称与分类）
+type AddressLabelImportRequest struct {
+	Labels []AddressLabelEntry `json:"labels" binding:"required,min=1,max=1000,dive"`
+}
+
+// AddressLabelImportResponse 导入地址标签响应
+type AddressLabelImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// CounterpartyLabel 交易对手方的标签（公共标签优先，未匹配时回退到用户的地址簿联系人）
+type CounterpartyLabel struct {
+	Label    string `json:"label"`
+	Category string `json:"category"` // exchange、defi、token contract或contact
+}
+
+// AddressLabelResponse 地址标签查询结果（每条链一条）
+type AddressLabelResponse struct {
+	Address   string `json:"address"`
+	ChainID   int    `json:"chain_id"`
+	ChainName string `json:"chain_name"`
+	CounterpartyLabel
+	Source string `json:"source"` // dataset、import或contact
+}
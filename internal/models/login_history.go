@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"crypto-wallet-api/pkg/geoip"
+)
+
+// LoginHistory 登录记录（每次登录成功写入一条，对应一个已签发的Token）
+type LoginHistory struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index:idx_login_history_user_created,priority:1" json:"-"`
+	JTI          string    `gorm:"column:jti;not null;size:36;index" json:"-"` // 签发Token的唯一标识
+	TokenVersion int       `gorm:"not null" json:"-"`                          // 签发时的Token版本（低于当前版本表示已下线）
+	IP           string    `gorm:"not null;size:45" json:"ip"`
+	DeviceHash   string    `gorm:"not null;size:64" json:"-"` // User-Agent的SHA-256摘要（不保存原文）
+	NewDevice    bool      `gorm:"not null" json:"new_device"`
+	ExpiresAt    time.Time `gorm:"not null" json:"-"` // Token过期时间
+	CreatedAt    time.Time `gorm:"index:idx_login_history_user_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginHistory) TableName() string {
+	return "login_history"
+}
+
+// LoginResponse 登录响应
+type LoginResponse struct {
+	Token     string        `json:"token"`
+	User      *UserResponse `json:"user"`
+	NewDevice bool          `json:"new_device"` // 首次从该IP或设备登录，客户端可提示用户
+}
+
+// SessionResponse 登录会话
+type SessionResponse struct {
+	ID        uint            `json:"id"`
+	IP        string          `json:"ip"`
+	Device    string          `json:"device"`             // 设备指纹（User-Agent摘要前缀）
+	Location  *geoip.Location `json:"location,omitempty"` // IP地理位置（未配置解析器时为空）
+	NewDevice bool            `json:"new_device"`
+	Current   bool            `json:"current"` // 当前请求使用的会话
+	Active    bool            `json:"active"`  // Token仍然有效（未过期、未退出、未被全部下线）
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// RevokeSessionsResponse 下线其他会话的响应（原Token随之失效，客户端需改用新Token）
+type RevokeSessionsResponse struct {
+	Token string `json:"token"`
+}
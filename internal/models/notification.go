@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationType 通知类型（用户按类型设置偏好）
+type NotificationType string
+
+const (
+	NotificationTxIncluded      NotificationType = "transaction_included"  // 交易已打包，等待达到确认深度
+	NotificationTxConfirmed     NotificationType = "transaction_confirmed" // 交易已最终确认（成功或失败）
+	NotificationDepositReceived NotificationType = "deposit_received"      // 收到入账
+	NotificationNewLogin        NotificationType = "login_new_ip"          // 新IP或新设备登录
+	NotificationLimitsChanged   NotificationType = "limits_changed"        // 钱包限额变更
+	NotificationPasswordChanged NotificationType = "password_changed"      // 登录密码已修改
+	NotificationKeyExported     NotificationType = "key_exported"          // 钱包私钥备份已导出
+	NotificationTxStuck         NotificationType = "transaction_stuck"     // 交易长时间未打包（建议加速或取消）
+)
+
+// NotificationTypes 所有通知类型（偏好查询按此顺序返回）
+var NotificationTypes = []NotificationType{
+	NotificationTxIncluded,
+	NotificationTxConfirmed,
+	NotificationDepositReceived,
+	NotificationNewLogin,
+	NotificationLimitsChanged,
+	NotificationPasswordChanged,
+	NotificationKeyExported,
+	NotificationTxStuck,
+}
+
+// NotificationChannel 站外通知渠道（站内通知中心始终记录）
+type NotificationChannel string
+
+const (
+	NotificationChannelNone    NotificationChannel = "none"    // 仅站内通知
+	NotificationChannelEmail   NotificationChannel = "email"   // 邮件
+	NotificationChannelWebhook NotificationChannel = "webhook" // 回调用户配置的HTTPS地址
+)
+
+// NotificationPreference 用户的通知偏好（未设置的类型按默认值：启用、仅站内）
+type NotificationPreference struct {
+	ID         uint                `gorm:"primaryKey" json:"-"`
+	UserID     uint                `gorm:"not null;uniqueIndex:idx_notification_prefs_user_type,priority:1" json:"-"`
+	Type       NotificationType    `gorm:"not null;size:50;uniqueIndex:idx_notification_prefs_user_type,priority:2" json:"type"`
+	Enabled    bool                `gorm:"not null" json:"enabled"`               // 关闭后该类型既不记录站内通知也不外发
+	Channel    NotificationChannel `gorm:"not null;size:20" json:"channel"`       // 站外通知渠道
+	WebhookURL string              `gorm:"size:500" json:"webhook_url,omitempty"` // 渠道为webhook时的回调地址
+	CreatedAt  time.Time           `json:"-"`
+	UpdatedAt  time.Time           `json:"updated_at,omitempty"`
+}
+
+// TableName 指定表名
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference 未设置偏好时的默认值
+func DefaultNotificationPreference(userID uint, notificationType NotificationType) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:  userID,
+		Type:    notificationType,
+		Enabled: true,
+		Channel: NotificationChannelNone,
+	}
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null;index:idx_notifications_user_created,priority:1" json:"-"`
+	Type      NotificationType `gorm:"not null;size:50" json:"type"`
+	Title     string           `gorm:"not null;size:200" json:"title"`
+	Message   string           `gorm:"type:text" json:"message"`
+	Data      string           `gorm:"type:text" json:"-"` // 触发通知的事件JSON
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `gorm:"index:idx_notifications_user_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationResponse 站内通知响应
+type NotificationResponse struct {
+	ID        uint             `json:"id"`
+	Type      NotificationType `json:"type"`
+	Title     string           `json:"title"`
+	Message   string           `json:"message"`
+	Data      json.RawMessage  `json:"data,omitempty"`
+	Read      bool             `json:"read"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (n *Notification) ToResponse() *NotificationResponse {
+	resp := &NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Message:   n.Message,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.Data != "" {
+		resp.Data = json.RawMessage(n.Data)
+	}
+	return resp
+}
+
+// NotificationListRequest 站内通知列表查询请求
+type NotificationListRequest struct {
+	Unread   bool `form:"unread"`                                      // 仅返回未读通知
+	Page     int  `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize int  `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页数量，默认20
+}
+
+// NotificationListResponse 站内通知列表响应
+type NotificationListResponse struct {
+	Total         int64                   `json:"total"`
+	Unread        int64                   `json:"unread"` // 未读总数（不受unread筛选影响）
+	Page          int                     `json:"page"`
+	PageSize      int                     `json:"page_size"`
+	Notifications []*NotificationResponse `json:"notifications"`
+}
+
+// NotificationPreferenceItem 单个通知类型的偏好设置
+type NotificationPreferenceItem struct {
+	Type       NotificationType    `json:"type" binding:"required,oneof=transaction_included transaction_confirmed deposit_received login_new_ip limits_changed password_changed key_exported transaction_stuck"`
+	Enabled    *bool               `json:"enabled" binding:"required"`
+	Channel    NotificationChannel `json:"channel" binding:"required,oneof=none email webhook"`
+	WebhookURL string              `json:"webhook_url" binding:"omitempty,url,startswith=https://,max=500"` // 渠道为webhook时必填，仅支持HTTPS
+}
+
+// NotificationPreferencesRequest 更新通知偏好请求（只更新列出的类型）
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceItem `json:"preferences" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+)
+
+// OrgRole 组织成员角色
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"  // 所有者：管理成员
+	OrgRoleAdmin  OrgRole = "admin"  // 管理员：管理钱包设置
+	OrgRoleMember OrgRole = "member" // 成员：在限额内转账
+	OrgRoleViewer OrgRole = "viewer" // 只读：查看余额与交易记录
+)
+
+// Rank 角色等级（数值越大权限越高）
+func (r OrgRole) Rank() int {
+	switch r {
+	case OrgRoleOwner:
+		return 4
+	case OrgRoleAdmin:
+		return 3
+	case OrgRoleMember:
+		return 2
+	case OrgRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Organization 组织（团队共享钱包）
+type Organization struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	Name               string    `gorm:"not null;size:100" json:"name"`                  // 组织名称
+	MemberSendLimitWei string    `gorm:"size:78" json:"member_send_limit_wei,omitempty"` // member角色单笔转账上限（Wei），空表示不限
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// OrgMembership 组织成员关系
+type OrgMembership struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	OrgID        uint         `gorm:"not null;uniqueIndex:idx_org_memberships_org_user,priority:1" json:"org_id"`        // 组织ID
+	UserID       uint         `gorm:"not null;uniqueIndex:idx_org_memberships_org_user,priority:2;index" json:"user_id"` // 用户ID
+	Role         OrgRole      `gorm:"not null;size:20" json:"role"`                                                      // 成员角色
+	Organization Organization `gorm:"foreignKey:OrgID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// TableName 指定表名
+func (OrgMembership) TableName() string {
+	return "org_memberships"
+}
+
+// OrgCreateRequest 创建组织请求
+type OrgCreateRequest struct {
+	Name               string `json:"name" binding:"required,max=100"`
+	MemberSendLimitWei string `json:"member_send_limit_wei" binding:"omitempty,numeric"` // 为空或0表示不限
+}
+
+// OrgUpdateRequest 更新组织请求（字段为空表示不修改）
+type OrgUpdateRequest struct {
+	Name               *string `json:"name" binding:"omitempty,min=1,max=100"`
+	MemberSendLimitWei *string `json:"member_send_limit_wei" binding:"omitempty,numeric"` // 空字符串或0表示不限
+}
+
+// OrgMemberRequest 添加组织成员请求
+type OrgMemberRequest struct {
+	UserID uint    `json:"user_id" binding:"required"`
+	Role   OrgRole `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
+
+// OrgMemberUpdateRequest 修改成员角色请求
+type OrgMemberUpdateRequest struct {
+	Role OrgRole `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
+
+// OrgResponse 组织响应
+type OrgResponse struct {
+	ID                 uint      `json:"id"`
+	Name               string    `json:"name"`
+	MemberSendLimitWei string    `json:"member_send_limit_wei,omitempty"`
+	Role               OrgRole   `json:"role"` // 当前用户在组织中的角色
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (m *OrgMembership) ToResponse() *OrgResponse {
+	return &OrgResponse{
+		ID:                 m.Organization.ID,
+		Name:               m.Organization.Name,
+		MemberSendLimitWei: m.Organization.MemberSendLimitWei,
+		Role:               m.Role,
+		CreatedAt:          m.Organization.CreatedAt,
+	}
+}
+
+// OrgMemberResponse 组织成员响应
+type OrgMemberResponse struct {
+	UserID    uint      `json:"user_id"`
+	Role      OrgRole   `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToMemberResponse 转换为成员响应格式
+func (m *OrgMembership) ToMemberResponse() *OrgMemberResponse {
+	return &OrgMemberResponse{
+		UserID:    m.UserID,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent 事务性发件箱事件（与业务数据同事务写入，由后台分发器投递到消息队列）
+type OutboxEvent struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Queue     string     `gorm:"not null;size:100" json:"queue"`        // 目标队列
+	Payload   string     `gorm:"not null;type:text" json:"payload"`     // 消息体（JSON，配置队列密钥时为加密信封）
+	Headers   string     `gorm:"type:text" json:"headers,omitempty"`    // 链路上下文（JSON），用于延续Trace
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`    // 投递失败次数
+	LastError string     `gorm:"type:text" json:"last_error,omitempty"` // 最近一次投递错误
+	SentAt    *time.Time `gorm:"index" json:"sent_at,omitempty"`        // 投递成功时间，为空表示待投递
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// TransactionCreatedMessage 交易创建消息（仅携带定位交易所需的字段，Worker按哈希从数据库读取完整记录）
+type TransactionCreatedMessage struct {
+	TxHash   string `json:"tx_hash"`
+	ChainID  int    `json:"chain_id"`
+	WalletID uint   `json:"wallet_id"`
+}
+
+// WalletFundingMessage 测试链领水消息（Worker按地址读取钱包，仅处理仍为funding_pending的钱包）
+type WalletFundingMessage struct {
+	Address string `json:"address"`
+	ChainID int    `json:"chain_id"`
+}
+
+// EmailSendMessage 外发邮件消息（Worker以Data渲染Template模板后发送给To；消息配置队列密钥时加密）
+type EmailSendMessage struct {
+	To       string            `json:"to"`
+	Template string            `json:"template"`
+	Data     map[string]string `json:"data"`
+}
+
+// ExportJobMessage 导出任务消息（Worker按ID读取任务，仅处理尚未完成的任务）
+type ExportJobMessage struct {
+	JobID string `json:"job_id"`
+}
@@ -0,0 +1,23 @@
+package models
+
+const (
+	// DefaultPage 列表接口未指定页码时的默认页码
+	DefaultPage = 1
+	// DefaultPageSize 列表接口未指定每页数量时的默认值
+	DefaultPageSize = 20
+	// MaxPageSize 列表接口每页数量上限（超过时拒绝请求，不截断）
+	MaxPageSize = 100
+)
+
+// TotalPages 总页数（没有记录时为0）
+func TotalPages(total int64, pageSize int) int64 {
+	if total <= 0 || pageSize <= 0 {
+		return 0
+	}
+	return (total + int64(pageSize) - 1) / int64(pageSize)
+}
+
+// HasNextPage 当前页之后是否还有记录
+func HasNextPage(total int64, page, pageSize int) bool {
+	return int64(page)*int64(pageSize) < total
+}
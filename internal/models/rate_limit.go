@@ -0,0 +1,13 @@
+package models
+
+// 限流令牌桶名称
+const (
+	RateLimitBucketGlobal    = "global"    // 全局限流（所有请求共用）
+	RateLimitBucketExpensive = "expensive" // 调用链节点等外部系统的路由（按用户）
+	RateLimitBucketExport    = "export"    // 导出路由（按用户）
+)
+
+// RateLimitExceededData 超出限流响应的附加信息
+type RateLimitExceededData struct {
+	Bucket string `json:"bucket"` // 超出的令牌桶
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// ReconciliationRun 一次余额对账（比较数据库中的钱包余额与链上余额）
+type ReconciliationRun struct {
+	ID             uint                   `gorm:"primaryKey" json:"id"`
+	ChainID        int                    `gorm:"not null;index:idx_reconciliation_runs_chain_started,priority:1" json:"chain_id"`
+	ThresholdWei   string                 `gorm:"type:decimal(78,0);not null;default:0" json:"threshold_wei"` // 记录差异的阈值
+	WalletsChecked int                    `gorm:"not null;default:0" json:"wallets_checked"`                  // 已比较的钱包数
+	Corrected      int                    `gorm:"not null;default:0" json:"corrected"`                        // 已修正的钱包数（含低于阈值的差异）
+	Discrepancies  int                    `gorm:"not null;default:0" json:"discrepancies"`                    // 超过阈值的差异数
+	Failed         int                    `gorm:"not null;default:0" json:"failed"`                           // 链上余额查询失败的钱包数
+	StartedAt      time.Time              `gorm:"not null;index:idx_reconciliation_runs_chain_started,priority:2,sort:desc" json:"started_at"`
+	FinishedAt     *time.Time             `json:"finished_at,omitempty"` // 为空表示仍在执行或Worker中途退出
+	Reports        []ReconciliationReport `gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE" json:"reports"`
+}
+
+// TableName 指定表名
+func (ReconciliationRun) TableName() string {
+	return "reconciliation_runs"
+}
+
+// ReconciliationReport 超过阈值的余额差异（金额均为Wei）
+type ReconciliationReport struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	RunID          uint      `gorm:"not null;index" json:"run_id"`
+	WalletID       uint      `gorm:"not null;index" json:"wallet_id"`
+	Address        string    `gorm:"not null;size:42" json:"address"`
+	StoredBalance  string    `gorm:"type:decimal(78,0);not null" json:"stored_balance"`                         // 修正前数据库中的账本余额
+	OnChainBalance string    `gorm:"column:onchain_balance;type:decimal(78,0);not null" json:"onchain_balance"` // 链上余额
+	InternalNetWei string    `gorm:"type:decimal(78,0);not null" json:"internal_net_wei"`                       // 尚未在链上结算的内部转账净额
+	DeltaWei       string    `gorm:"type:decimal(78,0);not null" json:"delta_wei"`                              // 修正后 − 修正前（链上余额 + 内部转账净额 − 数据库余额）
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ReconciliationReport) TableName() string {
+	return "reconciliation_reports"
+}
+
+// ReconciliationListRequest 对账记录查询请求
+type ReconciliationListRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"` // 返回最近的对账次数，默认10
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+)
+
+// RecurringPaymentStatus 定期转账状态
+type RecurringPaymentStatus string
+
+const (
+	RecurringStatusActive    RecurringPaymentStatus = "active"    // 执行中
+	RecurringStatusPaused    RecurringPaymentStatus = "paused"    // 已暂停（手动或连续失败）
+	RecurringStatusCompleted RecurringPaymentStatus = "completed" // 已达到结束条件
+	RecurringStatusCancelled RecurringPaymentStatus = "cancelled" // 已取消
+)
+
+// RecurringPayment 定期转账计划
+type RecurringPayment struct {
+	ID                  uint                   `gorm:"primaryKey" json:"id"`
+	UserID              uint                   `gorm:"not null;index" json:"user_id"`                  // 所属用户ID
+	WalletID            uint                   `gorm:"not null;index" json:"wallet_id"`                // 付款钱包ID
+	FromAddress         string                 `gorm:"not null;size:42" json:"from_address"`           // 付款地址
+	ToAddress           string                 `gorm:"not null;size:42" json:"to_address"`             // 收款地址
+	Amount              string                 `gorm:"not null;size:78" json:"amount"`                 // 每次转账金额（Wei）
+	ChainID             int                    `gorm:"not null" json:"chain_id"`                       // 链ID
+	Schedule            string                 `gorm:"not null;size:32" json:"schedule"`               // daily、weekly、monthly或时间间隔（如36h）
+	NextRunAt           time.Time              `gorm:"not null;index" json:"next_run_at"`              // 下次执行时间
+	EndAt               *time.Time             `json:"end_at,omitempty"`                               // 结束时间（不含），为空表示不限
+	MaxRuns             int                    `gorm:"not null;default:0" json:"max_runs"`             // 最多执行次数，0表示不限
+	RunCount            int                    `gorm:"not null;default:0" json:"run_count"`            // 已执行次数（含失败）
+	ConsecutiveFailures int                    `gorm:"not null;default:0" json:"consecutive_failures"` // 连续失败次数
+	LastError           string                 `gorm:"type:text" json:"last_error,omitempty"`          // 最近一次失败原因
+	LastRunAt           *time.Time             `json:"last_run_at,omitempty"`                          // 最近一次执行时间
+	Status              RecurringPaymentStatus `gorm:"not null;index;size:20" json:"status"`           // 计划状态
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (RecurringPayment) TableName() string {
+	return "recurring_payments"
+}
+
+// RecurringPaymentCreateRequest 创建定期转账请求
+type RecurringPaymentCreateRequest struct {
+	FromAddress string     `json:"from_address" binding:"required,eth_addr"`
+	ToAddress   string     `json:"to_address" binding:"required,eth_addr"`
+	Amount      string     `json:"amount" binding:"required"`                               // 每次转账金额（单位见amount_unit，大于0，按Wei保存）
+	AmountUnit  AmountUnit `json:"amount_unit,omitempty" binding:"omitempty,oneof=wei eth"` // 金额单位：wei（默认）或eth（原生币主单位，最多18位小数）
+	ChainID     int        `json:"chain_id" binding:"required,chain_id"`
+	Schedule    string     `json:"schedule" binding:"required"`       // daily、weekly、monthly或时间间隔（不少于1h）
+	StartAt     *time.Time `json:"start_at"`                          // 首次执行时间，默认立即
+	EndAt       *time.Time `json:"end_at"`                            // 结束时间，可选
+	MaxRuns     int        `json:"max_runs" binding:"omitempty,gt=0"` // 最多执行次数，可选
+}
+
+// RecurringPaymentUpdateRequest 更新定期转账请求（字段均可选）
+type RecurringPaymentUpdateRequest struct {
+	Amount     string                 `json:"amount"`                                                  // 每次转账金额（单位见amount_unit，按Wei保存）
+	AmountUnit AmountUnit             `json:"amount_unit,omitempty" binding:"omitempty,oneof=wei eth"` // 金额单位：wei（默认）或eth
+	Schedule   string                 `json:"schedule"`
+	EndAt      *time.Time             `json:"end_at"`
+	MaxRuns    *int                   `json:"max_runs" binding:"omitempty,min=0"`
+	Status     RecurringPaymentStatus `json:"status" binding:"omitempty,oneof=active paused"` // 暂停或恢复
+}
+
+// RecurringPaymentResponse 定期转账响应
+type RecurringPaymentResponse struct {
+	ID                  uint                   `json:"id"`
+	FromAddress         string                 `json:"from_address"`
+	ToAddress           string                 `json:"to_address"`
+	Amount              string                 `json:"amount"`
+	ChainID             int                    `json:"chain_id"`
+	Schedule            string                 `json:"schedule"`
+	NextRunAt           time.Time              `json:"next_run_at"`
+	EndAt               *time.Time             `json:"end_at,omitempty"`
+	MaxRuns             int                    `json:"max_runs"`
+	RunCount            int                    `json:"run_count"`
+	ConsecutiveFailures int                    `json:"consecutive_failures"`
+	LastError           string                 `json:"last_error,omitempty"`
+	LastRunAt           *time.Time             `json:"last_run_at,omitempty"`
+	Status              RecurringPaymentStatus `json:"status"`
+	CreatedAt           time.Time              `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (p *RecurringPayment) ToResponse() *RecurringPaymentResponse {
+	return &RecurringPaymentResponse{
+		ID:                  p.ID,
+		FromAddress:         p.FromAddress,
+		ToAddress:           p.ToAddress,
+		Amount:              p.Amount,
+		ChainID:             p.ChainID,
+		Schedule:            p.Schedule,
+		NextRunAt:           p.NextRunAt,
+		EndAt:               p.EndAt,
+		MaxRuns:             p.MaxRuns,
+		RunCount:            p.RunCount,
+		ConsecutiveFailures: p.ConsecutiveFailures,
+		LastError:           p.LastError,
+		LastRunAt:           p.LastRunAt,
+		Status:              p.Status,
+		CreatedAt:           p.CreatedAt,
+	}
+}
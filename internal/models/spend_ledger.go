@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// SpendLedgerEntry 钱包转出额度台账（用于滚动24小时限额统计）
+type SpendLedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	WalletID  uint      `gorm:"not null;index:idx_spend_ledger_wallet_created" json:"wallet_id"` // 所属钱包ID
+	Amount    string    `gorm:"type:numeric(78,0);not null" json:"amount"`                       // 转出金额（Wei）
+	TxHash    string    `gorm:"size:66" json:"tx_hash,omitempty"`                                // 关联交易哈希（发送成功后回填）
+	CreatedAt time.Time `gorm:"index:idx_spend_ledger_wallet_created" json:"created_at"`
+}
+
+// TableName 指定表名
+func (SpendLedgerEntry) TableName() string {
+	return "spend_ledger"
+}
+
+// SpendUsage 滚动窗口内的额度使用情况
+type SpendUsage struct {
+	SpentWei string     // 已转出金额（Wei）
+	TxCount  int64      // 已发起交易笔数
+	OldestAt *time.Time // 窗口内最早一笔的时间（用于计算重置时间）
+}
+
+// DailyLimitExceededData 超出每日限额时的响应数据
+type DailyLimitExceededData struct {
+	RemainingWei   string    `json:"remaining_wei,omitempty"`   // 剩余可转出金额（Wei），未设置金额上限时为空
+	RemainingCount *int      `json:"remaining_count,omitempty"` // 剩余可发起笔数，未设置笔数上限时为空
+	ResetAt        time.Time `json:"reset_at"`                  // 最早一笔额度释放的时间
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// TransactionStatsRequest 交易统计查询请求
+type TransactionStatsRequest struct {
+	From          time.Time `form:"from" time_format:"2006-01-02"`               // 开始日期（含），默认30天前
+	To            time.Time `form:"to" time_format:"2006-01-02"`                 // 结束日期（含），默认今天
+	ChainID       int       `form:"chain_id" binding:"omitempty,chain_id"`       // 按链筛选
+	WalletAddress string    `form:"wallet_address" binding:"omitempty,eth_addr"` // 按钱包地址筛选
+	TransactionFilter
+}
+
+// TransactionStatsFilter 交易统计查询条件（仓库层使用）
+type TransactionStatsFilter struct {
+	UserID   uint
+	WalletID uint // 0表示用户所有钱包
+	ChainID  int
+	From     time.Time
+	To       time.Time // 不含
+	TransactionFilter
+}
+
+// TransactionStatusStat 按状态聚合的统计
+type TransactionStatusStat struct {
+	Status      TransactionStatus `json:"status"`
+	Count       int64             `json:"count"`
+	TotalAmount string            `json:"total_amount"`  // 转账金额合计（ETH）
+	TotalGasFee string            `json:"total_gas_fee"` // Gas费用合计（Wei）
+}
+
+// TransactionDailyStat 按天与状态聚合的统计
+type TransactionDailyStat struct {
+	Day         time.Time         `json:"day"`
+	Status      TransactionStatus `json:"status"`
+	Count       int64             `json:"count"`
+	TotalAmount string            `json:"total_amount"`  // 转账金额合计（ETH）
+	TotalGasFee string            `json:"total_gas_fee"` // Gas费用合计（Wei）
+}
+
+// TransactionStatsResponse 交易统计响应
+type TransactionStatsResponse struct {
+	From          time.Time                   `json:"from"`
+	To            time.Time                   `json:"to"`
+	TotalSent     string                      `json:"total_sent"`      // 成功转出金额（ETH）
+	TotalReceived string                      `json:"total_received"`  // 成功转入金额（ETH，仅限本系统钱包之间）
+	TotalGasSpent string                      `json:"total_gas_spent"` // Gas总花费（Wei）
+	CountByStatus map[TransactionStatus]int64 `json:"count_by_status"`
+	Daily         []*TransactionDailyStat     `json:"daily"`
+}
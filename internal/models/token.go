@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// Token ERC-20代币元数据（预置常用代币，其余在首次使用时从合约读取）
+type Token struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ChainID         int       `gorm:"not null;uniqueIndex:idx_tokens_chain_contract" json:"chain_id"`                 // 链ID
+	ContractAddress string    `gorm:"not null;size:42;uniqueIndex:idx_tokens_chain_contract" json:"contract_address"` // 合约地址（校验和格式）
+	Symbol          string    `gorm:"not null;size:32" json:"symbol"`                                                 // 代币符号
+	Name            string    `gorm:"size:100" json:"name"`                                                           // 代币名称
+	Decimals        int       `gorm:"not null" json:"decimals"`                                                       // 小数位数
+	LogoURL         string    `gorm:"size:255" json:"logo_url,omitempty"`                                             // 图标地址
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Token) TableName() string {
+	return "tokens"
+}
+
+// TokenWatch 用户关注的代币
+type TokenWatch struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_token_watches_user_token" json:"user_id"`  // 所属用户ID
+	TokenID   uint      `gorm:"not null;uniqueIndex:idx_token_watches_user_token" json:"token_id"` // 代币ID
+	Token     Token     `gorm:"foreignKey:TokenID" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TokenWatch) TableName() string {
+	return "token_watches"
+}
+
+// TokenWatchRequest 关注/取消关注代币请求
+type TokenWatchRequest struct {
+	ContractAddress string `json:"contract_address" binding:"required,eth_addr"`
+	ChainID         int    `json:"chain_id" binding:"required,chain_id"`
+}
+
+// TokenResponse 代币响应
+type TokenResponse struct {
+	ContractAddress string `json:"contract_address"`
+	ChainID         int    `json:"chain_id"`
+	Symbol          string `json:"symbol"`
+	Name            string `json:"name"`
+	Decimals        int    `json:"decimals"`
+	LogoURL         string `json:"logo_url,omitempty"`
+}
+
+// ToResponse 转换为响应格式
+func (t *Token) ToResponse() *TokenResponse {
+	return &TokenResponse{
+		ContractAddress: t.ContractAddress,
+		ChainID:         t.ChainID,
+		Symbol:          t.Symbol,
+		Name:            t.Name,
+		Decimals:        t.Decimals,
+		LogoURL:         t.LogoURL,
+	}
+}
+
+// TokenBalance 单个代币余额（查询失败时仅该项携带error，不影响其他代币）
+type TokenBalance struct {
+	Token            *TokenResponse `json:"token"`
+	Balance          string         `json:"balance"`           // 最小单位
+	BalanceFormatted string         `json:"balance_formatted"` // 按decimals换算后的余额
+	Error            string         `json:"error,omitempty"`
+}
+
+// WalletTokensResponse 钱包代币余额响应
+type WalletTokensResponse struct {
+	Address string          `json:"address"`
+	ChainID int             `json:"chain_id"`
+	Tokens  []*TokenBalance `json:"tokens"`
+}
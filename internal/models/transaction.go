@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -8,30 +10,65 @@ import (
 type TransactionStatus string
 
 const (
-	TxStatusPending   TransactionStatus = "pending"   // 待确认
-	TxStatusSuccess   TransactionStatus = "success"   // 成功
-	TxStatusFailed    TransactionStatus = "failed"    // 失败
-	TxStatusCancelled TransactionStatus = "cancelled" // 已取消
+	TxStatusPending    TransactionStatus = "pending"    // 待确认
+	TxStatusConfirming TransactionStatus = "confirming" // 已打包，等待达到确认深度
+	TxStatusSuccess    TransactionStatus = "success"    // 成功
+	TxStatusFailed     TransactionStatus = "failed"     // 失败
+	TxStatusCancelled  TransactionStatus = "cancelled"  // 已取消
+	TxStatusDropped    TransactionStatus = "dropped"    // 长时间未打包且nonce已被其他交易使用（被替换或被节点丢弃），不会再上链
+
+	TxStatusAwaitingApproval TransactionStatus = "awaiting_approval" // 等待审批（尚未签名）
+	TxStatusRejected         TransactionStatus = "rejected"          // 审批被拒绝
+	TxStatusExpired          TransactionStatus = "expired"           // 审批已过期
+)
+
+// TransactionType 交易类型
+type TransactionType string
+
+const (
+	TxTypeOnchain  TransactionType = "onchain"  // 链上交易
+	TxTypeInternal TransactionType = "internal" // 本系统钱包之间的内部转账（只更新账本余额，不上链）
 )
 
 // Transaction 交易模型
 type Transaction struct {
-	ID          uint              `gorm:"primaryKey" json:"id"`
-	WalletID    uint              `gorm:"not null;index" json:"wallet_id"`              // 所属钱包ID
-	TxHash      string            `gorm:"unique;not null;size:66;index" json:"tx_hash"` // 交易哈希
-	FromAddress string            `gorm:"not null;size:42" json:"from_address"`         // 发送方地址
-	ToAddress   string            `gorm:"not null;size:42" json:"to_address"`           // 接收方地址
-	Amount      string            `gorm:"type:decimal(36,18);not null" json:"amount"`   // 转账金额
-	GasPrice    string            `gorm:"type:decimal(36,18)" json:"gas_price"`         // Gas价格
-	GasUsed     int64             `json:"gas_used"`                                     // 实际使用的Gas
-	GasLimit    int64             `json:"gas_limit"`                                    // Gas限制
-	Nonce       uint64            `json:"nonce"`                                        // 交易nonce
-	Status      TransactionStatus `gorm:"not null;index;size:20" json:"status"`         // 交易状态
-	BlockNumber int64             `json:"block_number"`                                 // 区块号
-	ChainID     int               `gorm:"not null" json:"chain_id"`                     // 链ID
-	ErrorMsg    string            `gorm:"type:text" json:"error_msg,omitempty"`         // 错误信息（失败时）
-	CreatedAt   time.Time         `json:"created_at"`                                   // 创建时间
-	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`                       // 确认时间
+	ID                    uint                  `gorm:"primaryKey" json:"id"`
+	WalletID              uint                  `gorm:"not null;index;index:idx_transactions_wallet_created,priority:1;index:idx_transactions_wallet_status,priority:1;index:idx_transactions_wallet_updated,priority:1;index:idx_transactions_wallet_amount,priority:1" json:"wallet_id"` // 所属钱包ID
+	TxHash                string                `gorm:"not null;size:66;index;uniqueIndex:idx_transactions_signed_hash_log,priority:1,where:tx_hash <> ''" json:"tx_hash"`                                                                                                                 // 交易哈希
+	Type                  TransactionType       `gorm:"not null;size:20;default:onchain" json:"type"`                                                                                                                                                                                      // 交易类型（内部转账的tx_hash为internal-前缀的合成值）
+	FromAddress           string                `gorm:"not null;size:42;index:idx_transactions_from_address_lower,expression:LOWER(from_address)" json:"from_address"`                                                                                                                     // 发送方地址（表达式索引用于对手方筛选）
+	ToAddress             string                `gorm:"not null;size:42;index:idx_transactions_to_address_lower,expression:LOWER(to_address)" json:"to_address"`                                                                                                                           // 接收方地址（表达式索引用于转入查询）
+	ToENSName             string                `gorm:"size:255" json:"to_ens_name,omitempty"`                                                                                                                                                                                             // 发送时填写的ENS名称（to_address为解析结果）
+	Amount                string                `gorm:"type:decimal(36,18);not null;index:idx_transactions_wallet_amount,priority:2" json:"amount"`                                                                                                                                        // 转账金额（主单位：原生币为ETH/BNB等，代币转账为代币单位）
+	GasPrice              string                `gorm:"type:decimal(36,18)" json:"gas_price"`                                                                                                                                                                                              // Gas价格
+	GasUsed               int64                 `json:"gas_used"`                                                                                                                                                                                                                          // 实际使用的Gas
+	EffectiveGasPrice     string                `gorm:"type:decimal(36,18)" json:"effective_gas_price,omitempty"`                                                                                                                                                                          // 回执中的实际gas单价（最终确认时写入）
+	GasLimit              int64                 `json:"gas_limit"`                                                                                                                                                                                                                         // Gas限制
+	Nonce                 uint64                `json:"nonce"`                                                                                                                                                                                                                             // 交易nonce
+	Status                TransactionStatus     `gorm:"not null;index;size:20;index:idx_transactions_status_created,priority:1;index:idx_transactions_wallet_status,priority:2" json:"status"`                                                                                             // 交易状态
+	BlockNumber           int64                 `json:"block_number"`                                                                                                                                                                                                                      // 区块号
+	Confirmations         uint64                `gorm:"not null;default:0" json:"confirmations"`                                                                                                                                                                                           // 已确认区块数（含交易所在区块）
+	ConfirmationsRequired uint64                `gorm:"not null;default:0" json:"confirmations_required"`                                                                                                                                                                                  // 最终确认所需的区块数（广播时按钱包设置确定），0表示使用链配置
+	ChainID               int                   `gorm:"not null" json:"chain_id"`                                                                                                                                                                                                          // 链ID
+	ErrorMsg              string                `gorm:"type:text" json:"error_msg,omitempty"`                                                                                                                                                                                              // 错误信息（失败时）
+	MethodName            string                `gorm:"size:100" json:"method_name,omitempty"`                                                                                                                                                                                             // 合约方法名（合约调用）
+	MethodArgs            string                `gorm:"type:text" json:"method_args,omitempty"`                                                                                                                                                                                            // 合约方法参数JSON（合约调用）
+	RecurringPaymentID    *uint                 `gorm:"index" json:"recurring_payment_id,omitempty"`                                                                                                                                                                                       // 关联的定期转账计划（定期转账执行）
+	TokenAddress          string                `gorm:"size:42" json:"token_address,omitempty"`                                                                                                                                                                                            // ERC-20合约地址（代币转账），为空表示原生币
+	TokenSymbol           string                `gorm:"size:32" json:"token_symbol,omitempty"`                                                                                                                                                                                             // 代币符号（代币转账）
+	LogIndex              *uint                 `gorm:"uniqueIndex:idx_transactions_signed_hash_log,priority:2,expression:COALESCE(log_index\\,-1)" json:"log_index,omitempty"`                                                                                                            // 事件日志序号（代币入账，同一交易可包含多笔代币转账）
+	Note                  string                `gorm:"size:500" json:"-"`                                                                                                                                                                                                                 // 用户备注（不写入链上与队列消息）
+	Tags                  []TransactionTag      `gorm:"foreignKey:TransactionID;constraint:OnDelete:CASCADE" json:"-"`                                                                                                                                                                     // 用户标签
+	RequiredApprovals     int                   `gorm:"not null;default:0" json:"required_approvals,omitempty"`                                                                                                                                                                            // 所需审批人数量（创建时的钱包审批策略）
+	ProposedBy            *uint                 `json:"proposed_by,omitempty"`                                                                                                                                                                                                             // 发起待审批交易的用户（不能批准自己发起的交易）
+	ApprovalExpiresAt     *time.Time            `json:"approval_expires_at,omitempty"`                                                                                                                                                                                                     // 审批截止时间
+	Approvals             []TransactionApproval `gorm:"foreignKey:TransactionID;constraint:OnDelete:CASCADE" json:"-"`                                                                                                                                                                     // 审批记录
+	CreatedAt             time.Time             `gorm:"index:idx_transactions_wallet_created,priority:2,sort:desc;index:idx_transactions_status_created,priority:2" json:"created_at"`                                                                                                     // 创建时间
+	ConfirmedAt           *time.Time            `json:"confirmed_at,omitempty"`                                                                                                                                                                                                            // 确认时间
+	UpdatedAt             time.Time             `gorm:"index:idx_transactions_wallet_updated,priority:2" json:"-"`                                                                                                                                                                         // 最近更新时间（交易列表的ETag）
+	NextCheckAt           time.Time             `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_transactions_next_check" json:"-"`                                                                                                                                                     // 下次检查回执的时间（按交易年龄分级退避）
+	StuckNotifiedAt       *time.Time            `json:"-"`                                                                                                                                                                                                                                 // 已推送交易卡住提醒的时间（每笔交易只提醒一次）
+	CheckFailures         int                   `gorm:"not null;default:0" json:"-"`                                                                                                                                                                                                       // 连续查询回执或交易失败的次数（按次数退避，检查成功后清零）
 }
 
 // TableName 指定表名
@@ -41,72 +78,309 @@ func (Transaction) TableName() string {
 
 // TransactionCreateRequest 创建交易请求
 type TransactionCreateRequest struct {
-	FromAddress string `json:"from_address" binding:"required,eth_addr"` // 自定义验证器：eth_addr
-	ToAddress   string `json:"to_address" binding:"required,eth_addr"`
-	Amount      string `json:"amount" binding:"required,numeric,gt=0"` // 金额必须大于0
-	ChainID     int    `json:"chain_id" binding:"required,oneof=1 56 560048"`
-	GasLimit    int64  `json:"gas_limit" binding:"omitempty,gt=0"` // 可选，默认21000
+	FromAddress string     `json:"from_address" binding:"omitempty,eth_addr"`                                 // 发送钱包，省略时使用用户在chain_id上的默认钱包
+	ToAddress   string     `json:"to_address" binding:"required_without=ContactID,omitempty,eth_addr_or_ens"` // 地址或ENS名称，与contact_id二选一
+	ContactID   uint       `json:"contact_id" binding:"omitempty"`                                            // 地址簿联系人ID
+	Amount      string     `json:"amount" binding:"required"`                                                 // 金额（单位见amount_unit，大于0，服务层精确换算为Wei）
+	AmountUnit  AmountUnit `json:"amount_unit,omitempty" binding:"omitempty,oneof=wei eth"`                   // 金额单位：wei（默认，十进制整数）或eth（原生币主单位，最多18位小数）
+	ChainID     int        `json:"chain_id" binding:"required,chain_id"`
+	GasLimit    int64      `json:"gas_limit" binding:"omitempty,gt=0"`                             // 可选，默认为链的原生币转账gas用量
+	Speed       GasSpeed   `json:"speed,omitempty" binding:"omitempty,oneof=slow standard fast"`   // Gas价格档位，未指定时使用节点建议价格
+	Passphrase  string     `json:"passphrase,omitempty"`                                           // 钱包私钥口令（钱包设置了口令时必填）
+	Note        string     `json:"note,omitempty" binding:"max=500"`                               // 备注（仅本地保存）
+	Tags        []string   `json:"tags,omitempty" binding:"omitempty,max=10,dive,required,max=32"` // 标签（仅本地保存）
+	Internal    bool       `json:"internal,omitempty"`                                             // 内部转账：收款方为本系统中同一链上的钱包时只更新双方账本余额，不上链、不消耗gas
+}
+
+// TransactionSimulateRequest 交易模拟请求（按发送交易的规则构建，不签名、不保存）
+type TransactionSimulateRequest struct {
+	FromAddress string        `json:"from_address" binding:"required,eth_addr"`
+	ToAddress   string        `json:"to_address" binding:"required,eth_addr_or_ens"`           // 收款地址或ENS名称，合约调用时为合约地址
+	Amount      string        `json:"amount"`                                                  // 金额（单位见amount_unit），普通转账必填且大于0，合约调用默认0
+	AmountUnit  AmountUnit    `json:"amount_unit,omitempty" binding:"omitempty,oneof=wei eth"` // 金额单位：wei（默认）或eth（原生币主单位，最多18位小数）
+	ChainID     int           `json:"chain_id" binding:"required,chain_id"`
+	ABI         string        `json:"abi"`                                                          // 合约调用：完整ABI数组或单个方法片段，可省略
+	Method      string        `json:"method"`                                                       // 合约调用：方法名，未提供ABI时为方法签名；为空时按普通转账模拟
+	Args        []interface{} `json:"args"`                                                         // 合约调用：方法参数，大整数请使用字符串
+	GasLimit    int64         `json:"gas_limit" binding:"omitempty,gt=0"`                           // 可选，默认与发送交易相同
+	Speed       GasSpeed      `json:"speed,omitempty" binding:"omitempty,oneof=slow standard fast"` // Gas价格档位，未指定时使用节点建议价格
+}
+
+// TransactionSimulateResponse 交易模拟结果（金额均为Wei）
+type TransactionSimulateResponse struct {
+	Success           bool   `json:"success"`                 // 预计可以成功执行（未回滚且余额充足）
+	Reverted          bool   `json:"reverted"`                // 合约调用执行回滚
+	RevertReason      string `json:"revert_reason,omitempty"` // 回滚原因（Error(string)/Panic(uint256)解码，无法解码时为节点返回的错误）
+	ReturnData        string `json:"return_data,omitempty"`   // 合约调用返回数据（十六进制）
+	GasLimit          int64  `json:"gas_limit"`               // 执行回滚且未指定gas_limit时为0
+	GasPrice          string `json:"gas_price"`
+	EstimatedFee      string `json:"estimated_fee"` // gas_limit × gas_price
+	TotalCost         string `json:"total_cost"`    // 金额 + 手续费
+	Balance           string `json:"balance"`       // 可用于链上发送的余额
+	SufficientBalance bool   `json:"sufficient_balance"`
+}
+
+// WalletSweepRequest 清空钱包余额请求（金额为链上余额减去网络费用，由服务端计算）
+type WalletSweepRequest struct {
+	ToAddress  string   `json:"to_address" binding:"required,eth_addr_or_ens"`                  // 收款地址或ENS名称
+	Speed      GasSpeed `json:"speed,omitempty" binding:"omitempty,oneof=slow standard fast"`   // Gas价格档位，未指定时使用节点建议价格
+	Passphrase string   `json:"passphrase,omitempty"`                                           // 钱包私钥口令（钱包设置了口令时必填）
+	Note       string   `json:"note,omitempty" binding:"max=500"`                               // 备注（仅本地保存）
+	Tags       []string `json:"tags,omitempty" binding:"omitempty,max=10,dive,required,max=32"` // 标签（仅本地保存）
 }
 
 // TransactionResponse 交易响应
 type TransactionResponse struct {
-	ID          uint              `json:"id"`
-	TxHash      string            `json:"tx_hash"`
-	FromAddress string            `json:"from_address"`
-	ToAddress   string            `json:"to_address"`
-	Amount      string            `json:"amount"`
-	GasPrice    string            `json:"gas_price"`
-	GasUsed     int64             `json:"gas_used"`
-	Status      TransactionStatus `json:"status"`
-	BlockNumber int64             `json:"block_number"`
-	ChainID     int               `json:"chain_id"`
-	ChainName   string            `json:"chain_name"`
-	CreatedAt   time.Time         `json:"created_at"`
-	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+	ID                    uint               `json:"id"`
+	TxHash                string             `json:"tx_hash"`
+	Type                  TransactionType    `json:"type"` // onchain或internal
+	FromAddress           string             `json:"from_address"`
+	ToAddress             string             `json:"to_address"`
+	ToENSName             string             `json:"to_ens_name,omitempty"`   // 发送时填写的ENS名称
+	FromENSName           string             `json:"from_ens_name,omitempty"` // 转入交易发送方的ENS主名称（启用反向解析时）
+	Amount                string             `json:"amount"`                  // 金额（主单位十进制数：原生币转账为ETH/BNB等，代币转账为代币单位）
+	AmountWei             string             `json:"amount_wei,omitempty"`    // 原生币金额（Wei，代币转账为空）
+	AmountSymbol          string             `json:"amount_symbol,omitempty"` // 金额的币种符号（原生币符号或代币符号）
+	GasPrice              string             `json:"gas_price"`
+	GasUsed               int64              `json:"gas_used"`
+	EffectiveGasPrice     string             `json:"effective_gas_price,omitempty"` // 实际gas单价（最终确认后）
+	Status                TransactionStatus  `json:"status"`
+	ErrorMsg              string             `json:"error_msg,omitempty"` // 失败原因（链上执行失败时为回滚原因，无法获取时为reason unavailable）
+	BlockNumber           int64              `json:"block_number"`
+	Confirmations         uint64             `json:"confirmations"`
+	ConfirmationsRequired uint64             `json:"confirmations_required,omitempty"` // 最终确认所需的区块数
+	ChainID               int                `json:"chain_id"`
+	ChainName             string             `json:"chain_name"`
+	ExplorerURL           string             `json:"explorer_url,omitempty"`         // 交易在区块浏览器中的链接（链未配置浏览器、内部转账或尚未发送时为空）
+	ContactName           string             `json:"contact_name,omitempty"`         // 收款地址匹配的地址簿联系人名称
+	FromLabel             *CounterpartyLabel `json:"from_label,omitempty"`           // 发送方标签（公共地址标签，未匹配时为地址簿联系人）
+	ToLabel               *CounterpartyLabel `json:"to_label,omitempty"`             // 收款方标签（公共地址标签，未匹配时为地址簿联系人）
+	Method                string             `json:"method,omitempty"`               // 合约调用摘要，如approve(spender, amount)
+	MethodArgs            json.RawMessage    `json:"method_args,omitempty"`          // 合约调用参数
+	RecurringPaymentID    *uint              `json:"recurring_payment_id,omitempty"` // 关联的定期转账计划
+	TokenAddress          string             `json:"token_address,omitempty"`        // ERC-20合约地址（代币转账）
+	TokenSymbol           string             `json:"token_symbol,omitempty"`         // 代币符号（代币转账）
+	Note                  string             `json:"note,omitempty"`                 // 用户备注
+	Tags                  []string           `json:"tags,omitempty"`                 // 用户标签
+	RequiredApprovals     int                `json:"required_approvals,omitempty"`   // 所需审批人数量
+	ProposedBy            *uint              `json:"proposed_by,omitempty"`          // 发起审批的用户
+	ApprovedBy            []uint             `json:"approved_by,omitempty"`          // 已批准的审批人
+	ApprovalExpiresAt     *time.Time         `json:"approval_expires_at,omitempty"`  // 审批截止时间
+	CreatedAt             time.Time          `json:"created_at"`
+	ConfirmedAt           *time.Time         `json:"confirmed_at,omitempty"`
+	*TransactionFees                         // 费用明细（展开为同级字段，内部转账与非本系统发送的交易没有费用明细）
+}
+
+// TransactionFees 交易费用明细（服务端以整数精确计算，*_eth为18位小数的原生币金额）
+//
+// 最高费用 = gas_price * gas_limit（EIP-1559交易的gas_price为每单位gas的最高费用）；最终确认后实际费用 = gas_used * effective_gas_price。
+type TransactionFees struct {
+	MaxFeeWei       string `json:"max_fee_wei,omitempty"`        // 最高手续费（Wei）
+	MaxFeeEth       string `json:"max_fee_eth,omitempty"`        // 最高手续费
+	TotalMaxCostWei string `json:"total_max_cost_wei,omitempty"` // 最多扣除的原生币总额（转账金额 + 最高手续费，Wei）
+	TotalMaxCostEth string `json:"total_max_cost_eth,omitempty"` // 最多扣除的原生币总额
+	ActualFeeWei    string `json:"actual_fee_wei,omitempty"`     // 实际手续费（最终确认后，Wei）
+	ActualFeeEth    string `json:"actual_fee_eth,omitempty"`     // 实际手续费（最终确认后）
 }
 
 // ToResponse 转换为响应格式
 func (t *Transaction) ToResponse() *TransactionResponse {
-	chainName := "Unknown"
-	switch t.ChainID {
-	case 1:
-		chainName = "Ethereum"
-	case 56:
-		chainName = "BSC"
-	case 560048:
-		chainName = "Hoodi"
+	var methodArgs json.RawMessage
+	if t.MethodArgs != "" {
+		methodArgs = json.RawMessage(t.MethodArgs)
 	}
 
 	return &TransactionResponse{
-		ID:          t.ID,
-		TxHash:      t.TxHash,
-		FromAddress: t.FromAddress,
-		ToAddress:   t.ToAddress,
-		Amount:      t.Amount,
-		GasPrice:    t.GasPrice,
-		GasUsed:     t.GasUsed,
-		Status:      t.Status,
-		BlockNumber: t.BlockNumber,
-		ChainID:     t.ChainID,
-		ChainName:   chainName,
-		CreatedAt:   t.CreatedAt,
-		ConfirmedAt: t.ConfirmedAt,
+		ID:                    t.ID,
+		TxHash:                t.TxHash,
+		Type:                  t.Type,
+		FromAddress:           t.FromAddress,
+		ToAddress:             t.ToAddress,
+		ToENSName:             t.ToENSName,
+		Amount:                t.Amount,
+		AmountWei:             t.amountWei(),
+		AmountSymbol:          t.amountSymbol(),
+		GasPrice:              t.GasPrice,
+		GasUsed:               t.GasUsed,
+		EffectiveGasPrice:     t.EffectiveGasPrice,
+		Status:                t.Status,
+		ErrorMsg:              t.ErrorMsg,
+		BlockNumber:           t.BlockNumber,
+		Confirmations:         t.Confirmations,
+		ConfirmationsRequired: t.ConfirmationsRequired,
+		ChainID:               t.ChainID,
+		ChainName:             ChainName(t.ChainID),
+		ExplorerURL:           ExplorerTxURL(t.ChainID, t.TxHash),
+		CreatedAt:             t.CreatedAt,
+		ConfirmedAt:           t.ConfirmedAt,
+		Method:                t.methodSummary(),
+		MethodArgs:            methodArgs,
+		RecurringPaymentID:    t.RecurringPaymentID,
+		TokenAddress:          t.TokenAddress,
+		TokenSymbol:           t.TokenSymbol,
+		Note:                  t.Note,
+		Tags:                  t.TagNames(),
+		RequiredApprovals:     t.RequiredApprovals,
+		ProposedBy:            t.ProposedBy,
+		ApprovedBy:            t.ApprovedBy(),
+		ApprovalExpiresAt:     t.ApprovalExpiresAt,
+	}
+}
+
+// ApprovedBy 已批准的审批人用户ID
+func (t *Transaction) ApprovedBy() []uint {
+	var ids []uint
+	for _, approval := range t.Approvals {
+		if approval.Decision == ApprovalApproved {
+			ids = append(ids, approval.UserID)
+		}
+	}
+	return ids
+}
+
+// TagNames 标签名称列表
+func (t *Transaction) TagNames() []string {
+	if len(t.Tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		names[i] = tag.Tag
 	}
+	return names
+}
+
+// amountWei 原生币金额的Wei值（代币转账返回空字符串）
+func (t *Transaction) amountWei() string {
+	if t.TokenAddress != "" {
+		return ""
+	}
+	return nativeAmountWei(t.Amount)
+}
+
+// amountSymbol 金额的币种符号（代币转账为代币符号，否则为链的原生币符号）
+func (t *Transaction) amountSymbol() string {
+	if t.TokenAddress != "" {
+		return t.TokenSymbol
+	}
+	return nativeSymbol(t.ChainID)
+}
+
+// methodSummary 生成合约调用摘要，如approve(spender, amount)
+func (t *Transaction) methodSummary() string {
+	if t.MethodName == "" {
+		return ""
+	}
+
+	var args []struct {
+		Name string `json:"name"`
+	}
+	json.Unmarshal([]byte(t.MethodArgs), &args)
+
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Name
+	}
+	return t.MethodName + "(" + strings.Join(names, ", ") + ")"
 }
 
 // TransactionListRequest 交易列表查询请求
 type TransactionListRequest struct {
-	WalletAddress string            `form:"wallet_address" binding:"omitempty,eth_addr"`             // 按钱包地址筛选
-	Status        TransactionStatus `form:"status" binding:"omitempty,oneof=pending success failed"` // 按状态筛选
-	ChainID       int               `form:"chain_id" binding:"omitempty,oneof=1 56 560048"`          // 按链筛选
-	Page          int               `form:"page" binding:"omitempty,min=1"`                          // 页码，默认1
-	PageSize      int               `form:"page_size" binding:"omitempty,min=1,max=100"`             // 每页数量，默认20
+	WalletAddress string            `form:"wallet_address" binding:"omitempty,eth_addr"`                                                                   // 按钱包地址筛选
+	Status        TransactionStatus `form:"status" binding:"omitempty,oneof=pending confirming success failed dropped awaiting_approval rejected expired"` // 按状态筛选
+	ChainID       int               `form:"chain_id" binding:"omitempty,chain_id"`                                                                         // 按链筛选
+	Tag           string            `form:"tag" binding:"omitempty,max=32"`                                                                                // 按标签筛选
+	From          time.Time         `form:"from"`                                                                                                          // 创建时间下限（含，RFC3339），默认不限
+	To            time.Time         `form:"to"`                                                                                                            // 创建时间上限（不含，RFC3339），默认不限
+	Page          int               `form:"page" binding:"omitempty,min=1"`                                                                                // 页码，默认1
+	PageSize      int               `form:"page_size" binding:"omitempty,min=1,max=100"`                                                                   // 每页数量，默认20
+	TransactionFilter
+}
+
+// TransactionDirection 交易方向筛选（相对于筛选范围内的钱包）
+type TransactionDirection string
+
+const (
+	DirectionIncoming TransactionDirection = "incoming" // 转入：代币入账，以及收款地址为筛选范围内钱包的交易
+	DirectionOutgoing TransactionDirection = "outgoing" // 转出：由筛选范围内的钱包发送的交易
+)
+
+// TransactionFilter 交易列表、导出与统计共用的筛选条件（零值表示不限）
+type TransactionFilter struct {
+	Direction    TransactionDirection `json:"direction,omitempty" form:"direction" binding:"omitempty,oneof=incoming outgoing"` // 交易方向
+	MinAmount    string               `json:"min_amount,omitempty" form:"min_amount" binding:"omitempty,decimal_amount"`        // 金额下限（含，主单位，按数值比较）
+	MaxAmount    string               `json:"max_amount,omitempty" form:"max_amount" binding:"omitempty,decimal_amount"`        // 金额上限（含，主单位，按数值比较）
+	Counterparty string               `json:"counterparty,omitempty" form:"counterparty" binding:"omitempty,eth_addr"`          // 对手方地址（交易的发送方或接收方）
 }
 
 // TransactionListResponse 交易列表响应
 type TransactionListResponse struct {
 	Total        int64                  `json:"total"`
-	Page         int                    `json:"page"`
-	PageSize     int                    `json:"page_size"`
+	Page         int                    `json:"page"`        // 实际使用的页码（未指定时为默认值1）
+	PageSize     int                    `json:"page_size"`   // 实际使用的每页数量（未指定时为默认值20）
+	TotalPages   int64                  `json:"total_pages"` // 总页数（没有记录时为0）
+	HasNext      bool                   `json:"has_next"`    // 当前页之后是否还有记录
 	Transactions []*TransactionResponse `json:"transactions"`
 }
+
+// TransactionExportRequest 交易导出请求
+type TransactionExportRequest struct {
+	Format        string    `form:"format" binding:"omitempty,oneof=csv json"`   // 导出格式，默认csv
+	From          time.Time `form:"from" time_format:"2006-01-02"`               // 开始日期（含），默认不限
+	To            time.Time `form:"to" time_format:"2006-01-02"`                 // 结束日期（含），默认不限
+	ChainID       int       `form:"chain_id" binding:"omitempty,chain_id"`       // 按链筛选
+	WalletAddress string    `form:"wallet_address" binding:"omitempty,eth_addr"` // 按钱包地址筛选，默认用户所有钱包
+	TransactionFilter
+}
+
+// TransactionExportFilter 交易导出查询条件（仓库层使用）
+type TransactionExportFilter struct {
+	UserID   uint
+	WalletID uint // 0表示用户所有钱包
+	ChainID  int
+	From     time.Time // 零值表示不限
+	To       time.Time // 不含，零值表示不限
+	TransactionFilter
+}
+
+// TransactionMetaRequest 更新交易备注与标签请求（字段为空表示不修改）
+type TransactionMetaRequest struct {
+	Note *string   `json:"note" binding:"omitempty,max=500"`                     // 备注，空字符串表示清除
+	Tags *[]string `json:"tags" binding:"omitempty,max=10,dive,required,max=32"` // 标签，整体替换，空数组表示清除
+}
+
+// TransactionTag 交易标签
+type TransactionTag struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	TransactionID uint   `gorm:"not null;uniqueIndex:idx_transaction_tags_tx_tag,priority:1" json:"transaction_id"`    // 交易ID
+	Tag           string `gorm:"not null;size:32;uniqueIndex:idx_transaction_tags_tx_tag,priority:2;index" json:"tag"` // 标签（小写）
+}
+
+// TableName 指定表名
+func (TransactionTag) TableName() string {
+	return "transaction_tags"
+}
+
+// 交易方向
+const (
+	TxDirectionOut  = "out"  // 转出
+	TxDirectionIn   = "in"   // 转入
+	TxDirectionSelf = "self" // 用户钱包之间互转
+)
+
+// TransactionExportRow 交易导出行
+type TransactionExportRow struct {
+	TxHash      string            `json:"tx_hash"`
+	Direction   string            `json:"direction"`
+	FromAddress string            `json:"from_address"`
+	ToAddress   string            `json:"to_address"`
+	Amount      string            `json:"amount"`  // 转账金额（ETH）
+	GasFee      string            `json:"gas_fee"` // Gas费用（ETH）
+	Status      TransactionStatus `json:"status"`
+	ChainName   string            `json:"chain_name"`
+	ExplorerURL string            `json:"explorer_url,omitempty"` // 交易在区块浏览器中的链接
+	CreatedAt   time.Time         `json:"created_at"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+	Note        string            `json:"note,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
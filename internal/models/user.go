@@ -1,20 +1,35 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
+	"unicode"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// UserRole 用户角色
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"  // 普通用户
+	UserRoleAdmin UserRole = "admin" // 运维管理员（可访问/api/v1/admin）
+)
+
 // User 用户模型
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"unique;not null;size:50" json:"username"`
-	Email     string    `gorm:"unique;not null;size:100" json:"email"`
-	Password  string    `gorm:"column:password_hash;not null;size:255" json:"-"` // 密码哈希，不返回给前端
-	Wallets   []Wallet  `gorm:"foreignKey:UserID" json:"wallets,omitempty"`      // 关联钱包
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Username     string     `gorm:"unique;not null;size:50" json:"username"`
+	Email        string     `gorm:"serializer:encrypted;not null;size:255" json:"email"` // 加密存储（AES-GCM）
+	EmailHash    *string    `gorm:"unique;size:64" json:"-"`                             // 邮箱的HMAC摘要，用于唯一约束与检索（回填前为空）
+	Password     string     `gorm:"column:password_hash;not null;size:255" json:"-"`     // 密码哈希，不返回给前端
+	TokenVersion int        `gorm:"not null;default:0" json:"-"`                         // Token版本，递增后所有旧Token失效
+	Role         UserRole   `gorm:"not null;size:20;default:user" json:"role"`           // 用户角色（管理员只能通过数据库或运维工具授予）
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`                               // 停用时间（运维工具停用，停用期间无法登录、API Key无法认证）
+	Wallets      []Wallet   `gorm:"foreignKey:UserID" json:"wallets,omitempty"`          // 关联钱包
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -45,6 +60,64 @@ type UserCreateRequest struct {
 	Password string `json:"password" binding:"required,min=6,max=50"`
 }
 
+// UserProfileUpdateRequest 更新个人资料请求
+type UserProfileUpdateRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+}
+
+// ChangePasswordRequest 修改密码请求（新密码需满足密码复杂度策略）
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,max=50"`
+}
+
+// ChangePasswordResponse 修改密码的响应（已签发的Token全部失效，客户端需改用新Token）
+type ChangePasswordResponse struct {
+	Token string `json:"token"`
+}
+
+// PasswordPolicy 密码复杂度策略
+type PasswordPolicy struct {
+	MinLength     int  // 最小长度
+	RequireUpper  bool // 必须包含大写字母
+	RequireLower  bool // 必须包含小写字母
+	RequireDigit  bool // 必须包含数字
+	RequireSymbol bool // 必须包含标点或符号
+}
+
+// Check 校验密码是否满足策略，返回第一条不满足的规则
+func (p PasswordPolicy) Check(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters", p.MinLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return errors.New("password must contain an uppercase letter")
+	case p.RequireLower && !lower:
+		return errors.New("password must contain a lowercase letter")
+	case p.RequireDigit && !digit:
+		return errors.New("password must contain a digit")
+	case p.RequireSymbol && !symbol:
+		return errors.New("password must contain a symbol")
+	}
+	return nil
+}
+
 // UserLoginRequest 用户登录请求
 type UserLoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -56,6 +129,7 @@ type UserResponse struct {
 	ID        uint      `json:"id"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
+	Role      UserRole  `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -65,6 +139,7 @@ func (u *User) ToResponse() *UserResponse {
 		ID:        u.ID,
 		Username:  u.Username,
 		Email:     u.Email,
+		Role:      u.Role,
 		CreatedAt: u.CreatedAt,
 	}
 }
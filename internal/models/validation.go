@@ -0,0 +1,6 @@
+package models
+
+// ValidationErrorData 参数校验失败响应的附加信息
+type ValidationErrorData struct {
+	Fields map[string]string `json:"fields"` // 字段名 -> 失败原因（嵌套字段如preferences[0].type）
+}
@@ -1,21 +1,40 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 // Wallet 钱包模型
 type Wallet struct {
-	ID                  uint          `gorm:"primaryKey" json:"id"`
-	UserID              uint          `gorm:"not null;index" json:"user_id"`                     // 所属用户ID
-	Address             string        `gorm:"unique;not null;size:42;index" json:"address"`      // 钱包地址
-	PrivateKeyEncrypted string        `gorm:"not null;type:text" json:"-"`                       // 加密的私钥，不返回给前端
-	ChainID             int           `gorm:"not null" json:"chain_id"`                          // 链ID：1=Ethereum, 56=BSC
-	Balance             string        `gorm:"type:decimal(36,18);default:0" json:"balance"`      // 余额（字符串避免精度问题）
-	Name                string        `gorm:"size:100" json:"name,omitempty"`                    // 钱包名称（可选）
-	Transactions        []Transaction `gorm:"foreignKey:WalletID" json:"transactions,omitempty"` // 关联交易
-	CreatedAt           time.Time     `json:"created_at"`
-	UpdatedAt           time.Time     `json:"updated_at"`
+	ID                    uint             `gorm:"primaryKey" json:"id"`
+	UserID                uint             `gorm:"not null;index;index:idx_wallets_user_chain,priority:1;uniqueIndex:idx_wallets_user_chain_default,priority:1,where:is_default" json:"user_id"` // 所属用户ID
+	OrgID                 *uint            `gorm:"index" json:"org_id,omitempty"`                                                                                                                // 所属组织ID（组织钱包按成员角色授权），为空表示个人钱包
+	Address               string           `gorm:"unique;not null;size:42;index" json:"address"`                                                                                                 // 钱包地址
+	PrivateKeyEncrypted   string           `gorm:"not null;type:text" json:"-"`                                                                                                                  // 加密的私钥，不返回给前端
+	KeyKDF                string           `gorm:"size:20" json:"-"`                                                                                                                             // 用户口令的密钥派生算法（scrypt），为空表示未设置口令
+	KeyKDFParams          string           `gorm:"size:100" json:"-"`                                                                                                                            // 密钥派生参数JSON
+	KeyKDFSalt            string           `gorm:"size:64" json:"-"`                                                                                                                             // 密钥派生盐值（十六进制）
+	ChainID               int              `gorm:"not null;index:idx_wallets_user_chain,priority:2;uniqueIndex:idx_wallets_user_chain_default,priority:2" json:"chain_id"`                       // 链ID：1=Ethereum, 56=BSC
+	Balance               string           `gorm:"type:decimal(78,0);default:0" json:"balance"`                                                                                                  // 账本余额（Wei，字符串避免精度问题）= 链上余额 + 内部转账净额
+	InternalNetWei        string           `gorm:"type:decimal(78,0);not null;default:0" json:"-"`                                                                                               // 内部转账累计净额（Wei，转入为正、转出为负，尚未在链上结算）
+	BalanceStaleAt        *time.Time       `gorm:"index" json:"-"`                                                                                                                               // 交易最终确认后链上余额尚未刷新的时间（与交易状态同一事务写入，刷新后清空，Worker定时重试）
+	Name                  string           `gorm:"size:100" json:"name,omitempty"`                                                                                                               // 钱包名称（可选）
+	Label                 string           `gorm:"size:50" json:"label,omitempty"`                                                                                                               // 分类标签（可选）
+	Color                 string           `gorm:"size:7" json:"color,omitempty"`                                                                                                                // 展示颜色（#RGB或#RRGGBB，可选）
+	Archived              bool             `gorm:"not null;default:false" json:"archived"`                                                                                                       // 已归档（不在默认列表中展示，不参与后台余额刷新，不能发送交易）
+	IsDefault             bool             `gorm:"not null;default:false" json:"is_default"`                                                                                                     // 用户在该链上的默认钱包（仅个人钱包，唯一约束见迁移00021），转账可省略from_address
+	WhitelistEnabled      bool             `gorm:"not null;default:false" json:"whitelist_enabled"`                                                                                              // 是否仅允许向白名单地址转账
+	DailyLimitWei         string           `gorm:"size:78" json:"daily_limit_wei,omitempty"`                                                                                                     // 滚动24小时最大转出金额（Wei），空表示不限
+	DailyTxLimit          int              `gorm:"not null;default:0" json:"daily_tx_limit"`                                                                                                     // 滚动24小时最大交易笔数，0表示不限
+	ApprovalThresholdWei  string           `gorm:"size:78" json:"approval_threshold_wei,omitempty"`                                                                                              // 超过该金额（Wei）的转账需要审批，空表示不需要
+	RequiredApprovals     int              `gorm:"not null;default:0" json:"required_approvals"`                                                                                                 // 所需的不同审批人数量
+	ConfirmationsRequired uint64           `gorm:"not null;default:0" json:"confirmations_required"`                                                                                             // 交易视为最终确认所需的区块数，0表示使用链配置
+	FundingStatus         string           `gorm:"size:20;not null;default:''" json:"funding_status,omitempty"`                                                                                  // 测试链领水状态（funding_pending、funded、funding_failed），为空表示未领水
+	Approvers             []WalletApprover `gorm:"foreignKey:WalletID;constraint:OnDelete:CASCADE" json:"-"`                                                                                     // 审批人
+	Transactions          []Transaction    `gorm:"foreignKey:WalletID" json:"transactions,omitempty"`                                                                                            // 关联交易
+	CreatedAt             time.Time        `json:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -23,10 +42,56 @@ func (Wallet) TableName() string {
 	return "wallets"
 }
 
+// PassphraseProtected 私钥是否由用户口令保护（非托管模式）
+func (w *Wallet) PassphraseProtected() bool {
+	return w.KeyKDF != ""
+}
+
 // WalletCreateRequest 创建钱包请求
 type WalletCreateRequest struct {
-	ChainID int    `json:"chain_id" binding:"required,oneof=1 56 560048"` // 只支持1(Ethereum)和56(BSC) 560048(Hoodi)
-	Name    string `json:"name" binding:"max=100"`                        // 可选的钱包名称
+	ChainID    int    `json:"chain_id" binding:"required,chain_id"`         // 需为blockchain.chains中配置的链
+	Name       string `json:"name" binding:"max=100"`                       // 可选的钱包名称
+	Passphrase string `json:"passphrase" binding:"omitempty,min=8,max=128"` // 可选的私钥口令，设置后签名交易必须提供（服务端无法单独动用资金）
+	OrgID      uint   `json:"org_id" binding:"omitempty"`                   // 创建为组织钱包（需要admin及以上角色）
+}
+
+// WalletBulkCreateRequest 批量创建钱包请求（用于交易所等批量生成充值地址，不支持私钥口令）
+type WalletBulkCreateRequest struct {
+	ChainID    int    `json:"chain_id" binding:"required,chain_id"`   // 需为blockchain.chains中配置的链
+	Count      int    `json:"count" binding:"required,min=1,max=500"` // 创建的钱包数量
+	NamePrefix string `json:"name_prefix" binding:"omitempty,max=90"` // 可选的名称前缀，钱包名称为<前缀>-<序号>
+	OrgID      uint   `json:"org_id" binding:"omitempty"`             // 创建为组织钱包（需要admin及以上角色）
+}
+
+// WalletBulkCreateResponse 批量创建钱包响应
+type WalletBulkCreateResponse struct {
+	ChainID   int      `json:"chain_id"`
+	Addresses []string `json:"addresses"` // 按创建顺序排列的钱包地址
+}
+
+// WalletImportRequest 导入钱包请求（private_key与keystore二选一）
+type WalletImportRequest struct {
+	ChainID            int             `json:"chain_id" binding:"required,chain_id"`                         // 需为blockchain.chains中配置的链
+	Name               string          `json:"name" binding:"max=100"`                                       // 可选的钱包名称
+	PrivateKey         string          `json:"private_key" binding:"required_without=Keystore,max=66"`       // 十六进制私钥（可带0x前缀）
+	Keystore           json.RawMessage `json:"keystore,omitempty" binding:"max=8192"`                        // Web3 Secret Storage格式的keystore JSON（geth、MetaMask导出）
+	KeystorePassphrase string          `json:"keystore_passphrase" binding:"required_with=Keystore,max=128"` // keystore的解密口令
+	Passphrase         string          `json:"passphrase" binding:"omitempty,min=8,max=128"`                 // 可选的私钥口令（同创建钱包）
+	OrgID              uint            `json:"org_id" binding:"omitempty"`                                   // 导入为组织钱包（需要admin及以上角色）
+}
+
+// WalletKeystoreExportRequest 导出keystore备份请求
+type WalletKeystoreExportRequest struct {
+	Password           string `json:"password" binding:"required,max=50"`                   // 当前登录密码（导出前重新验证身份）
+	KeystorePassphrase string `json:"keystore_passphrase" binding:"required,min=8,max=128"` // 加密keystore的口令，导入其他钱包时需要
+	Passphrase         string `json:"passphrase" binding:"omitempty,max=128"`               // 钱包私钥口令（钱包设置了口令时必填）
+}
+
+// WalletKeystoreResponse keystore备份响应
+type WalletKeystoreResponse struct {
+	Address  string          `json:"address"`
+	Filename string          `json:"filename"` // geth约定的文件名（UTC--<时间>--<地址>）
+	Keystore json.RawMessage `json:"keystore"` // Web3 Secret Storage v3格式（scrypt）的keystore JSON
 }
 
 // WalletResponse 钱包响应
@@ -37,34 +102,117 @@ type WalletResponse struct {
 	ChainName string    `json:"chain_name"` // 链名称（前端展示用）
 	Balance   string    `json:"balance"`
 	Name      string    `json:"name,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Color     string    `json:"color,omitempty"`
+	Archived  bool      `json:"archived"`
+	IsDefault bool      `json:"is_default"`       // 用户在该链上的默认钱包
+	OrgID     *uint     `json:"org_id,omitempty"` // 所属组织ID
 	CreatedAt time.Time `json:"created_at"`
+
+	ExplorerURL string `json:"explorer_url,omitempty"` // 地址在区块浏览器中的链接（链未配置浏览器时为空）
+
+	FundingStatus string `json:"funding_status,omitempty"` // 测试链领水状态：funding_pending（待发送）、funded（已广播）、funding_failed
+
+	WhitelistEnabled bool   `json:"whitelist_enabled"`         // 是否启用转账白名单
+	DailyLimitWei    string `json:"daily_limit_wei,omitempty"` // 每日转出金额上限（Wei）
+	DailyTxLimit     int    `json:"daily_tx_limit,omitempty"`  // 每日交易笔数上限
+
+	ApprovalThresholdWei string `json:"approval_threshold_wei,omitempty"` // 超过该金额（Wei）的转账需要审批
+	RequiredApprovals    int    `json:"required_approvals,omitempty"`     // 所需的审批人数量
+
+	ConfirmationsRequired uint64 `json:"confirmations_required,omitempty"` // 交易视为最终确认所需的区块数，未设置时使用链配置
+
+	PassphraseProtected bool `json:"passphrase_protected"` // 私钥是否由用户口令保护
+
+	BalanceUSD       string `json:"balance_usd,omitempty"`       // 余额的美元估值
+	PriceUnavailable bool   `json:"price_unavailable,omitempty"` // 价格服务不可用或资产无法定价
+	Stale            bool   `json:"stale,omitempty"`             // 链节点不健康，余额可能已过期
 }
 
 // ToResponse 转换为响应格式
 func (w *Wallet) ToResponse() *WalletResponse {
-	chainName := "Unknown"
-	switch w.ChainID {
-	case 1:
-		chainName = "Ethereum"
-	case 56:
-		chainName = "BSC"
-	case 560048:
-		chainName = "Hoodi"
-	}
-
 	return &WalletResponse{
 		ID:        w.ID,
 		Address:   w.Address,
 		ChainID:   w.ChainID,
-		ChainName: chainName,
+		ChainName: ChainName(w.ChainID),
 		Balance:   w.Balance,
 		Name:      w.Name,
+		Label:     w.Label,
+		Color:     w.Color,
+		Archived:  w.Archived,
+		IsDefault: w.IsDefault,
+		OrgID:     w.OrgID,
 		CreatedAt: w.CreatedAt,
+
+		ExplorerURL: ExplorerAddressURL(w.ChainID, w.Address),
+
+		FundingStatus: w.FundingStatus,
+
+		WhitelistEnabled: w.WhitelistEnabled,
+		DailyLimitWei:    w.DailyLimitWei,
+		DailyTxLimit:     w.DailyTxLimit,
+
+		ApprovalThresholdWei: w.ApprovalThresholdWei,
+		RequiredApprovals:    w.RequiredApprovals,
+
+		ConfirmationsRequired: w.ConfirmationsRequired,
+
+		PassphraseProtected: w.PassphraseProtected(),
+	}
+}
+
+// WalletListRequest 钱包列表查询请求
+type WalletListRequest struct {
+	Archived string `form:"archived" binding:"omitempty,oneof=true false all"` // 归档状态筛选，默认false（不含已归档钱包）
+}
+
+// ArchivedFilter 归档状态筛选条件（nil表示不筛选）
+func (r *WalletListRequest) ArchivedFilter() *bool {
+	switch r.Archived {
+	case "all":
+		return nil
+	case "true":
+		archived := true
+		return &archived
+	default:
+		archived := false
+		return &archived
 	}
 }
 
+// WalletUpdateRequest 部分更新钱包请求（只修改提供的字段，空字符串表示清除名称、标签或颜色）
+type WalletUpdateRequest struct {
+	Name                  *string `json:"name" binding:"omitempty,max=100"`
+	Label                 *string `json:"label" binding:"omitempty,max=50"`
+	Color                 *string `json:"color" binding:"omitnil,max=7,len=0|hexcolor"` // #RGB或#RRGGBB
+	Archived              *bool   `json:"archived"`
+	ConfirmationsRequired *uint64 `json:"confirmations_required" binding:"omitnil,max=1000"` // 最终确认所需的区块数，0表示恢复为链配置（仅影响之后发送的交易）
+}
+
+// WalletSettingsRequest 钱包安全设置请求
+type WalletSettingsRequest struct {
+	WhitelistEnabled *bool `json:"whitelist_enabled" binding:"required"` // 启用后仅允许向已生效的白名单地址转账
+}
+
+// WalletLimitsRequest 钱包每日限额设置请求（滚动24小时窗口）
+type WalletLimitsRequest struct {
+	DailyLimitWei string `json:"daily_limit_wei" binding:"omitempty,numeric"` // 为空或0表示不限
+	DailyTxLimit  int    `json:"daily_tx_limit" binding:"min=0"`              // 0表示不限
+}
+
 // WalletListResponse 钱包列表响应
 type WalletListResponse struct {
 	Total   int64             `json:"total"`
 	Wallets []*WalletResponse `json:"wallets"`
 }
+
+// BalanceResponse 余额查询响应
+type BalanceResponse struct {
+	Address          string `json:"address"`
+	BalanceWei       string `json:"balance_wei"`                 // 余额（Wei）
+	BalanceEth       string `json:"balance_eth"`                 // 余额（原生币主单位，精确换算，不保留末尾的0）
+	BalanceUSD       string `json:"balance_usd,omitempty"`       // 余额的美元估值
+	PriceUnavailable bool   `json:"price_unavailable,omitempty"` // 价格服务不可用或资产无法定价
+	Stale            bool   `json:"stale,omitempty"`             // 链节点不健康，余额可能已过期
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// WhitelistEntry 钱包转账白名单条目
+type WhitelistEntry struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	WalletID    uint      `gorm:"not null;uniqueIndex:idx_whitelist_wallet_address" json:"wallet_id"`       // 所属钱包ID
+	Address     string    `gorm:"not null;size:42;uniqueIndex:idx_whitelist_wallet_address" json:"address"` // 允许的收款地址
+	Label       string    `gorm:"size:100" json:"label,omitempty"`                                          // 备注
+	ActivatesAt time.Time `gorm:"not null" json:"activates_at"`                                             // 生效时间（冷静期结束）
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (WhitelistEntry) TableName() string {
+	return "wallet_whitelist_entries"
+}
+
+// IsActive 条目是否已过冷静期
+func (e *WhitelistEntry) IsActive(now time.Time) bool {
+	return !now.Before(e.ActivatesAt)
+}
+
+// WhitelistAddRequest 添加白名单地址请求
+type WhitelistAddRequest struct {
+	Address string `json:"address" binding:"required,eth_addr"`
+	Label   string `json:"label" binding:"max=100"`
+}
+
+// WhitelistEntryResponse 白名单条目响应
+type WhitelistEntryResponse struct {
+	ID          uint      `json:"id"`
+	Address     string    `json:"address"`
+	Label       string    `json:"label,omitempty"`
+	ActivatesAt time.Time `json:"activates_at"`
+	Active      bool      `json:"active"` // false表示仍在冷静期内
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (e *WhitelistEntry) ToResponse() *WhitelistEntryResponse {
+	return &WhitelistEntryResponse{
+		ID:          e.ID,
+		Address:     e.Address,
+		Label:       e.Label,
+		ActivatesAt: e.ActivatesAt,
+		Active:      e.IsActive(time.Now()),
+		CreatedAt:   e.CreatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/database"
+)
+
+// ActivityRepository 钱包变更记录数据访问层
+type ActivityRepository struct {
+	db      *gorm.DB
+	replica *database.Replica // 只读副本（为nil时全部查询使用db）
+}
+
+// NewActivityRepository 创建钱包变更记录仓库实例
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// SetReplica 设置只读副本，动态流查询从副本读取
+func (r *ActivityRepository) SetReplica(replica *database.Replica) {
+	r.replica = replica
+}
+
+// Create 写入变更记录
+func (r *ActivityRepository) Create(ctx context.Context, activity *models.WalletActivity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+// ListBefore 按时间倒序查询游标之前的变更记录
+func (r *ActivityRepository) ListBefore(ctx context.Context, walletID uint, cursor *models.ActivityCursor, limit int) ([]*models.WalletActivity, error) {
+	var activities []*models.WalletActivity
+	query := readDB(r.db, r.replica).WithContext(ctx).Where("wallet_id = ?", walletID)
+	err := beforeCursor(query, cursor, models.ActivitySourceChange).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&activities).Error
+	return activities, err
+}
+
+// beforeCursor 追加游标条件：(created_at, source, id) 严格小于游标
+func beforeCursor(query *gorm.DB, cursor *models.ActivityCursor, source int) *gorm.DB {
+	if cursor == nil {
+		return query
+	}
+
+	switch {
+	case source < cursor.Source:
+		return query.Where("created_at <= ?", cursor.CreatedAt)
+	case source > cursor.Source:
+		return query.Where("created_at < ?", cursor.CreatedAt)
+	default:
+		return query.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+}
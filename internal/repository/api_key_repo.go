@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// APIKeyRepository API Key数据访问层
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository 创建API Key仓库实例
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create 创建API Key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByPrefix 根据前缀查询API Key（所属用户已停用时视为不存在）
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).
+		Joins("JOIN users ON users.id = api_keys.user_id AND users.disabled_at IS NULL").
+		Where("api_keys.prefix = ?", prefix).
+		First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("api key")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByUserID 查询用户的所有API Key
+func (r *APIKeyRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// Delete 删除用户的API Key，返回是否存在
+func (r *APIKeyRepository) Delete(ctx context.Context, userID uint, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.APIKey{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateLastUsed 更新最后使用时间
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// BalanceSnapshotRepository 余额快照数据访问层
+type BalanceSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewBalanceSnapshotRepository 创建余额快照仓库实例
+func NewBalanceSnapshotRepository(db *gorm.DB) *BalanceSnapshotRepository {
+	return &BalanceSnapshotRepository{db: db}
+}
+
+// Upsert 写入快照，同一钱包同一时间段已有快照时覆盖余额与估值
+func (r *BalanceSnapshotRepository) Upsert(ctx context.Context, snapshots []*models.BalanceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "resolution"}, {Name: "taken_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"balance", "usd_value", "updated_at"}),
+	}).Create(&snapshots).Error
+}
+
+// ListRange 查询时间范围[from, to)内的快照（按时间升序）
+func (r *BalanceSnapshotRepository) ListRange(ctx context.Context, walletID uint, resolution models.SnapshotResolution, from, to time.Time) ([]*models.BalanceSnapshot, error) {
+	var snapshots []*models.BalanceSnapshot
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ? AND resolution = ? AND taken_at >= ? AND taken_at < ?", walletID, resolution, from, to).
+		Order("taken_at").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// LatestBefore 查询指定时间之前的最后一条快照（任意粒度），不存在时返回nil
+func (r *BalanceSnapshotRepository) LatestBefore(ctx context.Context, walletID uint, before time.Time) (*models.BalanceSnapshot, error) {
+	var snapshots []*models.BalanceSnapshot
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ? AND taken_at < ?", walletID, before).
+		Order("taken_at DESC, resolution DESC").
+		Limit(1).
+		Find(&snapshots).Error
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return snapshots[0], nil
+}
+
+// RollupHourly 将指定时间之前的小时快照降采样为天快照（取每天最后一条）并删除，返回删除的行数
+func (r *BalanceSnapshotRepository) RollupHourly(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 每个钱包每天最后一条小时快照写入天快照
+		err := tx.Exec(`
+			INSERT INTO balance_snapshots (wallet_id, resolution, taken_at, balance, usd_value, created_at, updated_at)
+			SELECT DISTINCT ON (wallet_id, day) wallet_id, ?, day, balance, usd_value, NOW(), NOW()
+			FROM (
+				SELECT wallet_id, taken_at, balance, usd_value,
+					date_trunc('day', taken_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day
+				FROM balance_snapshots
+				WHERE resolution = ? AND taken_at < ?
+			) hourly
+			ORDER BY wallet_id, day, taken_at DESC
+			ON CONFLICT (wallet_id, resolution, taken_at)
+			DO UPDATE SET balance = EXCLUDED.balance, usd_value = EXCLUDED.usd_value, updated_at = EXCLUDED.updated_at`,
+			models.SnapshotDaily, models.SnapshotHourly, before,
+		).Error
+		if err != nil {
+			return err
+		}
+
+		// 2. 删除已降采样的小时快照
+		result := tx.Where("resolution = ? AND taken_at < ?", models.SnapshotHourly, before).Delete(&models.BalanceSnapshot{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// ChainCursorRepository 链上扫描进度数据访问层
+type ChainCursorRepository struct {
+	db *gorm.DB
+}
+
+// NewChainCursorRepository 创建扫描进度仓库实例
+func NewChainCursorRepository(db *gorm.DB) *ChainCursorRepository {
+	return &ChainCursorRepository{db: db}
+}
+
+// Get 查询扫描进度，不存在时返回false
+func (r *ChainCursorRepository) Get(ctx context.Context, chainID int, topic string) (uint64, bool, error) {
+	var cursor models.ChainCursor
+	err := r.db.WithContext(ctx).
+		Where("chain_id = ? AND topic = ?", chainID, topic).
+		First(&cursor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return cursor.BlockNumber, true, nil
+}
+
+// Save 保存扫描进度
+func (r *ChainCursorRepository) Save(ctx context.Context, chainID int, topic string, blockNumber uint64) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chain_id"}, {Name: "topic"}},
+			DoUpdates: clause.AssignmentColumns([]string{"block_number", "updated_at"}),
+		}).
+		Create(&models.ChainCursor{ChainID: chainID, Topic: topic, BlockNumber: blockNumber}).Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// ContactRepository 地址簿数据访问层
+type ContactRepository struct {
+	db *gorm.DB
+}
+
+// NewContactRepository 创建地址簿仓库实例
+func NewContactRepository(db *gorm.DB) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+// Create 创建联系人
+func (r *ContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	return r.db.WithContext(ctx).Create(contact).Error
+}
+
+// GetByID 查询用户的指定联系人
+func (r *ContactRepository) GetByID(ctx context.Context, userID uint, id uint) (*models.Contact, error) {
+	var contact models.Contact
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&contact, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("contact")
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// GetByUserID 查询用户的所有联系人
+func (r *ContactRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.Contact, error) {
+	var contacts []*models.Contact
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+// GetByAddresses 按地址批量查询用户的联系人（地址不区分大小写）
+func (r *ContactRepository) GetByAddresses(ctx context.Context, userID uint, addresses []string) ([]*models.Contact, error) {
+	var contacts []*models.Contact
+	if len(addresses) == 0 {
+		return contacts, nil
+	}
+
+	lowered := make([]string, len(addresses))
+	for i, address := range addresses {
+		lowered[i] = strings.ToLower(address)
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND LOWER(address) IN ?", userID, lowered).
+		Find(&contacts).Error
+	return contacts, err
+}
+
+// ExistsByAddress 检查用户在指定链上是否已保存该地址（excludeID用于更新时排除自身）
+func (r *ContactRepository) ExistsByAddress(ctx context.Context, userID uint, address string, chainID int, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Contact{}).
+		Where("user_id = ? AND LOWER(address) = ? AND chain_id = ? AND id <> ?", userID, strings.ToLower(address), chainID, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update 更新联系人
+func (r *ContactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	return r.db.WithContext(ctx).Save(contact).Error
+}
+
+// Delete 删除用户的联系人，返回是否存在
+func (r *ContactRepository) Delete(ctx context.Context, userID uint, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.Contact{}, id)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// ExportJobRepository 导出任务数据访问层
+type ExportJobRepository struct {
+	db *gorm.DB
+}
+
+// NewExportJobRepository 创建导出任务仓库实例
+func NewExportJobRepository(db *gorm.DB) *ExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+// CreateWithOutbox 在同一事务中写入导出任务与发件箱事件
+func (r *ExportJobRepository) CreateWithOutbox(ctx context.Context, job *models.ExportJob, event *models.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if err := db.Create(job).Error; err != nil {
+			return err
+		}
+		return db.Create(event).Error
+	})
+}
+
+// GetByID 按ID查询导出任务
+func (r *ExportJobRepository) GetByID(ctx context.Context, id string) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("export job")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Claim 将尚未完成的任务（pending、processing或failed，后两者为Worker中断或失败后的重试）标记为processing，
+// 任务已完成或已过期时返回false
+func (r *ExportJobRepository) Claim(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ExportJob{}).
+		Where("id = ? AND status IN ?", id, []models.ExportJobStatus{models.ExportJobPending, models.ExportJobProcessing, models.ExportJobFailed}).
+		Updates(map[string]interface{}{
+			"status":    models.ExportJobProcessing,
+			"error_msg": "",
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Complete 记录生成结果并标记为completed
+func (r *ExportJobRepository) Complete(ctx context.Context, id string, rowCount, fileSize int64, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ExportJob{}).
+		Where("id = ? AND status = ?", id, models.ExportJobProcessing).
+		Updates(map[string]interface{}{
+			"status":       models.ExportJobCompleted,
+			"row_count":    rowCount,
+			"file_size":    fileSize,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// Fail 标记为failed并记录失败原因
+func (r *ExportJobRepository) Fail(ctx context.Context, id string, errMsg string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ExportJob{}).
+		Where("id = ? AND status = ?", id, models.ExportJobProcessing).
+		Updates(map[string]interface{}{
+			"status":    models.ExportJobFailed,
+			"error_msg": errMsg,
+		}).Error
+}
+
+// ListExpired 查询待清理的任务：文件已过期的completed任务，以及创建早于staleBefore仍未完成的任务
+func (r *ExportJobRepository) ListExpired(ctx context.Context, now, staleBefore time.Time, limit int) ([]*models.ExportJob, error) {
+	var jobs []*models.ExportJob
+	err := r.db.WithContext(ctx).
+		Where("(status = ? AND expires_at <= ?) OR (status IN ? AND created_at < ?)",
+			models.ExportJobCompleted, now,
+			[]models.ExportJobStatus{models.ExportJobPending, models.ExportJobProcessing, models.ExportJobFailed}, staleBefore,
+		).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// MarkExpired 标记任务为expired（文件已删除）
+func (r *ExportJobRepository) MarkExpired(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.ExportJob{}).
+		Where("id IN ?", ids).
+		Update("status", models.ExportJobExpired).Error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// FeatureFlagRepository 功能开关审计记录数据访问层（开关状态本身保存在Redis中）
+type FeatureFlagRepository struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagRepository 创建功能开关仓库实例
+func NewFeatureFlagRepository(db *gorm.DB) *FeatureFlagRepository {
+	return &FeatureFlagRepository{db: db}
+}
+
+// CreateChange 写入变更记录
+func (r *FeatureFlagRepository) CreateChange(ctx context.Context, change *models.FeatureFlagChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+// ListChanges 查询最近的变更记录（按时间倒序）
+func (r *FeatureFlagRepository) ListChanges(ctx context.Context, limit int) ([]*models.FeatureFlagChange, error) {
+	var changes []*models.FeatureFlagChange
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
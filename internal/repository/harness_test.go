@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+var seq atomic.Int64
+
+// createUser 写入测试用户
+func createUser(t testing.TB, db *gorm.DB) *models.User {
+	t.Helper()
+	n := seq.Add(1)
+	user := &models.User{
+		Username: fmt.Sprintf("user%d", n),
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Password: "unused",
+	}
+	if err := NewUserRepository(db, testutil.EmailHMACKey).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// createWallet 写入测试钱包（地址按序号生成）
+func createWallet(t testing.TB, db *gorm.DB, userID uint) *models.Wallet {
+	t.Helper()
+	wallet := &models.Wallet{
+		UserID:              userID,
+		Address:             fmt.Sprintf("0x%040x", seq.Add(1)),
+		PrivateKeyEncrypted: "unused",
+		ChainID:             testutil.ChainID,
+		Balance:             "0",
+	}
+	if err := NewWalletRepository(db).Create(context.Background(), wallet); err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	return wallet
+}
+
+// createTransaction 写入钱包的测试交易，fn可调整字段
+func createTransaction(t *testing.T, db *gorm.DB, wallet *models.Wallet, fn func(tx *models.Transaction)) *models.Transaction {
+	t.Helper()
+	tx := &models.Transaction{
+		WalletID:    wallet.ID,
+		TxHash:      fmt.Sprintf("0x%064x", seq.Add(1)),
+		FromAddress: wallet.Address,
+		ToAddress:   fmt.Sprintf("0x%040x", seq.Add(1)),
+		Amount:      "1",
+		Status:      models.TxStatusPending,
+		ChainID:     wallet.ChainID,
+	}
+	if fn != nil {
+		fn(tx)
+	}
+	if err := db.Create(tx).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	return tx
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// labelUpsertBatchSize 批量写入标签的每批条数
+const labelUpsertBatchSize = 500
+
+// LabelRepository 公共地址标签数据访问层（地址统一为EIP-55校验和格式，由Service层在写入与查询前转换）
+type LabelRepository struct {
+	db *gorm.DB
+}
+
+// NewLabelRepository 创建地址标签仓库实例
+func NewLabelRepository(db *gorm.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+// Upsert 批量写入标签，已存在的（地址, 链）更新名称、分类与来源；keepImported为true时不覆盖管理员导入的标签
+func (r *LabelRepository) Upsert(ctx context.Context, labels []*models.AddressLabel, keepImported bool) (int64, error) {
+	if len(labels) == 0 {
+		return 0, nil
+	}
+	conflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}, {Name: "chain_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "category", "source", "updated_at"}),
+	}
+	if keepImported {
+		conflict.Where = clause.Where{Exprs: []clause.Expression{
+			clause.Neq{Column: clause.Column{Table: models.AddressLabel{}.TableName(), Name: "source"}, Value: models.LabelSourceImport},
+		}}
+	}
+	result := r.db.WithContext(ctx).Clauses(conflict).CreateInBatches(labels, labelUpsertBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+// GetByAddresses 按地址批量查询标签（所有链）
+func (r *LabelRepository) GetByAddresses(ctx context.Context, addresses []string) ([]*models.AddressLabel, error) {
+	var labels []*models.AddressLabel
+	if len(addresses) == 0 {
+		return labels, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("address IN ?", addresses).
+		Order("chain_id ASC").
+		Find(&labels).Error
+	return labels, err
+}
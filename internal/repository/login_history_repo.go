@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// LoginHistoryRepository 登录记录数据访问层
+type LoginHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginHistoryRepository 创建登录记录仓库实例
+func NewLoginHistoryRepository(db *gorm.DB) *LoginHistoryRepository {
+	return &LoginHistoryRepository{db: db}
+}
+
+// LoginFamiliarity 登录来源与历史记录的比对结果
+type LoginFamiliarity struct {
+	HasHistory bool // 用户此前有过登录记录
+	SeenIP     bool // 此前从该IP登录过
+	SeenDevice bool // 此前从该设备登录过
+}
+
+// Create 写入登录记录
+func (r *LoginHistoryRepository) Create(ctx context.Context, entry *models.LoginHistory) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// Familiarity 比对IP与设备是否在用户的历史登录中出现过（单次查询）
+func (r *LoginHistoryRepository) Familiarity(ctx context.Context, userID uint, ip, deviceHash string) (*LoginFamiliarity, error) {
+	var result LoginFamiliarity
+	err := r.db.WithContext(ctx).Model(&models.LoginHistory{}).
+		Select("COUNT(*) > 0 AS has_history, COALESCE(BOOL_OR(ip = ?), false) AS seen_ip, COALESCE(BOOL_OR(device_hash = ?), false) AS seen_device", ip, deviceHash).
+		Where("user_id = ?", userID).
+		Scan(&result).Error
+	return &result, err
+}
+
+// ListRecent 按时间倒序查询最近的登录记录
+func (r *LoginHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]*models.LoginHistory, error) {
+	var entries []*models.LoginHistory
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Reissue 将登录记录关联到重新签发的Token（下线其他会话后当前会话继续有效）
+func (r *LoginHistoryRepository) Reissue(ctx context.Context, userID uint, oldJTI, newJTI string, tokenVersion int, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginHistory{}).
+		Where("user_id = ? AND jti = ?", userID, oldJTI).
+		Updates(map[string]interface{}{
+			"jti":           newJTI,
+			"token_version": tokenVersion,
+			"expires_at":    expiresAt,
+		}).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// NotificationRepository 通知与通知偏好数据访问层
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建通知仓库实例
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create 写入站内通知
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// List 按时间倒序分页查询用户的站内通知
+func (r *NotificationRepository) List(ctx context.Context, userID uint, req *models.NotificationListRequest) ([]*models.Notification, int64, error) {
+	var notifications []*models.Notification
+	var total int64
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ?", userID)
+	if req.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead 标记通知为已读（已读通知保留首次阅读时间）
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NotFound("notification")
+	}
+	return nil
+}
+
+// GetPreferences 查询用户已设置的通知偏好
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID uint) ([]*models.NotificationPreference, error) {
+	var prefs []*models.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+// GetPreference 查询用户对指定类型的通知偏好（未设置时返回nil）
+func (r *NotificationRepository) GetPreference(ctx context.Context, userID uint, notificationType models.NotificationType) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, notificationType).
+		First(&pref).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// UpsertPreferences 批量写入通知偏好（同一用户同一类型覆盖原设置）
+func (r *NotificationRepository) UpsertPreferences(ctx context.Context, prefs []*models.NotificationPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "channel", "webhook_url", "updated_at"}),
+	}).Create(&prefs).Error
+}
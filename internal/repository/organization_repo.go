@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// OrganizationRepository 组织数据访问层
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository 创建组织仓库实例
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// Create 创建组织并将创建者设为所有者
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.Organization, ownerID uint) (*models.OrgMembership, error) {
+	membership := &models.OrgMembership{UserID: ownerID, Role: models.OrgRoleOwner}
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if err := db.Create(org).Error; err != nil {
+			return err
+		}
+		membership.OrgID = org.ID
+		return db.Create(membership).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	membership.Organization = *org
+	return membership, nil
+}
+
+// ListByUserID 查询用户加入的所有组织（含用户角色）
+func (r *OrganizationRepository) ListByUserID(ctx context.Context, userID uint) ([]*models.OrgMembership, error) {
+	var memberships []*models.OrgMembership
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("org_id ASC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// Update 更新组织信息
+func (r *OrganizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	return r.db.WithContext(ctx).Model(org).Updates(map[string]interface{}{
+		"name":                  org.Name,
+		"member_send_limit_wei": org.MemberSendLimitWei,
+	}).Error
+}
+
+// Delete 删除组织（成员关系级联删除，仍有组织钱包时拒绝删除）
+func (r *OrganizationRepository) Delete(ctx context.Context, orgID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var count int64
+		if err := db.Model(&models.Wallet{}).Where("org_id = ?", orgID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperr.Invalid("error.organization_has_wallets", "organization still has wallets")
+		}
+		return db.Delete(&models.Organization{}, orgID).Error
+	})
+}
+
+// ListMembers 查询组织成员
+func (r *OrganizationRepository) ListMembers(ctx context.Context, orgID uint) ([]*models.OrgMembership, error) {
+	var memberships []*models.OrgMembership
+	err := r.db.WithContext(ctx).
+		Where("org_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// AddMember 添加组织成员
+func (r *OrganizationRepository) AddMember(ctx context.Context, membership *models.OrgMembership) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var count int64
+		if err := db.Model(&models.User{}).Where("id = ?", membership.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return apperr.NotFound("user")
+		}
+
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(membership)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.Invalid("error.already_member", "user is already a member")
+		}
+		return nil
+	})
+}
+
+// UpdateMemberRole 修改成员角色（组织至少保留一名所有者）
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uint, role models.OrgRole) (*models.OrgMembership, error) {
+	var membership *models.OrgMembership
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var err error
+		if membership, err = lockMembers(db, orgID, userID, role != models.OrgRoleOwner); err != nil {
+			return err
+		}
+		membership.Role = role
+		return db.Model(membership).Update("role", role).Error
+	})
+	return membership, err
+}
+
+// RemoveMember 移除组织成员（组织至少保留一名所有者）
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		membership, err := lockMembers(db, orgID, userID, true)
+		if err != nil {
+			return err
+		}
+		return db.Delete(membership).Error
+	})
+}
+
+// lockMembers 锁定组织的成员记录并返回指定成员，keepOwner时拒绝移除或降级最后一名所有者
+func lockMembers(db *gorm.DB, orgID, userID uint, keepOwner bool) (*models.OrgMembership, error) {
+	var memberships []*models.OrgMembership
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("org_id = ?", orgID).
+		Find(&memberships).Error; err != nil {
+		return nil, err
+	}
+
+	var target *models.OrgMembership
+	owners := 0
+	for _, m := range memberships {
+		if m.Role == models.OrgRoleOwner {
+			owners++
+		}
+		if m.UserID == userID {
+			target = m
+		}
+	}
+	if target == nil {
+		return nil, apperr.NotFound("member")
+	}
+	if keepOwner && target.Role == models.OrgRoleOwner && owners <= 1 {
+		return nil, apperr.Invalid("error.last_owner", "organization must keep at least one owner")
+	}
+	return target, nil
+}
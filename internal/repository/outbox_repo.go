@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// OutboxRepository 发件箱数据访问层
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository 创建发件箱仓库实例
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ProcessPending 锁定一批待投递事件并逐个处理（SKIP LOCKED允许多个分发器并行）
+// handler返回nil时标记为已投递，否则记录失败次数与错误并结束本批次，等待下一轮重试
+func (r *OutboxRepository) ProcessPending(ctx context.Context, limit int, handler func(event *models.OutboxEvent) error) (int, error) {
+	processed := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定待投递事件
+		var events []*models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL").
+			Order("id ASC").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		// 2. 按顺序投递并更新状态（失败时停止本批次，避免队列不可用时长时间持有锁）
+		for _, event := range events {
+			if err := handler(event); err != nil {
+				return tx.Model(&models.OutboxEvent{}).
+					Where("id = ?", event.ID).
+					Updates(map[string]interface{}{
+						"attempts":   gorm.Expr("attempts + 1"),
+						"last_error": err.Error(),
+					}).Error
+			}
+
+			if err := tx.Model(&models.OutboxEvent{}).
+				Where("id = ?", event.ID).
+				Update("sent_at", time.Now()).Error; err != nil {
+				return err
+			}
+			processed++
+		}
+		return nil
+	})
+	return processed, err
+}
+
+// DeleteSentBefore 清理早于指定时间且已投递的事件
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("sent_at IS NOT NULL AND sent_at < ?", before).
+		Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
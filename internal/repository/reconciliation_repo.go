@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// ReconciliationRepository 余额对账数据访问层
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewReconciliationRepository 创建余额对账仓库实例
+func NewReconciliationRepository(db *gorm.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// CreateRun 创建对账记录
+func (r *ReconciliationRepository) CreateRun(ctx context.Context, run *models.ReconciliationRun) error {
+	return r.db.WithContext(ctx).Omit("Reports").Create(run).Error
+}
+
+// FinishRun 保存对账结果（统计数与完成时间）
+func (r *ReconciliationRepository) FinishRun(ctx context.Context, run *models.ReconciliationRun) error {
+	return r.db.WithContext(ctx).Omit("Reports").Save(run).Error
+}
+
+// CreateReports 批量写入差异记录
+func (r *ReconciliationRepository) CreateReports(ctx context.Context, reports []*models.ReconciliationReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&reports).Error
+}
+
+// LatestRun 查询链上最近一次对账，不存在时返回nil
+func (r *ReconciliationRepository) LatestRun(ctx context.Context, chainID int) (*models.ReconciliationRun, error) {
+	var runs []*models.ReconciliationRun
+	err := r.db.WithContext(ctx).
+		Where("chain_id = ?", chainID).
+		Order("started_at DESC").
+		Limit(1).
+		Find(&runs).Error
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return runs[0], nil
+}
+
+// ListRuns 查询最近的对账记录及其差异（按开始时间倒序）
+func (r *ReconciliationRepository) ListRuns(ctx context.Context, limit int) ([]*models.ReconciliationRun, error) {
+	var runs []*models.ReconciliationRun
+	err := r.db.WithContext(ctx).
+		Preload("Reports", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// RecurringPaymentRepository 定期转账数据访问层
+type RecurringPaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewRecurringPaymentRepository 创建定期转账仓库实例
+func NewRecurringPaymentRepository(db *gorm.DB) *RecurringPaymentRepository {
+	return &RecurringPaymentRepository{db: db}
+}
+
+// Create 创建定期转账计划
+func (r *RecurringPaymentRepository) Create(ctx context.Context, payment *models.RecurringPayment) error {
+	return r.db.WithContext(ctx).Create(payment).Error
+}
+
+// GetByID 查询用户的指定定期转账计划
+func (r *RecurringPaymentRepository) GetByID(ctx context.Context, userID uint, id uint) (*models.RecurringPayment, error) {
+	var payment models.RecurringPayment
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&payment, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("recurring payment")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetByUserID 查询用户的所有定期转账计划
+func (r *RecurringPaymentRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.RecurringPayment, error) {
+	var payments []*models.RecurringPayment
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&payments).Error
+	return payments, err
+}
+
+// UpdateSettings 更新用户可修改的字段（不覆盖Worker维护的next_run_at与run_count）
+func (r *RecurringPaymentRepository) UpdateSettings(ctx context.Context, payment *models.RecurringPayment) error {
+	return r.db.WithContext(ctx).
+		Model(payment).
+		Select("amount", "schedule", "end_at", "max_runs", "status", "consecutive_failures").
+		Updates(payment).Error
+}
+
+// GetDue 查询已到执行时间的计划
+func (r *RecurringPaymentRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.RecurringPayment, error) {
+	var payments []*models.RecurringPayment
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ?", models.RecurringStatusActive, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	return payments, err
+}
+
+// Claim 认领一次执行：仅当next_run_at未被其他Worker推进时更新为下次执行时间，返回是否认领成功
+func (r *RecurringPaymentRepository) Claim(ctx context.Context, payment *models.RecurringPayment, nextRunAt time.Time, status models.RecurringPaymentStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RecurringPayment{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", payment.ID, models.RecurringStatusActive, payment.NextRunAt).
+		Updates(map[string]interface{}{
+			"next_run_at": nextRunAt,
+			"run_count":   gorm.Expr("run_count + 1"),
+			"last_run_at": gorm.Expr("NOW()"),
+			"status":      status,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordSuccess 记录执行成功（清零连续失败次数）
+func (r *RecurringPaymentRepository) RecordSuccess(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&models.RecurringPayment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"consecutive_failures": 0,
+			"last_error":           "",
+		}).Error
+}
+
+// RecordFailure 记录执行失败，连续失败达到maxFailures时暂停计划，返回是否因本次失败而暂停
+func (r *RecurringPaymentRepository) RecordFailure(ctx context.Context, id uint, errMsg string, maxFailures int) (bool, error) {
+	var payment models.RecurringPayment
+	err := r.db.WithContext(ctx).
+		Model(&payment).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+			"last_error":           errMsg,
+			"status": gorm.Expr("CASE WHEN status = ? AND consecutive_failures + 1 >= ? THEN ? ELSE status END",
+				models.RecurringStatusActive, maxFailures, models.RecurringStatusPaused),
+		}).Error
+	if err != nil {
+		return false, err
+	}
+	return payment.Status == models.RecurringStatusPaused && payment.ConsecutiveFailures == maxFailures, nil
+}
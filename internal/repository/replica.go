@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/pkg/database"
+)
+
+// readDB 可容忍复制延迟的只读查询（列表、统计、导出）使用的连接：设置了只读副本时由副本按复制延迟选择副本或主库，
+// 未设置副本（包括事务中创建的仓库）时使用db；写入与写后立即读取（权限校验、状态推进）始终使用db
+func readDB(db *gorm.DB, replica *database.Replica) *gorm.DB {
+	if replica == nil {
+		return db
+	}
+	return replica.DB()
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// SpendLedgerRepository 转出额度台账数据访问层
+type SpendLedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewSpendLedgerRepository 创建额度台账仓库实例
+func NewSpendLedgerRepository(db *gorm.DB) *SpendLedgerRepository {
+	return &SpendLedgerRepository{db: db}
+}
+
+// Reserve 在钱包行锁内统计窗口用量，check通过后写入台账（并发发送按钱包串行化）
+func (r *SpendLedgerRepository) Reserve(
+	ctx context.Context,
+	entry *models.SpendLedgerEntry,
+	since time.Time,
+	check func(usage *models.SpendUsage) error,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定钱包行
+		var wallet models.Wallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&wallet, entry.WalletID).Error; err != nil {
+			return err
+		}
+
+		// 2. 统计窗口内用量
+		var row struct {
+			Spent string
+			Count int64
+		}
+		if err := tx.Model(&models.SpendLedgerEntry{}).
+			Select("COALESCE(SUM(amount), 0)::text AS spent, COUNT(*) AS count").
+			Where("wallet_id = ? AND created_at > ?", entry.WalletID, since).
+			Scan(&row).Error; err != nil {
+			return err
+		}
+		usage := &models.SpendUsage{SpentWei: row.Spent, TxCount: row.Count}
+
+		// 窗口内最早一笔（用于计算重置时间）
+		if row.Count > 0 {
+			var oldest models.SpendLedgerEntry
+			if err := tx.Select("created_at").
+				Where("wallet_id = ? AND created_at > ?", entry.WalletID, since).
+				Order("created_at ASC").
+				Take(&oldest).Error; err != nil {
+				return err
+			}
+			usage.OldestAt = &oldest.CreatedAt
+		}
+
+		// 3. 校验限额
+		if err := check(usage); err != nil {
+			return err
+		}
+
+		// 4. 写入台账
+		return tx.Create(entry).Error
+	})
+}
+
+// Release 释放额度（交易发送失败时调用）
+func (r *SpendLedgerRepository) Release(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.SpendLedgerEntry{}, id).Error
+}
+
+// AttachTxHash 回填台账关联的交易哈希
+func (r *SpendLedgerRepository) AttachTxHash(ctx context.Context, id uint, txHash string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.SpendLedgerEntry{}).
+		Where("id = ?", id).
+		UpdateColumn("tx_hash", txHash).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// TokenRepository 代币元数据与关注列表数据访问层
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository 创建代币仓库实例
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+// GetByAddress 按链ID与合约地址查询代币
+func (r *TokenRepository) GetByAddress(ctx context.Context, chainID int, contractAddress string) (*models.Token, error) {
+	var token models.Token
+	err := r.db.WithContext(ctx).
+		Where("chain_id = ? AND contract_address = ?", chainID, contractAddress).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("token")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CreateIfNotExists 写入代币元数据（并发写入同一代币时保留先写入的记录）
+func (r *TokenRepository) CreateIfNotExists(ctx context.Context, token *models.Token) (*models.Token, error) {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(token).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByAddress(ctx, token.ChainID, token.ContractAddress)
+}
+
+// AddWatch 添加关注（已关注时忽略）
+func (r *TokenRepository) AddWatch(ctx context.Context, userID uint, tokenID uint) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TokenWatch{UserID: userID, TokenID: tokenID}).Error
+}
+
+// RemoveWatch 取消关注
+func (r *TokenRepository) RemoveWatch(ctx context.Context, userID uint, tokenID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND token_id = ?", userID, tokenID).
+		Delete(&models.TokenWatch{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("token not watched")
+	}
+	return nil
+}
+
+// ListWatched 查询用户关注的代币（chainID为0时返回所有链）
+func (r *TokenRepository) ListWatched(ctx context.Context, userID uint, chainID int) ([]*models.Token, error) {
+	var tokens []*models.Token
+	query := r.db.WithContext(ctx).
+		Joins("JOIN token_watches ON token_watches.token_id = tokens.id").
+		Where("token_watches.user_id = ?", userID)
+	if chainID > 0 {
+		query = query.Where("tokens.chain_id = ?", chainID)
+	}
+	err := query.Order("tokens.chain_id ASC, tokens.symbol ASC").Find(&tokens).Error
+	return tokens, err
+}
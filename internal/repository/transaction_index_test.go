@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// seedTransactions 为每个钱包批量写入perWallet笔交易（创建时间递增，约十分之一为pending）
+func seedTransactions(tb testing.TB, db *gorm.DB, wallets []*models.Wallet, perWallet int) {
+	tb.Helper()
+	start := time.Now().Add(-time.Duration(perWallet) * time.Minute)
+	rows := make([]*models.Transaction, 0, len(wallets)*perWallet)
+	for _, wallet := range wallets {
+		for i := range perWallet {
+			status := models.TxStatusSuccess
+			if i%10 == 0 {
+				status = models.TxStatusPending
+			}
+			rows = append(rows, &models.Transaction{
+				WalletID:    wallet.ID,
+				TxHash:      fmt.Sprintf("0x%064x", seq.Add(1)),
+				FromAddress: wallet.Address,
+				ToAddress:   fmt.Sprintf("0x%040x", seq.Add(1)),
+				Amount:      "1",
+				Status:      status,
+				ChainID:     wallet.ChainID,
+				CreatedAt:   start.Add(time.Duration(i) * time.Minute),
+			})
+		}
+	}
+	if err := db.CreateInBatches(rows, 500).Error; err != nil {
+		tb.Fatalf("seed transactions: %v", err)
+	}
+	if err := db.Exec("ANALYZE").Error; err != nil {
+		tb.Fatalf("analyze: %v", err)
+	}
+}
+
+// queryPlan 返回SQLite对query（DryRun会话构建的查询）的EXPLAIN QUERY PLAN输出，每个步骤一行
+func queryPlan(t *testing.T, db *gorm.DB, query *gorm.DB) string {
+	t.Helper()
+	sql := query.Statement.SQL.String()
+	rows, err := db.Raw("EXPLAIN QUERY PLAN "+sql, query.Statement.Vars...).Rows()
+	if err != nil {
+		t.Fatalf("explain %s: %v", sql, err)
+	}
+	defer rows.Close()
+
+	var steps []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		steps = append(steps, detail)
+	}
+	return strings.Join(steps, "\n")
+}
+
+func TestTransactionQueriesUseIndexes(t *testing.T) {
+	db := testutil.NewDB(t)
+	user := createUser(t, db)
+	wallets := []*models.Wallet{createWallet(t, db, user.ID), createWallet(t, db, user.ID), createWallet(t, db, createUser(t, db).ID)}
+	seedTransactions(t, db, wallets, 100)
+
+	dry := db.Session(&gorm.Session{DryRun: true})
+	var rows []models.Transaction
+	tests := []struct {
+		name  string
+		query *gorm.DB
+		index string
+	}{
+		// 钱包交易历史：按钱包与创建时间倒序分页，不需要额外排序
+		{"wallet history", dry.Where("wallet_id = ?", wallets[0].ID).Order("created_at DESC").Limit(20).Find(&rows), "idx_transactions_wallet_created"},
+		{"pending scan", dry.Where("status = ?", models.TxStatusPending).Order("created_at").Limit(100).Find(&rows), "idx_transactions_status_created"},
+		{"incoming lookup", dry.Where("LOWER(to_address) = ?", strings.ToLower(wallets[0].Address)).Find(&rows), "idx_transactions_to_address_lower"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := queryPlan(t, db, tt.query)
+			if !strings.Contains(plan, "USING INDEX "+tt.index) || strings.Contains(plan, "TEMP B-TREE") {
+				t.Errorf("plan does not use %s without sorting:\n%s", tt.index, plan)
+			}
+		})
+	}
+}
+
+// BenchmarkListTransactions 10个钱包共1万笔交易时查询单个钱包的第一页
+func BenchmarkListTransactions(b *testing.B) {
+	ctx := context.Background()
+	db := testutil.NewDB(b)
+	repo := NewTransactionRepository(db)
+	user := createUser(b, db)
+	wallets := make([]*models.Wallet, 10)
+	for i := range wallets {
+		wallets[i] = createWallet(b, db, user.ID)
+	}
+	seedTransactions(b, db, wallets, 1000)
+
+	for b.Loop() {
+		if _, _, err := repo.GetByWalletID(ctx, wallets[0].ID, 1, 20); err != nil {
+			b.Fatalf("list: %v", err)
+		}
+	}
+}
+
+// postgresRows PostgreSQL索引测试写入的交易数量
+const postgresRows = 1_000_000
+
+// TestTransactionIndexesPostgres 在PostgreSQL中写入100万笔交易，校验列表查询走复合索引而不是顺序扫描
+//
+// 需要设置CWA_TEST_POSTGRES_DSN，未设置时跳过
+func TestTransactionIndexesPostgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("seeds 1M rows")
+	}
+	db := testutil.NewPostgres(t)
+	user := createUser(t, db)
+	wallets := make([]*models.Wallet, 100)
+	for i := range wallets {
+		wallets[i] = createWallet(t, db, user.ID)
+	}
+
+	// 按钱包轮流分配交易，创建时间递减，1%为pending
+	err := db.Exec(`
+		WITH w AS (SELECT id, address, chain_id, ROW_NUMBER() OVER (ORDER BY id) - 1 AS n FROM wallets)
+		INSERT INTO transactions (wallet_id, tx_hash, from_address, to_address, amount, status, chain_id, created_at)
+		SELECT w.id, '0x' || LPAD(TO_HEX(g), 64, '0'), w.address, '0x' || LPAD(TO_HEX(g), 40, '0'), 1,
+			CASE WHEN g % 100 = 0 THEN 'pending' ELSE 'success' END, w.chain_id, NOW() - g * INTERVAL '1 second'
+		FROM generate_series(1, ?) AS g JOIN w ON w.n = g % ?`, postgresRows, len(wallets)).Error
+	if err != nil {
+		t.Fatalf("seed transactions: %v", err)
+	}
+	if err := db.Exec("ANALYZE transactions").Error; err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+
+	var rows []models.Transaction
+	tests := []struct {
+		name    string
+		query   func(tx *gorm.DB) *gorm.DB
+		index   string
+		ordered bool // 索引已按ORDER BY排序，不需要额外排序
+	}{
+		{"wallet history", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("wallet_id = ?", wallets[0].ID).Order("created_at DESC").Limit(20).Find(&rows)
+		}, "idx_transactions_wallet_created", true},
+		{"wallet status filter", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("wallet_id = ? AND status = ?", wallets[0].ID, models.TxStatusPending).Find(&rows)
+		}, "idx_transactions_wallet_status", false},
+		{"pending scan", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("status = ?", models.TxStatusPending).Order("created_at").Limit(100).Find(&rows)
+		}, "idx_transactions_status_created", true},
+		{"incoming lookup", func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("LOWER(to_address) = ?", "0x"+strings.Repeat("0", 38)+"2a").Find(&rows)
+		}, "idx_transactions_to_address_lower", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := postgresPlan(t, db, db.ToSQL(tt.query))
+			if !strings.Contains(plan, tt.index) || strings.Contains(plan, "Seq Scan") {
+				t.Errorf("plan does not use %s:\n%s", tt.index, plan)
+			}
+			if tt.ordered && strings.Contains(plan, "Sort Key") {
+				t.Errorf("plan sorts instead of reading %s in order:\n%s", tt.index, plan)
+			}
+		})
+	}
+}
+
+// postgresPlan 返回PostgreSQL对sql的EXPLAIN输出（不含代价估算），每个节点一行
+func postgresPlan(t *testing.T, db *gorm.DB, sql string) string {
+	t.Helper()
+	rows, err := db.Raw("EXPLAIN (COSTS OFF) " + sql).Rows()
+	if err != nil {
+		t.Fatalf("explain %s: %v", sql, err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,15 +3,30 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/database"
+)
+
+var (
+	// ErrInsufficientLedgerBalance 内部转账时发送方账本余额不足（扣款条件未命中任何行）
+	ErrInsufficientLedgerBalance = errors.New("insufficient ledger balance")
+	// ErrNotAwaitingApproval 执行审批时交易已不处于等待审批状态（已被其他请求执行、拒绝或过期）
+	ErrNotAwaitingApproval = errors.New("transaction is not awaiting approval")
 )
 
 // TransactionRepository 交易数据访问层
 type TransactionRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	replica *database.Replica // 只读副本（为nil时全部查询使用db）
 }
 
 // NewTransactionRepository 创建交易仓库实例
@@ -19,6 +34,16 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// SetReplica 设置只读副本，交易列表、展示用的单笔查询、统计、导出与动态流从副本读取
+func (r *TransactionRepository) SetReplica(replica *database.Replica) {
+	r.replica = replica
+}
+
+// reader 只读查询使用的连接
+func (r *TransactionRepository) reader() *gorm.DB {
+	return readDB(r.db, r.replica)
+}
+
 // Create 创建交易记录
 func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
 	return r.db.WithContext(ctx).Create(tx).Error
@@ -30,20 +55,36 @@ func (r *TransactionRepository) GetByID(ctx context.Context, id uint) (*models.T
 	err := r.db.WithContext(ctx).First(&tx, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("transaction not found")
+			return nil, apperr.NotFound("transaction")
 		}
 		return nil, err
 	}
 	return &tx, nil
 }
 
-// GetByTxHash 根据交易哈希查询
+// GetByTxHash 根据交易哈希查询（同一哈希存在代币入账记录时优先返回交易本身）
 func (r *TransactionRepository) GetByTxHash(ctx context.Context, txHash string) (*models.Transaction, error) {
 	var tx models.Transaction
-	err := r.db.WithContext(ctx).Where("tx_hash = ?", txHash).First(&tx).Error
+	err := r.db.WithContext(ctx).Where("tx_hash = ?", txHash).Order("log_index NULLS FIRST").First(&tx).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("transaction")
+		}
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetByTxHashForDisplay 展示用的按哈希查询：从只读副本读取，副本尚未同步（刚发送的交易）时再查询主库
+func (r *TransactionRepository) GetByTxHashForDisplay(ctx context.Context, txHash string) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.reader().WithContext(ctx).Where("tx_hash = ?", txHash).Order("log_index NULLS FIRST").First(&tx).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && r.replica != nil {
+		return r.GetByTxHash(ctx, txHash)
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("transaction not found")
+			return nil, apperr.NotFound("transaction")
 		}
 		return nil, err
 	}
@@ -73,26 +114,67 @@ func (r *TransactionRepository) GetByWalletID(ctx context.Context, walletID uint
 }
 
 // List 查询交易列表（支持多条件筛选）
-func (r *TransactionRepository) List(ctx context.Context, req *models.TransactionListRequest) ([]*models.Transaction, int64, error) {
+func (r *TransactionRepository) List(ctx context.Context, walletIDs []uint, req *models.TransactionListRequest) ([]*models.Transaction, int64, error) {
 	var transactions []*models.Transaction
 	var total int64
 
+	// 设置默认分页参数（服务层已处理，这里兜底其他调用方）
+	if req.Page <= 0 {
+		req.Page = models.DefaultPage
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = models.DefaultPageSize
+	}
+
+	// 未指定任何钱包时不查询（避免返回全表数据）
+	if len(walletIDs) == 0 {
+		return []*models.Transaction{}, 0, nil
+	}
+
 	// 构建查询条件
-	query := r.db.WithContext(ctx).Model(&models.Transaction{})
-
-	// 按钱包地址筛选
-	if req.WalletAddress != "" {
-		// 需要先查询钱包ID
-		var wallet models.Wallet
-		if err := r.db.WithContext(ctx).Where("address = ?", req.WalletAddress).First(&wallet).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return []*models.Transaction{}, 0, nil
-			}
-			return nil, 0, err
-		}
-		query = query.Where("wallet_id = ?", wallet.ID)
+	query := r.listQuery(ctx, walletIDs, req)
+
+	// 计算总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
+	// 分页查询
+	offset := (req.Page - 1) * req.PageSize
+	err := query.
+		Preload("Tags", orderTags).
+		Order("created_at DESC").
+		Limit(req.PageSize).
+		Offset(offset).
+		Find(&transactions).Error
+
+	return transactions, total, err
+}
+
+// ListVersion 查询与List相同筛选条件下的交易数量与最近更新时间（用于交易列表的ETag，不加载交易记录）
+func (r *TransactionRepository) ListVersion(ctx context.Context, walletIDs []uint, req *models.TransactionListRequest) (int64, time.Time, error) {
+	if len(walletIDs) == 0 {
+		return 0, time.Time{}, nil
+	}
+
+	var count int64
+	if err := r.listQuery(ctx, walletIDs, req).Count(&count).Error; err != nil || count == 0 {
+		return count, time.Time{}, err
+	}
+
+	// 读取最近更新的一行的updated_at列（按wallet_id+updated_at索引），不用MAX聚合：聚合结果没有列类型，SQLite驱动按字符串返回
+	var latest models.Transaction
+	err := r.listQuery(ctx, walletIDs, req).
+		Select("updated_at").
+		Order("updated_at DESC").
+		Take(&latest).Error
+	return count, latest.UpdatedAt, err
+}
+
+// listQuery 交易列表的筛选条件（钱包、状态、链与标签）
+func (r *TransactionRepository) listQuery(ctx context.Context, walletIDs []uint, req *models.TransactionListRequest) *gorm.DB {
+	query := r.reader().WithContext(ctx).Model(&models.Transaction{}).Where("wallet_id IN ?", walletIDs)
+
 	// 按状态筛选
 	if req.Status != "" {
 		query = query.Where("status = ?", req.Status)
@@ -103,28 +185,391 @@ func (r *TransactionRepository) List(ctx context.Context, req *models.Transactio
 		query = query.Where("chain_id = ?", req.ChainID)
 	}
 
-	// 计算总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	// 按标签筛选
+	if req.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM transaction_tags WHERE transaction_tags.transaction_id = transactions.id AND transaction_tags.tag = ?)", strings.ToLower(req.Tag))
 	}
 
-	// 设置默认分页参数
-	if req.Page <= 0 {
-		req.Page = 1
+	// 按创建时间筛选
+	if !req.From.IsZero() {
+		query = query.Where("created_at >= ?", req.From)
 	}
-	if req.PageSize <= 0 {
-		req.PageSize = 20
+	if !req.To.IsZero() {
+		query = query.Where("created_at < ?", req.To)
 	}
 
-	// 分页查询
-	offset := (req.Page - 1) * req.PageSize
-	err := query.
-		Order("created_at DESC").
-		Limit(req.PageSize).
-		Offset(offset).
+	// 按方向、金额与对手方筛选
+	db := r.reader()
+	return filterTransactions(query, &req.TransactionFilter, func(column string) *gorm.DB {
+		return db.Model(&models.Wallet{}).Select(column).Where("id IN ?", walletIDs)
+	})
+}
+
+// filterTransactions 交易列表、导出与统计共用的方向、金额与对手方筛选
+//
+// wallets返回筛选范围内钱包指定列的子查询，用于判断方向：转出为范围内钱包发送的交易，转入为范围内钱包的代币入账
+// 以及收款地址为范围内钱包的交易（范围内钱包之间的互转同时属于两个方向）。金额按decimal数值比较。
+func filterTransactions(query *gorm.DB, filter *models.TransactionFilter, wallets func(column string) *gorm.DB) *gorm.DB {
+	switch filter.Direction {
+	case models.DirectionOutgoing:
+		query = query.Where("log_index IS NULL AND wallet_id IN (?)", wallets("id"))
+	case models.DirectionIncoming:
+		query = query.Where("(log_index IS NOT NULL AND wallet_id IN (?)) OR LOWER(to_address) IN (?)", wallets("id"), wallets("LOWER(address)"))
+	}
+	if filter.MinAmount != "" {
+		query = query.Where("amount >= CAST(? AS numeric)", filter.MinAmount)
+	}
+	if filter.MaxAmount != "" {
+		query = query.Where("amount <= CAST(? AS numeric)", filter.MaxAmount)
+	}
+	if filter.Counterparty != "" {
+		counterparty := strings.ToLower(filter.Counterparty)
+		query = query.Where("LOWER(from_address) = ? OR LOWER(to_address) = ?", counterparty, counterparty)
+	}
+	return query
+}
+
+// userWallets 返回用户可访问钱包（walletID大于0时只含该钱包）指定列的子查询构造函数
+func userWallets(db *gorm.DB, userID, walletID uint) func(column string) *gorm.DB {
+	return func(column string) *gorm.DB {
+		wallets := db.Model(&models.Wallet{}).Select(column).Scopes(accessibleWallets(db, userID))
+		if walletID > 0 {
+			wallets = wallets.Where("id = ?", walletID)
+		}
+		return wallets
+	}
+}
+
+// orderTags 标签按名称排序
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tag")
+}
+
+// GetTags 查询交易的标签
+func (r *TransactionRepository) GetTags(ctx context.Context, transactionID uint) ([]models.TransactionTag, error) {
+	var tags []models.TransactionTag
+	err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).Order("tag").Find(&tags).Error
+	return tags, err
+}
+
+// UpdateMeta 更新交易备注与标签（note为nil时不修改备注，tags为nil时不修改标签，否则整体替换）
+func (r *TransactionRepository) UpdateMeta(ctx context.Context, transactionID uint, note *string, tags []string) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if note != nil {
+			if err := db.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("note", *note).Error; err != nil {
+				return err
+			}
+		}
+		if tags == nil {
+			return nil
+		}
+		// 标签存于独立表，更新交易的updated_at使列表ETag失效
+		if err := db.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("updated_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := db.Where("transaction_id = ?", transactionID).Delete(&models.TransactionTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		rows := make([]models.TransactionTag, len(tags))
+		for i, tag := range tags {
+			rows[i] = models.TransactionTag{TransactionID: transactionID, Tag: tag}
+		}
+		return db.Create(&rows).Error
+	})
+}
+
+// WithTx 工作单元：在同一数据库事务中执行fn，fn通过传入的Repository执行的写入一起提交，fn返回错误时全部回滚
+func (r *TransactionRepository) WithTx(ctx context.Context, fn func(txRepo *TransactionRepository, walletRepo *WalletRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		return fn(NewTransactionRepository(db), NewWalletRepository(db))
+	})
+}
+
+// CreateWithOutbox 在同一事务中写入交易记录（含标签）、发件箱事件，并将每日限额占用关联到交易哈希（spend为nil表示未占用）
+//
+// 关联在广播前写入，交易被标记为dropped时按哈希释放额度
+func (r *TransactionRepository) CreateWithOutbox(ctx context.Context, tx *models.Transaction, event *models.OutboxEvent, spend *models.SpendLedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if err := db.Create(tx).Error; err != nil {
+			return err
+		}
+		if err := attachSpendEntry(db, spend, tx.TxHash); err != nil {
+			return err
+		}
+		return db.Create(event).Error
+	})
+}
+
+// attachSpendEntry 在事务中回填每日限额占用关联的交易哈希
+func attachSpendEntry(db *gorm.DB, spend *models.SpendLedgerEntry, txHash string) error {
+	if spend == nil {
+		return nil
+	}
+	return db.Model(&models.SpendLedgerEntry{}).
+		Where("id = ?", spend.ID).
+		UpdateColumn("tx_hash", txHash).Error
+}
+
+// CreateInternalTransfer 在同一事务中从发送方账本余额扣除金额、计入收款方并保存内部转账记录（发送方账本余额不足时不做任何修改）
+func (r *TransactionRepository) CreateInternalTransfer(ctx context.Context, tx *models.Transaction, toWalletID uint, amountWei string) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		// 1. 按ID顺序锁定双方钱包，避免两个钱包同时互相转账时死锁
+		var locked []models.Wallet
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id IN ?", []uint{tx.WalletID, toWalletID}).
+			Order("id ASC").
+			Find(&locked).Error; err != nil {
+			return err
+		}
+
+		// 2. 扣除发送方账本余额（numeric精确计算）
+		result := db.Model(&models.Wallet{}).
+			Where("id = ? AND balance >= ?::numeric", tx.WalletID, amountWei).
+			Updates(map[string]interface{}{
+				"balance":          gorm.Expr("balance - ?::numeric", amountWei),
+				"internal_net_wei": gorm.Expr("internal_net_wei - ?::numeric", amountWei),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientLedgerBalance
+		}
+
+		// 3. 计入收款方账本余额
+		if err := db.Model(&models.Wallet{}).
+			Where("id = ?", toWalletID).
+			Updates(map[string]interface{}{
+				"balance":          gorm.Expr("balance + ?::numeric", amountWei),
+				"internal_net_wei": gorm.Expr("internal_net_wei + ?::numeric", amountWei),
+			}).Error; err != nil {
+			return err
+		}
+
+		// 4. 保存转账记录
+		return db.Create(tx).Error
+	})
+}
+
+// GetForApproval 根据ID查询交易及其审批记录
+func (r *TransactionRepository) GetForApproval(ctx context.Context, id uint) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.db.WithContext(ctx).Preload("Approvals").First(&tx, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("transaction")
+		}
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// AddApproval 记录审批决定（同一审批人重复提交时保留首次决定）
+func (r *TransactionRepository) AddApproval(ctx context.Context, approval *models.TransactionApproval) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(approval).Error
+}
+
+// ExecuteApproval 审批通过后在同一事务中写入签名交易信息、发件箱事件与每日限额占用的交易哈希，仅当交易仍处于等待审批状态时生效
+func (r *TransactionRepository) ExecuteApproval(ctx context.Context, tx *models.Transaction, event *models.OutboxEvent, spend *models.SpendLedgerEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&models.Transaction{}).
+			Where("id = ? AND status = ?", tx.ID, models.TxStatusAwaitingApproval).
+			Updates(map[string]interface{}{
+				"tx_hash":   tx.TxHash,
+				"gas_price": tx.GasPrice,
+				"gas_limit": tx.GasLimit,
+				"nonce":     tx.Nonce,
+				"status":    tx.Status,
+
+				"confirmations_required": tx.ConfirmationsRequired,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotAwaitingApproval
+		}
+		if err := attachSpendEntry(db, spend, tx.TxHash); err != nil {
+			return err
+		}
+		return db.Create(event).Error
+	})
+}
+
+// ResolveApproval 将等待审批的交易标记为拒绝或过期，返回是否由本次调用完成更新
+func (r *TransactionRepository) ResolveApproval(ctx context.Context, id uint, status models.TransactionStatus, errMsg string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", id, models.TxStatusAwaitingApproval).
+		Updates(map[string]interface{}{
+			"status":    status,
+			"error_msg": errMsg,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListAwaitingApproval 查询用户作为审批人的待审批交易
+func (r *TransactionRepository) ListAwaitingApproval(ctx context.Context, userID uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.WithContext(ctx).
+		Preload("Approvals").
+		Where("status = ?", models.TxStatusAwaitingApproval).
+		Where("wallet_id IN (?)", r.db.Model(&models.WalletApprover{}).Select("wallet_id").Where("user_id = ?", userID)).
+		Order("created_at ASC").
 		Find(&transactions).Error
+	return transactions, err
+}
 
-	return transactions, total, err
+// GetExpiredApprovals 查询审批已过期但仍处于等待审批状态的交易
+func (r *TransactionRepository) GetExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND approval_expires_at <= ?", models.TxStatusAwaitingApproval, now).
+		Order("approval_expires_at ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// ConfirmIfPending 仅当交易尚未最终确认（pending或confirming）时更新为最终状态并写入回执中的gas用量与实际单价（effectiveGasPrice为nil时不更新），
+// errMsg非空时记录为失败原因，返回是否由本次调用完成更新（用于幂等处理重复消息）
+func (r *TransactionRepository) ConfirmIfPending(ctx context.Context, txHash string, status models.TransactionStatus, blockNumber int64, confirmations uint64, gasUsed int64, effectiveGasPrice *big.Int, errMsg string) (bool, error) {
+	updates := map[string]interface{}{
+		"status":        status,
+		"block_number":  blockNumber,
+		"confirmations": confirmations,
+		"gas_used":      gasUsed,
+		"confirmed_at":  gorm.Expr("NOW()"),
+	}
+	if effectiveGasPrice != nil {
+		updates["effective_gas_price"] = effectiveGasPrice.String()
+	}
+	if errMsg != "" {
+		updates["error_msg"] = errMsg
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status IN ?", txHash, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkIncluded 交易首次被打包时将pending交易更新为confirming，返回是否由本次调用完成更新（用于只推送一次打包事件）
+func (r *TransactionRepository) MarkIncluded(ctx context.Context, txHash string, blockNumber int64, confirmations uint64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status = ?", txHash, models.TxStatusPending).
+		Updates(map[string]interface{}{
+			"status":        models.TxStatusConfirming,
+			"block_number":  blockNumber,
+			"confirmations": confirmations,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkConfirming 记录交易已打包但尚未达到确认深度（区块号可能因链重组变化）
+func (r *TransactionRepository) MarkConfirming(ctx context.Context, txHash string, blockNumber int64, confirmations uint64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status IN ?", txHash, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		Updates(map[string]interface{}{
+			"status":        models.TxStatusConfirming,
+			"block_number":  blockNumber,
+			"confirmations": confirmations,
+		}).Error
+}
+
+// RevertToPending 回执消失（链重组）时将confirming交易恢复为pending，返回是否有记录被恢复
+func (r *TransactionRepository) RevertToPending(ctx context.Context, txHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status = ?", txHash, models.TxStatusConfirming).
+		Updates(map[string]interface{}{
+			"status":        models.TxStatusPending,
+			"block_number":  0,
+			"confirmations": 0,
+			"next_check_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RequeueIfPending 仅当交易仍在等待确认（pending或confirming）时写入发件箱事件，返回是否已写入
+func (r *TransactionRepository) RequeueIfPending(ctx context.Context, txHash string, event *models.OutboxEvent) (bool, error) {
+	requeued := false
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var tx models.Transaction
+		err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tx_hash = ? AND log_index IS NULL AND status IN ?", txHash, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+			First(&tx).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		requeued = true
+		return db.Create(event).Error
+	})
+	return requeued, err
+}
+
+// OverrideStatus 仅当交易尚未最终确认（pending或confirming）时手动设置为最终状态并记录原因，返回是否由本次调用完成更新
+func (r *TransactionRepository) OverrideStatus(ctx context.Context, txHash string, status models.TransactionStatus, reason string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status IN ?", txHash, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"error_msg":    reason,
+			"confirmed_at": gorm.Expr("NOW()"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkDropped 仅当交易仍为pending时标记为dropped并记录原因，同时释放该交易占用的每日限额，返回是否由本次调用完成更新
+func (r *TransactionRepository) MarkDropped(ctx context.Context, txHash string, reason string) (bool, error) {
+	dropped := false
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&models.Transaction{}).
+			Where("tx_hash = ? AND log_index IS NULL AND status = ?", txHash, models.TxStatusPending).
+			Updates(map[string]interface{}{
+				"status":    models.TxStatusDropped,
+				"error_msg": reason,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		dropped = true
+		return db.Where("tx_hash = ?", txHash).Delete(&models.SpendLedgerEntry{}).Error
+	})
+	return dropped, err
+}
+
+// MarkStuckNotified 记录已推送卡住提醒（不更新updated_at），返回是否由本次调用完成记录（并发检查时只有一方推送）
+func (r *TransactionRepository) MarkStuckNotified(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ? AND status = ? AND stuck_notified_at IS NULL", id, models.TxStatusPending).
+		UpdateColumn("stuck_notified_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkBroadcastFailed 标记广播失败的交易
+func (r *TransactionRepository) MarkBroadcastFailed(ctx context.Context, txHash string, errMsg string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status = ?", txHash, models.TxStatusPending).
+		Updates(map[string]interface{}{
+			"status":    models.TxStatusFailed,
+			"error_msg": errMsg,
+		}).Error
 }
 
 // UpdateStatus 更新交易状态
@@ -141,7 +586,7 @@ func (r *TransactionRepository) UpdateStatus(ctx context.Context, txHash string,
 
 	return r.db.WithContext(ctx).
 		Model(&models.Transaction{}).
-		Where("tx_hash = ?", txHash).
+		Where("tx_hash = ? AND log_index IS NULL", txHash).
 		Updates(updates).Error
 }
 
@@ -150,19 +595,249 @@ func (r *TransactionRepository) Update(ctx context.Context, tx *models.Transacti
 	return r.db.WithContext(ctx).Save(tx).Error
 }
 
-// GetPendingTransactions 查询所有待确认的交易
+// GetPendingTransactions 查询所有未最终确认（pending或confirming）的交易（代币入账由扫描器按确认深度单独处理）
 func (r *TransactionRepository) GetPendingTransactions(ctx context.Context) ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
 	err := r.db.WithContext(ctx).
-		Where("status = ?", models.TxStatusPending).
+		Where("status IN ? AND log_index IS NULL", []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
 		Order("created_at ASC").
 		Find(&transactions).Error
 	return transactions, err
 }
 
+// DueForCheck 按下次检查时间查询链上已到期的未最终确认交易（最早到期的优先，最多limit笔）
+func (r *TransactionRepository) DueForCheck(ctx context.Context, chainID int, now time.Time, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.WithContext(ctx).
+		Where("chain_id = ? AND next_check_at <= ? AND status IN ? AND log_index IS NULL", chainID, now, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		Order("next_check_at ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// ScheduleNextCheck 设置交易的下次检查时间并清零连续失败次数（不更新updated_at，检查未推进状态时交易列表的ETag保持不变）
+func (r *TransactionRepository) ScheduleNextCheck(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ? AND status IN ?", id, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		UpdateColumns(map[string]interface{}{
+			"next_check_at":  at,
+			"check_failures": 0,
+		}).Error
+}
+
+// RecordCheckFailure 查询回执或交易失败时递增连续失败次数并设置下次检查时间（不更新updated_at）
+func (r *TransactionRepository) RecordCheckFailure(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ? AND status IN ?", id, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		UpdateColumns(map[string]interface{}{
+			"next_check_at":  at,
+			"check_failures": gorm.Expr("check_failures + 1"),
+		}).Error
+}
+
+// CheckNow 将未最终确认的交易设为立即检查（交易创建消息到达或重新入队时）
+func (r *TransactionRepository) CheckNow(ctx context.Context, txHash string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("tx_hash = ? AND log_index IS NULL AND status IN ?", txHash, []models.TransactionStatus{models.TxStatusPending, models.TxStatusConfirming}).
+		UpdateColumn("next_check_at", time.Now()).Error
+}
+
+// CreateTokenDeposit 写入代币入账记录（同一交易的同一日志已存在时忽略），返回是否新写入
+func (r *TransactionRepository) CreateTokenDeposit(ctx context.Context, tx *models.Transaction) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(tx)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetPendingTokenDeposits 查询已达到确认深度（区块号不超过maxBlock）的待确认代币入账
+func (r *TransactionRepository) GetPendingTokenDeposits(ctx context.Context, chainID int, maxBlock uint64, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.WithContext(ctx).
+		Where("chain_id = ? AND log_index IS NOT NULL AND status = ? AND block_number <= ?", chainID, models.TxStatusPending, maxBlock).
+		Order("block_number ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// ConfirmTokenDeposit 仅当代币入账仍为pending时更新为最终状态，返回是否由本次调用完成更新
+func (r *TransactionRepository) ConfirmTokenDeposit(ctx context.Context, id uint, status models.TransactionStatus, errMsg string) (bool, error) {
+	updates := map[string]interface{}{
+		"status":       status,
+		"confirmed_at": gorm.Expr("NOW()"),
+	}
+	if errMsg != "" {
+		updates["error_msg"] = errMsg
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", id, models.TxStatusPending).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateBlockNumber 更新交易所在区块（链重组后交易被打包进新区块）
+func (r *TransactionRepository) UpdateBlockNumber(ctx context.Context, id uint, blockNumber int64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("id = ?", id).
+		Update("block_number", blockNumber).Error
+}
+
 // CountByStatus 统计指定状态的交易数量
 func (r *TransactionRepository) CountByStatus(ctx context.Context, status models.TransactionStatus) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Transaction{}).Where("status = ?", status).Count(&count).Error
 	return count, err
 }
+
+// CountByChainAndStatus 按链与状态统计交易数量（不含代币转账日志）
+func (r *TransactionRepository) CountByChainAndStatus(ctx context.Context) ([]*models.ChainStatusCount, error) {
+	var counts []*models.ChainStatusCount
+	err := r.reader().WithContext(ctx).
+		Model(&models.Transaction{}).
+		Select("chain_id, status, COUNT(*) AS count").
+		Where("log_index IS NULL").
+		Group("chain_id, status").
+		Order("chain_id ASC, status ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// VolumeByChain 按链统计最近7天的成功交易金额（含最近24小时）与平均确认耗时
+func (r *TransactionRepository) VolumeByChain(ctx context.Context, now time.Time) ([]*models.ChainVolumeStat, error) {
+	var stats []*models.ChainVolumeStat
+	err := r.reader().WithContext(ctx).
+		Model(&models.Transaction{}).
+		Select("chain_id, "+
+			"COALESCE(SUM(amount) FILTER (WHERE status = ? AND created_at >= ?), 0)::text AS volume24h, "+
+			"COALESCE(SUM(amount) FILTER (WHERE status = ?), 0)::text AS volume7d, "+
+			"COALESCE(AVG(EXTRACT(EPOCH FROM confirmed_at - created_at)) FILTER (WHERE confirmed_at IS NOT NULL), 0) AS avg_confirmation_seconds",
+			models.TxStatusSuccess, now.Add(-24*time.Hour), models.TxStatusSuccess).
+		Where("created_at >= ? AND log_index IS NULL", now.AddDate(0, 0, -7)).
+		Group("chain_id").
+		Order("chain_id ASC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// statsScope 构建统计查询的公共筛选条件（发送方钱包属于用户）
+func (r *TransactionRepository) statsScope(ctx context.Context, filter *models.TransactionStatsFilter) *gorm.DB {
+	db := r.reader()
+	query := db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("created_at >= ? AND created_at < ?", filter.From, filter.To).
+		Where("log_index IS NULL")
+
+	if filter.WalletID > 0 {
+		query = query.Where("wallet_id = ?", filter.WalletID)
+	} else {
+		query = query.Where("wallet_id IN (?)", db.Model(&models.Wallet{}).Select("id").Scopes(accessibleWallets(db, filter.UserID)))
+	}
+
+	if filter.ChainID > 0 {
+		query = query.Where("chain_id = ?", filter.ChainID)
+	}
+
+	return filterTransactions(query, &filter.TransactionFilter, userWallets(db, filter.UserID, filter.WalletID))
+}
+
+// statsSelect 聚合字段（数值在数据库中计算，以文本返回避免精度丢失）
+const statsSelect = "status, COUNT(*) AS count, " +
+	"COALESCE(SUM(amount), 0)::text AS total_amount, " +
+	"COALESCE(SUM(gas_used * COALESCE(effective_gas_price, gas_price, 0)), 0)::text AS total_gas_fee"
+
+// AggregateByStatus 按状态聚合交易数量、金额与Gas费用
+func (r *TransactionRepository) AggregateByStatus(ctx context.Context, filter *models.TransactionStatsFilter) ([]*models.TransactionStatusStat, error) {
+	var stats []*models.TransactionStatusStat
+	err := r.statsScope(ctx, filter).
+		Select(statsSelect).
+		Group("status").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// AggregateByDay 按天与状态聚合交易数量、金额与Gas费用
+func (r *TransactionRepository) AggregateByDay(ctx context.Context, filter *models.TransactionStatsFilter) ([]*models.TransactionDailyStat, error) {
+	var stats []*models.TransactionDailyStat
+	err := r.statsScope(ctx, filter).
+		Select(fmt.Sprintf("date_trunc('day', created_at) AS day, %s", statsSelect)).
+		Group("day, status").
+		Order("day ASC, status ASC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// SumReceived 统计成功转入用户钱包的金额合计（ETH）
+func (r *TransactionRepository) SumReceived(ctx context.Context, filter *models.TransactionStatsFilter) (string, error) {
+	db := r.reader()
+	wallets := userWallets(db, filter.UserID, filter.WalletID)
+
+	query := db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("created_at >= ? AND created_at < ?", filter.From, filter.To).
+		Where("status = ? AND log_index IS NULL", models.TxStatusSuccess).
+		Where("to_address IN (?)", wallets("address"))
+
+	if filter.ChainID > 0 {
+		query = query.Where("chain_id = ?", filter.ChainID)
+	}
+	query = filterTransactions(query, &filter.TransactionFilter, wallets)
+
+	var total string
+	err := query.Select("COALESCE(SUM(amount), 0)::text").Scan(&total).Error
+	return total, err
+}
+
+// ExportInBatches 按ID顺序分批读取用户相关交易（转出或转入用户钱包），避免一次性加载全部记录
+func (r *TransactionRepository) ExportInBatches(ctx context.Context, filter *models.TransactionExportFilter, batchSize int, fn func([]*models.Transaction) error) error {
+	var batch []*models.Transaction
+	return r.exportQuery(ctx, filter).Preload("Tags", orderTags).FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// CountForExport 统计导出条件下的交易数（创建导出任务时估算行数）
+func (r *TransactionRepository) CountForExport(ctx context.Context, filter *models.TransactionExportFilter) (int64, error) {
+	var count int64
+	err := r.exportQuery(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// exportQuery 导出条件：用户可访问钱包的转出与转入交易，按链与时间筛选
+func (r *TransactionRepository) exportQuery(ctx context.Context, filter *models.TransactionExportFilter) *gorm.DB {
+	db := r.reader()
+	wallets := userWallets(db, filter.UserID, filter.WalletID)
+
+	query := db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Where("wallet_id IN (?) OR to_address IN (?)", wallets("id"), wallets("address"))
+
+	if filter.ChainID > 0 {
+		query = query.Where("chain_id = ?", filter.ChainID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return filterTransactions(query, &filter.TransactionFilter, wallets)
+}
+
+// ListForFeed 按时间倒序查询钱包的转出与转入交易（游标分页）
+func (r *TransactionRepository) ListForFeed(ctx context.Context, walletID uint, address string, cursor *models.ActivityCursor, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	query := r.reader().WithContext(ctx).Where("wallet_id = ? OR LOWER(to_address) = ?", walletID, strings.ToLower(address))
+	err := beforeCursor(query, cursor, models.ActivitySourceTransaction).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// walletLedger 读取钱包的账本余额与内部转账净额（按数值比较，不受小数位格式影响）
+func walletLedger(t *testing.T, db *gorm.DB, id uint) (balance, net string) {
+	t.Helper()
+	var row struct {
+		Balance        string
+		InternalNetWei string
+	}
+	err := db.Table("wallets").
+		Select("CAST(balance AS BIGINT) AS balance, CAST(internal_net_wei AS BIGINT) AS internal_net_wei").
+		Where("id = ?", id).
+		Scan(&row).Error
+	if err != nil {
+		t.Fatalf("load wallet ledger: %v", err)
+	}
+	return row.Balance, row.InternalNetWei
+}
+
+// internalTransfer 构造内部转账记录
+func internalTransfer(from, to *models.Wallet, amount string) *models.Transaction {
+	return &models.Transaction{
+		WalletID:    from.ID,
+		TxHash:      fmt.Sprintf("internal-%056x", seq.Add(1)),
+		Type:        models.TxTypeInternal,
+		FromAddress: from.Address,
+		ToAddress:   to.Address,
+		Amount:      amount,
+		Status:      models.TxStatusSuccess,
+		ChainID:     from.ChainID,
+	}
+}
+
+func TestCreateInternalTransfer(t *testing.T) {
+	testCreateInternalTransfer(t, testutil.NewDB(t))
+}
+
+// TestCreateInternalTransferPostgres 在PostgreSQL中执行内部转账（numeric列与::numeric转换）
+//
+// 需要设置CWA_TEST_POSTGRES_DSN，未设置时跳过
+func TestCreateInternalTransferPostgres(t *testing.T) {
+	testCreateInternalTransfer(t, testutil.NewPostgres(t))
+}
+
+func testCreateInternalTransfer(t *testing.T, db *gorm.DB) {
+	ctx := context.Background()
+	repo := NewTransactionRepository(db)
+	walletRepo := NewWalletRepository(db)
+	user := createUser(t, db)
+	from := createWallet(t, db, user.ID)
+	to := createWallet(t, db, user.ID)
+	if err := walletRepo.UpdateBalance(ctx, from.Address, "100"); err != nil {
+		t.Fatalf("seed balance: %v", err)
+	}
+
+	// 按数值比较余额：按字符串比较时"100" >= "99"不成立，"99" >= "100"成立
+	transfers := []struct {
+		amount   string
+		reverse  bool // 由收款方转回发送方
+		wantErr  bool
+		from, to string
+	}{
+		{amount: "101", wantErr: true, from: "100", to: "0"},
+		{amount: "99", from: "1", to: "99"},
+		{amount: "100", reverse: true, wantErr: true, from: "1", to: "99"},
+		{amount: "2", wantErr: true, from: "1", to: "99"},
+		{amount: "1", from: "0", to: "100"},
+	}
+	for _, tt := range transfers {
+		src, dst := from, to
+		if tt.reverse {
+			src, dst = to, from
+		}
+		err := repo.CreateInternalTransfer(ctx, internalTransfer(src, dst, tt.amount), dst.ID, tt.amount)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("transfer %s: err = %v, want error %t", tt.amount, err, tt.wantErr)
+		}
+		// 余额不足时双方余额均不变
+		if balance, _ := walletLedger(t, db, from.ID); balance != tt.from {
+			t.Errorf("after transfer %s: sender balance = %s, want %s", tt.amount, balance, tt.from)
+		}
+		if balance, _ := walletLedger(t, db, to.ID); balance != tt.to {
+			t.Errorf("after transfer %s: recipient balance = %s, want %s", tt.amount, balance, tt.to)
+		}
+	}
+
+	// 净额记录未在链上结算的转账，双方合计为0
+	if _, net := walletLedger(t, db, from.ID); net != "-100" {
+		t.Errorf("sender internal net = %s, want -100", net)
+	}
+	if _, net := walletLedger(t, db, to.ID); net != "100" {
+		t.Errorf("recipient internal net = %s, want 100", net)
+	}
+	var count int64
+	if err := db.Model(&models.Transaction{}).Where("wallet_id = ? AND type = ?", from.ID, models.TxTypeInternal).Count(&count).Error; err != nil {
+		t.Fatalf("count transfers: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("internal transactions = %d, want 2 (failed transfers not saved)", count)
+	}
+
+	// 链上余额刷新保留内部转账净额
+	if err := walletRepo.UpdateBalance(ctx, to.Address, "5"); err != nil {
+		t.Fatalf("update balance: %v", err)
+	}
+	if balance, _ := walletLedger(t, db, to.ID); balance != "105" {
+		t.Errorf("recipient ledger after refresh = %s, want 105", balance)
+	}
+}
+
+func TestWithTxRollsBackOnFailure(t *testing.T) {
+	errCrash := errors.New("crash between writes")
+
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, txRepo *TransactionRepository, walletRepo *WalletRepository, tx *models.Transaction) error
+	}{
+		{
+			name: "failure after status update",
+			fn: func(ctx context.Context, txRepo *TransactionRepository, walletRepo *WalletRepository, tx *models.Transaction) error {
+				if _, err := txRepo.ConfirmIfPending(ctx, tx.TxHash, models.TxStatusSuccess, 100, 1, 21000, nil, ""); err != nil {
+					return err
+				}
+				return errCrash
+			},
+		},
+		{
+			name: "failure after both writes",
+			fn: func(ctx context.Context, txRepo *TransactionRepository, walletRepo *WalletRepository, tx *models.Transaction) error {
+				if _, err := txRepo.ConfirmIfPending(ctx, tx.TxHash, models.TxStatusSuccess, 100, 1, 21000, nil, ""); err != nil {
+					return err
+				}
+				if err := walletRepo.MarkBalanceStale(ctx, tx.WalletID); err != nil {
+					return err
+				}
+				return errCrash
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := testutil.NewDB(t)
+			wallet := createWallet(t, db, createUser(t, db).ID)
+			tx := createTransaction(t, db, wallet, nil)
+			txRepo := NewTransactionRepository(db)
+
+			err := txRepo.WithTx(ctx, func(txRepo *TransactionRepository, walletRepo *WalletRepository) error {
+				return tt.fn(ctx, txRepo, walletRepo, tx)
+			})
+			if !errors.Is(err, errCrash) {
+				t.Fatalf("err = %v, want %v", err, errCrash)
+			}
+
+			// 交易状态与余额待刷新标记都未提交
+			saved, err := txRepo.GetByTxHash(ctx, tx.TxHash)
+			if err != nil {
+				t.Fatalf("load transaction: %v", err)
+			}
+			if saved.Status != models.TxStatusPending || saved.ConfirmedAt != nil {
+				t.Errorf("status = %s confirmed_at = %v, want pending and unconfirmed", saved.Status, saved.ConfirmedAt)
+			}
+			savedWallet, err := NewWalletRepository(db).GetByID(ctx, wallet.ID)
+			if err != nil {
+				t.Fatalf("load wallet: %v", err)
+			}
+			if savedWallet.BalanceStaleAt != nil {
+				t.Errorf("balance_stale_at = %v, want nil", savedWallet.BalanceStaleAt)
+			}
+		})
+	}
+}
+
+func TestWithTxCommitsAllWrites(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	wallet := createWallet(t, db, createUser(t, db).ID)
+	tx := createTransaction(t, db, wallet, nil)
+	txRepo := NewTransactionRepository(db)
+
+	err := txRepo.WithTx(ctx, func(txRepo *TransactionRepository, walletRepo *WalletRepository) error {
+		if _, err := txRepo.ConfirmIfPending(ctx, tx.TxHash, models.TxStatusSuccess, 100, 1, 21000, nil, ""); err != nil {
+			return err
+		}
+		return walletRepo.MarkBalanceStale(ctx, tx.WalletID)
+	})
+	if err != nil {
+		t.Fatalf("with tx: %v", err)
+	}
+
+	saved, err := txRepo.GetByTxHash(ctx, tx.TxHash)
+	if err != nil {
+		t.Fatalf("load transaction: %v", err)
+	}
+	savedWallet, err := NewWalletRepository(db).GetByID(ctx, wallet.ID)
+	if err != nil {
+		t.Fatalf("load wallet: %v", err)
+	}
+	if saved.Status != models.TxStatusSuccess || savedWallet.BalanceStaleAt == nil {
+		t.Errorf("status = %s balance_stale_at = %v, want success and marked stale", saved.Status, savedWallet.BalanceStaleAt)
+	}
+}
+
+func TestListTransactionFilters(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewTransactionRepository(db)
+	user := createUser(t, db)
+	a := createWallet(t, db, user.ID)
+	b := createWallet(t, db, user.ID)
+	other := createWallet(t, db, createUser(t, db).ID)
+	external := "0xabababababababababababababababababababab"
+	logIndex := uint(0)
+
+	send := func(from *models.Wallet, to, amount string) *models.Transaction {
+		return createTransaction(t, db, from, func(tx *models.Transaction) {
+			tx.ToAddress = to
+			tx.Amount = amount
+		})
+	}
+	small := send(a, "0x1111111111111111111111111111111111111111", "0.5")
+	large := send(a, "0x2222222222222222222222222222222222222222", "2")
+	between := send(b, a.Address, "1.5") // 范围内钱包之间的互转同时属于两个方向
+	deposit := createTransaction(t, db, a, func(tx *models.Transaction) {
+		tx.FromAddress = external
+		tx.ToAddress = a.Address
+		tx.Amount = "10"
+		tx.LogIndex = &logIndex
+	})
+	send(other, external, "3") // 其他用户的交易不在范围内
+
+	tests := []struct {
+		name   string
+		filter models.TransactionFilter
+		want   []*models.Transaction
+	}{
+		{"no filter", models.TransactionFilter{}, []*models.Transaction{small, large, between, deposit}},
+		{"outgoing", models.TransactionFilter{Direction: models.DirectionOutgoing}, []*models.Transaction{small, large, between}},
+		{"incoming", models.TransactionFilter{Direction: models.DirectionIncoming}, []*models.Transaction{between, deposit}},
+		{"min amount", models.TransactionFilter{MinAmount: "1.5"}, []*models.Transaction{large, between, deposit}},
+		{"max amount", models.TransactionFilter{MaxAmount: "2"}, []*models.Transaction{small, large, between}},
+		{"amount range", models.TransactionFilter{MinAmount: "1", MaxAmount: "2"}, []*models.Transaction{large, between}},
+		{"counterparty ignores case", models.TransactionFilter{Counterparty: strings.ToUpper(external)}, []*models.Transaction{deposit}},
+		{"counterparty sender", models.TransactionFilter{Counterparty: b.Address}, []*models.Transaction{between}},
+		{"outgoing and min amount", models.TransactionFilter{Direction: models.DirectionOutgoing, MinAmount: "1"}, []*models.Transaction{large, between}},
+		{"incoming and max amount", models.TransactionFilter{Direction: models.DirectionIncoming, MaxAmount: "5"}, []*models.Transaction{between}},
+		{"incoming and counterparty", models.TransactionFilter{Direction: models.DirectionIncoming, Counterparty: external}, []*models.Transaction{deposit}},
+		{"all filters", models.TransactionFilter{Direction: models.DirectionOutgoing, MinAmount: "0.1", MaxAmount: "1", Counterparty: small.ToAddress}, []*models.Transaction{small}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, total, err := repo.List(ctx, []uint{a.ID, b.ID}, &models.TransactionListRequest{TransactionFilter: tt.filter})
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			got := make([]string, len(transactions))
+			for i, tx := range transactions {
+				got[i] = tx.TxHash
+			}
+			want := make([]string, len(tt.want))
+			for i, tx := range tt.want {
+				want[i] = tx.TxHash
+			}
+			slices.Sort(got)
+			slices.Sort(want)
+			if total != int64(len(want)) || !slices.Equal(got, want) {
+				t.Errorf("list = %d %v, want %v", total, got, want)
+			}
+		})
+	}
+}
+
+func TestTransactionHashLogIndexUnique(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewTransactionRepository(db)
+	wallet := createWallet(t, db, createUser(t, db).ID)
+	logIndex := func(n uint) *uint { return &n }
+
+	sent := createTransaction(t, db, wallet, nil)
+	insert := func(hash string, index *uint) error {
+		return db.Create(&models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      hash,
+			LogIndex:    index,
+			FromAddress: wallet.Address,
+			ToAddress:   wallet.Address,
+			Amount:      "1",
+			Status:      models.TxStatusPending,
+			ChainID:     wallet.ChainID,
+		}).Error
+	}
+
+	// 同一哈希的普通交易（无日志序号）只能有一条
+	if err := insert(sent.TxHash, nil); err == nil {
+		t.Error("duplicate tx_hash without log_index inserted, want unique violation")
+	}
+	// 同一交易中的代币日志按序号区分，同一序号只能有一条
+	if err := insert(sent.TxHash, logIndex(0)); err != nil {
+		t.Fatalf("insert log 0: %v", err)
+	}
+	if err := insert(sent.TxHash, logIndex(1)); err != nil {
+		t.Fatalf("insert log 1: %v", err)
+	}
+	if err := insert(sent.TxHash, logIndex(0)); err == nil {
+		t.Error("duplicate (tx_hash, log_index) inserted, want unique violation")
+	}
+	// 未签名的交易（空哈希）不受约束
+	for range 2 {
+		if err := insert("", nil); err != nil {
+			t.Fatalf("insert unsigned: %v", err)
+		}
+	}
+
+	// 代币入账重复写入时忽略
+	deposit := func() *models.Transaction {
+		return &models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      sent.TxHash,
+			LogIndex:    logIndex(7),
+			FromAddress: wallet.Address,
+			ToAddress:   wallet.Address,
+			Amount:      "1",
+			Status:      models.TxStatusPending,
+			ChainID:     wallet.ChainID,
+		}
+	}
+	for i, want := range []bool{true, false} {
+		created, err := repo.CreateTokenDeposit(ctx, deposit())
+		if err != nil {
+			t.Fatalf("create token deposit #%d: %v", i+1, err)
+		}
+		if created != want {
+			t.Errorf("create token deposit #%d = %v, want %v", i+1, created, want)
+		}
+	}
+}
@@ -3,24 +3,41 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
 )
 
 // UserRepository 用户数据访问层
 type UserRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	emailHMACKey []byte // 邮箱检索摘要密钥（与加密密钥分离）
 }
 
 // NewUserRepository 创建用户仓库实例
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *gorm.DB, emailHMACKey []byte) *UserRepository {
+	return &UserRepository{db: db, emailHMACKey: emailHMACKey}
+}
+
+// emailHash 计算邮箱的检索摘要
+func (r *UserRepository) emailHash(email string) *string {
+	hash := utils.HMACSHA256Hex(email, r.emailHMACKey)
+	return &hash
+}
+
+// emailCondition 按邮箱查询的条件（按摘要匹配；尚未回填摘要的旧记录邮箱仍为明文，按原值匹配）
+func (r *UserRepository) emailCondition(db *gorm.DB, email string) *gorm.DB {
+	return db.Where("email_hash = ? OR (email_hash IS NULL AND email = ?)", *r.emailHash(email), email)
 }
 
 // Create 创建用户
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	user.EmailHash = r.emailHash(user.Email)
 	return r.db.WithContext(ctx).Create(user).Error
 }
 
@@ -30,7 +47,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uint) (*models.User, er
 	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperr.NotFound("user")
 		}
 		return nil, err
 	}
@@ -40,10 +57,10 @@ func (r *UserRepository) GetByID(ctx context.Context, id uint) (*models.User, er
 // GetByEmail 根据邮箱查询用户
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := r.emailCondition(r.db.WithContext(ctx), email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperr.NotFound("user")
 		}
 		return nil, err
 	}
@@ -56,7 +73,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperr.NotFound("user")
 		}
 		return nil, err
 	}
@@ -65,18 +82,81 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 
 // Update 更新用户信息
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	user.EmailHash = r.emailHash(user.Email)
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// IncrementTokenVersion 递增Token版本并返回新版本号
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, id uint) (int, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Select("token_version").First(&user, id).Error
+	})
+	return user.TokenVersion, err
+}
+
+// UpdateUsername 修改用户名
+func (r *UserRepository) UpdateUsername(ctx context.Context, id uint, username string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		Update("username", username).Error
+}
+
+// UpdatePassword 更新密码哈希并递增Token版本（同一事务内完成），返回新版本号
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) (int, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"password_hash": passwordHash,
+				"token_version": gorm.Expr("token_version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Select("token_version").First(&user, id).Error
+	})
+	return user.TokenVersion, err
+}
+
+// SetDisabled 设置停用时间（nil表示启用）并递增Token版本（同一事务内完成），返回新版本号
+func (r *UserRepository) SetDisabled(ctx context.Context, id uint, disabledAt *time.Time) (int, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"disabled_at":   disabledAt,
+				"token_version": gorm.Expr("token_version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Select("token_version").First(&user, id).Error
+	})
+	return user.TokenVersion, err
+}
+
 // Delete 删除用户（软删除）
 func (r *UserRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
 }
 
+// CountAll 统计用户总数
+func (r *UserRepository) CountAll(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
 // ExistsByEmail 检查邮箱是否已存在
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	err := r.emailCondition(r.db.WithContext(ctx).Model(&models.User{}), email).Count(&count).Error
 	return count > 0, err
 }
 
@@ -86,3 +166,36 @@ func (r *UserRepository) ExistsByUsername(ctx context.Context, username string)
 	err := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username).Count(&count).Error
 	return count > 0, err
 }
+
+// BackfillEmails 加密尚未回填的明文邮箱并写入检索摘要（每批batchSize条），返回处理的记录数
+func (r *UserRepository) BackfillEmails(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		// 1. 读取原始列值（不经过加密序列化器）
+		var rows []struct {
+			ID    uint
+			Email string
+		}
+		if err := r.db.WithContext(ctx).Table("users").
+			Select("id, email").
+			Where("email_hash IS NULL").
+			Order("id").
+			Limit(batchSize).
+			Scan(&rows).Error; err != nil {
+			return total, err
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		// 2. 逐条写回（邮箱经序列化器加密）
+		for _, row := range rows {
+			if err := r.db.WithContext(ctx).Model(&models.User{ID: row.ID}).
+				Select("email", "email_hash").
+				Updates(&models.User{Email: row.Email, EmailHash: r.emailHash(row.Email)}).Error; err != nil {
+				return total, fmt.Errorf("failed to backfill user %d: %w", row.ID, err)
+			}
+		}
+		total += len(rows)
+	}
+}
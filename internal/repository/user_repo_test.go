@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// insertLegacyUser 以原始SQL写入加密上线前的用户（邮箱为明文，无检索摘要）
+func insertLegacyUser(t *testing.T, db *gorm.DB, email string) {
+	t.Helper()
+	n := seq.Add(1)
+	now := time.Now()
+	err := db.Exec(`INSERT INTO users (username, email, password_hash, token_version, role, created_at, updated_at)
+		VALUES (?, ?, 'unused', 0, 'user', ?, ?)`, fmt.Sprintf("legacy%d", n), email, now, now).Error
+	if err != nil {
+		t.Fatalf("insert legacy user: %v", err)
+	}
+}
+
+// storedEmail 读取users表中邮箱列与摘要列的原始值（不经过加密序列化器）
+func storedEmail(t *testing.T, db *gorm.DB, username string) (email string, hash *string) {
+	t.Helper()
+	var row struct {
+		Email     string
+		EmailHash *string
+	}
+	if err := db.Table("users").Select("email, email_hash").Where("username = ?", username).Scan(&row).Error; err != nil {
+		t.Fatalf("load raw user: %v", err)
+	}
+	return row.Email, row.EmailHash
+}
+
+func TestUserEmailLookupByHash(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db, testutil.EmailHMACKey)
+	user := createUser(t, db)
+
+	// 邮箱加密存储，摘要单独写入
+	email, hash := storedEmail(t, db, user.Username)
+	if email == user.Email {
+		t.Errorf("email stored as plaintext %q", email)
+	}
+	if hash == nil || *hash != *repo.emailHash(user.Email) {
+		t.Errorf("email_hash = %v, want HMAC of %q", hash, user.Email)
+	}
+
+	got, err := repo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("get by email: %v", err)
+	}
+	if got.ID != user.ID || got.Email != user.Email {
+		t.Errorf("got user %d %q, want %d %q", got.ID, got.Email, user.ID, user.Email)
+	}
+
+	// 其他密钥计算的摘要不匹配
+	if _, err := NewUserRepository(db, testutil.EncryptionKey).GetByEmail(ctx, user.Email); err == nil {
+		t.Error("lookup with a different HMAC key found the user")
+	}
+	if exists, err := repo.ExistsByEmail(ctx, "nobody@example.com"); err != nil || exists {
+		t.Errorf("exists unknown email = %v, %v; want false", exists, err)
+	}
+}
+
+func TestUserEmailLookupLegacyPlaintext(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db, testutil.EmailHMACKey)
+	insertLegacyUser(t, db, "legacy@example.com")
+
+	got, err := repo.GetByEmail(ctx, "legacy@example.com")
+	if err != nil {
+		t.Fatalf("get legacy user by email: %v", err)
+	}
+	if got.Email != "legacy@example.com" {
+		t.Errorf("email = %q, want plaintext read unchanged", got.Email)
+	}
+	if exists, err := repo.ExistsByEmail(ctx, "legacy@example.com"); err != nil || !exists {
+		t.Errorf("exists legacy email = %v, %v; want true", exists, err)
+	}
+	if exists, err := repo.ExistsByEmail(ctx, "LEGACY@example.com"); err != nil || exists {
+		t.Errorf("exists differently cased email = %v, %v; want false", exists, err)
+	}
+}
+
+func TestBackfillEmails(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db, testutil.EmailHMACKey)
+	const legacy = 5
+	for i := range legacy {
+		insertLegacyUser(t, db, fmt.Sprintf("backfill%d@example.com", i))
+	}
+	migrated := createUser(t, db)
+
+	// 回填前按明文匹配
+	if _, err := repo.GetByEmail(ctx, "backfill0@example.com"); err != nil {
+		t.Fatalf("lookup before backfill: %v", err)
+	}
+
+	// 统计读取待回填记录的批次数
+	var batches int
+	err := db.Callback().Row().After("gorm:row").Register("test:count_batches", func(tx *gorm.DB) {
+		if tx.Statement.Table == "users" {
+			batches++
+		}
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	n, err := repo.BackfillEmails(ctx, 2)
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if n != legacy {
+		t.Errorf("backfilled = %d, want %d", n, legacy)
+	}
+	// 2+2+1条，最后一次读取为空
+	if batches != 4 {
+		t.Errorf("batches = %d, want 4", batches)
+	}
+
+	var users []*models.User
+	if err := db.Order("id").Find(&users).Error; err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+	for _, user := range users {
+		email, hash := storedEmail(t, db, user.Username)
+		if email == user.Email || hash == nil {
+			t.Errorf("%s not backfilled: email %q hash %v", user.Username, email, hash)
+		}
+		got, err := repo.GetByEmail(ctx, user.Email)
+		if err != nil || got.ID != user.ID {
+			t.Errorf("lookup %s after backfill = %v, %v", user.Email, got, err)
+		}
+	}
+	if _, err := repo.GetByEmail(ctx, migrated.Email); err != nil {
+		t.Errorf("lookup already migrated user: %v", err)
+	}
+
+	// 再次执行不重复处理
+	if n, err := repo.BackfillEmails(ctx, 2); err != nil || n != 0 {
+		t.Errorf("second backfill = %d, %v; want 0", n, err)
+	}
+}
@@ -3,12 +3,28 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/models"
 )
 
+// walletInsertBatchSize 批量创建钱包时每条INSERT语句的行数（批量创建上限为500，通常一条语句完成）
+const walletInsertBatchSize = 500
+
+const (
+	// pgUniqueViolation PostgreSQL唯一约束冲突的错误码
+	pgUniqueViolation = "23505"
+	// walletAddressConstraint 钱包地址的唯一约束
+	walletAddressConstraint = "uni_wallets_address"
+)
+
+// ErrDuplicateAddress 钱包地址已被收录（写入时违反地址唯一约束）
+var ErrDuplicateAddress = apperr.Conflict("error.wallet_exists", "wallet already exists")
+
 // WalletRepository 钱包数据访问层
 type WalletRepository struct {
 	db *gorm.DB
@@ -19,9 +35,100 @@ func NewWalletRepository(db *gorm.DB) *WalletRepository {
 	return &WalletRepository{db: db}
 }
 
-// Create 创建钱包
+// Create 创建钱包（用户在该链上还没有默认钱包时，新建的个人钱包自动成为默认钱包）
 func (r *WalletRepository) Create(ctx context.Context, wallet *models.Wallet) error {
-	return r.db.WithContext(ctx).Create(wallet).Error
+	return r.CreateWithOutbox(ctx, wallet, nil)
+}
+
+// CreateWithOutbox 在同一事务中创建钱包与发件箱事件（event为nil时只创建钱包）
+func (r *WalletRepository) CreateWithOutbox(ctx context.Context, wallet *models.Wallet, event *models.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		wallet.IsDefault = false
+		if err := tx.Create(wallet).Error; err != nil {
+			return walletInsertError(err)
+		}
+		if event != nil {
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+		if wallet.OrgID != nil {
+			return nil
+		}
+
+		result := tx.Model(&models.Wallet{}).
+			Where("id = ?", wallet.ID).
+			Where("NOT EXISTS (SELECT 1 FROM wallets WHERE user_id = ? AND chain_id = ? AND is_default)", wallet.UserID, wallet.ChainID).
+			Update("is_default", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		wallet.IsDefault = result.RowsAffected > 0
+		return nil
+	})
+}
+
+// CreateBatch 在同一事务中批量创建钱包（同一用户同一链上的个人钱包：用户在该链上还没有默认钱包时，第一个钱包成为默认钱包）
+func (r *WalletRepository) CreateBatch(ctx context.Context, wallets []*models.Wallet) error {
+	if len(wallets) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, wallet := range wallets {
+			wallet.IsDefault = false
+		}
+		if err := tx.CreateInBatches(wallets, walletInsertBatchSize).Error; err != nil {
+			return walletInsertError(err)
+		}
+		first := wallets[0]
+		if first.OrgID != nil {
+			return nil
+		}
+
+		result := tx.Model(&models.Wallet{}).
+			Where("id = ?", first.ID).
+			Where("NOT EXISTS (SELECT 1 FROM wallets WHERE user_id = ? AND chain_id = ? AND is_default)", first.UserID, first.ChainID).
+			Update("is_default", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		first.IsDefault = result.RowsAffected > 0
+		return nil
+	})
+}
+
+// GetDefault 查询用户在指定链上的默认钱包
+func (r *WalletRepository) GetDefault(ctx context.Context, userID uint, chainID int) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND chain_id = ? AND is_default", userID, chainID).
+		First(&wallet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("wallet")
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// SetDefault 将钱包设为所有者在该链上的默认钱包（同一事务内取消原默认钱包，部分唯一索引保证至多一个）
+func (r *WalletRepository) SetDefault(ctx context.Context, wallet *models.Wallet) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Wallet{}).
+			Where("user_id = ? AND chain_id = ? AND is_default AND id <> ?", wallet.UserID, wallet.ChainID, wallet.ID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Wallet{}).
+			Where("id = ?", wallet.ID).
+			Update("is_default", true).Error
+	})
+	if err != nil {
+		return err
+	}
+	wallet.IsDefault = true
+	return nil
 }
 
 // GetByID 根据ID查询钱包
@@ -30,7 +137,7 @@ func (r *WalletRepository) GetByID(ctx context.Context, id uint) (*models.Wallet
 	err := r.db.WithContext(ctx).First(&wallet, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("wallet not found")
+			return nil, apperr.NotFound("wallet")
 		}
 		return nil, err
 	}
@@ -43,23 +150,58 @@ func (r *WalletRepository) GetByAddress(ctx context.Context, address string) (*m
 	err := r.db.WithContext(ctx).Where("address = ?", address).First(&wallet).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("wallet not found")
+			return nil, apperr.NotFound("wallet")
 		}
 		return nil, err
 	}
 	return &wallet, nil
 }
 
-// GetByUserID 查询用户的所有钱包
+// GetByUserID 查询用户可访问的所有钱包（个人钱包与所属组织的钱包）
 func (r *WalletRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.Wallet, error) {
 	var wallets []*models.Wallet
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+		Scopes(accessibleWallets(r.db, userID)).
 		Order("created_at DESC").
 		Find(&wallets).Error
 	return wallets, err
 }
 
+// ListByUserID 查询用户可访问的钱包，按归档状态筛选（archived为nil时不筛选）
+func (r *WalletRepository) ListByUserID(ctx context.Context, userID uint, archived *bool) ([]*models.Wallet, error) {
+	query := r.db.WithContext(ctx).Scopes(accessibleWallets(r.db, userID))
+	if archived != nil {
+		query = query.Where("archived = ?", *archived)
+	}
+
+	var wallets []*models.Wallet
+	err := query.Order("created_at DESC").Find(&wallets).Error
+	return wallets, err
+}
+
+// WalletListVersion 钱包列表中一条链的钱包数量与最近更新时间
+type WalletListVersion struct {
+	ChainID   int
+	Count     int64
+	UpdatedAt time.Time
+}
+
+// ListVersions 按链统计与ListByUserID相同筛选条件下的钱包数量与最近更新时间（用于钱包列表的ETag，不加载钱包记录）
+func (r *WalletRepository) ListVersions(ctx context.Context, userID uint, archived *bool) ([]*WalletListVersion, error) {
+	query := r.db.WithContext(ctx).Model(&models.Wallet{}).Scopes(accessibleWallets(r.db, userID))
+	if archived != nil {
+		query = query.Where("archived = ?", *archived)
+	}
+
+	var versions []*WalletListVersion
+	err := query.
+		Select("chain_id, COUNT(*) AS count, MAX(updated_at) AS updated_at").
+		Group("chain_id").
+		Order("chain_id ASC").
+		Scan(&versions).Error
+	return versions, err
+}
+
 // GetByUserIDAndChainID 查询用户在指定链上的钱包
 func (r *WalletRepository) GetByUserIDAndChainID(ctx context.Context, userID uint, chainID int) ([]*models.Wallet, error) {
 	var wallets []*models.Wallet
@@ -70,17 +212,91 @@ func (r *WalletRepository) GetByUserIDAndChainID(ctx context.Context, userID uin
 	return wallets, err
 }
 
-// UpdateBalance 更新钱包余额
+// GetByChainID 查询链上所有钱包（仅ID、用户与地址，用于链上扫描匹配）
+func (r *WalletRepository) GetByChainID(ctx context.Context, chainID int) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+	err := r.db.WithContext(ctx).
+		Select("id", "user_id", "address").
+		Where("chain_id = ?", chainID).
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// FindInBatches 分批遍历未归档的钱包（仅ID、链与余额，用于定时余额快照）
+func (r *WalletRepository) FindInBatches(ctx context.Context, batchSize int, fn func(wallets []*models.Wallet) error) error {
+	var batch []*models.Wallet
+	return r.db.WithContext(ctx).
+		Select("id", "chain_id", "balance").
+		Where("archived = ?", false).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// FindForReconciliation 分批遍历链上未归档的钱包（仅ID、地址、账本余额与内部转账净额，用于余额对账）
+func (r *WalletRepository) FindForReconciliation(ctx context.Context, chainID int, batchSize int, fn func(wallets []*models.Wallet) error) error {
+	var batch []*models.Wallet
+	return r.db.WithContext(ctx).
+		Select("id", "address", "chain_id", "balance", "internal_net_wei").
+		Where("chain_id = ? AND archived = ?", chainID, false).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// UpdateBalance 按链上余额更新钱包账本余额（保留尚未在链上结算的内部转账净额），并清除待刷新标记
 func (r *WalletRepository) UpdateBalance(ctx context.Context, address string, balance string) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Wallet{}).
 		Where("address = ?", address).
-		Update("balance", balance).Error
+		Updates(map[string]interface{}{
+			"balance":          gorm.Expr("?::numeric + internal_net_wei", balance),
+			"balance_stale_at": nil,
+		}).Error
+}
+
+// MarkBalanceStale 标记钱包的链上余额待刷新（已有标记时保留最早的时间，刷新余额时由UpdateBalance清除）
+func (r *WalletRepository) MarkBalanceStale(ctx context.Context, ids ...uint) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Wallet{}).
+		Where("id IN ?", ids).
+		Update("balance_stale_at", gorm.Expr("COALESCE(balance_stale_at, NOW())")).Error
+}
+
+// ListBalanceStale 查询在before之前标记为待刷新、链上余额仍未刷新的钱包
+func (r *WalletRepository) ListBalanceStale(ctx context.Context, before time.Time, limit int) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+	err := r.db.WithContext(ctx).
+		Where("balance_stale_at < ?", before).
+		Order("balance_stale_at ASC").
+		Limit(limit).
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// UpdateFundingStatus 钱包领水状态为from时更新为to，返回是否更新（并发处理同一领水任务时只有一方成功）
+func (r *WalletRepository) UpdateFundingStatus(ctx context.Context, id uint, from, to string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Wallet{}).
+		Where("id = ? AND funding_status = ?", id, from).
+		Update("funding_status", to)
+	return result.RowsAffected > 0, result.Error
 }
 
-// Update 更新钱包信息
+// Update 更新钱包信息（不写入余额及其待刷新标记、默认标记与领水状态，余额只通过UpdateBalance与内部转账更新，默认标记只通过SetDefault更新，
+// 领水状态只通过UpdateFundingStatus更新，避免覆盖并发的变更）
 func (r *WalletRepository) Update(ctx context.Context, wallet *models.Wallet) error {
-	return r.db.WithContext(ctx).Save(wallet).Error
+	return r.db.WithContext(ctx).Omit("balance", "internal_net_wei", "balance_stale_at", "is_default", "funding_status").Save(wallet).Error
+}
+
+// ListWithInternalNet 查询存在未结算内部转账净额的钱包
+func (r *WalletRepository) ListWithInternalNet(ctx context.Context) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+	err := r.db.WithContext(ctx).
+		Where("internal_net_wei <> 0").
+		Order("chain_id ASC, id ASC").
+		Find(&wallets).Error
+	return wallets, err
 }
 
 // Delete 删除钱包
@@ -101,3 +317,104 @@ func (r *WalletRepository) Count(ctx context.Context, userID uint) (int64, error
 	err := r.db.WithContext(ctx).Model(&models.Wallet{}).Where("user_id = ?", userID).Count(&count).Error
 	return count, err
 }
+
+// CountByChain 按链统计钱包数量
+func (r *WalletRepository) CountByChain(ctx context.Context) ([]*models.ChainCount, error) {
+	var counts []*models.ChainCount
+	err := r.db.WithContext(ctx).
+		Model(&models.Wallet{}).
+		Select("chain_id, COUNT(*) AS count").
+		Group("chain_id").
+		Order("chain_id ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetMembership 查询用户在组织中的成员关系（含组织信息），非成员时返回nil
+func (r *WalletRepository) GetMembership(ctx context.Context, orgID, userID uint) (*models.OrgMembership, error) {
+	var membership models.OrgMembership
+	err := r.db.WithContext(ctx).
+		Preload("Organization").
+		Where("org_id = ? AND user_id = ?", orgID, userID).
+		First(&membership).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// accessibleWallets 用户可访问的钱包条件：本人的个人钱包与其所属组织的钱包
+func accessibleWallets(db *gorm.DB, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where("((wallets.user_id = ? AND wallets.org_id IS NULL) OR wallets.org_id IN (?))",
+			userID,
+			db.Model(&models.OrgMembership{}).Select("org_id").Where("user_id = ?", userID),
+		)
+	}
+}
+
+// GetApproverIDs 查询钱包审批人用户ID
+func (r *WalletRepository) GetApproverIDs(ctx context.Context, walletID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.WalletApprover{}).
+		Where("wallet_id = ?", walletID).
+		Order("user_id").
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// IsApprover 检查用户是否为钱包审批人
+func (r *WalletRepository) IsApprover(ctx context.Context, walletID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.WalletApprover{}).
+		Where("wallet_id = ? AND user_id = ?", walletID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateApprovalPolicy 在同一事务中保存钱包审批阈值并整体替换审批人（审批人必须是已存在的用户）
+func (r *WalletRepository) UpdateApprovalPolicy(ctx context.Context, wallet *models.Wallet, approverIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		if len(approverIDs) > 0 {
+			var count int64
+			if err := db.Model(&models.User{}).Where("id IN ?", approverIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != int64(len(approverIDs)) {
+				return apperr.Invalid("error.approver_not_found", "approver not found")
+			}
+		}
+
+		if err := db.Model(wallet).Updates(map[string]interface{}{
+			"approval_threshold_wei": wallet.ApprovalThresholdWei,
+			"required_approvals":     wallet.RequiredApprovals,
+		}).Error; err != nil {
+			return err
+		}
+		if err := db.Where("wallet_id = ?", wallet.ID).Delete(&models.WalletApprover{}).Error; err != nil {
+			return err
+		}
+		if len(approverIDs) == 0 {
+			return nil
+		}
+		approvers := make([]models.WalletApprover, len(approverIDs))
+		for i, userID := range approverIDs {
+			approvers[i] = models.WalletApprover{WalletID: wallet.ID, UserID: userID}
+		}
+		return db.Create(&approvers).Error
+	})
+}
+
+// walletInsertError 将地址唯一约束冲突转换为ErrDuplicateAddress，其他错误原样返回
+func walletInsertError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == walletAddressConstraint {
+		return ErrDuplicateAddress
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// defaultWalletIDs 用户在测试链上标记为默认的钱包ID
+func defaultWalletIDs(t *testing.T, db *gorm.DB, userID uint) []uint {
+	t.Helper()
+	var ids []uint
+	if err := db.Model(&models.Wallet{}).Where("user_id = ? AND chain_id = ? AND is_default", userID, testutil.ChainID).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatalf("load default wallets: %v", err)
+	}
+	return ids
+}
+
+func TestDefaultWalletUniquePerUserChain(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	repo := NewWalletRepository(db)
+	user := createUser(t, db)
+	other := createUser(t, db)
+
+	// 第一个钱包自动成为默认钱包，之后创建的不是
+	first := createWallet(t, db, user.ID)
+	second := createWallet(t, db, user.ID)
+	if !first.IsDefault || second.IsDefault {
+		t.Errorf("is_default = %t, %t; want first wallet only", first.IsDefault, second.IsDefault)
+	}
+	if otherWallet := createWallet(t, db, other.ID); !otherWallet.IsDefault {
+		t.Error("another user's first wallet is not default")
+	}
+
+	// 部分唯一索引拒绝同一用户同一链上的第二个默认钱包
+	err := db.Model(&models.Wallet{}).Where("id = ?", second.ID).Update("is_default", true).Error
+	if err == nil {
+		t.Fatal("second default wallet accepted")
+	}
+	if ids := defaultWalletIDs(t, db, user.ID); len(ids) != 1 || ids[0] != first.ID {
+		t.Errorf("default wallets = %v, want [%d]", ids, first.ID)
+	}
+
+	// SetDefault在同一事务中切换默认钱包
+	if err := repo.SetDefault(ctx, second); err != nil {
+		t.Fatalf("set default: %v", err)
+	}
+	if ids := defaultWalletIDs(t, db, user.ID); len(ids) != 1 || ids[0] != second.ID {
+		t.Errorf("default wallets after switch = %v, want [%d]", ids, second.ID)
+	}
+	got, err := repo.GetDefault(ctx, user.ID, testutil.ChainID)
+	if err != nil || got.ID != second.ID {
+		t.Errorf("get default = %v, %v; want wallet %d", got, err, second.ID)
+	}
+
+	// 普通更新不写入默认标记
+	first.IsDefault = true
+	first.Name = "renamed"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if ids := defaultWalletIDs(t, db, user.ID); len(ids) != 1 || ids[0] != second.ID {
+		t.Errorf("default wallets after update = %v, want [%d]", ids, second.ID)
+	}
+}
+
+func TestWalletInsertError(t *testing.T) {
+	duplicate := &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: walletAddressConstraint}
+	otherErr := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"address unique violation", duplicate, ErrDuplicateAddress},
+		{"wrapped address unique violation", fmt.Errorf("insert: %w", duplicate), ErrDuplicateAddress},
+		{"other unique constraint", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "wallets_pkey"}, nil},
+		{"other error code", &pgconn.PgError{Code: "23503", ConstraintName: walletAddressConstraint}, nil},
+		{"non-postgres error", otherErr, otherErr},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := walletInsertError(tt.err)
+			want := tt.want
+			if want == nil {
+				want = tt.err // 未识别的错误原样返回
+			}
+			if got != want {
+				t.Errorf("walletInsertError(%v) = %v, want %v", tt.err, got, want)
+			}
+			if errors.Is(got, ErrDuplicateAddress) && !errors.Is(got, apperr.ErrConflict) {
+				t.Errorf("ErrDuplicateAddress is not a conflict error")
+			}
+		})
+	}
+}
+
+func TestWalletAddressUnique(t *testing.T) {
+	ctx := context.Background()
+	db := testutil.NewDB(t)
+	wallet := createWallet(t, db, createUser(t, db).ID)
+
+	duplicate := &models.Wallet{
+		UserID:              createUser(t, db).ID,
+		Address:             wallet.Address,
+		ChainID:             wallet.ChainID,
+		Balance:             "0",
+		PrivateKeyEncrypted: "unused",
+	}
+	if err := NewWalletRepository(db).Create(ctx, duplicate); err == nil {
+		t.Fatal("duplicate address inserted, want unique constraint violation")
+	}
+}
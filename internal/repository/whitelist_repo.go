@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+)
+
+// WhitelistRepository 转账白名单数据访问层
+type WhitelistRepository struct {
+	db *gorm.DB
+}
+
+// NewWhitelistRepository 创建白名单仓库实例
+func NewWhitelistRepository(db *gorm.DB) *WhitelistRepository {
+	return &WhitelistRepository{db: db}
+}
+
+// Create 添加白名单条目
+func (r *WhitelistRepository) Create(ctx context.Context, entry *models.WhitelistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// GetByWalletID 查询钱包的所有白名单条目（含冷静期内的条目）
+func (r *WhitelistRepository) GetByWalletID(ctx context.Context, walletID uint) ([]*models.WhitelistEntry, error) {
+	var entries []*models.WhitelistEntry
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ?", walletID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetByAddress 查询钱包中指定地址的白名单条目（地址不区分大小写）
+func (r *WhitelistRepository) GetByAddress(ctx context.Context, walletID uint, address string) (*models.WhitelistEntry, error) {
+	var entry models.WhitelistEntry
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ? AND LOWER(address) = ?", walletID, strings.ToLower(address)).
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.NotFound("whitelist entry")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Delete 删除钱包的白名单条目，返回被删除的条目
+func (r *WhitelistRepository) Delete(ctx context.Context, walletID uint, id uint) (*models.WhitelistEntry, error) {
+	var entry models.WhitelistEntry
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("wallet_id = ?", walletID).
+		Delete(&entry, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperr.NotFound("whitelist entry")
+	}
+	return &entry, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// ActivityService 钱包动态服务（合并交易与钱包变更记录）
+type ActivityService struct {
+	activityRepo   *repository.ActivityRepository
+	txRepo         *repository.TransactionRepository
+	walletRepo     *repository.WalletRepository
+	contactService *ContactService
+}
+
+// NewActivityService 创建钱包动态服务实例
+func NewActivityService(
+	activityRepo *repository.ActivityRepository,
+	txRepo *repository.TransactionRepository,
+	walletRepo *repository.WalletRepository,
+	contactService *ContactService,
+) *ActivityService {
+	return &ActivityService{
+		activityRepo:   activityRepo,
+		txRepo:         txRepo,
+		walletRepo:     walletRepo,
+		contactService: contactService,
+	}
+}
+
+// Record 记录钱包变更（失败仅记录日志，不影响主流程）
+func (s *ActivityService) Record(ctx context.Context, walletID uint, activityType models.WalletActivityType, details interface{}) {
+	body, err := json.Marshal(details)
+	if err == nil {
+		err = s.activityRepo.Create(ctx, &models.WalletActivity{
+			WalletID: walletID,
+			Type:     activityType,
+			Details:  string(body),
+		})
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to record wallet activity",
+			zap.Uint("wallet_id", walletID),
+			zap.String("type", string(activityType)),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetFeed 获取钱包动态（交易与变更记录按时间倒序合并，游标分页）
+func (s *ActivityService) GetFeed(ctx context.Context, userID uint, address string, req *models.ActivityFeedRequest) (*models.ActivityFeedResponse, error) {
+	// 1. 验证钱包查看权限
+	wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, address, PermView)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 解析游标
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	cursor, err := decodeActivityCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 两个来源各取limit+1条，合并后截取
+	transactions, err := s.txRepo.ListForFeed(ctx, wallet.ID, wallet.Address, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.activityRepo.ListBefore(ctx, wallet.ID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	type feedEntry struct {
+		item   *models.ActivityItem
+		cursor models.ActivityCursor
+	}
+	entries := make([]feedEntry, 0, limit+1)
+	txResponses := make([]*models.TransactionResponse, 0, len(transactions))
+	i, j := 0, 0
+	for len(entries) < limit+1 && (i < len(transactions) || j < len(activities)) {
+		// 按(created_at, source, id)倒序选取较新的一条
+		takeTx := j >= len(activities)
+		if i < len(transactions) && j < len(activities) {
+			tx, act := transactions[i], activities[j]
+			takeTx = tx.CreatedAt.After(act.CreatedAt) || tx.CreatedAt.Equal(act.CreatedAt)
+		}
+
+		if takeTx {
+			tx := transactions[i]
+			i++
+			activityType := models.ActivityTxIncoming
+			if strings.EqualFold(tx.FromAddress, wallet.Address) {
+				activityType = models.ActivityTxOutgoing
+			}
+			resp := transactionResponse(tx)
+			txResponses = append(txResponses, resp)
+			entries = append(entries, feedEntry{
+				item:   &models.ActivityItem{Type: activityType, CreatedAt: tx.CreatedAt, Transaction: resp},
+				cursor: models.ActivityCursor{CreatedAt: tx.CreatedAt, Source: models.ActivitySourceTransaction, ID: tx.ID},
+			})
+			continue
+		}
+
+		act := activities[j]
+		j++
+		entries = append(entries, feedEntry{
+			item:   &models.ActivityItem{Type: act.Type, CreatedAt: act.CreatedAt, Details: json.RawMessage(act.Details)},
+			cursor: models.ActivityCursor{CreatedAt: act.CreatedAt, Source: models.ActivitySourceChange, ID: act.ID},
+		})
+	}
+
+	// 4. 填充联系人名称
+	if err := s.contactService.ResolveNames(ctx, userID, txResponses); err != nil {
+		logger.WithCtx(ctx).Warn("failed to resolve contact names", zap.Error(err))
+	}
+
+	// 5. 多取的一条用于判断是否还有下一页
+	resp := &models.ActivityFeedResponse{Items: make([]*models.ActivityItem, 0, limit)}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		resp.NextCursor = encodeActivityCursor(&entries[limit-1].cursor)
+	}
+	for _, entry := range entries {
+		resp.Items = append(resp.Items, entry.item)
+	}
+
+	return resp, nil
+}
+
+// encodeActivityCursor 编码游标（不透明字符串）
+func encodeActivityCursor(cursor *models.ActivityCursor) string {
+	body, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// decodeActivityCursor 解码游标，空字符串表示首页
+func decodeActivityCursor(value string) (*models.ActivityCursor, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, apperr.Invalid("error.invalid_cursor", "invalid cursor")
+	}
+	var cursor models.ActivityCursor
+	if err := json.Unmarshal(body, &cursor); err != nil || cursor.CreatedAt.IsZero() {
+		return nil, apperr.Invalid("error.invalid_cursor", "invalid cursor")
+	}
+	return &cursor, nil
+}
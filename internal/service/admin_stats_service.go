@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/queue"
+)
+
+const (
+	// adminStatsCacheKey 运维总览统计缓存键（所有实例共用）
+	adminStatsCacheKey = "admin:stats"
+	// adminStatsCacheTTL 运维总览统计缓存时间
+	adminStatsCacheTTL = 60 * time.Second
+	// adminRPCProbeTimeout 单条链RPC探测的超时时间
+	adminRPCProbeTimeout = 3 * time.Second
+)
+
+// adminQueues 运维总览展示积压情况的队列
+var adminQueues = []string{TransactionCreatedQueue}
+
+// AdminStatsService 运维总览统计服务（聚合在数据库中计算，结果在Redis中缓存60秒）
+type AdminStatsService struct {
+	userRepo   *repository.UserRepository
+	walletRepo *repository.WalletRepository
+	txRepo     *repository.TransactionRepository
+	queue      *queue.RabbitMQ
+	cache      cache.Cache
+	clients    map[int]blockchain.BlockchainClient
+}
+
+// NewAdminStatsService 创建运维总览统计服务实例
+func NewAdminStatsService(
+	userRepo *repository.UserRepository,
+	walletRepo *repository.WalletRepository,
+	txRepo *repository.TransactionRepository,
+	queue *queue.RabbitMQ,
+	cache cache.Cache,
+	clients ...blockchain.BlockchainClient,
+) *AdminStatsService {
+	s := &AdminStatsService{
+		userRepo:   userRepo,
+		walletRepo: walletRepo,
+		txRepo:     txRepo,
+		queue:      queue,
+		cache:      cache,
+		clients:    make(map[int]blockchain.BlockchainClient, len(clients)),
+	}
+	for _, client := range clients {
+		s.clients[client.GetChainID()] = client
+	}
+	return s
+}
+
+// GetStats 获取运维总览统计（用户、钱包与交易数量，各链成交量、确认耗时与RPC健康状态，队列积压）
+func (s *AdminStatsService) GetStats(ctx context.Context) (*models.AdminStatsResponse, error) {
+	// 1. 查询缓存
+	if cached, ok := readCache(ctx, s.cache, adminStatsCacheKey); ok {
+		var resp models.AdminStatsResponse
+		if json.Unmarshal([]byte(cached), &resp) == nil {
+			return &resp, nil
+		}
+	}
+
+	// 2. 数据库聚合
+	now := time.Now()
+	users, err := s.userRepo.CountAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	walletCounts, err := s.walletRepo.CountByChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txCounts, err := s.txRepo.CountByChainAndStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := s.txRepo.VolumeByChain(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 按链汇总（包括有数据的链与已连接节点的链）
+	resp := &models.AdminStatsResponse{
+		Users:        users,
+		Transactions: make(map[models.TransactionStatus]int64),
+		GeneratedAt:  now,
+	}
+	chains := make(map[int]*models.AdminChainStats)
+	chain := func(chainID int) *models.AdminChainStats {
+		if c, ok := chains[chainID]; ok {
+			return c
+		}
+		c := &models.AdminChainStats{
+			ChainID:      chainID,
+			Transactions: make(map[models.TransactionStatus]int64),
+			Volume24h:    "0",
+			Volume7d:     "0",
+		}
+		chains[chainID] = c
+		return c
+	}
+	for _, wc := range walletCounts {
+		chain(wc.ChainID).Wallets = wc.Count
+		resp.Wallets += wc.Count
+	}
+	for _, tc := range txCounts {
+		chain(tc.ChainID).Transactions[tc.Status] = tc.Count
+		resp.Transactions[tc.Status] += tc.Count
+	}
+	for _, v := range volumes {
+		c := chain(v.ChainID)
+		c.Volume24h = v.Volume24h
+		c.Volume7d = v.Volume7d
+		c.AvgConfirmationSeconds = v.AvgConfirmationSeconds
+	}
+	for chainID := range s.clients {
+		chain(chainID)
+	}
+
+	// 4. 并行探测RPC节点
+	var wg sync.WaitGroup
+	for chainID, client := range s.clients {
+		c := chains[chainID]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.RPC = probeRPC(ctx, client)
+		}()
+	}
+	wg.Wait()
+
+	resp.Chains = make([]*models.AdminChainStats, 0, len(chains))
+	for _, c := range chains {
+		resp.Chains = append(resp.Chains, c)
+	}
+	sort.Slice(resp.Chains, func(i, j int) bool { return resp.Chains[i].ChainID < resp.Chains[j].ChainID })
+
+	// 5. 队列积压（RabbitMQ不可用时记录错误，不影响其他统计）
+	for _, name := range adminQueues {
+		resp.Queues = append(resp.Queues, s.inspectQueue(name))
+	}
+
+	// 6. 写入缓存
+	if data, err := json.Marshal(resp); err == nil {
+		if err := s.cache.Set(ctx, adminStatsCacheKey, string(data), adminStatsCacheTTL); err != nil {
+			logger.WithCtx(ctx).Warn("failed to cache admin stats", zap.Error(err))
+		}
+	}
+	return resp, nil
+}
+
+// inspectQueue 查询单个队列的积压情况
+func (s *AdminStatsService) inspectQueue(name string) *models.AdminQueueStats {
+	result := &models.AdminQueueStats{Queue: name}
+	stats, err := s.queue.Inspect(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Messages = stats.Messages
+	result.Consumers = stats.Consumers
+	result.Retrying = stats.Retrying
+	result.Dead = stats.Dead
+	return result
+}
+
+// probeRPC 查询最新区块号与延迟，多节点客户端同时返回各节点状态
+func probeRPC(ctx context.Context, client blockchain.BlockchainClient) *models.AdminRPCHealth {
+	ctx, cancel := context.WithTimeout(ctx, adminRPCProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	block, err := client.GetBlockNumber(ctx)
+	health := &models.AdminRPCHealth{
+		Status:      "up",
+		LatestBlock: block,
+		LatencyMs:   time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		health.Status = "down"
+		health.Error = err.Error()
+	}
+
+	if reporter, ok := client.(blockchain.EndpointReporter); ok {
+		for _, ep := range reporter.Endpoints() {
+			health.Endpoints = append(health.Endpoints, &models.AdminRPCEndpoint{
+				Name:        ep.Name,
+				Healthy:     ep.Healthy,
+				LatestBlock: ep.LatestBlock,
+				LastError:   ep.LastError,
+			})
+		}
+	}
+	return health
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+)
+
+// apiKeyPrefix API Key固定前缀，格式：cwa_<prefix>_<secret>
+const apiKeyPrefix = "cwa_"
+
+// ErrInvalidAPIKey API Key无效
+var ErrInvalidAPIKey = apperr.New("error.invalid_api_key", "invalid api key")
+
+// APIKeyService API Key服务
+type APIKeyService struct {
+	apiKeyRepo *repository.APIKeyRepository
+}
+
+// NewAPIKeyService 创建API Key服务实例
+func NewAPIKeyService(apiKeyRepo *repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateKey 创建API Key，返回记录与完整密钥（完整密钥仅此一次可见）
+func (s *APIKeyService) CreateKey(ctx context.Context, userID uint, req *models.APIKeyCreateRequest) (*models.APIKey, string, error) {
+	// 1. 生成前缀与密钥
+	prefix, err := utils.GenerateRandomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := utils.GenerateRandomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	rawKey := apiKeyPrefix + prefix + "_" + secret
+
+	// 2. 默认读写权限
+	scope := req.Scope
+	if scope == "" {
+		scope = models.APIKeyScopeWrite
+	}
+
+	// 3. 仅保存哈希
+	key := &models.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: utils.SHA256Hex(rawKey),
+		Scope:   scope,
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, rawKey, nil
+}
+
+// ListKeys 查询用户的API Key
+func (s *APIKeyService) ListKeys(ctx context.Context, userID uint) ([]*models.APIKey, error) {
+	return s.apiKeyRepo.GetByUserID(ctx, userID)
+}
+
+// RevokeKey 吊销API Key
+func (s *APIKeyService) RevokeKey(ctx context.Context, userID uint, id uint) error {
+	deleted, err := s.apiKeyRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperr.NotFound("api key")
+	}
+	return nil
+}
+
+// Authenticate 校验API Key，返回对应记录
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	// 1. 解析前缀
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	parts := strings.SplitN(strings.TrimPrefix(rawKey, apiKeyPrefix), "_", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// 2. 查询并以常量时间比较哈希
+	key, err := s.apiKeyRepo.GetByPrefix(ctx, parts[0])
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(utils.SHA256Hex(rawKey))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// 3. 记录最后使用时间
+	now := time.Now()
+	if err := s.apiKeyRepo.UpdateLastUsed(ctx, key.ID, now); err != nil {
+		logger.WithCtx(ctx).Warn("failed to update api key last used time",
+			zap.Uint("api_key_id", key.ID),
+			zap.Error(err),
+		)
+	}
+	key.LastUsedAt = &now
+
+	return key, nil
+}
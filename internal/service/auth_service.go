@@ -2,31 +2,118 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/geoip"
 )
 
+// ErrTokenRevoked Token已被吊销
+var ErrTokenRevoked = apperr.New("error.token_revoked", "token has been revoked")
+
+// ErrUsernameTaken 用户名已被占用
+var ErrUsernameTaken = apperr.New("error.username_taken", "username already exists")
+
+// ErrEmailTaken 邮箱已被注册
+var ErrEmailTaken = apperr.New("error.email_taken", "email already exists")
+
+// ErrInvalidCredentials 邮箱不存在或密码错误（两种情况不做区分）
+var ErrInvalidCredentials = apperr.New("error.invalid_credentials", "invalid email or password")
+
+// ErrUserDisabled 账号已被停用（密码正确时才返回，避免泄露账号状态）
+var ErrUserDisabled = apperr.Forbidden("error.user_disabled", "this account has been disabled")
+
+// ErrInvalidPassword 当前密码错误
+var ErrInvalidPassword = apperr.New("error.invalid_password", "current password is incorrect")
+
+// ErrWeakPassword 新密码不满足复杂度策略
+var ErrWeakPassword = apperr.New("error.weak_password", "password does not meet the complexity policy")
+
+// ErrPasswordUnchanged 新密码与当前密码相同
+var ErrPasswordUnchanged = apperr.New("error.password_unchanged", "new password must differ from the current password")
+
+// TokenClaims 解析后的Token信息
+type TokenClaims struct {
+	UserID       uint
+	JTI          string
+	TokenVersion int
+	ExpiresAt    time.Time
+}
+
+// sessionListLimit 会话列表返回的最近登录记录数
+const sessionListLimit = 50
+
 // AuthService 认证服务
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtSecret string
-	jwtExpire int // 小时
+	userRepo     *repository.UserRepository
+	loginRepo    *repository.LoginHistoryRepository
+	cache        *cache.RedisCache
+	eventService *EventService
+	geoResolver  geoip.Resolver
+	policy       models.PasswordPolicy
+	jwtSecret    string
+	jwtExpire    int // 小时
 }
 
-// NewAuthService 创建认证服务实例
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret string, jwtExpire int) *AuthService {
+// NewAuthService 创建认证服务实例（IP地理位置默认不解析，可通过SetGeoIPResolver接入；密码策略默认仅要求8位，可通过SetPasswordPolicy替换）
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	loginRepo *repository.LoginHistoryRepository,
+	cache *cache.RedisCache,
+	eventService *EventService,
+	jwtSecret string,
+	jwtExpire int,
+) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpire: jwtExpire,
+		userRepo:     userRepo,
+		loginRepo:    loginRepo,
+		cache:        cache,
+		eventService: eventService,
+		geoResolver:  geoip.Noop{},
+		policy:       models.PasswordPolicy{MinLength: 8},
+		jwtSecret:    jwtSecret,
+		jwtExpire:    jwtExpire,
 	}
 }
 
+// SetGeoIPResolver 设置会话列表使用的IP地理位置解析器
+func (s *AuthService) SetGeoIPResolver(resolver geoip.Resolver) {
+	s.geoResolver = resolver
+}
+
+// SetPasswordPolicy 设置修改密码时使用的复杂度策略
+func (s *AuthService) SetPasswordPolicy(policy models.PasswordPolicy) {
+	s.policy = policy
+}
+
+// tokenTTL Token有效期
+func (s *AuthService) tokenTTL() time.Duration {
+	return time.Duration(s.jwtExpire) * time.Hour
+}
+
+// revokedTokenKey 已吊销Token的缓存键
+func revokedTokenKey(jti string) string {
+	return "jwt:revoked:" + jti
+}
+
+// tokenVersionKey 用户Token版本的缓存键
+func tokenVersionKey(userID uint) string {
+	return fmt.Sprintf("jwt:version:%d", userID)
+}
+
 // Register 用户注册
 func (s *AuthService) Register(ctx context.Context, req *models.UserCreateRequest) (*models.User, error) {
 	// 1. 检查邮箱是否已存在
@@ -35,7 +122,7 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserCreateReques
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailTaken
 	}
 
 	// 2. 检查用户名是否已存在
@@ -44,13 +131,14 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserCreateReques
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameTaken
 	}
 
 	// 3. 创建用户对象
 	user := &models.User{
 		Username: req.Username,
 		Email:    req.Email,
+		Role:     models.UserRoleUser,
 	}
 
 	// 4. 加密密码
@@ -66,35 +154,64 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserCreateReques
 	return user, nil
 }
 
-// Login 用户登录
-func (s *AuthService) Login(ctx context.Context, req *models.UserLoginRequest) (string, *models.User, error) {
+// Login 用户登录（记录登录IP与设备，首次从新IP或新设备登录时发送通知）
+func (s *AuthService) Login(ctx context.Context, req *models.UserLoginRequest, ip, userAgent string) (*models.LoginResponse, error) {
 	// 1. 根据邮箱查询用户
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
 	}
 
 	// 2. 验证密码
 	if !user.CheckPassword(req.Password) {
-		return "", nil, errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
 	}
 
 	// 3. 生成JWT Token
-	token, err := s.GenerateToken(user.ID)
+	token, claims, err := s.issueToken(user.ID, user.TokenVersion)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	return token, user, nil
+	// 4. 记录登录（失败不影响登录）
+	newDevice := s.recordLogin(ctx, user.ID, claims, ip, userAgent)
+
+	return &models.LoginResponse{
+		Token:     token,
+		User:      user.ToResponse(),
+		NewDevice: newDevice,
+	}, nil
 }
 
 // GenerateToken 生成JWT Token
-func (s *AuthService) GenerateToken(userID uint) (string, error) {
+func (s *AuthService) GenerateToken(userID uint, tokenVersion int) (string, error) {
+	token, _, err := s.issueToken(userID, tokenVersion)
+	return token, err
+}
+
+// issueToken 签发JWT Token并返回其标识信息
+func (s *AuthService) issueToken(userID uint, tokenVersion int) (string, *TokenClaims, error) {
+	now := time.Now()
+	info := &TokenClaims{
+		UserID:       userID,
+		JTI:          uuid.NewString(),
+		TokenVersion: tokenVersion,
+		ExpiresAt:    now.Add(s.tokenTTL()),
+	}
+
 	// 创建Claims
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * time.Duration(s.jwtExpire)).Unix(), // 过期时间
-		"iat":     time.Now().Unix(),                                             // 签发时间
+		"jti":     info.JTI,              // Token唯一标识（用于吊销）
+		"ver":     tokenVersion,          // Token版本（用于全部下线）
+		"exp":     info.ExpiresAt.Unix(), // 过期时间
+		"iat":     now.Unix(),            // 签发时间
 	}
 
 	// 创建Token
@@ -103,14 +220,62 @@ func (s *AuthService) GenerateToken(userID uint) (string, error) {
 	// 签名
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, info, nil
+}
+
+// deviceHash User-Agent指纹（只保存摘要，不保存原文）
+func deviceHash(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
 }
 
-// ValidateToken 验证JWT Token
-func (s *AuthService) ValidateToken(tokenString string) (uint, error) {
+// recordLogin 写入登录记录并判断是否为新IP或新设备（首次登录不视为新设备）
+func (s *AuthService) recordLogin(ctx context.Context, userID uint, claims *TokenClaims, ip, userAgent string) bool {
+	log := logger.WithCtx(ctx).With(zap.Uint("user_id", userID))
+	hash := deviceHash(userAgent)
+
+	// 1. 与历史登录比对
+	familiarity, err := s.loginRepo.Familiarity(ctx, userID, ip, hash)
+	if err != nil {
+		log.Warn("failed to check login history", zap.Error(err))
+		return false
+	}
+	newDevice := familiarity.HasHistory && (!familiarity.SeenIP || !familiarity.SeenDevice)
+
+	// 2. 写入登录记录
+	entry := &models.LoginHistory{
+		UserID:       userID,
+		JTI:          claims.JTI,
+		TokenVersion: claims.TokenVersion,
+		IP:           ip,
+		DeviceHash:   hash,
+		NewDevice:    newDevice,
+		ExpiresAt:    claims.ExpiresAt,
+	}
+	if err := s.loginRepo.Create(ctx, entry); err != nil {
+		log.Warn("failed to record login", zap.Error(err))
+	}
+
+	// 3. 新IP或新设备登录时发布事件（通知中心据此提醒用户）
+	if newDevice {
+		event := &models.WalletEvent{
+			Type:    models.EventNewLogin,
+			UserID:  userID,
+			Message: fmt.Sprintf("New sign-in from IP %s", ip),
+		}
+		if err := s.eventService.Publish(ctx, event); err != nil {
+			log.Warn("failed to publish new login event", zap.Error(err))
+		}
+	}
+
+	return newDevice
+}
+
+// ValidateToken 验证JWT Token（签名、有效期、吊销状态与Token版本）
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*TokenClaims, error) {
 	// 解析Token
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// 验证签名方法
@@ -121,30 +286,326 @@ func (s *AuthService) ValidateToken(tokenString string) (uint, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// 验证Token有效性
 	if !token.Valid {
-		return 0, errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	// 提取Claims
-	claims, ok := token.Claims.(jwt.MapClaims)
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("invalid token claims")
+		return nil, errors.New("invalid token claims")
 	}
 
 	// 提取用户ID
-	userID, ok := claims["user_id"].(float64)
+	userID, ok := mapClaims["user_id"].(float64)
 	if !ok {
-		return 0, errors.New("invalid user_id in token")
+		return nil, errors.New("invalid user_id in token")
+	}
+
+	claims := &TokenClaims{UserID: uint(userID)}
+	claims.JTI, _ = mapClaims["jti"].(string)
+	if ver, ok := mapClaims["ver"].(float64); ok {
+		claims.TokenVersion = int(ver)
+	}
+	if exp, err := mapClaims.GetExpirationTime(); err == nil && exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
+
+	// 检查吊销状态
+	if err := s.checkRevocation(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// checkRevocation 检查Token是否被单独吊销或因版本递增而失效（单次Redis往返）
+func (s *AuthService) checkRevocation(ctx context.Context, claims *TokenClaims) error {
+	values, err := s.cache.MGet(ctx, revokedTokenKey(claims.JTI), tokenVersionKey(claims.UserID))
+	if err != nil {
+		return err
+	}
+
+	// 1. 单个Token被吊销
+	if claims.JTI != "" && values[0] != nil {
+		return ErrTokenRevoked
+	}
+
+	// 2. Token版本（缓存未命中时回源数据库并回填）
+	var currentVersion int
+	if cached, ok := values[1].(string); ok {
+		currentVersion, _ = strconv.Atoi(cached)
+	} else {
+		user, err := s.userRepo.GetByID(ctx, claims.UserID)
+		if err != nil {
+			return err
+		}
+		currentVersion = user.TokenVersion
+		s.cache.Set(ctx, tokenVersionKey(claims.UserID), currentVersion, s.tokenTTL())
+	}
+
+	if claims.TokenVersion < currentVersion {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// Logout 吊销当前Token（加入黑名单直至其自然过期）
+func (s *AuthService) Logout(ctx context.Context, claims *TokenClaims) error {
+	if claims.JTI == "" {
+		return errors.New("token cannot be revoked")
 	}
 
-	return uint(userID), nil
+	ttl := time.Until(claims.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.cache.Set(ctx, revokedTokenKey(claims.JTI), 1, ttl+time.Second)
+}
+
+// LogoutAll 吊销用户的所有Token
+func (s *AuthService) LogoutAll(ctx context.Context, userID uint) error {
+	version, err := s.userRepo.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(ctx, tokenVersionKey(userID), version, s.tokenTTL())
+}
+
+// FindUserByEmail 按邮箱查询用户（不校验权限，仅供运维工具使用）
+func (s *AuthService) FindUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.userRepo.GetByEmail(ctx, email)
+}
+
+// SetUserDisabled 停用或启用用户（运维工具使用，operator记录在审计日志中；dryRun时只查询不修改）
+//
+// 停用与启用都会递增Token版本，停用前签发的Token全部失效；停用期间无法登录，API Key无法认证。
+// 用户已处于目标状态时不做修改。
+func (s *AuthService) SetUserDisabled(ctx context.Context, email string, disabled bool, operator string, dryRun bool) (*models.User, error) {
+	// 1. 查询用户
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if (user.DisabledAt != nil) == disabled || dryRun {
+		return user, nil
+	}
+
+	// 2. 更新停用状态并递增Token版本
+	var disabledAt *time.Time
+	if disabled {
+		now := time.Now()
+		disabledAt = &now
+	}
+	version, err := s.userRepo.SetDisabled(ctx, user.ID, disabledAt)
+	if err != nil {
+		return nil, err
+	}
+	user.DisabledAt = disabledAt
+	user.TokenVersion = version
+	if err := s.cache.Set(ctx, tokenVersionKey(user.ID), version, s.tokenTTL()); err != nil {
+		return nil, err
+	}
+
+	// 3. 审计日志
+	logger.WithCtx(ctx).Info("user disabled state changed",
+		zap.Uint("user_id", user.ID),
+		zap.Bool("disabled", disabled),
+		zap.String("operator", operator),
+	)
+	return user, nil
+}
+
+// ListSessions 查询最近的登录会话（标记当前会话与仍然有效的会话）
+func (s *AuthService) ListSessions(ctx context.Context, claims *TokenClaims) ([]*models.SessionResponse, error) {
+	// 1. 查询最近登录记录
+	entries, err := s.loginRepo.ListRecent(ctx, claims.UserID, sessionListLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []*models.SessionResponse{}, nil
+	}
+
+	// 2. 批量查询吊销状态（单次Redis往返）
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = revokedTokenKey(entry.JTI)
+	}
+	revoked, err := s.cache.MGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 组装响应（同一IP只解析一次地理位置）
+	now := time.Now()
+	locations := make(map[string]*geoip.Location)
+	sessions := make([]*models.SessionResponse, len(entries))
+	for i, entry := range entries {
+		location, ok := locations[entry.IP]
+		if !ok {
+			location, err = s.geoResolver.Lookup(ctx, entry.IP)
+			if err != nil {
+				logger.WithCtx(ctx).Warn("failed to resolve login location", zap.String("ip", entry.IP), zap.Error(err))
+			}
+			locations[entry.IP] = location
+		}
+
+		sessions[i] = &models.SessionResponse{
+			ID:        entry.ID,
+			IP:        entry.IP,
+			Device:    entry.DeviceHash[:12],
+			Location:  location,
+			NewDevice: entry.NewDevice,
+			Current:   entry.JTI == claims.JTI,
+			Active:    entry.TokenVersion >= user.TokenVersion && entry.ExpiresAt.After(now) && revoked[i] == nil,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return sessions, nil
+}
+
+// RevokeOtherSessions 下线当前会话以外的所有会话（递增Token版本并为当前会话重新签发Token）
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, claims *TokenClaims) (*models.RevokeSessionsResponse, error) {
+	// 1. 递增Token版本，使所有已签发Token失效
+	if err := s.LogoutAll(ctx, claims.UserID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 为当前会话签发新版本Token
+	token, issued, err := s.issueToken(user.ID, user.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 当前会话的登录记录改为关联新Token
+	if err := s.loginRepo.Reissue(ctx, user.ID, claims.JTI, issued.JTI, issued.TokenVersion, issued.ExpiresAt); err != nil {
+		logger.WithCtx(ctx).Warn("failed to update current session", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	return &models.RevokeSessionsResponse{Token: token}, nil
 }
 
 // GetProfile 获取用户信息
 func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*models.User, error) {
 	return s.userRepo.GetByID(ctx, userID)
 }
+
+// UpdateProfile 更新个人资料（目前仅支持修改用户名，用户名需唯一）
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *models.UserProfileUpdateRequest, ip string) (*models.User, error) {
+	// 1. 查询用户（用户名未变化时直接返回）
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == req.Username {
+		return user, nil
+	}
+
+	// 2. 检查用户名是否已被占用（并发冲突由数据库唯一约束兜底）
+	exists, err := s.userRepo.ExistsByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrUsernameTaken
+	}
+
+	// 3. 保存
+	previous := user.Username
+	if err := s.userRepo.UpdateUsername(ctx, userID, req.Username); err != nil {
+		return nil, err
+	}
+	user.Username = req.Username
+
+	// 4. 审计日志
+	logger.WithCtx(ctx).Info("username changed",
+		zap.Uint("user_id", userID),
+		zap.String("previous", previous),
+		zap.String("username", user.Username),
+		zap.String("ip", ip),
+	)
+
+	return user, nil
+}
+
+// ChangePassword 修改密码（校验当前密码与复杂度策略，递增Token版本使已签发Token全部失效，并为当前会话签发新Token）
+func (s *AuthService) ChangePassword(ctx context.Context, claims *TokenClaims, req *models.ChangePasswordRequest, ip string) (*models.ChangePasswordResponse, error) {
+	log := logger.WithCtx(ctx).With(zap.Uint("user_id", claims.UserID))
+
+	// 1. 校验当前密码
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CheckPassword(req.CurrentPassword) {
+		return nil, ErrInvalidPassword
+	}
+
+	// 2. 校验新密码
+	if req.NewPassword == req.CurrentPassword {
+		return nil, ErrPasswordUnchanged
+	}
+	if err := s.policy.Check(req.NewPassword); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWeakPassword, err)
+	}
+
+	// 3. 重新哈希并递增Token版本
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		return nil, err
+	}
+	version, err := s.userRepo.UpdatePassword(ctx, user.ID, user.Password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, tokenVersionKey(user.ID), version, s.tokenTTL()); err != nil {
+		return nil, err
+	}
+
+	// 4. 为当前会话签发新版本Token
+	token, issued, err := s.issueToken(user.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginRepo.Reissue(ctx, user.ID, claims.JTI, issued.JTI, issued.TokenVersion, issued.ExpiresAt); err != nil {
+		log.Warn("failed to update current session", zap.Error(err))
+	}
+
+	// 5. 审计日志与通知
+	log.Info("password changed", zap.String("ip", ip))
+	event := &models.WalletEvent{
+		Type:    models.EventPasswordChanged,
+		UserID:  user.ID,
+		Message: fmt.Sprintf("Your password was changed from IP %s and all other sessions were signed out", ip),
+	}
+	if err := s.eventService.Publish(ctx, event); err != nil {
+		log.Warn("failed to publish password changed event", zap.Error(err))
+	}
+
+	return &models.ChangePasswordResponse{Token: token}, nil
+}
+
+// IsAdmin 判断用户是否为管理员（每次从数据库读取，撤销角色立即生效）
+func (s *AuthService) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.Role == models.UserRoleAdmin, nil
+}
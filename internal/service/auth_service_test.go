@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// newAuthService 按生产方式组装认证服务
+func (e *testEnv) newAuthService() *AuthService {
+	return NewAuthService(e.userRepo, repository.NewLoginHistoryRepository(e.db), e.redis, e.events, "test-secret", 1)
+}
+
+func TestRegisterAndLoginFailures(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	auth := env.newAuthService()
+
+	const password = "Password123!"
+	user, err := auth.Register(ctx, &models.UserCreateRequest{Username: "alice", Email: "alice@example.com", Password: password})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	// 用户名或邮箱已被注册
+	if _, err := auth.Register(ctx, &models.UserCreateRequest{Username: "alice", Email: "other@example.com", Password: password}); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("duplicate username err = %v, want ErrUsernameTaken", err)
+	}
+	if _, err := auth.Register(ctx, &models.UserCreateRequest{Username: "bob", Email: "alice@example.com", Password: password}); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("duplicate email err = %v, want ErrEmailTaken", err)
+	}
+	var count int64
+	if err := env.db.Model(&models.User{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("users = %d (%v), want 1", count, err)
+	}
+
+	// 密码错误与邮箱不存在返回相同的错误
+	for _, req := range []*models.UserLoginRequest{
+		{Email: "alice@example.com", Password: "Wrong123!"},
+		{Email: "nobody@example.com", Password: password},
+	} {
+		if resp, err := auth.Login(ctx, req, "127.0.0.1", "test"); !errors.Is(err, ErrInvalidCredentials) || resp != nil {
+			t.Errorf("login %s = %v, %v, want ErrInvalidCredentials", req.Email, resp, err)
+		}
+	}
+
+	resp, err := auth.Login(ctx, &models.UserLoginRequest{Email: "alice@example.com", Password: password}, "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	claims, err := auth.ValidateToken(ctx, resp.Token)
+	if err != nil || claims.UserID != user.ID {
+		t.Errorf("token claims = %+v, %v, want user %d", claims, err, user.ID)
+	}
+}
+
+func TestUpdateProfileDuplicateUsername(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	auth := env.newAuthService()
+	user := env.createUser(t)
+	other := env.createUser(t)
+
+	if _, err := auth.UpdateProfile(ctx, user.ID, &models.UserProfileUpdateRequest{Username: other.Username}, "127.0.0.1"); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("taken username err = %v, want ErrUsernameTaken", err)
+	}
+	// 用户名未变化时直接返回
+	if _, err := auth.UpdateProfile(ctx, user.ID, &models.UserProfileUpdateRequest{Username: user.Username}, "127.0.0.1"); err != nil {
+		t.Errorf("unchanged username: %v", err)
+	}
+	updated, err := auth.UpdateProfile(ctx, user.ID, &models.UserProfileUpdateRequest{Username: "renamed"}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("update profile: %v", err)
+	}
+	saved, err := env.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if updated.Username != "renamed" || saved.Username != "renamed" {
+		t.Errorf("username = %q (saved %q), want renamed", updated.Username, saved.Username)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	auth := env.newAuthService()
+	const password = "Password123!"
+	if _, err := auth.Register(ctx, &models.UserCreateRequest{Username: "carol", Email: "carol@example.com", Password: password}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	login := func(password string) (*models.LoginResponse, error) {
+		return auth.Login(ctx, &models.UserLoginRequest{Email: "carol@example.com", Password: password}, "127.0.0.1", "test")
+	}
+	current, err := login(password)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	other, err := login(password)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	claims, err := auth.ValidateToken(ctx, current.Token)
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     models.ChangePasswordRequest
+		wantErr error
+	}{
+		{"wrong current password", models.ChangePasswordRequest{CurrentPassword: "Wrong123!", NewPassword: "Another123!"}, ErrInvalidPassword},
+		{"unchanged password", models.ChangePasswordRequest{CurrentPassword: password, NewPassword: password}, ErrPasswordUnchanged},
+		{"too short", models.ChangePasswordRequest{CurrentPassword: password, NewPassword: "short"}, ErrWeakPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := auth.ChangePassword(ctx, claims, &tt.req, "127.0.0.1"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	// 校验失败时旧Token仍然有效
+	if _, err := auth.ValidateToken(ctx, other.Token); err != nil {
+		t.Fatalf("token after rejected changes: %v", err)
+	}
+
+	// 修改后旧Token全部失效，当前会话使用新Token，新密码可以登录
+	resp, err := auth.ChangePassword(ctx, claims, &models.ChangePasswordRequest{CurrentPassword: password, NewPassword: "Another123!"}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+	for name, token := range map[string]string{"current": current.Token, "other": other.Token} {
+		if _, err := auth.ValidateToken(ctx, token); err == nil {
+			t.Errorf("%s session token still valid after password change", name)
+		}
+	}
+	if _, err := auth.ValidateToken(ctx, resp.Token); err != nil {
+		t.Errorf("reissued token: %v", err)
+	}
+	if _, err := login(password); err == nil {
+		t.Error("login with the old password succeeded")
+	}
+	if _, err := login("Another123!"); err != nil {
+		t.Errorf("login with the new password: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// WalletPermission 钱包操作权限
+type WalletPermission int
+
+const (
+	PermView   WalletPermission = iota // 查看余额与交易记录（viewer及以上）
+	PermSend                           // 发起转账（member及以上，member受组织单笔限额约束）
+	PermManage                         // 修改钱包设置（admin及以上）
+)
+
+// minRole 各权限所需的最低组织角色
+var minRole = map[WalletPermission]models.OrgRole{
+	PermView:   models.OrgRoleViewer,
+	PermSend:   models.OrgRoleMember,
+	PermManage: models.OrgRoleAdmin,
+}
+
+var (
+	// ErrPermissionDenied 用户可以访问钱包但角色权限不足
+	ErrPermissionDenied = apperr.Forbidden("error.permission_denied", "insufficient permissions for this wallet")
+	// ErrMemberSendLimitExceeded 转账金额超过组织对member角色的单笔限额
+	ErrMemberSendLimitExceeded = apperr.New("error.member_send_limit_exceeded", "amount exceeds the organization's member send limit")
+)
+
+// authorizeWallet 校验用户对钱包的权限：个人钱包仅所有者可操作，组织钱包按成员角色判断
+// 无任何访问权限时返回ErrWalletNotFound（不泄露钱包归属），有查看权限但角色不足时返回ErrPermissionDenied
+func authorizeWallet(ctx context.Context, walletRepo *repository.WalletRepository, userID uint, wallet *models.Wallet, perm WalletPermission) (*models.OrgMembership, error) {
+	// 1. 个人钱包
+	if wallet.OrgID == nil {
+		if wallet.UserID != userID {
+			return nil, ErrWalletNotFound
+		}
+		return nil, nil
+	}
+
+	// 2. 组织钱包：按成员角色判断
+	membership, err := walletRepo.GetMembership(ctx, *wallet.OrgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return nil, ErrWalletNotFound
+	}
+	if membership.Role.Rank() < minRole[perm].Rank() {
+		return nil, ErrPermissionDenied
+	}
+	return membership, nil
+}
+
+// loadAuthorizedWallet 按地址查询钱包并校验用户权限（钱包未收录与无访问权限统一返回ErrWalletNotFound）
+func loadAuthorizedWallet(ctx context.Context, walletRepo *repository.WalletRepository, userID uint, address string, perm WalletPermission) (*models.Wallet, *models.OrgMembership, error) {
+	wallet, err := walletRepo.GetByAddress(ctx, address)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil, ErrWalletNotFound
+		}
+		return nil, nil, err
+	}
+	membership, err := authorizeWallet(ctx, walletRepo, userID, wallet, perm)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wallet, membership, nil
+}
+
+// checkMemberSendLimit 校验member角色的单笔转账限额（个人钱包与admin及以上角色不受限）
+func checkMemberSendLimit(membership *models.OrgMembership, amount *big.Int) error {
+	if membership == nil || membership.Role != models.OrgRoleMember || membership.Organization.MemberSendLimitWei == "" {
+		return nil
+	}
+
+	limit, ok := new(big.Int).SetString(membership.Organization.MemberSendLimitWei, 10)
+	if ok && amount.Cmp(limit) > 0 {
+		return ErrMemberSendLimitExceeded
+	}
+	return nil
+}
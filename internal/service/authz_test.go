@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/testutil"
+)
+
+func TestForeignWalletLooksUnknown(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	owner := env.createUser(t)
+	other := env.createUser(t)
+	wallet := env.createWallet(t, owner.ID, ether(10))
+	unknown := "0x" + "22222222222222222222222222222222222222aa"
+
+	tokens := NewTokenService(repository.NewTokenRepository(env.db), env.walletRepo, blockchain.NewClients(env.chain), env.redis, time.Minute, 4)
+	stats := NewStatsService(env.txRepo, env.walletRepo, env.redis)
+	exports := env.newExportService(t)
+	history := NewBalanceHistoryService(repository.NewBalanceSnapshotRepository(env.db), env.walletRepo, env.wallets)
+	recurring := NewRecurringPaymentService(repository.NewRecurringPaymentRepository(env.db), env.walletRepo, env.txs, env.events, 3)
+	enabled := true
+
+	// 其他用户对钱包的每个操作都与不存在的地址返回相同的错误（404），不泄露钱包是否存在
+	calls := []struct {
+		name string
+		call func(address string) error
+	}{
+		{"get wallet", func(address string) error {
+			_, err := env.wallets.GetWalletByAddress(ctx, other.ID, address)
+			return err
+		}},
+		{"get balance", func(address string) error {
+			_, err := env.wallets.GetBalance(ctx, other.ID, address)
+			return err
+		}},
+		{"refresh balance", func(address string) error {
+			_, err := env.wallets.RefreshBalance(ctx, other.ID, address)
+			return err
+		}},
+		{"update wallet", func(address string) error {
+			return env.wallets.UpdateWallet(ctx, other.ID, address, "renamed")
+		}},
+		{"update settings", func(address string) error {
+			_, err := env.wallets.UpdateSettings(ctx, other.ID, address, &models.WalletSettingsRequest{WhitelistEnabled: &enabled})
+			return err
+		}},
+		{"update limits", func(address string) error {
+			_, err := env.wallets.UpdateLimits(ctx, other.ID, address, &models.WalletLimitsRequest{DailyTxLimit: 1})
+			return err
+		}},
+		{"get approval policy", func(address string) error {
+			_, err := env.wallets.GetApprovalPolicy(ctx, other.ID, address)
+			return err
+		}},
+		{"update approval policy", func(address string) error {
+			_, err := env.wallets.UpdateApprovalPolicy(ctx, other.ID, address, &models.ApprovalPolicyRequest{ThresholdWei: "1", RequiredApprovals: 1, ApproverIDs: []uint{other.ID}})
+			return err
+		}},
+		{"delete wallet", func(address string) error {
+			return env.wallets.DeleteWallet(ctx, other.ID, address)
+		}},
+		{"add whitelist entry", func(address string) error {
+			_, err := env.whitelist.AddEntry(ctx, other.ID, address, &models.WhitelistAddRequest{Address: recipient})
+			return err
+		}},
+		{"list whitelist entries", func(address string) error {
+			_, err := env.whitelist.ListEntries(ctx, other.ID, address)
+			return err
+		}},
+		{"remove whitelist entry", func(address string) error {
+			return env.whitelist.RemoveEntry(ctx, other.ID, address, 1)
+		}},
+		{"send transaction", func(address string) error {
+			_, err := env.txs.SendTransaction(ctx, other.ID, &models.TransactionCreateRequest{FromAddress: address, ToAddress: recipient, Amount: "1000", ChainID: testutil.ChainID})
+			return err
+		}},
+		{"send contract transaction", func(address string) error {
+			_, err := env.txs.SendContractTransaction(ctx, other.ID, &models.ContractTransactionRequest{FromAddress: address, ContractAddress: recipient, Method: "approve(address,uint256)", Args: []interface{}{recipient, "1"}, ChainID: testutil.ChainID})
+			return err
+		}},
+		{"list transactions", func(address string) error {
+			_, err := env.txs.ListTransactions(ctx, other.ID, &models.TransactionListRequest{WalletAddress: address})
+			return err
+		}},
+		{"activity feed", func(address string) error {
+			_, err := env.activity.GetFeed(ctx, other.ID, address, &models.ActivityFeedRequest{})
+			return err
+		}},
+		{"balance history", func(address string) error {
+			_, err := history.GetHistory(ctx, other.ID, address, &models.BalanceHistoryRequest{})
+			return err
+		}},
+		{"wallet tokens", func(address string) error {
+			_, err := tokens.GetWalletTokens(ctx, other.ID, address)
+			return err
+		}},
+		{"transaction stats", func(address string) error {
+			_, err := stats.GetTransactionStats(ctx, other.ID, &models.TransactionStatsRequest{WalletAddress: address})
+			return err
+		}},
+		{"export transactions", func(address string) error {
+			_, err := exports.PrepareExport(ctx, other.ID, &models.TransactionExportRequest{WalletAddress: address})
+			return err
+		}},
+		{"create recurring payment", func(address string) error {
+			_, err := recurring.CreatePayment(ctx, other.ID, &models.RecurringPaymentCreateRequest{FromAddress: address, ToAddress: recipient, Amount: "1000", ChainID: testutil.ChainID, Schedule: "daily"})
+			return err
+		}},
+	}
+
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(wallet.Address); !errors.Is(err, ErrWalletNotFound) {
+				t.Errorf("foreign wallet err = %v, want ErrWalletNotFound", err)
+			}
+			if err := tt.call(unknown); !errors.Is(err, ErrWalletNotFound) {
+				t.Errorf("unknown wallet err = %v, want ErrWalletNotFound", err)
+			}
+		})
+	}
+
+	// 钱包未被修改，也没有发出交易
+	saved, err := env.wallets.GetWalletByAddress(ctx, owner.ID, wallet.Address)
+	if err != nil {
+		t.Fatalf("owner get wallet: %v", err)
+	}
+	if saved.Name != wallet.Name || saved.WhitelistEnabled {
+		t.Errorf("wallet = name %q whitelist %t, want unchanged", saved.Name, saved.WhitelistEnabled)
+	}
+	if sent := env.chain.SentTransactions(); len(sent) != 0 {
+		t.Errorf("broadcast transactions = %d, want 0", len(sent))
+	}
+}
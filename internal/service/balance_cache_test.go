@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+func TestBalanceCacheInvalidatedOnSendAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	from := env.createWallet(t, user.ID, ether(10))
+	to := env.createWallet(t, user.ID, ether(0))
+	other := env.createWallet(t, user.ID, ether(1))
+
+	// 写入一个与链上余额都不同的旧值（金额足够发送），失效后缓存要么不存在，要么已被异步刷新为链上余额
+	stale := ether(50).String()
+	cacheBalances := func() {
+		t.Helper()
+		for _, wallet := range []*models.Wallet{from, to, other} {
+			if err := env.redis.Set(ctx, balanceCacheKey(wallet.Address), stale, 300); err != nil {
+				t.Fatalf("set cache: %v", err)
+			}
+		}
+	}
+	// assertStale 校验各钱包余额缓存是否仍为旧值
+	assertStale := func(stage string, want map[*models.Wallet]bool) {
+		t.Helper()
+		for wallet, wantStale := range want {
+			cached, err := env.redis.Get(ctx, balanceCacheKey(wallet.Address))
+			if isStale := err == nil && cached == stale; isStale != wantStale {
+				t.Errorf("%s: %s stale = %v, want %v", stage, wallet.Address, isStale, wantStale)
+			}
+		}
+	}
+
+	// 广播后失效发送方与收款方（本系统钱包）的余额缓存，无关钱包不受影响
+	cacheBalances()
+	tx, err := env.txs.SendTransaction(ctx, user.ID, &models.TransactionCreateRequest{
+		FromAddress: from.Address,
+		ToAddress:   to.Address,
+		Amount:      ether(1).String(),
+		ChainID:     testutil.ChainID,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	assertStale("after send", map[*models.Wallet]bool{from: false, to: false, other: true})
+
+	// 最终确认后再次失效
+	cacheBalances()
+	env.chain.SetReceipt(tx.TxHash, types.ReceiptStatusSuccessful, 100, 21000)
+	env.chain.SetBlockNumber(100)
+	if err := env.txs.MonitorTransaction(ctx, tx.TxHash); err != nil {
+		t.Fatalf("monitor: %v", err)
+	}
+	assertStale("after confirmation", map[*models.Wallet]bool{from: false, to: false, other: true})
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+const (
+	// balanceHistoryMaxPoints 单次查询返回的最大点数
+	balanceHistoryMaxPoints = 1000
+	// balanceSnapshotBatch 定时快照每批处理的钱包数
+	balanceSnapshotBatch = 500
+	// balanceSnapshotLockName 定时快照锁名称（多副本部署时同一时刻只有一个副本执行）
+	balanceSnapshotLockName = "balance-snapshots"
+	// balanceSnapshotLockTTL 定时快照锁有效期（执行期间自动续期）
+	balanceSnapshotLockTTL = 30 * time.Second
+)
+
+var (
+	// ErrInvalidTimeRange 查询的开始时间不早于结束时间
+	ErrInvalidTimeRange = apperr.New("error.invalid_time_range", "from must be before to")
+	// ErrTimeRangeTooLarge 查询范围包含的点数超过上限
+	ErrTimeRangeTooLarge = apperr.New("error.time_range_too_large", "time range too large for the requested resolution")
+)
+
+// BalanceHistoryService 余额历史服务（记录余额快照并生成余额曲线）
+type BalanceHistoryService struct {
+	snapshotRepo  *repository.BalanceSnapshotRepository
+	walletRepo    *repository.WalletRepository
+	walletService *WalletService
+}
+
+// NewBalanceHistoryService 创建余额历史服务实例
+func NewBalanceHistoryService(
+	snapshotRepo *repository.BalanceSnapshotRepository,
+	walletRepo *repository.WalletRepository,
+	walletService *WalletService,
+) *BalanceHistoryService {
+	return &BalanceHistoryService{
+		snapshotRepo:  snapshotRepo,
+		walletRepo:    walletRepo,
+		walletService: walletService,
+	}
+}
+
+// Record 记录钱包当前小时的余额快照（同一小时内多次刷新只保留最后一次，失败仅记录日志）
+func (s *BalanceHistoryService) Record(ctx context.Context, wallet *models.Wallet, balance *big.Int) {
+	snapshot := s.newSnapshot(ctx, wallet, models.SnapshotHourly, balance, time.Now())
+	if err := s.snapshotRepo.Upsert(ctx, []*models.BalanceSnapshot{snapshot}); err != nil {
+		logger.WithCtx(ctx).Warn("failed to record balance snapshot",
+			zap.Uint("wallet_id", wallet.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// newSnapshot 构造快照（附带当前价格下的美元估值）
+func (s *BalanceHistoryService) newSnapshot(ctx context.Context, wallet *models.Wallet, resolution models.SnapshotResolution, balance *big.Int, at time.Time) *models.BalanceSnapshot {
+	snapshot := &models.BalanceSnapshot{
+		WalletID:   wallet.ID,
+		Resolution: resolution,
+		TakenAt:    resolution.Bucket(at),
+		Balance:    balance.String(),
+	}
+	if usd, ok := s.walletService.ValueInUSD(ctx, wallet.ChainID, balance); ok {
+		snapshot.USDValue = &usd
+	}
+	return snapshot
+}
+
+// SnapshotAll 为所有钱包记录当天的余额快照（使用数据库中的最新余额）
+func (s *BalanceHistoryService) SnapshotAll(ctx context.Context) (int, error) {
+	now := time.Now()
+	total := 0
+	err := s.walletRepo.FindInBatches(ctx, balanceSnapshotBatch, func(wallets []*models.Wallet) error {
+		snapshots := make([]*models.BalanceSnapshot, len(wallets))
+		for i, wallet := range wallets {
+			snapshots[i] = s.newSnapshot(ctx, wallet, models.SnapshotDaily, utils.DecimalToWei(wallet.Balance), now)
+		}
+		total += len(snapshots)
+		return s.snapshotRepo.Upsert(ctx, snapshots)
+	})
+	return total, err
+}
+
+// Rollup 将超过保留时长的小时快照降采样为天快照
+func (s *BalanceHistoryService) Rollup(ctx context.Context, hourlyRetention time.Duration) (int64, error) {
+	return s.snapshotRepo.RollupHourly(ctx, models.SnapshotDaily.Bucket(time.Now().Add(-hourlyRetention)))
+}
+
+// GetHistory 查询钱包的余额曲线（按粒度补齐没有快照的时间段，沿用上一个值；首个快照之前的时间段不返回）
+func (s *BalanceHistoryService) GetHistory(ctx context.Context, userID uint, address string, req *models.BalanceHistoryRequest) (*models.BalanceHistoryResponse, error) {
+	// 1. 验证钱包查看权限
+	wallet, err := s.walletService.GetWalletByAddress(ctx, userID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 确定粒度与时间范围
+	resolution := req.Resolution
+	if resolution == "" {
+		resolution = models.SnapshotHourly
+	}
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := req.From
+	if from.IsZero() {
+		if resolution == models.SnapshotDaily {
+			from = to.AddDate(0, 0, -30)
+		} else {
+			from = to.Add(-24 * time.Hour)
+		}
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidTimeRange
+	}
+	step := resolution.Duration()
+	start := resolution.Bucket(from)
+	if to.Sub(start) > step*balanceHistoryMaxPoints {
+		return nil, ErrTimeRangeTooLarge
+	}
+
+	// 3. 查询范围内的快照（天粒度同时合并尚未降采样的小时快照，以每天最后一条为准）
+	snapshots, err := s.snapshotRepo.ListRange(ctx, wallet.ID, resolution, start, to)
+	if err != nil {
+		return nil, err
+	}
+	if resolution == models.SnapshotDaily {
+		hourly, err := s.snapshotRepo.ListRange(ctx, wallet.ID, models.SnapshotHourly, start, to)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, hourly...)
+	}
+	byBucket := make(map[time.Time]*models.BalanceSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		byBucket[resolution.Bucket(snapshot.TakenAt)] = snapshot
+	}
+
+	// 4. 以范围之前的最后一个快照作为初始值，逐段补齐
+	current, err := s.snapshotRepo.LatestBefore(ctx, wallet.ID, start)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]*models.BalanceHistoryPoint, 0, len(byBucket))
+	for t := start; t.Before(to); t = t.Add(step) {
+		if snapshot, ok := byBucket[t]; ok {
+			current = snapshot
+		}
+		if current == nil {
+			continue
+		}
+		points = append(points, &models.BalanceHistoryPoint{
+			Time:     t,
+			Balance:  utils.DecimalToWei(current.Balance).String(),
+			USDValue: current.USDValue,
+		})
+	}
+
+	return &models.BalanceHistoryResponse{
+		Address:    wallet.Address,
+		Resolution: resolution,
+		From:       start,
+		To:         to,
+		Points:     points,
+	}, nil
+}
+
+// BalanceSnapshotScheduler 定时余额快照（为所有钱包记录天快照，并将过期的小时快照降采样）
+type BalanceSnapshotScheduler struct {
+	historyService  *BalanceHistoryService
+	interval        time.Duration     // 执行间隔
+	hourlyRetention time.Duration     // 小时快照保留时长
+	locker          *cache.RedisCache // 分布式锁（为nil时不加锁）
+}
+
+// NewBalanceSnapshotScheduler 创建定时余额快照任务
+func NewBalanceSnapshotScheduler(historyService *BalanceHistoryService, interval, hourlyRetention time.Duration) *BalanceSnapshotScheduler {
+	return &BalanceSnapshotScheduler{
+		historyService:  historyService,
+		interval:        interval,
+		hourlyRetention: hourlyRetention,
+	}
+}
+
+// SetLocker 设置分布式锁，多个worker副本同时运行时每轮只有持有锁的一方执行
+func (s *BalanceSnapshotScheduler) SetLocker(locker *cache.RedisCache) {
+	s.locker = locker
+}
+
+// Run 启动时执行一次，之后按间隔执行，直到ctx取消
+func (s *BalanceSnapshotScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if s.locker == nil {
+			s.tick(ctx)
+		} else if _, err := s.locker.WithLock(ctx, balanceSnapshotLockName, balanceSnapshotLockTTL, s.tick); err != nil {
+			logger.Warn("failed to acquire balance snapshot lock", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 执行一轮快照与降采样
+func (s *BalanceSnapshotScheduler) tick(ctx context.Context) {
+	wallets, err := s.historyService.SnapshotAll(ctx)
+	if err != nil {
+		logger.Error("failed to snapshot wallet balances", zap.Error(err))
+	}
+	rolledUp, err := s.historyService.Rollup(ctx, s.hourlyRetention)
+	if err != nil {
+		logger.Error("failed to roll up hourly balance snapshots", zap.Error(err))
+	}
+	logger.Info("balance snapshots taken",
+		zap.Int("wallets", wallets),
+		zap.Int64("hourly_rolled_up", rolledUp),
+	)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+)
+
+// BalanceRefresher 后台余额刷新器（有界并发的去重队列，同一地址排队期间多次请求只查询一次，支持JSON-RPC批量请求）
+type BalanceRefresher struct {
+	walletService *WalletService
+	chains        *blockchain.Clients
+	workers       int
+	batchSize     int // 单次请求的最大地址数，1表示逐个查询（节点不支持批量请求时）
+
+	mu      sync.Mutex
+	pending []balanceRefresh    // 按入队顺序等待刷新的地址
+	queued  map[string]struct{} // 已入队的链ID与地址（小写），用于去重
+	wakeup  chan struct{}
+}
+
+// balanceRefresh 等待刷新的钱包地址及其所在链
+type balanceRefresh struct {
+	chainID int
+	address string
+}
+
+// key 去重键
+func (b balanceRefresh) key() string {
+	return fmt.Sprintf("%d:%s", b.chainID, strings.ToLower(b.address))
+}
+
+// NewBalanceRefresher 创建后台余额刷新器（需调用Run启动）
+func NewBalanceRefresher(walletService *WalletService, chains *blockchain.Clients, workers, batchSize int) *BalanceRefresher {
+	return &BalanceRefresher{
+		walletService: walletService,
+		chains:        chains,
+		workers:       workers,
+		batchSize:     batchSize,
+		queued:        make(map[string]struct{}),
+		wakeup:        make(chan struct{}, 1),
+	}
+}
+
+// Enqueue 将链上地址加入刷新队列（不阻塞，已在队列中的地址直接忽略）
+func (r *BalanceRefresher) Enqueue(chainID int, address string) {
+	item := balanceRefresh{chainID: chainID, address: address}
+	key := item.key()
+
+	r.mu.Lock()
+	if _, ok := r.queued[key]; ok {
+		r.mu.Unlock()
+		return
+	}
+	r.queued[key] = struct{}{}
+	r.pending = append(r.pending, item)
+	metrics.BalanceRefreshQueueDepth.Set(float64(len(r.pending)))
+	r.mu.Unlock()
+
+	r.notify()
+}
+
+// notify 唤醒一个空闲的刷新协程
+func (r *BalanceRefresher) notify() {
+	select {
+	case r.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Run 启动刷新协程，阻塞直到ctx取消（退出时未处理的地址被丢弃，余额在缓存过期后按需刷新）
+func (r *BalanceRefresher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work 循环取出一批地址并刷新
+func (r *BalanceRefresher) work(ctx context.Context) {
+	for {
+		chainID, batch := r.take()
+		if len(batch) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.wakeup:
+			}
+			continue
+		}
+		r.refresh(ctx, chainID, batch)
+	}
+}
+
+// take 按入队顺序取出与队首同一条链的最多batchSize个地址（一批只查询一条链，取出后同一地址可再次入队）
+func (r *BalanceRefresher) take() (int, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return 0, nil
+	}
+	chainID := r.pending[0].chainID
+	var batch []string
+	rest := r.pending[:0]
+	for _, item := range r.pending {
+		if item.chainID != chainID || len(batch) == r.batchSize {
+			rest = append(rest, item)
+			continue
+		}
+		batch = append(batch, item.address)
+		delete(r.queued, item.key())
+	}
+	clear(r.pending[len(rest):])
+	r.pending = rest
+	metrics.BalanceRefreshQueueDepth.Set(float64(len(r.pending)))
+
+	// 队列中仍有地址时唤醒其他协程
+	if len(r.pending) > 0 {
+		r.notify()
+	}
+	return chainID, batch
+}
+
+// refresh 查询一批地址在chainID链上的余额并写入数据库与缓存
+func (r *BalanceRefresher) refresh(ctx context.Context, chainID int, batch []string) {
+	metrics.BalanceRefreshBatchSize.Observe(float64(len(batch)))
+
+	client, err := chainClient(r.chains, chainID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to refresh balances",
+			zap.Int("chain_id", chainID),
+			zap.Int("batch_size", len(batch)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	var balances []*big.Int
+	if len(batch) == 1 {
+		var balance *big.Int
+		balance, err = client.GetBalance(ctx, batch[0])
+		balances = []*big.Int{balance}
+	} else {
+		balances, err = client.BatchGetBalances(ctx, batch)
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to refresh balances",
+			zap.Int("chain_id", chainID),
+			zap.Int("batch_size", len(batch)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for i, address := range batch {
+		r.walletService.applyBalance(ctx, chainID, address, balances[i])
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// readCache 读取缓存，键不存在时返回false；Redis不可用等读取错误同样返回false，但会记录日志与指标，
+// 避免缓存故障被当作未命中而悄悄把全部读取压力转移到链节点或数据库
+func readCache(ctx context.Context, c cache.Cache, key string) (string, bool) {
+	value, err := c.Get(ctx, key)
+	switch {
+	case err == nil:
+		return value, true
+	case errors.Is(err, cache.ErrNotFound):
+	default:
+		metrics.CacheReadFailures.WithLabelValues("error").Inc()
+		logger.WithCtx(ctx).Warn("failed to read cache, falling back to source",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// failingCache 所有操作都失败的缓存（模拟Redis不可用）
+type failingCache struct{ err error }
+
+func (c failingCache) Get(ctx context.Context, key string) (string, error) {
+	return "", c.err
+}
+
+func (c failingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.err
+}
+
+func (c failingCache) Delete(ctx context.Context, keys ...string) error {
+	return c.err
+}
+
+func (c failingCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return false, c.err
+}
+
+func (c failingCache) Incr(ctx context.Context, key string) (int64, error) {
+	return 0, c.err
+}
+
+func TestBalanceReadsFallBackWhenCacheFails(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnvWithCache(t, failingCache{err: errors.New("redis: connection refused")})
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(3))
+
+	// 缓存读取失败时从链上查询，不向调用方返回错误
+	balance, err := env.wallets.GetBalance(ctx, user.ID, wallet.Address)
+	if err != nil {
+		t.Fatalf("get balance: %v", err)
+	}
+	if balance.Cmp(ether(3)) != 0 {
+		t.Errorf("balance = %s, want %s", balance, ether(3))
+	}
+
+	// 发送前的余额检查同样回源
+	if _, err := env.txs.SendTransaction(ctx, user.ID, &models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      ether(1).String(),
+		ChainID:     testutil.ChainID,
+	}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
+
+func TestInvalidCachedBalanceFallsBack(t *testing.T) {
+	ctx := context.Background()
+	memory := cache.NewMemoryCache(100)
+	env := newTestEnvWithCache(t, memory)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(3))
+
+	// 无法解析或为负数的缓存值不可信，按链上余额返回并覆盖缓存
+	for _, cached := range []string{"garbage", "-1"} {
+		if err := memory.Set(ctx, balanceCacheKey(wallet.Address), cached, time.Minute); err != nil {
+			t.Fatalf("set cache: %v", err)
+		}
+		balance, err := env.wallets.GetBalance(ctx, user.ID, wallet.Address)
+		if err != nil {
+			t.Fatalf("get balance with cached %q: %v", cached, err)
+		}
+		if balance.Cmp(ether(3)) != 0 {
+			t.Errorf("balance with cached %q = %s, want %s", cached, balance, ether(3))
+		}
+		if value, _ := memory.Get(ctx, balanceCacheKey(wallet.Address)); value != ether(3).String() {
+			t.Errorf("cache after cached %q = %q, want %s", cached, value, ether(3))
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/models"
+)
+
+const (
+	// chainHealthProbeTimeout 单次查询最新区块号的超时时间
+	chainHealthProbeTimeout = 5 * time.Second
+	// defaultChainHealthInterval 未单独设置时检查链头的间隔
+	defaultChainHealthInterval = 15 * time.Second
+)
+
+// ErrChainUnhealthy 链节点长时间未同步到新区块（余额与nonce可能已过期），暂停发送交易
+var ErrChainUnhealthy = apperr.New("error.chain_unhealthy", "chain node unhealthy")
+
+// chainHead 单条链的观测记录
+type chainHead struct {
+	block      uint64
+	advancedAt time.Time // 本地观测到区块号推进的时间（从未成功查询时为监控启动时间）
+	lastError  string
+	degraded   bool // 上一次检查时的状态（用于记录状态变化）
+}
+
+// ChainHealthMonitor 链头监控（定期查询各链最新区块号，超过阈值未推进时将链标记为不健康）
+//
+// 节点同步落后或卡住时不会返回错误，只会持续返回旧区块上的余额与nonce，因此按本地观测到的
+// 区块推进时间判断，而不依赖RPC调用是否成功。
+type ChainHealthMonitor struct {
+	clients    map[int]blockchain.BlockchainClient
+	staleAfter map[int]time.Duration
+	interval   map[int]time.Duration // 各链的检查间隔
+
+	mu    sync.RWMutex
+	heads map[int]*chainHead
+}
+
+// NewChainHealthMonitor 创建链头监控实例（阈值默认为10个出块时间，可通过SetStaleAfter覆盖）
+func NewChainHealthMonitor(clients ...blockchain.BlockchainClient) *ChainHealthMonitor {
+	m := &ChainHealthMonitor{
+		clients:    make(map[int]blockchain.BlockchainClient, len(clients)),
+		staleAfter: make(map[int]time.Duration, len(clients)),
+		interval:   make(map[int]time.Duration, len(clients)),
+		heads:      make(map[int]*chainHead, len(clients)),
+	}
+	now := time.Now()
+	for _, client := range clients {
+		chainID := client.GetChainID()
+		m.clients[chainID] = client
+		m.staleAfter[chainID] = 10 * blockchain.DefaultChainParams(chainID).BlockTime
+		m.interval[chainID] = defaultChainHealthInterval
+		m.heads[chainID] = &chainHead{advancedAt: now}
+	}
+	return m
+}
+
+// SetStaleAfter 设置链头未推进多久后视为不健康
+func (m *ChainHealthMonitor) SetStaleAfter(chainID int, staleAfter time.Duration) {
+	if staleAfter > 0 {
+		m.staleAfter[chainID] = staleAfter
+	}
+}
+
+// SetCheckInterval 设置链的检查间隔（需在Run之前调用）
+func (m *ChainHealthMonitor) SetCheckInterval(chainID int, interval time.Duration) {
+	if interval > 0 {
+		m.interval[chainID] = interval
+	}
+}
+
+// Check 查询所有链的最新区块号并更新状态
+func (m *ChainHealthMonitor) Check(ctx context.Context) {
+	for chainID, client := range m.clients {
+		m.check(ctx, chainID, client)
+	}
+}
+
+// check 查询单条链的最新区块号
+func (m *ChainHealthMonitor) check(ctx context.Context, chainID int, client blockchain.BlockchainClient) {
+	probeCtx, cancel := context.WithTimeout(ctx, chainHealthProbeTimeout)
+	block, err := client.GetBlockNumber(probeCtx)
+	cancel()
+
+	m.mu.Lock()
+	head := m.heads[chainID]
+	if err != nil {
+		head.lastError = err.Error()
+	} else {
+		head.lastError = ""
+		if block > head.block {
+			head.block = block
+			head.advancedAt = time.Now()
+		}
+	}
+	status := m.statusLocked(chainID, head)
+	wasDegraded := head.degraded
+	head.degraded = status.Degraded
+	m.mu.Unlock()
+
+	// 更新指标并在状态变化时记录日志
+	label := strconv.Itoa(chainID)
+	metrics.ChainHeadBlock.WithLabelValues(label).Set(float64(status.LatestBlock))
+	metrics.ChainHeadAge.WithLabelValues(label).Set(status.Age.Seconds())
+	if status.Degraded {
+		metrics.ChainDegraded.WithLabelValues(label).Set(1)
+	} else {
+		metrics.ChainDegraded.WithLabelValues(label).Set(0)
+	}
+	switch {
+	case status.Degraded && !wasDegraded:
+		logger.Warn("chain head stalled, marking chain degraded",
+			zap.Int("chain_id", chainID),
+			zap.Uint64("latest_block", status.LatestBlock),
+			zap.Duration("head_age", status.Age),
+			zap.String("last_error", status.LastError),
+		)
+	case !status.Degraded && wasDegraded:
+		logger.Info("chain head advancing again, chain recovered",
+			zap.Int("chain_id", chainID),
+			zap.Uint64("latest_block", status.LatestBlock),
+		)
+	}
+}
+
+// Run 按各链的检查间隔分别检查所有链，直到ctx取消
+func (m *ChainHealthMonitor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for chainID, client := range m.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.runChain(ctx, chainID, client)
+		}()
+	}
+	wg.Wait()
+}
+
+// runChain 按间隔检查单条链
+func (m *ChainHealthMonitor) runChain(ctx context.Context, chainID int, client blockchain.BlockchainClient) {
+	ticker := time.NewTicker(m.interval[chainID])
+	defer ticker.Stop()
+
+	for {
+		m.check(ctx, chainID, client)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status 获取链头状态（未监控的链返回nil）
+func (m *ChainHealthMonitor) Status(chainID int) *models.ChainHeadStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	head, ok := m.heads[chainID]
+	if !ok {
+		return nil
+	}
+	return m.statusLocked(chainID, head)
+}
+
+// Statuses 获取所有链的链头状态
+func (m *ChainHealthMonitor) Statuses() []*models.ChainHeadStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]*models.ChainHeadStatus, 0, len(m.heads))
+	for chainID, head := range m.heads {
+		statuses = append(statuses, m.statusLocked(chainID, head))
+	}
+	return statuses
+}
+
+// Degraded 链是否被标记为不健康（未监控的链视为健康）
+func (m *ChainHealthMonitor) Degraded(chainID int) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	head, ok := m.heads[chainID]
+	return ok && m.degradedLocked(chainID, head)
+}
+
+// CheckSendable 链不健康时返回ErrChainUnhealthy
+func (m *ChainHealthMonitor) CheckSendable(chainID int) error {
+	if !m.Degraded(chainID) {
+		return nil
+	}
+	status := m.Status(chainID)
+	return fmt.Errorf("%w: chain %d head has not advanced for %s", ErrChainUnhealthy, chainID, status.Age.Truncate(time.Second))
+}
+
+// degradedLocked 链头是否超过阈值未推进（调用方持有锁）
+func (m *ChainHealthMonitor) degradedLocked(chainID int, head *chainHead) bool {
+	staleAfter := m.staleAfter[chainID]
+	return staleAfter > 0 && time.Since(head.advancedAt) > staleAfter
+}
+
+// statusLocked 构建链头状态（调用方持有锁）
+func (m *ChainHealthMonitor) statusLocked(chainID int, head *chainHead) *models.ChainHeadStatus {
+	age := time.Since(head.advancedAt)
+	return &models.ChainHeadStatus{
+		ChainID:     chainID,
+		LatestBlock: head.block,
+		Age:         age,
+		AgeSeconds:  int64(age.Seconds()),
+		StaleAfter:  int64(m.staleAfter[chainID].Seconds()),
+		Degraded:    m.degradedLocked(chainID, head),
+		LastError:   head.lastError,
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+)
+
+// ChainService 链发现服务（已配置的链及其当前发送状态、Gas价格与最新区块号）
+type ChainService struct {
+	gasOracle    *GasOracle
+	chainHealth  *ChainHealthMonitor
+	featureFlags *FeatureFlagService
+}
+
+// NewChainService 创建链发现服务实例
+func NewChainService(gasOracle *GasOracle, chainHealth *ChainHealthMonitor, featureFlags *FeatureFlagService) *ChainService {
+	return &ChainService{
+		gasOracle:    gasOracle,
+		chainHealth:  chainHealth,
+		featureFlags: featureFlags,
+	}
+}
+
+// ListChains 按配置顺序返回已配置的链
+//
+// Gas价格只读取Worker刷新的缓存、最新区块号取链头监控的本地观测值，接口不会触发链节点请求。
+func (s *ChainService) ListChains(ctx context.Context) []*models.ChainInfo {
+	sendsAllowed := s.sendsAllowed(ctx)
+	chains := models.Chains()
+	infos := make([]*models.ChainInfo, 0, len(chains))
+	for _, chain := range chains {
+		info := &models.ChainInfo{
+			Chain:        chain,
+			SendsEnabled: sendsAllowed && chain.Enabled && !s.chainHealth.Degraded(chain.ChainID),
+		}
+		if tiers, ok := s.gasOracle.CachedTiers(ctx, chain.ChainID); ok {
+			info.GasPrices = tiers
+		}
+		if status := s.chainHealth.Status(chain.ChainID); status != nil {
+			info.LatestBlock = status.LatestBlock
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// sendsAllowed 发送功能开关已开启且未处于只读维护模式
+func (s *ChainService) sendsAllowed(ctx context.Context) bool {
+	return s.featureFlags.Check(ctx, models.FlagTransactionsSend) == nil && s.featureFlags.CheckWritable(ctx) == nil
+}
+
+// chainClient 获取链的区块链客户端，没有连接该链的节点时返回ErrUnsupportedChain（不能回退到其他链的节点）
+func chainClient(chains *blockchain.Clients, chainID int) (blockchain.BlockchainClient, error) {
+	client, ok := chains.Get(chainID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedChain, chainID)
+	}
+	return client, nil
+}
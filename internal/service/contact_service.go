@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// ContactService 地址簿服务
+type ContactService struct {
+	contactRepo *repository.ContactRepository
+	ensService  *ENSService // ENS解析（为nil时不接受ENS名称）
+}
+
+// NewContactService 创建地址簿服务实例
+func NewContactService(contactRepo *repository.ContactRepository) *ContactService {
+	return &ContactService{
+		contactRepo: contactRepo,
+	}
+}
+
+// SetENSService 设置ENS解析服务，启用后联系人地址可填写ENS名称
+func (s *ContactService) SetENSService(ensService *ENSService) {
+	s.ensService = ensService
+}
+
+// CreateContact 创建联系人
+func (s *ContactService) CreateContact(ctx context.Context, userID uint, req *models.ContactCreateRequest) (*models.Contact, error) {
+	// 1. 解析ENS名称并统一地址格式（EIP-55校验和格式）
+	resolved, _, err := resolveRecipient(ctx, s.ensService, req.ChainID, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	address := common.HexToAddress(resolved).Hex()
+
+	// 2. 同一用户同一链上地址唯一
+	exists, err := s.contactRepo.ExistsByAddress(ctx, userID, address, req.ChainID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperr.Invalid("error.contact_exists", "contact already exists")
+	}
+
+	// 3. 保存联系人
+	contact := &models.Contact{
+		UserID:  userID,
+		Name:    req.Name,
+		Address: address,
+		ChainID: req.ChainID,
+	}
+	if err := s.contactRepo.Create(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// GetContact 获取联系人详情
+func (s *ContactService) GetContact(ctx context.Context, userID uint, id uint) (*models.Contact, error) {
+	return s.contactRepo.GetByID(ctx, userID, id)
+}
+
+// ListContacts 查询用户的联系人列表
+func (s *ContactService) ListContacts(ctx context.Context, userID uint) ([]*models.Contact, error) {
+	return s.contactRepo.GetByUserID(ctx, userID)
+}
+
+// UpdateContact 更新联系人
+func (s *ContactService) UpdateContact(ctx context.Context, userID uint, id uint, req *models.ContactUpdateRequest) (*models.Contact, error) {
+	// 1. 查询联系人
+	contact, err := s.contactRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 应用变更
+	if req.Name != "" {
+		contact.Name = req.Name
+	}
+	if req.ChainID != 0 {
+		contact.ChainID = req.ChainID
+	}
+	if req.Address != "" {
+		resolved, _, err := resolveRecipient(ctx, s.ensService, contact.ChainID, req.Address)
+		if err != nil {
+			return nil, err
+		}
+		contact.Address = common.HexToAddress(resolved).Hex()
+	}
+
+	// 3. 校验唯一性（排除自身）
+	exists, err := s.contactRepo.ExistsByAddress(ctx, userID, contact.Address, contact.ChainID, contact.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperr.Invalid("error.contact_exists", "contact already exists")
+	}
+
+	// 4. 保存
+	if err := s.contactRepo.Update(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// DeleteContact 删除联系人
+func (s *ContactService) DeleteContact(ctx context.Context, userID uint, id uint) error {
+	deleted, err := s.contactRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperr.NotFound("contact")
+	}
+	return nil
+}
+
+// ResolveNames 为交易填充对手方（收款地址）的联系人名称
+func (s *ContactService) ResolveNames(ctx context.Context, userID uint, txs []*models.TransactionResponse) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	// 1. 收集对手方地址
+	addresses := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		addresses = append(addresses, tx.ToAddress)
+	}
+
+	// 2. 批量查询联系人
+	contacts, err := s.contactRepo.GetByAddresses(ctx, userID, addresses)
+	if err != nil {
+		return err
+	}
+
+	// 3. 按（地址, 链）匹配名称
+	type contactKey struct {
+		address string
+		chainID int
+	}
+	names := make(map[contactKey]string, len(contacts))
+	for _, contact := range contacts {
+		names[contactKey{strings.ToLower(contact.Address), contact.ChainID}] = contact.Name
+	}
+	for _, tx := range txs {
+		tx.ContactName = names[contactKey{strings.ToLower(tx.ToAddress), tx.ChainID}]
+	}
+
+	return nil
+}
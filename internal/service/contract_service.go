@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+)
+
+// ErrInvalidContractCall 合约调用参数错误（ABI、方法或参数不匹配）
+var ErrInvalidContractCall = apperr.New("error.invalid_contract_call", "invalid contract call")
+
+// ContractService 合约交互服务
+type ContractService struct {
+	chains *blockchain.Clients
+}
+
+// NewContractService 创建合约交互服务实例
+func NewContractService(chains *blockchain.Clients) *ContractService {
+	return &ContractService{
+		chains: chains,
+	}
+}
+
+// Call 执行合约只读调用并解码返回值
+func (s *ContractService) Call(ctx context.Context, req *models.ContractCallRequest) (*models.ContractCallResponse, error) {
+	// 1. 解析ABI与方法
+	method, err := blockchain.LoadMethod(req.ABI, req.Method)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+	}
+
+	// 2. 编码调用数据
+	data, err := blockchain.EncodeCall(method, req.Args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+	}
+
+	// 3. 在请求的链上执行eth_call（未指定链时使用默认链）
+	client := s.chains.Default()
+	if req.ChainID != 0 {
+		if client, err = chainClient(s.chains, req.ChainID); err != nil {
+			return nil, err
+		}
+	}
+	var blockNumber *big.Int
+	if req.BlockNumber != nil {
+		blockNumber = big.NewInt(*req.BlockNumber)
+	}
+	result, err := client.CallContract(ctx, req.ContractAddress, data, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 解码返回值
+	values, err := blockchain.DecodeOutputs(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode outputs: %w", err)
+	}
+
+	outputs := make([]*models.ContractOutput, len(values))
+	for i, value := range values {
+		outputs[i] = &models.ContractOutput{
+			Name:  method.Outputs[i].Name,
+			Type:  method.Outputs[i].Type.String(),
+			Value: value,
+		}
+	}
+
+	return &models.ContractCallResponse{
+		ContractAddress: req.ContractAddress,
+		Method:          method.Name,
+		Outputs:         outputs,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/mailer"
+	"crypto-wallet-api/pkg/queue"
+)
+
+// EmailSendQueue 外发邮件队列
+const EmailSendQueue = "email.send"
+
+// EmailService 外发邮件服务：API与Worker将邮件发布到队列，Worker消费后渲染模板并发送
+type EmailService struct {
+	mq         *queue.RabbitMQ
+	queueCodec *QueueCodec
+	mailer     mailer.Mailer
+}
+
+// NewEmailService 创建外发邮件服务实例
+func NewEmailService(mq *queue.RabbitMQ, queueCodec *QueueCodec, m mailer.Mailer) *EmailService {
+	return &EmailService{
+		mq:         mq,
+		queueCodec: queueCodec,
+		mailer:     m,
+	}
+}
+
+// Enqueue 将邮件发布到发送队列（收件人邮箱属于个人信息，配置队列密钥时加密）
+func (s *EmailService) Enqueue(ctx context.Context, to, template string, data map[string]string) error {
+	if !mailer.HasTemplate(template) {
+		return fmt.Errorf("unknown email template %q", template)
+	}
+	payload, err := s.queueCodec.Seal(&models.EmailSendMessage{
+		To:       to,
+		Template: template,
+		Data:     data,
+	})
+	if err != nil {
+		return err
+	}
+	return s.mq.PublishRaw(ctx, EmailSendQueue, payload)
+}
+
+// Deliver 渲染并发送邮件（由Worker调用）：模板不存在、收件人无效或被服务器拒绝时返回包装queue.ErrReject的错误，
+// 直接转入死信队列；连接失败等其他错误由队列重试
+func (s *EmailService) Deliver(ctx context.Context, msg *models.EmailSendMessage) error {
+	log := logger.WithCtx(ctx).With(zap.String("template", msg.Template))
+
+	// 1. 渲染模板
+	email, err := mailer.Render(msg.Template, msg.Data)
+	if err != nil {
+		return s.fail(log, msg.Template, err)
+	}
+	email.To = []string{msg.To}
+
+	// 2. 发送
+	if err := s.mailer.Send(ctx, email); err != nil {
+		return s.fail(log, msg.Template, err)
+	}
+	metrics.EmailsSent.WithLabelValues(msg.Template, "sent").Inc()
+	log.Debug("email sent")
+	return nil
+}
+
+// fail 记录发送失败，不可重试的错误包装queue.ErrReject
+func (s *EmailService) fail(log *zap.Logger, template string, err error) error {
+	if errors.Is(err, mailer.ErrPermanent) {
+		metrics.EmailsSent.WithLabelValues(template, "rejected").Inc()
+		log.Error("email rejected", zap.Error(err))
+		return fmt.Errorf("%w: %v", queue.ErrReject, err)
+	}
+	metrics.EmailsSent.WithLabelValues(template, "retry").Inc()
+	log.Warn("email delivery failed, will retry", zap.Error(err))
+	return err
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/pkg/mailer"
+	"crypto-wallet-api/pkg/queue"
+)
+
+func TestEmailDeliverThroughSMTP(t *testing.T) {
+	ctx := context.Background()
+	server := testutil.NewSMTPServer(t)
+	server.Reject("gone@example.com", "550 mailbox unavailable")
+	m, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:    server.Host,
+		Port:    server.Port,
+		From:    "no-reply@example.com",
+		TLSMode: mailer.TLSModeNone,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new smtp mailer: %v", err)
+	}
+	emails := NewEmailService(nil, NewQueueCodec(nil), m)
+
+	// 通知邮件按模板渲染后送达
+	if err := emails.Deliver(ctx, &models.EmailSendMessage{
+		To:       "alice@example.com",
+		Template: "notification",
+		Data:     map[string]string{"Username": "alice", "Title": "Withdrawal sent"},
+	}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	messages := server.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "alice@example.com" || !strings.Contains(messages[0].Data, "Withdrawal sent") {
+		t.Fatalf("received %+v, want one notification to alice@example.com", messages)
+	}
+
+	// 服务器拒绝收件人或模板不存在时转入死信队列，不重试
+	for _, msg := range []*models.EmailSendMessage{
+		{To: "gone@example.com", Template: "notification"},
+		{To: "alice@example.com", Template: "missing"},
+	} {
+		if err := emails.Deliver(ctx, msg); !errors.Is(err, queue.ErrReject) {
+			t.Errorf("deliver to %s with %s: err = %v, want queue.ErrReject", msg.To, msg.Template, err)
+		}
+	}
+	if n := len(server.Messages()); n != 1 {
+		t.Errorf("received %d messages, want 1", n)
+	}
+}
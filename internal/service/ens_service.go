@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// ErrENSResolution ENS名称无法解析为有效地址（未注册、解析为零地址、当前链不支持或未启用ENS）
+var ErrENSResolution = apperr.New("error.ens_resolution", "ENS name could not be resolved")
+
+// ENSService ENS名称解析服务（正向与反向解析结果缓存在Redis中）
+type ENSService struct {
+	chains        *blockchain.Clients
+	cache         cache.Cache
+	ttl           time.Duration // 解析结果缓存时间
+	reverseLookup bool          // 是否反向解析转入交易的发送方
+}
+
+// NewENSService 创建ENS解析服务实例
+func NewENSService(chains *blockchain.Clients, cache cache.Cache, ttl time.Duration, reverseLookup bool) *ENSService {
+	return &ENSService{
+		chains:        chains,
+		cache:         cache,
+		ttl:           ttl,
+		reverseLookup: reverseLookup,
+	}
+}
+
+// ensNameKey ENS正向解析缓存键
+func ensNameKey(chainID int, name string) string {
+	return fmt.Sprintf("ens:name:%d:%s", chainID, name)
+}
+
+// ensAddressKey ENS反向解析缓存键
+func ensAddressKey(chainID int, address string) string {
+	return fmt.Sprintf("ens:addr:%d:%s", chainID, strings.ToLower(address))
+}
+
+// Resolve 解析ENS名称对应的地址（EIP-55格式），失败时返回ErrENSResolution
+func (s *ENSService) Resolve(ctx context.Context, chainID int, name string) (string, error) {
+	name = blockchain.NormalizeENSName(name)
+
+	// 1. 没有连接节点的链上的名称无法解析
+	client, ok := s.chains.Get(chainID)
+	if !ok {
+		return "", fmt.Errorf("%w: %s: %v", ErrENSResolution, name, blockchain.ErrENSUnsupported)
+	}
+
+	// 2. 先查缓存
+	key := ensNameKey(chainID, name)
+	if address, ok := readCache(ctx, s.cache, key); ok {
+		return address, nil
+	}
+
+	// 3. 链上解析（零地址由客户端视为未解析）
+	address, err := client.ResolveName(ctx, name)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrENSNameNotFound) || errors.Is(err, blockchain.ErrENSUnsupported) {
+			return "", fmt.Errorf("%w: %s: %v", ErrENSResolution, name, err)
+		}
+		return "", err
+	}
+
+	s.cache.Set(ctx, key, address, s.ttl)
+	return address, nil
+}
+
+// Lookup 反向解析地址在指定链上的ENS主名称，未设置、链未连接节点或解析失败时返回空字符串
+func (s *ENSService) Lookup(ctx context.Context, chainID int, address string) string {
+	client, ok := s.chains.Get(chainID)
+	if !ok {
+		return ""
+	}
+	key := ensAddressKey(chainID, address)
+	if name, ok := readCache(ctx, s.cache, key); ok {
+		return name
+	}
+
+	name, err := client.LookupAddress(ctx, address)
+	if err != nil {
+		if !errors.Is(err, blockchain.ErrENSUnsupported) {
+			logger.WithCtx(ctx).Warn("failed to reverse resolve address",
+				zap.String("address", address),
+				zap.Error(err),
+			)
+		}
+		return ""
+	}
+
+	// 未设置主名称同样缓存，避免重复查询
+	s.cache.Set(ctx, key, name, s.ttl)
+	return name
+}
+
+// AnnotateSenders 为转入交易填充发送方的ENS主名称（ownAddresses为用户自己的钱包地址，小写）
+func (s *ENSService) AnnotateSenders(ctx context.Context, ownAddresses map[string]bool, txs []*models.TransactionResponse) {
+	names := make(map[string]string)
+	for _, tx := range txs {
+		from := strings.ToLower(tx.FromAddress)
+		if ownAddresses[from] {
+			continue
+		}
+		key := fmt.Sprintf("%d:%s", tx.ChainID, from)
+		name, ok := names[key]
+		if !ok {
+			name = s.Lookup(ctx, tx.ChainID, tx.FromAddress)
+			names[key] = name
+		}
+		tx.FromENSName = name
+	}
+}
+
+// resolveRecipient 收款方为ENS名称时解析为地址，返回解析后的地址与规范化的名称（普通地址原样返回，名称为空）
+func resolveRecipient(ctx context.Context, ens *ENSService, chainID int, recipient string) (string, string, error) {
+	if !blockchain.IsENSName(recipient) {
+		return recipient, "", nil
+	}
+	name := blockchain.NormalizeENSName(recipient)
+	if ens == nil {
+		return "", "", fmt.Errorf("%w: %s: ENS resolution is disabled", ErrENSResolution, name)
+	}
+	address, err := ens.Resolve(ctx, chainID, name)
+	if err != nil {
+		return "", "", err
+	}
+	return address, name, nil
+}
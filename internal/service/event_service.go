@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// walletEventChannel Redis发布订阅频道（Worker与API服务共享）
+const walletEventChannel = "events:wallet"
+
+// EventHook 事件发布后在发布方进程内执行一次的处理（如生成通知），不随Pub/Sub广播到其他进程
+type EventHook func(ctx context.Context, event *models.WalletEvent)
+
+// EventService 钱包事件服务（基于Redis Pub/Sub跨进程分发事件）
+type EventService struct {
+	cache       *cache.RedisCache
+	hooks       []EventHook
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *models.WalletEvent]struct{} // 地址(小写) -> 订阅者
+	users       map[uint]map[chan *models.WalletEvent]struct{}   // 用户ID -> 订阅者（定向推送给用户的事件）
+}
+
+// NewEventService 创建事件服务实例
+func NewEventService(cache *cache.RedisCache) *EventService {
+	return &EventService{
+		cache:       cache,
+		subscribers: make(map[string]map[chan *models.WalletEvent]struct{}),
+		users:       make(map[uint]map[chan *models.WalletEvent]struct{}),
+	}
+}
+
+// Publish 发布钱包事件
+func (s *EventService) Publish(ctx context.Context, event *models.WalletEvent) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = s.cache.Publish(ctx, walletEventChannel, body)
+	for _, hook := range s.hooks {
+		hook(ctx, event)
+	}
+	return err
+}
+
+// OnPublish 注册事件发布钩子（启动时调用，Redis发布失败时钩子仍会执行）
+func (s *EventService) OnPublish(hook EventHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// Subscribe 订阅指定地址的事件
+func (s *EventService) Subscribe(address string, ch chan *models.WalletEvent) {
+	key := strings.ToLower(address)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscribers[key] == nil {
+		s.subscribers[key] = make(map[chan *models.WalletEvent]struct{})
+	}
+	s.subscribers[key][ch] = struct{}{}
+}
+
+// Unsubscribe 取消订阅指定地址的事件
+func (s *EventService) Unsubscribe(address string, ch chan *models.WalletEvent) {
+	key := strings.ToLower(address)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscribers[key], ch)
+	if len(s.subscribers[key]) == 0 {
+		delete(s.subscribers, key)
+	}
+}
+
+// SubscribeUser 订阅定向推送给指定用户的事件
+func (s *EventService) SubscribeUser(userID uint, ch chan *models.WalletEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.users[userID] == nil {
+		s.users[userID] = make(map[chan *models.WalletEvent]struct{})
+	}
+	s.users[userID][ch] = struct{}{}
+}
+
+// UnsubscribeUser 取消订阅定向推送给指定用户的事件
+func (s *EventService) UnsubscribeUser(userID uint, ch chan *models.WalletEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users[userID], ch)
+	if len(s.users[userID]) == 0 {
+		delete(s.users, userID)
+	}
+}
+
+// Run 监听Redis频道并分发事件给本进程的订阅者（阻塞直到ctx取消）
+func (s *EventService) Run(ctx context.Context) {
+	pubsub := s.cache.Subscribe(ctx, walletEventChannel)
+	defer pubsub.Close()
+
+	msgs := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+
+			var event models.WalletEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logger.Warn("failed to unmarshal wallet event", zap.Error(err))
+				continue
+			}
+
+			s.dispatch(&event)
+		}
+	}
+}
+
+// dispatch 将事件分发给订阅了该地址的所有连接，定向事件只分发给目标用户（慢消费者直接丢弃，避免阻塞）
+func (s *EventService) dispatch(event *models.WalletEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscribers := s.subscribers[strings.ToLower(event.Address)]
+	if event.UserID != 0 {
+		subscribers = s.users[event.UserID]
+	}
+	for ch := range subscribers {
+		select {
+		case ch <- event:
+		default:
+			logger.Warn("dropping wallet event for slow subscriber",
+				zap.String("address", event.Address),
+				zap.String("type", string(event.Type)),
+			)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// TestEventServiceDeliversAcrossProcesses Worker发布的事件经Redis送达API服务中订阅了该地址的连接
+func TestEventServiceDeliversAcrossProcesses(t *testing.T) {
+	redis, server := testutil.NewRedis(t)
+	worker := NewEventService(redis)
+	api := NewEventService(redis)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go api.Run(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for server.PubSubNumSub(walletEventChannel)[walletEventChannel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("event service did not subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	const address = "0xAbCdEf0000000000000000000000000000000001"
+	subscribed := make(chan *models.WalletEvent, 1)
+	other := make(chan *models.WalletEvent, 1)
+	api.Subscribe(address, subscribed)
+	api.Subscribe("0x0000000000000000000000000000000000000002", other)
+
+	// 地址大小写不同也应送达
+	event := &models.WalletEvent{Type: models.EventTransactionConfirmed, Address: "0xabcdef0000000000000000000000000000000001", TxHash: "0x01", Status: models.TxStatusSuccess}
+	if err := worker.Publish(ctx, event); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	select {
+	case got := <-subscribed:
+		if got.TxHash != event.TxHash || got.Status != event.Status || got.Timestamp.IsZero() {
+			t.Errorf("event = %+v, want tx %s status %s with timestamp", got, event.TxHash, event.Status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber did not receive the event")
+	}
+	select {
+	case got := <-other:
+		t.Errorf("subscriber of another address received %+v", got)
+	default:
+	}
+
+	// 取消订阅后不再送达
+	api.Unsubscribe(address, subscribed)
+	if err := worker.Publish(ctx, &models.WalletEvent{Type: models.EventDepositDetected, Address: address}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	select {
+	case got := <-subscribed:
+		t.Errorf("unsubscribed channel received %+v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestEventServiceSkipsSlowSubscriber 缓冲已满的订阅者丢弃事件，不阻塞其他订阅者
+func TestEventServiceSkipsSlowSubscriber(t *testing.T) {
+	s := NewEventService(nil)
+	const address = "0x0000000000000000000000000000000000000003"
+	slow := make(chan *models.WalletEvent) // 无缓冲且无人读取
+	fast := make(chan *models.WalletEvent, 1)
+	s.Subscribe(address, slow)
+	s.Subscribe(address, fast)
+
+	done := make(chan struct{})
+	go func() {
+		s.dispatch(&models.WalletEvent{Type: models.EventDepositDetected, Address: address})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatch blocked on a slow subscriber")
+	}
+	if len(fast) != 1 {
+		t.Error("fast subscriber did not receive the event")
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/models"
+)
+
+// ExportJobQueue 异步导出任务队列
+const ExportJobQueue = "export.generate"
+
+// exportTokenAudience 下载令牌的aud（区分登录Token）
+const exportTokenAudience = "export-download"
+
+// exportCleanupBatchSize 每轮清理的最大任务数
+const exportCleanupBatchSize = 500
+
+var (
+	// ErrExportNotReady 导出文件尚未生成
+	ErrExportNotReady = apperr.Conflict("error.export_not_ready", "export is not ready for download")
+	// ErrExportExpired 导出文件已过期删除
+	ErrExportExpired = apperr.Conflict("error.export_expired", "export file has expired")
+	// ErrDownloadTokenInvalid 下载令牌无效、已过期、已使用或不属于该任务
+	ErrDownloadTokenInvalid = apperr.Forbidden("error.download_token_invalid", "download token is invalid, expired or already used")
+)
+
+// exportTokenKey 由JWT密钥派生下载令牌签名密钥（两种令牌互相无法通过验证）
+func exportTokenKey(jwtSecret string) []byte {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(exportTokenAudience))
+	return mac.Sum(nil)
+}
+
+// exportTokenUsedKey 已使用的下载令牌
+func exportTokenUsedKey(jti string) string {
+	return "export:token_used:" + jti
+}
+
+// CreateJob 创建导出任务：校验参数、估算行数，并在同一事务中写入任务与发件箱事件，由Worker生成文件
+func (s *ExportService) CreateJob(ctx context.Context, userID uint, req *models.ExportJobRequest) (*models.ExportJobResponse, error) {
+	// 1. 校验参数（与同步导出相同）
+	exportReq := req.ExportRequest()
+	filter, err := s.PrepareExport(ctx, userID, exportReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 估算行数
+	estimated, err := s.txRepo.CountForExport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 写入任务与发件箱事件
+	job := &models.ExportJob{
+		ID:            uuid.NewString(),
+		UserID:        userID,
+		Format:        exportReq.Format,
+		WalletID:      filter.WalletID,
+		ChainID:       filter.ChainID,
+		Direction:     filter.Direction,
+		MinAmount:     filter.MinAmount,
+		MaxAmount:     filter.MaxAmount,
+		Counterparty:  filter.Counterparty,
+		Status:        models.ExportJobPending,
+		EstimatedRows: estimated,
+	}
+	if !filter.From.IsZero() {
+		job.FromDate = &filter.From
+	}
+	if !filter.To.IsZero() {
+		job.ToDate = &filter.To
+	}
+	payload, err := s.queueCodec.Seal(&models.ExportJobMessage{JobID: job.ID})
+	if err != nil {
+		return nil, err
+	}
+	event, err := NewOutboxEvent(ctx, ExportJobQueue, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.jobRepo.CreateWithOutbox(ctx, job, event); err != nil {
+		return nil, err
+	}
+
+	return &models.ExportJobResponse{ExportJob: job}, nil
+}
+
+// GetJob 查询导出任务状态（只能查询自己的任务），文件可下载时签发一次性下载链接
+func (s *ExportService) GetJob(ctx context.Context, userID uint, jobID string) (*models.ExportJobResponse, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, apperr.NotFound("export job")
+	}
+
+	resp := &models.ExportJobResponse{ExportJob: job}
+	if job.Status != models.ExportJobCompleted || job.ExpiresAt == nil || !time.Now().Before(*job.ExpiresAt) {
+		return resp, nil
+	}
+	token, expiresAt, err := s.issueDownloadToken(job)
+	if err != nil {
+		return nil, err
+	}
+	resp.DownloadURL = "/api/v1/exports/" + job.ID + "/download?token=" + url.QueryEscape(token)
+	resp.TokenExpiresAt = &expiresAt
+	return resp, nil
+}
+
+// issueDownloadToken 签发绑定任务与用户的下载令牌（有效期不超过文件过期时间）
+func (s *ExportService) issueDownloadToken(job *models.ExportJob) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.tokenTTL)
+	if job.ExpiresAt != nil && job.ExpiresAt.Before(expiresAt) {
+		expiresAt = *job.ExpiresAt
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":       strconv.FormatUint(uint64(job.UserID), 10),
+		"aud":       exportTokenAudience,
+		"export_id": job.ID,
+		"jti":       uuid.NewString(), // 令牌唯一标识（下载时标记为已使用）
+		"exp":       expiresAt.Unix(),
+		"iat":       now.Unix(),
+	})
+	signed, err := token.SignedString(s.tokenKey)
+	return signed, expiresAt, err
+}
+
+// OpenDownload 校验下载令牌并打开导出文件（无需登录，令牌绑定任务与发起导出的用户，只能使用一次）
+//
+// 令牌在文件成功打开后才标记为已使用，文件不存在时令牌不会被消耗。调用方负责关闭返回的文件。
+func (s *ExportService) OpenDownload(ctx context.Context, jobID, tokenString string) (*models.ExportJob, *os.File, error) {
+	// 1. 验证令牌签名、有效期与用途
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return s.tokenKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(exportTokenAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, nil, ErrDownloadTokenInvalid
+	}
+	claims, _ := token.Claims.(jwt.MapClaims)
+	exportID, _ := claims["export_id"].(string)
+	jti, _ := claims["jti"].(string)
+	subject, _ := claims.GetSubject()
+	userID, err := strconv.ParseUint(subject, 10, 64)
+	if err != nil || exportID != jobID || jti == "" {
+		return nil, nil, ErrDownloadTokenInvalid
+	}
+	exp, _ := claims.GetExpirationTime()
+
+	// 2. 令牌必须由任务所属用户签发
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil, ErrDownloadTokenInvalid
+		}
+		return nil, nil, err
+	}
+	if uint64(job.UserID) != userID {
+		return nil, nil, ErrDownloadTokenInvalid
+	}
+
+	// 3. 检查任务状态并打开文件
+	switch {
+	case job.Status == models.ExportJobExpired,
+		job.Status == models.ExportJobCompleted && job.ExpiresAt != nil && !time.Now().Before(*job.ExpiresAt):
+		return nil, nil, ErrExportExpired
+	case job.Status != models.ExportJobCompleted:
+		return nil, nil, ErrExportNotReady
+	}
+	file, err := os.Open(s.filePath(job))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrExportExpired
+		}
+		return nil, nil, err
+	}
+
+	// 4. 标记令牌已使用（保留到令牌过期）
+	fresh, err := s.redis.SetNX(ctx, exportTokenUsedKey(jti), 1, time.Until(exp.Time)+time.Minute)
+	if err != nil || !fresh {
+		file.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrDownloadTokenInvalid
+	}
+	return job, file, nil
+}
+
+// Generate 生成导出文件（由Worker调用）：任务已完成或已过期时直接返回；失败时标记为failed并返回错误，由队列重试
+func (s *ExportService) Generate(ctx context.Context, jobID string) error {
+	log := logger.WithCtx(ctx).With(zap.String("export_id", jobID))
+
+	// 1. 认领任务
+	claimed, err := s.jobRepo.Claim(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Info("export job already finished, skipping")
+		return nil
+	}
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	// 2. 写入临时文件后重命名（下载时不会读到未写完的文件）
+	rows, size, err := s.writeFile(ctx, job)
+	if err != nil {
+		metrics.ExportJobs.WithLabelValues(job.Format, "failed").Inc()
+		log.Error("export job failed", zap.Error(err))
+		if failErr := s.jobRepo.Fail(ctx, job.ID, "export failed, will retry"); failErr != nil {
+			log.Error("failed to mark export job failed", zap.Error(failErr))
+		}
+		return err
+	}
+
+	// 3. 记录结果
+	if err := s.jobRepo.Complete(ctx, job.ID, rows, size, time.Now().Add(s.fileTTL)); err != nil {
+		return err
+	}
+	metrics.ExportJobs.WithLabelValues(job.Format, "completed").Inc()
+	log.Info("export job completed", zap.Int64("rows", rows), zap.Int64("bytes", size))
+	return nil
+}
+
+// writeFile 将任务的交易记录写入导出文件，返回行数与文件大小
+func (s *ExportService) writeFile(ctx context.Context, job *models.ExportJob) (int64, int64, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return 0, 0, err
+	}
+	path := s.filePath(job)
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(tmp)
+
+	w := bufio.NewWriter(file)
+	rows, err := s.exportTransactions(ctx, job.Filter(), job.Format, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, 0, err
+	}
+	return rows, info.Size(), nil
+}
+
+// CleanupExpired 删除过期的导出文件并标记任务为expired（创建超过文件保留时间仍未完成的任务一并清理），返回清理的任务数
+func (s *ExportService) CleanupExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	jobs, err := s.jobRepo.ListExpired(ctx, now, now.Add(-s.fileTTL), exportCleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		path := s.filePath(job)
+		if err := removeIfExists(path); err != nil {
+			logger.WithCtx(ctx).Warn("failed to remove export file", zap.String("export_id", job.ID), zap.Error(err))
+			continue
+		}
+		_ = removeIfExists(path + ".tmp")
+		ids = append(ids, job.ID)
+	}
+	if err := s.jobRepo.MarkExpired(ctx, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// RunCleanup 按间隔清理过期的导出文件，直到ctx取消
+func (s *ExportService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.CleanupExpired(ctx)
+			if err != nil {
+				logger.WithCtx(ctx).Error("export cleanup failed", zap.Error(err))
+				continue
+			}
+			if count > 0 {
+				logger.WithCtx(ctx).Info("expired exports removed", zap.Int("count", count))
+			}
+		}
+	}
+}
+
+// filePath 导出文件路径（任务ID为服务端生成的UUID，不含路径分隔符）
+func (s *ExportService) filePath(job *models.ExportJob) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%s", job.ID, job.Format))
+}
+
+// removeIfExists 删除文件，文件不存在时不报错
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// exportBatchSize 导出时每批读取的交易数
+const exportBatchSize = 500
+
+// exportCSVHeader CSV表头
+var exportCSVHeader = []string{
+	"tx_hash", "direction", "from_address", "to_address", "amount_eth", "gas_fee_eth",
+	"status", "chain_name", "created_at", "confirmed_at", "note", "tags", "explorer_url",
+}
+
+// ExportService 交易导出服务（同步流式导出，以及由Worker生成文件、凭一次性下载令牌下载的异步导出）
+type ExportService struct {
+	txRepo     *repository.TransactionRepository
+	walletRepo *repository.WalletRepository
+	jobRepo    *repository.ExportJobRepository
+	redis      *cache.RedisCache
+	queueCodec *QueueCodec
+	tokenKey   []byte        // 下载令牌签名密钥（由JWT密钥派生，与登录Token不能互换）
+	dir        string        // 导出文件目录
+	fileTTL    time.Duration // 文件保留时间
+	tokenTTL   time.Duration // 下载令牌有效期
+}
+
+// NewExportService 创建交易导出服务实例
+func NewExportService(
+	txRepo *repository.TransactionRepository,
+	walletRepo *repository.WalletRepository,
+	jobRepo *repository.ExportJobRepository,
+	redis *cache.RedisCache,
+	queueCodec *QueueCodec,
+	jwtSecret string,
+	dir string,
+	fileTTL time.Duration,
+	tokenTTL time.Duration,
+) *ExportService {
+	return &ExportService{
+		txRepo:     txRepo,
+		walletRepo: walletRepo,
+		jobRepo:    jobRepo,
+		redis:      redis,
+		queueCodec: queueCodec,
+		tokenKey:   exportTokenKey(jwtSecret),
+		dir:        dir,
+		fileTTL:    fileTTL,
+		tokenTTL:   tokenTTL,
+	}
+}
+
+// PrepareExport 校验导出请求并构建查询条件（在写入响应前调用，以便返回错误状态码）
+func (s *ExportService) PrepareExport(ctx context.Context, userID uint, req *models.TransactionExportRequest) (*models.TransactionExportFilter, error) {
+	filter := &models.TransactionExportFilter{
+		UserID:            userID,
+		ChainID:           req.ChainID,
+		TransactionFilter: req.TransactionFilter,
+	}
+
+	// 1. 日期范围（按UTC自然日，结束日期包含当天）
+	if !req.From.IsZero() {
+		filter.From = truncateDay(req.From)
+	}
+	if !req.To.IsZero() {
+		filter.To = truncateDay(req.To).AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, apperr.Invalid("error.from_after_to", "from must not be after to")
+	}
+	if err := checkTransactionFilter(&req.TransactionFilter); err != nil {
+		return nil, err
+	}
+
+	// 2. 指定钱包时校验查看权限
+	if req.WalletAddress != "" {
+		wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.WalletAddress, PermView)
+		if err != nil {
+			return nil, err
+		}
+		filter.WalletID = wallet.ID
+	}
+
+	return filter, nil
+}
+
+// ExportTransactions 分批读取交易并以CSV或JSON格式流式写出
+func (s *ExportService) ExportTransactions(ctx context.Context, filter *models.TransactionExportFilter, format string, w io.Writer) error {
+	_, err := s.exportTransactions(ctx, filter, format, w)
+	return err
+}
+
+// exportTransactions 分批读取交易并写出，返回写出的交易数
+func (s *ExportService) exportTransactions(ctx context.Context, filter *models.TransactionExportFilter, format string, w io.Writer) (int64, error) {
+	// 1. 用户钱包地址（用于判断交易方向）
+	wallets, err := s.walletRepo.GetByUserID(ctx, filter.UserID)
+	if err != nil {
+		return 0, err
+	}
+	owned := make(map[string]bool, len(wallets))
+	for _, wallet := range wallets {
+		owned[strings.ToLower(wallet.Address)] = true
+	}
+
+	// 2. 按格式逐批写出，每批结束后刷新到客户端
+	var out exportWriter
+	if format == "json" {
+		out = &jsonExportWriter{w: w}
+	} else {
+		out = &csvExportWriter{w: csv.NewWriter(w)}
+	}
+	if err := out.begin(); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err = s.txRepo.ExportInBatches(ctx, filter, exportBatchSize, func(batch []*models.Transaction) error {
+		for _, tx := range batch {
+			if err := out.write(toExportRow(tx, owned)); err != nil {
+				return err
+			}
+		}
+		rows += int64(len(batch))
+		if err := out.flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, out.end()
+}
+
+// toExportRow 转换为导出行
+func toExportRow(tx *models.Transaction, owned map[string]bool) *models.TransactionExportRow {
+	direction := models.TxDirectionIn
+	if owned[strings.ToLower(tx.FromAddress)] {
+		direction = models.TxDirectionOut
+		if owned[strings.ToLower(tx.ToAddress)] {
+			direction = models.TxDirectionSelf
+		}
+	}
+
+	// Gas费用 = gas_used * 实际gas单价（回执未提供时使用gas_price），转换为ETH
+	gasPrice := tx.GasPrice
+	if tx.EffectiveGasPrice != "" {
+		gasPrice = tx.EffectiveGasPrice
+	}
+	gasFee := utils.GasFee(utils.DecimalToWei(gasPrice), tx.GasUsed)
+
+	return &models.TransactionExportRow{
+		TxHash:      tx.TxHash,
+		Direction:   direction,
+		FromAddress: tx.FromAddress,
+		ToAddress:   tx.ToAddress,
+		Amount:      tx.Amount,
+		GasFee:      utils.WeiToEthString(gasFee),
+		Status:      tx.Status,
+		ChainName:   models.ChainName(tx.ChainID),
+		ExplorerURL: models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		CreatedAt:   tx.CreatedAt,
+		ConfirmedAt: tx.ConfirmedAt,
+		Note:        tx.Note,
+		Tags:        tx.TagNames(),
+	}
+}
+
+// exportWriter 导出格式写入器
+type exportWriter interface {
+	begin() error
+	write(row *models.TransactionExportRow) error
+	flush() error
+	end() error
+}
+
+// csvExportWriter CSV写入器（字段转义由encoding/csv处理）
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) begin() error {
+	return c.w.Write(exportCSVHeader)
+}
+
+func (c *csvExportWriter) write(row *models.TransactionExportRow) error {
+	confirmedAt := ""
+	if row.ConfirmedAt != nil {
+		confirmedAt = row.ConfirmedAt.UTC().Format(time.RFC3339)
+	}
+	return c.w.Write([]string{
+		row.TxHash,
+		row.Direction,
+		row.FromAddress,
+		row.ToAddress,
+		row.Amount,
+		row.GasFee,
+		string(row.Status),
+		row.ChainName,
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		confirmedAt,
+		row.Note,
+		strings.Join(row.Tags, ";"),
+		row.ExplorerURL,
+	})
+}
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) end() error {
+	return c.flush()
+}
+
+// jsonExportWriter JSON数组写入器（逐行编码，不缓存整个数组）
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonExportWriter) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonExportWriter) write(row *models.TransactionExportRow) error {
+	body, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, "\n"); err != nil {
+		return err
+	}
+	_, err = j.w.Write(body)
+	return err
+}
+
+func (j *jsonExportWriter) flush() error {
+	return nil
+}
+
+func (j *jsonExportWriter) end() error {
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// newExportService 创建导出服务（导出文件写入测试临时目录）
+func (e *testEnv) newExportService(t *testing.T) *ExportService {
+	return NewExportService(e.txRepo, e.walletRepo, repository.NewExportJobRepository(e.db), e.redis, NewQueueCodec(nil), "secret", t.TempDir(), time.Hour, time.Hour)
+}
+
+// flushRecorder 记录导出输出的http.Flusher：每次Flush（一批写出完成）时统计批次并采样存活堆内存
+type flushRecorder struct {
+	bytes.Buffer
+	baseline uint64
+	flushes  int
+	peak     uint64
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	if stats.HeapAlloc > r.baseline {
+		r.peak = max(r.peak, stats.HeapAlloc-r.baseline)
+	}
+}
+
+func TestExportTransactionsStreamsLargeCSV(t *testing.T) {
+	const total = 10000
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(0))
+
+	rows := make([]*models.Transaction, total)
+	start := time.Now().Add(-total * time.Second)
+	for i := range rows {
+		rows[i] = &models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      fmt.Sprintf("0x%064x", i+1),
+			FromAddress: wallet.Address,
+			ToAddress:   recipient,
+			Amount:      "1",
+			Status:      models.TxStatusSuccess,
+			ChainID:     testutil.ChainID,
+			CreatedAt:   start.Add(time.Duration(i) * time.Second),
+		}
+	}
+	if err := env.db.CreateInBatches(rows, 500).Error; err != nil {
+		t.Fatalf("seed transactions: %v", err)
+	}
+	rows = nil
+
+	exports := env.newExportService(t)
+	out := &flushRecorder{}
+	out.Grow(8 << 20) // 预先分配输出缓冲区，堆内存采样只反映导出过程
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	out.baseline = stats.HeapAlloc
+
+	if err := exports.ExportTransactions(ctx, &models.TransactionExportFilter{UserID: user.ID}, "csv", out); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	records, err := csv.NewReader(&out.Buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != total+1 {
+		t.Errorf("csv rows = %d, want header + %d", len(records), total)
+	}
+	// 按批读取与写出：每批刷新一次，导出过程中的存活内存只包含一批交易
+	if want := total / exportBatchSize; out.flushes < want {
+		t.Errorf("flushes = %d, want at least %d batches", out.flushes, want)
+	}
+	t.Logf("peak live heap during export: %d KB", out.peak>>10)
+	if out.peak > 4<<20 {
+		t.Errorf("peak live heap = %d KB, want bounded by one batch (< 4096 KB)", out.peak>>10)
+	}
+}
+
+func TestExportCSVEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	out := &csvExportWriter{w: csv.NewWriter(&buf)}
+	row := &models.TransactionExportRow{
+		TxHash:    `0x01,"quoted"`,
+		Direction: models.TxDirectionOut,
+		ChainName: "line\nbreak",
+		Status:    models.TxStatusSuccess,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := out.begin(); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := out.write(row); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := out.end(); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"0x01,""quoted"""`) {
+		t.Errorf("csv does not quote the field with a comma and quotes:\n%s", buf.String())
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("csv rows = %d, want 2", len(records))
+	}
+	want := []string{row.TxHash, row.Direction, "", "", "", "", "success", "line\nbreak", "2024-01-02T03:04:05Z", "", "", "", ""}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("row = %q, want %q", records[1], want)
+	}
+}
+
+func TestExportTransactionsJSONDirections(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(0))
+	second := env.createWallet(t, user.ID, ether(0))
+
+	seed := []struct {
+		from, to string
+	}{
+		{wallet.Address, recipient},      // 转出
+		{recipient, wallet.Address},      // 转入
+		{wallet.Address, second.Address}, // 自有钱包间
+	}
+	for i, s := range seed {
+		tx := &models.Transaction{
+			WalletID:    wallet.ID,
+			TxHash:      fmt.Sprintf("0x%064x", i+1),
+			FromAddress: s.from,
+			ToAddress:   s.to,
+			Amount:      "1",
+			Status:      models.TxStatusSuccess,
+			ChainID:     testutil.ChainID,
+			CreatedAt:   time.Now().Add(time.Duration(i) * time.Second),
+		}
+		if err := env.db.Create(tx).Error; err != nil {
+			t.Fatalf("seed transaction: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	exports := env.newExportService(t)
+	if err := exports.ExportTransactions(ctx, &models.TransactionExportFilter{UserID: user.ID}, "json", &buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var rows []models.TransactionExportRow
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("export is not a JSON array: %v\n%s", err, buf.String())
+	}
+	directions := map[string]string{}
+	for _, row := range rows {
+		directions[row.TxHash] = row.Direction
+	}
+	want := map[string]string{
+		fmt.Sprintf("0x%064x", 1): models.TxDirectionOut,
+		fmt.Sprintf("0x%064x", 2): models.TxDirectionIn,
+		fmt.Sprintf("0x%064x", 3): models.TxDirectionSelf,
+	}
+	if !reflect.DeepEqual(directions, want) {
+		t.Errorf("directions = %v, want %v", directions, want)
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/queue"
+)
+
+// WalletFundingQueue 测试链新钱包领水队列
+const WalletFundingQueue = "wallet.funding"
+
+// faucetCounterTTL 每日领取计数的保留时间（按UTC自然日计数，多保留1小时避免跨日时计数提前消失）
+const faucetCounterTTL = 25 * time.Hour
+
+// FaucetService 测试链水龙头：新建钱包时写入领水任务，Worker从水龙头钱包经正常转账流程转入少量原生币
+type FaucetService struct {
+	walletRepo *repository.WalletRepository
+	txService  *TransactionService
+	redis      *cache.RedisCache
+	queueCodec *QueueCodec
+	faucets    map[int]models.Faucet // 链ID -> 水龙头（仅测试链白名单中的链）
+}
+
+// NewFaucetService 创建水龙头服务实例（不在测试链白名单中的链即使传入也不会启用）
+func NewFaucetService(
+	walletRepo *repository.WalletRepository,
+	txService *TransactionService,
+	redis *cache.RedisCache,
+	queueCodec *QueueCodec,
+	faucets []models.Faucet,
+) *FaucetService {
+	s := &FaucetService{
+		walletRepo: walletRepo,
+		txService:  txService,
+		redis:      redis,
+		queueCodec: queueCodec,
+		faucets:    make(map[int]models.Faucet, len(faucets)),
+	}
+	for _, faucet := range faucets {
+		if !blockchain.IsTestnet(faucet.ChainID) {
+			logger.Error("faucet ignored: chain is not an allowlisted test chain", zap.Int("chain_id", faucet.ChainID))
+			continue
+		}
+		s.faucets[faucet.ChainID] = faucet
+	}
+	return s
+}
+
+// faucet 查询链上启用的水龙头（运行时再次校验测试链白名单）
+func (s *FaucetService) faucet(chainID int) (models.Faucet, bool) {
+	faucet, ok := s.faucets[chainID]
+	return faucet, ok && blockchain.IsTestnet(chainID)
+}
+
+// Prepare 为即将创建的钱包预留当日领取次数并构建领水任务
+//
+// 链未启用水龙头或用户当日领取次数已用完时返回nil（钱包照常创建，不领水）；返回任务时钱包状态设为funding_pending，
+// 调用方需将任务与钱包在同一事务中写入，写入失败时调用Release归还次数。
+func (s *FaucetService) Prepare(ctx context.Context, wallet *models.Wallet) (*models.OutboxEvent, error) {
+	faucet, ok := s.faucet(wallet.ChainID)
+	if !ok {
+		return nil, nil
+	}
+
+	// 1. 预留当日领取次数（超出时归还）
+	key := faucetCounterKey(wallet.UserID, wallet.ChainID, time.Now())
+	count, err := s.redis.Incr(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if count == 1 {
+		if err := s.redis.Expire(ctx, key, faucetCounterTTL); err != nil {
+			logger.WithCtx(ctx).Warn("failed to set faucet counter expiry", zap.String("key", key), zap.Error(err))
+		}
+	}
+	if count > int64(faucet.DailyLimit) {
+		s.release(ctx, key)
+		logger.WithCtx(ctx).Info("faucet daily limit reached, wallet will not be funded",
+			zap.Uint("user_id", wallet.UserID),
+			zap.Int("chain_id", wallet.ChainID),
+			zap.Int("daily_limit", faucet.DailyLimit),
+		)
+		return nil, nil
+	}
+
+	// 2. 构建领水任务
+	payload, err := s.queueCodec.Seal(&models.WalletFundingMessage{
+		Address: wallet.Address,
+		ChainID: wallet.ChainID,
+	})
+	if err != nil {
+		s.release(ctx, key)
+		return nil, err
+	}
+	event, err := NewOutboxEvent(ctx, WalletFundingQueue, payload)
+	if err != nil {
+		s.release(ctx, key)
+		return nil, err
+	}
+	wallet.FundingStatus = models.WalletFundingPending
+	return event, nil
+}
+
+// Release 钱包未能创建时归还Prepare预留的领取次数
+func (s *FaucetService) Release(ctx context.Context, wallet *models.Wallet) {
+	s.release(ctx, faucetCounterKey(wallet.UserID, wallet.ChainID, time.Now()))
+}
+
+// release 归还一次领取次数
+func (s *FaucetService) release(ctx context.Context, key string) {
+	if _, err := s.redis.Decr(ctx, key); err != nil {
+		logger.WithCtx(ctx).Warn("failed to release faucet counter", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// Fund 处理领水任务（由Worker调用）：认领仍为funding_pending的钱包后从水龙头钱包发送交易
+//
+// 可重试的错误（节点或数据库暂时不可用、发送暂停、水龙头余额不足）恢复为funding_pending后返回，由队列重试；
+// 其他业务错误标记为funding_failed并返回包装queue.ErrReject的错误，直接转入死信队列。
+func (s *FaucetService) Fund(ctx context.Context, msg *models.WalletFundingMessage) error {
+	log := logger.WithCtx(ctx).With(zap.String("address", msg.Address), zap.Int("chain_id", msg.ChainID))
+
+	// 1. 校验水龙头与钱包（钱包已删除或状态不再是funding_pending时忽略，重复投递的消息在此结束）
+	faucet, ok := s.faucet(msg.ChainID)
+	if !ok {
+		return fmt.Errorf("%w: no faucet enabled for chain %d", queue.ErrReject, msg.ChainID)
+	}
+	if !utils.IsEthAddress(msg.Address) {
+		return fmt.Errorf("%w: malformed wallet address %q", queue.ErrReject, msg.Address)
+	}
+	wallet, err := s.walletRepo.GetByAddress(ctx, msg.Address)
+	if errors.Is(err, apperr.ErrNotFound) {
+		log.Info("faucet funding skipped: wallet no longer exists")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if wallet.ChainID != msg.ChainID {
+		return fmt.Errorf("%w: wallet %s is not on chain %d", queue.ErrReject, wallet.Address, msg.ChainID)
+	}
+
+	// 2. 认领任务，防止多个Worker重复发送
+	claimed, err := s.walletRepo.UpdateFundingStatus(ctx, wallet.ID, models.WalletFundingPending, models.WalletFunded)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Debug("faucet funding skipped: already processed", zap.String("funding_status", wallet.FundingStatus))
+		return nil
+	}
+
+	// 3. 以水龙头钱包所有者的身份经正常转账流程发送（余额、限额、白名单与审批规则照常生效）
+	tx, err := s.send(ctx, faucet, wallet)
+	if err != nil {
+		status := models.WalletFundingPending
+		if !faucetRetryable(err) {
+			status = models.WalletFundingFailed
+			err = fmt.Errorf("%w: %v", queue.ErrReject, err)
+		}
+		if _, updateErr := s.walletRepo.UpdateFundingStatus(context.WithoutCancel(ctx), wallet.ID, models.WalletFunded, status); updateErr != nil {
+			log.Error("failed to reset wallet funding status", zap.String("funding_status", status), zap.Error(updateErr))
+		}
+		log.Warn("faucet funding failed", zap.String("funding_status", status), zap.Error(err))
+		return err
+	}
+
+	log.Info("wallet funded from faucet", zap.String("tx_hash", tx.TxHash), zap.String("amount_wei", faucet.AmountWei))
+	return nil
+}
+
+// send 从水龙头钱包向新钱包转账
+func (s *FaucetService) send(ctx context.Context, faucet models.Faucet, wallet *models.Wallet) (*models.Transaction, error) {
+	source, err := s.walletRepo.GetByAddress(ctx, faucet.Address)
+	if err != nil {
+		return nil, fmt.Errorf("faucet wallet %s: %w", faucet.Address, err)
+	}
+	return s.txService.SendTransaction(ctx, source.UserID, &models.TransactionCreateRequest{
+		FromAddress: source.Address,
+		ToAddress:   wallet.Address,
+		Amount:      faucet.AmountWei,
+		ChainID:     wallet.ChainID,
+		Passphrase:  faucet.Passphrase,
+		Note:        "Test chain faucet funding",
+	})
+}
+
+// faucetRetryable 领水失败是否可以重试：暂停发送与链节点不健康可重试，其他业务错误（钱包不存在、口令错误、超出限额等）不可重试，
+// 非业务错误（数据库、节点请求失败、水龙头余额不足）可重试
+func faucetRetryable(err error) bool {
+	var disabled *FeatureDisabledError
+	if errors.As(err, &disabled) || errors.Is(err, ErrChainUnhealthy) {
+		return true
+	}
+	var appErr *apperr.Error
+	return !errors.As(err, &appErr)
+}
+
+// faucetCounterKey 用户在链上当日（UTC）领水次数的Redis键
+func faucetCounterKey(userID uint, chainID int, now time.Time) string {
+	return fmt.Sprintf("faucet:daily:%d:%d:%s", userID, chainID, now.UTC().Format("20060102"))
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// featureFlagKeyPrefix 功能开关在Redis中的键前缀
+const featureFlagKeyPrefix = "feature:"
+
+var (
+	// ErrFeatureDisabled 功能暂时关闭（维护期间）
+	ErrFeatureDisabled = apperr.New("error.feature_disabled", "feature temporarily disabled")
+	// ErrUnknownFeatureFlag 功能开关不存在
+	ErrUnknownFeatureFlag = apperr.NotFound("feature flag")
+)
+
+// FeatureDisabledError 功能暂时关闭（携带开关名称与管理员填写的说明）
+type FeatureDisabledError struct {
+	Data *models.FeatureDisabledData
+}
+
+func (e *FeatureDisabledError) Error() string {
+	if e.Data.Message != "" {
+		return fmt.Sprintf("%s: %s", ErrFeatureDisabled, e.Data.Message)
+	}
+	return ErrFeatureDisabled.Error()
+}
+
+// Unwrap 支持errors.Is(err, ErrFeatureDisabled)
+func (e *FeatureDisabledError) Unwrap() error {
+	return ErrFeatureDisabled
+}
+
+// cachedFlag 进程内缓存的开关状态
+type cachedFlag struct {
+	flag      *models.FeatureFlag
+	fetchedAt time.Time
+}
+
+// FeatureFlagService 功能开关服务（状态保存在Redis中，各实例在refresh间隔内同步）
+type FeatureFlagService struct {
+	cache   cache.Cache
+	repo    *repository.FeatureFlagRepository
+	refresh time.Duration // 进程内缓存时间
+
+	mu    sync.Mutex
+	flags map[string]*cachedFlag // 最近一次读取的状态（Redis不可用时沿用）
+}
+
+// NewFeatureFlagService 创建功能开关服务实例
+func NewFeatureFlagService(cache cache.Cache, repo *repository.FeatureFlagRepository, refresh time.Duration) *FeatureFlagService {
+	return &FeatureFlagService{
+		cache:   cache,
+		repo:    repo,
+		refresh: refresh,
+		flags:   make(map[string]*cachedFlag),
+	}
+}
+
+// Get 获取开关状态（Redis中没有记录时使用默认值；Redis不可用时沿用最近一次读取的状态，从未读取过则使用默认值）
+func (s *FeatureFlagService) Get(ctx context.Context, name string) (*models.FeatureFlag, error) {
+	def, ok := models.FeatureFlagDefaults[name]
+	if !ok {
+		return nil, ErrUnknownFeatureFlag
+	}
+
+	// 1. 进程内缓存未过期时直接返回
+	s.mu.Lock()
+	cached := s.flags[name]
+	s.mu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < s.refresh {
+		return cached.flag, nil
+	}
+
+	// 2. 读取Redis
+	flag := &models.FeatureFlag{Name: name, Enabled: def, Default: true}
+	value, err := s.cache.Get(ctx, featureFlagKeyPrefix+name)
+	switch {
+	case err == nil:
+		var stored models.FeatureFlag
+		if err := json.Unmarshal([]byte(value), &stored); err != nil {
+			logger.WithCtx(ctx).Error("invalid feature flag value, using default",
+				zap.String("flag", name),
+				zap.Error(err),
+			)
+			break
+		}
+		stored.Name = name
+		stored.Default = false
+		flag = &stored
+	case errors.Is(err, cache.ErrNotFound):
+	default:
+		logger.WithCtx(ctx).Warn("failed to read feature flag",
+			zap.String("flag", name),
+			zap.Error(err),
+		)
+		if cached != nil {
+			flag = cached.flag
+		}
+	}
+
+	// 3. 更新进程内缓存（Redis不可用时同样更新时间，避免每个请求都重试）
+	s.mu.Lock()
+	s.flags[name] = &cachedFlag{flag: flag, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return flag, nil
+}
+
+// List 获取所有开关的状态（按名称排序）
+func (s *FeatureFlagService) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	names := make([]string, 0, len(models.FeatureFlagDefaults))
+	for name := range models.FeatureFlagDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]*models.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flag, err := s.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// Set 修改开关状态并记录审计日志（Redis不可用时返回错误，不做修改）
+func (s *FeatureFlagService) Set(ctx context.Context, adminID uint, ip, name string, req *models.FeatureFlagUpdateRequest) (*models.FeatureFlag, error) {
+	// 1. 读取当前状态（用于审计记录）
+	previous, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 写入Redis（不过期）
+	now := time.Now()
+	flag := &models.FeatureFlag{
+		Name:      name,
+		Enabled:   *req.Enabled,
+		Message:   req.Message,
+		UpdatedBy: adminID,
+		UpdatedAt: &now,
+	}
+	value, err := json.Marshal(flag)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, featureFlagKeyPrefix+name, string(value), 0); err != nil {
+		return nil, err
+	}
+
+	// 3. 立即在本实例生效，其他实例在refresh间隔内生效
+	s.mu.Lock()
+	s.flags[name] = &cachedFlag{flag: flag, fetchedAt: now}
+	s.mu.Unlock()
+
+	// 4. 审计日志（数据库记录失败时日志中仍保留完整信息）
+	logger.WithCtx(ctx).Info("feature flag changed",
+		zap.String("flag", name),
+		zap.Bool("previous", previous.Enabled),
+		zap.Bool("enabled", flag.Enabled),
+		zap.String("message", flag.Message),
+		zap.Uint("admin_id", adminID),
+		zap.String("ip", ip),
+	)
+	change := &models.FeatureFlagChange{
+		Flag:      name,
+		Previous:  previous.Enabled,
+		Enabled:   flag.Enabled,
+		Message:   flag.Message,
+		ChangedBy: adminID,
+		IP:        ip,
+	}
+	if err := s.repo.CreateChange(ctx, change); err != nil {
+		logger.WithCtx(ctx).Error("failed to record feature flag change",
+			zap.String("flag", name),
+			zap.Error(err),
+		)
+	}
+
+	return flag, nil
+}
+
+// ListChanges 查询最近的开关变更记录
+func (s *FeatureFlagService) ListChanges(ctx context.Context, limit int) ([]*models.FeatureFlagChange, error) {
+	return s.repo.ListChanges(ctx, limit)
+}
+
+// Check 功能开关关闭时返回FeatureDisabledError
+func (s *FeatureFlagService) Check(ctx context.Context, name string) error {
+	flag, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !flag.Enabled {
+		return &FeatureDisabledError{Data: &models.FeatureDisabledData{Feature: name, Message: flag.Message}}
+	}
+	return nil
+}
+
+// CheckWritable 只读维护模式开启时返回FeatureDisabledError
+func (s *FeatureFlagService) CheckWritable(ctx context.Context) error {
+	flag, err := s.Get(ctx, models.FlagMaintenanceReadOnly)
+	if err != nil {
+		return err
+	}
+	if flag.Enabled {
+		return &FeatureDisabledError{Data: &models.FeatureDisabledData{Feature: models.FlagMaintenanceReadOnly, Message: flag.Message}}
+	}
+	return nil
+}
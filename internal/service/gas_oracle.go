@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// gasTierPercentiles 三档价格对应的小费分位数（slow、standard、fast）
+var gasTierPercentiles = []float64{10, 50, 90}
+
+const (
+	// gasPriceSmoothing eth_gasPrice平滑系数（新样本权重，百分比）
+	gasPriceSmoothing = 30
+	// gasSlowPercent、gasFastPercent 节点不支持eth_feeHistory时slow与fast相对standard的比例（百分比）
+	gasSlowPercent = 90
+	gasFastPercent = 125
+)
+
+// ErrGasChainUnsupported 没有连接该链的节点，无法提供Gas价格
+var ErrGasChainUnsupported = apperr.New("error.gas_chain_unsupported", "gas prices are not available for this chain")
+
+// GasOracle Gas价格预言机（按链维护slow、standard、fast三档价格，缓存在Redis中供所有实例读取）
+type GasOracle struct {
+	clients    map[int]blockchain.BlockchainClient
+	params     map[int]blockchain.ChainParams // 各链的参数（legacy Gas模型、gas价格下限）
+	cache      cache.Cache
+	blockCount uint64        // eth_feeHistory采样的区块数
+	ttl        time.Duration // 缓存有效期（Worker停止刷新后过期，改为实时计算）
+
+	mu       sync.Mutex
+	smoothed map[int]*big.Int // 各链平滑后的eth_gasPrice（仅回退计算使用）
+}
+
+// NewGasOracle 创建Gas价格预言机实例
+func NewGasOracle(cache cache.Cache, blockCount uint64, ttl time.Duration, clients ...blockchain.BlockchainClient) *GasOracle {
+	o := &GasOracle{
+		clients:    make(map[int]blockchain.BlockchainClient, len(clients)),
+		params:     make(map[int]blockchain.ChainParams, len(clients)),
+		cache:      cache,
+		blockCount: blockCount,
+		ttl:        ttl,
+		smoothed:   make(map[int]*big.Int),
+	}
+	for _, client := range clients {
+		o.clients[client.GetChainID()] = client
+		o.params[client.GetChainID()] = blockchain.DefaultChainParams(client.GetChainID())
+	}
+	return o
+}
+
+// SetChainParams 设置链参数（覆盖默认值）
+func (o *GasOracle) SetChainParams(params ...blockchain.ChainParams) {
+	for _, p := range params {
+		o.params[p.ChainID] = p
+	}
+}
+
+// gasTiersKey 三档价格缓存键
+func gasTiersKey(chainID int) string {
+	return fmt.Sprintf("gas:tiers:%d", chainID)
+}
+
+// GetTiers 获取链的三档价格（缓存未命中时实时计算）
+func (o *GasOracle) GetTiers(ctx context.Context, chainID int) (*models.GasTiers, error) {
+	if _, ok := o.clients[chainID]; !ok {
+		return nil, ErrGasChainUnsupported
+	}
+
+	if value, ok := readCache(ctx, o.cache, gasTiersKey(chainID)); ok {
+		var tiers models.GasTiers
+		if err := json.Unmarshal([]byte(value), &tiers); err == nil {
+			return &tiers, nil
+		}
+	}
+	return o.Refresh(ctx, chainID)
+}
+
+// CachedTiers 只读取缓存中的三档价格（不查询链节点），没有连接该链的节点或缓存未命中时返回false
+func (o *GasOracle) CachedTiers(ctx context.Context, chainID int) (*models.GasTiers, bool) {
+	if _, ok := o.clients[chainID]; !ok {
+		return nil, false
+	}
+	value, ok := readCache(ctx, o.cache, gasTiersKey(chainID))
+	if !ok {
+		return nil, false
+	}
+	var tiers models.GasTiers
+	if err := json.Unmarshal([]byte(value), &tiers); err != nil {
+		return nil, false
+	}
+	return &tiers, true
+}
+
+// Price 获取指定档位的价格（Wei）
+func (o *GasOracle) Price(ctx context.Context, chainID int, speed models.GasSpeed) (*big.Int, error) {
+	tiers, err := o.GetTiers(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	price, ok := new(big.Int).SetString(tiers.Price(speed), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid cached gas price %q", tiers.Price(speed))
+	}
+	return price, nil
+}
+
+// Estimate 返回三档价格及按gasLimit计算的预估费用（gasLimit为0时使用链的原生币转账gas用量）
+func (o *GasOracle) Estimate(ctx context.Context, chainID int, gasLimit int64) (*models.GasPriceResponse, error) {
+	tiers, err := o.GetTiers(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if gasLimit == 0 {
+		gasLimit = int64(o.params[chainID].NativeGasLimit)
+	}
+
+	resp := &models.GasPriceResponse{
+		ChainID:   chainID,
+		GasLimit:  gasLimit,
+		Tiers:     make(map[models.GasSpeed]models.GasTierEstimate, 3),
+		BaseFee:   tiers.BaseFee,
+		Source:    tiers.Source,
+		UpdatedAt: tiers.UpdatedAt,
+	}
+	for _, speed := range []models.GasSpeed{models.GasSpeedSlow, models.GasSpeedStandard, models.GasSpeedFast} {
+		price := utils.DecimalToWei(tiers.Price(speed))
+		fee := new(big.Int).Mul(price, big.NewInt(gasLimit))
+		resp.Tiers[speed] = models.GasTierEstimate{
+			GasPrice:     price.String(),
+			EstimatedFee: utils.WeiToEthString(fee),
+		}
+	}
+	return resp, nil
+}
+
+// Refresh 重新计算链的三档价格并写入缓存（优先使用eth_feeHistory，legacy Gas模型的链或节点不支持时使用平滑后的eth_gasPrice）
+func (o *GasOracle) Refresh(ctx context.Context, chainID int) (*models.GasTiers, error) {
+	client, ok := o.clients[chainID]
+	if !ok {
+		return nil, ErrGasChainUnsupported
+	}
+	params := o.params[chainID]
+
+	var tiers *models.GasTiers
+	var err error
+	if !params.LegacyGas {
+		if tiers, err = o.fromFeeHistory(ctx, client); err != nil {
+			logger.WithCtx(ctx).Debug("fee history unavailable, falling back to gas price",
+				zap.Int("chain_id", chainID),
+				zap.Error(err),
+			)
+		}
+	}
+	if tiers == nil {
+		if tiers, err = o.fromGasPrice(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+	tiers.Slow = applyFloor(params, tiers.Slow)
+	tiers.Standard = applyFloor(params, tiers.Standard)
+	tiers.Fast = applyFloor(params, tiers.Fast)
+	tiers.ChainID = chainID
+	tiers.UpdatedAt = time.Now()
+
+	if data, err := json.Marshal(tiers); err == nil {
+		if err := o.cache.Set(ctx, gasTiersKey(chainID), string(data), o.ttl); err != nil {
+			logger.WithCtx(ctx).Warn("failed to cache gas tiers", zap.Int("chain_id", chainID), zap.Error(err))
+		}
+	}
+	return tiers, nil
+}
+
+// fromFeeHistory 下一个区块的基础费用加上各分位数小费的中位数
+func (o *GasOracle) fromFeeHistory(ctx context.Context, client blockchain.BlockchainClient) (*models.GasTiers, error) {
+	history, err := client.FeeHistory(ctx, o.blockCount, gasTierPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 || len(history.Reward) == 0 {
+		return nil, errors.New("empty fee history")
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	prices := make([]*big.Int, len(gasTierPercentiles))
+	for i := range gasTierPercentiles {
+		tips := make([]*big.Int, 0, len(history.Reward))
+		for _, rewards := range history.Reward {
+			if i < len(rewards) && rewards[i] != nil {
+				tips = append(tips, rewards[i])
+			}
+		}
+		prices[i] = new(big.Int).Add(baseFee, median(tips))
+	}
+
+	// 保证slow <= standard <= fast
+	for i := 1; i < len(prices); i++ {
+		if prices[i].Cmp(prices[i-1]) < 0 {
+			prices[i] = prices[i-1]
+		}
+	}
+
+	return &models.GasTiers{
+		Slow:     prices[0].String(),
+		Standard: prices[1].String(),
+		Fast:     prices[2].String(),
+		BaseFee:  baseFee.String(),
+		Source:   models.GasSourceFeeHistory,
+	}, nil
+}
+
+// fromGasPrice 对eth_gasPrice做指数平滑，slow与fast按固定比例计算
+func (o *GasOracle) fromGasPrice(ctx context.Context, client blockchain.BlockchainClient) (*models.GasTiers, error) {
+	sample, err := client.GetGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	standard := sample
+	if previous, ok := o.smoothed[client.GetChainID()]; ok {
+		// standard = previous + (sample - previous) × smoothing%
+		delta := new(big.Int).Sub(sample, previous)
+		delta.Mul(delta, big.NewInt(gasPriceSmoothing))
+		delta.Quo(delta, big.NewInt(100))
+		standard = new(big.Int).Add(previous, delta)
+	}
+	o.smoothed[client.GetChainID()] = standard
+	o.mu.Unlock()
+
+	return &models.GasTiers{
+		Slow:     percentOf(standard, gasSlowPercent).String(),
+		Standard: standard.String(),
+		Fast:     percentOf(standard, gasFastPercent).String(),
+		Source:   models.GasSourceGasPrice,
+	}, nil
+}
+
+// Run 按间隔刷新所有链的三档价格，直到ctx取消
+func (o *GasOracle) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for chainID := range o.clients {
+			if _, err := o.Refresh(ctx, chainID); err != nil && ctx.Err() == nil {
+				logger.Warn("failed to refresh gas tiers", zap.Int("chain_id", chainID), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// median 中位数（空列表返回0）
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return new(big.Int).Set(sorted[len(sorted)/2])
+}
+
+// percentOf value × percent / 100
+func percentOf(value *big.Int, percent int64) *big.Int {
+	result := new(big.Int).Mul(value, big.NewInt(percent))
+	return result.Quo(result, big.NewInt(100))
+}
+
+// applyFloor 按链的gas价格下限调整十进制价格
+func applyFloor(params blockchain.ChainParams, price string) string {
+	return params.ApplyGasPriceFloor(utils.DecimalToWei(price)).String()
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/blockchain/mock"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/pkg/cache"
+)
+
+func TestMain(m *testing.M) {
+	logger.Logger = zap.NewNop()
+	testutil.RegisterChains()
+	os.Exit(m.Run())
+}
+
+// testEnv 服务层测试环境：SQLite数据库、miniredis、内存区块链客户端与按生产方式组装的服务
+type testEnv struct {
+	db    *gorm.DB
+	chain *mock.Client
+	redis *cache.RedisCache
+	cache cache.Cache
+
+	userRepo    *repository.UserRepository
+	walletRepo  *repository.WalletRepository
+	txRepo      *repository.TransactionRepository
+	outboxRepo  *repository.OutboxRepository
+	contactRepo *repository.ContactRepository
+
+	events    *EventService
+	contacts  *ContactService
+	activity  *ActivityService
+	wallets   *WalletService
+	whitelist *WhitelistService
+	limits    *LimitService
+	txs       *TransactionService
+}
+
+// newTestEnv 创建测试环境（余额缓存使用miniredis）
+func newTestEnv(t *testing.T) *testEnv {
+	return newTestEnvWithCache(t, nil)
+}
+
+// newTestEnvWithCache 创建测试环境，余额缓存使用指定实现（用于注入故障），为nil时使用miniredis
+func newTestEnvWithCache(t *testing.T, c cache.Cache) *testEnv {
+	t.Helper()
+	db := testutil.NewDB(t)
+	redis, _ := testutil.NewRedis(t)
+	chain := mock.NewClient(testutil.ChainID)
+	chains := blockchain.NewClients(chain)
+	if c == nil {
+		c = redis
+	}
+
+	env := &testEnv{
+		db:          db,
+		chain:       chain,
+		redis:       redis,
+		cache:       c,
+		userRepo:    repository.NewUserRepository(db, testutil.EmailHMACKey),
+		walletRepo:  repository.NewWalletRepository(db),
+		txRepo:      repository.NewTransactionRepository(db),
+		outboxRepo:  repository.NewOutboxRepository(db),
+		contactRepo: repository.NewContactRepository(db),
+	}
+	env.events = NewEventService(redis)
+	env.contacts = NewContactService(env.contactRepo)
+	env.activity = NewActivityService(repository.NewActivityRepository(db), env.txRepo, env.walletRepo, env.contacts)
+	env.wallets = NewWalletService(env.walletRepo, env.userRepo, chains, c, env.events, nil, env.activity, testutil.EncryptionKey)
+	t.Cleanup(env.wallets.Close)
+	env.whitelist = NewWhitelistService(repository.NewWhitelistRepository(db), env.wallets, env.activity, 0)
+	env.limits = NewLimitService(repository.NewSpendLedgerRepository(db))
+	env.txs = NewTransactionService(env.txRepo, env.walletRepo, env.wallets, chains, env.events, env.contacts, env.whitelist, env.limits)
+	return env
+}
+
+var userSeq atomic.Int64
+
+// createUser 创建测试用户
+func (e *testEnv) createUser(t *testing.T) *models.User {
+	t.Helper()
+	n := userSeq.Add(1)
+	user := &models.User{
+		Username: fmt.Sprintf("user%d", n),
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Password: "unused",
+	}
+	if err := e.userRepo.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// createWallet 为用户创建钱包并设置链上余额（Wei）
+func (e *testEnv) createWallet(t *testing.T, userID uint, balanceWei *big.Int) *models.Wallet {
+	t.Helper()
+	wallet, err := e.wallets.CreateWallet(context.Background(), userID, &models.WalletCreateRequest{
+		ChainID: testutil.ChainID,
+		Name:    "test",
+	})
+	if err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	// 等待创建时的异步余额刷新写入缓存（缓存不可用时不等待），再设置余额并清除缓存
+	cacheKey := "balance:" + wallet.Address
+	waitFor(t, "initial balance refresh", func() bool {
+		_, err := e.cache.Get(context.Background(), cacheKey)
+		return !errors.Is(err, cache.ErrNotFound)
+	})
+	e.chain.SetBalance(wallet.Address, balanceWei)
+	e.cache.Delete(context.Background(), cacheKey)
+	return wallet
+}
+
+// ether 以ETH为单位的Wei金额
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+// waitFor 轮询直到条件成立（用于异步更新），超时时测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+)
+
+// 内部转账的合成交易哈希（不是链上交易哈希）：前缀 + 随机十六进制
+const (
+	internalTxHashPrefix = "internal-"
+	internalTxHashBytes  = 28
+)
+
+var (
+	// ErrInternalRecipient 内部转账的收款方不是当前用户可访问的同链钱包
+	ErrInternalRecipient = apperr.New("error.internal_recipient", "internal transfers require a recipient wallet you can access on the same chain")
+	// ErrInsufficientLedgerBalance 发送方账本余额不足
+	ErrInsufficientLedgerBalance = apperr.New("error.insufficient_balance", "insufficient balance")
+)
+
+// IsTransactionHash 是否为交易记录使用的哈希格式：链上交易哈希或内部转账的合成哈希
+func IsTransactionHash(hash string) bool {
+	suffix, ok := strings.CutPrefix(hash, internalTxHashPrefix)
+	if !ok {
+		return utils.IsTxHash(hash)
+	}
+	_, err := hex.DecodeString(suffix)
+	return len(suffix) == 2*internalTxHashBytes && err == nil
+}
+
+// internalNet 钱包尚未在链上结算的内部转账净额（Wei，转入为正）
+func internalNet(wallet *models.Wallet) *big.Int {
+	return utils.DecimalToWei(wallet.InternalNetWei)
+}
+
+// spendableOnChain 可通过链上交易转出的余额：内部转出的金额仍在链上地址中，但已记入收款方账本，不能再次转出
+func spendableOnChain(wallet *models.Wallet, onChain *big.Int) *big.Int {
+	if net := internalNet(wallet); net.Sign() < 0 {
+		return new(big.Int).Add(onChain, net)
+	}
+	return onChain
+}
+
+// sendInternal 内部转账：在同一数据库事务中移动双方账本余额并记录交易，不签名、不上链
+func (s *TransactionService) sendInternal(ctx context.Context, userID uint, wallet *models.Wallet, out *outgoingTx) (*models.Transaction, error) {
+	// 维护期间暂停发送，已归档或所在链被禁用的钱包不能转出
+	if err := s.checkSendingEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if wallet.Archived {
+		return nil, ErrWalletArchived
+	}
+	if err := checkChainEnabled(wallet.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 内部转账立即生效，超过审批阈值时只能通过链上审批流程发送
+	if wallet.RequiresApproval(out.Value) {
+		return nil, ErrApprovalRequired
+	}
+
+	// 1. 收款方必须是当前用户可访问的同链钱包
+	recipient, err := s.walletRepo.GetByAddress(ctx, common.HexToAddress(out.To).Hex())
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, ErrInternalRecipient
+		}
+		return nil, err
+	}
+	if recipient.ChainID != wallet.ChainID {
+		return nil, ErrInternalRecipient
+	}
+	if _, err := authorizeWallet(ctx, s.walletRepo, userID, recipient, PermView); err != nil {
+		if errors.Is(err, ErrPermissionDenied) || errors.Is(err, ErrWalletNotFound) {
+			return nil, ErrInternalRecipient
+		}
+		return nil, err
+	}
+
+	// 2. 校验钱包口令（口令保护的钱包转出资金同样需要口令）
+	if _, err := s.walletService.GetPrivateKey(ctx, wallet.Address, out.Passphrase); err != nil {
+		return nil, err
+	}
+
+	// 3. 占用每日限额（转账未完成时释放）
+	reservation, err := s.limitService.Reserve(ctx, wallet, out.Value)
+	if err != nil {
+		return nil, err
+	}
+	done := false
+	defer func() {
+		if !done {
+			s.limitService.Release(context.WithoutCancel(ctx), reservation)
+		}
+	}()
+
+	// 4. 移动账本余额并保存交易记录
+	suffix, err := utils.GenerateRandomHex(internalTxHashBytes)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	transaction := &models.Transaction{
+		WalletID:    wallet.ID,
+		TxHash:      internalTxHashPrefix + suffix,
+		Type:        models.TxTypeInternal,
+		FromAddress: wallet.Address,
+		ToAddress:   recipient.Address,
+		ToENSName:   out.ToENSName,
+		Amount:      utils.WeiToEthString(out.Value),
+		GasPrice:    "0",
+		Status:      models.TxStatusSuccess,
+		ChainID:     wallet.ChainID,
+		Note:        out.Note,
+		ConfirmedAt: &now,
+	}
+	for _, tag := range out.Tags {
+		transaction.Tags = append(transaction.Tags, models.TransactionTag{Tag: tag})
+	}
+	if err := s.txRepo.CreateInternalTransfer(ctx, transaction, recipient.ID, out.Value.String()); err != nil {
+		if errors.Is(err, repository.ErrInsufficientLedgerBalance) {
+			return nil, ErrInsufficientLedgerBalance
+		}
+		return nil, err
+	}
+	done = true
+	s.limitService.Commit(ctx, reservation, transaction.TxHash)
+
+	// 5. 推送收款方入账事件
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:    models.EventDepositDetected,
+		Address: recipient.Address,
+		TxHash:  transaction.TxHash,
+		Amount:  out.Value.String(),
+		Message: "internal transfer from " + wallet.Address,
+	})
+
+	logger.WithCtx(ctx).Info("internal transfer completed",
+		zap.String("tx_hash", transaction.TxHash),
+		zap.String("from", wallet.Address),
+		zap.String("to", recipient.Address),
+		zap.String("amount_wei", out.Value.String()),
+	)
+	return transaction, nil
+}
+
+// InternalLedgerReport 对账报告：列出存在未结算内部转账净额的钱包，与实时链上余额比较并标记异常
+func (s *TransactionService) InternalLedgerReport(ctx context.Context) (*models.InternalLedgerReport, error) {
+	// 1. 查询存在内部转账净额的钱包
+	wallets, err := s.walletRepo.ListWithInternalNet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.InternalLedgerReport{
+		Chains:      []*models.InternalLedgerChain{},
+		Wallets:     make([]*models.InternalLedgerEntry, 0, len(wallets)),
+		GeneratedAt: time.Now(),
+	}
+	totals := make(map[int]*big.Int)
+	var chainIDs []int
+
+	// 2. 逐个比较账本余额与实时链上余额
+	for _, wallet := range wallets {
+		net := internalNet(wallet)
+		ledger := utils.DecimalToWei(wallet.Balance)
+		recorded := new(big.Int).Sub(ledger, net)
+		entry := &models.InternalLedgerEntry{
+			WalletID:        wallet.ID,
+			Address:         wallet.Address,
+			ChainID:         wallet.ChainID,
+			LedgerBalance:   ledger.String(),
+			InternalNet:     net.String(),
+			RecordedOnChain: recorded.String(),
+		}
+		if onChain, err := s.walletService.fetchBalance(ctx, wallet.ChainID, wallet.Address); err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.OnChain = onChain.String()
+			entry.Unbacked = ledger.Cmp(onChain) > 0
+			entry.Drift = recorded.Cmp(onChain) != 0
+		}
+		if entry.Unbacked || entry.Drift {
+			report.Flagged++
+		}
+		report.Wallets = append(report.Wallets, entry)
+
+		if _, ok := totals[wallet.ChainID]; !ok {
+			totals[wallet.ChainID] = new(big.Int)
+			chainIDs = append(chainIDs, wallet.ChainID)
+		}
+		totals[wallet.ChainID].Add(totals[wallet.ChainID], net)
+	}
+
+	// 3. 按链汇总净额（钱包按链排序，chainIDs已有序）
+	for _, chainID := range chainIDs {
+		report.Chains = append(report.Chains, &models.InternalLedgerChain{
+			ChainID:  chainID,
+			NetTotal: totals[chainID].String(),
+			Balanced: totals[chainID].Sign() == 0,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// setLedger 设置链上余额并刷新，等待账本余额（链上余额 + 内部转账净额）写入数据库
+func (e *testEnv) setLedger(t *testing.T, wallet *models.Wallet, onChain, ledger int64) {
+	t.Helper()
+	e.chain.SetBalance(wallet.Address, big.NewInt(onChain))
+	want := big.NewInt(ledger).String()
+	waitFor(t, "ledger balance", func() bool {
+		if _, err := e.wallets.RefreshBalance(context.Background(), wallet.UserID, wallet.Address); err != nil {
+			t.Fatalf("refresh balance: %v", err)
+		}
+		saved, err := e.walletRepo.GetByAddress(context.Background(), wallet.Address)
+		return err == nil && saved.Balance == want
+	})
+}
+
+// assertLedger 校验钱包的账本余额与内部转账净额（Wei）
+func (e *testEnv) assertLedger(t *testing.T, wallet *models.Wallet, balance, net int64) {
+	t.Helper()
+	saved, err := e.walletRepo.GetByAddress(context.Background(), wallet.Address)
+	if err != nil {
+		t.Fatalf("get wallet: %v", err)
+	}
+	if got := saved.Balance; got != big.NewInt(balance).String() {
+		t.Errorf("%s balance = %s, want %d", wallet.Address, got, balance)
+	}
+	if got := internalNet(saved); got.Int64() != net {
+		t.Errorf("%s internal net = %s, want %d", wallet.Address, got, net)
+	}
+}
+
+// setDailyLimit 设置钱包每日转出限额（Wei），转账时占用额度
+func (e *testEnv) setDailyLimit(t *testing.T, wallet *models.Wallet, wei int64) {
+	t.Helper()
+	if err := e.db.Model(&models.Wallet{}).Where("id = ?", wallet.ID).Update("daily_limit_wei", big.NewInt(wei).String()).Error; err != nil {
+		t.Fatalf("set daily limit: %v", err)
+	}
+}
+
+// sendInternalTransfer 发起内部转账
+func (e *testEnv) sendInternalTransfer(userID uint, from, to string, wei int64) (*models.Transaction, error) {
+	return e.txs.SendTransaction(context.Background(), userID, &models.TransactionCreateRequest{
+		FromAddress: from,
+		ToAddress:   to,
+		Amount:      big.NewInt(wei).String(),
+		ChainID:     testutil.ChainID,
+		Internal:    true,
+	})
+}
+
+func TestInternalTransferMovesLedgerBalances(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	from := env.createWallet(t, user.ID, ether(0))
+	to := env.createWallet(t, user.ID, ether(0))
+	env.setLedger(t, from, 1000, 1000)
+	env.setDailyLimit(t, from, 5000)
+
+	// 按数值比较余额：按字符串比较时"1000" >= "400"不成立
+	tx, err := env.sendInternalTransfer(user.ID, from.Address, to.Address, 400)
+	if err != nil {
+		t.Fatalf("internal transfer: %v", err)
+	}
+	if tx.Type != models.TxTypeInternal || tx.Status != models.TxStatusSuccess || !strings.HasPrefix(tx.TxHash, internalTxHashPrefix) {
+		t.Errorf("transaction = type %s status %s hash %s, want successful internal transfer", tx.Type, tx.Status, tx.TxHash)
+	}
+	if sent := env.chain.SentTransactions(); len(sent) != 0 {
+		t.Errorf("broadcast transactions = %d, want 0", len(sent))
+	}
+	env.assertLedger(t, from, 600, -400)
+	env.assertLedger(t, to, 400, 400)
+
+	// 每日限额占用关联到内部转账
+	var entry models.SpendLedgerEntry
+	if err := env.db.Where("wallet_id = ?", from.ID).First(&entry).Error; err != nil {
+		t.Fatalf("load spend ledger: %v", err)
+	}
+	if entry.TxHash != tx.TxHash {
+		t.Errorf("spend ledger tx_hash = %q, want %q", entry.TxHash, tx.TxHash)
+	}
+
+	// 链上余额刷新保留未结算的净额
+	env.setLedger(t, to, 100, 500)
+	env.assertLedger(t, to, 500, 400)
+}
+
+func TestInternalTransferRejected(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	other := env.createUser(t)
+	from := env.createWallet(t, user.ID, ether(0))
+	to := env.createWallet(t, user.ID, ether(0))
+	foreign := env.createWallet(t, other.ID, ether(0))
+	otherChain := env.createWallet(t, user.ID, ether(0))
+	if err := env.db.Model(&models.Wallet{}).Where("id = ?", otherChain.ID).Update("chain_id", 1).Error; err != nil {
+		t.Fatalf("move wallet to another chain: %v", err)
+	}
+	env.setLedger(t, from, 1000, 1000)
+	env.setDailyLimit(t, from, 5000)
+
+	tests := []struct {
+		name    string
+		to      string
+		amount  int64
+		wantErr error
+	}{
+		{"insufficient balance", to.Address, 1001, ErrInsufficientLedgerBalance},
+		{"recipient on another chain", otherChain.Address, 100, ErrInternalRecipient},
+		{"recipient not in the system", "0x" + "33333333333333333333333333333333333333bb", 100, ErrInternalRecipient},
+		{"recipient owned by another user", foreign.Address, 100, ErrInternalRecipient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.sendInternalTransfer(user.ID, from.Address, tt.to, tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 没有移动余额、保存交易或占用每日限额
+	env.assertLedger(t, from, 1000, 0)
+	env.assertLedger(t, to, 0, 0)
+	var transactions, reservations int64
+	env.db.Model(&models.Transaction{}).Count(&transactions)
+	env.db.Model(&models.SpendLedgerEntry{}).Count(&reservations)
+	if transactions != 0 || reservations != 0 {
+		t.Errorf("transactions = %d, spend ledger entries = %d; want 0", transactions, reservations)
+	}
+}
+
+func TestInternalLedgerReport(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	from := env.createWallet(t, user.ID, ether(0))
+	to := env.createWallet(t, user.ID, ether(0))
+	env.createWallet(t, user.ID, ether(0)) // 没有内部转账的钱包不在报告中
+	env.setLedger(t, from, 1000, 1000)
+	if _, err := env.sendInternalTransfer(user.ID, from.Address, to.Address, 400); err != nil {
+		t.Fatalf("internal transfer: %v", err)
+	}
+
+	report, err := env.txs.InternalLedgerReport(ctx)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if len(report.Chains) != 1 || report.Chains[0].NetTotal != "0" || !report.Chains[0].Balanced {
+		t.Errorf("chains = %+v, want one balanced chain", report.Chains)
+	}
+	entries := make(map[string]*models.InternalLedgerEntry)
+	for _, entry := range report.Wallets {
+		entries[entry.Address] = entry
+	}
+	if len(entries) != 2 {
+		t.Fatalf("report wallets = %d, want 2", len(entries))
+	}
+	// 收款方账本余额尚未在链上结算
+	if e := entries[from.Address]; e.Unbacked || e.Drift || e.RecordedOnChain != "1000" {
+		t.Errorf("sender entry = %+v, want backed without drift", e)
+	}
+	if e := entries[to.Address]; !e.Unbacked || e.Drift || e.OnChain != "0" {
+		t.Errorf("recipient entry = %+v, want unbacked without drift", e)
+	}
+	if report.Flagged != 1 {
+		t.Errorf("flagged = %d, want 1", report.Flagged)
+	}
+
+	// 链上余额变化尚未同步时标记drift
+	env.chain.SetBalance(from.Address, big.NewInt(1500))
+	report, err = env.txs.InternalLedgerReport(ctx)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	for _, e := range report.Wallets {
+		if e.Address == from.Address && (!e.Drift || e.Unbacked || e.OnChain != "1500") {
+			t.Errorf("sender entry after deposit = %+v, want drift", e)
+		}
+	}
+	if report.Flagged != 2 {
+		t.Errorf("flagged after deposit = %d, want 2", report.Flagged)
+	}
+}
+
+func TestIsTransactionHash(t *testing.T) {
+	internalHash := internalTxHashPrefix + strings.Repeat("0f", internalTxHashBytes)
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"onchain hash", "0x" + strings.Repeat("ab", 32), true},
+		{"onchain hash mixed case", "0x" + strings.Repeat("aB", 32), true},
+		{"onchain hash missing 0x", strings.Repeat("ab", 32), false},
+		{"onchain hash wrong length", "0x" + strings.Repeat("ab", 31), false},
+		{"onchain hash non-hex", "0x" + strings.Repeat("zz", 32), false},
+		{"internal hash", internalHash, true},
+		{"internal hash wrong length", internalHash[:len(internalHash)-2], false},
+		{"internal hash non-hex", internalTxHashPrefix + strings.Repeat("zz", internalTxHashBytes), false},
+		{"internal prefix only", internalTxHashPrefix, false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransactionHash(tt.value); got != tt.want {
+				t.Errorf("IsTransactionHash(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
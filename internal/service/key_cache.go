@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyCacheEntry 缓存的私钥
+type keyCacheEntry struct {
+	key       *ecdsa.PrivateKey
+	expiresAt time.Time
+}
+
+// keyCache 进程内私钥缓存（仅存于内存，不写入Redis；过期、淘汰与关闭时清零私钥）
+type keyCache struct {
+	mu      sync.Mutex
+	entries map[string]*keyCacheEntry // 地址(小写) -> 私钥
+	ttl     time.Duration
+	maxSize int
+	stop    chan struct{}
+}
+
+// newKeyCache 创建私钥缓存并启动过期清理
+func newKeyCache(ttl time.Duration, maxSize int) *keyCache {
+	c := &keyCache{
+		entries: make(map[string]*keyCacheEntry),
+		ttl:     ttl,
+		maxSize: maxSize,
+		stop:    make(chan struct{}),
+	}
+	go c.janitor()
+	return c
+}
+
+// get 获取私钥副本（调用方持有的副本不受缓存淘汰影响）
+func (c *keyCache) get(address string) (*ecdsa.PrivateKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	address = strings.ToLower(address)
+	entry, ok := c.entries[address]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		c.evictLocked(address)
+		return nil, false
+	}
+	return copyPrivateKey(entry.key), true
+}
+
+// put 缓存私钥副本，超出容量时淘汰最早过期的条目
+func (c *keyCache) put(address string, key *ecdsa.PrivateKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	address = strings.ToLower(address)
+	if _, ok := c.entries[address]; ok {
+		c.evictLocked(address)
+	}
+	for len(c.entries) >= c.maxSize {
+		var oldest string
+		var oldestAt time.Time
+		for addr, entry := range c.entries {
+			if oldest == "" || entry.expiresAt.Before(oldestAt) {
+				oldest, oldestAt = addr, entry.expiresAt
+			}
+		}
+		c.evictLocked(oldest)
+	}
+	c.entries[address] = &keyCacheEntry{key: copyPrivateKey(key), expiresAt: time.Now().Add(c.ttl)}
+}
+
+// remove 移除并清零指定地址的私钥
+func (c *keyCache) remove(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictLocked(strings.ToLower(address))
+}
+
+// close 停止过期清理并清零所有私钥
+func (c *keyCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.stop:
+		return
+	default:
+		close(c.stop)
+	}
+	for address := range c.entries {
+		c.evictLocked(address)
+	}
+}
+
+// janitor 定期清理过期私钥
+func (c *keyCache) janitor() {
+	ticker := time.NewTicker(c.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			now := time.Now()
+			for address, entry := range c.entries {
+				if now.After(entry.expiresAt) {
+					c.evictLocked(address)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// evictLocked 清零并移除条目（调用方需持有锁）
+func (c *keyCache) evictLocked(address string) {
+	if entry, ok := c.entries[address]; ok {
+		zeroPrivateKey(entry.key)
+		delete(c.entries, address)
+	}
+}
+
+// copyPrivateKey 深拷贝私钥（D独立分配，清零原值不影响副本）
+func copyPrivateKey(key *ecdsa.PrivateKey) *ecdsa.PrivateKey {
+	dup := *key
+	dup.D = new(big.Int).Set(key.D)
+	return &dup
+}
+
+// zeroPrivateKey 覆写私钥D的底层数据
+func zeroPrivateKey(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	words := key.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	key.D.SetInt64(0)
+}
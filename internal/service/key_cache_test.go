@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+func TestKeyCacheReturnsIndependentCopies(t *testing.T) {
+	c := newKeyCache(time.Minute, 10)
+	defer c.close()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	want := key.D.String()
+	address := "0xAbCdEf0000000000000000000000000000000001"
+
+	// 调用方清零自己持有的私钥不影响缓存
+	c.put(address, key)
+	zeroPrivateKey(key)
+	got, ok := c.get(strings.ToLower(address))
+	if !ok || got.D.String() != want {
+		t.Fatalf("get = %v, %v, want cached key", ok, got)
+	}
+	zeroPrivateKey(got)
+	if again, ok := c.get(address); !ok || again.D.String() != want {
+		t.Errorf("cached key changed after zeroing a returned copy")
+	}
+}
+
+func TestKeyCacheWipesOnEvict(t *testing.T) {
+	c := newKeyCache(time.Hour, 2)
+	defer c.close()
+
+	// cached 返回缓存内部持有的私钥（用于检查是否已清零）
+	cached := func(address string) func() bool {
+		entry := c.entries[strings.ToLower(address)]
+		return func() bool { return entry.key.D.Sign() == 0 }
+	}
+	put := func(address string) {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		c.put(address, key)
+	}
+
+	put("0x01")
+	firstWiped := cached("0x01")
+	handedOut, _ := c.get("0x01")
+	put("0x02")
+	secondWiped := cached("0x02")
+
+	// 超出容量淘汰最早过期的条目
+	put("0x03")
+	if !firstWiped() {
+		t.Error("evicted key not wiped")
+	}
+	if _, ok := c.get("0x01"); ok {
+		t.Error("evicted key still cached")
+	}
+	if handedOut.D.Sign() == 0 {
+		t.Error("copy held by caller wiped on evict")
+	}
+
+	// 移除与过期时清零
+	c.remove("0x02")
+	if !secondWiped() {
+		t.Error("removed key not wiped")
+	}
+	thirdWiped := cached("0x03")
+	c.entries["0x03"].expiresAt = time.Now().Add(-time.Second)
+	if _, ok := c.get("0x03"); ok || !thirdWiped() {
+		t.Errorf("expired key returned = %v, wiped = %v, want miss and wiped", ok, thirdWiped())
+	}
+
+	// 关闭时清零全部
+	put("0x04")
+	fourthWiped := cached("0x04")
+	c.close()
+	if !fourthWiped() || len(c.entries) != 0 {
+		t.Error("keys not wiped on close")
+	}
+}
+
+func TestGetPrivateKeyReusesCache(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	env.wallets.EnableKeyCache(time.Minute, 10)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(0))
+	protected, err := env.wallets.CreateWallet(ctx, user.ID, &models.WalletCreateRequest{
+		ChainID:    testutil.ChainID,
+		Passphrase: "correct horse battery",
+	})
+	if err != nil {
+		t.Fatalf("create protected wallet: %v", err)
+	}
+
+	first, err := env.wallets.GetPrivateKey(ctx, wallet.Address, "")
+	if err != nil {
+		t.Fatalf("get private key: %v", err)
+	}
+	if _, err := env.wallets.GetPrivateKey(ctx, protected.Address, "correct horse battery"); err != nil {
+		t.Fatalf("get protected private key: %v", err)
+	}
+
+	// 破坏库中的密文：缓存的私钥不再解密，口令保护的私钥不缓存
+	if err := env.db.Model(&models.Wallet{}).Where("id IN ?", []uint{wallet.ID, protected.ID}).
+		Update("private_key_encrypted", "corrupted").Error; err != nil {
+		t.Fatalf("corrupt keys: %v", err)
+	}
+	second, err := env.wallets.GetPrivateKey(ctx, wallet.Address, "")
+	if err != nil {
+		t.Fatalf("get cached private key: %v", err)
+	}
+	if second.D.Cmp(first.D) != 0 {
+		t.Error("cached private key differs from the decrypted one")
+	}
+	if _, err := env.wallets.GetPrivateKey(ctx, protected.Address, "correct horse battery"); err == nil {
+		t.Error("passphrase-protected key served from cache")
+	}
+
+	// 删除钱包后移出缓存
+	if err := env.wallets.DeleteWallet(ctx, user.ID, wallet.Address); err != nil {
+		t.Fatalf("delete wallet: %v", err)
+	}
+	if _, err := env.wallets.GetPrivateKey(ctx, wallet.Address, ""); err == nil {
+		t.Error("deleted wallet's key served from cache")
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+)
+
+// labelDataset 内置的公共地址标签数据集（知名交易所地址、各链常用DeFi合约与代币合约）
+//
+//go:embed data/address_labels.json
+var labelDataset []byte
+
+// ErrLabelNotFound 地址没有公共标签，也不在用户的地址簿中
+var ErrLabelNotFound = apperr.NotFound("label")
+
+// LabelService 公共地址标签服务：标注交易对手方，未匹配时回退到用户的地址簿联系人
+type LabelService struct {
+	labelRepo   *repository.LabelRepository
+	contactRepo *repository.ContactRepository
+}
+
+// NewLabelService 创建地址标签服务实例
+func NewLabelService(labelRepo *repository.LabelRepository, contactRepo *repository.ContactRepository) *LabelService {
+	return &LabelService{
+		labelRepo:   labelRepo,
+		contactRepo: contactRepo,
+	}
+}
+
+// labelKey 按（地址, 链）匹配标签，地址统一为小写
+type labelKey struct {
+	address string
+	chainID int
+}
+
+// SeedDataset 将内置数据集同步到标签表（启动时调用，可重复执行；管理员导入的同一地址标签不会被覆盖）
+func (s *LabelService) SeedDataset(ctx context.Context) (int64, error) {
+	var entries []models.AddressLabelEntry
+	if err := json.Unmarshal(labelDataset, &entries); err != nil {
+		return 0, fmt.Errorf("parse label dataset: %w", err)
+	}
+	labels, err := newAddressLabels(entries, models.LabelSourceDataset)
+	if err != nil {
+		return 0, fmt.Errorf("label dataset: %w", err)
+	}
+	return s.labelRepo.Upsert(ctx, labels, true)
+}
+
+// Import 导入管理员提供的标签（覆盖同一地址的内置标签与此前导入的标签），操作写入审计日志
+func (s *LabelService) Import(ctx context.Context, adminID uint, ip string, req *models.AddressLabelImportRequest) (*models.AddressLabelImportResponse, error) {
+	labels, err := newAddressLabels(req.Labels, models.LabelSourceImport)
+	if err != nil {
+		return nil, apperr.Invalidf("%v", err)
+	}
+	imported, err := s.labelRepo.Upsert(ctx, labels, false)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithCtx(ctx).Info("address labels imported",
+		zap.Uint("admin_id", adminID),
+		zap.String("ip", ip),
+		zap.Int("labels", len(labels)),
+		zap.Int64("imported", imported),
+	)
+	return &models.AddressLabelImportResponse{Imported: int(imported)}, nil
+}
+
+// Lookup 查询地址在各链上的标签（chainID为0时不限链），没有公共标签时回退到用户的地址簿联系人
+func (s *LabelService) Lookup(ctx context.Context, userID uint, address string, chainID int) ([]*models.AddressLabelResponse, error) {
+	address = common.HexToAddress(address).Hex()
+
+	// 1. 公共标签
+	labels, err := s.labelRepo.GetByAddresses(ctx, []string{address})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.AddressLabelResponse, 0, len(labels))
+	for _, label := range labels {
+		if chainID == 0 || label.ChainID == chainID {
+			result = append(result, labelResponse(label.Address, label.ChainID, label.Name, label.Category, label.Source))
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	// 2. 回退到地址簿联系人
+	contacts, err := s.contactRepo.GetByAddresses(ctx, userID, []string{address})
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		if chainID == 0 || contact.ChainID == chainID {
+			result = append(result, labelResponse(address, contact.ChainID, contact.Name, models.LabelCategoryContact, models.LabelCategoryContact))
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrLabelNotFound
+	}
+	return result, nil
+}
+
+// Annotate 为交易的发送方与收款方填充标签：公共标签优先，未匹配的地址使用用户的地址簿联系人名称
+func (s *LabelService) Annotate(ctx context.Context, userID uint, txs []*models.TransactionResponse) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	// 1. 收集对手方地址（统一为校验和格式）
+	seen := make(map[string]bool, len(txs)*2)
+	addresses := make([]string, 0, len(txs)*2)
+	for _, tx := range txs {
+		for _, address := range []string{tx.FromAddress, tx.ToAddress} {
+			if !utils.IsEthAddress(address) {
+				continue
+			}
+			address = common.HexToAddress(address).Hex()
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	// 2. 批量查询公共标签
+	labels, err := s.labelRepo.GetByAddresses(ctx, addresses)
+	if err != nil {
+		return err
+	}
+	matched := make(map[labelKey]*models.CounterpartyLabel, len(labels))
+	for _, label := range labels {
+		matched[labelKey{strings.ToLower(label.Address), label.ChainID}] = &models.CounterpartyLabel{Label: label.Name, Category: label.Category}
+	}
+
+	// 3. 未匹配的地址回退到地址簿联系人
+	unmatched := make([]string, 0, len(addresses))
+	for _, tx := range txs {
+		for _, address := range []string{tx.FromAddress, tx.ToAddress} {
+			if _, ok := matched[labelKey{strings.ToLower(address), tx.ChainID}]; !ok && utils.IsEthAddress(address) {
+				unmatched = append(unmatched, address)
+			}
+		}
+	}
+	if len(unmatched) > 0 {
+		contacts, err := s.contactRepo.GetByAddresses(ctx, userID, unmatched)
+		if err != nil {
+			return err
+		}
+		for _, contact := range contacts {
+			key := labelKey{strings.ToLower(contact.Address), contact.ChainID}
+			if _, ok := matched[key]; !ok {
+				matched[key] = &models.CounterpartyLabel{Label: contact.Name, Category: models.LabelCategoryContact}
+			}
+		}
+	}
+
+	// 4. 填充标签
+	for _, tx := range txs {
+		tx.FromLabel = matched[labelKey{strings.ToLower(tx.FromAddress), tx.ChainID}]
+		tx.ToLabel = matched[labelKey{strings.ToLower(tx.ToAddress), tx.ChainID}]
+	}
+	return nil
+}
+
+// newAddressLabels 校验数据集或导入请求中的标签并统一地址格式（EIP-55校验和格式）
+func newAddressLabels(entries []models.AddressLabelEntry, source string) ([]*models.AddressLabel, error) {
+	labels := make([]*models.AddressLabel, 0, len(entries))
+	seen := make(map[labelKey]int, len(entries))
+	for i, entry := range entries {
+		switch {
+		case !utils.IsEthAddress(entry.Address):
+			return nil, fmt.Errorf("labels[%d]: invalid address %q", i, entry.Address)
+		case entry.ChainID <= 0:
+			return nil, fmt.Errorf("labels[%d]: invalid chain_id %d", i, entry.ChainID)
+		case entry.Name == "":
+			return nil, fmt.Errorf("labels[%d]: name is required", i)
+		case !slices.Contains(models.LabelCategories, entry.Category):
+			return nil, fmt.Errorf("labels[%d]: category must be one of %q", i, models.LabelCategories)
+		}
+		address := common.HexToAddress(entry.Address).Hex()
+		key := labelKey{strings.ToLower(address), entry.ChainID}
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("labels[%d]: duplicate of labels[%d]", i, first)
+		}
+		seen[key] = i
+		labels = append(labels, &models.AddressLabel{
+			Address:  address,
+			ChainID:  entry.ChainID,
+			Name:     entry.Name,
+			Category: entry.Category,
+			Source:   source,
+		})
+	}
+	return labels, nil
+}
+
+// labelResponse 构建地址标签查询结果
+func labelResponse(address string, chainID int, name, category, source string) *models.AddressLabelResponse {
+	return &models.AddressLabelResponse{
+		Address:           address,
+		ChainID:           chainID,
+		ChainName:         models.ChainName(chainID),
+		CounterpartyLabel: models.CounterpartyLabel{Label: name, Category: category},
+		Source:            source,
+	}
+}
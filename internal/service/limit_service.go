@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// limitWindow 限额统计的滚动窗口
+const limitWindow = 24 * time.Hour
+
+// DailyLimitExceededError 超出每日限额（携带剩余额度与重置时间）
+type DailyLimitExceededError struct {
+	Data *models.DailyLimitExceededData
+}
+
+func (e *DailyLimitExceededError) Error() string {
+	return fmt.Sprintf("daily transfer limit exceeded, resets at %s", e.Data.ResetAt.Format(time.RFC3339))
+}
+
+// LimitService 每日转出限额服务
+type LimitService struct {
+	spendRepo *repository.SpendLedgerRepository
+}
+
+// NewLimitService 创建限额服务实例
+func NewLimitService(spendRepo *repository.SpendLedgerRepository) *LimitService {
+	return &LimitService{
+		spendRepo: spendRepo,
+	}
+}
+
+// Reserve 校验并占用额度，未设置限额时返回nil
+func (s *LimitService) Reserve(ctx context.Context, wallet *models.Wallet, amount *big.Int) (*models.SpendLedgerEntry, error) {
+	// 1. 解析限额
+	limitWei, hasAmountLimit := new(big.Int).SetString(wallet.DailyLimitWei, 10)
+	hasAmountLimit = hasAmountLimit && limitWei.Sign() > 0
+	hasCountLimit := wallet.DailyTxLimit > 0
+	if !hasAmountLimit && !hasCountLimit {
+		return nil, nil
+	}
+
+	// 2. 在钱包锁内校验并写入台账
+	now := time.Now()
+	entry := &models.SpendLedgerEntry{
+		WalletID: wallet.ID,
+		Amount:   amount.String(),
+	}
+	err := s.spendRepo.Reserve(ctx, entry, now.Add(-limitWindow), func(usage *models.SpendUsage) error {
+		spent, _ := new(big.Int).SetString(usage.SpentWei, 10)
+		if spent == nil {
+			spent = new(big.Int)
+		}
+
+		amountExceeded := hasAmountLimit && new(big.Int).Add(spent, amount).Cmp(limitWei) > 0
+		countExceeded := hasCountLimit && usage.TxCount >= int64(wallet.DailyTxLimit)
+		if !amountExceeded && !countExceeded {
+			return nil
+		}
+
+		// 计算剩余额度与重置时间
+		data := &models.DailyLimitExceededData{ResetAt: now.Add(limitWindow)}
+		if usage.OldestAt != nil {
+			data.ResetAt = usage.OldestAt.Add(limitWindow)
+		}
+		if hasAmountLimit {
+			remaining := new(big.Int).Sub(limitWei, spent)
+			if remaining.Sign() < 0 {
+				remaining.SetInt64(0)
+			}
+			data.RemainingWei = remaining.String()
+		}
+		if hasCountLimit {
+			remaining := wallet.DailyTxLimit - int(usage.TxCount)
+			if remaining < 0 {
+				remaining = 0
+			}
+			data.RemainingCount = &remaining
+		}
+		return &DailyLimitExceededError{Data: data}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Release 释放占用的额度（交易未发出时调用）
+func (s *LimitService) Release(ctx context.Context, entry *models.SpendLedgerEntry) {
+	if entry == nil {
+		return
+	}
+	if err := s.spendRepo.Release(ctx, entry.ID); err != nil {
+		logger.WithCtx(ctx).Error("failed to release spend reservation",
+			zap.Uint("wallet_id", entry.WalletID),
+			zap.Error(err),
+		)
+	}
+}
+
+// Commit 将占用的额度关联到已发送的交易
+func (s *LimitService) Commit(ctx context.Context, entry *models.SpendLedgerEntry, txHash string) {
+	if entry == nil {
+		return
+	}
+	if err := s.spendRepo.AttachTxHash(ctx, entry.ID, txHash); err != nil {
+		logger.WithCtx(ctx).Warn("failed to attach tx hash to spend entry",
+			zap.String("tx_hash", txHash),
+			zap.Error(err),
+		)
+	}
+}
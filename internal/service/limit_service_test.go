@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+)
+
+func TestReserveConcurrentNeverExceedsLimit(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(10))
+
+	const limit, amount, goroutines = 5000, 1000, 20
+	wallet.DailyLimitWei = big.NewInt(limit).String()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved, rejected := 0, 0
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := env.limits.Reserve(context.Background(), wallet, big.NewInt(amount))
+			mu.Lock()
+			defer mu.Unlock()
+			var limitErr *DailyLimitExceededError
+			switch {
+			case err == nil:
+				reserved++
+			case errors.As(err, &limitErr):
+				rejected++
+			default:
+				t.Errorf("reserve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != limit/amount || rejected != goroutines-limit/amount {
+		t.Errorf("reserved %d, rejected %d; want %d and %d", reserved, rejected, limit/amount, goroutines-limit/amount)
+	}
+	var entries []models.SpendLedgerEntry
+	if err := env.db.Where("wallet_id = ?", wallet.ID).Find(&entries).Error; err != nil {
+		t.Fatalf("list ledger: %v", err)
+	}
+	total := new(big.Int)
+	for _, entry := range entries {
+		v, _ := new(big.Int).SetString(entry.Amount, 10)
+		total.Add(total, v)
+	}
+	if total.Cmp(big.NewInt(limit)) > 0 {
+		t.Errorf("committed %s wei, exceeds the %d wei limit", total, limit)
+	}
+}
+
+func TestReserveLimitExceededData(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(10))
+	wallet.DailyLimitWei = "1000"
+	wallet.DailyTxLimit = 2
+
+	first, err := env.limits.Reserve(context.Background(), wallet, big.NewInt(600))
+	if err != nil {
+		t.Fatalf("first reserve: %v", err)
+	}
+
+	_, err = env.limits.Reserve(context.Background(), wallet, big.NewInt(500))
+	var limitErr *DailyLimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("err = %v, want DailyLimitExceededError", err)
+	}
+	if limitErr.Data.RemainingWei != "400" {
+		t.Errorf("remaining_wei = %s, want 400", limitErr.Data.RemainingWei)
+	}
+	if limitErr.Data.RemainingCount == nil || *limitErr.Data.RemainingCount != 1 {
+		t.Errorf("remaining_count = %v, want 1", limitErr.Data.RemainingCount)
+	}
+	if want := first.CreatedAt.Add(limitWindow); !limitErr.Data.ResetAt.Equal(want) {
+		t.Errorf("reset_at = %s, want %s", limitErr.Data.ResetAt, want)
+	}
+
+	// 释放后额度恢复
+	env.limits.Release(context.Background(), first)
+	if _, err := env.limits.Reserve(context.Background(), wallet, big.NewInt(1000)); err != nil {
+		t.Errorf("reserve after release: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+)
+
+// MonitorSchedule 待确认交易的分级检查策略：交易创建越久仍未打包，检查间隔越长
+type MonitorSchedule struct {
+	BatchSize      int           // 每轮最多检查的到期交易数
+	FreshAge       time.Duration // 创建未超过该时长的交易按FreshInterval检查
+	FreshInterval  time.Duration // 新交易及已打包等待确认深度的交易的检查间隔
+	RecentAge      time.Duration // 创建未超过该时长的交易按RecentInterval检查
+	RecentInterval time.Duration // 较新交易的检查间隔
+	StaleInterval  time.Duration // 更早交易的检查间隔
+	StuckAfter     time.Duration // 创建超过该时长仍未打包的交易视为卡住
+	UnknownGrace   time.Duration // 创建超过该时长后节点仍不知道的交易标记为dropped
+}
+
+// DefaultMonitorSchedule 默认检查策略：2分钟内每10秒，2~30分钟每分钟，之后每10分钟；超过2小时未打包视为卡住，
+// 超过30分钟节点仍不知道的交易标记为dropped
+func DefaultMonitorSchedule() MonitorSchedule {
+	return MonitorSchedule{
+		BatchSize:      200,
+		FreshAge:       2 * time.Minute,
+		FreshInterval:  10 * time.Second,
+		RecentAge:      30 * time.Minute,
+		RecentInterval: time.Minute,
+		StaleInterval:  10 * time.Minute,
+		StuckAfter:     2 * time.Hour,
+		UnknownGrace:   30 * time.Minute,
+	}
+}
+
+// SetMonitorSchedule 设置待确认交易的分级检查策略
+func (s *TransactionService) SetMonitorSchedule(schedule MonitorSchedule) {
+	s.schedule = schedule
+}
+
+// nextCheckDelay 本次检查未推进到最终状态时距下次检查的间隔：已打包的交易需要跟踪确认数（回执消失时需尽快确认是否重新打包），
+// 按最短间隔检查；未打包的交易按创建时长分级退避
+func (s *TransactionService) nextCheckDelay(tx *models.Transaction) time.Duration {
+	if tx.Status == models.TxStatusConfirming {
+		return s.schedule.FreshInterval
+	}
+	switch age := time.Since(tx.CreatedAt); {
+	case age < s.schedule.FreshAge:
+		return s.schedule.FreshInterval
+	case age < s.schedule.RecentAge:
+		return s.schedule.RecentInterval
+	default:
+		return s.schedule.StaleInterval
+	}
+}
+
+// failureDelay 查询回执或交易失败时距下次检查的间隔：在正常间隔的基础上按连续失败次数指数退避，不超过StaleInterval
+func (s *TransactionService) failureDelay(tx *models.Transaction) time.Duration {
+	base := s.nextCheckDelay(tx)
+	delay := base
+	for i := 0; i < tx.CheckFailures && delay < s.schedule.StaleInterval; i++ {
+		delay *= 2
+	}
+	return max(base, min(delay, s.schedule.StaleInterval))
+}
+
+// scheduleNextCheck 将交易的下次检查推迟delay（检查成功，连续失败次数清零）
+func (s *TransactionService) scheduleNextCheck(ctx context.Context, tx *models.Transaction, delay time.Duration) {
+	if err := s.txRepo.ScheduleNextCheck(ctx, tx.ID, time.Now().Add(delay)); err != nil {
+		logger.WithCtx(ctx).Warn("failed to schedule next check", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+	}
+}
+
+// scheduleRetry 记录一次查询失败并按退避间隔推迟下次检查
+func (s *TransactionService) scheduleRetry(ctx context.Context, tx *models.Transaction) {
+	if err := s.txRepo.RecordCheckFailure(ctx, tx.ID, time.Now().Add(s.failureDelay(tx))); err != nil {
+		logger.WithCtx(ctx).Warn("failed to schedule next check", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+	}
+}
+
+// SeedMonitoring 交易创建消息到达时将交易设为立即检查（重新入队的旧交易也立即检查一次，之后按所处档位退避）
+func (s *TransactionService) SeedMonitoring(ctx context.Context, txHash string) error {
+	return s.txRepo.CheckNow(ctx, txHash)
+}
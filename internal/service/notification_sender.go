@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+)
+
+// NotificationSender 站外通知渠道
+type NotificationSender interface {
+	// Send 将通知发送给用户（pref为该通知类型的偏好，含webhook地址等渠道参数）
+	Send(ctx context.Context, user *models.User, pref *models.NotificationPreference, notification *models.Notification) error
+}
+
+// WebhookSender 以JSON POST方式将通知回调到用户配置的HTTPS地址
+type WebhookSender struct {
+	httpClient *http.Client
+}
+
+// NewWebhookSender 创建webhook发送器
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send 发送webhook（非2xx响应视为失败）
+func (s *WebhookSender) Send(ctx context.Context, user *models.User, pref *models.NotificationPreference, notification *models.Notification) error {
+	body, err := json.Marshal(notification.ToResponse())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pref.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notification-Type", string(notification.Type))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailNotificationSender 将通知渲染为邮件发布到外发邮件队列（由Worker发送）
+type EmailNotificationSender struct {
+	emails *EmailService
+}
+
+// NewEmailNotificationSender 创建邮件通知发送器
+func NewEmailNotificationSender(emails *EmailService) *EmailNotificationSender {
+	return &EmailNotificationSender{emails: emails}
+}
+
+// Send 以notification模板将通知发送到用户的注册邮箱
+func (s *EmailNotificationSender) Send(ctx context.Context, user *models.User, pref *models.NotificationPreference, notification *models.Notification) error {
+	return s.emails.Enqueue(ctx, user.Email, "notification", map[string]string{
+		"Username":  user.Username,
+		"Type":      string(notification.Type),
+		"Title":     notification.Title,
+		"Message":   notification.Message,
+		"CreatedAt": notification.CreatedAt.UTC().Format(time.RFC1123),
+	})
+}
+
+// logEmailSender 邮件渠道的默认实现（未设置邮件服务时仅记录日志）
+type logEmailSender struct{}
+
+// Send 记录待发送的邮件
+func (logEmailSender) Send(ctx context.Context, user *models.User, pref *models.NotificationPreference, notification *models.Notification) error {
+	logger.WithCtx(ctx).Info("email notification not sent, no mailer configured",
+		zap.Uint("user_id", user.ID),
+		zap.String("email", user.Email),
+		zap.String("title", notification.Title),
+	)
+	return nil
+}
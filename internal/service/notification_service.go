@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// ErrWebhookURLRequired 通知渠道为webhook时未提供回调地址
+var ErrWebhookURLRequired = apperr.New("error.webhook_url_required", "webhook_url is required for the webhook channel")
+
+// NotificationService 通知服务（将钱包事件按用户偏好转换为站内通知，并通过邮件或webhook外发）
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	userRepo         *repository.UserRepository
+	walletRepo       *repository.WalletRepository
+	orgRepo          *repository.OrganizationRepository
+	senders          map[models.NotificationChannel]NotificationSender
+}
+
+// NewNotificationService 创建通知服务实例（邮件渠道默认仅记录日志，可通过SetSender替换）
+func NewNotificationService(
+	notificationRepo *repository.NotificationRepository,
+	userRepo *repository.UserRepository,
+	walletRepo *repository.WalletRepository,
+	orgRepo *repository.OrganizationRepository,
+	webhookTimeout time.Duration,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		walletRepo:       walletRepo,
+		orgRepo:          orgRepo,
+		senders: map[models.NotificationChannel]NotificationSender{
+			models.NotificationChannelEmail:   logEmailSender{},
+			models.NotificationChannelWebhook: NewWebhookSender(webhookTimeout),
+		},
+	}
+}
+
+// SetSender 设置指定渠道的发送实现
+func (s *NotificationService) SetSender(channel models.NotificationChannel, sender NotificationSender) {
+	s.senders[channel] = sender
+}
+
+// HandleEvent 事件发布钩子：为事件关联钱包的用户生成通知（个人钱包为所有者，组织钱包为全部成员）
+func (s *NotificationService) HandleEvent(ctx context.Context, event *models.WalletEvent) {
+	notificationType, title, message, ok := describeEvent(event)
+	if !ok {
+		return
+	}
+
+	recipients, err := s.recipients(ctx, event)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to resolve notification recipients",
+			zap.String("type", string(event.Type)),
+			zap.String("address", event.Address),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, userID := range recipients {
+		s.Notify(ctx, userID, notificationType, title, message, event)
+	}
+}
+
+// Notify 按用户偏好记录站内通知并异步外发（失败仅记录日志，不影响主流程）
+func (s *NotificationService) Notify(ctx context.Context, userID uint, notificationType models.NotificationType, title, message string, data interface{}) {
+	log := logger.WithCtx(ctx).With(zap.Uint("user_id", userID), zap.String("type", string(notificationType)))
+
+	// 1. 查询偏好（未设置时使用默认值）
+	pref, err := s.notificationRepo.GetPreference(ctx, userID, notificationType)
+	if err != nil {
+		log.Warn("failed to load notification preference", zap.Error(err))
+		return
+	}
+	if pref == nil {
+		pref = models.DefaultNotificationPreference(userID, notificationType)
+	}
+	if !pref.Enabled {
+		return
+	}
+
+	// 2. 记录站内通知
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Warn("failed to marshal notification data", zap.Error(err))
+		return
+	}
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data:    string(body),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		log.Warn("failed to create notification", zap.Error(err))
+		return
+	}
+
+	// 3. 站外渠道异步发送
+	if sender, ok := s.senders[pref.Channel]; ok {
+		go s.deliver(context.WithoutCancel(ctx), sender, pref, notification)
+	}
+}
+
+// deliver 通过站外渠道发送通知
+func (s *NotificationService) deliver(ctx context.Context, sender NotificationSender, pref *models.NotificationPreference, notification *models.Notification) {
+	log := logger.WithCtx(ctx).With(
+		zap.Uint("notification_id", notification.ID),
+		zap.String("channel", string(pref.Channel)),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, notification.UserID)
+	if err != nil {
+		log.Warn("failed to load notification recipient", zap.Error(err))
+		return
+	}
+	if err := sender.Send(ctx, user, pref, notification); err != nil {
+		log.Warn("failed to deliver notification", zap.Error(err))
+	}
+}
+
+// recipients 解析事件的接收用户
+func (s *NotificationService) recipients(ctx context.Context, event *models.WalletEvent) ([]uint, error) {
+	// 1. 定向事件只通知目标用户
+	if event.UserID != 0 {
+		return []uint{event.UserID}, nil
+	}
+
+	// 2. 按地址查找钱包（非本系统钱包不通知）
+	wallet, err := s.walletRepo.GetByAddress(ctx, event.Address)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if wallet.OrgID == nil {
+		return []uint{wallet.UserID}, nil
+	}
+
+	// 3. 组织钱包通知全部成员
+	members, err := s.orgRepo.ListMembers(ctx, *wallet.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	return userIDs, nil
+}
+
+// describeEvent 将钱包事件映射为通知类型与文案，不需要通知的事件返回false
+func describeEvent(event *models.WalletEvent) (models.NotificationType, string, string, bool) {
+	switch event.Type {
+	case models.EventTransactionIncluded:
+		return models.NotificationTxIncluded, "Transaction included",
+			fmt.Sprintf("Transaction %s was included in block %d (%d/%d confirmations)", event.TxHash, event.BlockNumber, event.Confirmations, event.ConfirmationsRequired), true
+	case models.EventTransactionConfirmed:
+		if event.Status == models.TxStatusFailed {
+			message := fmt.Sprintf("Transaction %s failed in block %d", event.TxHash, event.BlockNumber)
+			if event.Message != "" {
+				message += ": " + event.Message
+			}
+			return models.NotificationTxConfirmed, "Transaction failed", message, true
+		}
+		return models.NotificationTxConfirmed, "Transaction confirmed",
+			fmt.Sprintf("Transaction %s was confirmed in block %d", event.TxHash, event.BlockNumber), true
+	case models.EventTransactionStuck:
+		return models.NotificationTxStuck, "Transaction stuck",
+			fmt.Sprintf("Transaction %s is still pending: %s", event.TxHash, event.Message), true
+	case models.EventTransactionDropped:
+		return models.NotificationTxConfirmed, "Transaction dropped",
+			fmt.Sprintf("Transaction %s was dropped: %s", event.TxHash, event.Message), true
+	case models.EventDepositDetected:
+		if event.TokenAddress != "" {
+			return models.NotificationDepositReceived, "Deposit received",
+				fmt.Sprintf("Wallet %s received %s units of token %s", event.Address, event.Amount, event.TokenAddress), true
+		}
+		return models.NotificationDepositReceived, "Deposit received",
+			fmt.Sprintf("Wallet %s received %s wei", event.Address, event.Amount), true
+	case models.EventLimitsChanged:
+		return models.NotificationLimitsChanged, "Wallet limits changed",
+			fmt.Sprintf("Daily limits of wallet %s were changed", event.Address), true
+	case models.EventNewLogin:
+		return models.NotificationNewLogin, "New sign-in detected", event.Message, true
+	case models.EventPasswordChanged:
+		return models.NotificationPasswordChanged, "Password changed", event.Message, true
+	case models.EventKeyExported:
+		return models.NotificationKeyExported, "Wallet key exported", event.Message, true
+	default:
+		return "", "", "", false
+	}
+}
+
+// List 分页查询站内通知
+func (s *NotificationService) List(ctx context.Context, userID uint, req *models.NotificationListRequest) (*models.NotificationListResponse, error) {
+	notifications, total, err := s.notificationRepo.List(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = notification.ToResponse()
+	}
+	return &models.NotificationListResponse{
+		Total:         total,
+		Unread:        unread,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		Notifications: responses,
+	}, nil
+}
+
+// MarkRead 标记通知为已读
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	return s.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+// GetPreferences 查询全部通知类型的偏好（未设置的类型返回默认值）
+func (s *NotificationService) GetPreferences(ctx context.Context, userID uint) ([]*models.NotificationPreference, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[models.NotificationType]*models.NotificationPreference, len(stored))
+	for _, pref := range stored {
+		byType[pref.Type] = pref
+	}
+	prefs := make([]*models.NotificationPreference, len(models.NotificationTypes))
+	for i, notificationType := range models.NotificationTypes {
+		if pref, ok := byType[notificationType]; ok {
+			prefs[i] = pref
+		} else {
+			prefs[i] = models.DefaultNotificationPreference(userID, notificationType)
+		}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences 更新通知偏好（只更新请求中列出的类型，同一类型重复出现时以最后一项为准）
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uint, req *models.NotificationPreferencesRequest) ([]*models.NotificationPreference, error) {
+	byType := make(map[models.NotificationType]*models.NotificationPreference, len(req.Preferences))
+	var prefs []*models.NotificationPreference
+	for _, item := range req.Preferences {
+		if item.Channel == models.NotificationChannelWebhook && item.WebhookURL == "" {
+			return nil, ErrWebhookURLRequired
+		}
+		pref, ok := byType[item.Type]
+		if !ok {
+			pref = &models.NotificationPreference{UserID: userID, Type: item.Type}
+			byType[item.Type] = pref
+			prefs = append(prefs, pref)
+		}
+		pref.Enabled = *item.Enabled
+		pref.Channel = item.Channel
+		pref.WebhookURL = item.WebhookURL
+	}
+
+	if err := s.notificationRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// ErrOrgNotFound 组织不存在或当前用户不是成员（两种情况不做区分）
+var ErrOrgNotFound = apperr.NotFound("organization")
+
+// OrganizationService 组织服务（admin及以上可修改组织设置，仅owner可管理成员）
+type OrganizationService struct {
+	orgRepo    *repository.OrganizationRepository
+	walletRepo *repository.WalletRepository
+}
+
+// NewOrganizationService 创建组织服务实例
+func NewOrganizationService(orgRepo *repository.OrganizationRepository, walletRepo *repository.WalletRepository) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:    orgRepo,
+		walletRepo: walletRepo,
+	}
+}
+
+// CreateOrg 创建组织（创建者成为所有者）
+func (s *OrganizationService) CreateOrg(ctx context.Context, userID uint, req *models.OrgCreateRequest) (*models.OrgMembership, error) {
+	org := &models.Organization{
+		Name:               req.Name,
+		MemberSendLimitWei: normalizeLimit(req.MemberSendLimitWei),
+	}
+	return s.orgRepo.Create(ctx, org, userID)
+}
+
+// ListOrgs 查询用户加入的组织
+func (s *OrganizationService) ListOrgs(ctx context.Context, userID uint) ([]*models.OrgMembership, error) {
+	return s.orgRepo.ListByUserID(ctx, userID)
+}
+
+// GetOrg 查询组织详情（任意成员可查看）
+func (s *OrganizationService) GetOrg(ctx context.Context, userID uint, orgID uint) (*models.OrgMembership, error) {
+	return s.authorizeOrg(ctx, userID, orgID, models.OrgRoleViewer)
+}
+
+// UpdateOrg 更新组织名称与member单笔限额（admin及以上）
+func (s *OrganizationService) UpdateOrg(ctx context.Context, userID uint, orgID uint, req *models.OrgUpdateRequest) (*models.OrgMembership, error) {
+	// 1. 验证角色
+	membership, err := s.authorizeOrg(ctx, userID, orgID, models.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 应用变更
+	org := &membership.Organization
+	if req.Name != nil {
+		org.Name = *req.Name
+	}
+	if req.MemberSendLimitWei != nil {
+		org.MemberSendLimitWei = normalizeLimit(*req.MemberSendLimitWei)
+	}
+
+	// 3. 保存
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// DeleteOrg 删除组织（仅owner，且组织下不能有钱包）
+func (s *OrganizationService) DeleteOrg(ctx context.Context, userID uint, orgID uint) error {
+	if _, err := s.authorizeOrg(ctx, userID, orgID, models.OrgRoleOwner); err != nil {
+		return err
+	}
+	return s.orgRepo.Delete(ctx, orgID)
+}
+
+// ListMembers 查询组织成员（任意成员可查看）
+func (s *OrganizationService) ListMembers(ctx context.Context, userID uint, orgID uint) ([]*models.OrgMembership, error) {
+	if _, err := s.authorizeOrg(ctx, userID, orgID, models.OrgRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// AddMember 添加组织成员（仅owner）
+func (s *OrganizationService) AddMember(ctx context.Context, userID uint, orgID uint, req *models.OrgMemberRequest) (*models.OrgMembership, error) {
+	if _, err := s.authorizeOrg(ctx, userID, orgID, models.OrgRoleOwner); err != nil {
+		return nil, err
+	}
+
+	membership := &models.OrgMembership{
+		OrgID:  orgID,
+		UserID: req.UserID,
+		Role:   req.Role,
+	}
+	if err := s.orgRepo.AddMember(ctx, membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// UpdateMember 修改成员角色（仅owner，不能降级最后一名owner）
+func (s *OrganizationService) UpdateMember(ctx context.Context, userID uint, orgID uint, memberID uint, req *models.OrgMemberUpdateRequest) (*models.OrgMembership, error) {
+	if _, err := s.authorizeOrg(ctx, userID, orgID, models.OrgRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.UpdateMemberRole(ctx, orgID, memberID, req.Role)
+}
+
+// RemoveMember 移除组织成员（owner可移除任意成员，其他成员只能退出组织；不能移除最后一名owner）
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID uint, orgID uint, memberID uint) error {
+	required := models.OrgRoleOwner
+	if memberID == userID {
+		required = models.OrgRoleViewer
+	}
+	if _, err := s.authorizeOrg(ctx, userID, orgID, required); err != nil {
+		return err
+	}
+	return s.orgRepo.RemoveMember(ctx, orgID, memberID)
+}
+
+// authorizeOrg 校验用户在组织中的角色不低于required（非成员视为组织不存在）
+func (s *OrganizationService) authorizeOrg(ctx context.Context, userID uint, orgID uint, required models.OrgRole) (*models.OrgMembership, error) {
+	membership, err := s.walletRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return nil, ErrOrgNotFound
+	}
+	if membership.Role.Rank() < required.Rank() {
+		return nil, ErrPermissionDenied
+	}
+	return membership, nil
+}
+
+// normalizeLimit 限额为0时视为不限
+func normalizeLimit(limit string) string {
+	if limit == "0" {
+		return ""
+	}
+	return limit
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+const (
+	// TransactionCreatedQueue 新交易监听队列（带重试与死信参数，不能沿用旧队列名重新声明）
+	TransactionCreatedQueue = "transaction.created.v2"
+	// LegacyTransactionCreatedQueue 升级前的新交易监听队列（无死信参数），其中的消息由Worker转发到TransactionCreatedQueue
+	LegacyTransactionCreatedQueue = "transaction.created"
+)
+
+// NewOutboxEvent 构建发件箱事件（payload为已编码的消息体，保存当前链路上下文，投递时延续同一条Trace）
+func NewOutboxEvent(ctx context.Context, queueName string, payload []byte) (*models.OutboxEvent, error) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	headers, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEvent{
+		Queue:   queueName,
+		Payload: string(payload),
+		Headers: string(headers),
+	}, nil
+}
+
+// OutboxPublisher 发件箱事件的发布目标（由*queue.RabbitMQ实现）
+type OutboxPublisher interface {
+	PublishRaw(ctx context.Context, queueName string, body []byte) error
+}
+
+// OutboxDispatcher 发件箱分发器（轮询未投递事件并发布到RabbitMQ，至少一次语义）
+type OutboxDispatcher struct {
+	outboxRepo *repository.OutboxRepository
+	queue      OutboxPublisher
+	interval   time.Duration
+	batchSize  int
+	retention  time.Duration
+}
+
+// NewOutboxDispatcher 创建发件箱分发器实例
+func NewOutboxDispatcher(
+	outboxRepo *repository.OutboxRepository,
+	queue OutboxPublisher,
+	interval time.Duration,
+	batchSize int,
+	retention time.Duration,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo: outboxRepo,
+		queue:      queue,
+		interval:   interval,
+		batchSize:  batchSize,
+		retention:  retention,
+	}
+}
+
+// Run 定期投递未发送事件并清理过期记录（阻塞直到ctx取消）
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		case <-cleanup.C:
+			deleted, err := d.outboxRepo.DeleteSentBefore(ctx, time.Now().Add(-d.retention))
+			if err != nil {
+				logger.Warn("failed to clean up outbox events", zap.Error(err))
+			} else if deleted > 0 {
+				logger.Info("cleaned up outbox events", zap.Int64("count", deleted))
+			}
+		}
+	}
+}
+
+// dispatch 投递一批事件，批次已满时继续处理下一批
+func (d *OutboxDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := d.outboxRepo.ProcessPending(ctx, d.batchSize, func(event *models.OutboxEvent) error {
+			return d.publish(ctx, event)
+		})
+		if err != nil {
+			logger.Error("failed to dispatch outbox events", zap.Error(err))
+			return
+		}
+		if processed < d.batchSize {
+			return
+		}
+	}
+}
+
+// publish 恢复链路上下文并发布事件
+func (d *OutboxDispatcher) publish(ctx context.Context, event *models.OutboxEvent) error {
+	carrier := propagation.MapCarrier{}
+	if event.Headers != "" {
+		if err := json.Unmarshal([]byte(event.Headers), &carrier); err != nil {
+			logger.Warn("invalid outbox event headers", zap.Uint("event_id", event.ID), zap.Error(err))
+		}
+	}
+	msgCtx := otel.GetTextMapPropagator().Extract(ctx, carrier)
+
+	if err := d.queue.PublishRaw(msgCtx, event.Queue, []byte(event.Payload)); err != nil {
+		logger.Warn("failed to publish outbox event",
+			zap.Uint("event_id", event.ID),
+			zap.String("queue", event.Queue),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
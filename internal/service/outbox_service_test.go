@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// countingPublisher 记录每条消息的发布次数
+type countingPublisher struct {
+	mu        sync.Mutex
+	published map[string]int
+}
+
+func (p *countingPublisher) PublishRaw(ctx context.Context, queueName string, body []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published[string(body)]++
+	return nil
+}
+
+func TestOutboxDispatchConcurrentWorkersPublishOnce(t *testing.T) {
+	env := newTestEnv(t)
+	const events = 50
+	for i := range events {
+		if err := env.db.Create(&models.OutboxEvent{Queue: TransactionCreatedQueue, Payload: fmt.Sprintf(`{"n":%d}`, i)}).Error; err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+	}
+
+	// 两个Worker同时分发，批次小于事件数以便交替认领
+	publisher := &countingPublisher{published: make(map[string]int)}
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			NewOutboxDispatcher(env.outboxRepo, publisher, 0, 5, 0).dispatch(context.Background())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if len(publisher.published) != events {
+		t.Errorf("published events = %d, want %d", len(publisher.published), events)
+	}
+	for payload, count := range publisher.published {
+		if count != 1 {
+			t.Errorf("event %s published %d times, want exactly once", payload, count)
+		}
+	}
+	assertOutbox(t, env, events, true)
+}
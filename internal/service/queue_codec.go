@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"crypto-wallet-api/internal/utils"
+)
+
+// queueEnvelopeVersion 加密信封格式版本
+const queueEnvelopeVersion = 1
+
+// ErrInvalidQueuePayload 队列消息无法解密或校验失败（被篡改或密钥不匹配）
+var ErrInvalidQueuePayload = errors.New("invalid queue payload")
+
+// queueEnvelope 加密的队列消息（AES-256-GCM同时提供机密性与完整性校验）
+type queueEnvelope struct {
+	Version int    `json:"v"`
+	Data    string `json:"data"` // 密文（Base64）
+}
+
+// QueueCodec 队列消息编解码（配置密钥时加密为信封，否则为明文JSON）
+type QueueCodec struct {
+	key []byte
+}
+
+// NewQueueCodec 创建队列消息编解码器（key为空时不加密）
+func NewQueueCodec(key []byte) *QueueCodec {
+	return &QueueCodec{key: key}
+}
+
+// Encrypted 是否加密消息
+func (c *QueueCodec) Encrypted() bool {
+	return len(c.key) > 0
+}
+
+// Seal 编码消息
+func (c *QueueCodec) Seal(message interface{}) ([]byte, error) {
+	plaintext, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Encrypted() {
+		return plaintext, nil
+	}
+
+	data, err := utils.EncryptAES(string(plaintext), c.key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&queueEnvelope{Version: queueEnvelopeVersion, Data: data})
+}
+
+// Open 解码消息（兼容未加密的旧格式；信封无法解密时返回ErrInvalidQueuePayload）
+func (c *QueueCodec) Open(body []byte, message interface{}) error {
+	// 1. 未加密的消息直接解析
+	var envelope queueEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Version == 0 || envelope.Data == "" {
+		return json.Unmarshal(body, message)
+	}
+
+	// 2. 校验版本与密钥
+	if envelope.Version != queueEnvelopeVersion {
+		return fmt.Errorf("%w: unsupported envelope version %d", ErrInvalidQueuePayload, envelope.Version)
+	}
+	if !c.Encrypted() {
+		return fmt.Errorf("%w: encrypted message but no queue key configured", ErrInvalidQueuePayload)
+	}
+
+	// 3. 解密（密文被篡改时GCM校验失败）
+	plaintext, err := utils.DecryptAES(envelope.Data, c.key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQueuePayload, err)
+	}
+	if err := json.Unmarshal([]byte(plaintext), message); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQueuePayload, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+)
+
+// ReceiptMonitor 基于新区块订阅的单条链交易回执监听（每个新区块批量检查一次该链的交易，订阅不可用时由轮询路径接管）
+type ReceiptMonitor struct {
+	txService  *TransactionService
+	wsURL      string
+	chainID    int
+	retryDelay time.Duration // 订阅断开后重新连接的间隔
+	subscribed atomic.Bool
+}
+
+// NewReceiptMonitor 创建回执监听器（wsURL为空时不启用订阅）
+func NewReceiptMonitor(txService *TransactionService, wsURL string, chainID int, retryDelay time.Duration) *ReceiptMonitor {
+	return &ReceiptMonitor{
+		txService:  txService,
+		wsURL:      wsURL,
+		chainID:    chainID,
+		retryDelay: retryDelay,
+	}
+}
+
+// Subscribed 订阅是否正常（为false时调用方应使用轮询路径）
+func (m *ReceiptMonitor) Subscribed() bool {
+	return m.subscribed.Load()
+}
+
+// Run 保持新区块订阅，断开后按retryDelay重连，直到ctx取消
+func (m *ReceiptMonitor) Run(ctx context.Context) {
+	if m.wsURL == "" {
+		logger.Info("websocket RPC URL not configured, receipt monitoring uses polling", zap.Int("chain_id", m.chainID))
+		return
+	}
+
+	for {
+		err := m.watch(ctx)
+		m.subscribed.Store(false)
+		metrics.ReceiptMonitorSubscribed.WithLabelValues(strconv.Itoa(m.chainID)).Set(0)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("new head subscription unavailable, falling back to polling", zap.Int("chain_id", m.chainID), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.retryDelay):
+		}
+	}
+}
+
+// watch 建立订阅并在每个新区块到达时检查待确认交易，订阅出错时返回
+func (m *ReceiptMonitor) watch(ctx context.Context) error {
+	client, err := blockchain.NewEthereumClient(m.wsURL, m.chainID)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	heads := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	m.subscribed.Store(true)
+	metrics.ReceiptMonitorSubscribed.WithLabelValues(strconv.Itoa(m.chainID)).Set(1)
+	logger.Info("subscribed to new heads, receipt monitoring is block driven", zap.Int("chain_id", m.chainID))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case head := <-heads:
+			m.txService.MonitorAtBlock(ctx, m.chainID, head.Number.Uint64())
+		}
+	}
+}
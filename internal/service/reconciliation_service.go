@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+const (
+	// reconciliationLockName 余额对账锁名称前缀（按链加锁，多副本部署时同一条链同一时刻只有一个副本执行）
+	reconciliationLockName = "balance-reconciliation"
+	// reconciliationLockTTL 余额对账锁有效期（执行期间自动续期）
+	reconciliationLockTTL = 30 * time.Second
+	// reconciliationRetryDelay 未获取到锁或对账失败后的重试间隔
+	reconciliationRetryDelay = 10 * time.Minute
+)
+
+// ReconciliationService 余额对账服务（批量查询链上余额，修正数据库中漂移的钱包余额并记录超过阈值的差异）
+//
+// 钱包余额通过缓存失效、后台刷新等尽力而为的异步路径更新，丢失的消息或写入失败会使数据库余额与链上长期不一致。
+// 期望的账本余额为链上余额加上尚未在链上结算的内部转账净额。
+type ReconciliationService struct {
+	reconRepo     *repository.ReconciliationRepository
+	walletRepo    *repository.WalletRepository
+	walletService *WalletService
+	chains        *blockchain.Clients
+	batchSize     int               // 每批钱包数（单次JSON-RPC批量请求）
+	threshold     *big.Int          // 差异超过该值时记录到对账报告
+	locker        *cache.RedisCache // 分布式锁（为nil时不加锁）
+}
+
+// NewReconciliationService 创建余额对账服务实例（threshold为十进制Wei，配置校验时已保证格式正确）
+func NewReconciliationService(
+	reconRepo *repository.ReconciliationRepository,
+	walletRepo *repository.WalletRepository,
+	walletService *WalletService,
+	chains *blockchain.Clients,
+	batchSize int,
+	threshold string,
+) *ReconciliationService {
+	return &ReconciliationService{
+		reconRepo:     reconRepo,
+		walletRepo:    walletRepo,
+		walletService: walletService,
+		chains:        chains,
+		batchSize:     batchSize,
+		threshold:     utils.DecimalToWei(threshold),
+	}
+}
+
+// SetLocker 设置分布式锁，多个worker副本同时运行时每轮只有持有锁的一方执行
+func (s *ReconciliationService) SetLocker(locker *cache.RedisCache) {
+	s.locker = locker
+}
+
+// Run 对每条连接了节点的链，距该链上一次对账满interval时执行，直到ctx取消（按数据库中的上一次开始时间计算，Worker重启后不会立即重复执行）
+func (s *ReconciliationService) Run(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for _, client := range s.chains.All() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runChain(ctx, client, interval)
+		}()
+	}
+	wg.Wait()
+}
+
+// runChain 按interval循环对账一条链
+func (s *ReconciliationService) runChain(ctx context.Context, client blockchain.BlockchainClient, interval time.Duration) {
+	chainID := client.GetChainID()
+	lockName := reconciliationLockName + ":" + strconv.Itoa(chainID)
+	for {
+		// 1. 等待到下一次对账时间
+		if wait := s.untilNext(ctx, chainID, interval); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		// 2. 持有锁时再次检查（其他副本可能刚完成对账）
+		tick := func(ctx context.Context) {
+			if s.untilNext(ctx, chainID, interval) > 0 {
+				return
+			}
+			if _, err := s.Reconcile(ctx, client); err != nil && ctx.Err() == nil {
+				logger.Error("balance reconciliation failed", zap.Int("chain_id", chainID), zap.Error(err))
+			}
+		}
+		if s.locker == nil {
+			tick(ctx)
+		} else if _, err := s.locker.WithLock(ctx, lockName, reconciliationLockTTL, tick); err != nil {
+			logger.Warn("failed to acquire balance reconciliation lock", zap.Int("chain_id", chainID), zap.Error(err))
+		}
+
+		// 3. 对账完成后由步骤1等待到下一次；未获取到锁或失败时稍后重试
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(reconciliationRetryDelay, interval)):
+		}
+	}
+}
+
+// untilNext 距该链下一次对账的时间（查询失败时按重试间隔等待）
+func (s *ReconciliationService) untilNext(ctx context.Context, chainID int, interval time.Duration) time.Duration {
+	last, err := s.reconRepo.LatestRun(ctx, chainID)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("failed to load last reconciliation run", zap.Int("chain_id", chainID), zap.Error(err))
+		}
+		return reconciliationRetryDelay
+	}
+	if last == nil {
+		return 0
+	}
+	return time.Until(last.StartedAt.Add(interval))
+}
+
+// Reconcile 对client所在链执行一次对账：分批查询链上余额，修正数据库余额（含低于阈值的差异），超过阈值的差异写入对账报告
+func (s *ReconciliationService) Reconcile(ctx context.Context, client blockchain.BlockchainClient) (*models.ReconciliationRun, error) {
+	chainID := client.GetChainID()
+
+	// 1. 创建对账记录
+	run := &models.ReconciliationRun{
+		ChainID:      chainID,
+		ThresholdWei: s.threshold.String(),
+		StartedAt:    time.Now(),
+	}
+	if err := s.reconRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	// 2. 分批比较（单批链上查询失败时计入失败数并继续下一批）
+	err := s.walletRepo.FindForReconciliation(ctx, chainID, s.batchSize, func(wallets []*models.Wallet) error {
+		reports := s.reconcileBatch(ctx, client, run, wallets)
+		return s.reconRepo.CreateReports(ctx, reports)
+	})
+	if err != nil {
+		return run, err
+	}
+
+	// 3. 保存结果
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if err := s.reconRepo.FinishRun(ctx, run); err != nil {
+		return run, err
+	}
+
+	// 4. 更新指标并记录汇总
+	label := strconv.Itoa(chainID)
+	metrics.ReconciliationDiscrepancies.WithLabelValues(label).Set(float64(run.Discrepancies))
+	metrics.ReconciliationCorrected.WithLabelValues(label).Set(float64(run.Corrected))
+	metrics.ReconciliationLastRun.WithLabelValues(label).Set(float64(finishedAt.Unix()))
+
+	fields := []zap.Field{
+		zap.Uint("run_id", run.ID),
+		zap.Int("chain_id", chainID),
+		zap.Int("wallets_checked", run.WalletsChecked),
+		zap.Int("corrected", run.Corrected),
+		zap.Int("discrepancies", run.Discrepancies),
+		zap.Int("failed", run.Failed),
+		zap.Duration("duration", finishedAt.Sub(run.StartedAt)),
+	}
+	if run.Discrepancies > 0 || run.Failed > 0 {
+		logger.Warn("balance reconciliation found discrepancies", fields...)
+	} else {
+		logger.Info("balance reconciliation completed", fields...)
+	}
+	return run, nil
+}
+
+// reconcileBatch 比较一批钱包，修正余额并返回超过阈值的差异
+func (s *ReconciliationService) reconcileBatch(ctx context.Context, client blockchain.BlockchainClient, run *models.ReconciliationRun, wallets []*models.Wallet) []*models.ReconciliationReport {
+	// 1. 批量查询链上余额
+	addresses := make([]string, len(wallets))
+	for i, wallet := range wallets {
+		addresses[i] = wallet.Address
+	}
+	balances, err := client.BatchGetBalances(ctx, addresses)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to fetch balances for reconciliation",
+			zap.Int("batch_size", len(addresses)),
+			zap.Error(err),
+		)
+		run.Failed += len(wallets)
+		return nil
+	}
+
+	// 2. 期望的账本余额 = 链上余额 + 内部转账净额
+	var reports []*models.ReconciliationReport
+	for i, wallet := range wallets {
+		run.WalletsChecked++
+		stored := utils.DecimalToWei(wallet.Balance)
+		net := internalNet(wallet)
+		delta := new(big.Int).Add(balances[i], net)
+		delta.Sub(delta, stored)
+		if delta.Sign() == 0 {
+			continue
+		}
+
+		// 3. 修正数据库余额与缓存
+		s.walletService.applyBalance(ctx, wallet.ChainID, wallet.Address, balances[i])
+		run.Corrected++
+
+		// 4. 记录超过阈值的差异
+		if new(big.Int).Abs(delta).Cmp(s.threshold) <= 0 {
+			continue
+		}
+		run.Discrepancies++
+		reports = append(reports, &models.ReconciliationReport{
+			RunID:          run.ID,
+			WalletID:       wallet.ID,
+			Address:        wallet.Address,
+			StoredBalance:  stored.String(),
+			OnChainBalance: balances[i].String(),
+			InternalNetWei: net.String(),
+			DeltaWei:       delta.String(),
+		})
+	}
+	return reports
+}
+
+// ListRuns 查询最近的对账记录及其差异
+func (s *ReconciliationService) ListRuns(ctx context.Context, limit int) ([]*models.ReconciliationRun, error) {
+	return s.reconRepo.ListRuns(ctx, limit)
+}
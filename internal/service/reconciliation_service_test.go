@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/blockchain/mock"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// newReconciliationService 创建余额对账服务（每批2个钱包，差异阈值10 Wei）
+func (e *testEnv) newReconciliationService() *ReconciliationService {
+	return NewReconciliationService(repository.NewReconciliationRepository(e.db), e.walletRepo, e.wallets, blockchain.NewClients(e.chain), 2, "10")
+}
+
+func TestReconcileCorrectsDrift(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	synced := env.createWallet(t, user.ID, ether(0))
+	small := env.createWallet(t, user.ID, ether(0))
+	large := env.createWallet(t, user.ID, ether(0))
+	internal := env.createWallet(t, user.ID, ether(0))
+	archived := env.createWallet(t, user.ID, ether(0))
+	for _, wallet := range []*models.Wallet{synced, small, large, internal, archived} {
+		env.setLedger(t, wallet, 1000, 1000)
+	}
+
+	// 链上余额变化未同步到数据库；账本余额包含未结算的内部转入；已归档的钱包不参与对账
+	env.chain.SetBalance(small.Address, big.NewInt(1005))
+	env.chain.SetBalance(large.Address, big.NewInt(5000))
+	env.chain.SetBalance(archived.Address, big.NewInt(5000))
+	if err := env.db.Model(&models.Wallet{}).Where("id = ?", internal.ID).
+		Updates(map[string]interface{}{"balance": "1200", "internal_net_wei": "200"}).Error; err != nil {
+		t.Fatalf("record internal transfer: %v", err)
+	}
+	if err := env.db.Model(&models.Wallet{}).Where("id = ?", archived.ID).Update("archived", true).Error; err != nil {
+		t.Fatalf("archive wallet: %v", err)
+	}
+
+	recon := env.newReconciliationService()
+	run, err := recon.Reconcile(ctx, env.chain)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if run.WalletsChecked != 4 || run.Corrected != 2 || run.Discrepancies != 1 || run.Failed != 0 || run.FinishedAt == nil {
+		t.Errorf("run = checked %d corrected %d discrepancies %d failed %d finished %v; want 4/2/1/0 finished",
+			run.WalletsChecked, run.Corrected, run.Discrepancies, run.Failed, run.FinishedAt)
+	}
+
+	// 低于阈值的差异只修正，超过阈值的差异写入报告
+	env.assertLedger(t, small, 1005, 0)
+	env.assertLedger(t, large, 5000, 0)
+	env.assertLedger(t, internal, 1200, 200)
+	env.assertLedger(t, archived, 1000, 0)
+
+	runs, err := recon.ListRuns(ctx, 10)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 1 || len(runs[0].Reports) != 1 {
+		t.Fatalf("runs = %+v, want one run with one report", runs)
+	}
+	if r := runs[0].Reports[0]; r.WalletID != large.ID || r.StoredBalance != "1000" || r.OnChainBalance != "5000" || r.DeltaWei != "4000" {
+		t.Errorf("report = %+v, want %s 1000 -> 5000 (delta 4000)", r, large.Address)
+	}
+
+	// 修正后再次对账没有差异
+	run, err = recon.Reconcile(ctx, env.chain)
+	if err != nil {
+		t.Fatalf("second reconcile: %v", err)
+	}
+	if run.WalletsChecked != 4 || run.Corrected != 0 || run.Discrepancies != 0 {
+		t.Errorf("second run = checked %d corrected %d discrepancies %d; want 4/0/0", run.WalletsChecked, run.Corrected, run.Discrepancies)
+	}
+}
+
+func TestReconcileCountsFailedBatches(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallets := make([]*models.Wallet, 3)
+	for i := range wallets {
+		wallets[i] = env.createWallet(t, user.ID, ether(0))
+		env.setLedger(t, wallets[i], 1000, 1000)
+		env.chain.SetBalance(wallets[i].Address, big.NewInt(5000))
+	}
+
+	// 链上查询失败的钱包计入失败数，数据库余额保持不变
+	env.chain.FailOn(mock.MethodBatchGetBalances, errors.New("rpc unavailable"))
+	run, err := env.newReconciliationService().Reconcile(ctx, env.chain)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if run.Failed != 3 || run.WalletsChecked != 0 || run.Corrected != 0 {
+		t.Errorf("run = failed %d checked %d corrected %d; want 3/0/0", run.Failed, run.WalletsChecked, run.Corrected)
+	}
+	for _, wallet := range wallets {
+		env.assertLedger(t, wallet, 1000, 0)
+	}
+}
+
+func TestReconciliationScheduledFromLastRun(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	recon := env.newReconciliationService()
+
+	// 尚未对账时立即执行，之后按上一次开始时间等待
+	if wait := recon.untilNext(ctx, testutil.ChainID, time.Hour); wait > 0 {
+		t.Errorf("wait before first run = %s, want 0", wait)
+	}
+	if _, err := recon.Reconcile(ctx, env.chain); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if wait := recon.untilNext(ctx, testutil.ChainID, time.Hour); wait <= 59*time.Minute || wait > time.Hour {
+		t.Errorf("wait after run = %s, want about 1h", wait)
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// minRecurringInterval 自定义执行间隔的下限
+const minRecurringInterval = time.Hour
+
+// RecurringPaymentService 定期转账服务
+type RecurringPaymentService struct {
+	paymentRepo  *repository.RecurringPaymentRepository
+	walletRepo   *repository.WalletRepository
+	txService    *TransactionService
+	eventService *EventService
+	maxFailures  int // 连续失败达到该次数后暂停计划
+}
+
+// NewRecurringPaymentService 创建定期转账服务实例
+func NewRecurringPaymentService(
+	paymentRepo *repository.RecurringPaymentRepository,
+	walletRepo *repository.WalletRepository,
+	txService *TransactionService,
+	eventService *EventService,
+	maxFailures int,
+) *RecurringPaymentService {
+	return &RecurringPaymentService{
+		paymentRepo:  paymentRepo,
+		walletRepo:   walletRepo,
+		txService:    txService,
+		eventService: eventService,
+		maxFailures:  maxFailures,
+	}
+}
+
+// CreatePayment 创建定期转账计划
+func (s *RecurringPaymentService) CreatePayment(ctx context.Context, userID uint, req *models.RecurringPaymentCreateRequest) (*models.RecurringPayment, error) {
+	// 1. 验证付款钱包转账权限
+	wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.FromAddress, PermSend)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.ChainID != req.ChainID {
+		return nil, ErrChainIDMismatch
+	}
+	if wallet.PassphraseProtected() {
+		return nil, apperr.Invalid("error.recurring_passphrase_wallet", "recurring payments are not available for passphrase-protected wallets")
+	}
+	if err := validateRecipient(wallet.Address, req.ToAddress); err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount(req.Amount, req.AmountUnit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验执行周期与结束条件
+	if err := validateSchedule(req.Schedule); err != nil {
+		return nil, err
+	}
+	startAt := time.Now()
+	if req.StartAt != nil && req.StartAt.After(startAt) {
+		startAt = *req.StartAt
+	}
+	if req.EndAt != nil && !req.EndAt.After(startAt) {
+		return nil, apperr.Invalid("error.invalid_end_at", "end_at must be after start time")
+	}
+
+	// 3. 保存计划
+	payment := &models.RecurringPayment{
+		UserID:      userID,
+		WalletID:    wallet.ID,
+		FromAddress: wallet.Address,
+		ToAddress:   common.HexToAddress(req.ToAddress).Hex(),
+		Amount:      amount.String(),
+		ChainID:     req.ChainID,
+		Schedule:    strings.ToLower(req.Schedule),
+		NextRunAt:   startAt,
+		EndAt:       req.EndAt,
+		MaxRuns:     req.MaxRuns,
+		Status:      models.RecurringStatusActive,
+	}
+	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// GetPayment 获取定期转账计划详情
+func (s *RecurringPaymentService) GetPayment(ctx context.Context, userID uint, id uint) (*models.RecurringPayment, error) {
+	return s.paymentRepo.GetByID(ctx, userID, id)
+}
+
+// ListPayments 查询用户的定期转账计划
+func (s *RecurringPaymentService) ListPayments(ctx context.Context, userID uint) ([]*models.RecurringPayment, error) {
+	return s.paymentRepo.GetByUserID(ctx, userID)
+}
+
+// UpdatePayment 更新定期转账计划（金额、周期、结束条件、暂停/恢复）
+func (s *RecurringPaymentService) UpdatePayment(ctx context.Context, userID uint, id uint, req *models.RecurringPaymentUpdateRequest) (*models.RecurringPayment, error) {
+	// 1. 查询计划（已完成或已取消的计划不可修改）
+	payment, err := s.paymentRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != models.RecurringStatusActive && payment.Status != models.RecurringStatusPaused {
+		return nil, apperr.Invalidf("recurring payment is %s", payment.Status)
+	}
+
+	// 2. 应用变更
+	if req.Amount != "" {
+		amount, err := parseAmount(req.Amount, req.AmountUnit, false)
+		if err != nil {
+			return nil, err
+		}
+		payment.Amount = amount.String()
+	}
+	if req.Schedule != "" {
+		if err := validateSchedule(req.Schedule); err != nil {
+			return nil, err
+		}
+		payment.Schedule = strings.ToLower(req.Schedule)
+	}
+	if req.EndAt != nil {
+		payment.EndAt = req.EndAt
+	}
+	if req.MaxRuns != nil {
+		payment.MaxRuns = *req.MaxRuns
+	}
+	if req.Status != "" {
+		// 恢复执行时清零连续失败次数
+		if req.Status == models.RecurringStatusActive && payment.Status == models.RecurringStatusPaused {
+			payment.ConsecutiveFailures = 0
+		}
+		payment.Status = req.Status
+	}
+
+	// 3. 保存
+	if err := s.paymentRepo.UpdateSettings(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// CancelPayment 取消定期转账计划（保留记录，已执行的交易仍关联该计划）
+func (s *RecurringPaymentService) CancelPayment(ctx context.Context, userID uint, id uint) error {
+	payment, err := s.paymentRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	payment.Status = models.RecurringStatusCancelled
+	return s.paymentRepo.UpdateSettings(ctx, payment)
+}
+
+// RunDue 执行已到期的定期转账（由Worker定时调用）
+func (s *RecurringPaymentService) RunDue(ctx context.Context, batchSize int) {
+	// 暂停发送期间不认领计划，恢复后错过的周期补执行一次，不计为失败
+	if err := s.txService.checkSendingEnabled(ctx); err != nil {
+		logger.WithCtx(ctx).Info("recurring payments skipped", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	payments, err := s.paymentRepo.GetDue(ctx, now, batchSize)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to load due recurring payments", zap.Error(err))
+		return
+	}
+
+	for _, payment := range payments {
+		s.runOnce(ctx, payment, now)
+	}
+}
+
+// runOnce 认领并执行一次定期转账
+func (s *RecurringPaymentService) runOnce(ctx context.Context, payment *models.RecurringPayment, now time.Time) {
+	log := logger.WithCtx(ctx).With(zap.Uint("recurring_payment_id", payment.ID))
+
+	// 1. 计算下次执行时间（Worker停机期间错过的周期只补执行一次）
+	next := nextOccurrence(payment.Schedule, payment.NextRunAt)
+	for !next.After(now) {
+		next = nextOccurrence(payment.Schedule, next)
+	}
+
+	// 2. 判断本次执行后是否达到结束条件
+	status := models.RecurringStatusActive
+	if payment.MaxRuns > 0 && payment.RunCount+1 >= payment.MaxRuns {
+		status = models.RecurringStatusCompleted
+	}
+	if payment.EndAt != nil && !next.Before(*payment.EndAt) {
+		status = models.RecurringStatusCompleted
+	}
+
+	// 3. 原子推进next_run_at，防止多个Worker重复执行
+	claimed, err := s.paymentRepo.Claim(ctx, payment, next, status)
+	if err != nil {
+		log.Error("failed to claim recurring payment", zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// 4. 执行转账
+	tx, err := s.txService.SendRecurringPayment(ctx, payment)
+	if err == nil {
+		log.Info("recurring payment executed", zap.String("tx_hash", tx.TxHash))
+		if err := s.paymentRepo.RecordSuccess(ctx, payment.ID); err != nil {
+			log.Error("failed to record recurring payment success", zap.Error(err))
+		}
+		return
+	}
+
+	// 5. 记录失败，连续失败达到上限时暂停并通知
+	log.Warn("recurring payment failed", zap.Error(err))
+	paused, recordErr := s.paymentRepo.RecordFailure(ctx, payment.ID, err.Error(), s.maxFailures)
+	if recordErr != nil {
+		log.Error("failed to record recurring payment failure", zap.Error(recordErr))
+		return
+	}
+	if paused {
+		log.Warn("recurring payment paused after consecutive failures", zap.Int("failures", s.maxFailures))
+		if pubErr := s.eventService.Publish(ctx, &models.WalletEvent{
+			Type:               models.EventRecurringPaused,
+			Address:            payment.FromAddress,
+			RecurringPaymentID: payment.ID,
+			Message:            fmt.Sprintf("paused after %d consecutive failures: %s", s.maxFailures, err.Error()),
+		}); pubErr != nil {
+			log.Error("failed to publish recurring payment event", zap.Error(pubErr))
+		}
+	}
+}
+
+// validateSchedule 校验执行周期：daily、weekly、monthly或不少于1小时的时间间隔（如36h）
+func validateSchedule(schedule string) error {
+	switch strings.ToLower(schedule) {
+	case "daily", "weekly", "monthly":
+		return nil
+	}
+
+	interval, err := time.ParseDuration(schedule)
+	if err != nil {
+		return apperr.Invalid("error.invalid_schedule", "schedule must be daily, weekly, monthly or an interval such as 36h")
+	}
+	if interval < minRecurringInterval {
+		return apperr.Invalidf("schedule interval must be at least %s", minRecurringInterval)
+	}
+	return nil
+}
+
+// nextOccurrence 计算下一次执行时间（monthly按自然月推进）
+func nextOccurrence(schedule string, from time.Time) time.Time {
+	switch schedule {
+	case "daily":
+		return from.AddDate(0, 0, 1)
+	case "weekly":
+		return from.AddDate(0, 0, 7)
+	case "monthly":
+		return from.AddDate(0, 1, 0)
+	}
+
+	interval, err := time.ParseDuration(schedule)
+	if err != nil || interval < minRecurringInterval {
+		interval = minRecurringInterval
+	}
+	return from.Add(interval)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/testutil"
+)
+
+func TestRunDueConcurrentWorkersExecuteOnce(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(10))
+
+	paymentRepo := repository.NewRecurringPaymentRepository(env.db)
+	payment := &models.RecurringPayment{
+		UserID:      user.ID,
+		WalletID:    wallet.ID,
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      "1000",
+		ChainID:     testutil.ChainID,
+		Schedule:    "daily",
+		NextRunAt:   time.Now().Add(-time.Minute).Truncate(time.Second),
+		Status:      models.RecurringStatusActive,
+	}
+	if err := paymentRepo.Create(ctx, payment); err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+
+	// 两个Worker同时处理同一批到期计划
+	service := NewRecurringPaymentService(paymentRepo, env.walletRepo, env.txs, env.events, 3)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			service.RunDue(ctx, 10)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if sent := env.chain.SentTransactions(); len(sent) != 1 {
+		t.Errorf("broadcast transactions = %d, want exactly 1", len(sent))
+	}
+	saved, err := paymentRepo.GetByID(ctx, user.ID, payment.ID)
+	if err != nil {
+		t.Fatalf("load payment: %v", err)
+	}
+	if saved.RunCount != 1 || !saved.NextRunAt.After(time.Now()) {
+		t.Errorf("run_count = %d next_run_at = %v, want 1 run and a future next run", saved.RunCount, saved.NextRunAt)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// defaultStatsCacheTTL 统计结果默认缓存时间
+const defaultStatsCacheTTL = 5 * time.Minute
+
+// StatsService 统计服务
+type StatsService struct {
+	txRepo     *repository.TransactionRepository
+	walletRepo *repository.WalletRepository
+	cache      cache.Cache
+	cacheTTL   atomic.Int64 // 统计结果缓存时间
+}
+
+// NewStatsService 创建统计服务实例
+func NewStatsService(
+	txRepo *repository.TransactionRepository,
+	walletRepo *repository.WalletRepository,
+	cache cache.Cache,
+) *StatsService {
+	s := &StatsService{
+		txRepo:     txRepo,
+		walletRepo: walletRepo,
+		cache:      cache,
+	}
+	s.SetCacheTTL(defaultStatsCacheTTL)
+	return s
+}
+
+// SetCacheTTL 调整统计结果缓存时间（支持运行时调整）
+func (s *StatsService) SetCacheTTL(ttl time.Duration) {
+	s.cacheTTL.Store(int64(ttl))
+}
+
+// GetTransactionStats 获取用户的交易统计（按天时间序列与合计）
+func (s *StatsService) GetTransactionStats(ctx context.Context, userID uint, req *models.TransactionStatsRequest) (*models.TransactionStatsResponse, error) {
+	// 1. 默认时间范围：最近30天
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = truncateDay(to)
+	from := req.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -29)
+	}
+	from = truncateDay(from)
+	if from.After(to) {
+		return nil, apperr.Invalid("error.from_after_to", "from must not be after to")
+	}
+	if err := checkTransactionFilter(&req.TransactionFilter); err != nil {
+		return nil, err
+	}
+
+	filter := &models.TransactionStatsFilter{
+		UserID:            userID,
+		ChainID:           req.ChainID,
+		From:              from,
+		To:                to.AddDate(0, 0, 1), // 结束日期当天包含在内
+		TransactionFilter: req.TransactionFilter,
+	}
+
+	// 2. 验证钱包查看权限
+	if req.WalletAddress != "" {
+		wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.WalletAddress, PermView)
+		if err != nil {
+			return nil, err
+		}
+		filter.WalletID = wallet.ID
+	}
+
+	// 3. 查询缓存
+	cacheKey := fmt.Sprintf("stats:tx:%d:%s:%s:%d:%s:%s:%s:%s:%s",
+		userID, from.Format("2006-01-02"), to.Format("2006-01-02"), req.ChainID, strings.ToLower(req.WalletAddress),
+		req.Direction, req.MinAmount, req.MaxAmount, strings.ToLower(req.Counterparty))
+	if cached, ok := readCache(ctx, s.cache, cacheKey); ok {
+		var resp models.TransactionStatsResponse
+		if json.Unmarshal([]byte(cached), &resp) == nil {
+			return &resp, nil
+		}
+	}
+
+	// 4. 数据库聚合
+	totals, err := s.txRepo.AggregateByStatus(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.txRepo.AggregateByDay(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	received, err := s.txRepo.SumReceived(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 汇总
+	resp := &models.TransactionStatsResponse{
+		From:          from,
+		To:            to,
+		TotalSent:     "0",
+		TotalReceived: received,
+		CountByStatus: make(map[models.TransactionStatus]int64),
+		Daily:         daily,
+	}
+	gasSpent := new(big.Int)
+	for _, stat := range totals {
+		resp.CountByStatus[stat.Status] = stat.Count
+		gasSpent.Add(gasSpent, utils.DecimalToWei(stat.TotalGasFee))
+		if stat.Status == models.TxStatusSuccess {
+			resp.TotalSent = stat.TotalAmount
+		}
+	}
+	resp.TotalGasSpent = gasSpent.String()
+
+	// 6. 写入缓存
+	if body, err := json.Marshal(resp); err == nil {
+		s.cache.Set(ctx, cacheKey, string(body), time.Duration(s.cacheTTL.Load()))
+	}
+
+	return resp, nil
+}
+
+// truncateDay 截断到UTC零点
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+const (
+	// tokenTransferCursorTopic ERC-20 Transfer扫描进度的主题名
+	tokenTransferCursorTopic = "erc20_transfer"
+	// tokenDepositAddressChunk 单次eth_getLogs查询的接收地址数量上限（topic过滤条件不宜过长）
+	tokenDepositAddressChunk = 500
+	// tokenDepositConfirmBatch 每轮确认的代币入账数量上限
+	tokenDepositConfirmBatch = 100
+	// tokenDepositLockName 扫描锁名称前缀（按链加锁，多副本部署时同一条链同一时刻只有一个副本扫描）
+	tokenDepositLockName = "token-deposit-scan"
+	// tokenDepositLockTTL 扫描锁有效期（扫描期间自动续期）
+	tokenDepositLockTTL = 30 * time.Second
+)
+
+// TokenDepositScanner 单条链的代币入账扫描器（按区块范围拉取转入用户钱包的Transfer事件，达到确认深度后标记为已确认）
+type TokenDepositScanner struct {
+	txRepo           *repository.TransactionRepository
+	walletRepo       *repository.WalletRepository
+	cursorRepo       *repository.ChainCursorRepository
+	tokenService     *TokenService
+	blockchainClient blockchain.BlockchainClient
+	eventService     *EventService
+	interval         time.Duration     // 扫描间隔
+	blockRange       uint64            // 单次查询的区块数
+	confirmations    uint64            // 最终确认所需的区块数（含事件所在区块）
+	locker           *cache.RedisCache // 分布式锁（为nil时不加锁）
+}
+
+// NewTokenDepositScanner 创建代币入账扫描器
+func NewTokenDepositScanner(
+	txRepo *repository.TransactionRepository,
+	walletRepo *repository.WalletRepository,
+	cursorRepo *repository.ChainCursorRepository,
+	tokenService *TokenService,
+	blockchainClient blockchain.BlockchainClient,
+	eventService *EventService,
+	interval time.Duration,
+	blockRange uint64,
+	confirmations uint64,
+) *TokenDepositScanner {
+	return &TokenDepositScanner{
+		txRepo:           txRepo,
+		walletRepo:       walletRepo,
+		cursorRepo:       cursorRepo,
+		tokenService:     tokenService,
+		blockchainClient: blockchainClient,
+		eventService:     eventService,
+		interval:         interval,
+		blockRange:       blockRange,
+		confirmations:    confirmations,
+	}
+}
+
+// SetLocker 设置分布式锁，多个worker副本同时运行时每轮只有持有锁的一方扫描
+func (s *TokenDepositScanner) SetLocker(locker *cache.RedisCache) {
+	s.locker = locker
+}
+
+// Run 循环扫描，直到ctx取消
+func (s *TokenDepositScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.locker == nil {
+				s.tick(ctx)
+				continue
+			}
+			lockName := fmt.Sprintf("%s:%d", tokenDepositLockName, s.blockchainClient.GetChainID())
+			if _, err := s.locker.WithLock(ctx, lockName, tokenDepositLockTTL, s.tick); err != nil {
+				logger.Warn("failed to acquire token deposit scan lock", zap.Int("chain_id", s.blockchainClient.GetChainID()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// tick 执行一轮扫描与确认
+func (s *TokenDepositScanner) tick(ctx context.Context) {
+	latest, err := s.blockchainClient.GetBlockNumber(ctx)
+	if err != nil {
+		logger.Warn("failed to get latest block number", zap.Int("chain_id", s.blockchainClient.GetChainID()), zap.Error(err))
+		return
+	}
+	if err := s.scan(ctx, latest); err != nil {
+		logger.Error("failed to scan token transfers", zap.Int("chain_id", s.blockchainClient.GetChainID()), zap.Error(err))
+	}
+	s.confirm(ctx, latest)
+}
+
+// scan 从上次进度开始扫描到最新区块，每处理完一个区块范围保存一次进度
+func (s *TokenDepositScanner) scan(ctx context.Context, latest uint64) error {
+	chainID := s.blockchainClient.GetChainID()
+
+	// 1. 读取扫描进度（首次运行从当前区块开始，不回溯历史）
+	last, found, err := s.cursorRepo.Get(ctx, chainID, tokenTransferCursorTopic)
+	if err != nil {
+		return err
+	}
+	if !found {
+		logger.Info("token transfer cursor initialized", zap.Int("chain_id", chainID), zap.Uint64("block", latest))
+		return s.cursorRepo.Save(ctx, chainID, tokenTransferCursorTopic, latest)
+	}
+	if last >= latest {
+		return nil
+	}
+
+	// 2. 加载本链所有用户钱包
+	wallets, err := s.walletRepo.GetByChainID(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	walletByAddress := make(map[common.Address]*models.Wallet, len(wallets))
+	for _, wallet := range wallets {
+		walletByAddress[common.HexToAddress(wallet.Address)] = wallet
+	}
+
+	// 3. 按区块范围拉取事件
+	for from := last + 1; from <= latest && ctx.Err() == nil; from += s.blockRange {
+		to := from + s.blockRange - 1
+		if to > latest {
+			to = latest
+		}
+
+		logs, err := s.fetchLogs(ctx, from, to, walletByAddress)
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs in blocks %d-%d: %w", from, to, err)
+		}
+		for i := range logs {
+			if err := s.handleLog(ctx, chainID, &logs[i], walletByAddress); err != nil {
+				return err
+			}
+		}
+
+		if err := s.cursorRepo.Save(ctx, chainID, tokenTransferCursorTopic, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchLogs 查询区块范围内转入用户钱包的Transfer事件（接收地址分批作为topic过滤条件）
+func (s *TokenDepositScanner) fetchLogs(ctx context.Context, from, to uint64, wallets map[common.Address]*models.Wallet) ([]types.Log, error) {
+	recipients := make([]common.Hash, 0, len(wallets))
+	for address := range wallets {
+		recipients = append(recipients, common.BytesToHash(address.Bytes()))
+	}
+
+	var logs []types.Log
+	for start := 0; start < len(recipients); start += tokenDepositAddressChunk {
+		end := start + tokenDepositAddressChunk
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+		chunk, err := s.blockchainClient.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Topics:    [][]common.Hash{{blockchain.ERC20TransferTopic}, nil, recipients[start:end]},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, chunk...)
+	}
+	return logs, nil
+}
+
+// handleLog 解码Transfer事件并写入待确认的代币入账记录
+func (s *TokenDepositScanner) handleLog(ctx context.Context, chainID int, log *types.Log, wallets map[common.Address]*models.Wallet) error {
+	if log.Removed {
+		return nil
+	}
+
+	// 1. 解码事件（忽略ERC-721等同签名的非标准事件）
+	transfer, err := blockchain.DecodeTransferLog(log)
+	if err != nil {
+		return nil
+	}
+	wallet, ok := wallets[common.HexToAddress(transfer.To)]
+	if !ok || transfer.Value.Sign() == 0 {
+		return nil
+	}
+
+	// 2. 获取代币元数据（无法读取元数据的合约跳过）
+	token, err := s.tokenService.ResolveToken(ctx, chainID, transfer.Contract)
+	if err != nil {
+		logger.Warn("skipping transfer of unresolvable token",
+			zap.String("token", transfer.Contract),
+			zap.String("tx_hash", log.TxHash.Hex()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	// 3. 写入代币入账记录（重复扫描同一区块时忽略）
+	logIndex := log.Index
+	tx := &models.Transaction{
+		WalletID:     wallet.ID,
+		TxHash:       log.TxHash.Hex(),
+		FromAddress:  transfer.From,
+		ToAddress:    wallet.Address,
+		Amount:       utils.FormatUnits(transfer.Value, token.Decimals),
+		Status:       models.TxStatusPending,
+		BlockNumber:  int64(log.BlockNumber),
+		ChainID:      chainID,
+		TokenAddress: token.ContractAddress,
+		TokenSymbol:  token.Symbol,
+		LogIndex:     &logIndex,
+	}
+	created, err := s.txRepo.CreateTokenDeposit(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to save token deposit: %w", err)
+	}
+	if !created {
+		return nil
+	}
+
+	logger.Info("token deposit detected",
+		zap.String("address", wallet.Address),
+		zap.String("token", token.Symbol),
+		zap.String("amount", tx.Amount),
+		zap.String("tx_hash", tx.TxHash),
+	)
+	if err := s.eventService.Publish(ctx, &models.WalletEvent{
+		Type:         models.EventDepositDetected,
+		Address:      wallet.Address,
+		TxHash:       tx.TxHash,
+		ExplorerURL:  models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		Status:       models.TxStatusPending,
+		BlockNumber:  tx.BlockNumber,
+		Amount:       transfer.Value.String(),
+		TokenAddress: token.ContractAddress,
+	}); err != nil {
+		logger.Warn("failed to publish deposit event", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+	}
+	return nil
+}
+
+// confirm 确认达到确认深度的代币入账（重新核对回执，被链重组移除的记录标记为失败）
+func (s *TokenDepositScanner) confirm(ctx context.Context, latest uint64) {
+	if latest+1 < s.confirmations {
+		return
+	}
+
+	deposits, err := s.txRepo.GetPendingTokenDeposits(ctx, s.blockchainClient.GetChainID(), latest+1-s.confirmations, tokenDepositConfirmBatch)
+	if err != nil {
+		logger.Error("failed to get pending token deposits", zap.Error(err))
+		return
+	}
+
+	for _, deposit := range deposits {
+		// 1. 重新获取回执
+		receipt, err := s.blockchainClient.GetTransactionReceipt(ctx, deposit.TxHash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			logger.Warn("failed to get token deposit receipt", zap.String("tx_hash", deposit.TxHash), zap.Error(err))
+			continue
+		}
+
+		// 2. 交易或事件已不在链上：被链重组移除
+		if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful || !receiptHasLog(receipt, deposit) {
+			if ok, err := s.txRepo.ConfirmTokenDeposit(ctx, deposit.ID, models.TxStatusFailed, "removed by chain reorganization"); err != nil {
+				logger.Error("failed to update token deposit", zap.Uint("id", deposit.ID), zap.Error(err))
+			} else if ok {
+				logger.Warn("token deposit removed by reorg", zap.String("tx_hash", deposit.TxHash))
+				s.publishConfirmed(ctx, deposit, models.TxStatusFailed)
+			}
+			continue
+		}
+
+		// 3. 交易被重新打包到其他区块：更新区块号，待新区块达到确认深度后再确认
+		if receipt.BlockNumber.Int64() != deposit.BlockNumber {
+			if err := s.txRepo.UpdateBlockNumber(ctx, deposit.ID, receipt.BlockNumber.Int64()); err != nil {
+				logger.Error("failed to update token deposit block", zap.Uint("id", deposit.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		// 4. 标记为已确认
+		if ok, err := s.txRepo.ConfirmTokenDeposit(ctx, deposit.ID, models.TxStatusSuccess, ""); err != nil {
+			logger.Error("failed to confirm token deposit", zap.Uint("id", deposit.ID), zap.Error(err))
+		} else if ok {
+			s.publishConfirmed(ctx, deposit, models.TxStatusSuccess)
+		}
+	}
+}
+
+// publishConfirmed 推送代币入账确认事件
+func (s *TokenDepositScanner) publishConfirmed(ctx context.Context, deposit *models.Transaction, status models.TransactionStatus) {
+	if err := s.eventService.Publish(ctx, &models.WalletEvent{
+		Type:         models.EventTransactionConfirmed,
+		Address:      deposit.ToAddress,
+		TxHash:       deposit.TxHash,
+		ExplorerURL:  models.ExplorerTxURL(deposit.ChainID, deposit.TxHash),
+		Status:       status,
+		BlockNumber:  deposit.BlockNumber,
+		TokenAddress: deposit.TokenAddress,
+	}); err != nil {
+		logger.Warn("failed to publish confirmation event", zap.String("tx_hash", deposit.TxHash), zap.Error(err))
+	}
+}
+
+// receiptHasLog 检查回执中是否仍包含该代币入账对应的Transfer事件
+func receiptHasLog(receipt *types.Receipt, deposit *models.Transaction) bool {
+	for _, log := range receipt.Logs {
+		if deposit.LogIndex != nil && log.Index == *deposit.LogIndex &&
+			strings.EqualFold(log.Address.Hex(), deposit.TokenAddress) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+	"crypto-wallet-api/internal/utils"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// ErrUnsupportedChain 当前节点不支持该链
+var ErrUnsupportedChain = apperr.New("error.unsupported_chain", "chain is not supported")
+
+// ErrTokenMetadata 无法从合约读取代币元数据（地址不是ERC-20合约或节点调用失败）
+var ErrTokenMetadata = apperr.New("error.token_metadata", "failed to read token metadata from the contract")
+
+// TokenService 代币元数据与关注列表服务
+type TokenService struct {
+	tokenRepo          *repository.TokenRepository
+	walletRepo         *repository.WalletRepository
+	chains             *blockchain.Clients
+	cache              cache.Cache
+	metadataTTL        time.Duration // 代币元数据缓存时间
+	balanceConcurrency int           // 并发查询代币余额的最大数量
+}
+
+// NewTokenService 创建代币服务实例
+func NewTokenService(
+	tokenRepo *repository.TokenRepository,
+	walletRepo *repository.WalletRepository,
+	chains *blockchain.Clients,
+	cache cache.Cache,
+	metadataTTL time.Duration,
+	balanceConcurrency int,
+) *TokenService {
+	return &TokenService{
+		tokenRepo:          tokenRepo,
+		walletRepo:         walletRepo,
+		chains:             chains,
+		cache:              cache,
+		metadataTTL:        metadataTTL,
+		balanceConcurrency: balanceConcurrency,
+	}
+}
+
+// ResolveToken 获取代币元数据（Redis缓存 -> 数据库 -> 链上读取并保存）
+func (s *TokenService) ResolveToken(ctx context.Context, chainID int, contractAddress string) (*models.Token, error) {
+	address := common.HexToAddress(contractAddress).Hex()
+
+	// 1. 查询缓存
+	cacheKey := fmt.Sprintf("token:%d:%s", chainID, strings.ToLower(address))
+	if cached, ok := readCache(ctx, s.cache, cacheKey); ok {
+		var token models.Token
+		if json.Unmarshal([]byte(cached), &token) == nil {
+			return &token, nil
+		}
+	}
+
+	// 2. 查询数据库
+	token, err := s.tokenRepo.GetByAddress(ctx, chainID, address)
+	if err != nil {
+		if !errors.Is(err, apperr.ErrNotFound) {
+			return nil, err
+		}
+
+		// 3. 首次出现的代币从合约读取元数据
+		client, err := chainClient(s.chains, chainID)
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := blockchain.ReadTokenMetadata(ctx, client, address)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTokenMetadata, err)
+		}
+		token, err = s.tokenRepo.CreateIfNotExists(ctx, &models.Token{
+			ChainID:         chainID,
+			ContractAddress: address,
+			Symbol:          truncate(metadata.Symbol, 32),
+			Name:            truncate(metadata.Name, 100),
+			Decimals:        int(metadata.Decimals),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. 写入缓存
+	if body, err := json.Marshal(token); err == nil {
+		s.cache.Set(ctx, cacheKey, string(body), s.metadataTTL)
+	}
+
+	return token, nil
+}
+
+// WatchToken 关注代币
+func (s *TokenService) WatchToken(ctx context.Context, userID uint, req *models.TokenWatchRequest) (*models.Token, error) {
+	token, err := s.ResolveToken(ctx, req.ChainID, req.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.AddWatch(ctx, userID, token.ID); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// UnwatchToken 取消关注代币
+func (s *TokenService) UnwatchToken(ctx context.Context, userID uint, req *models.TokenWatchRequest) error {
+	token, err := s.tokenRepo.GetByAddress(ctx, req.ChainID, common.HexToAddress(req.ContractAddress).Hex())
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return errors.New("token not watched")
+		}
+		return err
+	}
+	return s.tokenRepo.RemoveWatch(ctx, userID, token.ID)
+}
+
+// ListWatchlist 查询用户关注的代币
+func (s *TokenService) ListWatchlist(ctx context.Context, userID uint) ([]*models.Token, error) {
+	return s.tokenRepo.ListWatched(ctx, userID, 0)
+}
+
+// GetWalletTokens 查询钱包在其所在链上所有关注代币的余额
+func (s *TokenService) GetWalletTokens(ctx context.Context, userID uint, address string) (*models.WalletTokensResponse, error) {
+	// 1. 验证钱包查看权限
+	wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, address, PermView)
+	if err != nil {
+		return nil, err
+	}
+	client, err := chainClient(s.chains, wallet.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询该链上关注的代币
+	tokens, err := s.tokenRepo.ListWatched(ctx, userID, wallet.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 有限并发查询余额（单个代币失败不影响其他代币）
+	balances := make([]*models.TokenBalance, len(tokens))
+	sem := make(chan struct{}, s.balanceConcurrency)
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, token *models.Token) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item := &models.TokenBalance{Token: token.ToResponse(), Balance: "0", BalanceFormatted: "0"}
+			balance, err := blockchain.TokenBalanceOf(ctx, client, token.ContractAddress, wallet.Address)
+			if err != nil {
+				logger.WithCtx(ctx).Warn("failed to get token balance",
+					zap.String("token", token.ContractAddress),
+					zap.String("address", wallet.Address),
+					zap.Error(err),
+				)
+				item.Error = "failed to get balance"
+			} else {
+				item.Balance = balance.String()
+				item.BalanceFormatted = utils.FormatUnits(balance, token.Decimals)
+			}
+			balances[i] = item
+		}(i, token)
+	}
+	wg.Wait()
+
+	return &models.WalletTokensResponse{
+		Address: wallet.Address,
+		ChainID: wallet.ChainID,
+		Tokens:  balances,
+	}, nil
+}
+
+// truncate 截断超出列长度的字符串
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
+)
+
+const (
+	// defaultApprovalTTL 待审批交易的默认有效期
+	defaultApprovalTTL = 72 * time.Hour
+	// approvalExpireBatch 每轮过期处理的交易数量上限
+	approvalExpireBatch = 100
+)
+
+var (
+	// ErrInvalidApprovalPolicy 审批策略无效
+	ErrInvalidApprovalPolicy = apperr.New("error.invalid_approval_policy", "invalid approval policy")
+	// ErrApprovalRequired 金额超过审批阈值，该发送方式不支持审批流程
+	ErrApprovalRequired = apperr.New("error.approval_required", "amount exceeds the wallet approval threshold")
+	// ErrNotAwaitingApproval 交易不处于等待审批状态
+	ErrNotAwaitingApproval = apperr.New("error.not_awaiting_approval", "transaction is not awaiting approval")
+	// ErrApprovalExpired 审批已过期
+	ErrApprovalExpired = apperr.New("error.approval_expired", "transaction approval has expired")
+	// ErrSelfApproval 审批人不能批准自己发起的交易
+	ErrSelfApproval = apperr.Forbidden("error.self_approval", "you cannot approve a transaction you proposed")
+)
+
+// SetApprovalTTL 设置待审批交易的有效期
+func (s *TransactionService) SetApprovalTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.approvalTTL = ttl
+	}
+}
+
+// proposeTransaction 创建等待审批的交易并通知审批人（不签名、不占用nonce，批准后再广播）
+func (s *TransactionService) proposeTransaction(ctx context.Context, userID uint, wallet *models.Wallet, out *outgoingTx) (*models.Transaction, error) {
+	if wallet.Archived {
+		return nil, ErrWalletArchived
+	}
+	if err := checkChainEnabled(wallet.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 1. 查询审批人（发起人不能批准自己发起的交易，不计入可用审批人）
+	approverIDs, err := s.walletRepo.GetApproverIDs(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	approverIDs = slices.DeleteFunc(approverIDs, func(id uint) bool { return id == userID })
+	if len(approverIDs) < wallet.RequiredApprovals {
+		return nil, fmt.Errorf("%w: not enough approvers other than the proposer", ErrInvalidApprovalPolicy)
+	}
+
+	// 2. 保存待审批交易（金额按18位小数精确保存，执行时换算回Wei）
+	expiresAt := time.Now().Add(s.approvalTTL)
+	transaction := &models.Transaction{
+		WalletID:          wallet.ID,
+		FromAddress:       wallet.Address,
+		ToAddress:         out.To,
+		ToENSName:         out.ToENSName,
+		Amount:            utils.FormatUnits(out.Value, 18),
+		GasLimit:          out.GasLimit,
+		Status:            models.TxStatusAwaitingApproval,
+		ChainID:           wallet.ChainID,
+		Note:              out.Note,
+		RequiredApprovals: wallet.RequiredApprovals,
+		ProposedBy:        &userID,
+		ApprovalExpiresAt: &expiresAt,
+	}
+	for _, tag := range out.Tags {
+		transaction.Tags = append(transaction.Tags, models.TransactionTag{Tag: tag})
+	}
+	if err := s.txRepo.Create(ctx, transaction); err != nil {
+		return nil, err
+	}
+
+	// 3. 通知审批人
+	for _, approverID := range approverIDs {
+		s.publishApprovalEvent(ctx, models.EventApprovalRequested, transaction, approverID)
+	}
+	return transaction, nil
+}
+
+// ListPendingApprovals 查询当前用户作为审批人的待审批交易
+func (s *TransactionService) ListPendingApprovals(ctx context.Context, userID uint) ([]*models.TransactionResponse, error) {
+	transactions, err := s.txRepo.ListAwaitingApproval(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = transactionResponse(tx)
+	}
+	return responses, nil
+}
+
+// ApproveTransaction 审批人批准交易，达到所需人数后签名并广播（nonce、Gas价格与余额均在此时获取与校验）
+func (s *TransactionService) ApproveTransaction(ctx context.Context, userID uint, id uint) (*models.Transaction, error) {
+	// 1. 校验审批人与交易状态
+	tx, wallet, err := s.loadApprovalTarget(ctx, userID, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if tx.ApprovalExpiresAt != nil && time.Now().After(*tx.ApprovalExpiresAt) {
+		s.resolveApproval(ctx, tx, models.TxStatusExpired, "approval window elapsed")
+		return nil, ErrApprovalExpired
+	}
+	if tx.ProposedBy != nil && *tx.ProposedBy == userID {
+		return nil, ErrSelfApproval
+	}
+
+	// 2. 记录批准（重复批准不重复计数，但会在人数已满足时重试广播）
+	if err := s.txRepo.AddApproval(ctx, &models.TransactionApproval{
+		TransactionID: tx.ID,
+		UserID:        userID,
+		Decision:      models.ApprovalApproved,
+	}); err != nil {
+		return nil, err
+	}
+	if tx, err = s.txRepo.GetForApproval(ctx, id); err != nil {
+		return nil, err
+	}
+	if len(tx.ApprovedBy()) < tx.RequiredApprovals {
+		return tx, nil
+	}
+
+	// 3. 重新校验收款地址白名单
+	if err := s.whitelistService.CheckRecipient(ctx, wallet, tx.ToAddress); err != nil {
+		return nil, err
+	}
+
+	// 4. 签名并广播
+	value, ok := utils.ParseUnits(tx.Amount, 18)
+	if !ok {
+		return nil, fmt.Errorf("invalid stored amount %q", tx.Amount)
+	}
+	executed, err := s.broadcast(ctx, wallet.UserID, wallet, &outgoingTx{
+		To:        tx.ToAddress,
+		ToENSName: tx.ToENSName,
+		Value:     value,
+		GasLimit:  tx.GasLimit,
+		Proposal:  tx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithCtx(ctx).Info("approved transaction broadcast",
+		zap.Uint("id", tx.ID),
+		zap.String("tx_hash", executed.TxHash),
+	)
+	tx, err = s.txRepo.GetForApproval(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.publishApprovalEvent(ctx, models.EventApprovalResolved, tx, 0)
+	return tx, nil
+}
+
+// RejectTransaction 审批人拒绝交易（任一审批人拒绝即终止）
+func (s *TransactionService) RejectTransaction(ctx context.Context, userID uint, id uint) (*models.Transaction, error) {
+	tx, _, err := s.loadApprovalTarget(ctx, userID, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.txRepo.AddApproval(ctx, &models.TransactionApproval{
+		TransactionID: tx.ID,
+		UserID:        userID,
+		Decision:      models.ApprovalRejected,
+	}); err != nil {
+		return nil, err
+	}
+	if !s.resolveApproval(ctx, tx, models.TxStatusRejected, "rejected by approver") {
+		return nil, ErrNotAwaitingApproval
+	}
+	return s.txRepo.GetForApproval(ctx, id)
+}
+
+// ExpireTransaction 有钱包转账权限的用户或审批人提前结束审批（交易不会被广播）
+func (s *TransactionService) ExpireTransaction(ctx context.Context, userID uint, id uint) (*models.Transaction, error) {
+	tx, _, err := s.loadApprovalTarget(ctx, userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.resolveApproval(ctx, tx, models.TxStatusExpired, "expired by user") {
+		return nil, ErrNotAwaitingApproval
+	}
+	return s.txRepo.GetForApproval(ctx, id)
+}
+
+// ExpireStaleApprovals 将超过有效期仍未完成审批的交易标记为过期（后台任务调用）
+func (s *TransactionService) ExpireStaleApprovals(ctx context.Context) {
+	transactions, err := s.txRepo.GetExpiredApprovals(ctx, time.Now(), approvalExpireBatch)
+	if err != nil {
+		logger.Error("failed to get expired approvals", zap.Error(err))
+		return
+	}
+	for _, tx := range transactions {
+		s.resolveApproval(ctx, tx, models.TxStatusExpired, "approval window elapsed")
+	}
+}
+
+// loadApprovalTarget 查询等待审批的交易并校验当前用户为审批人（allowOwner时有钱包转账权限的用户也可操作）
+func (s *TransactionService) loadApprovalTarget(ctx context.Context, userID uint, id uint, allowOwner bool) (*models.Transaction, *models.Wallet, error) {
+	tx, err := s.txRepo.GetForApproval(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	wallet, err := s.walletRepo.GetByID(ctx, tx.WalletID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allowed := false
+	if allowOwner {
+		if _, err := authorizeWallet(ctx, s.walletRepo, userID, wallet, PermSend); err == nil {
+			allowed = true
+		}
+	}
+	if !allowed {
+		if allowed, err = s.walletRepo.IsApprover(ctx, wallet.ID, userID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !allowed {
+		return nil, nil, apperr.NotFound("transaction")
+	}
+	if tx.Status != models.TxStatusAwaitingApproval {
+		return nil, nil, ErrNotAwaitingApproval
+	}
+	return tx, wallet, nil
+}
+
+// resolveApproval 结束审批并通知钱包所有者，返回是否由本次调用完成更新
+func (s *TransactionService) resolveApproval(ctx context.Context, tx *models.Transaction, status models.TransactionStatus, reason string) bool {
+	ok, err := s.txRepo.ResolveApproval(ctx, tx.ID, status, reason)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to resolve approval", zap.Uint("id", tx.ID), zap.Error(err))
+		return false
+	}
+	if ok {
+		tx.Status = status
+		tx.ErrorMsg = reason
+		s.publishApprovalEvent(ctx, models.EventApprovalResolved, tx, 0)
+	}
+	return ok
+}
+
+// publishApprovalEvent 推送审批事件（userID为0时推送给钱包地址的订阅者）
+func (s *TransactionService) publishApprovalEvent(ctx context.Context, eventType models.WalletEventType, tx *models.Transaction, userID uint) {
+	amount, _ := utils.ParseUnits(tx.Amount, 18)
+	event := &models.WalletEvent{
+		Type:          eventType,
+		Address:       tx.FromAddress,
+		UserID:        userID,
+		TransactionID: tx.ID,
+		TxHash:        tx.TxHash,
+		ExplorerURL:   models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		Status:        tx.Status,
+		Message:       tx.ErrorMsg,
+	}
+	if amount != nil {
+		event.Amount = amount.String()
+	}
+	if err := s.eventService.Publish(ctx, event); err != nil {
+		logger.WithCtx(ctx).Warn("failed to publish approval event", zap.Uint("id", tx.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+func TestApproveTransactionRejectsProposer(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	owner := env.createUser(t)
+	approver := env.createUser(t)
+	wallet := env.createWallet(t, owner.ID, ether(10))
+
+	// 发起人同时是审批人（如组织钱包中有转账权限的审批人）
+	if _, err := env.wallets.UpdateApprovalPolicy(ctx, owner.ID, wallet.Address, &models.ApprovalPolicyRequest{
+		ThresholdWei:      "1",
+		RequiredApprovals: 1,
+		ApproverIDs:       []uint{owner.ID, approver.ID},
+	}); err != nil {
+		t.Fatalf("update approval policy: %v", err)
+	}
+
+	proposal, err := env.txs.SendTransaction(ctx, owner.ID, &models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      "1",
+		AmountUnit:  models.AmountUnitEth,
+		ChainID:     testutil.ChainID,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if proposal.Status != models.TxStatusAwaitingApproval || proposal.ProposedBy == nil || *proposal.ProposedBy != owner.ID {
+		t.Fatalf("proposal = status %s proposed_by %v, want awaiting_approval by %d", proposal.Status, proposal.ProposedBy, owner.ID)
+	}
+
+	// 发起人批准被拒绝，且不计入批准人数
+	if _, err := env.txs.ApproveTransaction(ctx, owner.ID, proposal.ID); !errors.Is(err, ErrSelfApproval) || !errors.Is(err, apperr.ErrForbidden) {
+		t.Fatalf("self approval err = %v, want ErrSelfApproval (forbidden)", err)
+	}
+	if sent := env.chain.SentTransactions(); len(sent) != 0 {
+		t.Fatalf("broadcast transactions = %d after self approval, want 0", len(sent))
+	}
+
+	// 其他审批人批准后广播
+	approved, err := env.txs.ApproveTransaction(ctx, approver.ID, proposal.ID)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if approved.Status != models.TxStatusPending || len(approved.ApprovedBy()) != 1 {
+		t.Errorf("approved = status %s approvals %v, want pending with 1 approval", approved.Status, approved.ApprovedBy())
+	}
+}
+
+func TestProposeTransactionRequiresApproverOtherThanProposer(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	owner := env.createUser(t)
+	wallet := env.createWallet(t, owner.ID, ether(10))
+
+	// 唯一的审批人是发起人自己时无法完成审批，拒绝创建待审批交易
+	if _, err := env.wallets.UpdateApprovalPolicy(ctx, owner.ID, wallet.Address, &models.ApprovalPolicyRequest{
+		ThresholdWei:      "1",
+		RequiredApprovals: 1,
+		ApproverIDs:       []uint{owner.ID},
+	}); err != nil {
+		t.Fatalf("update approval policy: %v", err)
+	}
+
+	_, err := env.txs.SendTransaction(ctx, owner.ID, &models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      "1",
+		AmountUnit:  models.AmountUnitEth,
+		ChainID:     testutil.ChainID,
+	})
+	if !errors.Is(err, ErrInvalidApprovalPolicy) {
+		t.Fatalf("err = %v, want ErrInvalidApprovalPolicy", err)
+	}
+	assertTransactionCount(t, env, 0)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// 运维工具（cmd/cli）使用的交易操作：不校验用户权限，operator记录在审计日志中
+
+// ErrTransactionNotPending 交易已最终确认（不是pending或confirming），不能重新入队或手动设置状态
+var ErrTransactionNotPending = apperr.Conflict("error.transaction_not_pending", "transaction is not pending or confirming")
+
+// OverrideStatuses 运维可手动设置的最终状态
+var OverrideStatuses = []models.TransactionStatus{models.TxStatusSuccess, models.TxStatusFailed, models.TxStatusCancelled}
+
+// LookupTransaction 按哈希查询交易（不校验用户权限，仅供运维工具使用）
+func (s *TransactionService) LookupTransaction(ctx context.Context, txHash string) (*models.Transaction, error) {
+	return s.txRepo.GetByTxHash(ctx, txHash)
+}
+
+// RequeueTransaction 为等待确认的交易重新写入监听队列消息（卡住的交易由Worker重新检查回执；dryRun时只校验不写入）
+func (s *TransactionService) RequeueTransaction(ctx context.Context, txHash, operator string, dryRun bool) (*models.Transaction, error) {
+	// 1. 查询交易并校验状态
+	tx, err := s.pendingTransaction(ctx, txHash)
+	if err != nil || dryRun {
+		return tx, err
+	}
+
+	// 2. 写入发件箱事件（写入前再次确认交易仍在等待确认）
+	event, err := s.createdEvent(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	requeued, err := s.txRepo.RequeueIfPending(ctx, txHash, event)
+	if err != nil {
+		return nil, err
+	}
+	if !requeued {
+		return nil, ErrTransactionNotPending
+	}
+
+	// 3. 审计日志
+	logger.WithCtx(ctx).Info("transaction requeued",
+		zap.String("tx_hash", txHash),
+		zap.String("status", string(tx.Status)),
+		zap.String("operator", operator),
+	)
+	return tx, nil
+}
+
+// SetTransactionStatus 将卡住的交易手动设置为最终状态（运维工具使用；dryRun时只校验不修改）
+//
+// 与回执确认相同：在同一事务中更新状态并标记双方余额待刷新，提交后推送确认事件并刷新余额；原因记录在error_msg中。
+func (s *TransactionService) SetTransactionStatus(ctx context.Context, txHash string, status models.TransactionStatus, reason, operator string, dryRun bool) (*models.Transaction, error) {
+	// 1. 校验目标状态与交易状态
+	if !slices.Contains(OverrideStatuses, status) {
+		return nil, apperr.Invalidf("status must be one of %v", OverrideStatuses)
+	}
+	tx, err := s.pendingTransaction(ctx, txHash)
+	if err != nil || dryRun {
+		return tx, err
+	}
+
+	// 2. 在同一事务中更新状态并标记余额待刷新
+	message := fmt.Sprintf("status set to %s by %s", status, operator)
+	if reason != "" {
+		message += ": " + reason
+	}
+	updated := false
+	err = s.txRepo.WithTx(ctx, func(txRepo *repository.TransactionRepository, walletRepo *repository.WalletRepository) error {
+		var err error
+		updated, err = txRepo.OverrideStatus(ctx, txHash, status, message)
+		if err != nil || !updated {
+			return err
+		}
+		return markConfirmedBalancesStale(ctx, walletRepo, tx, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrTransactionNotPending
+	}
+	previous := tx.Status
+	tx.Status = status
+	tx.ErrorMsg = message
+
+	// 3. 推送最终确认事件并刷新余额
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:        models.EventTransactionConfirmed,
+		Address:     tx.FromAddress,
+		TxHash:      txHash,
+		ExplorerURL: models.ExplorerTxURL(tx.ChainID, txHash),
+		Status:      status,
+		BlockNumber: tx.BlockNumber,
+		Message:     message,
+	})
+	s.refreshConfirmedBalances(ctx, tx, status)
+
+	// 4. 审计日志
+	logger.WithCtx(ctx).Info("transaction status overridden",
+		zap.String("tx_hash", txHash),
+		zap.String("previous", string(previous)),
+		zap.String("status", string(status)),
+		zap.String("reason", reason),
+		zap.String("operator", operator),
+	)
+	return tx, nil
+}
+
+// pendingTransaction 查询仍在等待确认（pending或confirming）的交易
+func (s *TransactionService) pendingTransaction(ctx context.Context, txHash string) (*models.Transaction, error) {
+	tx, err := s.txRepo.GetByTxHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.LogIndex != nil || (tx.Status != models.TxStatusPending && tx.Status != models.TxStatusConfirming) {
+		return nil, ErrTransactionNotPending
+	}
+	return tx, nil
+}
@@ -2,20 +2,27 @@ package service
 
 import (
 	"context"
-
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum"
 	"go.uber.org/zap"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/blockchain"
 	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/repository"
 	"crypto-wallet-api/internal/utils"
-	"crypto-wallet-api/pkg/queue"
+	"crypto-wallet-api/pkg/cache"
+
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // TransactionService 交易服务
@@ -23,246 +30,1247 @@ type TransactionService struct {
 	txRepo           *repository.TransactionRepository
 	walletRepo       *repository.WalletRepository
 	walletService    *WalletService
-	blockchainClient blockchain.BlockchainClient
-	queue            *queue.RabbitMQ
+	chains           *blockchain.Clients // 各链的客户端（按钱包与交易所在链路由）
+	eventService     *EventService
+	contactService   *ContactService
+	whitelistService *WhitelistService
+	limitService     *LimitService
+	ensService       *ENSService                    // ENS解析（为nil时不接受ENS名称）
+	featureFlags     *FeatureFlagService            // 功能开关（为nil时不检查）
+	gasOracle        *GasOracle                     // Gas价格档位（为nil时忽略speed，使用节点建议价格）
+	chainHealth      *ChainHealthMonitor            // 链头监控（节点不健康时拒绝发送，为nil时不检查）
+	chainParams      map[int]blockchain.ChainParams // 各链的交易构建参数（未设置的链使用默认值）
+	approvalTTL      time.Duration                  // 待审批交易的有效期
+	locker           *cache.RedisCache              // 分布式锁（多副本部署时按交易分片，为nil时不加锁）
+	queueCodec       *QueueCodec                    // 队列消息编解码（默认不加密）
+	labelService     *LabelService                  // 对手方地址标签（为nil时不标注）
+	schedule         MonitorSchedule                // 待确认交易的分级检查策略
 }
 
+// txLockTTL 单笔交易回执检查的锁有效期
+const txLockTTL = 30 * time.Second
+
+// revertReasonUnavailable 无法获取回滚原因时记录的失败原因（如节点已裁剪所在区块的状态）
+const revertReasonUnavailable = "reason unavailable"
+
+var (
+	// ErrAwaitingConfirmations 交易已打包但尚未达到确认深度
+	ErrAwaitingConfirmations = apperr.New("error.awaiting_confirmations", "transaction is awaiting confirmations")
+	// ErrEmptyMetaUpdate 更新交易备注与标签时未提供任何字段
+	ErrEmptyMetaUpdate = apperr.New("error.empty_meta_update", "note or tags is required")
+	// ErrChainIDMismatch 请求的链ID与发送钱包所在链不一致
+	ErrChainIDMismatch = apperr.Invalid("error.chain_id_mismatch", "chain_id mismatch")
+	// ErrInsufficientBalance 发送钱包的链上余额不足以支付金额与最大Gas费用
+	ErrInsufficientBalance = apperr.Invalid("error.insufficient_balance", "insufficient balance")
+	// ErrContactRecipientConflict 同时指定了收款地址与地址簿联系人
+	ErrContactRecipientConflict = apperr.Invalid("error.contact_recipient_conflict", "to_address and contact_id are mutually exclusive")
+	// ErrContactChainMismatch 地址簿联系人所在链与请求的链ID不一致
+	ErrContactChainMismatch = apperr.Invalid("error.contact_chain_mismatch", "contact chain_id mismatch")
+)
+
 // NewTransactionService 创建交易服务实例
 func NewTransactionService(
 	txRepo *repository.TransactionRepository,
 	walletRepo *repository.WalletRepository,
 	walletService *WalletService,
-	blockchainClient blockchain.BlockchainClient,
-	queue *queue.RabbitMQ,
+	chains *blockchain.Clients,
+	eventService *EventService,
+	contactService *ContactService,
+	whitelistService *WhitelistService,
+	limitService *LimitService,
 ) *TransactionService {
 	return &TransactionService{
 		txRepo:           txRepo,
 		walletRepo:       walletRepo,
 		walletService:    walletService,
-		blockchainClient: blockchainClient,
-		queue:            queue,
+		chains:           chains,
+		eventService:     eventService,
+		contactService:   contactService,
+		whitelistService: whitelistService,
+		limitService:     limitService,
+		approvalTTL:      defaultApprovalTTL,
+		queueCodec:       NewQueueCodec(nil),
+		schedule:         DefaultMonitorSchedule(),
+	}
+}
+
+// requiredConfirmations 钱包发送的交易最终确认所需的区块数（钱包未设置时使用钱包所在链的配置）
+func (s *TransactionService) requiredConfirmations(wallet *models.Wallet) uint64 {
+	if wallet.ConfirmationsRequired > 0 {
+		return wallet.ConfirmationsRequired
 	}
+	return s.paramsFor(wallet.ChainID).Confirmations
+}
+
+// confirmationsFor 交易最终确认所需的区块数（广播时记录，旧交易未记录时使用交易所在链的配置）
+func (s *TransactionService) confirmationsFor(tx *models.Transaction) uint64 {
+	if tx.ConfirmationsRequired > 0 {
+		return tx.ConfirmationsRequired
+	}
+	return s.paramsFor(tx.ChainID).Confirmations
+}
+
+// SetLocker 设置分布式锁，多个worker副本同时检查同一笔交易时只有持有锁的一方处理
+func (s *TransactionService) SetLocker(locker *cache.RedisCache) {
+	s.locker = locker
+}
+
+// SetChainHealth 设置链头监控，链节点长时间未同步到新区块时拒绝发送交易（余额与nonce可能已过期）
+func (s *TransactionService) SetChainHealth(monitor *ChainHealthMonitor) {
+	s.chainHealth = monitor
+}
+
+// SetQueueCodec 设置队列消息编解码器（配置队列密钥时加密交易创建消息）
+func (s *TransactionService) SetQueueCodec(codec *QueueCodec) {
+	s.queueCodec = codec
+}
+
+// SetENSService 设置ENS解析服务，启用后收款地址可填写ENS名称，并按配置反向解析转入交易的发送方
+func (s *TransactionService) SetENSService(ensService *ENSService) {
+	s.ensService = ensService
+}
+
+// SetLabelService 设置地址标签服务，交易响应按对手方地址标注交易所、DeFi合约与代币合约
+func (s *TransactionService) SetLabelService(labelService *LabelService) {
+	s.labelService = labelService
+}
+
+// SetFeatureFlags 设置功能开关，关闭发送或处于只读维护模式时拒绝广播交易（包括Worker执行的审批交易与定期转账）
+func (s *TransactionService) SetFeatureFlags(featureFlags *FeatureFlagService) {
+	s.featureFlags = featureFlags
+}
+
+// SetChainParams 设置各链的交易构建参数（默认gas用量、gas价格下限）
+func (s *TransactionService) SetChainParams(params ...blockchain.ChainParams) {
+	s.chainParams = make(map[int]blockchain.ChainParams, len(params))
+	for _, p := range params {
+		s.chainParams[p.ChainID] = p
+	}
+}
+
+// client 获取链的区块链客户端（没有连接该链的节点时返回ErrUnsupportedChain）
+func (s *TransactionService) client(chainID int) (blockchain.BlockchainClient, error) {
+	return chainClient(s.chains, chainID)
+}
+
+// paramsFor 获取链的交易构建参数
+func (s *TransactionService) paramsFor(chainID int) blockchain.ChainParams {
+	if params, ok := s.chainParams[chainID]; ok {
+		return params
+	}
+	return blockchain.DefaultChainParams(chainID)
+}
+
+// SetGasOracle 设置Gas价格预言机，交易可通过speed选择价格档位
+func (s *TransactionService) SetGasOracle(gasOracle *GasOracle) {
+	s.gasOracle = gasOracle
+}
+
+// checkSendingEnabled 关闭发送或处于只读维护模式时返回FeatureDisabledError
+func (s *TransactionService) checkSendingEnabled(ctx context.Context) error {
+	if s.featureFlags == nil {
+		return nil
+	}
+	if err := s.featureFlags.CheckWritable(ctx); err != nil {
+		return err
+	}
+	return s.featureFlags.Check(ctx, models.FlagTransactionsSend)
 }
 
 // SendTransaction 发起转账交易
 func (s *TransactionService) SendTransaction(ctx context.Context, userID uint, req *models.TransactionCreateRequest) (*models.Transaction, error) {
-	// 1. 验证发送方钱包所有权
-	wallet, err := s.walletRepo.GetByAddress(ctx, req.FromAddress)
+	// 未指定发送钱包时使用用户在该链上的默认钱包（交易记录与响应中的from_address即为实际使用的钱包）
+	if req.FromAddress == "" {
+		wallet, err := s.walletService.DefaultWallet(ctx, userID, req.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		req.FromAddress = wallet.Address
+	}
+
+	// 1. 验证发送方钱包转账权限
+	wallet, membership, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.FromAddress, PermSend)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证链ID匹配
+	if wallet.ChainID != req.ChainID {
+		return nil, ErrChainIDMismatch
+	}
+
+	// 通过地址簿联系人指定收款地址
+	if req.ContactID != 0 {
+		if req.ToAddress != "" {
+			return nil, ErrContactRecipientConflict
+		}
+		contact, err := s.contactService.GetContact(ctx, userID, req.ContactID)
+		if err != nil {
+			return nil, err
+		}
+		if contact.ChainID != req.ChainID {
+			return nil, ErrContactChainMismatch
+		}
+		req.ToAddress = contact.Address
+	}
+
+	// 收款地址为ENS名称时解析为地址（保留名称用于展示）
+	toAddress, ensName, err := resolveRecipient(ctx, s.ensService, req.ChainID, req.ToAddress)
 	if err != nil {
 		return nil, err
 	}
-	if wallet.UserID != userID {
-		return nil, errors.New("wallet not found")
+	req.ToAddress = toAddress
+
+	// 3. 校验收款地址与金额（拒绝零地址、向自身转账与无法精确解析的金额）
+	if err := validateRecipient(wallet.Address, req.ToAddress); err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount(req.Amount, req.AmountUnit, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMemberSendLimit(membership, amount); err != nil {
+		return nil, err
+	}
+
+	// 白名单校验（钱包启用白名单时仅允许向已生效的地址转账）
+	if err := s.whitelistService.CheckRecipient(ctx, wallet, req.ToAddress); err != nil {
+		return nil, err
+	}
+
+	out := &outgoingTx{
+		To:         req.ToAddress,
+		ToENSName:  ensName,
+		Value:      amount,
+		GasLimit:   req.GasLimit,
+		Speed:      req.Speed,
+		Passphrase: req.Passphrase,
+		Note:       req.Note,
+		Tags:       normalizeTags(req.Tags),
+	}
+
+	// 内部转账只移动双方账本余额，不上链
+	if req.Internal {
+		return s.sendInternal(ctx, userID, wallet, out)
+	}
+
+	// 4. 超过审批阈值时创建待审批交易，由审批人批准后再签名广播
+	if wallet.RequiresApproval(amount) {
+		return s.proposeTransaction(ctx, userID, wallet, out)
+	}
+
+	// 5. 广播交易
+	return s.broadcast(ctx, userID, wallet, out)
+}
+
+// SendContractTransaction 通过托管钱包调用合约写方法（如approve、stake）
+func (s *TransactionService) SendContractTransaction(ctx context.Context, userID uint, req *models.ContractTransactionRequest) (*models.Transaction, error) {
+	// 1. 验证发送方钱包转账权限
+	wallet, membership, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.FromAddress, PermSend)
+	if err != nil {
+		return nil, err
 	}
 
 	// 2. 验证链ID匹配
 	if wallet.ChainID != req.ChainID {
-		return nil, errors.New("chain_id mismatch")
+		return nil, ErrChainIDMismatch
+	}
+
+	// 合约地址不能为零地址或发送钱包自身
+	if err := validateRecipient(wallet.Address, req.ContractAddress); err != nil {
+		return nil, err
+	}
+
+	// 白名单校验（合约地址同样需要在白名单中）
+	if err := s.whitelistService.CheckRecipient(ctx, wallet, req.ContractAddress); err != nil {
+		return nil, err
+	}
+
+	// 3. 解析方法并编码calldata
+	method, err := blockchain.LoadMethod(req.ABI, req.Method)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+	}
+	data, err := blockchain.EncodeCall(method, req.Args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+	}
+
+	// 记录规范化后的参数，便于在交易历史中展示
+	decoded, err := blockchain.DecodeInputs(method, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+	}
+	args := make([]*models.ContractOutput, len(decoded))
+	for i, value := range decoded {
+		args[i] = &models.ContractOutput{
+			Name:  method.Inputs[i].Name,
+			Type:  method.Inputs[i].Type.String(),
+			Value: value,
+		}
+	}
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
 	}
 
-	// 3. 检查余额是否充足
-	balance, err := s.walletService.GetBalance(ctx, userID, req.FromAddress)
+	// 4. 转换金额并广播
+	value := new(big.Int)
+	if req.Value != "" {
+		if value, err = parseWeiAmount(req.Value, true); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkMemberSendLimit(membership, value); err != nil {
+		return nil, err
+	}
+
+	return s.broadcast(ctx, userID, wallet, &outgoingTx{
+		To:         req.ContractAddress,
+		Value:      value,
+		Data:       data,
+		GasLimit:   req.GasLimit,
+		MethodName: method.Name,
+		MethodArgs: string(argsJSON),
+		Passphrase: req.Passphrase,
+	})
+}
+
+// SendRecurringPayment 执行一次定期转账（由Worker调用，交易记录关联计划ID）
+func (s *TransactionService) SendRecurringPayment(ctx context.Context, payment *models.RecurringPayment) (*models.Transaction, error) {
+	// 1. 验证计划所有者仍有付款钱包的转账权限
+	wallet, err := s.walletRepo.GetByID(ctx, payment.WalletID)
 	if err != nil {
 		return nil, err
 	}
+	membership, err := authorizeWallet(ctx, s.walletRepo, payment.UserID, wallet, PermSend)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.ChainID != payment.ChainID {
+		return nil, ErrChainIDMismatch
+	}
+	if wallet.PassphraseProtected() {
+		return nil, ErrPassphraseRequired
+	}
 
-	// 转换金额
-	amount := new(big.Int)
-	amount.SetString(req.Amount, 10)
+	// 2. 白名单校验（每次执行时重新校验，白名单可能已变更）
+	if err := s.whitelistService.CheckRecipient(ctx, wallet, payment.ToAddress); err != nil {
+		return nil, err
+	}
 
-	// 获取gas价格
-	gasPrice, err := s.blockchainClient.GetGasPrice(ctx)
+	// 3. 转换金额并广播
+	amount, err := parseWeiAmount(payment.Amount, false)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkMemberSendLimit(membership, amount); err != nil {
+		return nil, err
+	}
+
+	paymentID := payment.ID
+	return s.broadcast(ctx, payment.UserID, wallet, &outgoingTx{
+		To:                 payment.ToAddress,
+		Value:              amount,
+		RecurringPaymentID: &paymentID,
+	})
+}
+
+// outgoingTx 待广播的交易参数
+type outgoingTx struct {
+	To                 string
+	ToENSName          string // 收款方ENS名称（以ENS名称发起时）
+	Value              *big.Int
+	Data               []byte              // 合约调用数据，普通转账为空
+	GasLimit           int64               // 为0时自动确定
+	GasPrice           *big.Int            // 固定gas价格（清空余额时金额按该价格计算），为nil时按档位获取
+	Speed              models.GasSpeed     // Gas价格档位，为空时使用节点建议价格
+	MethodName         string              // 合约方法名（合约调用）
+	MethodArgs         string              // 合约方法参数JSON（合约调用）
+	RecurringPaymentID *uint               // 关联的定期转账计划（定期转账执行）
+	Passphrase         string              // 钱包私钥口令（口令保护的钱包）
+	Note               string              // 用户备注（仅保存到数据库）
+	Tags               []string            // 用户标签（已规范化，仅保存到数据库）
+	Proposal           *models.Transaction // 审批通过的待审批交易（广播后更新该记录而非新建）
+}
+
+// broadcast 校验余额与限额后签名、广播并保存交易
+func (s *TransactionService) broadcast(ctx context.Context, userID uint, wallet *models.Wallet, out *outgoingTx) (*models.Transaction, error) {
+	// 维护期间暂停发送
+	if err := s.checkSendingEnabled(ctx); err != nil {
+		return nil, err
+	}
+
+	// 已归档的钱包不能发送交易（包括定期转账与审批通过的交易）
+	if wallet.Archived {
+		return nil, ErrWalletArchived
+	}
+
+	// 链被部署策略禁用后已有钱包不能再发送交易
+	if err := checkChainEnabled(wallet.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 链节点不健康时余额与nonce可能已过期，暂停发送
+	if err := s.chainHealth.CheckSendable(wallet.ChainID); err != nil {
+		return nil, err
+	}
+
+	// 超过审批阈值的交易只能通过审批流程发出
+	if out.Proposal == nil && wallet.RequiresApproval(out.Value) {
+		return nil, ErrApprovalRequired
+	}
+
+	// 钱包所在链的节点（余额、nonce、gas与广播都不能使用其他链的节点）
+	client, err := s.client(wallet.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 检查余额是否充足（扣除已内部转出、尚未在链上结算的金额）
+	balance, err := s.walletService.GetBalance(ctx, userID, wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+	balance = spendableOnChain(wallet, balance)
+
+	// 获取gas价格（不低于链的gas价格下限）：采用EIP-1559的链为最高费用与小费上限，指定固定价格时构建legacy交易（清空余额需精确计算网络费用）
+	params := s.paramsFor(wallet.ChainID)
+	gasPrice := out.GasPrice
+	var tipCap *big.Int
+	if gasPrice == nil {
+		if gasPrice, tipCap, err = s.fees(ctx, wallet.ChainID, out.Speed); err != nil {
+			return nil, err
+		}
+	}
 
-	// 设置gas limit（如果未指定，使用默认值21000）
-	gasLimit := req.GasLimit
+	// 设置gas limit（未指定时原生币与ERC-20转账使用链的默认值，其他合约调用按calldata估算）
+	gasLimit := out.GasLimit
 	if gasLimit == 0 {
-		gasLimit = 21000
+		gasLimit = int64(params.DefaultGasLimit(out.Data))
+		if gasLimit == 0 {
+			estimated, err := client.EstimateGas(ctx, wallet.Address, out.To, out.Value, out.Data)
+			if err != nil {
+				return nil, err
+			}
+			gasLimit = int64(estimated)
+		}
 	}
 
 	// 计算总费用：amount + gas费用
 	gasFee := new(big.Int).Mul(gasPrice, big.NewInt(gasLimit))
-	totalCost := new(big.Int).Add(amount, gasFee)
+	totalCost := new(big.Int).Add(out.Value, gasFee)
 
 	if balance.Cmp(totalCost) < 0 {
-		return nil, errors.New("insufficient balance")
+		return nil, ErrInsufficientBalance
 	}
 
-	// 4. 获取私钥
-	privateKey, err := s.walletService.GetPrivateKey(ctx, req.FromAddress)
+	// 占用每日限额（交易未成功发出时释放）
+	reservation, err := s.limitService.Reserve(ctx, wallet, out.Value)
 	if err != nil {
 		return nil, err
 	}
+	sent := false
+	defer func() {
+		if !sent {
+			s.limitService.Release(context.WithoutCancel(ctx), reservation)
+		}
+	}()
 
-	// 5. 获取nonce
-	nonce, err := s.blockchainClient.GetNonce(ctx, req.FromAddress)
+	// 2. 获取私钥
+	privateKey, err := s.walletService.GetPrivateKey(ctx, wallet.Address, out.Passphrase)
 	if err != nil {
 		return nil, err
 	}
 
-	// 6. 构建交易
-	toAddress := common.HexToAddress(req.ToAddress)
-	tx := types.NewTransaction(
-		nonce,
-		toAddress,
-		amount,
-		uint64(gasLimit),
-		gasPrice,
-		nil, // data字段为空（普通转账）
-	)
-
-	// 7. 签名交易
-	chainID := big.NewInt(int64(wallet.ChainID))
-	signedTx, err := s.blockchainClient.SignTransaction(tx, privateKey, chainID)
+	// 3. 获取nonce
+	nonce, err := client.GetNonce(ctx, wallet.Address)
 	if err != nil {
 		return nil, err
 	}
 
-	// 8. 发送交易到链上
-	if err := s.blockchainClient.SendTransaction(ctx, signedTx); err != nil {
+	// 4. 构建交易
+	tx := params.BuildTransaction(nonce, out.To, out.Value, uint64(gasLimit), gasPrice, tipCap, out.Data)
+
+	// 5. 签名交易
+	chainID := big.NewInt(int64(wallet.ChainID))
+	signedTx, err := client.SignTransaction(tx, privateKey, chainID)
+	if err != nil {
 		return nil, err
 	}
 
-	// 9. 保存交易记录到数据库
+	// 6. 先在同一事务中保存交易记录、发件箱事件与限额占用的交易哈希，避免出现链上已转账但无记录的情况
 	transaction := &models.Transaction{
-		WalletID:    wallet.ID,
-		TxHash:      signedTx.Hash().Hex(),
-		FromAddress: req.FromAddress,
-		ToAddress:   req.ToAddress,
-		Amount:      utils.WeiToEthString(amount),
-		GasPrice:    gasPrice.String(),
-		GasLimit:    gasLimit,
-		Nonce:       nonce,
-		Status:      models.TxStatusPending,
-		ChainID:     wallet.ChainID,
+		WalletID:              wallet.ID,
+		TxHash:                signedTx.Hash().Hex(),
+		FromAddress:           wallet.Address,
+		ToAddress:             out.To,
+		ToENSName:             out.ToENSName,
+		Amount:                utils.WeiToEthString(out.Value),
+		GasPrice:              gasPrice.String(),
+		GasLimit:              gasLimit,
+		Nonce:                 nonce,
+		Status:                models.TxStatusPending,
+		ChainID:               wallet.ChainID,
+		ConfirmationsRequired: s.requiredConfirmations(wallet),
+		MethodName:            out.MethodName,
+		MethodArgs:            out.MethodArgs,
+		RecurringPaymentID:    out.RecurringPaymentID,
+		Note:                  out.Note,
+	}
+	for _, tag := range out.Tags {
+		transaction.Tags = append(transaction.Tags, models.TransactionTag{Tag: tag})
+	}
+
+	// 审批通过的交易沿用待审批记录
+	if out.Proposal != nil {
+		transaction.ID = out.Proposal.ID
 	}
 
-	if err := s.txRepo.Create(ctx, transaction); err != nil {
+	event, err := s.createdEvent(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if out.Proposal != nil {
+		err = s.txRepo.ExecuteApproval(ctx, transaction, event, reservation)
+		if errors.Is(err, repository.ErrNotAwaitingApproval) {
+			err = ErrNotAwaitingApproval
+		}
+	} else {
+		err = s.txRepo.CreateWithOutbox(ctx, transaction, event, reservation)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	// 10. 发送消息到队列（异步监听交易状态）
-	if err := s.queue.Publish("transaction.created", transaction); err != nil {
-		logger.Warn("failed to publish transaction to queue",
+	// 7. 发送交易到链上（失败时标记记录，监听任务将忽略非pending交易）
+	// 记录已保存，此后的步骤不随请求取消或超时中断，广播使用客户端单独的广播时限
+	ctx = context.WithoutCancel(ctx)
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		// 广播超时时节点可能已接收交易，保持pending由监听任务确认（未上链时按卡住交易处理），不能标记为失败后让用户重发
+		if !apperr.IsTimeout(err) {
+			if markErr := s.txRepo.MarkBroadcastFailed(ctx, transaction.TxHash, err.Error()); markErr != nil {
+				logger.WithCtx(ctx).Error("failed to mark transaction as broadcast failed",
+					zap.String("tx_hash", transaction.TxHash),
+					zap.Error(markErr),
+				)
+			}
+			return nil, err
+		}
+		logger.WithCtx(ctx).Warn("broadcast timed out, leaving transaction pending",
 			zap.String("tx_hash", transaction.TxHash),
 			zap.Error(err),
 		)
 	}
+	sent = true
+
+	// 8. 广播成功后立即失效双方余额缓存，避免后续查询与余额校验读到旧值
+	s.invalidateBalances(ctx, transaction)
 
 	return transaction, nil
 }
 
+// gasPrice 按档位获取gas价格（未指定档位或档位价格不可用时使用节点建议价格）
+func (s *TransactionService) gasPrice(ctx context.Context, chainID int, speed models.GasSpeed) (*big.Int, error) {
+	if speed != "" && s.gasOracle != nil {
+		price, err := s.gasOracle.Price(ctx, chainID, speed)
+		if err == nil {
+			return price, nil
+		}
+		logger.WithCtx(ctx).Warn("gas tier unavailable, using suggested gas price",
+			zap.String("speed", string(speed)),
+			zap.Error(err),
+		)
+	}
+	client, err := s.client(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetGasPrice(ctx)
+}
+
+// fees 获取交易的gas价格（不低于链的gas价格下限）与EIP-1559小费上限
+//
+// 采用EIP-1559且Gas预言机提供基础费用时，小费为档位价格（未指定档位时为standard）减去基础费用，gasPrice为最高费用（2 × 基础费用 + 小费，
+// 基础费用连续上涨时交易仍可打包）；legacy链、未配置预言机或节点不支持eth_feeHistory时tipCap为nil，按档位或节点建议价格构建legacy交易。
+func (s *TransactionService) fees(ctx context.Context, chainID int, speed models.GasSpeed) (gasPrice, tipCap *big.Int, err error) {
+	params := s.paramsFor(chainID)
+	if !params.LegacyGas && s.gasOracle != nil {
+		tiers, err := s.gasOracle.GetTiers(ctx, chainID)
+		if err == nil && tiers.BaseFee != "" {
+			baseFee := utils.DecimalToWei(tiers.BaseFee)
+			tipCap = new(big.Int).Sub(utils.DecimalToWei(tiers.Price(speed)), baseFee)
+			if tipCap.Sign() < 0 {
+				tipCap.SetInt64(0)
+			}
+			feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+			return params.ApplyGasPriceFloor(feeCap), tipCap, nil
+		}
+		if err != nil {
+			logger.WithCtx(ctx).Warn("gas tiers unavailable, building legacy transaction",
+				zap.Int("chain_id", chainID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if gasPrice, err = s.gasPrice(ctx, chainID, speed); err != nil {
+		return nil, nil, err
+	}
+	return params.ApplyGasPriceFloor(gasPrice), nil, nil
+}
+
+// invalidateBalances 失效发送方余额缓存，收款方是本系统钱包时一并失效
+func (s *TransactionService) invalidateBalances(ctx context.Context, tx *models.Transaction) {
+	addresses := []string{tx.FromAddress}
+	if _, err := s.walletRepo.GetByAddress(ctx, tx.ToAddress); err == nil {
+		addresses = append(addresses, tx.ToAddress)
+	}
+	s.walletService.InvalidateBalance(ctx, addresses...)
+}
+
 // GetTransaction 获取交易详情
 func (s *TransactionService) GetTransaction(ctx context.Context, userID uint, txHash string) (*models.Transaction, error) {
-	// 1. 查询交易
-	tx, err := s.txRepo.GetByTxHash(ctx, txHash)
+	return s.getAuthorizedTransaction(ctx, userID, txHash, PermView)
+}
+
+// getAuthorizedTransaction 查询交易并校验用户对所属钱包的权限
+func (s *TransactionService) getAuthorizedTransaction(ctx context.Context, userID uint, txHash string, perm WalletPermission) (*models.Transaction, error) {
+	// 1. 查询交易（仅查看时可读只读副本，后续要修改交易时读主库）
+	lookup := s.txRepo.GetByTxHash
+	if perm == PermView {
+		lookup = s.txRepo.GetByTxHashForDisplay
+	}
+	tx, err := lookup(ctx, txHash)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 验证所有权
+	// 2. 验证权限（无访问权限时视为交易不存在）
 	wallet, err := s.walletRepo.GetByID(ctx, tx.WalletID)
 	if err != nil {
 		return nil, err
 	}
-	if wallet.UserID != userID {
-		return nil, errors.New("transaction not found")
+	if _, err := authorizeWallet(ctx, s.walletRepo, userID, wallet, perm); err != nil {
+		if errors.Is(err, ErrWalletNotFound) {
+			return nil, apperr.NotFound("transaction")
+		}
+		return nil, err
+	}
+
+	// 3. 加载标签
+	if tx.Tags, err = s.txRepo.GetTags(ctx, tx.ID); err != nil {
+		return nil, err
 	}
 
 	return tx, nil
 }
 
-// ListTransactions 查询交易列表
-func (s *TransactionService) ListTransactions(ctx context.Context, userID uint, req *models.TransactionListRequest) (*models.TransactionListResponse, error) {
-	// 1. 如果指定了钱包地址，验证所有权
-	if req.WalletAddress != "" {
-		wallet, err := s.walletRepo.GetByAddress(ctx, req.WalletAddress)
-		if err != nil {
-			return nil, err
-		}
-		if wallet.UserID != userID {
-			return nil, errors.New("wallet not found")
-		}
-	} else {
-		// 2. 如果未指定钱包地址，查询用户所有钱包的交易
-		wallets, err := s.walletRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			return nil, err
+// UpdateTransactionMeta 更新交易备注与标签（需要交易所属钱包的转账权限）
+func (s *TransactionService) UpdateTransactionMeta(ctx context.Context, userID uint, txHash string, req *models.TransactionMetaRequest) (*models.Transaction, error) {
+	if req.Note == nil && req.Tags == nil {
+		return nil, ErrEmptyMetaUpdate
+	}
+
+	// 1. 查询交易并验证权限
+	tx, err := s.getAuthorizedTransaction(ctx, userID, txHash, PermSend)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 更新备注与标签
+	var tags []string
+	if req.Tags != nil {
+		tags = normalizeTags(*req.Tags)
+		if tags == nil {
+			tags = []string{}
 		}
+	}
+	if err := s.txRepo.UpdateMeta(ctx, tx.ID, req.Note, tags); err != nil {
+		return nil, err
+	}
 
-		// 构建钱包ID列表（这里简化处理，实际应优化查询）
-		if len(wallets) > 0 {
-			req.WalletAddress = wallets[0].Address // 临时方案
+	// 3. 返回更新后的交易
+	return s.GetTransaction(ctx, userID, txHash)
+}
+
+// normalizeTags 标签去除首尾空白、转为小写并去重
+func normalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
 		}
+		seen[tag] = true
+		result = append(result, tag)
 	}
+	return result
+}
 
-	// 3. 查询交易列表
-	transactions, total, err := s.txRepo.List(ctx, req)
+// ListTransactions 查询交易列表
+func (s *TransactionService) ListTransactions(ctx context.Context, userID uint, req *models.TransactionListRequest) (*models.TransactionListResponse, error) {
+	// 1. 确定查询的钱包
+	if err := checkListRequest(req); err != nil {
+		return nil, err
+	}
+	walletIDs, err := s.listWalletIDs(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询交易列表
+	transactions, total, err := s.txRepo.List(ctx, walletIDs, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 转换为响应格式
+	// 3. 转换为响应格式
 	txResponses := make([]*models.TransactionResponse, len(transactions))
 	for i, tx := range transactions {
-		txResponses[i] = tx.ToResponse()
+		txResponses[i] = transactionResponse(tx)
 	}
+	s.resolveContactNames(ctx, userID, txResponses)
 
 	return &models.TransactionListResponse{
 		Total:        total,
 		Page:         req.Page,
 		PageSize:     req.PageSize,
+		TotalPages:   models.TotalPages(total, req.PageSize),
+		HasNext:      models.HasNextPage(total, req.Page, req.PageSize),
 		Transactions: txResponses,
 	}, nil
 }
 
-// MonitorTransaction 监听交易状态（后台任务调用）
+// ListTransactionsVersion 交易列表的数据版本（用于ETag）：筛选条件、分页与匹配交易的数量及最近更新时间
+// （联系人名称与对手方标签在响应时解析，其变化不会使ETag失效）
+func (s *TransactionService) ListTransactionsVersion(ctx context.Context, userID uint, req *models.TransactionListRequest) (string, error) {
+	if err := checkListRequest(req); err != nil {
+		return "", err
+	}
+	walletIDs, err := s.listWalletIDs(ctx, userID, req)
+	if err != nil {
+		return "", err
+	}
+	count, updatedAt, err := s.txRepo.ListVersion(ctx, walletIDs, req)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v:%v:%d:%d", *req, walletIDs, count, updatedAt.UnixNano()), nil
+}
+
+// checkListRequest 校验交易列表的分页、时间范围与金额范围（格式已由binding校验），并填充默认分页参数
+func checkListRequest(req *models.TransactionListRequest) error {
+	if err := normalizePage(&req.Page, &req.PageSize); err != nil {
+		return err
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && req.From.After(req.To) {
+		return apperr.Invalid("error.from_after_to", "from must not be after to")
+	}
+	return checkTransactionFilter(&req.TransactionFilter)
+}
+
+// normalizePage 未指定的页码与每页数量使用默认值；每页数量超过上限时返回错误（不截断，避免客户端按错误的页大小翻页）
+func normalizePage(page, pageSize *int) error {
+	if *pageSize > models.MaxPageSize {
+		return apperr.Invalid("error.page_size_too_large", fmt.Sprintf("page_size must not exceed %d", models.MaxPageSize))
+	}
+	if *page <= 0 {
+		*page = models.DefaultPage
+	}
+	if *pageSize <= 0 {
+		*pageSize = models.DefaultPageSize
+	}
+	return nil
+}
+
+// checkTransactionFilter 校验交易列表、导出与统计共用的筛选条件：金额下限不能大于上限（按数值比较）
+func checkTransactionFilter(filter *models.TransactionFilter) error {
+	if filter.MinAmount == "" || filter.MaxAmount == "" {
+		return nil
+	}
+	minAmount, okMin := utils.EthToWei(filter.MinAmount)
+	maxAmount, okMax := utils.EthToWei(filter.MaxAmount)
+	if okMin && okMax && minAmount.Cmp(maxAmount) > 0 {
+		return apperr.Invalid("error.min_amount_exceeds_max", "min_amount must not be greater than max_amount")
+	}
+	return nil
+}
+
+// listWalletIDs 交易列表查询的钱包：指定钱包地址时验证查看权限（未收录或无权访问的地址统一视为不存在），
+// 否则为用户可访问的所有钱包
+func (s *TransactionService) listWalletIDs(ctx context.Context, userID uint, req *models.TransactionListRequest) ([]uint, error) {
+	if req.WalletAddress != "" {
+		wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.WalletAddress, PermView)
+		if err != nil {
+			return nil, err
+		}
+		return []uint{wallet.ID}, nil
+	}
+
+	wallets, err := s.walletRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	walletIDs := make([]uint, len(wallets))
+	for i, wallet := range wallets {
+		walletIDs[i] = wallet.ID
+	}
+	return walletIDs, nil
+}
+
+// transactionResponse 转换为响应格式并附带费用明细
+func transactionResponse(tx *models.Transaction) *models.TransactionResponse {
+	resp := tx.ToResponse()
+	resp.TransactionFees = utils.TransactionFees(tx)
+	return resp
+}
+
+// BuildResponse 转换为响应格式并填充联系人名称
+func (s *TransactionService) BuildResponse(ctx context.Context, userID uint, tx *models.Transaction) *models.TransactionResponse {
+	resp := transactionResponse(tx)
+	s.resolveContactNames(ctx, userID, []*models.TransactionResponse{resp})
+	return resp
+}
+
+// resolveContactNames 填充联系人名称与对手方标签（失败仅记录日志，不影响主流程）
+func (s *TransactionService) resolveContactNames(ctx context.Context, userID uint, txs []*models.TransactionResponse) {
+	if err := s.contactService.ResolveNames(ctx, userID, txs); err != nil {
+		logger.WithCtx(ctx).Warn("failed to resolve contact names", zap.Error(err))
+	}
+	if s.labelService != nil {
+		if err := s.labelService.Annotate(ctx, userID, txs); err != nil {
+			logger.WithCtx(ctx).Warn("failed to resolve counterparty labels", zap.Error(err))
+		}
+	}
+	if s.ensService == nil || !s.ensService.reverseLookup || len(txs) == 0 {
+		return
+	}
+
+	// 反向解析转入交易发送方的ENS主名称（用户自己的钱包除外）
+	wallets, err := s.walletRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to load wallets for ENS lookup", zap.Error(err))
+		return
+	}
+	own := make(map[string]bool, len(wallets))
+	for _, wallet := range wallets {
+		own[strings.ToLower(wallet.Address)] = true
+	}
+	s.ensService.AnnotateSenders(ctx, own, txs)
+}
+
+// MonitorTransaction 监听交易状态（后台任务调用），达到确认深度前返回ErrAwaitingConfirmations
 func (s *TransactionService) MonitorTransaction(ctx context.Context, txHash string) error {
-	// 1. 查询交易回执
-	receipt, err := s.blockchainClient.GetTransactionReceipt(ctx, txHash)
+	// 已最终确认的交易直接返回（消息可能重复投递）
+	tx, err := s.txRepo.GetByTxHash(ctx, txHash)
 	if err != nil {
-		// 交易尚未确认
 		return err
 	}
+	if tx.Status != models.TxStatusPending && tx.Status != models.TxStatusConfirming {
+		return nil
+	}
 
-	// 2. 判断交易状态
-	status := models.TxStatusFailed
-	if receipt.Status == 1 {
-		status = models.TxStatusSuccess
+	return s.lockedCheckReceipt(ctx, tx, 0)
+}
+
+// lockedCheckReceipt 持有交易锁时检查回执，锁被其他副本持有时跳过（Redis不可用时退化为不加锁）
+func (s *TransactionService) lockedCheckReceipt(ctx context.Context, tx *models.Transaction, head uint64) error {
+	if s.locker == nil {
+		return s.checkReceipt(ctx, tx, head)
+	}
+
+	lock, err := s.locker.TryLock(ctx, "tx:"+tx.TxHash, txLockTTL)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to acquire transaction lock", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+		return s.checkReceipt(ctx, tx, head)
+	}
+	if lock == nil {
+		return nil
 	}
+	defer lock.Release(context.WithoutCancel(ctx))
 
-	// 3. 更新交易状态
-	if err := s.txRepo.UpdateStatus(ctx, txHash, status, receipt.BlockNumber.Int64()); err != nil {
+	return s.checkReceipt(ctx, tx, head)
+}
+
+// checkReceipt 查询回执并推进交易状态，head为0时查询最新区块号
+func (s *TransactionService) checkReceipt(ctx context.Context, tx *models.Transaction, head uint64) error {
+	txHash := tx.TxHash
+	client, err := s.client(tx.ChainID)
+	if err != nil {
 		return err
 	}
 
-	// 4. 如果交易成功，更新钱包余额
-	if status == models.TxStatusSuccess {
-		tx, err := s.txRepo.GetByTxHash(ctx, txHash)
-		if err != nil {
+	// 1. 查询交易回执
+	receipt, err := client.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		// 之前已打包的交易回执消失：所在区块被链重组移除，恢复为pending重新等待打包
+		if tx.Status == models.TxStatusConfirming && errors.Is(err, ethereum.NotFound) {
+			s.revertReorgedTransaction(ctx, tx)
+		}
+		// 交易尚未确认
+		return err
+	}
+	metrics.TransactionChecks.WithLabelValues(checkIncluded).Inc()
+
+	// 2. 计算确认数
+	latest := head
+	if latest == 0 {
+		if latest, err = client.GetBlockNumber(ctx); err != nil {
 			return err
 		}
+	}
+	blockNumber := receipt.BlockNumber.Int64()
+	var confirmations uint64
+	if latest >= receipt.BlockNumber.Uint64() {
+		confirmations = latest - receipt.BlockNumber.Uint64() + 1
+	}
+	required := s.confirmationsFor(tx)
 
-		wallet, err := s.walletRepo.GetByID(ctx, tx.WalletID)
-		if err != nil {
+	// 3. 首次打包时更新为confirming并推送打包事件（并发处理时只有完成更新的一方推送）
+	included := false
+	if tx.Status == models.TxStatusPending {
+		if included, err = s.txRepo.MarkIncluded(ctx, txHash, blockNumber, confirmations); err != nil {
 			return err
 		}
+		if included {
+			s.publishTransactionEvent(ctx, &models.WalletEvent{
+				Type:                  models.EventTransactionIncluded,
+				Address:               tx.FromAddress,
+				TxHash:                txHash,
+				ExplorerURL:           models.ExplorerTxURL(tx.ChainID, txHash),
+				Status:                models.TxStatusConfirming,
+				BlockNumber:           blockNumber,
+				Confirmations:         confirmations,
+				ConfirmationsRequired: required,
+			})
+		}
+	}
 
-		// 异步更新余额
-		go s.walletService.updateBalanceAsync(context.Background(), wallet.Address)
+	// 4. 未达到确认深度时记录进度并继续等待
+	if confirmations < required {
+		if included {
+			return ErrAwaitingConfirmations
+		}
+		if tx.Status == models.TxStatusConfirming && tx.BlockNumber != blockNumber {
+			logger.WithCtx(ctx).Warn("chain reorganization detected, transaction moved to another block",
+				zap.String("tx_hash", txHash),
+				zap.Int64("old_block", tx.BlockNumber),
+				zap.Int64("new_block", blockNumber),
+			)
+		}
+		if err := s.txRepo.MarkConfirming(ctx, txHash, blockNumber, confirmations); err != nil {
+			return err
+		}
+		return ErrAwaitingConfirmations
+	}
+
+	// 判断交易状态（失败时在所在区块上重新执行以获取回滚原因）
+	status := models.TxStatusSuccess
+	var errMsg string
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = models.TxStatusFailed
+		errMsg = s.revertReason(ctx, client, txHash, receipt.BlockNumber)
+	}
+
+	// 5. 在同一事务中更新交易状态与实际gas消耗、标记双方余额待刷新（仅未最终确认的交易可更新，并发处理时只有一方继续后续步骤）
+	updated := false
+	err = s.txRepo.WithTx(ctx, func(txRepo *repository.TransactionRepository, walletRepo *repository.WalletRepository) error {
+		var err error
+		updated, err = txRepo.ConfirmIfPending(ctx, txHash, status, blockNumber, confirmations, int64(receipt.GasUsed), receipt.EffectiveGasPrice, errMsg)
+		if err != nil || !updated {
+			return err
+		}
+		return markConfirmedBalancesStale(ctx, walletRepo, tx, status)
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return nil
 	}
+	metrics.TransactionsConfirmed.WithLabelValues(string(status)).Inc()
+
+	// 6. 推送交易最终确认事件
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:                  models.EventTransactionConfirmed,
+		Address:               tx.FromAddress,
+		TxHash:                txHash,
+		ExplorerURL:           models.ExplorerTxURL(tx.ChainID, txHash),
+		Status:                status,
+		BlockNumber:           blockNumber,
+		Confirmations:         confirmations,
+		ConfirmationsRequired: required,
+		Message:               errMsg,
+	})
 
-	logger.Info("transaction confirmed",
+	// 7. 提交后失效缓存并异步刷新链上余额（失败交易也消耗了gas；刷新失败或进程退出时由Worker按待刷新标记重试）
+	s.refreshConfirmedBalances(ctx, tx, status)
+
+	logger.WithCtx(ctx).Info("transaction confirmed",
 		zap.String("tx_hash", txHash),
 		zap.String("status", string(status)),
-		zap.Int64("block_number", receipt.BlockNumber.Int64()),
+		zap.Int64("block_number", blockNumber),
+		zap.Uint64("confirmations", confirmations),
 	)
 
 	return nil
 }
 
+// revertReason 在交易所在区块上重新执行失败交易，返回解码后的回滚原因
+//
+// 节点已裁剪该区块的状态、调用失败或重新执行未回滚（如依赖同一区块内之前的交易）时返回revertReasonUnavailable，不影响确认流程。
+func (s *TransactionService) revertReason(ctx context.Context, client blockchain.BlockchainClient, txHash string, blockNumber *big.Int) string {
+	_, err := client.ReplayTransaction(ctx, txHash, blockNumber)
+	if reason, ok := blockchain.RevertReason(err); ok {
+		return reason
+	}
+	logger.WithCtx(ctx).Info("revert reason unavailable for failed transaction",
+		zap.String("tx_hash", txHash),
+		zap.Error(err),
+	)
+	return revertReasonUnavailable
+}
+
+// refreshConfirmedBalances 交易最终确认后失效余额缓存，并异步刷新发送方与收款方（本系统钱包）的链上余额
+func (s *TransactionService) refreshConfirmedBalances(ctx context.Context, tx *models.Transaction, status models.TransactionStatus) {
+	s.invalidateBalances(ctx, tx)
+	if wallet, err := s.walletRepo.GetByID(ctx, tx.WalletID); err == nil && !wallet.Archived {
+		// 已归档的钱包不参与后台刷新，查询余额时再从链上读取
+		s.walletService.scheduleBalanceRefresh(ctx, wallet)
+	}
+	if status == models.TxStatusSuccess {
+		// 收款方也是本系统钱包时同步刷新其余额（触发入账事件）
+		if recipient, err := s.walletRepo.GetByAddress(ctx, tx.ToAddress); err == nil && !recipient.Archived {
+			s.walletService.scheduleBalanceRefresh(ctx, recipient)
+		}
+	}
+}
+
+// markConfirmedBalancesStale 在确认交易的同一事务中标记发送方与收款方（本系统钱包）的链上余额待刷新
+//
+// 账本余额按最近一次查询到的链上余额保存，等待确认期间的余额查询可能已写入扣款后的余额，因此不按交易金额增减，
+// 只记录待刷新标记；提交后的刷新失败或进程退出时，Worker按该标记重试，不会丢失余额更新。
+func markConfirmedBalancesStale(ctx context.Context, walletRepo *repository.WalletRepository, tx *models.Transaction, status models.TransactionStatus) error {
+	ids := []uint{tx.WalletID}
+	if status == models.TxStatusSuccess {
+		recipient, err := walletRepo.GetByAddress(ctx, tx.ToAddress)
+		if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return err
+		}
+		if err == nil && recipient.ID != tx.WalletID {
+			ids = append(ids, recipient.ID)
+		}
+	}
+	return walletRepo.MarkBalanceStale(ctx, ids...)
+}
+
+// createdEvent 构建交易监听队列的发件箱事件（消息仅携带交易哈希、链与钱包，不包含地址、金额与备注）
+func (s *TransactionService) createdEvent(ctx context.Context, tx *models.Transaction) (*models.OutboxEvent, error) {
+	payload, err := s.queueCodec.Seal(&models.TransactionCreatedMessage{
+		TxHash:   tx.TxHash,
+		ChainID:  tx.ChainID,
+		WalletID: tx.WalletID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewOutboxEvent(ctx, TransactionCreatedQueue, payload)
+}
+
+// publishTransactionEvent 推送交易事件（失败时只记录日志）
+func (s *TransactionService) publishTransactionEvent(ctx context.Context, event *models.WalletEvent) {
+	if err := s.eventService.Publish(ctx, event); err != nil {
+		logger.WithCtx(ctx).Warn("failed to publish transaction event",
+			zap.String("tx_hash", event.TxHash),
+			zap.String("type", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+}
+
+// MonitorAtBlock chainID链的新区块到达时批量检查该链已到期的未最终确认交易：pending交易仅在发送方nonce已被消耗（可能已打包）时查询回执，
+// 否则只确认节点是否仍持有该交易；confirming交易按区块号计算确认数，达到确认深度时再核对回执（回执消失则视为链重组）；未推进到最终状态的交易按档位推迟下次检查
+func (s *TransactionService) MonitorAtBlock(ctx context.Context, chainID int, head uint64) {
+	transactions, err := s.txRepo.DueForCheck(ctx, chainID, time.Now(), s.schedule.BatchSize)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to get due transactions", zap.Error(err))
+		return
+	}
+
+	confirmedNonces := make(map[string]uint64) // 发送方地址(小写) -> 最新区块中的nonce
+	nonceFailed := make(map[string]bool)       // 本轮nonce查询失败的发送方地址(小写)
+	for _, tx := range transactions {
+		switch tx.Status {
+		case models.TxStatusConfirming:
+			var confirmations uint64
+			if head >= uint64(tx.BlockNumber) {
+				confirmations = head - uint64(tx.BlockNumber) + 1
+			}
+			if confirmations < s.confirmationsFor(tx) {
+				if confirmations != tx.Confirmations {
+					if err := s.txRepo.MarkConfirming(ctx, tx.TxHash, tx.BlockNumber, confirmations); err != nil {
+						logger.WithCtx(ctx).Warn("failed to update confirmations", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+					}
+				}
+				s.scheduleNextCheck(ctx, tx, s.schedule.FreshInterval)
+				continue
+			}
+		case models.TxStatusPending:
+			// nonce查询失败时不缓存（否则nonce视为0，已打包的交易会被当作未打包），该地址的交易本轮直接查询回执
+			from := strings.ToLower(tx.FromAddress)
+			if nonceFailed[from] {
+				break
+			}
+			nonce, ok := confirmedNonces[from]
+			if !ok {
+				if nonce, err = s.confirmedNonce(ctx, tx); err != nil {
+					logger.WithCtx(ctx).Warn("failed to get confirmed nonce", zap.String("address", tx.FromAddress), zap.Error(err))
+					nonceFailed[from] = true
+					break
+				}
+				confirmedNonces[from] = nonce
+			}
+			if tx.Nonce >= nonce {
+				// 尚未打包：确认节点是否仍持有该交易
+				if !s.checkUnmined(ctx, tx) {
+					continue
+				}
+				if s.isStuck(tx) {
+					s.notifyStuck(ctx, tx)
+				}
+				s.scheduleNextCheck(ctx, tx, s.nextCheckDelay(tx))
+				continue
+			}
+		}
+
+		s.monitorOne(ctx, tx, head)
+	}
+}
+
+// BatchMonitor 轮询路径：批量检查chainID链已到期的未最终确认交易，最新区块号每轮只查询一次，回执查询以有限并发执行
+func (s *TransactionService) BatchMonitor(ctx context.Context, chainID, concurrency int) {
+	// 1. 查询到期的待确认交易
+	transactions, err := s.txRepo.DueForCheck(ctx, chainID, time.Now(), s.schedule.BatchSize)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to get due transactions", zap.Int("chain_id", chainID), zap.Error(err))
+		return
+	}
+	if len(transactions) == 0 {
+		return
+	}
+
+	// 2. 查询最新区块号（本轮所有交易共用）
+	client, err := s.client(chainID)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("no client for pending transactions", zap.Int("chain_id", chainID), zap.Error(err))
+		return
+	}
+	head, err := client.GetBlockNumber(ctx)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to get block number", zap.Int("chain_id", chainID), zap.Error(err))
+		return
+	}
+
+	// 3. 有限并发检查回执
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, tx := range transactions {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(tx *models.Transaction) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s.monitorOne(ctx, tx, head)
+		}(tx)
+	}
+	wg.Wait()
+
+	logger.WithCtx(ctx).Debug("due transactions checked",
+		zap.Int("chain_id", chainID),
+		zap.Int("count", len(transactions)),
+	)
+}
+
+// monitorOne 检查单笔交易的回执，未推进到最终状态（未打包或确认数不足）时按档位推迟下次检查，查询失败时按连续失败次数退避；
+// 没有回执的pending交易区分仍在交易池中与节点不知道该交易，长时间未打包的交易按nonce是否已被使用标记为dropped或提醒用户；
+// 其他副本持有锁时由对方处理，不修改下次检查时间
+func (s *TransactionService) monitorOne(ctx context.Context, tx *models.Transaction, head uint64) {
+	err := s.lockedCheckReceipt(ctx, tx, head)
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrAwaitingConfirmations):
+		// 已打包（可能由本次检查刚更新为confirming）：按最短间隔跟踪确认数
+		s.scheduleNextCheck(ctx, tx, s.schedule.FreshInterval)
+		return
+	case !errors.Is(err, ethereum.NotFound):
+		s.checkFailed(ctx, tx, "failed to check transaction receipt", err)
+		return
+	case tx.Status != models.TxStatusPending:
+		// 链重组后已恢复为pending，下次检查时再确认节点是否仍持有该交易
+	case !s.checkUnmined(ctx, tx):
+		return
+	case s.isStuck(tx):
+		// 长时间未打包：nonce已被其他交易使用时标记为dropped，否则提醒用户
+		nonce, err := s.confirmedNonce(ctx, tx)
+		if err != nil {
+			logger.WithCtx(ctx).Warn("failed to get confirmed nonce", zap.String("address", tx.FromAddress), zap.Error(err))
+			break
+		}
+		if s.handleStuck(ctx, tx, nonce) {
+			return
+		}
+	}
+	s.scheduleNextCheck(ctx, tx, s.nextCheckDelay(tx))
+}
+
+// confirmedNonce 查询交易发送方在其所在链最新区块中的nonce
+func (s *TransactionService) confirmedNonce(ctx context.Context, tx *models.Transaction) (uint64, error) {
+	client, err := s.client(tx.ChainID)
+	if err != nil {
+		return 0, err
+	}
+	return client.GetConfirmedNonce(ctx, tx.FromAddress)
+}
+
+// revertReorgedTransaction 将回执消失的交易恢复为pending并推送链重组事件
+func (s *TransactionService) revertReorgedTransaction(ctx context.Context, tx *models.Transaction) {
+	reverted, err := s.txRepo.RevertToPending(ctx, tx.TxHash)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to revert reorged transaction", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+		return
+	}
+	if !reverted {
+		return
+	}
+
+	logger.WithCtx(ctx).Warn("chain reorganization detected, transaction receipt disappeared",
+		zap.String("tx_hash", tx.TxHash),
+		zap.Int64("block_number", tx.BlockNumber),
+	)
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:        models.EventTransactionReorged,
+		Address:     tx.FromAddress,
+		TxHash:      tx.TxHash,
+		ExplorerURL: models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		Status:      models.TxStatusPending,
+		BlockNumber: tx.BlockNumber,
+		Message:     "transaction was removed from its block by a chain reorganization and is pending again",
+	})
+}
+
 // GetPendingTransactions 获取所有待确认的交易
 func (s *TransactionService) GetPendingTransactions(ctx context.Context) ([]*models.Transaction, error) {
 	return s.txRepo.GetPendingTransactions(ctx)
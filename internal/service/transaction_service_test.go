@@ -0,0 +1,488 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/blockchain/mock"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// recipient 测试使用的外部收款地址
+const recipient = "0x1111111111111111111111111111111111111111"
+
+// failingPublisher 总是发布失败的发件箱发布目标
+type failingPublisher struct{ err error }
+
+func (p failingPublisher) PublishRaw(ctx context.Context, queueName string, body []byte) error {
+	return p.err
+}
+
+func TestSendTransaction(t *testing.T) {
+	errRPC := errors.New("connection refused")
+	errQueue := errors.New("channel closed")
+
+	tests := []struct {
+		name    string
+		balance *big.Int
+		chainID int
+		setup   func(env *testEnv)
+		wantErr error
+		check   func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction)
+	}{
+		{
+			name:    "success",
+			balance: ether(10),
+			check: func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction) {
+				if tx.Status != models.TxStatusPending {
+					t.Errorf("status = %s, want pending", tx.Status)
+				}
+				if sent := env.chain.SentTransactions(); len(sent) != 1 || sent[0].Hash().Hex() != tx.TxHash {
+					t.Errorf("broadcast transactions = %d, want the saved transaction", len(sent))
+				}
+				assertOutbox(t, env, 1, false)
+			},
+		},
+		{
+			name:    "insufficient balance",
+			balance: big.NewInt(1000),
+			wantErr: ErrInsufficientBalance,
+			check: func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction) {
+				assertTransactionCount(t, env, 0)
+				if sent := env.chain.SentTransactions(); len(sent) != 0 {
+					t.Errorf("broadcast transactions = %d, want 0", len(sent))
+				}
+			},
+		},
+		{
+			name:    "chain id mismatch",
+			balance: ether(10),
+			chainID: 1,
+			wantErr: ErrChainIDMismatch,
+			check: func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction) {
+				assertTransactionCount(t, env, 0)
+			},
+		},
+		{
+			name:    "rpc failure after sign",
+			balance: ether(10),
+			setup: func(env *testEnv) {
+				env.chain.FailOn(mock.MethodSendTransaction, errRPC)
+			},
+			wantErr: errRPC,
+			check: func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction) {
+				// 记录已在广播前保存，广播失败后标记为failed（监听任务忽略）
+				var saved models.Transaction
+				if err := env.db.Where("wallet_id = ?", wallet.ID).First(&saved).Error; err != nil {
+					t.Fatalf("load transaction: %v", err)
+				}
+				if saved.Status != models.TxStatusFailed || saved.ErrorMsg != errRPC.Error() {
+					t.Errorf("status = %s (%q), want failed (%q)", saved.Status, saved.ErrorMsg, errRPC)
+				}
+			},
+		},
+		{
+			name:    "queue publish failure",
+			balance: ether(10),
+			check: func(t *testing.T, env *testEnv, wallet *models.Wallet, tx *models.Transaction) {
+				// 交易与发件箱事件已提交，队列不可用时事件保持待投递并记录错误，恢复后重新投递
+				dispatcher := NewOutboxDispatcher(env.outboxRepo, failingPublisher{err: errQueue}, 0, 10, 0)
+				dispatcher.dispatch(context.Background())
+
+				var event models.OutboxEvent
+				if err := env.db.First(&event).Error; err != nil {
+					t.Fatalf("load outbox event: %v", err)
+				}
+				if event.SentAt != nil || event.Attempts != 1 || event.LastError != errQueue.Error() {
+					t.Errorf("event = sent_at %v attempts %d error %q, want pending with 1 failed attempt", event.SentAt, event.Attempts, event.LastError)
+				}
+				assertTransactionCount(t, env, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			user := env.createUser(t)
+			wallet := env.createWallet(t, user.ID, tt.balance)
+			if tt.setup != nil {
+				tt.setup(env)
+			}
+
+			chainID := tt.chainID
+			if chainID == 0 {
+				chainID = testutil.ChainID
+			}
+			tx, err := env.txs.SendTransaction(context.Background(), user.ID, &models.TransactionCreateRequest{
+				FromAddress: wallet.Address,
+				ToAddress:   recipient,
+				Amount:      ether(1).String(),
+				ChainID:     chainID,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, env, wallet, tx)
+			}
+		})
+	}
+}
+
+func TestMonitorTransaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		receipt    bool
+		status     uint64
+		wantErr    error
+		wantStatus models.TransactionStatus
+	}{
+		{name: "success", receipt: true, status: types.ReceiptStatusSuccessful, wantStatus: models.TxStatusSuccess},
+		{name: "revert", receipt: true, status: types.ReceiptStatusFailed, wantStatus: models.TxStatusFailed},
+		{name: "receipt not found", wantErr: ethereum.NotFound, wantStatus: models.TxStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			user := env.createUser(t)
+			wallet := env.createWallet(t, user.ID, ether(10))
+			tx, err := env.txs.SendTransaction(context.Background(), user.ID, &models.TransactionCreateRequest{
+				FromAddress: wallet.Address,
+				ToAddress:   recipient,
+				Amount:      ether(1).String(),
+				ChainID:     testutil.ChainID,
+			})
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+
+			if tt.receipt {
+				env.chain.SetReceipt(tx.TxHash, tt.status, 100, 21000)
+			}
+			env.chain.SetBlockNumber(100)
+
+			if err := env.txs.MonitorTransaction(context.Background(), tx.TxHash); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			saved, err := env.txRepo.GetByTxHash(context.Background(), tx.TxHash)
+			if err != nil {
+				t.Fatalf("load transaction: %v", err)
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saved.Status, tt.wantStatus)
+			}
+			if tt.receipt && saved.BlockNumber != 100 {
+				t.Errorf("block = %d, want 100", saved.BlockNumber)
+			}
+		})
+	}
+}
+
+// blockingReceiptClient 查询回执时通知调用方并阻塞到释放（模拟慢节点，使两个Worker的检查重叠）
+type blockingReceiptClient struct {
+	*mock.Client
+	entered chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (c *blockingReceiptClient) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	c.calls.Add(1)
+	c.entered <- struct{}{}
+	<-c.release
+	return c.Client.GetTransactionReceipt(ctx, txHash)
+}
+
+func TestMonitorTransactionConcurrentWorkersCheckOnce(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(10))
+	tx, err := env.txs.SendTransaction(ctx, user.ID, &models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      ether(1).String(),
+		ChainID:     testutil.ChainID,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	env.chain.SetReceipt(tx.TxHash, types.ReceiptStatusSuccessful, 100, 21000)
+	env.chain.SetBlockNumber(100)
+
+	// 两个Worker副本共用Redis锁
+	client := &blockingReceiptClient{Client: env.chain, entered: make(chan struct{}, 2), release: make(chan struct{})}
+	newWorker := func() *TransactionService {
+		s := NewTransactionService(env.txRepo, env.walletRepo, env.wallets, blockchain.NewClients(client), env.events, env.contacts, env.whitelist, env.limits)
+		s.SetLocker(env.redis)
+		return s
+	}
+	first, second := newWorker(), newWorker()
+
+	// 第一个Worker持有交易锁并阻塞在回执查询中，第二个Worker此时跳过该交易
+	done := make(chan error, 1)
+	go func() { done <- first.MonitorTransaction(ctx, tx.TxHash) }()
+	<-client.entered
+	secondDone := make(chan error, 1)
+	go func() { secondDone <- second.MonitorTransaction(ctx, tx.TxHash) }()
+	if err := <-secondDone; err != nil {
+		t.Fatalf("second worker: %v", err)
+	}
+	close(client.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first worker: %v", err)
+	}
+
+	if calls := client.calls.Load(); calls != 1 {
+		t.Errorf("receipt checks = %d, want exactly 1", calls)
+	}
+	saved, err := env.txRepo.GetByTxHash(ctx, tx.TxHash)
+	if err != nil {
+		t.Fatalf("load transaction: %v", err)
+	}
+	if saved.Status != models.TxStatusSuccess {
+		t.Errorf("status = %s, want success", saved.Status)
+	}
+}
+
+// countingClient 统计最新区块号与回执查询次数的内存区块链客户端
+type countingClient struct {
+	*mock.Client
+	blockNumbers atomic.Int32
+	receipts     atomic.Int32
+}
+
+func (c *countingClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	c.blockNumbers.Add(1)
+	return c.Client.GetBlockNumber(ctx)
+}
+
+func (c *countingClient) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	c.receipts.Add(1)
+	return c.Client.GetTransactionReceipt(ctx, txHash)
+}
+
+func TestBatchMonitorConfirmsAllPendingInOneCycle(t *testing.T) {
+	const n = 12
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(100))
+
+	hashes := make([]string, n)
+	for i := range hashes {
+		tx, err := env.txs.SendTransaction(ctx, user.ID, &models.TransactionCreateRequest{
+			FromAddress: wallet.Address,
+			ToAddress:   recipient,
+			Amount:      ether(1).String(),
+			ChainID:     testutil.ChainID,
+		})
+		if err != nil {
+			t.Fatalf("send #%d: %v", i+1, err)
+		}
+		hashes[i] = tx.TxHash
+		env.chain.SetReceipt(tx.TxHash, types.ReceiptStatusSuccessful, 100, 21000)
+	}
+	env.chain.SetBlockNumber(100)
+
+	// 一轮检查确认全部交易，最新区块号只查询一次
+	client := &countingClient{Client: env.chain}
+	monitor := NewTransactionService(env.txRepo, env.walletRepo, env.wallets, blockchain.NewClients(client), env.events, env.contacts, env.whitelist, env.limits)
+	monitor.BatchMonitor(ctx, testutil.ChainID, 4)
+
+	for _, hash := range hashes {
+		saved, err := env.txRepo.GetByTxHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("load transaction: %v", err)
+		}
+		if saved.Status != models.TxStatusSuccess {
+			t.Errorf("%s status = %s, want success", hash, saved.Status)
+		}
+	}
+	if calls := client.blockNumbers.Load(); calls != 1 {
+		t.Errorf("block number queries = %d, want 1", calls)
+	}
+	if calls := client.receipts.Load(); calls != n {
+		t.Errorf("receipt queries = %d, want %d", calls, n)
+	}
+}
+
+func TestSendTransactionContact(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	other := env.createUser(t)
+	wallet := env.createWallet(t, user.ID, ether(10))
+
+	newContact := func(userID uint, chainID int) *models.Contact {
+		contact := &models.Contact{UserID: userID, Name: "friend", Address: recipient, ChainID: chainID}
+		if err := env.contactRepo.Create(context.Background(), contact); err != nil {
+			t.Fatalf("create contact: %v", err)
+		}
+		return contact
+	}
+	own := newContact(user.ID, testutil.ChainID)
+	foreign := newContact(other.ID, testutil.ChainID)
+	mainnet := newContact(user.ID, 1)
+
+	tests := []struct {
+		name      string
+		toAddress string
+		contactID uint
+		wantErr   error
+	}{
+		{name: "own contact", contactID: own.ID},
+		{name: "to_address and contact_id", toAddress: recipient, contactID: own.ID, wantErr: ErrContactRecipientConflict},
+		{name: "another user's contact", contactID: foreign.ID, wantErr: apperr.ErrNotFound},
+		{name: "contact on another chain", contactID: mainnet.ID, wantErr: ErrContactChainMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := len(env.chain.SentTransactions())
+			tx, err := env.txs.SendTransaction(context.Background(), user.ID, &models.TransactionCreateRequest{
+				FromAddress: wallet.Address,
+				ToAddress:   tt.toAddress,
+				ContactID:   tt.contactID,
+				Amount:      "1000",
+				ChainID:     testutil.ChainID,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(env.chain.SentTransactions()) != sent {
+					t.Error("transaction broadcast despite the error")
+				}
+				return
+			}
+			if !strings.EqualFold(tx.ToAddress, recipient) {
+				t.Errorf("to_address = %s, want contact address %s", tx.ToAddress, recipient)
+			}
+			// 广播的交易发往联系人地址
+			broadcast := env.chain.SentTransactions()
+			if len(broadcast) != sent+1 || !strings.EqualFold(broadcast[sent].To().Hex(), recipient) {
+				t.Errorf("broadcast recipient is not the contact address")
+			}
+		})
+	}
+}
+
+func TestListTransactionsPaging(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	env.createWallet(t, user.ID, ether(0))
+
+	// gRPC等不经过binding校验的调用同样拒绝超过上限的每页数量
+	_, err := env.txs.ListTransactions(ctx, user.ID, &models.TransactionListRequest{PageSize: models.MaxPageSize + 1})
+	if !errors.Is(err, apperr.ErrInvalid) {
+		t.Fatalf("page_size %d err = %v, want invalid", models.MaxPageSize+1, err)
+	}
+
+	resp, err := env.txs.ListTransactions(ctx, user.ID, &models.TransactionListRequest{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if resp.Page != models.DefaultPage || resp.PageSize != models.DefaultPageSize || resp.TotalPages != 0 || resp.HasNext {
+		t.Errorf("page = %d, page_size = %d, total_pages = %d, has_next = %t, want defaults with no pages", resp.Page, resp.PageSize, resp.TotalPages, resp.HasNext)
+	}
+}
+
+func TestSendTransactionFees(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+	history := &ethereum.FeeHistory{
+		BaseFee: []*big.Int{gwei(9), gwei(10)},
+		Reward:  [][]*big.Int{{gwei(1), gwei(2), gwei(3)}},
+	}
+
+	tests := []struct {
+		name       string
+		feeHistory *ethereum.FeeHistory
+		wantType   uint8
+		wantFeeCap *big.Int
+		wantTipCap *big.Int
+	}{
+		// 小费 = standard档位（基础费用10 + 小费中位数2）− 基础费用，最高费用 = 2 × 10 + 2
+		{name: "eip1559", feeHistory: history, wantType: types.DynamicFeeTxType, wantFeeCap: gwei(22), wantTipCap: gwei(2)},
+		// 节点不支持eth_feeHistory时按eth_gasPrice构建legacy交易
+		{name: "fee history unavailable", wantType: types.LegacyTxType, wantFeeCap: gwei(1), wantTipCap: gwei(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.chain.SetFeeHistory(tt.feeHistory)
+			env.txs.SetGasOracle(NewGasOracle(env.cache, 1, time.Minute, env.chain))
+			user := env.createUser(t)
+			wallet := env.createWallet(t, user.ID, ether(10))
+
+			tx, err := env.txs.SendTransaction(context.Background(), user.ID, &models.TransactionCreateRequest{
+				FromAddress: wallet.Address,
+				ToAddress:   recipient,
+				Amount:      ether(1).String(),
+				ChainID:     testutil.ChainID,
+			})
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+
+			sent := env.chain.SentTransactions()
+			if len(sent) != 1 {
+				t.Fatalf("broadcast transactions = %d, want 1", len(sent))
+			}
+			if sent[0].Type() != tt.wantType {
+				t.Errorf("type = %d, want %d", sent[0].Type(), tt.wantType)
+			}
+			if sent[0].GasFeeCap().Cmp(tt.wantFeeCap) != 0 || sent[0].GasTipCap().Cmp(tt.wantTipCap) != 0 {
+				t.Errorf("fee cap = %s tip cap = %s, want %s and %s", sent[0].GasFeeCap(), sent[0].GasTipCap(), tt.wantFeeCap, tt.wantTipCap)
+			}
+			// 记录的gas_price为每单位gas的最高费用
+			if tx.GasPrice != tt.wantFeeCap.String() {
+				t.Errorf("gas_price = %s, want %s", tx.GasPrice, tt.wantFeeCap)
+			}
+		})
+	}
+}
+
+// assertTransactionCount 校验交易记录数
+func assertTransactionCount(t *testing.T, env *testEnv, want int64) {
+	t.Helper()
+	var count int64
+	if err := env.db.Model(&models.Transaction{}).Count(&count).Error; err != nil {
+		t.Fatalf("count transactions: %v", err)
+	}
+	if count != want {
+		t.Errorf("transactions = %d, want %d", count, want)
+	}
+}
+
+// assertOutbox 校验发件箱事件数量与投递状态
+func assertOutbox(t *testing.T, env *testEnv, want int, sent bool) {
+	t.Helper()
+	var events []models.OutboxEvent
+	if err := env.db.Find(&events).Error; err != nil {
+		t.Fatalf("load outbox events: %v", err)
+	}
+	if len(events) != want {
+		t.Fatalf("outbox events = %d, want %d", len(events), want)
+	}
+	for _, event := range events {
+		if (event.SentAt != nil) != sent {
+			t.Errorf("event %d sent_at = %v, want sent=%v", event.ID, event.SentAt, sent)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
+)
+
+// SimulateTransaction 模拟发送交易：按发送交易的规则构建，合约调用以钱包为发送方通过eth_call在最新区块执行并估算gas，
+// 普通转账只校验地址、金额与余额；不签名、不保存任何状态，也不占用每日限额
+func (s *TransactionService) SimulateTransaction(ctx context.Context, userID uint, req *models.TransactionSimulateRequest) (*models.TransactionSimulateResponse, error) {
+	// 1. 验证发送方钱包转账权限
+	wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, req.FromAddress, PermSend)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.ChainID != req.ChainID {
+		return nil, ErrChainIDMismatch
+	}
+
+	// 2. 解析并校验收款地址与金额（与发送交易相同）
+	toAddress, _, err := resolveRecipient(ctx, s.ensService, req.ChainID, req.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRecipient(wallet.Address, toAddress); err != nil {
+		return nil, err
+	}
+	amount := new(big.Int)
+	if req.Method == "" || req.Amount != "" {
+		if amount, err = parseAmount(req.Amount, req.AmountUnit, req.Method != ""); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := s.client(wallet.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 合约调用：编码calldata并在最新区块上执行
+	resp := &models.TransactionSimulateResponse{}
+	var data []byte
+	if req.Method != "" {
+		method, err := blockchain.LoadMethod(req.ABI, req.Method)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+		}
+		if data, err = blockchain.EncodeCall(method, req.Args); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidContractCall, err)
+		}
+
+		result, err := client.SimulateCall(ctx, wallet.Address, toAddress, amount, data)
+		if err != nil {
+			reason, reverted := blockchain.RevertReason(err)
+			if !reverted {
+				return nil, err
+			}
+			resp.Reverted = true
+			resp.RevertReason = reason
+		} else {
+			resp.ReturnData = hexutil.Encode(result)
+		}
+	}
+
+	// 4. 确定gas价格与gas limit（执行回滚时无法估算，未指定gas limit时为0）
+	params := s.paramsFor(wallet.ChainID)
+	gasPrice, _, err := s.fees(ctx, wallet.ChainID, req.Speed)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		gasLimit = int64(params.DefaultGasLimit(data))
+	}
+	if gasLimit == 0 && !resp.Reverted {
+		estimated, err := client.EstimateGas(ctx, wallet.Address, toAddress, amount, data)
+		if err != nil {
+			reason, reverted := blockchain.RevertReason(err)
+			if !reverted {
+				return nil, err
+			}
+			resp.Reverted = true
+			resp.RevertReason = reason
+		}
+		gasLimit = int64(estimated)
+	}
+
+	// 5. 校验余额（扣除已内部转出、尚未在链上结算的金额）
+	balance, err := s.walletService.GetBalance(ctx, userID, wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+	balance = spendableOnChain(wallet, balance)
+
+	fee := new(big.Int).Mul(gasPrice, big.NewInt(gasLimit))
+	totalCost := new(big.Int).Add(amount, fee)
+
+	resp.GasLimit = gasLimit
+	resp.GasPrice = gasPrice.String()
+	resp.EstimatedFee = fee.String()
+	resp.TotalCost = totalCost.String()
+	resp.Balance = balance.String()
+	resp.SufficientBalance = balance.Cmp(totalCost) >= 0
+	resp.Success = !resp.Reverted && resp.SufficientBalance
+	return resp, nil
+}
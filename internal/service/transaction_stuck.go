@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// 未最终确认交易的检查结果（metrics.TransactionChecks的outcome标签）
+const (
+	checkIncluded = "included"  // 已打包
+	checkMempool  = "mempool"   // 仍在节点交易池中等待打包
+	checkUnknown  = "unknown"   // 节点不知道该交易
+	checkRPCError = "rpc_error" // 查询回执或交易失败
+)
+
+// isStuck 交易创建超过StuckAfter仍为pending（未打包）
+func (s *TransactionService) isStuck(tx *models.Transaction) bool {
+	return tx.Status == models.TxStatusPending && time.Since(tx.CreatedAt) >= s.schedule.StuckAfter
+}
+
+// handleStuck 处理卡住的pending交易，返回交易是否已标记为dropped
+//
+// 发送方nonce已被消耗且本交易仍无回执时，该nonce已由其他交易（加速、取消或在其他客户端发送的交易）使用，
+// 本交易不会再上链，标记为dropped并释放占用的限额；nonce尚未被消耗时保持pending，推送一次卡住提醒，建议用户加速或取消。
+func (s *TransactionService) handleStuck(ctx context.Context, tx *models.Transaction, confirmedNonce uint64) bool {
+	if confirmedNonce <= tx.Nonce {
+		s.notifyStuck(ctx, tx)
+		return false
+	}
+
+	// 查询nonce前交易可能刚被打包（nonce由本交易消耗），再次确认没有回执
+	client, err := s.client(tx.ChainID)
+	if err != nil {
+		return false
+	}
+	if _, err := client.GetTransactionReceipt(ctx, tx.TxHash); !errors.Is(err, ethereum.NotFound) {
+		if err != nil {
+			logger.WithCtx(ctx).Warn("failed to verify dropped transaction", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+		}
+		return false
+	}
+	return s.dropTransaction(ctx, tx, fmt.Sprintf("not mined after %s and nonce %d was used by another transaction (account nonce is now %d)",
+		s.schedule.StuckAfter, tx.Nonce, confirmedNonce))
+}
+
+// checkUnmined 查询没有回执的pending交易在节点上的状态并按结果计数：节点不知道该交易时等待宽限期后标记为dropped，
+// 查询失败时退避；返回true表示交易仍在交易池中（或刚被打包），由调用方继续处理卡住提醒与下次检查
+func (s *TransactionService) checkUnmined(ctx context.Context, tx *models.Transaction) bool {
+	client, err := s.client(tx.ChainID)
+	if err != nil {
+		s.checkFailed(ctx, tx, "failed to look up unmined transaction", err)
+		return false
+	}
+	_, isPending, err := client.GetTransactionByHash(ctx, tx.TxHash)
+	switch {
+	case err == nil:
+		outcome := checkMempool
+		if !isPending {
+			// 查询回执后刚被打包，下次检查时处理回执
+			outcome = checkIncluded
+		}
+		metrics.TransactionChecks.WithLabelValues(outcome).Inc()
+		return true
+	case errors.Is(err, ethereum.NotFound):
+		metrics.TransactionChecks.WithLabelValues(checkUnknown).Inc()
+		s.handleUnknown(ctx, tx)
+		return false
+	default:
+		s.checkFailed(ctx, tx, "failed to look up unmined transaction", err)
+		return false
+	}
+}
+
+// handleUnknown 处理节点不知道的pending交易（广播后未传播到当前节点，或已被移出交易池）
+//
+// 宽限期内可能仍在向当前节点传播（如广播与查询经过不同的故障转移节点），继续等待；超过宽限期时交易已不会上链，
+// 标记为dropped并释放占用的限额，其nonce由发送方的下一笔交易使用。
+func (s *TransactionService) handleUnknown(ctx context.Context, tx *models.Transaction) {
+	age := time.Since(tx.CreatedAt)
+	if age >= s.schedule.UnknownGrace {
+		if s.dropTransaction(ctx, tx, fmt.Sprintf("not known to the node %s after it was sent (never propagated or evicted from the mempool)",
+			age.Truncate(time.Second))) {
+			return
+		}
+	} else {
+		logger.WithCtx(ctx).Warn("transaction not known to the node",
+			zap.String("tx_hash", tx.TxHash),
+			zap.String("from", tx.FromAddress),
+			zap.Uint64("nonce", tx.Nonce),
+			zap.Duration("age", age),
+		)
+	}
+	s.scheduleNextCheck(ctx, tx, s.nextCheckDelay(tx))
+}
+
+// checkFailed 记录回执或交易查询失败并按连续失败次数退避
+func (s *TransactionService) checkFailed(ctx context.Context, tx *models.Transaction, msg string, err error) {
+	metrics.TransactionChecks.WithLabelValues(checkRPCError).Inc()
+	logger.WithCtx(ctx).Warn(msg,
+		zap.String("tx_hash", tx.TxHash),
+		zap.Int("consecutive_failures", tx.CheckFailures+1),
+		zap.Error(err),
+	)
+	s.scheduleRetry(ctx, tx)
+}
+
+// dropTransaction 在同一事务中标记为dropped、释放限额并标记发送方余额待刷新，提交后推送事件并刷新余额
+func (s *TransactionService) dropTransaction(ctx context.Context, tx *models.Transaction, message string) bool {
+	dropped := false
+	err := s.txRepo.WithTx(ctx, func(txRepo *repository.TransactionRepository, walletRepo *repository.WalletRepository) error {
+		var err error
+		dropped, err = txRepo.MarkDropped(ctx, tx.TxHash, message)
+		if err != nil || !dropped {
+			return err
+		}
+		return markConfirmedBalancesStale(ctx, walletRepo, tx, models.TxStatusDropped)
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to mark transaction dropped", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+		return false
+	}
+	if !dropped {
+		return false
+	}
+
+	logger.WithCtx(ctx).Warn("transaction dropped",
+		zap.String("tx_hash", tx.TxHash),
+		zap.String("from", tx.FromAddress),
+		zap.Uint64("nonce", tx.Nonce),
+		zap.String("reason", message),
+	)
+	metrics.TransactionsConfirmed.WithLabelValues(string(models.TxStatusDropped)).Inc()
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:        models.EventTransactionDropped,
+		Address:     tx.FromAddress,
+		TxHash:      tx.TxHash,
+		ExplorerURL: models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		Status:      models.TxStatusDropped,
+		Message:     message,
+	})
+	s.refreshConfirmedBalances(ctx, tx, models.TxStatusDropped)
+	return true
+}
+
+// notifyStuck 推送卡住提醒（每笔交易只推送一次）
+func (s *TransactionService) notifyStuck(ctx context.Context, tx *models.Transaction) {
+	if tx.StuckNotifiedAt != nil {
+		return
+	}
+	notified, err := s.txRepo.MarkStuckNotified(ctx, tx.ID)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to mark stuck transaction notified", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+		return
+	}
+	if !notified {
+		return
+	}
+
+	logger.WithCtx(ctx).Warn("transaction stuck",
+		zap.String("tx_hash", tx.TxHash),
+		zap.String("from", tx.FromAddress),
+		zap.Uint64("nonce", tx.Nonce),
+		zap.Duration("age", time.Since(tx.CreatedAt)),
+	)
+	s.publishTransactionEvent(ctx, &models.WalletEvent{
+		Type:        models.EventTransactionStuck,
+		Address:     tx.FromAddress,
+		TxHash:      tx.TxHash,
+		ExplorerURL: models.ExplorerTxURL(tx.ChainID, tx.TxHash),
+		Status:      models.TxStatusPending,
+		Message: fmt.Sprintf("not mined after %s; speed it up by resending nonce %d with a higher gas price, or cancel it with a zero-value transfer to yourself using the same nonce",
+			s.schedule.StuckAfter, tx.Nonce),
+	})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/blockchain/mock"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// spendCheckingClient 广播时记录发送钱包的每日限额占用（校验广播前已关联交易哈希）
+type spendCheckingClient struct {
+	*mock.Client
+	env       *testEnv
+	broadcast []string
+}
+
+func (c *spendCheckingClient) SendTransaction(ctx context.Context, signedTx *types.Transaction) error {
+	if err := c.env.db.Model(&models.SpendLedgerEntry{}).Pluck("tx_hash", &c.broadcast).Error; err != nil {
+		return err
+	}
+	return c.Client.SendTransaction(ctx, signedTx)
+}
+
+// sendStuckTransaction 在设置每日限额的钱包上发送交易，并将创建时间提前到超过卡住阈值
+func (e *testEnv) sendStuckTransaction(t *testing.T) (*models.Wallet, *models.Transaction) {
+	t.Helper()
+	user := e.createUser(t)
+	wallet := e.createWallet(t, user.ID, ether(10))
+	e.setDailyLimit(t, wallet, ether(5).Int64())
+	tx, err := e.txs.SendTransaction(context.Background(), user.ID, &models.TransactionCreateRequest{
+		FromAddress: wallet.Address,
+		ToAddress:   recipient,
+		Amount:      ether(1).String(),
+		ChainID:     testutil.ChainID,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	createdAt := time.Now().Add(-DefaultMonitorSchedule().StuckAfter - time.Minute)
+	if err := e.db.Model(&models.Transaction{}).Where("id = ?", tx.ID).UpdateColumn("created_at", createdAt).Error; err != nil {
+		t.Fatalf("backdate transaction: %v", err)
+	}
+	return wallet, tx
+}
+
+// spendEntries 钱包的每日限额占用（交易哈希）
+func (e *testEnv) spendEntries(t *testing.T, wallet *models.Wallet) []string {
+	t.Helper()
+	var hashes []string
+	if err := e.db.Model(&models.SpendLedgerEntry{}).Where("wallet_id = ?", wallet.ID).Pluck("tx_hash", &hashes).Error; err != nil {
+		t.Fatalf("load spend ledger: %v", err)
+	}
+	return hashes
+}
+
+func TestStuckTransactionDropped(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	client := &spendCheckingClient{Client: env.chain, env: env}
+	env.txs = NewTransactionService(env.txRepo, env.walletRepo, env.wallets, blockchain.NewClients(client), env.events, env.contacts, env.whitelist, env.limits)
+	wallet, tx := env.sendStuckTransaction(t)
+
+	// 广播前已在保存交易的事务中关联占用的额度
+	if hashes := client.broadcast; len(hashes) != 1 || hashes[0] != tx.TxHash {
+		t.Fatalf("spend ledger at broadcast = %v, want [%s]", hashes, tx.TxHash)
+	}
+
+	// 没有回执且nonce已被其他交易使用
+	env.chain.SetConfirmedNonce(wallet.Address, tx.Nonce+1)
+	env.chain.SetBlockNumber(100)
+	env.txs.BatchMonitor(ctx, testutil.ChainID, 4)
+
+	saved, err := env.txRepo.GetByTxHash(ctx, tx.TxHash)
+	if err != nil {
+		t.Fatalf("load transaction: %v", err)
+	}
+	if saved.Status != models.TxStatusDropped || saved.ErrorMsg == "" {
+		t.Errorf("status = %s (%q), want dropped with a reason", saved.Status, saved.ErrorMsg)
+	}
+	if hashes := env.spendEntries(t, wallet); len(hashes) != 0 {
+		t.Errorf("spend ledger after drop = %v, want released", hashes)
+	}
+
+	// 按dropped状态筛选交易列表
+	for status, want := range map[models.TransactionStatus]int64{models.TxStatusDropped: 1, models.TxStatusPending: 0} {
+		list, err := env.txs.ListTransactions(ctx, wallet.UserID, &models.TransactionListRequest{Status: status, Page: 1, PageSize: 20})
+		if err != nil {
+			t.Fatalf("list %s: %v", status, err)
+		}
+		if list.Total != want {
+			t.Errorf("%s transactions = %d, want %d", status, list.Total, want)
+		}
+	}
+}
+
+func TestStuckTransactionKeptPending(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	wallet, tx := env.sendStuckTransaction(t)
+
+	// nonce尚未被使用：保持pending并记录已提醒，不释放额度
+	env.chain.SetConfirmedNonce(wallet.Address, tx.Nonce)
+	env.chain.SetBlockNumber(100)
+	env.txs.BatchMonitor(ctx, testutil.ChainID, 4)
+
+	saved, err := env.txRepo.GetByTxHash(ctx, tx.TxHash)
+	if err != nil {
+		t.Fatalf("load transaction: %v", err)
+	}
+	if saved.Status != models.TxStatusPending || saved.StuckNotifiedAt == nil {
+		t.Errorf("status = %s, stuck_notified_at = %v; want pending and notified", saved.Status, saved.StuckNotifiedAt)
+	}
+	if hashes := env.spendEntries(t, wallet); len(hashes) != 1 {
+		t.Errorf("spend ledger = %v, want the reservation kept", hashes)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+)
+
+// sweepAttempts 清空余额的最多尝试次数（节点因余额变化拒绝交易时按最新余额重新计算一次）
+const sweepAttempts = 2
+
+// ErrNothingToSweep 余额不足以支付网络费用，没有可转出的金额
+var ErrNothingToSweep = apperr.New("error.nothing_to_sweep", "balance does not cover the network fee")
+
+// SweepWallet 将钱包的全部原生币余额扣除网络费用后转出，广播后钱包余额恰好为0（存在未结算的内部转出时保留该部分）
+func (s *TransactionService) SweepWallet(ctx context.Context, userID uint, address string, req *models.WalletSweepRequest) (*models.Transaction, error) {
+	// 1. 验证发送方钱包转账权限
+	wallet, membership, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, address, PermSend)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 解析并校验收款地址（ENS名称解析为地址，拒绝零地址、自身与未生效的白名单地址）
+	toAddress, ensName, err := resolveRecipient(ctx, s.ensService, wallet.ChainID, req.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRecipient(wallet.Address, toAddress); err != nil {
+		return nil, err
+	}
+	if err := s.whitelistService.CheckRecipient(ctx, wallet, toAddress); err != nil {
+		return nil, err
+	}
+
+	out := outgoingTx{
+		To:         toAddress,
+		ToENSName:  ensName,
+		Speed:      req.Speed,
+		Passphrase: req.Passphrase,
+		Note:       req.Note,
+		Tags:       normalizeTags(req.Tags),
+	}
+
+	// 3. 按最新余额计算金额并广播，节点返回余额不足（计算后余额发生变化）时重新计算一次
+	for attempt := 1; ; attempt++ {
+		tx, err := s.sweepOnce(ctx, userID, wallet, membership, out)
+		if err == nil || attempt >= sweepAttempts || !isInsufficientFunds(err) {
+			return tx, err
+		}
+		logger.WithCtx(ctx).Warn("sweep rejected by node for insufficient funds, retrying with fresh balance",
+			zap.String("address", wallet.Address),
+			zap.Error(err),
+		)
+	}
+}
+
+// sweepOnce 读取链上余额，按固定gas价格与原生币转账gas用量计算金额（余额 − gasLimit × gasPrice）后广播
+func (s *TransactionService) sweepOnce(ctx context.Context, userID uint, wallet *models.Wallet, membership *models.OrgMembership, out outgoingTx) (*models.Transaction, error) {
+	// 1. 清除缓存后读取链上余额（广播前的余额校验读取同一值），已内部转出的金额保留在链上
+	s.walletService.InvalidateBalance(ctx, wallet.Address)
+	balance, err := s.walletService.GetBalance(ctx, userID, wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+	balance = spendableOnChain(wallet, balance)
+
+	// 2. 计算网络费用（链上按gasUsed × gasPrice扣费，原生币转账的gasUsed等于gasLimit）
+	params := s.paramsFor(wallet.ChainID)
+	gasPrice, err := s.gasPrice(ctx, wallet.ChainID, out.Speed)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice = params.ApplyGasPriceFloor(gasPrice)
+	gasLimit := int64(params.NativeGasLimit)
+	fee := new(big.Int).Mul(gasPrice, big.NewInt(gasLimit))
+
+	// 3. 转出金额 = 余额 − 网络费用
+	amount := new(big.Int).Sub(balance, fee)
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: balance is %s wei, fee is %s wei", ErrNothingToSweep, balance.String(), fee.String())
+	}
+	if err := checkMemberSendLimit(membership, amount); err != nil {
+		return nil, err
+	}
+
+	// 清空余额的金额在审批期间会变化，超过审批阈值时不支持审批流程
+	if wallet.RequiresApproval(amount) {
+		return nil, ErrApprovalRequired
+	}
+
+	// 4. 广播交易
+	out.Value = amount
+	out.GasPrice = gasPrice
+	out.GasLimit = gasLimit
+	return s.broadcast(ctx, userID, wallet, &out)
+}
+
+// isInsufficientFunds 节点是否因余额不足拒绝交易（节点错误通过JSON-RPC以文本返回）
+func isInsufficientFunds(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "insufficient funds")
+}
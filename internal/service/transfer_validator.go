@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
+)
+
+var (
+	// ErrZeroAddress 收款地址为零地址（资金将被销毁）
+	ErrZeroAddress = apperr.New("error.zero_address", "cannot send to the zero address")
+	// ErrSelfTransfer 收款地址与发送钱包相同
+	ErrSelfTransfer = apperr.New("error.self_transfer", "cannot send to the sending wallet itself")
+	// ErrInvalidAmount 金额不是合法的Wei整数
+	ErrInvalidAmount = apperr.New("error.invalid_amount", "invalid amount")
+)
+
+// weiPattern Wei金额只允许十进制数字（不接受小数、符号与科学计数法）
+var weiPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// ethPattern 主单位金额只允许十进制数字与可选的小数部分（不接受符号、科学计数法与省略整数部分的".5"）
+var ethPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// validateRecipient 拒绝零地址与向自身转账
+func validateRecipient(from, to string) error {
+	if common.HexToAddress(to) == (common.Address{}) {
+		return ErrZeroAddress
+	}
+	if strings.EqualFold(from, to) {
+		return ErrSelfTransfer
+	}
+	return nil
+}
+
+// parseAmount 按单位解析原生币金额为Wei（未指定单位时按Wei解析）
+func parseAmount(value string, unit models.AmountUnit, allowZero bool) (*big.Int, error) {
+	switch unit {
+	case "", models.AmountUnitWei:
+		return parseWeiAmount(value, allowZero)
+	case models.AmountUnitEth:
+		return parseEthAmount(value, allowZero)
+	default:
+		return nil, fmt.Errorf("%w: unsupported amount_unit %q (wei or eth)", ErrInvalidAmount, unit)
+	}
+}
+
+// parseEthAmount 将主单位十进制金额（如"0.5"）精确换算为Wei（不经过浮点数），超过18位小数时拒绝而不是截断
+func parseEthAmount(value string, allowZero bool) (*big.Int, error) {
+	if !ethPattern.MatchString(value) {
+		return nil, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+	if _, fraction, _ := strings.Cut(value, "."); len(fraction) > utils.EtherDecimals {
+		return nil, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, value, utils.EtherDecimals)
+	}
+	amount, ok := utils.EthToWei(value)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q could not be converted to wei", ErrInvalidAmount, value)
+	}
+	if err := checkAmountRange(value, amount); err != nil {
+		return nil, err
+	}
+	if !allowZero && amount.Sign() == 0 {
+		return nil, fmt.Errorf("%w: must be greater than 0 (parsed as %s wei)", ErrInvalidAmount, amount.String())
+	}
+	return amount, nil
+}
+
+// parseWeiAmount 解析Wei金额，失败时错误信息中包含服务端解析到的值
+func parseWeiAmount(value string, allowZero bool) (*big.Int, error) {
+	if !weiPattern.MatchString(value) {
+		return nil, fmt.Errorf("%w: %q is not a whole number of wei (decimal digits only; set amount_unit to \"eth\" for decimal amounts)", ErrInvalidAmount, value)
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q could not be parsed as wei", ErrInvalidAmount, value)
+	}
+	if err := checkAmountRange(value, amount); err != nil {
+		return nil, err
+	}
+	if !allowZero && amount.Sign() == 0 {
+		return nil, fmt.Errorf("%w: must be greater than 0 (parsed as %s wei)", ErrInvalidAmount, amount.String())
+	}
+	return amount, nil
+}
+
+// checkAmountRange 拒绝超过uint256的金额（交易value的上限，numeric(78,0)列虽能存下但无法签名）
+func checkAmountRange(value string, amount *big.Int) error {
+	if amount.Cmp(math.MaxBig256) > 0 {
+		return fmt.Errorf("%w: %q exceeds the maximum uint256 value", ErrInvalidAmount, value)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+)
+
+func TestParseAmountLargeValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		unit    models.AmountUnit
+		want    string
+		wantErr bool
+	}{
+		{name: "2^64 wei", value: "18446744073709551616", want: "18446744073709551616"},
+		{name: "2^64 + 1 wei", value: "18446744073709551617", want: "18446744073709551617"},
+		{name: "max uint256 wei", value: "115792089237316195423570985008687907853269984665640564039457584007913129639935", want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{name: "max uint256 in eth", value: "115792089237316195423570985008687907853269984665640564039457.584007913129639935", unit: models.AmountUnitEth, want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{name: "max uint256 + 1 wei", value: "115792089237316195423570985008687907853269984665640564039457584007913129639936", wantErr: true},
+		{name: "max decimal(78,0) wei", value: "999999999999999999999999999999999999999999999999999999999999999999999999999999", wantErr: true},
+		{name: "max decimal(78,0) in eth", value: "999999999999999999999999999999999999999999999999999999999999.999999999999999999", unit: models.AmountUnitEth, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := parseAmount(tt.value, tt.unit, false)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("err = %v, want ErrInvalidAmount", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if amount.String() != tt.want {
+				t.Errorf("amount = %s, want %s", amount, tt.want)
+			}
+		})
+	}
+}
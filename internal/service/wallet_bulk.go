@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/utils"
+)
+
+// ErrWalletQuotaExceeded 超出每个用户的钱包数量上限
+var ErrWalletQuotaExceeded = apperr.Forbidden("error.wallet_quota_exceeded", "wallet quota exceeded")
+
+// defaultBulkConcurrency 批量创建钱包时默认同时生成与加密私钥的协程数
+const defaultBulkConcurrency = 8
+
+// BulkCreateWallets 批量创建钱包（交易所等批量生成充值地址）：有限并发生成并加密私钥，在同一事务中批量写入
+//
+// 批量创建的钱包不设置私钥口令、不领取测试链水龙头，也不刷新链上余额（新生成的地址余额为0）；整批记录一条审计日志。
+func (s *WalletService) BulkCreateWallets(ctx context.Context, userID uint, req *models.WalletBulkCreateRequest, ip string) (*models.WalletBulkCreateResponse, error) {
+	// 1. 校验链已启用、组织权限与钱包数量上限
+	if err := checkChainEnabled(req.ChainID); err != nil {
+		return nil, err
+	}
+	orgID, err := s.walletOrg(ctx, userID, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWalletQuota(ctx, userID, req.Count); err != nil {
+		return nil, err
+	}
+
+	// 2. 有限并发生成并加密私钥
+	wallets, err := s.generateWallets(ctx, req.ChainID, req.Count, func(i int, address, encrypted string) *models.Wallet {
+		wallet := &models.Wallet{
+			UserID:              userID,
+			OrgID:               orgID,
+			Address:             address,
+			ChainID:             req.ChainID,
+			Balance:             "0",
+			PrivateKeyEncrypted: encrypted,
+		}
+		if req.NamePrefix != "" {
+			wallet.Name = fmt.Sprintf("%s-%d", req.NamePrefix, i+1)
+		}
+		return wallet
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 在同一事务中批量写入
+	if err := s.walletRepo.CreateBatch(ctx, wallets); err != nil {
+		return nil, err
+	}
+
+	// 4. 整批记录一条审计日志
+	addresses := make([]string, len(wallets))
+	for i, wallet := range wallets {
+		addresses[i] = wallet.Address
+	}
+	logger.WithCtx(ctx).Info("wallets bulk created",
+		zap.Uint("user_id", userID),
+		zap.Uintp("org_id", orgID),
+		zap.Int("chain_id", req.ChainID),
+		zap.Int("count", len(wallets)),
+		zap.String("first_address", addresses[0]),
+		zap.String("last_address", addresses[len(addresses)-1]),
+		zap.String("ip", ip),
+	)
+
+	return &models.WalletBulkCreateResponse{
+		ChainID:   req.ChainID,
+		Addresses: addresses,
+	}, nil
+}
+
+// generateWallets 以bulkConcurrency个协程用chainID链的客户端生成count个私钥并用服务端密钥加密，build按序号构建钱包（任一失败时返回第一个错误）
+func (s *WalletService) generateWallets(ctx context.Context, chainID, count int, build func(i int, address, encrypted string) *models.Wallet) ([]*models.Wallet, error) {
+	client, err := chainClient(s.chains, chainID)
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]*models.Wallet, count)
+	sem := make(chan struct{}, s.bulkConcurrency)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() { firstErr = err })
+	}
+
+	for i := 0; i < count; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			address, privateKey, err := client.CreateWallet()
+			if err != nil {
+				fail(err)
+				return
+			}
+			encrypted, err := utils.EncryptAES(hex.EncodeToString(crypto.FromECDSA(privateKey)), s.encryptionKey)
+			if err != nil {
+				fail(err)
+				return
+			}
+			wallets[i] = build(i, address, encrypted)
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return wallets, nil
+}
+
+// checkWalletQuota 校验用户再创建n个钱包后不超过数量上限（并发创建时可能短暂超出上限）
+func (s *WalletService) checkWalletQuota(ctx context.Context, userID uint, n int) error {
+	if s.maxWallets <= 0 {
+		return nil
+	}
+	count, err := s.walletRepo.Count(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count+int64(n) > int64(s.maxWallets) {
+		return fmt.Errorf("%w: %d of %d wallets used, %d requested", ErrWalletQuotaExceeded, count, s.maxWallets, n)
+	}
+	return nil
+}
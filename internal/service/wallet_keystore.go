@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// ErrInvalidKeystore keystore格式错误或口令不正确（两种情况不做区分）
+var ErrInvalidKeystore = apperr.New("error.invalid_keystore", "invalid keystore or keystore passphrase")
+
+// keystoreFormat 私钥备份格式（记录在钱包动态中）
+const keystoreFormat = "keystore"
+
+// 导出keystore使用的scrypt参数（geth标准强度；测试中调低以加快加解密）
+var (
+	keystoreScryptN = keystore.StandardScryptN
+	keystoreScryptP = keystore.StandardScryptP
+)
+
+// 导入keystore允许的最大KDF参数（解密开销由文件决定，防止构造的超大参数耗尽内存或CPU）
+//
+// scrypt的计算量按N·p限制为geth标准强度，p单独设上限，geth轻量参数（N=4096、p=6）可以导入。
+const (
+	maxKeystoreScryptN    = keystore.StandardScryptN
+	maxKeystoreScryptR    = 8
+	maxKeystoreScryptP    = 16
+	maxKeystoreScryptWork = keystore.StandardScryptN * keystore.StandardScryptP
+	maxKeystorePBKDF2Itr  = 1 << 20
+)
+
+// ExportKeystore 将钱包私钥导出为Web3 Secret Storage格式的keystore JSON（scrypt加密，可导入geth、MetaMask）
+// 导出前重新校验登录密码，导出记录写入钱包动态、审计日志并通知用户
+func (s *WalletService) ExportKeystore(ctx context.Context, userID uint, address string, req *models.WalletKeystoreExportRequest, ip string) (*models.WalletKeystoreResponse, error) {
+	log := logger.WithCtx(ctx).With(zap.Uint("user_id", userID), zap.String("address", address))
+
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 重新校验登录密码
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CheckPassword(req.Password) {
+		log.Warn("wallet key export rejected: incorrect password", zap.String("ip", ip))
+		return nil, ErrInvalidPassword
+	}
+
+	// 3. 解密私钥（口令保护的钱包需要钱包口令）
+	privateKey, err := s.GetPrivateKey(ctx, wallet.Address, req.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 以用户选择的口令生成keystore
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	keyJSON, err := keystore.EncryptKey(key, req.KeystorePassphrase, keystoreScryptN, keystoreScryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 审计日志、钱包动态与通知
+	log.Info("wallet key exported", zap.String("format", keystoreFormat), zap.String("ip", ip))
+	s.activityService.Record(ctx, wallet.ID, models.ActivityKeyExported, &models.KeyExportedDetails{
+		Format: keystoreFormat,
+		UserID: userID,
+		IP:     ip,
+	})
+	if err := s.eventService.Publish(ctx, &models.WalletEvent{
+		Type:    models.EventKeyExported,
+		Address: wallet.Address,
+		UserID:  userID,
+		Message: fmt.Sprintf("A keystore backup of wallet %s was exported from IP %s", wallet.Address, ip),
+	}); err != nil {
+		log.Warn("failed to publish key exported event", zap.Error(err))
+	}
+
+	return &models.WalletKeystoreResponse{
+		Address:  wallet.Address,
+		Filename: keystoreFilename(key.Address.Hex(), time.Now()),
+		Keystore: keyJSON,
+	}, nil
+}
+
+// ImportWallet 导入已有私钥创建钱包（十六进制私钥或keystore JSON加口令）
+func (s *WalletService) ImportWallet(ctx context.Context, userID uint, req *models.WalletImportRequest, ip string) (*models.Wallet, error) {
+	// 1. 校验链已启用与组织权限
+	if err := checkChainEnabled(req.ChainID); err != nil {
+		return nil, err
+	}
+	orgID, err := s.walletOrg(ctx, userID, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 解析私钥
+	privateKey, err := importPrivateKey(req)
+	if err != nil {
+		return nil, err
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+
+	// 3. 同一地址只能收录一次，且不超过钱包数量上限
+	if err := s.checkImportAddress(ctx, userID, address); err != nil {
+		return nil, err
+	}
+	if err := s.checkWalletQuota(ctx, userID, 1); err != nil {
+		return nil, err
+	}
+
+	// 4. 加密私钥并保存
+	wallet := &models.Wallet{
+		UserID:  userID,
+		OrgID:   orgID,
+		Address: address,
+		ChainID: req.ChainID,
+		Balance: "0",
+		Name:    req.Name,
+	}
+	if err := s.saveWallet(ctx, wallet, privateKey, req.Passphrase, nil); err != nil {
+		if errors.Is(err, repository.ErrDuplicateAddress) {
+			// 并发导入同一地址（如客户端重试的请求）：按已收录钱包的归属返回
+			if err := s.checkImportAddress(ctx, userID, address); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	logger.WithCtx(ctx).Info("wallet imported",
+		zap.Uint("user_id", userID),
+		zap.String("address", wallet.Address),
+		zap.Bool("keystore", len(req.Keystore) > 0),
+		zap.String("ip", ip),
+	)
+	return wallet, nil
+}
+
+// checkImportAddress 校验导入的地址尚未被收录：已是当前用户的钱包时返回WalletAlreadyImportedError（附带已有钱包），
+// 属于其他账户时返回不区分归属的ErrDuplicateAddress
+func (s *WalletService) checkImportAddress(ctx context.Context, userID uint, address string) error {
+	existing, err := s.walletRepo.GetByAddress(ctx, address)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.UserID == userID {
+		return &WalletAlreadyImportedError{Wallet: existing}
+	}
+	return repository.ErrDuplicateAddress
+}
+
+// importPrivateKey 从导入请求中解析私钥（private_key与keystore二选一）
+func importPrivateKey(req *models.WalletImportRequest) (*ecdsa.PrivateKey, error) {
+	hasKeystore := len(req.Keystore) > 0 && string(req.Keystore) != "null"
+	switch {
+	case hasKeystore && req.PrivateKey != "":
+		return nil, apperr.Invalid("error.import_key_ambiguous", "provide either private_key or keystore, not both")
+	case hasKeystore:
+		return decryptKeystore(req.Keystore, req.KeystorePassphrase)
+	default:
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(req.PrivateKey, "0x"))
+		if err != nil {
+			return nil, apperr.Invalid("error.invalid_private_key", "private_key must be a 32-byte hex string")
+		}
+		return privateKey, nil
+	}
+}
+
+// decryptKeystore 校验KDF参数后解密keystore JSON
+func decryptKeystore(keyJSON []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	if err := checkKeystoreCost(keyJSON); err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// checkKeystoreCost 拒绝KDF参数超过geth标准强度的keystore（DecryptKey按文件中的参数执行派生）
+func checkKeystoreCost(keyJSON []byte) error {
+	var parsed struct {
+		Crypto struct {
+			KDF       string         `json:"kdf"`
+			KDFParams map[string]any `json:"kdfparams"`
+		} `json:"crypto"`
+	}
+	if err := json.Unmarshal(keyJSON, &parsed); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	param := func(name string) float64 {
+		value, _ := parsed.Crypto.KDFParams[name].(float64)
+		return value
+	}
+	switch parsed.Crypto.KDF {
+	case "scrypt":
+		if param("n") > maxKeystoreScryptN || param("r") > maxKeystoreScryptR || param("p") > maxKeystoreScryptP ||
+			param("n")*param("p") > maxKeystoreScryptWork {
+			return fmt.Errorf("%w: scrypt parameters exceed the supported maximum", ErrInvalidKeystore)
+		}
+	case "pbkdf2":
+		if param("c") > maxKeystorePBKDF2Itr {
+			return fmt.Errorf("%w: pbkdf2 iteration count exceeds the supported maximum", ErrInvalidKeystore)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported kdf %q", ErrInvalidKeystore, parsed.Crypto.KDF)
+	}
+	return nil
+}
+
+// keystoreFilename geth keystore目录使用的文件名（UTC--<ISO8601时间>--<小写地址>）
+func keystoreFilename(address string, t time.Time) string {
+	return fmt.Sprintf("UTC--%s--%s", t.UTC().Format("2006-01-02T15-04-05.000000000Z"), strings.ToLower(strings.TrimPrefix(address, "0x")))
+}
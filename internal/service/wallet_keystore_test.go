@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/testutil"
+)
+
+// lightKeystoreCost 测试期间以geth的轻量scrypt参数导出keystore
+func lightKeystoreCost(t *testing.T) {
+	n, p := keystoreScryptN, keystoreScryptP
+	keystoreScryptN, keystoreScryptP = keystore.LightScryptN, keystore.LightScryptP
+	t.Cleanup(func() { keystoreScryptN, keystoreScryptP = n, p })
+}
+
+func TestKeystoreExportImportRoundTrip(t *testing.T) {
+	lightKeystoreCost(t)
+	ctx := context.Background()
+	env := newTestEnv(t)
+	user := env.createUser(t)
+	if err := user.SetPassword("login-password"); err != nil {
+		t.Fatalf("set password: %v", err)
+	}
+	if err := env.db.Model(user).Update("password_hash", user.Password).Error; err != nil {
+		t.Fatalf("save password: %v", err)
+	}
+	wallet := env.createWallet(t, user.ID, ether(0))
+
+	if _, err := env.wallets.ExportKeystore(ctx, user.ID, wallet.Address, &models.WalletKeystoreExportRequest{
+		Password:           "wrong-password",
+		KeystorePassphrase: "keystore-passphrase",
+	}, "127.0.0.1"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("export with wrong password err = %v, want ErrInvalidPassword", err)
+	}
+
+	exported, err := env.wallets.ExportKeystore(ctx, user.ID, wallet.Address, &models.WalletKeystoreExportRequest{
+		Password:           "login-password",
+		KeystorePassphrase: "keystore-passphrase",
+	}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// 导出的文件可由go-ethereum直接解密，得到钱包的私钥
+	key, err := keystore.DecryptKey(exported.Keystore, "keystore-passphrase")
+	if err != nil {
+		t.Fatalf("decrypt exported keystore: %v", err)
+	}
+	privateKey, err := env.wallets.GetPrivateKey(ctx, wallet.Address, "")
+	if err != nil {
+		t.Fatalf("get private key: %v", err)
+	}
+	if key.Address.Hex() != wallet.Address || !key.PrivateKey.Equal(privateKey) {
+		t.Fatalf("decrypted key for %s, want the private key of %s", key.Address.Hex(), wallet.Address)
+	}
+
+	// 导入到另一个实例得到同一地址与私钥
+	other := newTestEnv(t)
+	owner := other.createUser(t)
+	if _, err := other.wallets.ImportWallet(ctx, owner.ID, &models.WalletImportRequest{
+		ChainID:            testutil.ChainID,
+		Keystore:           exported.Keystore,
+		KeystorePassphrase: "wrong-passphrase",
+	}, "127.0.0.1"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("import with wrong passphrase err = %v, want ErrInvalidKeystore", err)
+	}
+	imported, err := other.wallets.ImportWallet(ctx, owner.ID, &models.WalletImportRequest{
+		ChainID:            testutil.ChainID,
+		Keystore:           exported.Keystore,
+		KeystorePassphrase: "keystore-passphrase",
+	}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	importedKey, err := other.wallets.GetPrivateKey(ctx, imported.Address, "")
+	if err != nil {
+		t.Fatalf("get imported private key: %v", err)
+	}
+	if imported.Address != wallet.Address || !importedKey.Equal(privateKey) {
+		t.Errorf("imported wallet %s, want %s with the same private key", imported.Address, wallet.Address)
+	}
+}
+
+func TestImportKeystoreRejectsExcessiveKDFCost(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key := &keystore.Key{Address: crypto.PubkeyToAddress(privateKey.PublicKey), PrivateKey: privateKey}
+	keyJSON, err := keystore.EncryptKey(key, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// 只改写kdfparams中的n：超过上限时在派生前拒绝
+	excessive := []byte(strings.Replace(string(keyJSON), `"n":4096`, `"n":1048576`, 1))
+	if string(excessive) == string(keyJSON) {
+		t.Fatal("scrypt n not found in keystore JSON")
+	}
+	if _, err := decryptKeystore(excessive, "passphrase"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Errorf("excessive cost err = %v, want ErrInvalidKeystore", err)
+	}
+	if _, err := decryptKeystore(keyJSON, "passphrase"); err != nil {
+		t.Errorf("light cost keystore: %v", err)
+	}
+}
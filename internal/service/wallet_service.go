@@ -4,153 +4,708 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
+	"crypto-wallet-api/internal/apperr"
 	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/lifecycle"
 	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
 	"crypto-wallet-api/internal/models"
 	"crypto-wallet-api/internal/repository"
 	"crypto-wallet-api/internal/utils"
 	"crypto-wallet-api/pkg/cache"
+	"crypto-wallet-api/pkg/pricing"
 )
 
+// defaultBalanceCacheTTL 余额默认缓存时间
+const defaultBalanceCacheTTL = 30 * time.Second
+
+// degradedBalanceTTLFactor 链节点不健康时余额缓存时间的倍数（节点未同步时重新查询也只能得到相同的旧值）
+const degradedBalanceTTLFactor = 10
+
+const (
+	// balanceUpdateTimeout 异步余额更新（查询链上余额并写入缓存与数据库）的超时时间
+	balanceUpdateTimeout = 30 * time.Second
+	// balanceWriteAttempts、balanceWriteBackoff 余额写入缓存或数据库的尝试次数与首次重试间隔（之后按指数递增）
+	balanceWriteAttempts = 3
+	balanceWriteBackoff  = 200 * time.Millisecond
+	// staleBalanceBatchSize 每轮重试刷新的待刷新钱包数量上限
+	staleBalanceBatchSize = 100
+)
+
+// ErrWalletNotFound 钱包不存在或不属于当前用户（两种情况不做区分，避免泄露地址归属）
+var ErrWalletNotFound = apperr.NotFound("wallet")
+
+var (
+	// ErrPassphraseRequired 钱包私钥由用户口令保护，请求未提供口令
+	ErrPassphraseRequired = apperr.New("error.passphrase_required", "wallet passphrase is required")
+	// ErrInvalidPassphrase 钱包口令错误
+	ErrInvalidPassphrase = apperr.New("error.invalid_passphrase", "invalid wallet passphrase")
+	// ErrWalletArchived 钱包已归档，取消归档后才能发送交易
+	ErrWalletArchived = apperr.New("error.wallet_archived", "wallet is archived")
+	// ErrNotPersonalWallet 只有个人钱包可以设为默认钱包
+	ErrNotPersonalWallet = apperr.New("error.not_personal_wallet", "only personal wallets can be set as default")
+	// ErrNoDefaultWallet 未指定发送钱包且用户在该链上没有默认钱包
+	ErrNoDefaultWallet = apperr.New("error.no_default_wallet", "from_address is required: no default wallet on this chain")
+	// ErrChainNotAllowed 链被本部署的策略禁用（blockchain.allowed_chains/denied_chains），不能创建钱包或发送交易
+	ErrChainNotAllowed = apperr.New("error.chain_not_allowed", "chain is not enabled in this deployment")
+	// ErrWalletAlreadyImported 导入的地址已是当前用户的钱包
+	ErrWalletAlreadyImported = apperr.Conflict("error.wallet_already_imported", "wallet is already imported to your account")
+)
+
+// walletCreateAttempts 创建钱包时生成地址的最多次数（生成的地址已被收录时重新生成一次）
+const walletCreateAttempts = 2
+
+// WalletAlreadyImportedError 导入的地址已是当前用户的钱包（携带已有钱包，重试的导入请求可直接使用）
+type WalletAlreadyImportedError struct {
+	Wallet *models.Wallet
+}
+
+func (e *WalletAlreadyImportedError) Error() string {
+	return ErrWalletAlreadyImported.Error()
+}
+
+// Unwrap 支持errors.Is(err, ErrWalletAlreadyImported)
+func (e *WalletAlreadyImportedError) Unwrap() error {
+	return ErrWalletAlreadyImported
+}
+
+// checkChainEnabled 创建、导入钱包与发送交易前校验链未被部署策略禁用（已有钱包的查询不受影响）
+func checkChainEnabled(chainID int) error {
+	if models.ChainEnabled(chainID) {
+		return nil
+	}
+	return fmt.Errorf("%w: chain %d", ErrChainNotAllowed, chainID)
+}
+
+// kdfScrypt 用户口令的密钥派生算法
+const kdfScrypt = "scrypt"
+
+// kdfParams scrypt派生参数（保存在钱包记录中，调整默认值不影响已有钱包）
+type kdfParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// defaultKDFParams 新钱包使用的scrypt参数
+var defaultKDFParams = kdfParams{N: 1 << 15, R: 8, P: 1}
+
 // WalletService 钱包服务
 type WalletService struct {
 	walletRepo       *repository.WalletRepository
-	blockchainClient blockchain.BlockchainClient
-	cache            *cache.RedisCache
-	encryptionKey    []byte // 用于加密私钥的密钥
+	userRepo         *repository.UserRepository
+	chains           *blockchain.Clients // 按链ID路由的区块链客户端
+	cache            cache.Cache
+	eventService     *EventService
+	priceClient      *pricing.CoinGeckoClient
+	activityService  *ActivityService
+	encryptionKey    []byte       // 用于加密私钥的密钥
+	balanceTTL       atomic.Int64 // 余额缓存时间
+	keyCache         *keyCache    // 解密后的私钥缓存（未启用时为nil）
+	balanceRefresher *BalanceRefresher
+	balanceHistory   *BalanceHistoryService
+	chainHealth      *ChainHealthMonitor  // 链头监控（为nil时视为健康）
+	faucet           *FaucetService       // 测试链水龙头（为nil时新钱包不领水）
+	balanceFlight    singleflight.Group   // 合并同一地址并发的链上余额查询
+	jobs             *lifecycle.JobRunner // 后台任务队列（为nil时异步任务同步执行）
+	maxWallets       int                  // 每个用户最多拥有的钱包数（0表示不限制）
+	bulkConcurrency  int                  // 批量创建钱包时同时生成与加密私钥的协程数
+	closing          context.Context      // Close时取消，用于结束进行中的异步余额更新
+	closeFn          context.CancelFunc
 }
 
 // NewWalletService 创建钱包服务实例
 func NewWalletService(
 	walletRepo *repository.WalletRepository,
-	blockchainClient blockchain.BlockchainClient,
-	cache *cache.RedisCache,
+	userRepo *repository.UserRepository,
+	chains *blockchain.Clients,
+	cache cache.Cache,
+	eventService *EventService,
+	priceClient *pricing.CoinGeckoClient,
+	activityService *ActivityService,
 	encryptionKey []byte,
 ) *WalletService {
-	return &WalletService{
-		walletRepo:       walletRepo,
-		blockchainClient: blockchainClient,
-		cache:            cache,
-		encryptionKey:    encryptionKey,
+	s := &WalletService{
+		walletRepo:      walletRepo,
+		userRepo:        userRepo,
+		chains:          chains,
+		cache:           cache,
+		eventService:    eventService,
+		priceClient:     priceClient,
+		activityService: activityService,
+		encryptionKey:   encryptionKey,
+	}
+	s.bulkConcurrency = defaultBulkConcurrency
+	s.closing, s.closeFn = context.WithCancel(context.Background())
+	s.SetBalanceCacheTTL(defaultBalanceCacheTTL)
+	return s
+}
+
+// SetBalanceCacheTTL 调整余额缓存时间（支持运行时调整）
+func (s *WalletService) SetBalanceCacheTTL(ttl time.Duration) {
+	s.balanceTTL.Store(int64(ttl))
+}
+
+// SetChainHealth 设置链头监控（节点不健康时延长余额缓存时间）
+func (s *WalletService) SetChainHealth(monitor *ChainHealthMonitor) {
+	s.chainHealth = monitor
+}
+
+// ChainDegraded 链节点是否不健康（此时返回的余额可能已过期）
+func (s *WalletService) ChainDegraded(chainID int) bool {
+	return s.chainHealth.Degraded(chainID)
+}
+
+// balanceCacheTTL 余额缓存时间（钱包所在链的节点不健康时延长）
+func (s *WalletService) balanceCacheTTL(chainID int) time.Duration {
+	ttl := time.Duration(s.balanceTTL.Load())
+	if s.ChainDegraded(chainID) {
+		ttl *= degradedBalanceTTLFactor
 	}
+	return ttl
 }
 
-// CreateWallet 创建新钱包
+// SetFaucet 设置测试链水龙头（在启用水龙头的链上新建钱包时写入领水任务）
+func (s *WalletService) SetFaucet(faucet *FaucetService) {
+	s.faucet = faucet
+}
+
+// SetBalanceRefresher 设置后台余额刷新器（未设置时每次刷新提交一个后台任务）
+func (s *WalletService) SetBalanceRefresher(refresher *BalanceRefresher) {
+	s.balanceRefresher = refresher
+}
+
+// SetJobRunner 设置后台任务队列（异步更新数据库余额与刷新余额，进程关闭时执行完已提交的任务）
+func (s *WalletService) SetJobRunner(jobs *lifecycle.JobRunner) {
+	s.jobs = jobs
+}
+
+// SetWalletQuota 设置每个用户最多拥有的钱包数（创建、批量创建与导入时检查，0表示不限制）
+func (s *WalletService) SetWalletQuota(maxWallets int) {
+	s.maxWallets = maxWallets
+}
+
+// SetBulkConcurrency 设置批量创建钱包时同时生成与加密私钥的协程数
+func (s *WalletService) SetBulkConcurrency(concurrency int) {
+	s.bulkConcurrency = max(concurrency, 1)
+}
+
+// SetBalanceHistory 设置余额历史服务（刷新余额时记录快照）
+func (s *WalletService) SetBalanceHistory(history *BalanceHistoryService) {
+	s.balanceHistory = history
+}
+
+// EnableKeyCache 启用进程内私钥缓存，连续转账时避免重复查询与解密
+func (s *WalletService) EnableKeyCache(ttl time.Duration, maxSize int) {
+	s.keyCache = newKeyCache(ttl, maxSize)
+}
+
+// Close 清零缓存中的私钥并取消进行中的异步余额更新（进程退出时调用）
+func (s *WalletService) Close() {
+	s.closeFn()
+	if s.keyCache != nil {
+		s.keyCache.close()
+	}
+}
+
+// CreateWallet 创建新钱包（指定org_id时创建为组织钱包，需要admin及以上角色）
 func (s *WalletService) CreateWallet(ctx context.Context, userID uint, req *models.WalletCreateRequest) (*models.Wallet, error) {
-	// 1. 生成钱包地址和私钥
-	address, privateKey, err := s.blockchainClient.CreateWallet()
+	// 1. 校验链已启用与组织权限
+	if err := checkChainEnabled(req.ChainID); err != nil {
+		return nil, err
+	}
+	orgID, err := s.walletOrg(ctx, userID, req.OrgID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 导出私钥为十六进制字符串
-	privateKeyBytes := crypto.FromECDSA(privateKey)
-	privateKeyHex := hex.EncodeToString(privateKeyBytes)
+	// 2. 校验钱包数量上限
+	if err := s.checkWalletQuota(ctx, userID, 1); err != nil {
+		return nil, err
+	}
 
-	// 3. 加密私钥
-	encryptedKey, err := utils.EncryptAES(privateKeyHex, s.encryptionKey)
+	// 3. 生成并保存钱包（生成的地址已被收录时重新生成）
+	for attempt := 1; ; attempt++ {
+		wallet, err := s.createGeneratedWallet(ctx, userID, orgID, req)
+		if errors.Is(err, repository.ErrDuplicateAddress) && attempt < walletCreateAttempts {
+			logger.WithCtx(ctx).Warn("generated wallet address already exists, regenerating",
+				zap.Uint("user_id", userID),
+				zap.Int("chain_id", req.ChainID),
+			)
+			continue
+		}
+		return wallet, err
+	}
+}
+
+// createGeneratedWallet 生成钱包地址和私钥，加密私钥后保存
+func (s *WalletService) createGeneratedWallet(ctx context.Context, userID uint, orgID *uint, req *models.WalletCreateRequest) (*models.Wallet, error) {
+	// 1. 生成钱包地址和私钥
+	client, err := chainClient(s.chains, req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	address, privateKey, err := client.CreateWallet()
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 创建钱包对象
+	// 2. 创建钱包对象
 	wallet := &models.Wallet{
-		UserID:              userID,
-		Address:             address,
-		PrivateKeyEncrypted: encryptedKey,
-		ChainID:             req.ChainID,
-		Balance:             "0",
-		Name:                req.Name,
+		UserID:  userID,
+		OrgID:   orgID,
+		Address: address,
+		ChainID: req.ChainID,
+		Balance: "0",
+		Name:    req.Name,
 	}
 
-	// 5. 保存到数据库
-	if err := s.walletRepo.Create(ctx, wallet); err != nil {
-		return nil, err
+	// 3. 测试链启用水龙头时预留当日领取次数，领水任务与钱包在同一事务中写入
+	var funding *models.OutboxEvent
+	if s.faucet != nil {
+		if funding, err = s.faucet.Prepare(ctx, wallet); err != nil {
+			return nil, err
+		}
 	}
 
-	// 6. 异步查询链上余额并更新
-	go s.updateBalanceAsync(context.Background(), wallet.Address)
-
+	// 4. 加密私钥并保存
+	if err := s.saveWallet(ctx, wallet, privateKey, req.Passphrase, funding); err != nil {
+		if funding != nil {
+			s.faucet.Release(context.WithoutCancel(ctx), wallet)
+		}
+		return nil, err
+	}
 	return wallet, nil
 }
 
-// GetWalletByAddress 根据地址查询钱包
-func (s *WalletService) GetWalletByAddress(ctx context.Context, userID uint, address string) (*models.Wallet, error) {
-	// 1. 查询钱包
-	wallet, err := s.walletRepo.GetByAddress(ctx, address)
+// walletOrg 校验用户可以在组织下创建钱包（需要admin及以上角色），orgID为0时返回nil（个人钱包）
+func (s *WalletService) walletOrg(ctx context.Context, userID uint, orgID uint) (*uint, error) {
+	if orgID == 0 {
+		return nil, nil
+	}
+	membership, err := s.walletRepo.GetMembership(ctx, orgID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if membership == nil {
+		return nil, ErrOrgNotFound
+	}
+	if membership.Role.Rank() < models.OrgRoleAdmin.Rank() {
+		return nil, ErrPermissionDenied
+	}
+	return &orgID, nil
+}
+
+// saveWallet 加密私钥并保存新钱包（设置口令时先用口令派生的密钥加密，再用服务端密钥加密；funding为领水任务，可为nil）
+func (s *WalletService) saveWallet(ctx context.Context, wallet *models.Wallet, privateKey *ecdsa.PrivateKey, passphrase string, funding *models.OutboxEvent) error {
+	// 1. 导出私钥为十六进制字符串
+	privateKeyHex := hex.EncodeToString(crypto.FromECDSA(privateKey))
 
-	// 2. 验证所有权
-	if wallet.UserID != userID {
-		return nil, errors.New("wallet not found")
+	// 2. 加密私钥
+	plaintext := privateKeyHex
+	var err error
+	if passphrase != "" {
+		if plaintext, err = sealWithPassphrase(wallet, privateKeyHex, passphrase); err != nil {
+			return err
+		}
+	}
+	wallet.PrivateKeyEncrypted, err = utils.EncryptAES(plaintext, s.encryptionKey)
+	if err != nil {
+		return err
 	}
 
-	return wallet, nil
+	// 3. 保存到数据库
+	if err := s.walletRepo.CreateWithOutbox(ctx, wallet, funding); err != nil {
+		return err
+	}
+
+	// 4. 异步查询链上余额并更新
+	s.scheduleBalanceRefresh(ctx, wallet)
+	return nil
+}
+
+// GetWalletByAddress 根据地址查询用户可查看的钱包
+func (s *WalletService) GetWalletByAddress(ctx context.Context, userID uint, address string) (*models.Wallet, error) {
+	return s.GetAuthorizedWallet(ctx, userID, address, PermView)
 }
 
-// GetUserWallets 获取用户的所有钱包
-func (s *WalletService) GetUserWallets(ctx context.Context, userID uint) ([]*models.Wallet, error) {
-	return s.walletRepo.GetByUserID(ctx, userID)
+// GetAuthorizedWallet 根据地址查询钱包并校验用户权限
+func (s *WalletService) GetAuthorizedWallet(ctx context.Context, userID uint, address string, perm WalletPermission) (*models.Wallet, error) {
+	wallet, _, err := loadAuthorizedWallet(ctx, s.walletRepo, userID, address, perm)
+	return wallet, err
 }
 
-// GetBalance 查询钱包余额（实时从链上查询）
+// GetUserWallets 获取用户的钱包（archived为nil时包括已归档的钱包）
+func (s *WalletService) GetUserWallets(ctx context.Context, userID uint, archived *bool) ([]*models.Wallet, error) {
+	return s.walletRepo.ListByUserID(ctx, userID, archived)
+}
+
+// WalletListVersion 钱包列表的数据版本（用于ETag）：各链的钱包数量与最近更新时间，以及影响响应的美元价格与链健康状态
+func (s *WalletService) WalletListVersion(ctx context.Context, userID uint, archived *bool) (string, error) {
+	versions, err := s.walletRepo.ListVersions(ctx, userID, archived)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, v := range versions {
+		price, _ := s.usdPrice(ctx, v.ChainID)
+		fmt.Fprintf(&b, "%d:%d:%d:%g:%t;", v.ChainID, v.Count, v.UpdatedAt.UnixNano(), price, s.ChainDegraded(v.ChainID))
+	}
+	return b.String(), nil
+}
+
+// GetBalance 查询钱包余额（优先读取缓存）
 func (s *WalletService) GetBalance(ctx context.Context, userID uint, address string) (*big.Int, error) {
-	// 1. 验证钱包所有权
-	_, err := s.GetWalletByAddress(ctx, userID, address)
+	return s.getBalance(ctx, userID, address, true)
+}
+
+// RefreshBalance 绕过缓存从链上查询钱包余额，并以查询结果刷新缓存
+func (s *WalletService) RefreshBalance(ctx context.Context, userID uint, address string) (*big.Int, error) {
+	return s.getBalance(ctx, userID, address, false)
+}
+
+// getBalance 查询钱包余额，useCache为false时跳过缓存读取
+func (s *WalletService) getBalance(ctx context.Context, userID uint, address string, useCache bool) (*big.Int, error) {
+	// 1. 验证钱包查看权限
+	wallet, err := s.GetWalletByAddress(ctx, userID, address)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. 先查缓存
-	cacheKey := "balance:" + address
-	if cachedBalance, err := s.cache.Get(ctx, cacheKey); err == nil {
-		balance := new(big.Int)
-		balance.SetString(cachedBalance, 10)
-		return balance, nil
+	cacheKey := balanceCacheKey(address)
+	if useCache {
+		if cachedBalance, ok := readCache(ctx, s.cache, cacheKey); ok {
+			// 缓存值无法解析为非负整数时不可信，回源链上查询（避免返回零余额）
+			if balance, valid := new(big.Int).SetString(cachedBalance, 10); valid && balance.Sign() >= 0 {
+				return balance, nil
+			}
+			metrics.CacheReadFailures.WithLabelValues("invalid").Inc()
+			logger.WithCtx(ctx).Warn("invalid cached balance, fetching from chain",
+				zap.String("address", address),
+				zap.String("cached", cachedBalance),
+			)
+		}
 	}
 
-	// 3. 从链上查询
-	balance, err := s.blockchainClient.GetBalance(ctx, address)
+	// 3. 从链上查询（同一地址的并发查询合并为一次）
+	balance, err := s.fetchBalance(ctx, wallet.ChainID, address)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 写入缓存（30秒过期）
-	s.cache.Set(ctx, cacheKey, balance.String(), 30)
+	// 4. 写入缓存（失败仅记录日志，下次读取时回源）
+	if err := s.cache.Set(ctx, cacheKey, balance.String(), s.balanceCacheTTL(wallet.ChainID)); err != nil {
+		logger.WithCtx(ctx).Warn("failed to cache balance",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+	}
 
 	// 5. 异步更新数据库
-	go s.walletRepo.UpdateBalance(context.Background(), address, balance.String())
+	s.runBackground(ctx, "balance.write_db", func(ctx context.Context) {
+		s.writeBalanceDB(ctx, address, balance)
+	})
 
 	return balance, nil
 }
 
+// InvalidateBalance 删除钱包余额缓存（转账广播与确认后调用，失败仅记录日志）
+func (s *WalletService) InvalidateBalance(ctx context.Context, addresses ...string) {
+	keys := make([]string, len(addresses))
+	for i, address := range addresses {
+		keys[i] = balanceCacheKey(address)
+	}
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		logger.WithCtx(ctx).Warn("failed to invalidate balance cache",
+			zap.Strings("addresses", addresses),
+			zap.Error(err),
+		)
+	}
+}
+
+// balanceCacheKey 余额缓存键
+func balanceCacheKey(address string) string {
+	return "balance:" + address
+}
+
+// ValueInUSD 计算余额的美元估值，价格不可用时返回false（不影响主流程）
+func (s *WalletService) ValueInUSD(ctx context.Context, chainID int, wei *big.Int) (string, bool) {
+	price, ok := s.usdPrice(ctx, chainID)
+	if !ok {
+		return "", false
+	}
+
+	// USD = wei / 1e18 * price
+	value := new(big.Float).SetInt(wei)
+	value.Quo(value, big.NewFloat(1e18))
+	value.Mul(value, big.NewFloat(price))
+	return value.Text('f', 2), true
+}
+
+// usdPrice 查询链原生币的美元价格（链无价格来源、未配置价格客户端或查询失败时返回false）
+func (s *WalletService) usdPrice(ctx context.Context, chainID int) (float64, bool) {
+	asset, ok := pricing.AssetForChain(chainID)
+	if !ok || s.priceClient == nil {
+		return 0, false
+	}
+
+	price, err := s.priceClient.GetUSDPrice(ctx, asset)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("failed to get asset price",
+			zap.String("asset", asset),
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	return price, true
+}
+
 // UpdateWallet 更新钱包信息（仅支持更新名称）
 func (s *WalletService) UpdateWallet(ctx context.Context, userID uint, address string, name string) error {
-	// 1. 验证钱包所有权
-	wallet, err := s.GetWalletByAddress(ctx, userID, address)
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
 	if err != nil {
 		return err
 	}
 
 	// 2. 更新名称
+	oldName := wallet.Name
 	wallet.Name = name
 
 	// 3. 保存到数据库
-	return s.walletRepo.Update(ctx, wallet)
+	if err := s.walletRepo.Update(ctx, wallet); err != nil {
+		return err
+	}
+
+	if oldName != name {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityWalletRenamed, &models.WalletRenamedDetails{OldName: oldName, NewName: name})
+	}
+	return nil
+}
+
+// PatchWallet 部分更新钱包信息（名称、标签、颜色、归档状态与最终确认深度，只修改请求中提供的字段）
+func (s *WalletService) PatchWallet(ctx context.Context, userID uint, address string, req *models.WalletUpdateRequest) (*models.Wallet, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 合并修改
+	oldName, oldArchived, oldConfirmations := wallet.Name, wallet.Archived, wallet.ConfirmationsRequired
+	if req.Name != nil {
+		wallet.Name = *req.Name
+	}
+	if req.Label != nil {
+		wallet.Label = *req.Label
+	}
+	if req.Color != nil {
+		wallet.Color = strings.ToLower(*req.Color)
+	}
+	if req.Archived != nil {
+		wallet.Archived = *req.Archived
+	}
+	if req.ConfirmationsRequired != nil {
+		wallet.ConfirmationsRequired = *req.ConfirmationsRequired
+	}
+
+	// 3. 保存到数据库
+	if err := s.walletRepo.Update(ctx, wallet); err != nil {
+		return nil, err
+	}
+
+	// 4. 记录变更
+	if wallet.Name != oldName {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityWalletRenamed, &models.WalletRenamedDetails{OldName: oldName, NewName: wallet.Name})
+	}
+	if wallet.Archived != oldArchived {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityArchiveChanged, &models.ArchiveChangedDetails{Archived: wallet.Archived})
+	}
+	if wallet.ConfirmationsRequired != oldConfirmations {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityConfirmationsChanged, &models.ConfirmationsChangedDetails{ConfirmationsRequired: wallet.ConfirmationsRequired})
+	}
+	return wallet, nil
+}
+
+// UpdateSettings 更新钱包安全设置
+func (s *WalletService) UpdateSettings(ctx context.Context, userID uint, address string, req *models.WalletSettingsRequest) (*models.Wallet, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 更新设置
+	changed := wallet.WhitelistEnabled != *req.WhitelistEnabled
+	wallet.WhitelistEnabled = *req.WhitelistEnabled
+
+	// 3. 保存到数据库
+	if err := s.walletRepo.Update(ctx, wallet); err != nil {
+		return nil, err
+	}
+
+	if changed {
+		s.activityService.Record(ctx, wallet.ID, models.ActivitySettingsChanged, &models.SettingsChangedDetails{WhitelistEnabled: wallet.WhitelistEnabled})
+	}
+	return wallet, nil
+}
+
+// UpdateLimits 更新钱包每日限额
+func (s *WalletService) UpdateLimits(ctx context.Context, userID uint, address string, req *models.WalletLimitsRequest) (*models.Wallet, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 更新限额（0视为不限）
+	oldLimitWei, oldTxLimit := wallet.DailyLimitWei, wallet.DailyTxLimit
+	wallet.DailyLimitWei = req.DailyLimitWei
+	if wallet.DailyLimitWei == "0" {
+		wallet.DailyLimitWei = ""
+	}
+	wallet.DailyTxLimit = req.DailyTxLimit
+
+	// 3. 保存到数据库
+	if err := s.walletRepo.Update(ctx, wallet); err != nil {
+		return nil, err
+	}
+
+	if oldLimitWei != wallet.DailyLimitWei || oldTxLimit != wallet.DailyTxLimit {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityLimitsChanged, &models.LimitsChangedDetails{
+			DailyLimitWei: wallet.DailyLimitWei,
+			DailyTxLimit:  wallet.DailyTxLimit,
+		})
+		if err := s.eventService.Publish(ctx, &models.WalletEvent{
+			Type:    models.EventLimitsChanged,
+			Address: wallet.Address,
+		}); err != nil {
+			logger.WithCtx(ctx).Warn("failed to publish limits event", zap.String("address", wallet.Address), zap.Error(err))
+		}
+	}
+	return wallet, nil
+}
+
+// GetApprovalPolicy 查询钱包审批策略
+func (s *WalletService) GetApprovalPolicy(ctx context.Context, userID uint, address string) (*models.ApprovalPolicyResponse, error) {
+	wallet, err := s.GetWalletByAddress(ctx, userID, address)
+	if err != nil {
+		return nil, err
+	}
+	approverIDs, err := s.walletRepo.GetApproverIDs(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApprovalPolicyResponse{
+		ThresholdWei:      wallet.ApprovalThresholdWei,
+		RequiredApprovals: wallet.RequiredApprovals,
+		ApproverIDs:       approverIDs,
+	}, nil
+}
+
+// UpdateApprovalPolicy 更新钱包审批策略（阈值为空或0时关闭审批并清空审批人）
+//
+// 审批人可以包括钱包所有者或组织成员，每笔交易的发起人不能批准自己发起的交易（见ApproveTransaction）。
+func (s *WalletService) UpdateApprovalPolicy(ctx context.Context, userID uint, address string, req *models.ApprovalPolicyRequest) (*models.ApprovalPolicyResponse, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验策略
+	approverIDs := req.ApproverIDs
+	if req.ThresholdWei == "" || req.ThresholdWei == "0" {
+		wallet.ApprovalThresholdWei = ""
+		wallet.RequiredApprovals = 0
+		approverIDs = nil
+	} else {
+		if wallet.PassphraseProtected() {
+			return nil, fmt.Errorf("%w: not available for passphrase-protected wallets", ErrInvalidApprovalPolicy)
+		}
+		if req.RequiredApprovals < 1 {
+			return nil, fmt.Errorf("%w: required_approvals must be at least 1", ErrInvalidApprovalPolicy)
+		}
+		if len(approverIDs) < req.RequiredApprovals {
+			return nil, fmt.Errorf("%w: required_approvals exceeds the number of approvers", ErrInvalidApprovalPolicy)
+		}
+		wallet.ApprovalThresholdWei = req.ThresholdWei
+		wallet.RequiredApprovals = req.RequiredApprovals
+	}
+
+	// 3. 保存策略与审批人
+	if err := s.walletRepo.UpdateApprovalPolicy(ctx, wallet, approverIDs); err != nil {
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, wallet.ID, models.ActivityApprovalChanged, &models.ApprovalChangedDetails{
+		ThresholdWei:      wallet.ApprovalThresholdWei,
+		RequiredApprovals: wallet.RequiredApprovals,
+		ApproverIDs:       approverIDs,
+	})
+	return &models.ApprovalPolicyResponse{
+		ThresholdWei:      wallet.ApprovalThresholdWei,
+		RequiredApprovals: wallet.RequiredApprovals,
+		ApproverIDs:       approverIDs,
+	}, nil
+}
+
+// SetDefaultWallet 将个人钱包设为用户在该链上的默认钱包（原默认钱包自动取消）
+func (s *WalletService) SetDefaultWallet(ctx context.Context, userID uint, address string) (*models.Wallet, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 组织钱包与已归档钱包不能设为默认
+	if wallet.OrgID != nil {
+		return nil, ErrNotPersonalWallet
+	}
+	if wallet.Archived {
+		return nil, ErrWalletArchived
+	}
+	if wallet.IsDefault {
+		return wallet, nil
+	}
+
+	// 3. 保存到数据库
+	if err := s.walletRepo.SetDefault(ctx, wallet); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// DefaultWallet 查询用户在指定链上的默认钱包
+func (s *WalletService) DefaultWallet(ctx context.Context, userID uint, chainID int) (*models.Wallet, error) {
+	wallet, err := s.walletRepo.GetDefault(ctx, userID, chainID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, ErrNoDefaultWallet
+		}
+		return nil, err
+	}
+	return wallet, nil
 }
 
 // DeleteWallet 删除钱包
 func (s *WalletService) DeleteWallet(ctx context.Context, userID uint, address string) error {
-	// 1. 验证钱包所有权
-	wallet, err := s.GetWalletByAddress(ctx, userID, address)
+	// 1. 验证钱包管理权限
+	wallet, err := s.GetAuthorizedWallet(ctx, userID, address, PermManage)
 	if err != nil {
 		return err
 	}
@@ -162,26 +717,46 @@ func (s *WalletService) DeleteWallet(ctx context.Context, userID uint, address s
 	}
 
 	if balance.Cmp(big.NewInt(0)) > 0 {
-		return errors.New("cannot delete wallet with non-zero balance")
+		return apperr.Invalid("error.wallet_has_balance", "cannot delete wallet with non-zero balance")
 	}
 
 	// 3. 删除钱包
-	return s.walletRepo.Delete(ctx, wallet.ID)
+	if err := s.walletRepo.Delete(ctx, wallet.ID); err != nil {
+		return err
+	}
+	if s.keyCache != nil {
+		s.keyCache.remove(wallet.Address)
+	}
+	return nil
 }
 
-// GetPrivateKey 获取解密后的私钥（内部使用，不对外暴露）
-func (s *WalletService) GetPrivateKey(ctx context.Context, address string) (*ecdsa.PrivateKey, error) {
+// GetPrivateKey 获取解密后的私钥（内部使用，不对外暴露），口令保护的钱包必须提供passphrase
+func (s *WalletService) GetPrivateKey(ctx context.Context, address string, passphrase string) (*ecdsa.PrivateKey, error) {
+	if s.keyCache != nil {
+		if privateKey, ok := s.keyCache.get(address); ok {
+			return privateKey, nil
+		}
+	}
+
 	// 1. 查询钱包
 	wallet, err := s.walletRepo.GetByAddress(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 解密私钥
+	// 2. 解密私钥（口令保护的钱包再用口令派生的密钥解密一层）
 	privateKeyHex, err := utils.DecryptAES(wallet.PrivateKeyEncrypted, s.encryptionKey)
 	if err != nil {
 		return nil, err
 	}
+	if wallet.PassphraseProtected() {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		if privateKeyHex, err = openWithPassphrase(wallet, privateKeyHex, passphrase); err != nil {
+			return nil, err
+		}
+	}
 
 	// 3. 转换为ecdsa.PrivateKey
 	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
@@ -194,29 +769,274 @@ func (s *WalletService) GetPrivateKey(ctx context.Context, address string) (*ecd
 		return nil, err
 	}
 
+	// 口令保护的私钥不缓存，确保每次签名都需要用户参与
+	if s.keyCache != nil && !wallet.PassphraseProtected() {
+		s.keyCache.put(address, privateKey)
+	}
 	return privateKey, nil
 }
 
-// updateBalanceAsync 异步更新余额
-func (s *WalletService) updateBalanceAsync(ctx context.Context, address string) {
-	balance, err := s.blockchainClient.GetBalance(ctx, address)
+// sealWithPassphrase 用口令派生的密钥加密私钥，并在钱包上记录派生算法、参数与盐值
+func sealWithPassphrase(wallet *models.Wallet, privateKeyHex, passphrase string) (string, error) {
+	salt, err := utils.GenerateRandomHex(16)
 	if err != nil {
-		logger.Error("failed to update balance",
-			zap.String("address", address),
-			zap.Error(err),
-		)
+		return "", err
+	}
+	params, err := json.Marshal(defaultKDFParams)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := deriveWalletKey(passphrase, salt, defaultKDFParams)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := utils.EncryptAES(privateKeyHex, key)
+	if err != nil {
+		return "", err
+	}
+
+	wallet.KeyKDF = kdfScrypt
+	wallet.KeyKDFParams = string(params)
+	wallet.KeyKDFSalt = salt
+	return sealed, nil
+}
+
+// openWithPassphrase 用口令派生的密钥解密私钥，口令错误时返回ErrInvalidPassphrase
+func openWithPassphrase(wallet *models.Wallet, sealed, passphrase string) (string, error) {
+	if wallet.KeyKDF != kdfScrypt {
+		return "", fmt.Errorf("unsupported key derivation function: %s", wallet.KeyKDF)
+	}
+	var params kdfParams
+	if err := json.Unmarshal([]byte(wallet.KeyKDFParams), &params); err != nil {
+		return "", fmt.Errorf("invalid key derivation parameters: %w", err)
+	}
+
+	key, err := deriveWalletKey(passphrase, wallet.KeyKDFSalt, params)
+	if err != nil {
+		return "", err
+	}
+	privateKeyHex, err := utils.DecryptAES(sealed, key)
+	if err != nil {
+		return "", ErrInvalidPassphrase
+	}
+	return privateKeyHex, nil
+}
+
+// deriveWalletKey 从口令派生钱包私钥的加密密钥
+func deriveWalletKey(passphrase, saltHex string, params kdfParams) ([]byte, error) {
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key derivation salt: %w", err)
+	}
+	return utils.DeriveKeyScrypt(passphrase, salt, params.N, params.R, params.P)
+}
+
+// scheduleBalanceRefresh 异步刷新钱包的链上余额（配置了后台刷新器时进入去重队列）
+func (s *WalletService) scheduleBalanceRefresh(ctx context.Context, wallet *models.Wallet) {
+	chainID, address := wallet.ChainID, wallet.Address
+	if s.balanceRefresher != nil {
+		s.balanceRefresher.Enqueue(chainID, address)
+		return
+	}
+	s.runBackground(ctx, "balance.refresh", func(ctx context.Context) {
+		s.updateBalance(ctx, chainID, address)
+	})
+}
+
+// RefreshStaleBalances 重新刷新标记为待刷新超过grace的钱包余额（交易确认后的刷新失败或进程在刷新前退出，由Worker定时调用）
+func (s *WalletService) RefreshStaleBalances(ctx context.Context, grace time.Duration) {
+	wallets, err := s.walletRepo.ListBalanceStale(ctx, time.Now().Add(-grace), staleBalanceBatchSize)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to list wallets with stale balances", zap.Error(err))
+		return
+	}
+	for _, wallet := range wallets {
+		s.scheduleBalanceRefresh(ctx, wallet)
+	}
+	if len(wallets) > 0 {
+		logger.WithCtx(ctx).Info("retrying stale balance refreshes", zap.Int("count", len(wallets)))
+	}
+}
+
+// RefreshBalanceByAddress 从链上刷新钱包余额（运维工具使用，不校验用户权限；dryRun时只返回钱包当前记录的余额）
+//
+// 同步完成与后台刷新相同的处理（缓存、数据库、余额快照与入账事件），返回后进程可以直接退出。
+func (s *WalletService) RefreshBalanceByAddress(ctx context.Context, address, operator string, dryRun bool) (*models.Wallet, *big.Int, error) {
+	// 1. 查询钱包
+	wallet, err := s.walletRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dryRun {
+		return wallet, nil, nil
+	}
+
+	// 2. 查询链上余额并保存
+	balance, err := s.fetchBalance(ctx, wallet.ChainID, wallet.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.applyBalance(ctx, wallet.ChainID, wallet.Address, balance)
+
+	// 3. 审计日志
+	logger.WithCtx(ctx).Info("wallet balance refreshed",
+		zap.String("address", wallet.Address),
+		zap.String("previous", wallet.Balance),
+		zap.String("balance", balance.String()),
+		zap.String("operator", operator),
+	)
+	return wallet, balance, nil
+}
+
+// runBackground 将异步任务提交到后台任务队列（进程关闭时先执行完已提交的任务），未设置任务队列时同步执行
+func (s *WalletService) runBackground(ctx context.Context, name string, fn func(ctx context.Context)) {
+	job := func(jobCtx context.Context) {
+		bgCtx, cancel := s.backgroundContext(ctx)
+		defer cancel()
+		stop := context.AfterFunc(jobCtx, cancel)
+		defer stop()
+		fn(bgCtx)
+	}
+	if s.jobs == nil {
+		job(context.Background())
 		return
 	}
+	s.jobs.Submit(name, job)
+}
+
+// backgroundContext 异步任务的上下文：保留请求的追踪信息但不随请求取消，超时或服务关闭时取消
+func (s *WalletService) backgroundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), balanceUpdateTimeout)
+	stop := context.AfterFunc(s.closing, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
 
-	// 更新数据库
-	if err := s.walletRepo.UpdateBalance(ctx, address, balance.String()); err != nil {
-		logger.Error("failed to save balance to database",
+// fetchBalance 查询地址在指定链上的余额（同一地址的并发查询共享一次RPC调用）
+func (s *WalletService) fetchBalance(ctx context.Context, chainID int, address string) (*big.Int, error) {
+	client, err := chainClient(s.chains, chainID)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%d:%s", chainID, strings.ToLower(address))
+	v, err, _ := s.balanceFlight.Do(key, func() (interface{}, error) {
+		return client.GetBalance(ctx, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 共享结果的调用方各自持有副本
+	return new(big.Int).Set(v.(*big.Int)), nil
+}
+
+// updateBalance 从链上刷新余额（在后台任务中执行）
+func (s *WalletService) updateBalance(ctx context.Context, chainID int, address string) {
+	balance, err := s.fetchBalance(ctx, chainID, address)
+	if err != nil {
+		logger.WithCtx(ctx).Error("failed to update balance",
 			zap.String("address", address),
 			zap.Error(err),
 		)
+		return
+	}
+
+	s.applyBalance(ctx, chainID, address, balance)
+}
+
+// applyBalance 保存查询到的链上余额，余额变化时记录快照并推送入账事件
+func (s *WalletService) applyBalance(ctx context.Context, chainID int, address string, balance *big.Int) {
+	// 记录更新前的链上余额（账本余额扣除内部转账净额），用于检测入账
+	var previous *big.Int
+	wallet, err := s.walletRepo.GetByAddress(ctx, address)
+	if err == nil {
+		previous = new(big.Int).Sub(utils.DecimalToWei(wallet.Balance), internalNet(wallet))
+	}
+
+	// 先更新缓存（读取余额以缓存为准），再更新数据库
+	s.writeBalanceCache(ctx, chainID, address, balance)
+	s.writeBalanceDB(ctx, address, balance)
+
+	// 记录余额快照
+	if previous != nil && s.balanceHistory != nil {
+		s.balanceHistory.Record(ctx, wallet, balance)
 	}
 
-	// 更新缓存
-	cacheKey := "balance:" + address
-	s.cache.Set(ctx, cacheKey, balance.String(), 30)
+	// 余额变化时记录快照
+	if previous != nil && balance.Cmp(previous) != 0 {
+		s.activityService.Record(ctx, wallet.ID, models.ActivityBalanceChanged, &models.BalanceChangedDetails{
+			Previous: previous.String(),
+			Balance:  balance.String(),
+			Delta:    new(big.Int).Sub(balance, previous).String(),
+		})
+	}
+
+	// 余额增加时推送入账事件
+	if previous != nil && balance.Cmp(previous) > 0 {
+		event := &models.WalletEvent{
+			Type:        models.EventDepositDetected,
+			Address:     address,
+			ExplorerURL: models.ExplorerAddressURL(wallet.ChainID, address),
+			Balance:     balance.String(),
+			Amount:      new(big.Int).Sub(balance, previous).String(),
+		}
+		if err := s.eventService.Publish(ctx, event); err != nil {
+			logger.WithCtx(ctx).Warn("failed to publish deposit event",
+				zap.String("address", address),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// writeBalanceCache 写入余额缓存（失败时按退避重试，仍失败时删除旧值并计入指标，避免读到过期余额）
+func (s *WalletService) writeBalanceCache(ctx context.Context, chainID int, address string, balance *big.Int) {
+	key := balanceCacheKey(address)
+	err := retryWithBackoff(ctx, balanceWriteAttempts, balanceWriteBackoff, func() error {
+		return s.cache.Set(ctx, key, balance.String(), s.balanceCacheTTL(chainID))
+	})
+	if err == nil {
+		return
+	}
+	metrics.BalanceWriteFailures.WithLabelValues("cache").Inc()
+	logger.WithCtx(ctx).Error("failed to save balance to cache",
+		zap.String("address", address),
+		zap.Error(err),
+	)
+	s.cache.Delete(ctx, key)
+}
+
+// writeBalanceDB 写入数据库中的余额（失败时按退避重试，仍失败时计入指标，下次刷新时修正）
+func (s *WalletService) writeBalanceDB(ctx context.Context, address string, balance *big.Int) {
+	err := retryWithBackoff(ctx, balanceWriteAttempts, balanceWriteBackoff, func() error {
+		return s.walletRepo.UpdateBalance(ctx, address, balance.String())
+	})
+	if err == nil {
+		return
+	}
+	metrics.BalanceWriteFailures.WithLabelValues("database").Inc()
+	logger.WithCtx(ctx).Error("failed to save balance to database",
+		zap.String("address", address),
+		zap.Error(err),
+	)
+}
+
+// retryWithBackoff 执行fn，失败时按指数退避重试，直到成功、达到尝试次数或ctx取消
+func retryWithBackoff(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(backoff << (attempt - 1)):
+			}
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
 }
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/internal/repository"
+)
+
+// defaultWhitelistCoolingOff 白名单条目默认冷静期
+const defaultWhitelistCoolingOff = 24 * time.Hour
+
+// ErrAddressNotWhitelisted 收款地址不在白名单中或尚未生效
+var ErrAddressNotWhitelisted = apperr.New("error.address_not_whitelisted", "recipient address is not whitelisted or not yet active")
+
+// WhitelistService 转账白名单服务
+type WhitelistService struct {
+	whitelistRepo   *repository.WhitelistRepository
+	walletService   *WalletService
+	activityService *ActivityService
+	coolingOff      time.Duration
+}
+
+// NewWhitelistService 创建白名单服务实例
+func NewWhitelistService(
+	whitelistRepo *repository.WhitelistRepository,
+	walletService *WalletService,
+	activityService *ActivityService,
+	coolingOff time.Duration,
+) *WhitelistService {
+	if coolingOff <= 0 {
+		coolingOff = defaultWhitelistCoolingOff
+	}
+	return &WhitelistService{
+		whitelistRepo:   whitelistRepo,
+		walletService:   walletService,
+		activityService: activityService,
+		coolingOff:      coolingOff,
+	}
+}
+
+// AddEntry 添加白名单地址（冷静期结束后生效）
+func (s *WhitelistService) AddEntry(ctx context.Context, userID uint, walletAddress string, req *models.WhitelistAddRequest) (*models.WhitelistEntry, error) {
+	// 1. 验证钱包管理权限
+	wallet, err := s.walletService.GetAuthorizedWallet(ctx, userID, walletAddress, PermManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 检查是否重复
+	address := common.HexToAddress(req.Address).Hex()
+	if _, err := s.whitelistRepo.GetByAddress(ctx, wallet.ID, address); err == nil {
+		return nil, apperr.Invalid("error.address_already_whitelisted", "address already whitelisted")
+	}
+
+	// 3. 保存条目
+	entry := &models.WhitelistEntry{
+		WalletID:    wallet.ID,
+		Address:     address,
+		Label:       req.Label,
+		ActivatesAt: time.Now().Add(s.coolingOff),
+	}
+	if err := s.whitelistRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	s.activityService.Record(ctx, wallet.ID, models.ActivityWhitelistAdded, &models.WhitelistChangedDetails{
+		EntryID:     entry.ID,
+		Address:     entry.Address,
+		Label:       entry.Label,
+		ActivatesAt: &entry.ActivatesAt,
+	})
+	return entry, nil
+}
+
+// ListEntries 查询钱包的白名单（包含冷静期内的条目）
+func (s *WhitelistService) ListEntries(ctx context.Context, userID uint, walletAddress string) ([]*models.WhitelistEntry, error) {
+	wallet, err := s.walletService.GetWalletByAddress(ctx, userID, walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	return s.whitelistRepo.GetByWalletID(ctx, wallet.ID)
+}
+
+// RemoveEntry 删除白名单条目（立即生效）
+func (s *WhitelistService) RemoveEntry(ctx context.Context, userID uint, walletAddress string, id uint) error {
+	wallet, err := s.walletService.GetAuthorizedWallet(ctx, userID, walletAddress, PermManage)
+	if err != nil {
+		return err
+	}
+
+	entry, err := s.whitelistRepo.Delete(ctx, wallet.ID, id)
+	if err != nil {
+		return err
+	}
+
+	s.activityService.Record(ctx, wallet.ID, models.ActivityWhitelistRemoved, &models.WhitelistChangedDetails{
+		EntryID: entry.ID,
+		Address: entry.Address,
+		Label:   entry.Label,
+	})
+	return nil
+}
+
+// CheckRecipient 校验收款地址（钱包未启用白名单时直接放行）
+func (s *WhitelistService) CheckRecipient(ctx context.Context, wallet *models.Wallet, toAddress string) error {
+	if !wallet.WhitelistEnabled {
+		return nil
+	}
+
+	entry, err := s.whitelistRepo.GetByAddress(ctx, wallet.ID, toAddress)
+	if err != nil {
+		return ErrAddressNotWhitelisted
+	}
+	if !entry.IsActive(time.Now()) {
+		return ErrAddressNotWhitelisted
+	}
+	return nil
+}
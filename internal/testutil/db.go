@@ -0,0 +1,128 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"crypto-wallet-api/pkg/database"
+)
+
+var (
+	// EncryptionKey 测试使用的加密密钥（钱包私钥与个人信息共用）
+	EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	// EmailHMACKey 测试使用的邮箱检索摘要密钥
+	EmailHMACKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+// postgresCast PostgreSQL的类型转换语法（SQLite按列的类型亲和性比较，去掉即可）
+var postgresCast = regexp.MustCompile(`::(numeric|text|bigint|integer)\b`)
+
+// NewDB 创建测试数据库：临时目录中的SQLite文件（多个连接共享，支持并发测试），按全部模型建表
+//
+// 仓库中的PostgreSQL专有语法（::类型转换、NOW()、行锁）在执行前改写或忽略，事务以BEGIN IMMEDIATE开始（相互串行，代替行锁）；
+// 使用date_trunc、FILTER等聚合的查询，以及把MAX(updated_at)等聚合结果读入time.Time的查询（SQLite返回字符串）不受支持，相关测试需要连接PostgreSQL。
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	database.RegisterEncryptedSerializer(EncryptionKey)
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sqlite handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	pool := &rewritePool{db: sqlDB}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+
+	if err := db.AutoMigrate(database.Models()...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// rewriteSQL 将PostgreSQL专有语法改写为SQLite等价写法
+func rewriteSQL(query string) string {
+	query = postgresCast.ReplaceAllString(query, "")
+	query = strings.ReplaceAll(query, "NOW()", "CURRENT_TIMESTAMP")
+	return strings.ReplaceAll(query, " ILIKE ", " LIKE ")
+}
+
+// rewritePool 执行前改写SQL的连接池（事务中的语句同样改写）
+type rewritePool struct {
+	db *sql.DB
+}
+
+func (p *rewritePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, rewriteSQL(query))
+}
+
+func (p *rewritePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, rewriteSQL(query), args...)
+}
+
+func (p *rewritePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, rewriteSQL(query), args...)
+}
+
+func (p *rewritePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, rewriteSQL(query), args...)
+}
+
+func (p *rewritePool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &rewriteTx{tx: tx}, nil
+}
+
+func (p *rewritePool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// rewriteTx 执行前改写SQL的事务
+type rewriteTx struct {
+	tx *sql.Tx
+}
+
+func (t *rewriteTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, rewriteSQL(query))
+}
+
+func (t *rewriteTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, rewriteSQL(query), args...)
+}
+
+func (t *rewriteTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, rewriteSQL(query), args...)
+}
+
+func (t *rewriteTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, rewriteSQL(query), args...)
+}
+
+func (t *rewriteTx) StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	return t.tx.StmtContext(ctx, stmt)
+}
+
+func (t *rewriteTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *rewriteTx) Rollback() error {
+	return t.tx.Rollback()
+}
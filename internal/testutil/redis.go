@@ -0,0 +1,33 @@
+// Package testutil 测试共用的依赖：按模型建表的SQLite数据库（不依赖PostgreSQL）与进程内Redis，仅供_test.go使用
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"crypto-wallet-api/internal/models"
+	"crypto-wallet-api/pkg/cache"
+)
+
+// ChainID 测试链的链ID（由RegisterChains注册，对应mock.NewClient(ChainID)）
+const ChainID = 11155111
+
+// NewRedis 启动进程内的Redis服务并返回连接它的RedisCache（事件发布、分布式锁与限流使用）
+func NewRedis(t testing.TB) (*cache.RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(server.Addr(), "", 0, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("connect redis: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+	return redisCache, server
+}
+
+// RegisterChains 注册测试链（请求中的chain_id按已注册的链校验）
+func RegisterChains() {
+	models.SetChains([]models.Chain{
+		{ChainID: ChainID, Name: "Sepolia", NativeSymbol: "ETH", Decimals: 18, EIP1559: true, Enabled: true},
+	})
+}
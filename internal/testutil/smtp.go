@@ -0,0 +1,131 @@
+package testutil
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SMTPMessage 测试SMTP服务收到的一封邮件
+type SMTPMessage struct {
+	From string
+	To   []string
+	Data string // 邮件头与正文（已去掉结尾的"."行）
+}
+
+// SMTPServer 进程内的最简SMTP服务（不支持STARTTLS与认证，配合mailer.TLSModeNone使用）
+type SMTPServer struct {
+	Host string
+	Port int
+
+	listener net.Listener
+	mu       sync.Mutex
+	reject   map[string]string
+	messages []SMTPMessage
+}
+
+// NewSMTPServer 在本机随机端口启动测试SMTP服务，测试结束时关闭
+func NewSMTPServer(t testing.TB) *SMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen smtp: %v", err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	s := &SMTPServer{Host: addr.IP.String(), Port: addr.Port, listener: listener, reject: map[string]string{}}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+// Reject 设置收件人被拒时的应答（如"550 mailbox unavailable"）
+func (s *SMTPServer) Reject(rcpt, reply string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reject[strings.ToLower(rcpt)] = reply
+}
+
+// Messages 返回已接收的邮件
+func (s *SMTPServer) Messages() []SMTPMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SMTPMessage(nil), s.messages...)
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle 处理一个SMTP会话
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	reply := func(line string) bool {
+		return text.PrintfLine("%s", line) == nil
+	}
+
+	var msg SMTPMessage
+	if !reply("220 localhost ESMTP test") {
+		return
+	}
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			msg = SMTPMessage{From: address(arg)}
+			reply("250 OK")
+		case "RCPT":
+			rcpt := address(arg)
+			s.mu.Lock()
+			rejected, ok := s.reject[strings.ToLower(rcpt)]
+			s.mu.Unlock()
+			if ok {
+				reply(rejected)
+				continue
+			}
+			msg.To = append(msg.To, rcpt)
+			reply("250 OK")
+		case "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+// address 取出"FROM:<a@b>"、"TO:<a@b>"中的邮箱地址
+func address(arg string) string {
+	start, end := strings.Index(arg, "<"), strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
@@ -3,10 +3,15 @@ package utils
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"io"
+
+	"golang.org/x/crypto/scrypt"
 )
 
 // EncryptAES 使用AES-GCM加密数据
@@ -83,6 +88,11 @@ func DecryptAES(ciphertext string, key []byte) (string, error) {
 	return string(plaintext), nil
 }
 
+// DeriveKeyScrypt 使用scrypt从口令派生32字节密钥（用于AES-256）
+func DeriveKeyScrypt(passphrase string, salt []byte, n, r, p int) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, n, r, p, 32)
+}
+
 // GenerateEncryptionKey 生成32字节的加密密钥
 func GenerateEncryptionKey() ([]byte, error) {
 	key := make([]byte, 32)
@@ -91,3 +101,25 @@ func GenerateEncryptionKey() ([]byte, error) {
 	}
 	return key, nil
 }
+
+// GenerateRandomHex 生成n字节随机数的十六进制字符串
+func GenerateRandomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SHA256Hex 计算字符串的SHA-256哈希（十六进制）
+func SHA256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// HMACSHA256Hex 计算字符串的HMAC-SHA256（十六进制），相同输入与密钥得到相同结果，可用于加密字段的等值检索
+func HMACSHA256Hex(value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag 由数据版本（数量、最近更新时间、筛选条件等）计算弱校验ETag（响应体按语言翻译、可能被压缩，不保证逐字节一致）
+func ETag(parts ...interface{}) string {
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%v\x00", part)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// NotModified 以数据版本计算ETag并写入响应头；与If-None-Match匹配时直接返回304并返回true，调用方无需再查询与序列化响应体
+func NotModified(c *gin.Context, parts ...interface{}) bool {
+	// 响应消息按语言翻译，语言不同时ETag也不同
+	etag := ETag(append(parts, Language(c))...)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	if !etagMatch(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagMatch 按弱比较判断If-None-Match（可为逗号分隔的多个ETag或*）是否包含etag
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestETagMatch(t *testing.T) {
+	etag := ETag("wallet", 1)
+	strong := etag[len("W/"):]
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"empty", "", false},
+		{"same weak etag", etag, true},
+		{"strong form", strong, true},
+		{"in list", `W/"other", ` + etag, true},
+		{"wildcard", "*", true},
+		{"different etag", `W/"other"`, false},
+		{"different version", ETag("wallet", 2), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatch(tt.ifNoneMatch, etag); got != tt.want {
+				t.Errorf("etagMatch(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,109 @@
 package utils
 
-import "math/big"
+import (
+	"math/big"
+	"strings"
+)
 
+// EtherDecimals 原生币的小数位数（1 ETH = 10^18 Wei）
+const EtherDecimals = 18
+
+// WeiToEthString 将Wei精确换算为保留18位小数的ETH字符串（与数据库decimal(36,18)一致，不经过浮点数，任意大小均无精度损失）
 func WeiToEthString(wei *big.Int) string {
 	if wei == nil {
 		return "0"
 	}
 
-	// 转换为 big.Float
-	fwei := new(big.Float).SetInt(wei)
-	// 除以 10^18
-	ethValue := new(big.Float).Quo(fwei, big.NewFloat(1e18))
-	// 格式化为字符串，保留 18 位小数
-	return ethValue.Text('f', 18)
+	digits := new(big.Int).Abs(wei).String()
+	if len(digits) <= EtherDecimals {
+		digits = strings.Repeat("0", EtherDecimals-len(digits)+1) + digits
+	}
+	result := digits[:len(digits)-EtherDecimals] + "." + digits[len(digits)-EtherDecimals:]
+	if wei.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// EthToWei 将ETH十进制字符串（如数据库中的"1.500000000000000000"）精确换算为Wei，超过18位小数视为无效
+func EthToWei(value string) (*big.Int, bool) {
+	return ParseUnits(value, EtherDecimals)
+}
+
+// GasFee 手续费 = gas单价 * gas用量（Wei）
+func GasFee(gasPrice *big.Int, gas int64) *big.Int {
+	if gasPrice == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Mul(gasPrice, big.NewInt(gas))
+}
+
+// DecimalToWei 将数据库decimal字符串（如"100.000000000000000000"）解析为Wei整数
+func DecimalToWei(value string) *big.Int {
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	wei, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return wei
+}
+
+// FormatUnits 按小数位数将最小单位整数格式化为十进制字符串（精确换算，去除末尾多余的0）
+func FormatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+	if decimals <= 0 {
+		return amount.String()
+	}
+
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if amount.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// ParseUnits 将十进制字符串按小数位数精确换算为最小单位整数（FormatUnits的逆运算，超出精度的小数位视为无效）
+//
+// 只接受无符号的十进制数（整数部分不能省略），符号、科学计数法等视为无效。
+func ParseUnits(value string, decimals int) (*big.Int, bool) {
+	integer, fraction, hasFraction := strings.Cut(value, ".")
+	if !isDigits(integer) || (hasFraction && !isDigits(fraction)) {
+		return nil, false
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > decimals {
+		return nil, false
+	}
+	amount, ok := new(big.Int).SetString(integer+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok {
+		return nil, false
+	}
+	return amount, true
+}
+
+// isDigits 非空且只包含十进制数字
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
 }
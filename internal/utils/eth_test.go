@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+// bigInt 从十进制字符串构造big.Int（测试数据）
+func bigInt(t *testing.T, value string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		t.Fatalf("invalid big int %q", value)
+	}
+	return n
+}
+
+func TestWeiToEthStringLargeValues(t *testing.T) {
+	tests := []struct {
+		name string
+		wei  string
+		eth  string
+	}{
+		{"one wei", "1", "0.000000000000000001"},
+		{"2^64", "18446744073709551616", "18.446744073709551616"},
+		{"2^64 + 1", "18446744073709551617", "18.446744073709551617"},
+		{"max uint256", "115792089237316195423570985008687907853269984665640564039457584007913129639935", "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+		{"max decimal(78,0)", "999999999999999999999999999999999999999999999999999999999999999999999999999999", "999999999999999999999999999999999999999999999999999999999999.999999999999999999"},
+		{"negative 2^64", "-18446744073709551616", "-18.446744073709551616"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wei := bigInt(t, tt.wei)
+			if got := WeiToEthString(wei); got != tt.eth {
+				t.Errorf("WeiToEthString(%s) = %s, want %s", tt.wei, got, tt.eth)
+			}
+			if wei.Sign() < 0 {
+				return
+			}
+			// 换算是精确的：反向换算得到原值
+			back, ok := EthToWei(tt.eth)
+			if !ok || back.Cmp(wei) != 0 {
+				t.Errorf("EthToWei(%s) = %v, %v, want %s", tt.eth, back, ok, tt.wei)
+			}
+		})
+	}
+}
+
+func TestGasFeeLargeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		gasPrice string
+		gas      int64
+		fee      string
+	}{
+		{"2^64 gas price", "18446744073709551616", 21000, "387381625547900583936000"},
+		{"2^200 gas price", "1606938044258990275541962092341162602522202993782792835301376", 21000, "33745698929438795786381203939164414652966262869438649541328896000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GasFee(bigInt(t, tt.gasPrice), tt.gas).String(); got != tt.fee {
+				t.Errorf("GasFee = %s, want %s", got, tt.fee)
+			}
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		decimals int
+		want     string
+		ok       bool
+	}{
+		{name: "zero", value: "0", decimals: 18, want: "0", ok: true},
+		{name: "zero with fraction", value: "0.000", decimals: 18, want: "0", ok: true},
+		{name: "whole", value: "1", decimals: 18, want: "1000000000000000000", ok: true},
+		{name: "fraction", value: "1.5", decimals: 18, want: "1500000000000000000", ok: true},
+		{name: "max precision", value: "0.000000000000000001", decimals: 18, want: "1", ok: true},
+		{name: "max precision large", value: "123456789.123456789123456789", decimals: 18, want: "123456789123456789123456789", ok: true},
+		{name: "trailing zeros beyond precision", value: "1.1000000000000000000", decimals: 18, want: "1100000000000000000", ok: true},
+		{name: "six decimals", value: "2.5", decimals: 6, want: "2500000", ok: true},
+		{name: "zero decimals", value: "42", decimals: 0, want: "42", ok: true},
+		{name: "too many decimals", value: "0.0000000000000000001", decimals: 18},
+		{name: "too many decimals for token", value: "1.0000001", decimals: 6},
+		{name: "fraction with zero decimals", value: "1.5", decimals: 0},
+		{name: "negative", value: "-1", decimals: 18},
+		{name: "negative fraction", value: "-0.5", decimals: 18},
+		{name: "plus sign", value: "+1", decimals: 18},
+		{name: "non-numeric", value: "abc", decimals: 18},
+		{name: "scientific notation", value: "1e18", decimals: 18},
+		{name: "hex", value: "0x10", decimals: 18},
+		{name: "empty", value: "", decimals: 18},
+		{name: "missing integer part", value: ".5", decimals: 18},
+		{name: "missing fraction digits", value: "1.", decimals: 18},
+		{name: "two points", value: "1.2.3", decimals: 18},
+		{name: "whitespace", value: " 1", decimals: 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseUnits(tt.value, tt.decimals)
+			if ok != tt.ok {
+				t.Fatalf("ParseUnits(%q, %d) ok = %v, want %v (got %v)", tt.value, tt.decimals, ok, tt.ok, got)
+			}
+			if ok && got.String() != tt.want {
+				t.Errorf("ParseUnits(%q, %d) = %s, want %s", tt.value, tt.decimals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		want     string
+	}{
+		{"zero", "0", 18, "0"},
+		{"smallest unit", "1", 18, "0.000000000000000001"},
+		{"whole", "1000000000000000000", 18, "1"},
+		{"trailing zeros trimmed", "1500000000000000000", 18, "1.5"},
+		{"max precision large", "123456789123456789123456789", 18, "123456789.123456789123456789"},
+		{"six decimals", "2500000", 6, "2.5"},
+		{"zero decimals", "42", 0, "42"},
+		{"negative", "-1500000000000000000", 18, "-1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := bigInt(t, tt.amount)
+			got := FormatUnits(amount, tt.decimals)
+			if got != tt.want {
+				t.Fatalf("FormatUnits(%s, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+			}
+			// 非负值可以精确换算回原值
+			if amount.Sign() >= 0 {
+				if back, ok := ParseUnits(got, tt.decimals); !ok || back.Cmp(amount) != 0 {
+					t.Errorf("ParseUnits(%s, %d) = %v, %v, want %s", got, tt.decimals, back, ok, tt.amount)
+				}
+			}
+		})
+	}
+	if got := FormatUnits(nil, 18); got != "0" {
+		t.Errorf("FormatUnits(nil) = %s, want 0", got)
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"math/big"
+
+	"crypto-wallet-api/internal/models"
+)
+
+// TransactionFees 计算交易费用明细（内部转账、代币入账等没有gas信息的交易返回nil）
+func TransactionFees(tx *models.Transaction) *models.TransactionFees {
+	if tx.Type == models.TxTypeInternal {
+		return nil
+	}
+	fees := &models.TransactionFees{}
+
+	// 1. 最高手续费与最多扣除的总额（代币转账的金额不是原生币，不计入总额）
+	gasPrice := DecimalToWei(tx.GasPrice)
+	if gasPrice.Sign() > 0 && tx.GasLimit > 0 {
+		maxFee := GasFee(gasPrice, tx.GasLimit)
+		fees.MaxFeeWei = maxFee.String()
+		fees.MaxFeeEth = WeiToEthString(maxFee)
+
+		amount := big.NewInt(0)
+		if tx.TokenAddress == "" {
+			amount, _ = EthToWei(tx.Amount)
+		}
+		if amount != nil {
+			total := amount.Add(amount, maxFee)
+			fees.TotalMaxCostWei = total.String()
+			fees.TotalMaxCostEth = WeiToEthString(total)
+		}
+	}
+
+	// 2. 实际手续费（回执未提供实际单价时按签名时的单价计算，legacy交易两者相同）
+	if tx.GasUsed > 0 {
+		price := gasPrice
+		if tx.EffectiveGasPrice != "" {
+			price = DecimalToWei(tx.EffectiveGasPrice)
+		}
+		if price.Sign() > 0 {
+			actualFee := GasFee(price, tx.GasUsed)
+			fees.ActualFeeWei = actualFee.String()
+			fees.ActualFeeEth = WeiToEthString(actualFee)
+		}
+	}
+
+	if fees.MaxFeeWei == "" && fees.ActualFeeWei == "" {
+		return nil
+	}
+	return fees
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"crypto-wallet-api/internal/models"
+)
+
+func TestTransactionFeesLargeValues(t *testing.T) {
+	tx := &models.Transaction{
+		Type:              models.TxTypeOnchain,
+		Amount:            "18.446744073709551616", // 2^64 Wei
+		GasPrice:          "18446744073709551616",
+		GasLimit:          21000,
+		GasUsed:           21000,
+		EffectiveGasPrice: "18446744073709551617",
+	}
+
+	fees := TransactionFees(tx)
+	if fees == nil {
+		t.Fatal("fees = nil, want breakdown")
+	}
+	want := models.TransactionFees{
+		MaxFeeWei:       "387381625547900583936000",
+		MaxFeeEth:       "387381.625547900583936000",
+		TotalMaxCostWei: "387400072291974293487616",
+		TotalMaxCostEth: "387400.072291974293487616",
+		ActualFeeWei:    "387381625547900583957000",
+		ActualFeeEth:    "387381.625547900583957000",
+	}
+	if *fees != want {
+		t.Errorf("fees = %+v, want %+v", *fees, want)
+	}
+}
@@ -1,64 +1,174 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/apperr"
+	"crypto-wallet-api/internal/i18n"
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/models"
 )
 
 // Response 统一响应结构
 type Response struct {
-	Code    int         `json:"code"`            // 业务状态码：0表示成功，非0表示失败
-	Message string      `json:"message"`         // 响应消息
-	Data    interface{} `json:"data,omitempty"`  // 响应数据
-	Error   string      `json:"error,omitempty"` // 错误详情（仅开发环境）
+	Code      int         `json:"code"`                 // 业务状态码：0表示成功，非0表示失败
+	Message   string      `json:"message"`              // 响应消息（按请求语言翻译）
+	Data      interface{} `json:"data,omitempty"`       // 响应数据
+	Error     string      `json:"error,omitempty"`      // 错误详情（仅开发环境）
+	RequestID string      `json:"request_id,omitempty"` // 请求ID（错误时返回，便于排查）
 }
 
 // 业务状态码定义
 const (
-	CodeSuccess             = 0     // 成功
-	CodeInvalidParams       = 10001 // 参数错误
-	CodeUnauthorized        = 10002 // 未授权
-	CodeForbidden           = 10003 // 禁止访问
-	CodeNotFound            = 10004 // 资源不存在
-	CodeInternalError       = 10005 // 内部错误
-	CodeDatabaseError       = 10006 // 数据库错误
-	CodeBlockchainError     = 10007 // 区块链交互错误
-	CodeInsufficientBalance = 10008 // 余额不足
-	CodeDuplicateResource   = 10009 // 资源重复
+	CodeSuccess               = 0     // 成功
+	CodeInvalidParams         = 10001 // 参数错误
+	CodeUnauthorized          = 10002 // 未授权
+	CodeForbidden             = 10003 // 禁止访问
+	CodeNotFound              = 10004 // 资源不存在
+	CodeInternalError         = 10005 // 内部错误
+	CodeDatabaseError         = 10006 // 数据库错误
+	CodeBlockchainError       = 10007 // 区块链交互错误
+	CodeInsufficientBalance   = 10008 // 余额不足
+	CodeDuplicateResource     = 10009 // 资源重复
+	CodeAddressNotWhitelisted = 10010 // 收款地址不在白名单中或尚未生效
+	CodeDailyLimitExceeded    = 10011 // 超出每日转出限额
+	CodePassphraseRequired    = 10012 // 钱包设置了口令，请求未提供
+	CodeInvalidPassphrase     = 10013 // 钱包口令错误
+	CodeZeroAddress           = 10014 // 收款地址为零地址
+	CodeSelfTransfer          = 10015 // 收款地址与发送钱包相同
+	CodeApprovalRequired      = 10016 // 金额超过审批阈值，需通过审批流程发送
+	CodeNotAwaitingApproval   = 10017 // 交易不处于等待审批状态或审批已过期
+	CodeENSResolutionFailed   = 10018 // ENS名称无法解析为地址
+	CodeFeatureDisabled       = 10019 // 功能暂时关闭（维护期间）
+	CodeWalletArchived        = 10020 // 钱包已归档，取消归档后才能发送交易
+	CodeChainUnhealthy        = 10021 // 链节点长时间未同步到新区块，暂停发送交易
+	CodeInvalidPassword       = 10022 // 当前登录密码错误
+	CodeWeakPassword          = 10023 // 新密码不满足复杂度策略
+	CodeRateLimited           = 10024 // 超出限流（响应头X-RateLimit-*与Retry-After给出重试时间）
+	CodeChainNotAllowed       = 10025 // 链被本部署禁用，不能创建钱包或发送交易
+	CodeUpstreamTimeout       = 10026 // 链节点、Redis或数据库在请求时限内未响应
 )
 
+// Codes 全部业务状态码（每个状态码在各语言包中都有默认消息，见CodeMessageKey）
+var Codes = []int{
+	CodeSuccess, CodeInvalidParams, CodeUnauthorized, CodeForbidden, CodeNotFound,
+	CodeInternalError, CodeDatabaseError, CodeBlockchainError, CodeInsufficientBalance,
+	CodeDuplicateResource, CodeAddressNotWhitelisted, CodeDailyLimitExceeded,
+	CodePassphraseRequired, CodeInvalidPassphrase, CodeZeroAddress, CodeSelfTransfer,
+	CodeApprovalRequired, CodeNotAwaitingApproval, CodeENSResolutionFailed,
+	CodeFeatureDisabled, CodeWalletArchived, CodeChainUnhealthy, CodeInvalidPassword,
+	CodeWeakPassword, CodeRateLimited, CodeChainNotAllowed, CodeUpstreamTimeout,
+}
+
+// CodeMessageKey 业务状态码默认消息的消息键
+func CodeMessageKey(code int) string {
+	return "code." + strconv.Itoa(code)
+}
+
+// CheckTranslations 检查每个业务状态码在全部语言包中都有默认消息（启动时在i18n.Load之后调用）
+func CheckTranslations() error {
+	for _, lang := range i18n.Languages {
+		for _, code := range Codes {
+			if !i18n.Has(lang, CodeMessageKey(code)) {
+				return fmt.Errorf("%s bundle has no message for business code %d", lang, code)
+			}
+		}
+	}
+	return nil
+}
+
+// Language 当前请求的响应语言：?lang=优先，其次Accept-Language，均不支持时使用默认语言
+func Language(c *gin.Context) string {
+	if lang := i18n.Match(c.Query("lang")); lang != "" {
+		return lang
+	}
+	if lang := i18n.Match(c.GetHeader("Accept-Language")); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
+
+// localize 将消息键翻译为请求语言，并标注响应语言
+func localize(c *gin.Context, message string) string {
+	lang := Language(c)
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return i18n.T(lang, message)
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
 		Code:    CodeSuccess,
-		Message: "success",
+		Message: localize(c, CodeMessageKey(CodeSuccess)),
 		Data:    data,
 	})
 }
 
-// SuccessWithMessage 成功响应（自定义消息）
+// SuccessWithMessage 成功响应（自定义消息，message为消息键）
 func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
 	c.JSON(http.StatusOK, Response{
 		Code:    CodeSuccess,
-		Message: message,
+		Message: localize(c, message),
+		Data:    data,
+	})
+}
+
+// Accepted 已受理响应（请求已接收，需后续处理才能完成）
+func Accepted(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusAccepted, Response{
+		Code:    CodeSuccess,
+		Message: localize(c, message),
 		Data:    data,
 	})
 }
 
-// ErrorJson 错误响应
+// ErrorJson 错误响应（message为消息键，不是消息键的动态消息原样返回）
 func ErrorJson(c *gin.Context, httpStatus int, code int, message string) {
 	c.JSON(httpStatus, Response{
-		Code:    code,
-		Message: message,
+		Code:      code,
+		Message:   localize(c, message),
+		RequestID: c.GetString("request_id"),
+	})
+}
+
+// ErrorWithData 错误响应（附带业务数据）
+func ErrorWithData(c *gin.Context, httpStatus int, code int, message string, data interface{}) {
+	c.JSON(httpStatus, Response{
+		Code:      code,
+		Message:   localize(c, message),
+		Data:      data,
+		RequestID: c.GetString("request_id"),
 	})
 }
 
-// ErrorWithDetail 错误响应（包含详细错误信息）
+// ErrorWithDetail 错误响应（message必须是可以返回给客户端的安全消息或其消息键，err只记录日志，仅开发环境附带在响应中）
 func ErrorWithDetail(c *gin.Context, httpStatus int, code int, message string, err error) {
+	// 下游依赖超时的服务端错误统一返回504，客户端可稍后重试
+	if httpStatus >= http.StatusInternalServerError && apperr.IsTimeout(err) {
+		httpStatus, code, message = http.StatusGatewayTimeout, CodeUpstreamTimeout, CodeMessageKey(CodeUpstreamTimeout)
+	}
+
 	resp := Response{
-		Code:    code,
-		Message: message,
+		Code:      code,
+		Message:   localize(c, message),
+		RequestID: c.GetString("request_id"),
+	}
+
+	// 服务端错误记录底层错误（日志附带请求ID，便于按响应中的request_id排查）
+	if httpStatus >= http.StatusInternalServerError && err != nil {
+		logger.WithCtx(c.Request.Context()).Error("HTTP request failed",
+			zap.Int("status", httpStatus),
+			zap.Int("code", code),
+			zap.String("message", message),
+			zap.Error(err),
+		)
 	}
 
 	// 开发环境返回详细错误
@@ -74,6 +184,20 @@ func BadRequest(c *gin.Context, message string) {
 	ErrorJson(c, http.StatusBadRequest, CodeInvalidParams, message)
 }
 
+// BadRequestWithFields 400错误（附带字段级错误，字段名 -> 原因）
+func BadRequestWithFields(c *gin.Context, message string, fields map[string]string) {
+	if len(fields) == 0 {
+		BadRequest(c, message)
+		return
+	}
+	ErrorWithData(c, http.StatusBadRequest, CodeInvalidParams, message, &models.ValidationErrorData{Fields: fields})
+}
+
+// BindError 参数绑定失败的400错误（能定位到字段时返回字段级错误）
+func BindError(c *gin.Context, message string, err error) {
+	BadRequestWithFields(c, message, FieldErrors(err))
+}
+
 // Unauthorized 401错误
 func Unauthorized(c *gin.Context, message string) {
 	ErrorJson(c, http.StatusUnauthorized, CodeUnauthorized, message)
@@ -91,15 +215,36 @@ func NotFound(c *gin.Context, message string) {
 
 // InternalError 500错误
 func InternalError(c *gin.Context, err error) {
-	ErrorWithDetail(c, http.StatusInternalServerError, CodeInternalError, "internal server error", err)
+	ErrorWithDetail(c, http.StatusInternalServerError, CodeInternalError, CodeMessageKey(CodeInternalError), err)
 }
 
 // DatabaseError 数据库错误
 func DatabaseError(c *gin.Context, err error) {
-	ErrorWithDetail(c, http.StatusInternalServerError, CodeDatabaseError, "database error", err)
+	ErrorWithDetail(c, http.StatusInternalServerError, CodeDatabaseError, CodeMessageKey(CodeDatabaseError), err)
+}
+
+// AppError 按类型化错误（apperr）返回响应：返回其安全消息与对应业务码，其余错误一律按500处理
+func AppError(c *gin.Context, err error) {
+	if _, ok := apperr.Message(err); !ok {
+		InternalError(c, err)
+		return
+	}
+	message := apperr.MessageKey(err)
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		ErrorWithDetail(c, http.StatusNotFound, CodeNotFound, message, err)
+	case errors.Is(err, apperr.ErrInvalid):
+		ErrorWithDetail(c, http.StatusBadRequest, CodeInvalidParams, message, err)
+	case errors.Is(err, apperr.ErrConflict):
+		ErrorWithDetail(c, http.StatusConflict, CodeDuplicateResource, message, err)
+	case errors.Is(err, apperr.ErrForbidden):
+		ErrorWithDetail(c, http.StatusForbidden, CodeForbidden, message, err)
+	default:
+		InternalError(c, err)
+	}
 }
 
 // BlockchainError 区块链错误
 func BlockchainError(c *gin.Context, err error) {
-	ErrorWithDetail(c, http.StatusBadGateway, CodeBlockchainError, "blockchain interaction error", err)
+	ErrorWithDetail(c, http.StatusBadGateway, CodeBlockchainError, CodeMessageKey(CodeBlockchainError), err)
 }
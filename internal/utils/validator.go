@@ -1,28 +1,195 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+
+	"crypto-wallet-api/internal/blockchain"
+	"crypto-wallet-api/internal/models"
 )
 
 // CustomValidator 自定义验证器
 var CustomValidator *validator.Validate
 
+var (
+	// ethAddressPattern 以太坊地址格式：0x开头，后跟40个十六进制字符（大小写均可）
+	ethAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	// txHashPattern 链上交易哈希格式：0x开头，后跟64个十六进制字符（大小写均可）
+	txHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	// decimalAmountPattern 非负十进制金额（主单位），整数与小数部分均不超过18位，与数据库decimal(36,18)一致
+	decimalAmountPattern = regexp.MustCompile(`^[0-9]{1,18}(\.[0-9]{1,18})?$`)
+)
+
 // InitValidator 初始化验证器
 func InitValidator() {
 	CustomValidator = validator.New()
+	CustomValidator.RegisterTagNameFunc(fieldTagName)
 
 	// 注册自定义验证规则
 	CustomValidator.RegisterValidation("eth_addr", validateEthAddress)
+	CustomValidator.RegisterValidation("eth_addr_or_ens", validateEthAddressOrENS)
+	CustomValidator.RegisterValidation("chain_id", validateChainID)
+	CustomValidator.RegisterValidation("tx_hash", validateTxHash)
+	CustomValidator.RegisterValidation("decimal_amount", validateDecimalAmount)
+
+	// 同步注册到Gin的绑定验证器（binding标签使用）
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldTagName)
+		v.RegisterValidation("eth_addr", validateEthAddress)
+		v.RegisterValidation("eth_addr_or_ens", validateEthAddressOrENS)
+		v.RegisterValidation("chain_id", validateChainID)
+		v.RegisterValidation("tx_hash", validateTxHash)
+		v.RegisterValidation("decimal_amount", validateDecimalAmount)
+	}
+}
+
+// IsEthAddress 是否为以太坊地址格式（用于路径参数等不经过binding校验的输入）
+func IsEthAddress(s string) bool {
+	return ethAddressPattern.MatchString(s)
+}
+
+// IsTxHash 是否为链上交易哈希格式（用于路径参数与队列消息等不经过binding校验的输入）
+func IsTxHash(s string) bool {
+	return txHashPattern.MatchString(s)
 }
 
 // validateEthAddress 验证以太坊地址格式
 func validateEthAddress(fl validator.FieldLevel) bool {
-	address := fl.Field().String()
-	// 以太坊地址格式：0x开头，后跟40个十六进制字符
-	matched, _ := regexp.MatchString(`^0x[0-9a-fA-F]{40}$`, address)
-	return matched
+	return IsEthAddress(fl.Field().String())
+}
+
+// validateTxHash 验证链上交易哈希格式
+func validateTxHash(fl validator.FieldLevel) bool {
+	return IsTxHash(fl.Field().String())
+}
+
+// validateDecimalAmount 验证非负十进制金额（用于金额范围筛选）
+func validateDecimalAmount(fl validator.FieldLevel) bool {
+	return decimalAmountPattern.MatchString(fl.Field().String())
+}
+
+// validateEthAddressOrENS 验证以太坊地址或ENS名称（如vitalik.eth，由服务层解析）
+func validateEthAddressOrENS(fl validator.FieldLevel) bool {
+	return validateEthAddress(fl) || blockchain.IsENSName(fl.Field().String())
+}
+
+// validateChainID 验证链ID为已配置的链（见models.SetChains）
+func validateChainID(fl validator.FieldLevel) bool {
+	return models.SupportedChain(int(fl.Field().Int()))
+}
+
+// fieldTagName 校验错误中使用的字段名（优先json标签，其次form标签，与客户端提交的参数名一致）
+func fieldTagName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FieldErrors 从参数绑定错误中提取字段级错误（字段名 -> 原因），无法定位到字段时返回nil
+func FieldErrors(err error) map[string]string {
+	// 1. 校验规则不满足
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make(map[string]string, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields[fieldPath(fe)] = fieldErrorMessage(fe)
+		}
+		return fields
+	}
+
+	// 2. JSON字段类型不匹配
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string]string{typeErr.Field: "must be of type " + typeErr.Type.String()}
+	}
+
+	return nil
+}
+
+// fieldPath 字段路径（去掉顶层结构体名与嵌入结构体名，如preferences[0].type、min_amount）
+func fieldPath(fe validator.FieldError) string {
+	names := strings.Split(fe.Namespace(), ".")
+	goNames := strings.Split(fe.StructNamespace(), ".")
+	if len(names) < 2 || len(names) != len(goNames) {
+		return fe.Field()
+	}
+
+	// 没有json/form标签的中间层级只有嵌入结构体（参数名沿用Go字段名），其字段直接属于外层参数
+	path := make([]string, 0, len(names)-1)
+	for i := 1; i < len(names); i++ {
+		if i < len(names)-1 && names[i] == goNames[i] {
+			continue
+		}
+		path = append(path, names[i])
+	}
+	return strings.Join(path, ".")
+}
+
+// fieldErrorMessage 校验规则对应的错误说明
+func fieldErrorMessage(fe validator.FieldError) string {
+	// 长度类规则对字符串、切片与数值的含义不同
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "len":
+		return fmt.Sprintf("must be exactly %s%s", fe.Param(), unit)
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "startswith":
+		return fmt.Sprintf("must start with %q", fe.Param())
+	case "unique":
+		return "must not contain duplicates"
+	case "eth_addr":
+		return "must be a valid Ethereum address"
+	case "eth_addr_or_ens":
+		return "must be a valid Ethereum address or ENS name"
+	case "chain_id":
+		return "must be a supported chain ID"
+	case "tx_hash":
+		return "must be a valid transaction hash"
+	case "decimal_amount":
+		return "must be a non-negative decimal with at most 18 integer and 18 fractional digits"
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
 }
 
 // ValidateStruct 验证结构体
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsTxHash(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"lowercase", hash, true},
+		{"uppercase hex", "0x" + strings.Repeat("AB", 32), true},
+		{"mixed case", "0x" + strings.Repeat("aB", 32), true},
+		{"uppercase prefix", "0X" + strings.Repeat("ab", 32), false},
+		{"missing 0x", strings.Repeat("ab", 32), false},
+		{"missing 0x with 66 characters", strings.Repeat("ab", 33), false},
+		{"too short", hash[:65], false},
+		{"too long", hash + "a", false},
+		{"address length", "0x" + strings.Repeat("ab", 20), false},
+		{"non-hex character", hash[:65] + "g", false},
+		{"whitespace", hash[:65] + " ", false},
+		{"only prefix", "0x", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTxHash(tt.value); got != tt.want {
+				t.Errorf("IsTxHash(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEthAddress(t *testing.T) {
+	address := "0x" + strings.Repeat("ab", 20)
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"lowercase", address, true},
+		{"checksummed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"missing 0x", strings.Repeat("ab", 20), false},
+		{"too short", address[:41], false},
+		{"too long", address + "a", false},
+		{"non-hex character", address[:41] + "z", false},
+		{"ens name", "vitalik.eth", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEthAddress(tt.value); got != tt.want {
+				t.Errorf("IsEthAddress(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
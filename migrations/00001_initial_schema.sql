@@ -0,0 +1,173 @@
+-- 初始表结构（与原AutoMigrate生成的结构一致）
+-- 使用IF NOT EXISTS，已由AutoMigrate建表的库可直接执行以纳入版本管理
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "username" varchar(50) NOT NULL,
+    "email" varchar(100) NOT NULL,
+    "password_hash" varchar(255) NOT NULL,
+    "token_version" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username")
+);
+
+CREATE TABLE IF NOT EXISTS "wallets" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "address" varchar(42) NOT NULL,
+    "private_key_encrypted" text NOT NULL,
+    "chain_id" bigint NOT NULL,
+    "balance" decimal(36,18) DEFAULT '0',
+    "name" varchar(100),
+    "whitelist_enabled" boolean NOT NULL DEFAULT false,
+    "daily_limit_wei" varchar(78),
+    "daily_tx_limit" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_wallets" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "uni_wallets_address" UNIQUE ("address")
+);
+CREATE INDEX IF NOT EXISTS "idx_wallets_user_id" ON "wallets" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_wallets_address" ON "wallets" ("address");
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "tx_hash" varchar(66) NOT NULL,
+    "from_address" varchar(42) NOT NULL,
+    "to_address" varchar(42) NOT NULL,
+    "amount" decimal(36,18) NOT NULL,
+    "gas_price" decimal(36,18),
+    "gas_used" bigint,
+    "gas_limit" bigint,
+    "nonce" bigint,
+    "status" varchar(20) NOT NULL,
+    "block_number" bigint,
+    "chain_id" bigint NOT NULL,
+    "error_msg" text,
+    "method_name" varchar(100),
+    "method_args" text,
+    "recurring_payment_id" bigint,
+    "created_at" timestamptz,
+    "confirmed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_wallets_transactions" FOREIGN KEY ("wallet_id") REFERENCES "wallets"("id"),
+    CONSTRAINT "uni_transactions_tx_hash" UNIQUE ("tx_hash")
+);
+CREATE INDEX IF NOT EXISTS "idx_transactions_wallet_id" ON "transactions" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_tx_hash" ON "transactions" ("tx_hash");
+CREATE INDEX IF NOT EXISTS "idx_transactions_status" ON "transactions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_transactions_recurring_payment_id" ON "transactions" ("recurring_payment_id");
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" varchar(100),
+    "prefix" varchar(16) NOT NULL,
+    "key_hash" varchar(64) NOT NULL,
+    "scope" varchar(20) NOT NULL,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_api_keys_prefix" UNIQUE ("prefix")
+);
+CREATE INDEX IF NOT EXISTS "idx_api_keys_user_id" ON "api_keys" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "contacts" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "address" varchar(42) NOT NULL,
+    "chain_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_contacts_user_address_chain" ON "contacts" ("user_id","address","chain_id");
+
+CREATE TABLE IF NOT EXISTS "wallet_whitelist_entries" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "address" varchar(42) NOT NULL,
+    "label" varchar(100),
+    "activates_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_whitelist_wallet_address" ON "wallet_whitelist_entries" ("wallet_id","address");
+
+CREATE TABLE IF NOT EXISTS "spend_ledger" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "amount" numeric(78,0) NOT NULL,
+    "tx_hash" varchar(66),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_spend_ledger_wallet_created" ON "spend_ledger" ("wallet_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+    "id" bigserial,
+    "queue" varchar(100) NOT NULL,
+    "payload" text NOT NULL,
+    "headers" text,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "last_error" text,
+    "sent_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_sent_at" ON "outbox_events" ("sent_at");
+
+CREATE TABLE IF NOT EXISTS "recurring_payments" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "from_address" varchar(42) NOT NULL,
+    "to_address" varchar(42) NOT NULL,
+    "amount" varchar(78) NOT NULL,
+    "chain_id" bigint NOT NULL,
+    "schedule" varchar(32) NOT NULL,
+    "next_run_at" timestamptz NOT NULL,
+    "end_at" timestamptz,
+    "max_runs" bigint NOT NULL DEFAULT 0,
+    "run_count" bigint NOT NULL DEFAULT 0,
+    "consecutive_failures" bigint NOT NULL DEFAULT 0,
+    "last_error" text,
+    "last_run_at" timestamptz,
+    "status" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_recurring_payments_user_id" ON "recurring_payments" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_recurring_payments_wallet_id" ON "recurring_payments" ("wallet_id");
+CREATE INDEX IF NOT EXISTS "idx_recurring_payments_next_run_at" ON "recurring_payments" ("next_run_at");
+CREATE INDEX IF NOT EXISTS "idx_recurring_payments_status" ON "recurring_payments" ("status");
+
+CREATE TABLE IF NOT EXISTS "wallet_activities" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "type" varchar(50) NOT NULL,
+    "details" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_wallet_activities_feed" ON "wallet_activities" ("wallet_id","created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "wallet_activities";
+DROP TABLE IF EXISTS "recurring_payments";
+DROP TABLE IF EXISTS "outbox_events";
+DROP TABLE IF EXISTS "spend_ledger";
+DROP TABLE IF EXISTS "wallet_whitelist_entries";
+DROP TABLE IF EXISTS "contacts";
+DROP TABLE IF EXISTS "api_keys";
+DROP TABLE IF EXISTS "transactions";
+DROP TABLE IF EXISTS "wallets";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,15 @@
+-- 交易列表、状态统计、转入查询与按链筛选钱包的复合索引
+
+-- +goose Up
+CREATE INDEX IF NOT EXISTS "idx_transactions_wallet_created" ON "transactions" ("wallet_id","created_at" DESC);
+CREATE INDEX IF NOT EXISTS "idx_transactions_wallet_status" ON "transactions" ("wallet_id","status");
+CREATE INDEX IF NOT EXISTS "idx_transactions_status_created" ON "transactions" ("status","created_at");
+CREATE INDEX IF NOT EXISTS "idx_transactions_to_address_lower" ON "transactions" (LOWER(to_address));
+CREATE INDEX IF NOT EXISTS "idx_wallets_user_chain" ON "wallets" ("user_id","chain_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_wallets_user_chain";
+DROP INDEX IF EXISTS "idx_transactions_to_address_lower";
+DROP INDEX IF EXISTS "idx_transactions_status_created";
+DROP INDEX IF EXISTS "idx_transactions_wallet_status";
+DROP INDEX IF EXISTS "idx_transactions_wallet_created";
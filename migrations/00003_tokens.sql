@@ -0,0 +1,42 @@
+-- 代币元数据与用户关注列表，预置主网常用代币
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "tokens" (
+    "id" bigserial,
+    "chain_id" bigint NOT NULL,
+    "contract_address" varchar(42) NOT NULL,
+    "symbol" varchar(32) NOT NULL,
+    "name" varchar(100),
+    "decimals" bigint NOT NULL,
+    "logo_url" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tokens_chain_contract" ON "tokens" ("chain_id","contract_address");
+
+CREATE TABLE IF NOT EXISTS "token_watches" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_token_watches_token" FOREIGN KEY ("token_id") REFERENCES "tokens"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_token_watches_user_token" ON "token_watches" ("user_id","token_id");
+
+INSERT INTO "tokens" ("chain_id","contract_address","symbol","name","decimals","created_at","updated_at") VALUES
+    (1,  '0xdAC17F958D2ee523a2206206994597C13D831ec7', 'USDT', 'Tether USD',     6,  NOW(), NOW()),
+    (1,  '0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48', 'USDC', 'USD Coin',       6,  NOW(), NOW()),
+    (1,  '0x6B175474E89094C44Da98b954EedeAC495271d0F', 'DAI',  'Dai Stablecoin', 18, NOW(), NOW()),
+    (1,  '0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2', 'WETH', 'Wrapped Ether',  18, NOW(), NOW()),
+    (1,  '0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599', 'WBTC', 'Wrapped BTC',    8,  NOW(), NOW()),
+    (56, '0x55d398326f99059fF775485246999027B3197955', 'USDT', 'Tether USD',     18, NOW(), NOW()),
+    (56, '0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d', 'USDC', 'USD Coin',       18, NOW(), NOW()),
+    (56, '0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56', 'BUSD', 'BUSD Token',     18, NOW(), NOW()),
+    (56, '0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c', 'WBNB', 'Wrapped BNB',    18, NOW(), NOW())
+ON CONFLICT ("chain_id","contract_address") DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS "token_watches";
+DROP TABLE IF EXISTS "tokens";
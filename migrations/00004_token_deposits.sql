@@ -0,0 +1,27 @@
+-- 代币入账：交易表增加代币字段，同一交易哈希可对应多条代币入账记录（按日志序号区分），并记录链上扫描进度
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "token_address" varchar(42);
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "token_symbol" varchar(32);
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "log_index" bigint;
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS "uni_transactions_tx_hash";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transactions_hash_log" ON "transactions" ("tx_hash", COALESCE("log_index", -1));
+
+CREATE TABLE IF NOT EXISTS "chain_cursors" (
+    "id" bigserial,
+    "chain_id" bigint NOT NULL,
+    "topic" varchar(100) NOT NULL,
+    "block_number" bigint NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_chain_cursors_chain_topic" ON "chain_cursors" ("chain_id","topic");
+
+-- +goose Down
+DROP TABLE IF EXISTS "chain_cursors";
+DELETE FROM "transactions" WHERE "log_index" IS NOT NULL;
+DROP INDEX IF EXISTS "idx_transactions_hash_log";
+ALTER TABLE "transactions" ADD CONSTRAINT "uni_transactions_tx_hash" UNIQUE ("tx_hash");
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "log_index";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "token_symbol";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "token_address";
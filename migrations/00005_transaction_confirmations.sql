@@ -0,0 +1,8 @@
+-- 交易确认深度：记录已确认区块数，新增confirming状态（已打包但未达到确认深度）
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "confirmations" bigint NOT NULL DEFAULT 0;
+
+-- +goose Down
+UPDATE "transactions" SET "status" = 'pending' WHERE "status" = 'confirming';
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "confirmations";
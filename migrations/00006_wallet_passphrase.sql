@@ -0,0 +1,11 @@
+-- 钱包私钥可选的用户口令保护层（非托管模式）：记录密钥派生算法、参数与盐值
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "key_kdf" varchar(20);
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "key_kdf_params" varchar(100);
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "key_kdf_salt" varchar(64);
+
+-- +goose Down
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "key_kdf_salt";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "key_kdf_params";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "key_kdf";
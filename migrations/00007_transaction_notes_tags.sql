@@ -0,0 +1,18 @@
+-- 交易备注与标签：备注保存在交易表（不进入队列消息），标签使用关联表以支持按标签筛选
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "note" varchar(500);
+
+CREATE TABLE IF NOT EXISTS "transaction_tags" (
+    "id" bigserial,
+    "transaction_id" bigint NOT NULL,
+    "tag" varchar(32) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_transactions_tags" FOREIGN KEY ("transaction_id") REFERENCES "transactions"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_transaction_tags_tag" ON "transaction_tags" ("tag");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transaction_tags_tx_tag" ON "transaction_tags" ("transaction_id","tag");
+
+-- +goose Down
+DROP TABLE IF EXISTS "transaction_tags";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "note";
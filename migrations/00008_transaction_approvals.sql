@@ -0,0 +1,43 @@
+-- 大额转账审批：钱包审批阈值与审批人、交易审批记录；待审批交易尚未签名（交易哈希为空），哈希唯一索引仅约束已签名交易
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "approval_threshold_wei" varchar(78);
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "required_approvals" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "required_approvals" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "approval_expires_at" timestamptz;
+
+DROP INDEX IF EXISTS "idx_transactions_hash_log";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transactions_signed_hash_log" ON "transactions" ("tx_hash", COALESCE("log_index", -1)) WHERE "tx_hash" <> '';
+
+CREATE TABLE IF NOT EXISTS "wallet_approvers" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_wallets_approvers" FOREIGN KEY ("wallet_id") REFERENCES "wallets"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_wallet_approvers_user_id" ON "wallet_approvers" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wallet_approvers_wallet_user" ON "wallet_approvers" ("wallet_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "transaction_approvals" (
+    "id" bigserial,
+    "transaction_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "decision" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_transactions_approvals" FOREIGN KEY ("transaction_id") REFERENCES "transactions"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transaction_approvals_tx_user" ON "transaction_approvals" ("transaction_id","user_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "transaction_approvals";
+DROP TABLE IF EXISTS "wallet_approvers";
+DELETE FROM "transactions" WHERE "tx_hash" = '';
+DROP INDEX IF EXISTS "idx_transactions_signed_hash_log";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transactions_hash_log" ON "transactions" ("tx_hash", COALESCE("log_index", -1));
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "approval_expires_at";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "required_approvals";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "required_approvals";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "approval_threshold_wei";
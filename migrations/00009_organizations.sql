@@ -0,0 +1,32 @@
+-- 组织与团队钱包：组织、成员角色（owner/admin/member/viewer），钱包可归属组织而非个人
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "organizations" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "member_send_limit_wei" varchar(78),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "org_memberships" (
+    "id" bigserial,
+    "org_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "role" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_org_memberships_organization" FOREIGN KEY ("org_id") REFERENCES "organizations"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_org_memberships_user_id" ON "org_memberships" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_org_memberships_org_user" ON "org_memberships" ("org_id","user_id");
+
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "org_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_wallets_org_id" ON "wallets" ("org_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_wallets_org_id";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "org_id";
+DROP TABLE IF EXISTS "org_memberships";
+DROP TABLE IF EXISTS "organizations";
@@ -0,0 +1,32 @@
+-- 通知中心：用户按通知类型设置偏好（启用与站外渠道），站内通知记录已读状态
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "type" varchar(50) NOT NULL,
+    "enabled" boolean NOT NULL,
+    "channel" varchar(20) NOT NULL,
+    "webhook_url" varchar(500),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_notification_prefs_user_type" ON "notification_preferences" ("user_id","type");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "type" varchar(50) NOT NULL,
+    "title" varchar(200) NOT NULL,
+    "message" text,
+    "data" text,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_created" ON "notifications" ("user_id","created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "notification_preferences";
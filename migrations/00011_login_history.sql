@@ -0,0 +1,20 @@
+-- 登录记录：记录每次登录的IP与设备指纹（User-Agent摘要），用于新设备提醒与会话管理
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "login_history" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "jti" varchar(36) NOT NULL,
+    "token_version" bigint NOT NULL,
+    "ip" varchar(45) NOT NULL,
+    "device_hash" varchar(64) NOT NULL,
+    "new_device" boolean NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_login_history_user_created" ON "login_history" ("user_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_login_history_jti" ON "login_history" ("jti");
+
+-- +goose Down
+DROP TABLE IF EXISTS "login_history";
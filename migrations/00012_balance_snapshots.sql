@@ -0,0 +1,19 @@
+-- 余额快照：按小时与天记录钱包余额，用于余额曲线（小时快照定期降采样为天快照）
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "balance_snapshots" (
+    "id" bigserial,
+    "wallet_id" bigint NOT NULL,
+    "resolution" varchar(10) NOT NULL,
+    "taken_at" timestamptz NOT NULL,
+    "balance" decimal(36,18) NOT NULL,
+    "usd_value" decimal(24,2),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_balance_snapshots_bucket" ON "balance_snapshots" ("wallet_id","resolution","taken_at");
+CREATE INDEX IF NOT EXISTS "idx_balance_snapshots_taken_at" ON "balance_snapshots" ("taken_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "balance_snapshots";
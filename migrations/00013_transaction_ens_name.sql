@@ -0,0 +1,7 @@
+-- 交易收款方ENS名称：以ENS名称发起转账时记录名称（to_address保存解析结果）
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "to_ens_name" varchar(255);
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "to_ens_name";
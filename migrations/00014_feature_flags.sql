@@ -0,0 +1,22 @@
+-- 功能开关：用户角色（管理员可修改功能开关）与开关变更审计记录（开关状态本身保存在Redis中）
+
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" varchar(20) NOT NULL DEFAULT 'user';
+
+CREATE TABLE IF NOT EXISTS "feature_flag_changes" (
+    "id" bigserial,
+    "flag" varchar(100) NOT NULL,
+    "previous" boolean NOT NULL,
+    "enabled" boolean NOT NULL,
+    "message" varchar(255),
+    "changed_by" bigint NOT NULL,
+    "ip" varchar(45) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_changes_flag" ON "feature_flag_changes" ("flag");
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_changes_created_at" ON "feature_flag_changes" ("created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "feature_flag_changes";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "role";
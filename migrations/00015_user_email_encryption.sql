@@ -0,0 +1,15 @@
+-- 用户邮箱加密：邮箱以AES-GCM密文保存（密文随机，不能再作唯一约束），新增HMAC摘要列用于唯一约束与检索。
+-- 已有用户的明文邮箱需执行 `migrate backfill-emails` 回填，回填前仍可按明文登录
+
+-- +goose Up
+ALTER TABLE "users" ALTER COLUMN "email" TYPE varchar(255);
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "uni_users_email";
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_hash" varchar(64);
+ALTER TABLE "users" ADD CONSTRAINT "uni_users_email_hash" UNIQUE ("email_hash");
+
+-- +goose Down
+-- 回滚前需确保邮箱已恢复为明文，否则唯一约束与登录查询将失效
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "uni_users_email_hash";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_hash";
+ALTER TABLE "users" ADD CONSTRAINT "uni_users_email" UNIQUE ("email");
+ALTER TABLE "users" ALTER COLUMN "email" TYPE varchar(100);
@@ -0,0 +1,11 @@
+-- 钱包标签、颜色与归档状态：归档钱包保留私钥，但不在默认列表中展示、不参与后台余额刷新、不能发送交易
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "label" varchar(50);
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "color" varchar(7);
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "archived" boolean NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "archived";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "color";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "label";
@@ -0,0 +1,10 @@
+-- 交易确认深度按钱包设置：wallets.confirmations_required为0时使用链配置，
+-- 交易广播时记录实际使用的确认深度（已有交易为0，按链配置判断）
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "confirmations_required" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "confirmations_required" bigint NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "confirmations_required";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "confirmations_required";
@@ -0,0 +1,10 @@
+-- 内部转账：本系统钱包之间的转账只更新账本余额，不上链。
+-- wallets.balance为账本余额（链上余额 + 内部转账净额），internal_net_wei记录尚未在链上结算的净额
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "internal_net_wei" decimal(78,0) NOT NULL DEFAULT '0';
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "type" varchar(20) NOT NULL DEFAULT 'onchain';
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "type";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "internal_net_wei";
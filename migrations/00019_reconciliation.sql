@@ -0,0 +1,36 @@
+-- 余额对账：Worker定时比较数据库中的钱包余额与链上余额并修正，超过阈值的差异记录到reconciliation_reports
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "reconciliation_runs" (
+    "id" bigserial,
+    "chain_id" bigint NOT NULL,
+    "threshold_wei" decimal(78,0) NOT NULL DEFAULT '0',
+    "wallets_checked" bigint NOT NULL DEFAULT 0,
+    "corrected" bigint NOT NULL DEFAULT 0,
+    "discrepancies" bigint NOT NULL DEFAULT 0,
+    "failed" bigint NOT NULL DEFAULT 0,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reconciliation_runs_chain_started" ON "reconciliation_runs" ("chain_id","started_at" DESC);
+
+CREATE TABLE IF NOT EXISTS "reconciliation_reports" (
+    "id" bigserial,
+    "run_id" bigint NOT NULL,
+    "wallet_id" bigint NOT NULL,
+    "address" varchar(42) NOT NULL,
+    "stored_balance" decimal(78,0) NOT NULL,
+    "onchain_balance" decimal(78,0) NOT NULL,
+    "internal_net_wei" decimal(78,0) NOT NULL,
+    "delta_wei" decimal(78,0) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reconciliation_runs_reports" FOREIGN KEY ("run_id") REFERENCES "reconciliation_runs"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_reconciliation_reports_run_id" ON "reconciliation_reports" ("run_id");
+CREATE INDEX IF NOT EXISTS "idx_reconciliation_reports_wallet_id" ON "reconciliation_reports" ("wallet_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "reconciliation_reports";
+DROP TABLE IF EXISTS "reconciliation_runs";
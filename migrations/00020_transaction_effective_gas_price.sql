@@ -0,0 +1,7 @@
+-- 交易实际费用：最终确认时记录回执中的实际gas单价（gas_used同时从回执写入），实际费用 = gas_used * effective_gas_price
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "effective_gas_price" decimal(36,18);
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "effective_gas_price";
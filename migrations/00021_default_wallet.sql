@@ -0,0 +1,15 @@
+-- 默认钱包：每个用户在每条链上至多一个默认个人钱包，发起转账时可省略from_address。
+-- 已有数据将每个用户在每条链上最早创建的未归档个人钱包设为默认
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "is_default" boolean NOT NULL DEFAULT false;
+UPDATE "wallets" SET "is_default" = true WHERE "id" IN (
+    SELECT DISTINCT ON ("user_id", "chain_id") "id" FROM "wallets"
+    WHERE "org_id" IS NULL AND "archived" = false
+    ORDER BY "user_id", "chain_id", "created_at", "id"
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wallets_user_chain_default" ON "wallets" ("user_id", "chain_id") WHERE "is_default";
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_wallets_user_chain_default";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "is_default";
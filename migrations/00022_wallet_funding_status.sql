@@ -0,0 +1,7 @@
+-- 测试链水龙头：新建钱包的领水状态（funding_pending、funded、funding_failed），已有钱包为空
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "funding_status" varchar(20) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "funding_status";
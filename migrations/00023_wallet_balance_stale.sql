@@ -0,0 +1,10 @@
+-- 交易最终确认时与状态更新在同一事务中记录链上余额待刷新的时间；
+-- 提交后的余额刷新失败（或进程退出）时由Worker按该字段定时重试
+
+-- +goose Up
+ALTER TABLE "wallets" ADD COLUMN IF NOT EXISTS "balance_stale_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_wallets_balance_stale_at" ON "wallets" ("balance_stale_at");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_wallets_balance_stale_at";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "balance_stale_at";
@@ -0,0 +1,7 @@
+-- 运维工具停用用户：记录停用时间（为空表示正常），停用期间无法登录、API Key无法认证
+
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "disabled_at" timestamptz;
+
+-- +goose Down
+ALTER TABLE "users" DROP COLUMN IF EXISTS "disabled_at";
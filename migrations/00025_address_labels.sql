@@ -0,0 +1,18 @@
+-- 公共地址标签：交易所地址、DeFi协议与代币合约（内置数据集启动时同步，管理员可导入），用于标注交易对手方
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "address_labels" (
+    "id" bigserial,
+    "address" varchar(42) NOT NULL,
+    "chain_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "category" varchar(20) NOT NULL,
+    "source" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_address_labels_address_chain" ON "address_labels" ("address", "chain_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "address_labels";
@@ -0,0 +1,10 @@
+-- 交易更新时间：状态、确认数、备注或标签变化时更新，用于交易列表的ETag（未变化的轮询返回304）
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "updated_at" timestamptz;
+UPDATE "transactions" SET "updated_at" = COALESCE("confirmed_at", "created_at") WHERE "updated_at" IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_transactions_wallet_updated" ON "transactions" ("wallet_id", "updated_at");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_transactions_wallet_updated";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "updated_at";
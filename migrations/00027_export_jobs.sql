@@ -0,0 +1,29 @@
+-- 异步导出任务：Worker将交易记录生成到文件，用户凭一次性下载令牌下载，文件过期后删除
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "export_jobs" (
+    "id" varchar(36) NOT NULL,
+    "user_id" bigint NOT NULL,
+    "format" varchar(10) NOT NULL,
+    "wallet_id" bigint NOT NULL DEFAULT 0,
+    "chain_id" bigint NOT NULL DEFAULT 0,
+    "from_date" timestamptz,
+    "to_date" timestamptz,
+    "status" varchar(20) NOT NULL,
+    "estimated_rows" bigint NOT NULL DEFAULT 0,
+    "row_count" bigint NOT NULL DEFAULT 0,
+    "file_size" bigint NOT NULL DEFAULT 0,
+    "error_msg" text,
+    "expires_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_export_jobs_user_id" ON "export_jobs" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_export_jobs_status" ON "export_jobs" ("status");
+CREATE INDEX IF NOT EXISTS "idx_export_jobs_expires_at" ON "export_jobs" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_export_jobs_created_at" ON "export_jobs" ("created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "export_jobs";
@@ -0,0 +1,9 @@
+-- 交易下次检查时间：确认监控按next_check_at批量查询到期交易，未打包的交易按年龄分级退避，避免每轮检查全部待确认交易
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "next_check_at" timestamptz NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS "idx_transactions_next_check" ON "transactions" ("next_check_at");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_transactions_next_check";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "next_check_at";
@@ -0,0 +1,7 @@
+-- 交易卡住提醒时间：长时间未打包且nonce尚未被使用的交易只推送一次卡住提醒（nonce已被其他交易使用时标记为dropped）
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "stuck_notified_at" timestamptz;
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "stuck_notified_at";
@@ -0,0 +1,17 @@
+-- 交易列表、导出与统计的方向、金额与对手方筛选：按发送方查询对手方的表达式索引、钱包内按金额范围查询的组合索引，以及异步导出任务保存的筛选条件
+
+-- +goose Up
+CREATE INDEX IF NOT EXISTS "idx_transactions_from_address_lower" ON "transactions" (LOWER(from_address));
+CREATE INDEX IF NOT EXISTS "idx_transactions_wallet_amount" ON "transactions" ("wallet_id", "amount");
+ALTER TABLE "export_jobs" ADD COLUMN IF NOT EXISTS "direction" varchar(10) NOT NULL DEFAULT '';
+ALTER TABLE "export_jobs" ADD COLUMN IF NOT EXISTS "min_amount" varchar(40) NOT NULL DEFAULT '';
+ALTER TABLE "export_jobs" ADD COLUMN IF NOT EXISTS "max_amount" varchar(40) NOT NULL DEFAULT '';
+ALTER TABLE "export_jobs" ADD COLUMN IF NOT EXISTS "counterparty" varchar(42) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE "export_jobs" DROP COLUMN IF EXISTS "counterparty";
+ALTER TABLE "export_jobs" DROP COLUMN IF EXISTS "max_amount";
+ALTER TABLE "export_jobs" DROP COLUMN IF EXISTS "min_amount";
+ALTER TABLE "export_jobs" DROP COLUMN IF EXISTS "direction";
+DROP INDEX IF EXISTS "idx_transactions_wallet_amount";
+DROP INDEX IF EXISTS "idx_transactions_from_address_lower";
@@ -0,0 +1,7 @@
+-- 交易连续检查失败次数：查询回执或交易出错时递增并按次数退避下次检查，检查成功后清零
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "check_failures" bigint NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "check_failures";
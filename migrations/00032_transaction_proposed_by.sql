@@ -0,0 +1,7 @@
+-- 待审批交易的发起人：审批人不能批准自己发起的交易（组织钱包中发起人可能同时是审批人）
+
+-- +goose Up
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "proposed_by" bigint;
+
+-- +goose Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "proposed_by";
@@ -0,0 +1,7 @@
+-- 余额快照以Wei计，decimal(36,18)的整数部分最多18位（不足1 ETH），改为decimal(78,0)
+
+-- +goose Up
+ALTER TABLE "balance_snapshots" ALTER COLUMN "balance" TYPE decimal(78,0);
+
+-- +goose Down
+ALTER TABLE "balance_snapshots" ALTER COLUMN "balance" TYPE decimal(36,18);
@@ -0,0 +1,7 @@
+-- 账本余额以Wei计，decimal(36,18)的整数部分最多18位（不足1 ETH），改为与internal_net_wei相同的decimal(78,0)
+
+-- +goose Up
+ALTER TABLE "wallets" ALTER COLUMN "balance" TYPE decimal(78,0);
+
+-- +goose Down
+ALTER TABLE "wallets" ALTER COLUMN "balance" TYPE decimal(36,18);
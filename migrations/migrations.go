@@ -0,0 +1,11 @@
+// Package migrations 数据库版本化迁移脚本（goose格式，编译时嵌入二进制）
+package migrations
+
+import "embed"
+
+// FS 迁移脚本文件系统
+//
+// 新增表结构变更时按序号新建 NNNNN_描述.sql，已发布的脚本不可修改
+//
+//go:embed *.sql
+var FS embed.FS
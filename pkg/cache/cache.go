@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound 键不存在或已过期
+var ErrNotFound = errors.New("key not found")
+
+// Cache 键值缓存接口（ttl为0表示不过期）
+type Cache interface {
+	// Get 获取缓存，键不存在时返回ErrNotFound（调用方需区分未命中与读取失败）
+	Get(ctx context.Context, key string) (string, error)
+	// Set 设置缓存
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Delete 删除缓存
+	Delete(ctx context.Context, keys ...string) error
+	// SetNX 仅当键不存在时设置，返回是否设置成功
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	// Incr 自增（键不存在时从0开始）
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+var (
+	_ Cache = (*RedisCache)(nil)
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*TieredCache)(nil)
+)
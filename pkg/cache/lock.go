@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockKeyPrefix 分布式锁键前缀
+const lockKeyPrefix = "lock:"
+
+// ErrLockNotHeld 锁已过期或已被其他实例持有（续期、释放时返回）
+var ErrLockNotHeld = errors.New("lock not held")
+
+// extendScript 仅当令牌匹配时续期
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript 仅当令牌匹配时删除
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock Redis分布式锁（SET NX加随机令牌，续期与释放时校验令牌，避免误删其他实例的锁）
+type Lock struct {
+	client *redis.Client
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+// TryLock 尝试获取锁，已被其他实例持有时返回nil, nil
+func (c *RedisCache) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := lockKeyPrefix + name
+	ok, err := c.SetNX(ctx, key, token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &Lock{client: c.client, key: key, token: token, ttl: ttl}, nil
+}
+
+// Extend 将锁的过期时间重置为ttl
+func (l *Lock) Extend(ctx context.Context) error {
+	extended, err := extendScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release 释放锁（锁已过期并被其他实例获取时不做任何操作）
+func (l *Lock) Release(ctx context.Context) error {
+	released, err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Int()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// WithLock 持有锁期间执行fn（执行期间每ttl/3自动续期，续期失败时取消fn的ctx），返回是否获取到锁
+func (c *RedisCache) WithLock(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context)) (bool, error) {
+	// 1. 获取锁
+	lock, err := c.TryLock(ctx, name, ttl)
+	if err != nil || lock == nil {
+		return false, err
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	// 2. 后台续期
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-fnCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Extend(fnCtx); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	// 3. 执行
+	fn(fnCtx)
+	return true, nil
+}
+
+// lockToken 生成随机锁令牌
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
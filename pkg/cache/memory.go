@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryEntry 内存缓存条目
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time // 零值表示不过期
+}
+
+// expired 判断条目是否已过期
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// MemoryCache 进程内LRU缓存（超出容量时淘汰最久未访问的条目，过期条目在访问时惰性删除）
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 队首为最近访问
+	maxSize int
+}
+
+// NewMemoryCache 创建内存缓存实例，maxSize为最多缓存的键数量
+func NewMemoryCache(maxSize int) *MemoryCache {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	return &MemoryCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		maxSize: maxSize,
+	}
+}
+
+// Get 获取缓存
+func (c *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookupLocked(key, time.Now())
+	if entry == nil {
+		return "", ErrNotFound
+	}
+	return entry.value, nil
+}
+
+// Set 设置缓存
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.storeLocked(key, formatValue(value), ttl)
+	return nil
+}
+
+// Delete 删除缓存
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.removeLocked(elem)
+		}
+	}
+	return nil
+}
+
+// SetNX 仅当键不存在时设置
+func (c *MemoryCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lookupLocked(key, time.Now()) != nil {
+		return false, nil
+	}
+	c.storeLocked(key, formatValue(value), ttl)
+	return true, nil
+}
+
+// Incr 自增（保留原有过期时间，值不是整数时返回错误）
+func (c *MemoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookupLocked(key, time.Now())
+	if entry == nil {
+		c.storeLocked(key, "1", 0)
+		return 1, nil
+	}
+
+	n, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value is not an integer: %w", err)
+	}
+	n++
+	entry.value = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// Len 当前缓存的键数量（含尚未清理的过期条目）
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// lookupLocked 查找未过期的条目并标记为最近访问（调用方需持有锁）
+func (c *MemoryCache) lookupLocked(key string, now time.Time) *memoryEntry {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(now) {
+		c.removeLocked(elem)
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return entry
+}
+
+// storeLocked 写入条目，超出容量时淘汰最久未访问的条目（调用方需持有锁）
+func (c *MemoryCache) storeLocked(key, value string, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	for c.order.Len() >= c.maxSize {
+		c.removeLocked(c.order.Back())
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+}
+
+// removeLocked 移除条目（调用方需持有锁）
+func (c *MemoryCache) removeLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
+
+// formatValue 将值转换为字符串（与Redis客户端的序列化方式保持一致）
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
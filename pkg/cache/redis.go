@@ -13,15 +13,20 @@ type RedisCache struct {
 	client *redis.Client
 }
 
-// NewRedisCache 创建Redis缓存实例
-func NewRedisCache(addr string, password string, db int, poolSize int, minIdleConns int) (*RedisCache, error) {
+// NewRedisCache 创建Redis缓存实例（opTimeout为单次命令的时限，0表示只受调用方ctx限制）
+func NewRedisCache(addr string, password string, db int, poolSize int, minIdleConns int, opTimeout time.Duration) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:         addr,
 		Password:     password,
 		DB:           db,
 		PoolSize:     poolSize,
 		MinIdleConns: minIdleConns,
+		// 按ctx的截止时间设置连接读写超时，请求时限到期时命令立即返回而不是等待读写超时
+		ContextTimeoutEnabled: true,
 	})
+	if opTimeout > 0 {
+		client.AddHook(timeoutHook{timeout: opTimeout})
+	}
 
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -34,20 +39,53 @@ func NewRedisCache(addr string, password string, db int, poolSize int, minIdleCo
 	return &RedisCache{client: client}, nil
 }
 
-// Set 设置缓存（带过期时间，单位：秒）
-func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration int) error {
-	return c.client.Set(ctx, key, value, time.Duration(expiration)*time.Second).Err()
+// timeoutHook 为每条命令（含流水线与脚本）设置时限，覆盖直接使用GetClient的调用方（限流、分布式锁）
+type timeoutHook struct {
+	timeout time.Duration
+}
+
+// DialHook 建立连接不额外限时（由DialTimeout控制）
+func (h timeoutHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook 单条命令限时
+func (h timeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel := context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+		return next(ctx, cmd)
+	}
 }
 
-// Get 获取缓存
+// ProcessPipelineHook 流水线整体限时
+func (h timeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel := context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+		return next(ctx, cmds)
+	}
+}
+
+// Set 设置缓存（ttl为0表示不过期）
+func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Get 获取缓存（键不存在时返回ErrNotFound，连接失败等其他错误原样返回）
 func (c *RedisCache) Get(ctx context.Context, key string) (string, error) {
 	val, err := c.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("key not found")
+		return "", ErrNotFound
 	}
 	return val, err
 }
 
+// MGet 批量获取缓存（一次往返），不存在的键对应位置为nil
+func (c *RedisCache) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return c.client.MGet(ctx, keys...).Result()
+}
+
 // Delete 删除缓存
 func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
 	return c.client.Del(ctx, keys...).Err()
@@ -60,8 +98,8 @@ func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 }
 
 // Expire 设置键的过期时间
-func (c *RedisCache) Expire(ctx context.Context, key string, expiration int) error {
-	return c.client.Expire(ctx, key, time.Duration(expiration)*time.Second).Err()
+func (c *RedisCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.client.Expire(ctx, key, ttl).Err()
 }
 
 // Incr 自增
@@ -119,9 +157,24 @@ func (c *RedisCache) LRange(ctx context.Context, key string, start, stop int64)
 	return c.client.LRange(ctx, key, start, stop).Result()
 }
 
-// SetNX 仅当键不存在时设置（分布式锁）
-func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, expiration int) (bool, error) {
-	return c.client.SetNX(ctx, key, value, time.Duration(expiration)*time.Second).Result()
+// SetNX 仅当键不存在时设置（分布式锁见TryLock）
+func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Publish 发布消息到频道
+func (c *RedisCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe 订阅频道（调用方负责关闭返回的PubSub）
+func (c *RedisCache) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return c.client.Subscribe(ctx, channels...)
+}
+
+// Ping 检查Redis连接
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
 }
 
 // Close 关闭连接
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TieredCache 两级缓存：读取时先查本地内存，未命中再查远端（Redis），远端命中后回填本地；
+// 写入与删除同时作用于两级。本地条目的有效期不超过localTTL，以限制多副本间的不一致窗口；
+// 远端不可用时本地层仍可命中，避免所有读取都回源。SetNX与Incr需要跨副本的原子性，只作用于远端
+type TieredCache struct {
+	local    Cache
+	remote   Cache
+	localTTL time.Duration
+}
+
+// NewTieredCache 创建两级缓存
+func NewTieredCache(local, remote Cache, localTTL time.Duration) *TieredCache {
+	return &TieredCache{
+		local:    local,
+		remote:   remote,
+		localTTL: localTTL,
+	}
+}
+
+// Get 获取缓存（本地 → 远端）
+func (c *TieredCache) Get(ctx context.Context, key string) (string, error) {
+	if value, err := c.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	value, err := c.remote.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	c.local.Set(ctx, key, value, c.localTTL)
+	return value, nil
+}
+
+// Set 设置缓存（远端失败时本地仍然写入，返回远端错误）
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.local.Set(ctx, key, value, c.clampTTL(ttl))
+	return c.remote.Set(ctx, key, value, ttl)
+}
+
+// Delete 删除缓存（仅能删除当前进程的本地条目，其他副本的本地条目在localTTL内自然过期）
+func (c *TieredCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.Join(
+		c.local.Delete(ctx, keys...),
+		c.remote.Delete(ctx, keys...),
+	)
+}
+
+// SetNX 仅当键不存在时设置（只作用于远端）
+func (c *TieredCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.remote.SetNX(ctx, key, value, ttl)
+}
+
+// Incr 自增（只作用于远端）
+func (c *TieredCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.remote.Incr(ctx, key)
+}
+
+// clampTTL 本地条目有效期取ttl与localTTL中较小者
+func (c *TieredCache) clampTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.localTTL {
+		return c.localTTL
+	}
+	return ttl
+}
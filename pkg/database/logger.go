@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// redactedColumn 日志中需要隐藏参数值的列（加密私钥）
+const redactedColumn = "private_key_encrypted"
+
+var (
+	// assignPattern 匹配 "private_key_encrypted"=$N（UPDATE的SET与WHERE条件）
+	assignPattern = regexp.MustCompile(`"?` + redactedColumn + `"?\s*=\s*\$(\d+)`)
+	// insertPattern 匹配INSERT语句的列清单
+	insertPattern = regexp.MustCompile(`(?i)^INSERT INTO \S+ \(([^)]*)\)`)
+)
+
+// zapGormLogger 将GORM日志输出到zap（带请求ID与Trace ID），超过阈值的慢查询以Warn级别记录
+type zapGormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// newZapGormLogger 创建GORM日志适配器，level为silent/error/warn/info，slowThreshold为0时不记录慢查询
+func newZapGormLogger(level string, slowThreshold time.Duration) gormlogger.Interface {
+	return &zapGormLogger{
+		level:         parseGormLevel(level),
+		slowThreshold: slowThreshold,
+	}
+}
+
+// parseGormLevel 解析GORM日志级别，未知级别按warn处理
+func parseGormLevel(level string) gormlogger.LogLevel {
+	switch level {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+// LogMode 返回指定级别的日志适配器副本
+func (l *zapGormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info 记录信息日志
+func (l *zapGormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info && logger.Logger != nil {
+		logger.WithCtx(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn 记录警告日志
+func (l *zapGormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn && logger.Logger != nil {
+		logger.WithCtx(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error 记录错误日志
+func (l *zapGormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error && logger.Logger != nil {
+		logger.WithCtx(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace 记录SQL执行结果：错误（记录不存在除外）、慢查询与普通查询分别按Error、Warn、Info级别输出
+func (l *zapGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent || logger.Logger == nil {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("elapsed", elapsed),
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.WithCtx(ctx).Error("sql error", append(fields(), zap.Error(err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		logger.WithCtx(ctx).Warn("slow sql", append(fields(), zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info:
+		logger.WithCtx(ctx).Info("sql", fields()...)
+	}
+}
+
+// ParamsFilter 在SQL写入日志前隐藏加密私钥列的参数值
+func (l *zapGormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, redactParams(sql, params)
+}
+
+// redactParams 按占位符位置替换加密私钥列的参数（支持 "col"=$N 与INSERT列清单两种形式）
+func redactParams(sql string, params []interface{}) []interface{} {
+	if !strings.Contains(sql, redactedColumn) {
+		return params
+	}
+
+	redacted := make([]interface{}, len(params))
+	copy(redacted, params)
+
+	// 1. UPDATE/WHERE中的 "private_key_encrypted"=$N
+	for _, match := range assignPattern.FindAllStringSubmatch(sql, -1) {
+		if i, err := strconv.Atoi(match[1]); err == nil && i >= 1 && i <= len(redacted) {
+			redacted[i-1] = "[REDACTED]"
+		}
+	}
+
+	// 2. INSERT按列位置替换（批量插入时每行重复同一列序）
+	if match := insertPattern.FindStringSubmatch(sql); match != nil {
+		columns := strings.Split(match[1], ",")
+		for i, column := range columns {
+			if strings.Trim(strings.TrimSpace(column), `"`) != redactedColumn {
+				continue
+			}
+			for j := i; j < len(redacted); j += len(columns) {
+				redacted[j] = "[REDACTED]"
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/migrations"
+)
+
+// NewMigrator 创建版本化迁移执行器（迁移脚本见 migrations/ 目录）
+func NewMigrator(db *gorm.DB) (*goose.Provider, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, sqlDB, migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// CheckSchemaVersion 检查数据库版本是否已迁移到最新（落后时返回错误，服务应拒绝启动）
+func CheckSchemaVersion(ctx context.Context, db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	current, target, err := migrator.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if current < target {
+		return fmt.Errorf("database schema version %d is behind required version %d, run `migrate up` first", current, target)
+	}
+	return nil
+}
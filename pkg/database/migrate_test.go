@@ -0,0 +1,114 @@
+package database_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/testutil"
+	"crypto-wallet-api/pkg/database"
+)
+
+// TestMigrationsMatchModels 版本化迁移与按模型建表（开发模式与测试数据库）得到的表结构必须一致：
+// 只写在迁移中的索引、约束或列不会出现在测试数据库中，只写在模型中的则不会出现在生产环境
+//
+// 需要设置CWA_TEST_POSTGRES_DSN，未设置时跳过
+func TestMigrationsMatchModels(t *testing.T) {
+	migrated := testutil.NewPostgres(t)
+	modeled := testutil.NewPostgresSchema(t)
+	if err := modeled.AutoMigrate(database.Models()...); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	want, got := describeSchema(t, migrated), describeSchema(t, modeled)
+	var diffs []string
+	for key, def := range want {
+		switch modelDef, ok := got[key]; {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("only in migrations: %s %s", key, def))
+		case modelDef != def:
+			diffs = append(diffs, fmt.Sprintf("differs: %s\n\tmigrations: %s\n\tmodels:     %s", key, def, modelDef))
+		}
+	}
+	for key, def := range got {
+		if _, ok := want[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("only in models: %s %s", key, def))
+		}
+	}
+	sort.Strings(diffs)
+	for _, diff := range diffs {
+		t.Error(diff)
+	}
+}
+
+// describeSchema 返回当前schema的表结构描述：键为"column 表.列"、"index 索引名"、"constraint 表.约束名"，值为定义
+func describeSchema(t *testing.T, db *gorm.DB) map[string]string {
+	t.Helper()
+	var schema string
+	if err := db.Raw("SELECT current_schema()").Scan(&schema).Error; err != nil {
+		t.Fatalf("current schema: %v", err)
+	}
+
+	var columns []struct {
+		TableName  string
+		ColumnName string
+		Type       string
+		NotNull    bool
+		Default    string
+	}
+	err := db.Raw(`
+		SELECT c.relname AS table_name, a.attname AS column_name, format_type(a.atttypid, a.atttypmod) AS type,
+			a.attnotnull AS not_null, COALESCE(pg_get_expr(d.adbin, d.adrelid), '') AS "default"
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE c.relnamespace = current_schema()::regnamespace AND c.relkind = 'r' AND c.relname <> 'goose_db_version'
+			AND a.attnum > 0 AND NOT a.attisdropped`).Scan(&columns).Error
+	if err != nil {
+		t.Fatalf("load columns: %v", err)
+	}
+
+	var indexes []struct {
+		IndexName string
+		IndexDef  string
+	}
+	err = db.Raw(`SELECT indexname AS index_name, indexdef AS index_def FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename <> 'goose_db_version'`).Scan(&indexes).Error
+	if err != nil {
+		t.Fatalf("load indexes: %v", err)
+	}
+
+	var constraints []struct {
+		TableName string
+		Name      string
+		Def       string
+	}
+	err = db.Raw(`SELECT conrelid::regclass::text AS table_name, conname AS name, pg_get_constraintdef(oid) AS def FROM pg_constraint
+		WHERE connamespace = current_schema()::regnamespace AND conrelid <> 0 AND conrelid::regclass::text <> 'goose_db_version'`).Scan(&constraints).Error
+	if err != nil {
+		t.Fatalf("load constraints: %v", err)
+	}
+
+	desc := make(map[string]string, len(columns)+len(indexes)+len(constraints))
+	for _, c := range columns {
+		def := c.Type
+		if c.NotNull {
+			def += " NOT NULL"
+		}
+		if c.Default != "" {
+			def += " DEFAULT " + c.Default
+		}
+		desc["column "+c.TableName+"."+c.ColumnName] = def
+	}
+	for _, i := range indexes {
+		// 索引定义中的表名带schema前缀，两边schema不同
+		desc["index "+i.IndexName] = strings.ReplaceAll(i.IndexDef, schema+".", "")
+	}
+	for _, c := range constraints {
+		desc["constraint "+c.TableName+"."+c.Name] = c.Def
+	}
+	return desc
+}
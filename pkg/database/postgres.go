@@ -6,24 +6,25 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
 	"crypto-wallet-api/internal/models"
 )
 
-// NewPostgresDB 创建PostgreSQL数据库连接
-func NewPostgresDB(dsn string, maxOpenConns int, maxIdleConns int, connMaxLifetime time.Duration) (*gorm.DB, error) {
-	// 配置GORM日志
-	gormLogger := logger.Default.LogMode(logger.Info)
-
+// NewPostgresDB 创建PostgreSQL数据库连接（SQL日志经zap输出，logLevel为silent/error/warn/info）
+func NewPostgresDB(dsn string, maxOpenConns int, maxIdleConns int, connMaxLifetime time.Duration, logLevel string, slowThreshold time.Duration) (*gorm.DB, error) {
 	// 连接数据库
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger: newZapGormLogger(logLevel, slowThreshold),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// 注册SQL链路追踪插件（未启用追踪时为noop）
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	// 获取底层数据库连接
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -43,11 +44,49 @@ func NewPostgresDB(dsn string, maxOpenConns int, maxIdleConns int, connMaxLifeti
 	return db, nil
 }
 
-// AutoMigrate 自动迁移数据库表结构
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+// Models 按模型建表的全部模型（开发模式的AutoMigrate与测试数据库共用）
+func Models() []interface{} {
+	return []interface{}{
 		&models.User{},
 		&models.Wallet{},
 		&models.Transaction{},
-	)
+		&models.APIKey{},
+		&models.Contact{},
+		&models.WhitelistEntry{},
+		&models.SpendLedgerEntry{},
+		&models.OutboxEvent{},
+		&models.RecurringPayment{},
+		&models.WalletActivity{},
+		&models.Token{},
+		&models.TokenWatch{},
+		&models.ChainCursor{},
+		&models.TransactionTag{},
+		&models.WalletApprover{},
+		&models.TransactionApproval{},
+		&models.Organization{},
+		&models.OrgMembership{},
+		&models.NotificationPreference{},
+		&models.Notification{},
+		&models.LoginHistory{},
+		&models.BalanceSnapshot{},
+		&models.FeatureFlagChange{},
+		&models.ReconciliationRun{},
+		&models.ReconciliationReport{},
+		&models.AddressLabel{},
+		&models.ExportJob{},
+	}
+}
+
+// AutoMigrate 按模型自动迁移表结构（仅开发模式，生产环境使用 migrations/ 版本化迁移）
+func AutoMigrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+
+	// 交易哈希不再单独唯一（同一交易的多条代币入账按日志序号区分，待审批交易尚无哈希），
+	// 删除此前建表时的唯一约束与不区分是否已签名的旧索引
+	if err := db.Exec(`ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS "uni_transactions_tx_hash"`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`DROP INDEX IF EXISTS "idx_transactions_hash_log"`).Error
 }
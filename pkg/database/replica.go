@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// replicaLagQuery 查询副本的复制延迟秒数（已回放全部收到的WAL时为0，主库上执行时也为0）
+const replicaLagQuery = `SELECT CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// Replica 只读副本：复制延迟在容忍范围内时承担只读查询（列表、统计、导出），延迟超限或副本不可用时回退到主库
+type Replica struct {
+	primary *gorm.DB
+	replica *gorm.DB
+	maxLag  time.Duration
+	healthy atomic.Bool // 首次检查前为false（读主库）
+	checked bool        // 已完成首次检查（仅Run所在协程访问）
+}
+
+// NewReplica 创建只读副本路由
+func NewReplica(primary, replica *gorm.DB, maxLag time.Duration) *Replica {
+	return &Replica{primary: primary, replica: replica, maxLag: maxLag}
+}
+
+// DB 只读查询使用的连接
+func (r *Replica) DB() *gorm.DB {
+	if r.healthy.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Lag 查询副本当前的复制延迟
+func (r *Replica) Lag(ctx context.Context) (time.Duration, error) {
+	var seconds float64
+	if err := r.replica.WithContext(ctx).Raw(replicaLagQuery).Scan(&seconds).Error; err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// Run 按间隔检查复制延迟并切换只读查询的目标，直到ctx取消
+func (r *Replica) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check 检查一次复制延迟，状态变化时记录日志
+func (r *Replica) check(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lag, err := r.Lag(ctx)
+	healthy := err == nil && lag <= r.maxLag
+	if r.healthy.Swap(healthy) == healthy && r.checked {
+		return
+	}
+	r.checked = true
+	switch {
+	case healthy:
+		logger.Info("Read replica in sync, serving read-only queries", zap.Duration("lag", lag))
+	case err != nil:
+		logger.Warn("Read replica unavailable, falling back to primary", zap.Error(err))
+	default:
+		logger.Warn("Read replica lagging, falling back to primary", zap.Duration("lag", lag), zap.Duration("max_lag", r.maxLag))
+	}
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+
+	"crypto-wallet-api/internal/utils"
+)
+
+// EncryptedSerializerName 加密字段序列化器名称（字段标签serializer:encrypted）
+const EncryptedSerializerName = "encrypted"
+
+// EncryptedSerializer 字符串字段的透明加密（写入时AES-GCM加密，读取时解密）
+type EncryptedSerializer struct {
+	key []byte
+}
+
+// RegisterEncryptedSerializer 注册加密字段序列化器（需在访问使用该序列化器的模型之前调用）
+func RegisterEncryptedSerializer(key []byte) {
+	schema.RegisterSerializer(EncryptedSerializerName, EncryptedSerializer{key: key})
+}
+
+// Scan 读取并解密（不是Base64的值视为加密前写入的明文，原样返回，便于回填期间继续服务）
+func (s EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported encrypted value type %T for field %s", dbValue, field.Name)
+	}
+
+	if value != "" {
+		if _, err := base64.StdEncoding.DecodeString(value); err == nil {
+			plaintext, err := utils.DecryptAES(value, s.key)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+			}
+			value = plaintext
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+// Value 加密后写入（空字符串不加密）
+func (s EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted serializer only supports string fields, got %T for field %s", fieldValue, field.Name)
+	}
+	if value == "" {
+		return "", nil
+	}
+	return utils.EncryptAES(value, s.key)
+}
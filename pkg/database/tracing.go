@@ -0,0 +1,75 @@
+package database
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracingPlugin GORM插件：为每条SQL创建OpenTelemetry Span
+type tracingPlugin struct{}
+
+// Name 插件名称
+func (tracingPlugin) Name() string {
+	return "otel-tracing"
+}
+
+// Initialize 注册各类操作的前后回调
+func (p tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		name     string
+		before   func(name string, fn func(*gorm.DB)) error
+		after    func(name string, fn func(*gorm.DB)) error
+		callback string
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register, "gorm.create"},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register, "gorm.query"},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register, "gorm.update"},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register, "gorm.delete"},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register, "gorm.row"},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register, "gorm.raw"},
+	}
+
+	for _, h := range hooks {
+		if err := h.before("otel:before_"+h.name, p.before(h.callback)); err != nil {
+			return err
+		}
+		if err := h.after("otel:after_"+h.name, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 开始Span并写回Statement上下文
+func (tracingPlugin) before(spanName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, _ := otel.Tracer("gorm").Start(db.Statement.Context, spanName,
+			trace.WithSpanKind(trace.SpanKindClient),
+		)
+		db.Statement.Context = ctx
+	}
+}
+
+// after 记录SQL与影响行数并结束Span
+func (tracingPlugin) after(db *gorm.DB) {
+	span := trace.SpanFromContext(db.Statement.Context)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
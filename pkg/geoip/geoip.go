@@ -0,0 +1,24 @@
+package geoip
+
+import "context"
+
+// Location IP地址的地理位置
+type Location struct {
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+	City    string `json:"city,omitempty"`
+}
+
+// Resolver IP地理位置解析接口（可接入MaxMind等数据库或在线服务）
+type Resolver interface {
+	// Lookup 解析IP所在位置，无法解析时返回nil, nil
+	Lookup(ctx context.Context, ip string) (*Location, error)
+}
+
+// Noop 不解析任何地址的默认实现
+type Noop struct{}
+
+// Lookup 始终返回nil
+func (Noop) Lookup(ctx context.Context, ip string) (*Location, error) {
+	return nil, nil
+}
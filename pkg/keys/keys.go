@@ -0,0 +1,57 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// 密钥名称
+const (
+	WalletEncryption = "wallet_encryption" // 钱包私钥加密（AES-256-GCM）
+	PIIEncryption    = "pii_encryption"    // 用户敏感信息加密（AES-256-GCM）
+	EmailHMAC        = "email_hmac"        // 邮箱检索摘要（HMAC-SHA256）
+	QueueEncryption  = "queue_encryption"  // 队列消息加密（AES-256-GCM，可选）
+)
+
+// KeySize 密钥长度（字节）
+const KeySize = 32
+
+// ErrKeyNotFound 密钥不存在
+var ErrKeyNotFound = errors.New("key not found")
+
+// Provider 密钥提供者（生产环境可替换为KMS或Vault实现）
+type Provider interface {
+	// Key 按名称获取密钥
+	Key(name string) ([]byte, error)
+}
+
+// StaticProvider 从配置加载的固定密钥
+type StaticProvider struct {
+	keys map[string][]byte
+}
+
+// NewStaticProvider 从十六进制编码的密钥创建密钥提供者（每个密钥必须为32字节）
+func NewStaticProvider(hexKeys map[string]string) (*StaticProvider, error) {
+	keys := make(map[string][]byte, len(hexKeys))
+	for name, value := range hexKeys {
+		key, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %s is not valid hex: %w", name, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %s must be %d bytes, got %d", name, KeySize, len(key))
+		}
+		keys[name] = key
+	}
+	return &StaticProvider{keys: keys}, nil
+}
+
+// Key 按名称获取密钥
+func (p *StaticProvider) Key(name string) ([]byte, error) {
+	key, ok := p.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return key, nil
+}
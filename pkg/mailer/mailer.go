@@ -0,0 +1,46 @@
+// Package mailer 外发邮件：SMTP发送实现、开发环境只记录日志的实现，以及内嵌的HTML与纯文本邮件模板
+package mailer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+)
+
+// ErrPermanent 重试也无法成功的发送错误（收件人被拒、模板不存在等），调用方不应重试
+var ErrPermanent = errors.New("permanent mail error")
+
+// Message 待发送的邮件（Text与HTML至少一项非空，两项都有时以multipart/alternative发送）
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送邮件，不可重试的错误包装ErrPermanent
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	_ Mailer = (*SMTPMailer)(nil)
+	_ Mailer = LogMailer{}
+)
+
+// LogMailer 只记录日志不发送的实现（开发环境与未配置SMTP时使用）
+type LogMailer struct{}
+
+// Send 记录邮件的收件人与主题
+func (LogMailer) Send(ctx context.Context, msg *Message) error {
+	logger.WithCtx(ctx).Info("email not sent, log mailer in use",
+		zap.String("to", strings.Join(msg.To, ",")),
+		zap.String("subject", msg.Subject),
+	)
+	return nil
+}
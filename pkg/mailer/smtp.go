@@ -0,0 +1,257 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP连接的加密方式
+const (
+	TLSModeStartTLS = "starttls" // 明文连接后通过STARTTLS升级（587端口），服务器不支持时发送失败
+	TLSModeImplicit = "tls"      // 连接即建立TLS（465端口）
+	TLSModeNone     = "none"     // 不加密（仅用于本机或测试SMTP服务器，此时无法使用密码认证）
+)
+
+// SMTPConfig SMTP发送配置
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // 为空时不认证
+	Password string
+	From     string        // 发件人，可带显示名称，如"Crypto Wallet <no-reply@example.com>"
+	TLSMode  string        // starttls、tls或none
+	Timeout  time.Duration // 单封邮件从建立连接到发送完成的超时时间
+}
+
+// SMTPMailer 通过SMTP服务器发送邮件（每封邮件使用一个新连接）
+type SMTPMailer struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPMailer 创建SMTP发送器（发件人地址格式错误或加密方式未知时返回错误）
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", cfg.From, err)
+	}
+	switch cfg.TLSMode {
+	case TLSModeStartTLS, TLSModeImplicit, TLSModeNone:
+	default:
+		return nil, fmt.Errorf("unknown tls mode %q", cfg.TLSMode)
+	}
+	return &SMTPMailer{cfg: cfg, from: from}, nil
+}
+
+// Send 发送邮件：收件人格式错误或服务器以5xx拒绝时返回包装ErrPermanent的错误，连接、认证等其他错误可重试
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	// 1. 校验收件人并构建邮件内容
+	if len(msg.To) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrPermanent)
+	}
+	recipients := make([]*mail.Address, len(msg.To))
+	for i, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("%w: invalid recipient %q: %v", ErrPermanent, to, err)
+		}
+		recipients[i] = addr
+	}
+	body, err := m.build(recipients, msg)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	// 2. 建立连接（超时取配置与ctx截止时间中较早者，ctx取消时中断连接）
+	deadline := time.Now().Add(m.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn, err := m.dial(ctx, deadline)
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	// 3. 加密与认证
+	if m.cfg.TLSMode == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(m.tlsConfig()); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	// 4. 发送
+	if err := client.Mail(m.from.Address); err != nil {
+		return classify("smtp mail from", err)
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return classify("smtp rcpt to "+rcpt.Address, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return classify("smtp data", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return classify("smtp data", err)
+	}
+	// 邮件已被服务器接收，QUIT失败不影响结果
+	_ = client.Quit()
+	return nil
+}
+
+// dial 按加密方式建立TCP或TLS连接，并设置整体读写截止时间
+func (m *SMTPMailer) dial(ctx context.Context, deadline time.Time) (net.Conn, error) {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := &net.Dialer{Deadline: deadline}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if m.cfg.TLSMode == TLSModeImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// tlsConfig 校验服务器证书的TLS配置
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	return &tls.Config{ServerName: m.cfg.Host, MinVersion: tls.VersionTLS12}
+}
+
+// build 构建邮件头与正文（同时有纯文本与HTML时以multipart/alternative发送，正文使用quoted-printable编码）
+func (m *SMTPMailer) build(recipients []*mail.Address, msg *Message) ([]byte, error) {
+	if msg.Text == "" && msg.HTML == "" {
+		return nil, errors.New("empty message body")
+	}
+
+	to := make([]string, len(recipients))
+	for i, rcpt := range recipients {
+		to[i] = rcpt.String()
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", m.from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", m.messageID())
+	header("MIME-Version", "1.0")
+
+	// 只有一种正文时直接发送
+	if msg.Text == "" || msg.HTML == "" {
+		contentType, content := "text/plain; charset=utf-8", msg.Text
+		if msg.Text == "" {
+			contentType, content = "text/html; charset=utf-8", msg.HTML
+		}
+		header("Content-Type", contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, content); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	// 纯文本在前、HTML在后（客户端优先显示最后一个能渲染的部分）
+	writer := multipart.NewWriter(&buf)
+	header("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID 生成以发件人域名结尾的随机Message-ID
+func (m *SMTPMailer) messageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	domain := m.cfg.Host
+	if at := strings.LastIndex(m.from.Address, "@"); at >= 0 {
+		domain = m.from.Address[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// writeQuotedPrintable 以quoted-printable编码写入正文
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// classify 服务器以5xx拒绝时包装ErrPermanent（4xx为临时错误，可重试）
+func classify(op string, err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return fmt.Errorf("%w: %s: %v", ErrPermanent, op, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-wallet-api/internal/testutil"
+)
+
+// newTestMailer 连接进程内测试SMTP服务的发送器
+func newTestMailer(t *testing.T, server *testutil.SMTPServer) *SMTPMailer {
+	t.Helper()
+	m, err := NewSMTPMailer(SMTPConfig{
+		Host:    server.Host,
+		Port:    server.Port,
+		From:    "Crypto Wallet <no-reply@example.com>",
+		TLSMode: TLSModeNone,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new smtp mailer: %v", err)
+	}
+	return m
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := testutil.NewSMTPServer(t)
+	m := newTestMailer(t, server)
+
+	msg, err := Render("notification", map[string]string{
+		"Username": "alice",
+		"Title":    "Deposit received",
+		"Message":  "1 ETH arrived",
+		"Type":     "deposit",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	msg.To = []string{"Alice <alice@example.com>"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("received %d messages, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.From != "no-reply@example.com" || len(got.To) != 1 || got.To[0] != "alice@example.com" {
+		t.Errorf("envelope = from %q to %v, want no-reply@example.com to [alice@example.com]", got.From, got.To)
+	}
+	for _, want := range []string{
+		"Subject: [Crypto Wallet] Deposit received",
+		"To: \"Alice\" <alice@example.com>",
+		"Content-Type: multipart/alternative",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+		"Hi alice,",
+		"1 ETH arrived",
+	} {
+		if !strings.Contains(got.Data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, got.Data)
+		}
+	}
+}
+
+func TestSMTPMailerServerRejection(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		permanent bool
+	}{
+		{"mailbox unavailable", "550 mailbox unavailable", true},
+		{"temporary failure", "451 try again later", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewSMTPServer(t)
+			server.Reject("bob@example.com", tt.reply)
+
+			err := newTestMailer(t, server).Send(context.Background(), &Message{
+				To:      []string{"bob@example.com"},
+				Subject: "hello",
+				Text:    "hello",
+			})
+			if err == nil {
+				t.Fatal("send succeeded, want error")
+			}
+			if errors.Is(err, ErrPermanent) != tt.permanent {
+				t.Errorf("err = %v, permanent = %v, want %v", err, errors.Is(err, ErrPermanent), tt.permanent)
+			}
+			if n := len(server.Messages()); n != 0 {
+				t.Errorf("received %d messages, want 0", n)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerInvalidRecipient(t *testing.T) {
+	server := testutil.NewSMTPServer(t)
+	err := newTestMailer(t, server).Send(context.Background(), &Message{
+		To:   []string{"not an address"},
+		Text: "hello",
+	})
+	if !errors.Is(err, ErrPermanent) {
+		t.Errorf("err = %v, want ErrPermanent", err)
+	}
+}
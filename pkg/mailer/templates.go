@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templateFS 邮件模板：每个模板由同名的.txt（纯文本正文，并以subject块定义主题）与.html（HTML正文）组成
+//
+//go:embed templates/*.txt templates/*.html
+var templateFS embed.FS
+
+// mailTemplate 一个已解析的邮件模板
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates 按名称索引的邮件模板（启动时解析，模板有误时panic）
+var templates = mustParseTemplates()
+
+// mustParseTemplates 解析全部内嵌模板（每个模板单独解析，subject块不会互相覆盖；数据中缺少的字段渲染为空）
+func mustParseTemplates() map[string]*mailTemplate {
+	files, err := fs.Glob(templateFS, "templates/*.txt")
+	if err != nil {
+		panic(err)
+	}
+	parsed := make(map[string]*mailTemplate, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+		text := texttemplate.Must(texttemplate.New(path.Base(file)).Option("missingkey=zero").ParseFS(templateFS, file))
+		if text.Lookup("subject") == nil {
+			panic(fmt.Sprintf("mail template %s has no subject block", name))
+		}
+		html := htmltemplate.Must(htmltemplate.New(name+".html").Option("missingkey=zero").ParseFS(templateFS, "templates/"+name+".html"))
+		parsed[name] = &mailTemplate{text: text, html: html}
+	}
+	return parsed
+}
+
+// HasTemplate 判断模板是否存在
+func HasTemplate(name string) bool {
+	_, ok := templates[name]
+	return ok
+}
+
+// Render 以data渲染模板，返回填好主题与正文的邮件（收件人由调用方设置）；模板不存在或渲染失败时返回包装ErrPermanent的错误
+func Render(name string, data interface{}) (*Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown template %q", ErrPermanent, name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("%w: render %s subject: %v", ErrPermanent, name, err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("%w: render %s text: %v", ErrPermanent, name, err)
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("%w: render %s html: %v", ErrPermanent, name, err)
+	}
+
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
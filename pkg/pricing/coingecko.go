@@ -0,0 +1,126 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"crypto-wallet-api/pkg/cache"
+)
+
+// defaultPriceCacheTTL 价格默认缓存时间
+const defaultPriceCacheTTL = time.Minute
+
+// ErrPriceUnavailable 无法获取价格
+var ErrPriceUnavailable = errors.New("price unavailable")
+
+// chainAssets 链ID到CoinGecko资产ID的映射（测试网资产无法定价，不在此列）
+var chainAssets = map[int]string{
+	1:  "ethereum",
+	56: "binancecoin",
+}
+
+// AssetForChain 获取链原生资产对应的CoinGecko资产ID
+func AssetForChain(chainID int) (string, bool) {
+	asset, ok := chainAssets[chainID]
+	return asset, ok
+}
+
+// CoinGeckoClient CoinGecko价格客户端（Redis缓存）
+type CoinGeckoClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	cache      cache.Cache
+	cacheTTL   atomic.Int64 // 价格缓存时间
+}
+
+// NewCoinGeckoClient 创建CoinGecko价格客户端
+func NewCoinGeckoClient(baseURL string, apiKey string, timeout time.Duration, cache cache.Cache) *CoinGeckoClient {
+	c := &CoinGeckoClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+		cache:      cache,
+	}
+	c.SetCacheTTL(defaultPriceCacheTTL)
+	return c
+}
+
+// SetCacheTTL 调整价格缓存时间（支持运行时调整）
+func (c *CoinGeckoClient) SetCacheTTL(ttl time.Duration) {
+	c.cacheTTL.Store(int64(ttl))
+}
+
+// GetUSDPrice 获取资产的美元价格（优先读取缓存）
+func (c *CoinGeckoClient) GetUSDPrice(ctx context.Context, asset string) (float64, error) {
+	// 1. 查询缓存
+	cacheKey := "price:usd:" + asset
+	if cached, err := c.cache.Get(ctx, cacheKey); err == nil {
+		if price, err := strconv.ParseFloat(cached, 64); err == nil {
+			return price, nil
+		}
+	}
+
+	// 2. 请求CoinGecko
+	price, err := c.fetchUSDPrice(ctx, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. 写入缓存
+	c.cache.Set(ctx, cacheKey, strconv.FormatFloat(price, 'f', -1, 64), time.Duration(c.cacheTTL.Load()))
+
+	return price, nil
+}
+
+// fetchUSDPrice 调用 /simple/price 接口
+func (c *CoinGeckoClient) fetchUSDPrice(ctx context.Context, asset string) (float64, error) {
+	query := url.Values{}
+	query.Set("ids", asset)
+	query.Set("vs_currencies", "usd")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/simple/price?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		// Pro API与Demo API使用不同的请求头
+		if strings.Contains(c.baseURL, "pro-api") {
+			req.Header.Set("x-cg-pro-api-key", c.apiKey)
+		} else {
+			req.Header.Set("x-cg-demo-api-key", c.apiKey)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPriceUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%w: coingecko returned status %d", ErrPriceUnavailable, resp.StatusCode)
+	}
+
+	// 响应格式：{"ethereum":{"usd":3000.12}}
+	var body map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPriceUnavailable, err)
+	}
+
+	price, ok := body[asset]["usd"]
+	if !ok {
+		return 0, ErrPriceUnavailable
+	}
+
+	return price, nil
+}
@@ -3,98 +3,586 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"crypto-wallet-api/internal/logger"
+	"crypto-wallet-api/internal/metrics"
+)
+
+// 重连退避参数
+const (
+	reconnectBaseDelay = 1 * time.Second
+	reconnectMaxDelay  = 30 * time.Second
+)
+
+// abortGracePeriod 排空超时取消处理函数后，等待其返回的最长时间
+const abortGracePeriod = 5 * time.Second
+
+// publishPoolSize 同时打开的发布通道上限（amqp通道不支持并发发布，每个发布通道同一时间只由一个协程使用）
+const publishPoolSize = 16
+
+// 重试队列与死信队列后缀
+const (
+	retryQueueSuffix = ".retry"
+	deadQueueSuffix  = ".dlq"
+	lastErrorHeader  = "x-last-error"
+)
+
+// tracerName 消息队列Span使用的Tracer名称
+const tracerName = "rabbitmq"
+
+// Handler 消息处理函数（ctx携带从消息头恢复的链路上下文）
+type Handler func(ctx context.Context, body []byte) error
+
+// ErrReject 处理函数返回包装了该错误的error时不再重试，消息直接转入死信队列（如无法解析或校验失败的消息）
+var ErrReject = errors.New("message rejected")
+
+// headerCarrier 将amqp.Table适配为链路上下文传播载体
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c headerCarrier) Set(key string, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ErrNotConnected 在等待重连超时后返回
+var ErrNotConnected = errors.New("rabbitmq is not connected")
+
+var (
+	// ErrPublishNacked 服务端拒绝了发布的消息（未写入队列）
+	ErrPublishNacked = errors.New("message nacked by rabbitmq")
+	// ErrPublishUnconfirmed 在publishTimeout内未收到服务端确认，或通道在确认前关闭（消息可能已写入队列）
+	ErrPublishUnconfirmed = errors.New("message publish not confirmed by rabbitmq")
 )
 
-// RabbitMQ RabbitMQ封装
+// publisher 确认模式的发布通道
+type publisher struct {
+	channel  *amqp.Channel
+	confirms chan amqp.Confirmation
+	closed   chan *amqp.Error
+}
+
+// RabbitMQ RabbitMQ封装（支持断线自动重连）
 type RabbitMQ struct {
+	url            string
+	publishTimeout time.Duration // Publish等待重连与服务端确认的最长时间
+	maxRetries     int           // 消息处理失败的最大重试次数，超过后进入死信队列
+	retryBaseDelay time.Duration // 首次重试延迟，之后按指数递增
+	prefetch       int           // 服务端推送但未确认的最大消息数（QoS）
+	concurrency    int           // 每个消费者同时运行的处理函数数
+
+	mu      sync.RWMutex
 	conn    *amqp.Connection
 	channel *amqp.Channel
+	ready   chan struct{}       // 连接可用时处于关闭状态；断线后替换为新的未关闭通道
+	queues  map[string]struct{} // 已声明的队列（重连后自动重新声明）
+	done    chan struct{}       // Close时关闭，终止重连与消费
+
+	callMu       sync.Mutex      // 串行化共享通道上的同步调用（同一通道上并发的同步调用可能收到彼此的响应）
+	publishSlots chan struct{}   // 发布通道名额，持有名额的协程独占一个发布通道
+	publishers   chan *publisher // 空闲的发布通道（断线后失效的通道在取出时丢弃）
+
+	drainMu       sync.Mutex
+	draining      bool               // Drain开始后不再启动新的处理函数
+	handlers      sync.WaitGroup     // 正在运行的处理函数
+	handlerCtx    context.Context    // 处理函数的取消信号（排空超时后取消）
+	abortHandlers context.CancelFunc // 取消仍在运行的处理函数
+	consumerSeq   atomic.Uint64      // 消费者标识序号
 }
 
 // NewRabbitMQ 创建RabbitMQ实例
-func NewRabbitMQ(url string) (*RabbitMQ, error) {
+func NewRabbitMQ(url string, publishTimeout time.Duration, maxRetries int, retryBaseDelay time.Duration) (*RabbitMQ, error) {
+	if publishTimeout <= 0 {
+		publishTimeout = 5 * time.Second
+	}
+	if retryBaseDelay <= 0 {
+		retryBaseDelay = 5 * time.Second
+	}
+
+	handlerCtx, abortHandlers := context.WithCancel(context.Background())
+	mq := &RabbitMQ{
+		url:            url,
+		publishTimeout: publishTimeout,
+		maxRetries:     maxRetries,
+		retryBaseDelay: retryBaseDelay,
+		prefetch:       1,
+		concurrency:    1,
+		ready:          make(chan struct{}),
+		queues:         make(map[string]struct{}),
+		done:           make(chan struct{}),
+		publishSlots:   make(chan struct{}, publishPoolSize),
+		publishers:     make(chan *publisher, publishPoolSize),
+		handlerCtx:     handlerCtx,
+		abortHandlers:  abortHandlers,
+	}
+
+	if err := mq.connect(); err != nil {
+		return nil, err
+	}
+
+	return mq, nil
+}
+
+// SetConsumerConcurrency 设置消费者的预取数量与处理并发度（需在开始消费前调用）
+//
+// prefetch小于concurrency时按concurrency预取，否则部分处理协程始终空闲。
+func (mq *RabbitMQ) SetConsumerConcurrency(prefetch, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	mq.prefetch = max(prefetch, concurrency)
+	mq.concurrency = concurrency
+}
+
+// connect 建立连接与通道，重新声明队列并启动断线监听
+func (mq *RabbitMQ) connect() error {
 	// 连接RabbitMQ
-	conn, err := amqp.Dial(url)
+	conn, err := amqp.Dial(mq.url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	// 创建通道
 	channel, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
-	return &RabbitMQ{
-		conn:    conn,
-		channel: channel,
-	}, nil
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	// 重新声明已知队列
+	for queueName := range mq.queues {
+		if err := declareQueue(channel, queueName); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to redeclare queue %s: %w", queueName, err)
+		}
+	}
+
+	mq.conn = conn
+	mq.channel = channel
+	close(mq.ready)
+
+	go mq.watch(conn, channel)
+
+	return nil
 }
 
-// DeclareQueue 声明队列
-func (mq *RabbitMQ) DeclareQueue(queueName string) error {
-	_, err := mq.channel.QueueDeclare(
+// watch 监听连接/通道关闭事件并触发重连
+func (mq *RabbitMQ) watch(conn *amqp.Connection, channel *amqp.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chanClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	var reason *amqp.Error
+	select {
+	case <-mq.done:
+		return
+	case reason = <-connClosed:
+	case reason = <-chanClosed:
+	}
+
+	// 标记为断线状态，后续Publish将等待重连
+	mq.mu.Lock()
+	mq.ready = make(chan struct{})
+	mq.mu.Unlock()
+
+	// 通道单独关闭时也重建整个连接，保证状态一致
+	conn.Close()
+
+	var fields []zap.Field
+	if reason != nil {
+		fields = append(fields, zap.Error(reason))
+	}
+	logger.Warn("rabbitmq connection lost, reconnecting", fields...)
+	mq.reconnect()
+}
+
+// reconnect 指数退避重连，直到成功或实例被关闭
+func (mq *RabbitMQ) reconnect() {
+	delay := reconnectBaseDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-mq.done:
+			return
+		case <-time.After(delay):
+		}
+
+		if err := mq.connect(); err != nil {
+			delay = min(delay*2, reconnectMaxDelay)
+			logger.Warn("rabbitmq reconnect failed",
+				zap.Int("attempt", attempt),
+				zap.Duration("retry_in", delay),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		logger.Info("rabbitmq reconnected", zap.Int("attempt", attempt))
+		return
+	}
+}
+
+// waitChannel 等待连接可用并返回当前通道
+func (mq *RabbitMQ) waitChannel(ctx context.Context) (*amqp.Channel, error) {
+	mq.mu.RLock()
+	ready := mq.ready
+	mq.mu.RUnlock()
+
+	select {
+	case <-ready:
+	case <-mq.done:
+		return nil, ErrNotConnected
+	case <-ctx.Done():
+		return nil, ErrNotConnected
+	}
+
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+	return mq.channel, nil
+}
+
+// IsConnected 返回当前连接是否可用（用于健康检查）
+func (mq *RabbitMQ) IsConnected() bool {
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+
+	select {
+	case <-mq.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// declareQueue 在指定通道上声明队列及其重试队列、死信队列
+//
+// 拓扑：queue 被拒绝的消息进入 queue.dlq；queue.retry 中的消息到期后
+// 通过默认交换机回到 queue，到期次数记录在 x-death 头中作为重试计数。
+func declareQueue(channel *amqp.Channel, queueName string) error {
+	// 1. 死信队列
+	if _, err := channel.QueueDeclare(queueName+deadQueueSuffix, true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	// 2. 重试队列（消息按各自TTL到期后回到主队列）
+	if _, err := channel.QueueDeclare(queueName+retryQueueSuffix, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queueName,
+	}); err != nil {
+		return err
+	}
+
+	// 3. 主队列
+	_, err := channel.QueueDeclare(
 		queueName, // 队列名称
 		true,      // durable：持久化
 		false,     // autoDelete：自动删除
 		false,     // exclusive：独占
 		false,     // noWait：不等待
-		nil,       // arguments：额外参数
+		amqp.Table{ // arguments：被拒绝的消息进入死信队列
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queueName + deadQueueSuffix,
+		},
 	)
 	return err
 }
 
-// Publish 发布消息
-func (mq *RabbitMQ) Publish(queueName string, message interface{}) error {
-	// 1. 声明队列（确保队列存在）
-	if err := mq.DeclareQueue(queueName); err != nil {
+// DeclareQueue 声明队列
+func (mq *RabbitMQ) DeclareQueue(queueName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mq.publishTimeout)
+	defer cancel()
+
+	channel, err := mq.waitChannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	mq.callMu.Lock()
+	err = declareQueue(channel, queueName)
+	mq.callMu.Unlock()
+	if err != nil {
 		return err
 	}
 
-	// 2. 序列化消息
+	mq.mu.Lock()
+	mq.queues[queueName] = struct{}{}
+	mq.mu.Unlock()
+
+	return nil
+}
+
+// declared 队列是否已声明（重连时自动重新声明，发布前无需再次声明）
+func (mq *RabbitMQ) declared(queueName string) bool {
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+	_, ok := mq.queues[queueName]
+	return ok
+}
+
+// Publish 发布消息，服务端确认后返回nil（重连与等待确认最多publishTimeout）
+func (mq *RabbitMQ) Publish(queueName string, message interface{}) error {
+	return mq.PublishWithContext(context.Background(), queueName, message)
+}
+
+// PublishWithContext 发布消息，并将链路上下文写入消息头以便消费者延续同一条Trace
+func (mq *RabbitMQ) PublishWithContext(ctx context.Context, queueName string, message interface{}) error {
 	body, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	// 3. 发布消息
-	err = mq.channel.Publish(
-		"",        // exchange：默认交换机
-		queueName, // routing key：队列名称
-		false,     // mandatory：强制
-		false,     // immediate：立即
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent, // 持久化消息
-			ContentType:  "application/json",
-			Body:         body,
-			Timestamp:    time.Now(),
-		},
+	return mq.PublishRaw(ctx, queueName, body)
+}
+
+// PublishRaw 发布已序列化的JSON消息（ctx中的链路上下文写入消息头）
+func (mq *RabbitMQ) PublishRaw(ctx context.Context, queueName string, body []byte) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "publish "+queueName,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", queueName),
+		),
 	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
-	return err
+	return mq.publish(ctx, queueName, body)
+}
+
+// publish 声明队列并发布消息
+func (mq *RabbitMQ) publish(ctx context.Context, queueName string, body []byte) error {
+	// 1. 声明队列（首次发布到该队列时，确保队列存在）
+	if !mq.declared(queueName) {
+		if err := mq.DeclareQueue(queueName); err != nil {
+			return err
+		}
+	}
+
+	// 2. 注入链路上下文
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+
+	// 3. 发布消息并等待服务端确认
+	waitCtx, cancel := context.WithTimeout(ctx, mq.publishTimeout)
+	defer cancel()
+
+	return mq.publishConfirmed(waitCtx, queueName, amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent, // 持久化消息
+		ContentType:  "application/json",
+		Body:         body,
+		Timestamp:    time.Now(),
+	})
+}
+
+// publishConfirmed 通过独占的发布通道发布到默认交换机，服务端确认后返回nil
+func (mq *RabbitMQ) publishConfirmed(ctx context.Context, queueName string, msg amqp.Publishing) error {
+	p, err := mq.acquirePublisher(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := p.channel.Publish("", queueName, false, false, msg); err != nil {
+		mq.releasePublisher(p, false)
+		return err
+	}
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			mq.releasePublisher(p, false)
+			return fmt.Errorf("%w: channel closed", ErrPublishUnconfirmed)
+		}
+		mq.releasePublisher(p, true)
+		if !confirm.Ack {
+			return ErrPublishNacked
+		}
+		return nil
+	case <-ctx.Done():
+		// 确认可能稍后到达，关闭该通道，避免下一次发布读到本条消息的确认
+		mq.releasePublisher(p, false)
+		return fmt.Errorf("%w: %v", ErrPublishUnconfirmed, ctx.Err())
+	}
+}
+
+// acquirePublisher 获取一个发布通道（优先复用空闲通道；达到上限时等待其他协程归还）
+func (mq *RabbitMQ) acquirePublisher(ctx context.Context) (*publisher, error) {
+	select {
+	case mq.publishSlots <- struct{}{}:
+	case <-mq.done:
+		return nil, ErrNotConnected
+	case <-ctx.Done():
+		return nil, ErrNotConnected
+	}
+
+	for {
+		select {
+		case p := <-mq.publishers:
+			if p.usable() {
+				return p, nil
+			}
+			p.channel.Close()
+		default:
+			p, err := mq.openPublisher(ctx)
+			if err != nil {
+				<-mq.publishSlots
+				return nil, err
+			}
+			return p, nil
+		}
+	}
+}
+
+// releasePublisher 归还发布通道（通道状态未知时关闭而不放回）
+func (mq *RabbitMQ) releasePublisher(p *publisher, reusable bool) {
+	if reusable {
+		mq.publishers <- p
+	} else {
+		p.channel.Close()
+	}
+	<-mq.publishSlots
+}
+
+// openPublisher 在当前连接上打开确认模式的发布通道
+func (mq *RabbitMQ) openPublisher(ctx context.Context) (*publisher, error) {
+	if _, err := mq.waitChannel(ctx); err != nil {
+		return nil, err
+	}
+
+	mq.mu.RLock()
+	conn := mq.conn
+	mq.mu.RUnlock()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open publish channel: %w", err)
+	}
+	if err := channel.Confirm(false); err != nil {
+		channel.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	return &publisher{
+		channel:  channel,
+		confirms: channel.NotifyPublish(make(chan amqp.Confirmation, 1)),
+		closed:   channel.NotifyClose(make(chan *amqp.Error, 1)),
+	}, nil
+}
+
+// usable 通道是否仍然打开（断线后通道随连接关闭）
+func (p *publisher) usable() bool {
+	select {
+	case <-p.closed:
+		return false
+	default:
+		return true
+	}
 }
 
 // Consume 消费消息
-func (mq *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
+func (mq *RabbitMQ) Consume(queueName string, handler Handler) error {
+	return mq.ConsumeWithContext(context.Background(), queueName, handler)
+}
+
+// ConsumeWithContext 带上下文的消费（断线重连后自动重新注册消费者）
+//
+// ctx取消后停止接收新消息，已推送但未开始处理的消息重新入队；正在处理的消息
+// 不受ctx取消影响，由Drain等待其完成。
+func (mq *RabbitMQ) ConsumeWithContext(ctx context.Context, queueName string, handler Handler) error {
+	// 1. 首次注册同步执行，以便调用方获得错误
+	c, err := mq.startConsumer(ctx, queueName)
+	if err != nil {
+		return err
+	}
+
+	// 2. 处理消息（通道关闭后等待重连并重新注册；处理协程数在重连前后共用同一上限）
+	slots := make(chan struct{}, mq.concurrency)
+	go func() {
+		for {
+			if !mq.deliver(ctx, queueName, c, slots, handler) {
+				return
+			}
+
+			for {
+				c, err = mq.startConsumer(ctx, queueName)
+				if err == nil {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-mq.done:
+					return
+				case <-time.After(reconnectBaseDelay):
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// consumer 已注册的消费者
+type consumer struct {
+	channel *amqp.Channel
+	tag     string
+	msgs    <-chan amqp.Delivery
+}
+
+// startConsumer 声明队列、设置QoS并开始消费
+func (mq *RabbitMQ) startConsumer(ctx context.Context, queueName string) (*consumer, error) {
 	// 1. 声明队列
 	if err := mq.DeclareQueue(queueName); err != nil {
-		return err
+		return nil, err
 	}
 
-	// 2. 设置QoS（每次只处理1条消息）
-	if err := mq.channel.Qos(1, 0, false); err != nil {
-		return err
+	channel, err := mq.waitChannel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mq.callMu.Lock()
+	defer mq.callMu.Unlock()
+
+	// 2. 设置QoS（预取数量不小于处理并发度）
+	if err := channel.Qos(mq.prefetch, 0, false); err != nil {
+		return nil, err
 	}
 
 	// 3. 开始消费
-	msgs, err := mq.channel.Consume(
+	tag := fmt.Sprintf("%s-%d", queueName, mq.consumerSeq.Add(1))
+	msgs, err := channel.Consume(
 		queueName, // 队列名称
-		"",        // consumer：消费者标识
+		tag,       // consumer：消费者标识（停止消费时使用）
 		false,     // autoAck：手动确认
 		false,     // exclusive：独占
 		false,     // noLocal：不接收同一连接的消息
@@ -102,74 +590,417 @@ func (mq *RabbitMQ) Consume(queueName string, handler func([]byte) error) error
 		nil,       // arguments：额外参数
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &consumer{channel: channel, tag: tag, msgs: msgs}, nil
+}
 
-	// 4. 处理消息
-	go func() {
-		for msg := range msgs {
-			// 调用处理函数
-			if err := handler(msg.Body); err != nil {
-				// 处理失败，拒绝消息并重新入队
+// deliver 分发消息直到通道关闭；返回true表示需要重新注册消费者
+//
+// 每条消息在独立的处理协程中执行，slots限制同时运行的协程数；没有空闲协程时不再从通道取消息，
+// 已预取的消息留在本地缓冲，停止消费时重新入队。
+func (mq *RabbitMQ) deliver(ctx context.Context, queueName string, c *consumer, slots chan struct{}, handler Handler) bool {
+	for {
+		// 1. 等待空闲的处理协程
+		select {
+		case <-ctx.Done():
+			mq.stopConsumer(c)
+			return false
+		case <-mq.done:
+			return false
+		case slots <- struct{}{}:
+		}
+
+		// 2. 接收下一条消息
+		select {
+		case <-ctx.Done():
+			<-slots
+			mq.stopConsumer(c)
+			return false
+		case <-mq.done:
+			<-slots
+			return false
+		case msg, ok := <-c.msgs:
+			if !ok {
+				<-slots
+				return true
+			}
+
+			// 已停止消费时不再处理，重新入队
+			if ctx.Err() != nil || !mq.beginHandler() {
+				<-slots
 				msg.Nack(false, true)
-			} else {
-				// 处理成功，确认消息
-				msg.Ack(false)
+				mq.stopConsumer(c)
+				return false
 			}
+
+			go func() {
+				defer func() { <-slots }()
+				mq.process(ctx, queueName, msg, handler)
+			}()
 		}
+	}
+}
+
+// process 调用处理函数并确认消息（由处理该消息的协程自行ack/nack，amqp通道的确认操作可并发调用）
+func (mq *RabbitMQ) process(ctx context.Context, queueName string, msg amqp.Delivery, handler Handler) {
+	defer mq.handlers.Done()
+
+	inFlight := metrics.QueueHandlersInFlight.WithLabelValues(queueName)
+	inFlight.Inc()
+	defer inFlight.Dec()
+	start := time.Now()
+
+	err := mq.handle(ctx, queueName, msg, handler)
+	result := "success"
+	switch {
+	case err == nil:
+		// 处理成功，确认消息
+		msg.Ack(false)
+	case mq.handlerCtx.Err() != nil:
+		// 排空超时被中断，重新入队由下一个实例处理（不计入重试次数）
+		result = "aborted"
+		msg.Nack(false, true)
+	default:
+		// 处理失败，进入延迟重试或死信队列
+		result = "error"
+		mq.retryOrPark(queueName, msg, err)
+	}
+	metrics.QueueMessageDuration.WithLabelValues(queueName, result).Observe(time.Since(start).Seconds())
+}
+
+// beginHandler 登记一个正在运行的处理函数，Drain开始后返回false
+func (mq *RabbitMQ) beginHandler() bool {
+	mq.drainMu.Lock()
+	defer mq.drainMu.Unlock()
+
+	if mq.draining {
+		return false
+	}
+	mq.handlers.Add(1)
+	return true
+}
+
+// stopConsumer 停止接收新消息，并将已推送到本地但未处理的消息重新入队
+func (mq *RabbitMQ) stopConsumer(c *consumer) {
+	if err := c.channel.Cancel(c.tag, false); err != nil {
+		// 通道已关闭时未确认的消息由服务端自动重新入队
+		return
+	}
+	for msg := range c.msgs {
+		msg.Nack(false, true)
+	}
+}
+
+// Drain 停止启动新的处理函数并等待正在处理的消息完成（调用前应先取消消费的ctx）
+//
+// 超过timeout后取消仍在运行的处理函数，被中断的消息重新入队；返回是否在超时前全部完成。
+func (mq *RabbitMQ) Drain(timeout time.Duration) bool {
+	mq.drainMu.Lock()
+	mq.draining = true
+	mq.drainMu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		mq.handlers.Wait()
+		close(finished)
 	}()
 
-	return nil
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+	}
+
+	mq.abortHandlers()
+	select {
+	case <-finished:
+	case <-time.After(abortGracePeriod):
+	}
+	return false
 }
 
-// ConsumeWithContext 带上下文的消费（支持优雅关闭）
-func (mq *RabbitMQ) ConsumeWithContext(ctx context.Context, queueName string, handler func([]byte) error) error {
-	// 1. 声明队列
+// handle 从消息头恢复链路上下文并在消费Span中调用处理函数（ctx取消不影响处理中的消息，仅在排空超时后取消）
+func (mq *RabbitMQ) handle(ctx context.Context, queueName string, msg amqp.Delivery, handler Handler) error {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(mq.handlerCtx, cancel)
+	defer stop()
+
+	if msg.Headers != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Headers))
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "process "+queueName,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", queueName),
+		),
+	)
+	defer span.End()
+
+	err := handler(ctx, msg.Body)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// retryOrPark 将失败消息投递到重试队列（指数延迟），超过最大重试次数或被拒绝（ErrReject）时转入死信队列
+func (mq *RabbitMQ) retryOrPark(queueName string, msg amqp.Delivery, cause error) {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[lastErrorHeader] = cause.Error()
+
+	publishing := amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+		Timestamp:    msg.Timestamp,
+	}
+
+	retries := deathCount(msg.Headers, queueName+retryQueueSuffix)
+	target := queueName + deadQueueSuffix
+	if retries < mq.maxRetries && !errors.Is(cause, ErrReject) {
+		target = queueName + retryQueueSuffix
+		delay := mq.retryBaseDelay * time.Duration(1<<retries)
+		publishing.Expiration = strconv.FormatInt(delay.Milliseconds(), 10)
+	}
+
+	// 服务端确认转投成功后才确认原消息，无法转投时重新入队，避免丢失消息
+	ctx, cancel := context.WithTimeout(context.Background(), mq.publishTimeout)
+	defer cancel()
+
+	if err := mq.publishConfirmed(ctx, target, publishing); err != nil {
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// deathCount 从x-death头中读取消息在指定队列的过期次数（即已重试次数）
+func deathCount(headers amqp.Table, queueName string) int {
+	deaths, ok := headers["x-death"].([]interface{})
+	if !ok {
+		return 0
+	}
+
+	for _, d := range deaths {
+		death, ok := d.(amqp.Table)
+		if !ok || death["queue"] != queueName {
+			continue
+		}
+		if count, ok := death["count"].(int64); ok {
+			return int(count)
+		}
+	}
+	return 0
+}
+
+// DeadLetter 死信队列中的消息
+type DeadLetter struct {
+	Body      json.RawMessage `json:"body"`
+	Retries   int             `json:"retries"`
+	LastError string          `json:"last_error,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// ListDeadLetters 查看死信队列中的消息（不移除）
+func (mq *RabbitMQ) ListDeadLetters(queueName string, limit int) ([]*DeadLetter, error) {
 	if err := mq.DeclareQueue(queueName); err != nil {
-		return err
+		return nil, err
 	}
 
-	// 2. 设置QoS
-	if err := mq.channel.Qos(1, 0, false); err != nil {
-		return err
+	channel, err := mq.openChannel()
+	if err != nil {
+		return nil, err
 	}
+	// 关闭通道时未确认的消息自动重新入队
+	defer channel.Close()
 
-	// 3. 开始消费
-	msgs, err := mq.channel.Consume(
-		queueName,
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
+	var letters []*DeadLetter
+	for len(letters) < limit {
+		msg, ok, err := channel.Get(queueName+deadQueueSuffix, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		lastError, _ := msg.Headers[lastErrorHeader].(string)
+		letters = append(letters, &DeadLetter{
+			Body:      json.RawMessage(msg.Body),
+			Retries:   deathCount(msg.Headers, queueName+retryQueueSuffix),
+			LastError: lastError,
+			Timestamp: msg.Timestamp,
+		})
+	}
+
+	return letters, nil
+}
+
+// QueueStats 队列及其重试、死信队列的积压情况
+type QueueStats struct {
+	Messages  int // 主队列待消费消息数
+	Consumers int // 主队列消费者数量
+	Retrying  int // 重试队列中等待延迟到期的消息数
+	Dead      int // 死信队列消息数
+}
+
+// Inspect 查询队列积压情况（被动查询，不修改队列）
+func (mq *RabbitMQ) Inspect(queueName string) (*QueueStats, error) {
+	if err := mq.DeclareQueue(queueName); err != nil {
+		return nil, err
+	}
+
+	channel, err := mq.openChannel()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer channel.Close()
 
-	// 4. 处理消息（支持上下文取消）
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					return
-				}
+	main, err := channel.QueueInspect(queueName)
+	if err != nil {
+		return nil, err
+	}
+	retry, err := channel.QueueInspect(queueName + retryQueueSuffix)
+	if err != nil {
+		return nil, err
+	}
+	dead, err := channel.QueueInspect(queueName + deadQueueSuffix)
+	if err != nil {
+		return nil, err
+	}
 
-				// 调用处理函数
-				if err := handler(msg.Body); err != nil {
-					msg.Nack(false, true)
-				} else {
-					msg.Ack(false)
-				}
-			}
+	return &QueueStats{
+		Messages:  main.Messages,
+		Consumers: main.Consumers,
+		Retrying:  retry.Messages,
+		Dead:      dead.Messages,
+	}, nil
+}
+
+// ReplayDeadLetters 将死信队列中的消息重新投递到主队列（重置重试计数），返回已确认写入主队列的数量
+func (mq *RabbitMQ) ReplayDeadLetters(queueName string, limit int) (int, error) {
+	if err := mq.DeclareQueue(queueName); err != nil {
+		return 0, err
+	}
+
+	channel, err := mq.openChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+
+	replayed := 0
+	for replayed < limit {
+		msg, ok, err := channel.Get(queueName+deadQueueSuffix, false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
 		}
-	}()
 
-	return nil
+		// 通过确认模式的发布通道投递，服务端确认写入主队列后才从死信队列删除（未确认时消息留在死信队列）
+		ctx, cancel := context.WithTimeout(context.Background(), mq.publishTimeout)
+		err = mq.publishConfirmed(ctx, queueName, amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  msg.ContentType,
+			Body:         msg.Body,
+			Timestamp:    time.Now(),
+		})
+		cancel()
+		if err != nil {
+			msg.Nack(false, true)
+			return replayed, err
+		}
+
+		if err := msg.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// MoveLegacyQueue 将旧版本声明的同名队列（无死信参数）中的消息转发到queueName，返回转发数量
+//
+// 已存在的队列不能用不同参数重新声明（服务端返回PRECONDITION_FAILED并关闭通道），因此带死信参数的队列使用新名称；
+// 升级期间旧版本API仍可能发布到旧队列，由Worker定期调用本方法接管。旧队列只被动声明，不存在时返回0；
+// 消息在新队列确认写入后才从旧队列确认删除，升级完成且旧队列为空后可由运维删除。
+func (mq *RabbitMQ) MoveLegacyQueue(ctx context.Context, legacyQueue, queueName string) (int, error) {
+	if err := mq.DeclareQueue(queueName); err != nil {
+		return 0, err
+	}
+
+	channel, err := mq.openChannel()
+	if err != nil {
+		return 0, err
+	}
+	// 关闭通道时未确认的消息自动重新入队
+	defer channel.Close()
+
+	if _, err := channel.QueueDeclarePassive(legacyQueue, true, false, false, false, nil); err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	moved := 0
+	for ctx.Err() == nil {
+		msg, ok, err := channel.Get(legacyQueue, false)
+		if err != nil {
+			return moved, err
+		}
+		if !ok {
+			break
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, mq.publishTimeout)
+		err = mq.publishConfirmed(waitCtx, queueName, amqp.Publishing{
+			Headers:      msg.Headers,
+			DeliveryMode: amqp.Persistent,
+			ContentType:  msg.ContentType,
+			Body:         msg.Body,
+			Timestamp:    msg.Timestamp,
+		})
+		cancel()
+		if err != nil {
+			msg.Nack(false, true)
+			return moved, err
+		}
+
+		if err := msg.Ack(false); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+
+	return moved, ctx.Err()
+}
+
+// openChannel 在当前连接上打开独立通道（用于管理操作，避免影响消费通道）
+func (mq *RabbitMQ) openChannel() (*amqp.Channel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mq.publishTimeout)
+	defer cancel()
+
+	if _, err := mq.waitChannel(ctx); err != nil {
+		return nil, err
+	}
+
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+	return mq.conn.Channel()
 }
 
 // PublishWithRetry 发布消息（带重试）
@@ -189,13 +1020,28 @@ func (mq *RabbitMQ) PublishWithRetry(queueName string, message interface{}, maxR
 
 // Close 关闭连接
 func (mq *RabbitMQ) Close() error {
-	if err := mq.channel.Close(); err != nil {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	select {
+	case <-mq.done:
+		return nil
+	default:
+		close(mq.done)
+	}
+
+	if err := mq.channel.Close(); err != nil && err != amqp.ErrClosed {
+		return err
+	}
+	if err := mq.conn.Close(); err != nil && err != amqp.ErrClosed {
 		return err
 	}
-	return mq.conn.Close()
+	return nil
 }
 
 // GetChannel 获取原始通道（用于高级操作）
 func (mq *RabbitMQ) GetChannel() *amqp.Channel {
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
 	return mq.channel
 }
@@ -0,0 +1,337 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// testRabbitMQURL 返回CWA_TEST_RABBITMQ_URL指定的RabbitMQ地址（未设置时跳过）
+func testRabbitMQURL(t *testing.T) string {
+	t.Helper()
+	url := os.Getenv("CWA_TEST_RABBITMQ_URL")
+	if url == "" {
+		t.Skip("CWA_TEST_RABBITMQ_URL not set")
+	}
+	return url
+}
+
+// testRabbitMQ 连接测试RabbitMQ并返回独立的测试队列名，结束时删除队列
+func testRabbitMQ(t *testing.T) (*RabbitMQ, string) {
+	t.Helper()
+	mq, err := NewRabbitMQ(testRabbitMQURL(t), 10*time.Second, 3, time.Second)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	queueName := fmt.Sprintf("test.%s.%d", t.Name(), time.Now().UnixNano())
+	t.Cleanup(func() {
+		if channel, err := mq.openChannel(); err == nil {
+			for _, name := range []string{queueName, queueName + retryQueueSuffix, queueName + deadQueueSuffix} {
+				channel.QueueDelete(name, false, false, false)
+			}
+			channel.Close()
+		}
+		mq.Close()
+	})
+	return mq, queueName
+}
+
+// brokerProxy 转发到RabbitMQ的TCP代理，Sever断开所有已建立的连接以模拟Broker重启
+type brokerProxy struct {
+	listener net.Listener
+	target   string
+	mu       sync.Mutex
+	conns    []net.Conn
+}
+
+// newBrokerProxy 启动代理并返回经代理连接的AMQP地址
+func newBrokerProxy(t *testing.T, url string) (*brokerProxy, string) {
+	t.Helper()
+	uri, err := amqp.ParseURI(url)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	p := &brokerProxy{listener: listener, target: net.JoinHostPort(uri.Host, strconv.Itoa(uri.Port))}
+	t.Cleanup(func() {
+		listener.Close()
+		p.Sever()
+	})
+	go p.serve()
+
+	uri.Host = "127.0.0.1"
+	uri.Port = listener.Addr().(*net.TCPAddr).Port
+	return p, uri.String()
+}
+
+func (p *brokerProxy) serve() {
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		server, err := net.Dial("tcp", p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+		p.mu.Lock()
+		p.conns = append(p.conns, client, server)
+		p.mu.Unlock()
+		go func() { io.Copy(server, client); server.Close() }()
+		go func() { io.Copy(client, server); client.Close() }()
+	}
+}
+
+// Sever 断开所有经代理的连接
+func (p *brokerProxy) Sever() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+// waitConnected 等待连接状态变为want
+func waitConnected(t *testing.T, mq *RabbitMQ, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(15 * time.Second)
+	for mq.IsConnected() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("IsConnected did not become %v", want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPublishWaitsWhileDisconnected(t *testing.T) {
+	// 断线状态：ready未关闭，Publish最多等待publishTimeout
+	mq := &RabbitMQ{
+		publishTimeout: 100 * time.Millisecond,
+		ready:          make(chan struct{}),
+		queues:         make(map[string]struct{}),
+		done:           make(chan struct{}),
+	}
+	if mq.IsConnected() {
+		t.Fatal("IsConnected = true before connecting")
+	}
+
+	start := time.Now()
+	err := mq.Publish("test", map[string]string{"k": "v"})
+	if !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Publish err = %v, want ErrNotConnected", err)
+	}
+	if waited := time.Since(start); waited < mq.publishTimeout {
+		t.Errorf("Publish returned after %s, want to wait %s for a reconnect", waited, mq.publishTimeout)
+	}
+
+	// 关闭后不再等待
+	close(mq.done)
+	start = time.Now()
+	if err := mq.Publish("test", nil); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Publish after close err = %v, want ErrNotConnected", err)
+	}
+	if waited := time.Since(start); waited >= mq.publishTimeout {
+		t.Errorf("Publish after close waited %s", waited)
+	}
+}
+
+func TestReconnectRestoresPublishAndConsumers(t *testing.T) {
+	proxy, url := newBrokerProxy(t, testRabbitMQURL(t))
+	mq, err := NewRabbitMQ(url, 10*time.Second, 3, time.Second)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	queueName := fmt.Sprintf("test.%s.%d", t.Name(), time.Now().UnixNano())
+	t.Cleanup(func() {
+		if channel, err := mq.openChannel(); err == nil {
+			for _, name := range []string{queueName, queueName + retryQueueSuffix, queueName + deadQueueSuffix} {
+				channel.QueueDelete(name, false, false, false)
+			}
+			channel.Close()
+		}
+		mq.Close()
+	})
+
+	received := make(chan string, 10)
+	if err := mq.Consume(queueName, func(ctx context.Context, body []byte) error {
+		received <- string(body)
+		return nil
+	}); err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+
+	// Broker断开：状态变为未连接，随后自动重连
+	proxy.Sever()
+	waitConnected(t, mq, false)
+	waitConnected(t, mq, true)
+
+	// 重连后发布成功，且消费者已重新注册
+	if err := mq.Publish(queueName, "after-reconnect"); err != nil {
+		t.Fatalf("publish after reconnect: %v", err)
+	}
+	select {
+	case body := <-received:
+		if body != `"after-reconnect"` {
+			t.Errorf("received %s, want \"after-reconnect\"", body)
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("consumer did not receive the message after reconnect")
+	}
+}
+
+func TestDrainAcksOrRequeuesInFlight(t *testing.T) {
+	mq, queueName := testRabbitMQ(t)
+	mq.SetConsumerConcurrency(8, 4)
+
+	const total = 40
+	for i := 0; i < total; i++ {
+		if err := mq.Publish(queueName, map[string]int{"seq": i}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	handled := 0
+	started := make(chan struct{}, total)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := mq.ConsumeWithContext(ctx, queueName, func(ctx context.Context, body []byte) error {
+		started <- struct{}{}
+		time.Sleep(100 * time.Millisecond)
+		mu.Lock()
+		handled++
+		mu.Unlock()
+		return nil
+	}); err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+
+	// 有消息正在处理时关闭：停止消费后等待处理中的消息完成
+	<-started
+	cancel()
+	if !mq.Drain(10 * time.Second) {
+		t.Fatal("drain timed out")
+	}
+
+	// 每条消息要么已处理并确认，要么重新入队，没有丢失
+	mu.Lock()
+	acked := handled
+	mu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := mq.Inspect(queueName)
+		if err != nil {
+			t.Fatalf("inspect: %v", err)
+		}
+		if acked+stats.Messages == total && stats.Dead == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("acked = %d, requeued = %d, dead = %d, want %d in total", acked, stats.Messages, stats.Dead, total)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if acked == 0 || acked == total {
+		t.Errorf("acked = %d, want some but not all messages processed before shutdown", acked)
+	}
+}
+
+func TestPublishConcurrent(t *testing.T) {
+	mq, queueName := testRabbitMQ(t)
+
+	const goroutines, perGoroutine = 50, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*perGoroutine)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				if err := mq.Publish(queueName, map[string]int{"goroutine": g, "seq": i}); err != nil {
+					errs <- err
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("publish: %v", err)
+	}
+
+	// 所有发布都已被服务端确认，消息数应与发布数一致
+	stats, err := mq.Inspect(queueName)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if stats.Messages != goroutines*perGoroutine {
+		t.Errorf("messages = %d, want %d", stats.Messages, goroutines*perGoroutine)
+	}
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	mq, queueName := testRabbitMQ(t)
+	if err := mq.DeclareQueue(queueName); err != nil {
+		t.Fatalf("declare: %v", err)
+	}
+
+	channel, err := mq.openChannel()
+	if err != nil {
+		t.Fatalf("open channel: %v", err)
+	}
+	defer channel.Close()
+	for i := 0; i < 3; i++ {
+		if err := channel.Publish("", queueName+deadQueueSuffix, false, false, amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         []byte(fmt.Sprintf(`{"seq":%d}`, i)),
+		}); err != nil {
+			t.Fatalf("seed dead letter: %v", err)
+		}
+	}
+
+	// 等待死信写入后再重放
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := mq.Inspect(queueName)
+		if err != nil {
+			t.Fatalf("inspect: %v", err)
+		}
+		if stats.Dead == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dead letters = %d, want 3", stats.Dead)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	replayed, err := mq.ReplayDeadLetters(queueName, 10)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed != 3 {
+		t.Errorf("replayed = %d, want 3", replayed)
+	}
+
+	stats, err := mq.Inspect(queueName)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if stats.Messages != 3 || stats.Dead != 0 {
+		t.Errorf("messages = %d, dead = %d, want 3 and 0", stats.Messages, stats.Dead)
+	}
+}